@@ -29,6 +29,7 @@ const (
 	DiscoverAccess    string = "discover"
 	RegisterAccess    string = "register"
 	HealthcheckAccess string = "healthcheck"
+	WatchAccess       string = "watch"
 )
 
 /**
@@ -60,8 +61,21 @@ type Apiserver interface {
 	Restart(option map[string]interface{}, api map[string]APIConfig, errCh chan error) error
 }
 
+/**
+ * @brief 配置热更新的结果
+ */
+type ReloadResult struct {
+	// 已经生效的配置项
+	Applied []string `json:"applied"`
+	// 需要重启才能生效的配置项
+	Restart []string `json:"restart"`
+}
+
 var (
 	Slots = make(map[string]Apiserver)
+
+	// ConfigReloader 配置热更新函数，由启动流程设置
+	ConfigReloader func() (*ReloadResult, error)
 )
 
 /**
@@ -85,6 +99,7 @@ func GetClientOpenMethod(include []string, protocol string) (map[string]bool, er
 	clientAccess[DiscoverAccess] = []string{"Discover", "ReportClient"}
 	clientAccess[RegisterAccess] = []string{"RegisterInstance", "DeregisterInstance"}
 	clientAccess[HealthcheckAccess] = []string{"Heartbeat"}
+	clientAccess[WatchAccess] = []string{"Watch"}
 
 	openMethod := make(map[string]bool)
 	// 如果为空，开启全部接口
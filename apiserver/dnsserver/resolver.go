@@ -0,0 +1,427 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dnsserver
+
+import (
+	"encoding/hex"
+	"math"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// 未携带EDNS时UDP回复的最大长度
+	minUDPSize = 512
+	// 携带EDNS时UDP回复的最大长度
+	maxUDPSize = 4096
+	// TCP回复的最大长度
+	maxTCPSize = 65535
+	// SRV记录target中IP地址的标签，例如0a000001.addr.polaris.
+	addrLabel = "addr"
+	// SOA记录的刷新时间，单位为秒
+	soaRefresh = 3600
+	soaRetry   = 600
+	soaExpire  = 86400
+)
+
+// 根据服务名和命名空间查询服务，服务别名需要返回源服务
+type serviceGetter func(name string, namespace string) *model.Service
+
+// 根据服务ID查询实例
+type instancesGetter func(serviceID string) []*model.Instance
+
+/**
+ * @brief 把DNS查询解析为服务发现的请求
+ * 服务的域名为<service>.<namespace>.<zone>，命名空间为最后一个标签，其余的部分为服务名
+ * SRV查询兼容_<name>._<proto>.<service>.<namespace>.<zone>的格式
+ */
+type resolver struct {
+	zone       string
+	zoneName   dnsmessage.Name
+	ttl        uint32
+	maxAnswers int
+
+	getService   serviceGetter
+	getInstances instancesGetter
+	// 服务发现统计，可以为空
+	record func(service string, namespace string)
+}
+
+// 新建resolver，zone统一为小写并以.结尾
+func newResolver(zone string, ttl uint32, maxAnswers int, getService serviceGetter,
+	getInstances instancesGetter) (*resolver, error) {
+	zone = strings.ToLower(strings.Trim(zone, "."))
+	if zone == "" {
+		zone = defaultZone
+	}
+	zone += "."
+	zoneName, err := dnsmessage.NewName(zone)
+	if err != nil {
+		return nil, err
+	}
+	if ttl == 0 {
+		ttl = 1
+	}
+	return &resolver{
+		zone:         zone,
+		zoneName:     zoneName,
+		ttl:          ttl,
+		maxAnswers:   maxAnswers,
+		getService:   getService,
+		getInstances: getInstances,
+	}, nil
+}
+
+// 根据缓存的刷新间隔计算TTL，至少为1秒
+func ttlFromInterval(interval time.Duration) uint32 {
+	ttl := uint32(math.Ceil(interval.Seconds()))
+	if ttl == 0 {
+		ttl = 1
+	}
+	return ttl
+}
+
+/**
+ * @brief 处理一个DNS请求
+ * udp表示请求是否来自UDP，UDP的回复超过限制时截断并设置TC标记
+ * 返回nil表示请求无法解析，直接丢弃
+ */
+func (r *resolver) handle(data []byte, udp bool) ([]byte, dnsmessage.RCode, dnsmessage.Type) {
+	var req dnsmessage.Message
+	if err := req.Unpack(data); err != nil {
+		var parser dnsmessage.Parser
+		header, headerErr := parser.Start(data)
+		if headerErr != nil || header.Response {
+			return nil, dnsmessage.RCodeFormatError, 0
+		}
+		resp := &dnsmessage.Message{Header: r.responseHeader(header, dnsmessage.RCodeFormatError)}
+		out, _ := resp.Pack()
+		return out, dnsmessage.RCodeFormatError, 0
+	}
+	if req.Header.Response {
+		return nil, dnsmessage.RCodeFormatError, 0
+	}
+
+	resp := &dnsmessage.Message{Header: r.responseHeader(req.Header, dnsmessage.RCodeSuccess)}
+	var qtype dnsmessage.Type
+	switch {
+	case req.Header.OpCode != 0:
+		resp.Header.RCode = dnsmessage.RCodeNotImplemented
+	case len(req.Questions) != 1:
+		resp.Header.RCode = dnsmessage.RCodeFormatError
+	default:
+		question := req.Questions[0]
+		qtype = question.Type
+		resp.Questions = req.Questions
+		r.resolve(question, resp)
+	}
+
+	size := maxTCPSize
+	if udp {
+		size = minUDPSize
+	}
+	if opt := findOPT(req.Additionals); opt != nil {
+		// 客户端支持EDNS时，回复中也需要带上OPT记录
+		if udp && int(opt.Header.Class) > minUDPSize {
+			size = int(minUint32(uint32(opt.Header.Class), maxUDPSize))
+		}
+		resp.Additionals = append(resp.Additionals, newOPT())
+	}
+	out, err := packWithLimit(resp, size)
+	if err != nil {
+		resp = &dnsmessage.Message{Header: r.responseHeader(req.Header, dnsmessage.RCodeServerFailure),
+			Questions: req.Questions}
+		out, _ = resp.Pack()
+		return out, dnsmessage.RCodeServerFailure, qtype
+	}
+	return out, resp.Header.RCode, qtype
+}
+
+// 生成回复的头部
+func (r *resolver) responseHeader(req dnsmessage.Header, code dnsmessage.RCode) dnsmessage.Header {
+	return dnsmessage.Header{
+		ID:               req.ID,
+		Response:         true,
+		OpCode:           req.OpCode,
+		Authoritative:    true,
+		RecursionDesired: req.RecursionDesired,
+		RCode:            code,
+	}
+}
+
+// 解析查询的域名，填充回复的记录
+func (r *resolver) resolve(question dnsmessage.Question, resp *dnsmessage.Message) {
+	name := question.Name.String()
+	lower := strings.ToLower(name)
+	if question.Class != dnsmessage.ClassINET && question.Class != dnsmessage.ClassANY {
+		resp.Header.RCode = dnsmessage.RCodeRefused
+		return
+	}
+	if lower == r.zone {
+		r.resolveZone(question, resp)
+		return
+	}
+	if !strings.HasSuffix(lower, "."+r.zone) {
+		// 不是本zone的域名，不提供递归查询
+		resp.Header.Authoritative = false
+		resp.Header.RCode = dnsmessage.RCodeRefused
+		return
+	}
+
+	labels := strings.Split(name[:len(name)-len(r.zone)-1], ".")
+	if len(labels) == 2 && strings.ToLower(labels[1]) == addrLabel {
+		r.resolveAddr(question, labels[0], resp)
+		return
+	}
+	// 去掉SRV查询中_service._proto的前缀
+	for len(labels) > 0 && strings.HasPrefix(labels[0], "_") {
+		labels = labels[1:]
+	}
+	if len(labels) < 2 {
+		r.nxDomain(resp)
+		return
+	}
+	namespace := labels[len(labels)-1]
+	serviceName := strings.Join(labels[:len(labels)-1], ".")
+
+	service := r.getService(serviceName, namespace)
+	if service == nil {
+		r.nxDomain(resp)
+		return
+	}
+	if r.record != nil {
+		r.record(serviceName, namespace)
+	}
+
+	instances := availableInstances(r.getInstances(service.ID))
+	weightedShuffle(instances)
+	switch question.Type {
+	case dnsmessage.TypeA, dnsmessage.TypeAAAA, dnsmessage.TypeALL:
+		for _, instance := range instances {
+			if body := addressResource(instance.Host(), question.Type); body != nil {
+				resp.Answers = append(resp.Answers, r.resource(question.Name, body))
+			}
+		}
+	case dnsmessage.TypeSRV:
+		for _, instance := range instances {
+			target, additional := r.srvTarget(instance.Host())
+			if target.Length == 0 {
+				continue
+			}
+			resp.Answers = append(resp.Answers, r.resource(question.Name, &dnsmessage.SRVResource{
+				Priority: uint16(minUint32(instance.Priority(), math.MaxUint16)),
+				Weight:   uint16(minUint32(instance.Weight(), math.MaxUint16)),
+				Port:     uint16(instance.Port()),
+				Target:   target,
+			}))
+			if additional != nil {
+				resp.Additionals = append(resp.Additionals, r.resource(target, additional))
+			}
+		}
+	}
+	if r.maxAnswers > 0 && len(resp.Answers) > r.maxAnswers {
+		resp.Answers = resp.Answers[:r.maxAnswers]
+		if len(resp.Additionals) > r.maxAnswers {
+			resp.Additionals = resp.Additionals[:r.maxAnswers]
+		}
+	}
+	if len(resp.Answers) == 0 {
+		// 服务存在但是没有对应类型的记录，返回NODATA
+		resp.Authorities = append(resp.Authorities, r.soa())
+	}
+}
+
+// 查询zone本身，只提供SOA记录
+func (r *resolver) resolveZone(question dnsmessage.Question, resp *dnsmessage.Message) {
+	if question.Type == dnsmessage.TypeSOA || question.Type == dnsmessage.TypeALL {
+		resp.Answers = append(resp.Answers, r.soa())
+		return
+	}
+	resp.Authorities = append(resp.Authorities, r.soa())
+}
+
+// 查询SRV记录target中编码的IP地址
+func (r *resolver) resolveAddr(question dnsmessage.Question, label string, resp *dnsmessage.Message) {
+	ip := decodeAddr(label)
+	if ip == nil {
+		r.nxDomain(resp)
+		return
+	}
+	if body := addressResource(ip.String(), question.Type); body != nil {
+		resp.Answers = append(resp.Answers, r.resource(question.Name, body))
+		return
+	}
+	resp.Authorities = append(resp.Authorities, r.soa())
+}
+
+// 返回域名不存在，带上SOA记录用于否定缓存
+func (r *resolver) nxDomain(resp *dnsmessage.Message) {
+	resp.Header.RCode = dnsmessage.RCodeNameError
+	resp.Authorities = append(resp.Authorities, r.soa())
+}
+
+// 生成zone的SOA记录
+func (r *resolver) soa() dnsmessage.Resource {
+	return r.resource(r.zoneName, &dnsmessage.SOAResource{
+		NS:      dnsmessage.MustNewName("ns." + r.zone),
+		MBox:    dnsmessage.MustNewName("hostmaster." + r.zone),
+		Serial:  uint32(time.Now().Unix()),
+		Refresh: soaRefresh,
+		Retry:   soaRetry,
+		Expire:  soaExpire,
+		MinTTL:  r.ttl,
+	})
+}
+
+// 生成一条记录
+func (r *resolver) resource(name dnsmessage.Name, body dnsmessage.ResourceBody) dnsmessage.Resource {
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{
+			Name:  name,
+			Class: dnsmessage.ClassINET,
+			TTL:   r.ttl,
+		},
+		Body: body,
+	}
+}
+
+// SRV记录的target，IP地址编码为<hex>.addr.<zone>并返回对应的附加记录，域名直接使用
+func (r *resolver) srvTarget(host string) (dnsmessage.Name, dnsmessage.ResourceBody) {
+	if ip := net.ParseIP(utils.NormalizeIP(host)); ip != nil {
+		if ipv4 := ip.To4(); ipv4 != nil {
+			ip = ipv4
+		}
+		name := dnsmessage.MustNewName(hex.EncodeToString(ip) + "." + addrLabel + "." + r.zone)
+		if len(ip) == net.IPv4len {
+			return name, addressResource(host, dnsmessage.TypeA)
+		}
+		return name, addressResource(host, dnsmessage.TypeAAAA)
+	}
+	name, err := dnsmessage.NewName(strings.TrimSuffix(host, ".") + ".")
+	if err != nil {
+		return dnsmessage.Name{}, nil
+	}
+	return name, nil
+}
+
+// 解析SRV记录target中编码的IP地址
+func decodeAddr(label string) net.IP {
+	data, err := hex.DecodeString(label)
+	if err != nil || (len(data) != net.IPv4len && len(data) != net.IPv6len) {
+		return nil
+	}
+	return net.IP(data)
+}
+
+// 根据查询的类型生成A或者AAAA记录，类型不匹配以及域名的host返回nil
+func addressResource(host string, qtype dnsmessage.Type) dnsmessage.ResourceBody {
+	ip := net.ParseIP(utils.NormalizeIP(host))
+	if ip == nil {
+		return nil
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		if qtype != dnsmessage.TypeA && qtype != dnsmessage.TypeALL {
+			return nil
+		}
+		body := &dnsmessage.AResource{}
+		copy(body.A[:], ipv4)
+		return body
+	}
+	if qtype != dnsmessage.TypeAAAA && qtype != dnsmessage.TypeALL {
+		return nil
+	}
+	body := &dnsmessage.AAAAResource{}
+	copy(body.AAAA[:], ip.To16())
+	return body
+}
+
+// 过滤出健康、未隔离并且权重大于0的实例
+func availableInstances(instances []*model.Instance) []*model.Instance {
+	out := make([]*model.Instance, 0, len(instances))
+	for _, instance := range instances {
+		if instance.Healthy() && !instance.Isolate() && instance.Weight() > 0 {
+			out = append(out, instance)
+		}
+	}
+	return out
+}
+
+/**
+ * @brief 按照权重随机打乱实例的顺序
+ * 每个实例的排序值为rand^(1/weight)，权重越大越容易排在前面
+ */
+func weightedShuffle(instances []*model.Instance) {
+	keys := make(map[*model.Instance]float64, len(instances))
+	for _, instance := range instances {
+		keys[instance] = math.Pow(rand.Float64(), 1/float64(instance.Weight()))
+	}
+	sort.SliceStable(instances, func(i, j int) bool {
+		return keys[instances[i]] > keys[instances[j]]
+	})
+}
+
+// 打包回复，超过长度限制时丢弃附加记录和部分回答，并设置TC标记
+func packWithLimit(resp *dnsmessage.Message, size int) ([]byte, error) {
+	out, err := resp.Pack()
+	if err != nil || len(out) <= size {
+		return out, err
+	}
+	resp.Header.Truncated = true
+	var opt []dnsmessage.Resource
+	if found := findOPT(resp.Additionals); found != nil {
+		opt = append(opt, *found)
+	}
+	resp.Additionals = opt
+	for {
+		if out, err = resp.Pack(); err != nil || len(out) <= size || len(resp.Answers) == 0 {
+			return out, err
+		}
+		resp.Answers = resp.Answers[:len(resp.Answers)-1]
+	}
+}
+
+// 查找EDNS的OPT记录
+func findOPT(resources []dnsmessage.Resource) *dnsmessage.Resource {
+	for i := range resources {
+		if resources[i].Header.Type == dnsmessage.TypeOPT {
+			return &resources[i]
+		}
+	}
+	return nil
+}
+
+// 生成回复中的OPT记录，声明本端支持的UDP报文长度
+func newOPT() dnsmessage.Resource {
+	var header dnsmessage.ResourceHeader
+	_ = header.SetEDNS0(maxUDPSize, dnsmessage.RCodeSuccess, false)
+	return dnsmessage.Resource{Header: header, Body: &dnsmessage.OPTResource{}}
+}
+
+func minUint32(a uint32, b uint32) uint32 {
+	if a < b {
+		return a
+	}
+	return b
+}
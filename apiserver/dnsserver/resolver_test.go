@@ -0,0 +1,189 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dnsserver
+
+import (
+	"fmt"
+	"testing"
+
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func newTestInstance(host string, port uint32, weight uint32, healthy bool, isolate bool) *model.Instance {
+	return &model.Instance{Proto: &api.Instance{
+		Host:     utils.NewStringValue(host),
+		Port:     utils.NewUInt32Value(port),
+		Weight:   utils.NewUInt32Value(weight),
+		Priority: utils.NewUInt32Value(1),
+		Healthy:  utils.NewBoolValue(healthy),
+		Isolate:  utils.NewBoolValue(isolate),
+	}}
+}
+
+func newTestResolver(t *testing.T, instances []*model.Instance) *resolver {
+	r, err := newResolver("polaris.", 2, 0, func(name string, namespace string) *model.Service {
+		if name == "svc.a" && namespace == "Test" {
+			return &model.Service{ID: "svc-id", Name: name, Namespace: namespace}
+		}
+		return nil
+	}, func(serviceID string) []*model.Instance {
+		return instances
+	})
+	if err != nil {
+		t.Fatalf("new resolver err: %s", err.Error())
+	}
+	return r
+}
+
+func query(t *testing.T, r *resolver, name string, qtype dnsmessage.Type, udp bool) *dnsmessage.Message {
+	req := &dnsmessage.Message{
+		Header: dnsmessage.Header{ID: 1, RecursionDesired: true},
+		Questions: []dnsmessage.Question{
+			{Name: dnsmessage.MustNewName(name), Type: qtype, Class: dnsmessage.ClassINET},
+		},
+	}
+	data, err := req.Pack()
+	if err != nil {
+		t.Fatalf("pack request err: %s", err.Error())
+	}
+	out, _, _ := r.handle(data, udp)
+	resp := &dnsmessage.Message{}
+	if err := resp.Unpack(out); err != nil {
+		t.Fatalf("unpack response err: %s", err.Error())
+	}
+	return resp
+}
+
+// TestResolver_Address 测试A以及AAAA记录只返回健康、未隔离的实例
+func TestResolver_Address(t *testing.T) {
+	r := newTestResolver(t, []*model.Instance{
+		newTestInstance("10.0.0.1", 8080, 100, true, false),
+		newTestInstance("10.0.0.2", 8080, 100, false, false),
+		newTestInstance("10.0.0.3", 8080, 100, true, true),
+		newTestInstance("10.0.0.4", 8080, 0, true, false),
+		newTestInstance("fe80::1", 8080, 100, true, false),
+	})
+
+	resp := query(t, r, "svc.a.Test.polaris.", dnsmessage.TypeA, true)
+	if resp.Header.RCode != dnsmessage.RCodeSuccess || len(resp.Answers) != 1 {
+		t.Fatalf("A response: %+v", resp)
+	}
+	if body := resp.Answers[0].Body.(*dnsmessage.AResource); body.A != [4]byte{10, 0, 0, 1} ||
+		resp.Answers[0].Header.TTL != 2 {
+		t.Fatalf("A answer: %+v", resp.Answers[0])
+	}
+
+	resp = query(t, r, "svc.a.Test.polaris.", dnsmessage.TypeAAAA, true)
+	if len(resp.Answers) != 1 || resp.Answers[0].Header.Type != dnsmessage.TypeAAAA {
+		t.Fatalf("AAAA response: %+v", resp)
+	}
+
+	resp = query(t, r, "svc.a.Test.polaris.", dnsmessage.TypeMX, true)
+	if resp.Header.RCode != dnsmessage.RCodeSuccess || len(resp.Answers) != 0 || len(resp.Authorities) != 1 {
+		t.Fatalf("NODATA response: %+v", resp)
+	}
+}
+
+// TestResolver_SRV 测试SRV记录以及target中编码的IP地址
+func TestResolver_SRV(t *testing.T) {
+	r := newTestResolver(t, []*model.Instance{
+		newTestInstance("10.0.0.1", 8080, 100, true, false),
+		newTestInstance("db.example.com", 3306, 50, true, false),
+	})
+
+	resp := query(t, r, "_http._tcp.svc.a.Test.polaris.", dnsmessage.TypeSRV, false)
+	if len(resp.Answers) != 2 || len(resp.Additionals) != 1 {
+		t.Fatalf("SRV response: %+v", resp)
+	}
+	targets := make(map[string]*dnsmessage.SRVResource)
+	for _, answer := range resp.Answers {
+		body := answer.Body.(*dnsmessage.SRVResource)
+		targets[body.Target.String()] = body
+	}
+	if body := targets["0a000001.addr.polaris."]; body == nil || body.Port != 8080 || body.Weight != 100 ||
+		body.Priority != 1 {
+		t.Fatalf("SRV answers: %+v", targets)
+	}
+	if body := targets["db.example.com."]; body == nil || body.Port != 3306 {
+		t.Fatalf("SRV answers: %+v", targets)
+	}
+
+	resp = query(t, r, "0a000001.addr.polaris.", dnsmessage.TypeA, false)
+	if len(resp.Answers) != 1 || resp.Answers[0].Body.(*dnsmessage.AResource).A != [4]byte{10, 0, 0, 1} {
+		t.Fatalf("addr response: %+v", resp)
+	}
+}
+
+// TestResolver_NameError 测试未知的服务以及zone之外的域名
+func TestResolver_NameError(t *testing.T) {
+	r := newTestResolver(t, nil)
+
+	resp := query(t, r, "unknown.Test.polaris.", dnsmessage.TypeA, true)
+	if resp.Header.RCode != dnsmessage.RCodeNameError || len(resp.Authorities) != 1 {
+		t.Fatalf("unknown service response: %+v", resp)
+	}
+	if resp.Authorities[0].Body.(*dnsmessage.SOAResource).MinTTL != 2 {
+		t.Fatalf("SOA should use ttl: %+v", resp.Authorities[0])
+	}
+	resp = query(t, r, "www.example.com.", dnsmessage.TypeA, true)
+	if resp.Header.RCode != dnsmessage.RCodeRefused {
+		t.Fatalf("out of zone response: %+v", resp)
+	}
+	resp = query(t, r, "polaris.", dnsmessage.TypeSOA, true)
+	if resp.Header.RCode != dnsmessage.RCodeSuccess || len(resp.Answers) != 1 {
+		t.Fatalf("zone SOA response: %+v", resp)
+	}
+}
+
+// TestResolver_Truncate 测试UDP回复超过512字节时被截断
+func TestResolver_Truncate(t *testing.T) {
+	var instances []*model.Instance
+	for i := 1; i <= 64; i++ {
+		instances = append(instances, newTestInstance(fmt.Sprintf("2001:db8::%x", i), 8080, 100, true, false))
+	}
+	r := newTestResolver(t, instances)
+
+	resp := query(t, r, "svc.a.Test.polaris.", dnsmessage.TypeAAAA, true)
+	if !resp.Header.Truncated || len(resp.Answers) == 0 || len(resp.Answers) == len(instances) {
+		t.Fatalf("udp response should be truncated, answers: %d", len(resp.Answers))
+	}
+	resp = query(t, r, "svc.a.Test.polaris.", dnsmessage.TypeAAAA, false)
+	if resp.Header.Truncated || len(resp.Answers) != len(instances) {
+		t.Fatalf("tcp response should not be truncated, answers: %d", len(resp.Answers))
+	}
+}
+
+// TestWeightedShuffle 测试按照权重随机打乱，权重越大越容易排在前面
+func TestWeightedShuffle(t *testing.T) {
+	heavy := newTestInstance("10.0.0.1", 80, 1000, true, false)
+	light := newTestInstance("10.0.0.2", 80, 1, true, false)
+	first := 0
+	for i := 0; i < 1000; i++ {
+		instances := []*model.Instance{light, heavy}
+		weightedShuffle(instances)
+		if instances[0] == heavy {
+			first++
+		}
+	}
+	if first < 950 {
+		t.Fatalf("heavy instance should be first in most cases, got %d", first)
+	}
+}
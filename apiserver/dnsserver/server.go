@@ -0,0 +1,315 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dnsserver
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-server/apiserver"
+	"github.com/polarismesh/polaris-server/common/connlimit"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/naming"
+	"github.com/polarismesh/polaris-server/plugin"
+	"go.uber.org/zap"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// 默认的zone，服务的域名为<service>.<namespace>.polaris.
+	defaultZone = "polaris"
+	// TCP连接的空闲超时时间
+	tcpIdleTimeout = 10 * time.Second
+	// 默认同时处理的UDP请求数
+	defaultUDPConcurrency = 1024
+)
+
+/**
+ * @brief DNS API服务器，以DNS的方式提供服务发现
+ */
+type DNSServer struct {
+	listenIP   string
+	listenPort uint32
+	zone       string
+	maxAnswers int
+	// 同时处理的UDP请求数，超过时暂停读取，由内核缓冲区排队
+	udpConcurrency  int
+	connLimitConfig *connlimit.Config
+
+	udpConn      net.PacketConn
+	tcpListener  net.Listener
+	namingServer *naming.Server
+	statis       plugin.Statis
+	resolver     *resolver
+
+	restart bool
+	exitCh  chan struct{}
+}
+
+/**
+ * @brief 获取端口
+ */
+func (d *DNSServer) GetPort() uint32 {
+	return d.listenPort
+}
+
+/**
+ * @brief 获取Server的协议
+ */
+func (d *DNSServer) GetProtocol() string {
+	return "dns"
+}
+
+/**
+ * @brief 初始化DNS API服务器
+ */
+func (d *DNSServer) Initialize(_ context.Context, option map[string]interface{},
+	_ map[string]apiserver.APIConfig) error {
+	d.listenIP, _ = option["listenIP"].(string)
+	listenPort, _ := option["listenPort"].(int)
+	if listenPort <= 0 {
+		return fmt.Errorf("dnsserver listenPort(%v) is invalid", option["listenPort"])
+	}
+	d.listenPort = uint32(listenPort)
+
+	d.zone = defaultZone
+	if zone, _ := option["zone"].(string); zone != "" {
+		d.zone = zone
+	}
+	d.maxAnswers, _ = option["maxAnswers"].(int)
+	d.udpConcurrency = defaultUDPConcurrency
+	if concurrency, _ := option["udpConcurrency"].(int); concurrency > 0 {
+		d.udpConcurrency = concurrency
+	}
+	// TCP连接数限制的配置
+	d.connLimitConfig = nil
+	if raw, _ := option["connLimit"].(map[interface{}]interface{}); raw != nil {
+		connLimitConfig, err := connlimit.ParseConnLimitConfig(raw)
+		if err != nil {
+			return err
+		}
+		d.connLimitConfig = connLimitConfig
+	}
+	if _, err := dnsmessage.NewName(d.zone + "."); err != nil {
+		return fmt.Errorf("dnsserver zone(%s) is invalid: %s", d.zone, err.Error())
+	}
+	return nil
+}
+
+/**
+ * @brief 启动DNS API服务器，同时监听UDP和TCP
+ */
+func (d *DNSServer) Run(errCh chan error) {
+	log.Infof("start dnsserver")
+	d.exitCh = make(chan struct{})
+	defer close(d.exitCh)
+
+	var err error
+	d.namingServer, err = naming.GetServer()
+	if err != nil {
+		log.Errorf("%v", err)
+		errCh <- err
+		return
+	}
+	d.statis = plugin.GetStatis()
+	caches := d.namingServer.Cache()
+	d.resolver, err = newResolver(d.zone, ttlFromInterval(caches.GetUpdateCacheInterval()), d.maxAnswers,
+		func(name string, namespace string) *model.Service {
+			service := caches.Service().GetServiceByName(name, namespace)
+			if service != nil && service.IsAlias() {
+				service = caches.Service().GetServiceByID(service.Reference)
+			}
+			return service
+		}, caches.Instance().GetInstancesByServiceID)
+	if err != nil {
+		log.Errorf("[DNS] create resolver err: %s", err.Error())
+		errCh <- err
+		return
+	}
+	d.resolver.record = d.namingServer.RecordDiscoverStatis
+
+	address := net.JoinHostPort(d.listenIP, strconv.Itoa(int(d.listenPort)))
+	d.udpConn, err = net.ListenPacket("udp", address)
+	if err != nil {
+		log.Errorf("listen udp error: %v", err)
+		errCh <- err
+		return
+	}
+	d.tcpListener, err = net.Listen("tcp", address)
+	if err != nil {
+		log.Errorf("listen tcp error: %v", err)
+		_ = d.udpConn.Close()
+		errCh <- err
+		return
+	}
+	// 开启TCP的最大连接数限制
+	if d.connLimitConfig != nil && d.connLimitConfig.OpenConnLimit {
+		log.Infof("dnsserver use max connection limit per ip: %d, tcp max limit: %d",
+			d.connLimitConfig.MaxConnPerHost, d.connLimitConfig.MaxConnLimit)
+		listener, err := connlimit.NewListener(d.tcpListener, d.GetProtocol(), d.connLimitConfig)
+		if err != nil {
+			log.Errorf("conn limit init err: %s", err.Error())
+			_ = d.udpConn.Close()
+			_ = d.tcpListener.Close()
+			errCh <- err
+			return
+		}
+		d.tcpListener = listener
+	}
+
+	// UDP或者TCP任意一个退出，另外一个也需要关闭
+	var once sync.Once
+	var wg sync.WaitGroup
+	exit := func(err error) {
+		once.Do(func() {
+			d.Stop()
+			if !d.restart {
+				log.Errorf("dnsserver exit: %v", err)
+				errCh <- err
+			}
+		})
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		exit(d.serveUDP())
+	}()
+	go func() {
+		defer wg.Done()
+		exit(d.serveTCP())
+	}()
+	wg.Wait()
+}
+
+// stop server
+func (d *DNSServer) Stop() {
+	// 释放connLimit的数据，防止restart的时候冲突
+	connlimit.RemoveLimitListener(d.GetProtocol())
+	if d.udpConn != nil {
+		_ = d.udpConn.Close()
+	}
+	if d.tcpListener != nil {
+		_ = d.tcpListener.Close()
+	}
+}
+
+// restart server
+func (d *DNSServer) Restart(option map[string]interface{}, api map[string]apiserver.APIConfig,
+	errCh chan error) error {
+	log.Infof("restart dnsserver new config: %+v", option)
+	d.restart = true
+	d.Stop()
+	if d.exitCh != nil {
+		<-d.exitCh
+	}
+	d.restart = false
+
+	if err := d.Initialize(context.Background(), option, api); err != nil {
+		log.Errorf("restart dnsserver initialize err: %s", err.Error())
+		return err
+	}
+	go d.Run(errCh)
+	return nil
+}
+
+// 处理UDP请求，同时处理的请求数不超过udpConcurrency
+func (d *DNSServer) serveUDP() error {
+	sem := make(chan struct{}, d.udpConcurrency)
+	for {
+		buf := make([]byte, maxUDPSize)
+		n, addr, err := d.udpConn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			out := d.handle(buf[:n], addr.String(), true)
+			if out == nil {
+				return
+			}
+			if _, err := d.udpConn.WriteTo(out, addr); err != nil {
+				log.Errorf("[DNS] write udp response to %s err: %s", addr.String(), err.Error())
+			}
+		}()
+	}
+}
+
+// 处理TCP连接，每个连接一个协程
+func (d *DNSServer) serveTCP() error {
+	for {
+		conn, err := d.tcpListener.Accept()
+		if err != nil {
+			return err
+		}
+		go d.handleTCPConn(conn)
+	}
+}
+
+// TCP报文以2个字节的长度开头，同一个连接上可以有多个请求
+func (d *DNSServer) handleTCPConn(conn net.Conn) {
+	defer conn.Close()
+	var head [2]byte
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(tcpIdleTimeout))
+		if _, err := io.ReadFull(conn, head[:]); err != nil {
+			return
+		}
+		buf := make([]byte, binary.BigEndian.Uint16(head[:]))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return
+		}
+		out := d.handle(buf, conn.RemoteAddr().String(), false)
+		if out == nil {
+			return
+		}
+		packet := make([]byte, 2+len(out))
+		binary.BigEndian.PutUint16(packet, uint16(len(out)))
+		copy(packet[2:], out)
+		if _, err := conn.Write(packet); err != nil {
+			log.Errorf("[DNS] write tcp response to %s err: %s", conn.RemoteAddr().String(), err.Error())
+			return
+		}
+	}
+}
+
+// 处理请求，并记录统计以及耗时过长的请求
+func (d *DNSServer) handle(data []byte, clientAddr string, udp bool) []byte {
+	start := time.Now()
+	out, code, qtype := d.resolver.handle(data, udp)
+	diff := time.Since(start)
+	apiName := "DNS:" + qtype.String()
+	if diff > time.Second {
+		log.Info("handling time > 1s",
+			zap.String("client-addr", clientAddr),
+			zap.String("api", apiName),
+			zap.Duration("handling-time", diff),
+		)
+	}
+	if d.statis != nil {
+		_ = d.statis.AddAPICall(apiName, int(code), diff.Nanoseconds())
+	}
+	return out
+}
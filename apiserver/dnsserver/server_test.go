@@ -0,0 +1,137 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dnsserver
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/model"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// TestDNSServer_Initialize 测试UDP并发数以及TCP连接数限制的配置
+func TestDNSServer_Initialize(t *testing.T) {
+	d := &DNSServer{}
+	if err := d.Initialize(context.Background(), map[string]interface{}{"listenPort": 8053}, nil); err != nil {
+		t.Fatalf("initialize err: %s", err.Error())
+	}
+	if d.udpConcurrency != defaultUDPConcurrency || d.connLimitConfig != nil {
+		t.Fatalf("default config not match: %d, %+v", d.udpConcurrency, d.connLimitConfig)
+	}
+
+	err := d.Initialize(context.Background(), map[string]interface{}{
+		"listenPort":     8053,
+		"udpConcurrency": 16,
+		"connLimit": map[interface{}]interface{}{
+			"openConnLimit":  true,
+			"maxConnPerHost": 8,
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("initialize err: %s", err.Error())
+	}
+	if d.udpConcurrency != 16 || d.connLimitConfig == nil || !d.connLimitConfig.OpenConnLimit ||
+		d.connLimitConfig.MaxConnPerHost != 8 {
+		t.Fatalf("config not match: %d, %+v", d.udpConcurrency, d.connLimitConfig)
+	}
+}
+
+// TestDNSServer_UDPConcurrency 测试同时处理的UDP请求数不超过配置
+func TestDNSServer_UDPConcurrency(t *testing.T) {
+	var active, maxActive int32
+	release := make(chan struct{})
+	r, err := newResolver("polaris.", 2, 0, func(name string, namespace string) *model.Service {
+		current := atomic.AddInt32(&active, 1)
+		for {
+			old := atomic.LoadInt32(&maxActive)
+			if current <= old || atomic.CompareAndSwapInt32(&maxActive, old, current) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&active, -1)
+		return nil
+	}, func(serviceID string) []*model.Instance {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("new resolver err: %s", err.Error())
+	}
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen udp err: %s", err.Error())
+	}
+	d := &DNSServer{udpConn: conn, resolver: r, udpConcurrency: 2}
+	done := make(chan error, 1)
+	go func() {
+		done <- d.serveUDP()
+	}()
+	defer func() {
+		_ = conn.Close()
+		<-done
+	}()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("dial udp err: %s", err.Error())
+	}
+	defer client.Close()
+
+	const total = 5
+	for i := 0; i < total; i++ {
+		req := &dnsmessage.Message{
+			Header: dnsmessage.Header{ID: uint16(i)},
+			Questions: []dnsmessage.Question{
+				{Name: dnsmessage.MustNewName("svc.Test.polaris."), Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET},
+			},
+		}
+		data, err := req.Pack()
+		if err != nil {
+			t.Fatalf("pack request err: %s", err.Error())
+		}
+		if _, err := client.Write(data); err != nil {
+			t.Fatalf("write request err: %s", err.Error())
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&active) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if got := atomic.LoadInt32(&active); got != 2 {
+		t.Fatalf("active requests should be 2, got %d", got)
+	}
+	close(release)
+
+	_ = client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, maxUDPSize)
+	for i := 0; i < total; i++ {
+		if _, err := client.Read(buf); err != nil {
+			t.Fatalf("read response %d err: %s", i, err.Error())
+		}
+	}
+	if got := atomic.LoadInt32(&maxActive); got != 2 {
+		t.Fatalf("max active requests should be 2, got %d", got)
+	}
+}
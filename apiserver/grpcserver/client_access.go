@@ -21,17 +21,21 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"strings"
 
 	api "github.com/polarismesh/polaris-server/common/api/v1"
 	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/tlsutil"
 	"github.com/polarismesh/polaris-server/common/utils"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
 
+// 单个订阅回复中的最大事件数
+const maxWatchEventsPerResponse = 100
+
 /**
  * @brief 客户端上报
  */
@@ -75,6 +79,16 @@ func (g *GRPCServer) Discover(server api.PolarisGRPC_DiscoverServer) error {
 	userAgent, _ := ctx.Value(utils.StringContext("user-agent")).(string)
 	method, _ := grpc.MethodFromServerStream(server)
 
+	// 订阅模式下，所有回复都经由推送器串行发送
+	send := server.Send
+	var pusher *discoverPusher
+	if g.pushConfig != nil && g.pushConfig.Open && isSubscribeMode(server.Context()) {
+		pusher = newDiscoverPusher(ctx, g.pushConfig, g.namingServer.Cache().Changed, g.discover)
+		pusher.run(server)
+		defer pusher.stop(nil)
+		send = pusher.reply
+	}
+
 	for {
 		in, err := server.Recv()
 		if nil != err {
@@ -96,7 +110,7 @@ func (g *GRPCServer) Discover(server api.PolarisGRPC_DiscoverServer) error {
 		// 是否允许访问
 		if ok := g.allowAccess(method); !ok {
 			resp := api.NewDiscoverResponse(api.ClientAPINotOpen)
-			if sendErr := server.Send(resp); sendErr != nil {
+			if sendErr := send(resp); sendErr != nil {
 				return sendErr
 			}
 			continue
@@ -105,35 +119,54 @@ func (g *GRPCServer) Discover(server api.PolarisGRPC_DiscoverServer) error {
 		// stream模式，需要对每个包进行检测
 		if code := g.enterRateLimit(clientIP, method); code != api.ExecuteSuccess {
 			resp := api.NewDiscoverResponse(code)
-			if err = server.Send(resp); err != nil {
+			if err = send(resp); err != nil {
 				return err
 			}
 			continue
 		}
 
-		var out *api.DiscoverResponse
-		switch in.Type {
-		case api.DiscoverRequest_INSTANCE:
-			out = g.namingServer.ServiceInstancesCache(ctx, in.Service)
-		case api.DiscoverRequest_ROUTING:
-			out = g.namingServer.GetRoutingConfigWithCache(ctx, in.Service)
-		case api.DiscoverRequest_RATE_LIMIT:
-			out = g.namingServer.GetRateLimitWithCache(ctx, in.Service)
-		case api.DiscoverRequest_CIRCUIT_BREAKER:
-			out = g.namingServer.GetCircuitBreakerWithCache(ctx, in.Service)
-		case api.DiscoverRequest_SERVICES:
-			out = g.namingServer.GetServiceWithCache(ctx, in.Service)
-		default:
-			out = api.NewDiscoverRoutingResponse(api.InvalidDiscoverResource, in.Service)
+		subscribe := pusher != nil && isPushableType(in.Type)
+		if subscribe && !pusher.allowSubscribe(in) {
+			resp := api.NewDiscoverResponse(api.SubscriptionExceedLimit)
+			resp.Service = in.Service
+			if err = send(resp); err != nil {
+				return err
+			}
+			continue
+		}
+
+		out := g.discover(ctx, in)
+		if subscribe {
+			pusher.subscribe(in, out)
 		}
 
-		err = server.Send(out)
+		err = send(out)
 		if err != nil {
 			return err
 		}
 	}
 }
 
+/**
+ * @brief 根据请求的资源类型执行一次发现
+ */
+func (g *GRPCServer) discover(ctx context.Context, in *api.DiscoverRequest) *api.DiscoverResponse {
+	switch in.Type {
+	case api.DiscoverRequest_INSTANCE:
+		return g.namingServer.DiscoverInstances(ctx, in)
+	case api.DiscoverRequest_ROUTING:
+		return g.namingServer.GetRoutingConfigWithCache(ctx, in.Service)
+	case api.DiscoverRequest_RATE_LIMIT:
+		return g.namingServer.GetRateLimitWithCache(ctx, in.Service)
+	case api.DiscoverRequest_CIRCUIT_BREAKER:
+		return g.namingServer.GetCircuitBreakerWithCache(ctx, in.Service)
+	case api.DiscoverRequest_SERVICES:
+		return g.namingServer.GetServiceWithCache(ctx, in.Service)
+	default:
+		return api.NewDiscoverRoutingResponse(api.InvalidDiscoverResource, in.Service)
+	}
+}
+
 /**
  * @brief 上报心跳
  */
@@ -142,6 +175,37 @@ func (g *GRPCServer) Heartbeat(ctx context.Context, in *api.Instance) (*api.Resp
 	return out, nil
 }
 
+/**
+ * @brief 订阅配置的变更事件
+ * @note 第一个回复包含需要补发的事件以及当前的序号，之后每有事件到达则推送一次
+ */
+func (g *GRPCServer) Watch(in *api.WatchRequest, server api.PolarisGRPC_WatchServer) error {
+	method, _ := grpc.MethodFromServerStream(server)
+	if ok := g.allowAccess(method); !ok {
+		return server.Send(api.NewWatchResponse(api.ClientAPINotOpen, 0, nil))
+	}
+
+	watcher, resp := g.namingServer.Watch(convertContext(server.Context()), in)
+	if resp != nil {
+		return server.Send(api.NewWatchResponse(resp.GetCode().GetValue(), 0, nil))
+	}
+	defer watcher.Close()
+
+	events, ok := watcher.Drain(maxWatchEventsPerResponse)
+	for {
+		if !ok {
+			return server.Send(api.NewWatchResponse(api.WatchEventOverflow, watcher.Seq(), nil))
+		}
+		if err := server.Send(api.NewWatchResponse(api.ExecuteSuccess, watcher.Seq(), events)); err != nil {
+			return err
+		}
+		events, ok = watcher.Next(server.Context(), maxWatchEventsPerResponse)
+		if server.Context().Err() != nil {
+			return nil
+		}
+	}
+}
+
 /**
  * @brief 将GRPC上下文转换成内部上下文
  */
@@ -164,10 +228,7 @@ func convertContext(ctx context.Context) context.Context {
 	address := ""
 	if pr, ok := peer.FromContext(ctx); ok && pr.Addr != nil {
 		address = pr.Addr.String()
-		addrSlice := strings.Split(address, ":")
-		if len(addrSlice) == 2 {
-			clientIP = addrSlice[0]
-		}
+		clientIP = utils.ParseClientIP(address)
 	}
 
 	ctx = context.Background()
@@ -178,17 +239,31 @@ func convertContext(ctx context.Context) context.Context {
 	return ctx
 }
 
+// 获取已经校验的客户端证书的身份，没有开启双向认证时返回空
+func parseClientIdentity(ctx context.Context) string {
+	pr, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	info, ok := pr.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return ""
+	}
+	return tlsutil.ClientIdentity(&info.State)
+}
+
 // 构造请求源
 func ParseGrpcOperator(ctx context.Context) string {
 	// 获取请求源
 	operator := "GRPC"
 	if pr, ok := peer.FromContext(ctx); ok && pr.Addr != nil {
-		address := pr.Addr.String()
-		addrSlice := strings.Split(address, ":")
-		if len(addrSlice) == 2 {
-			operator += ":" + addrSlice[0]
+		if clientIP := utils.ParseClientIP(pr.Addr.String()); clientIP != "" {
+			operator += ":" + clientIP
 		}
 	}
+	if identity := parseClientIdentity(ctx); identity != "" {
+		operator += "(" + identity + ")"
+	}
 
 	return operator
 }
@@ -0,0 +1,360 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// 客户端在Discover stream的metadata中携带该字段，声明使用的发现模式
+	discoverModeKey = "discover-mode"
+	// 订阅模式，服务端在数据变更时主动推送
+	discoverModeSubscribe = "subscribe"
+
+	defaultMaxSubscriptions = 128
+	defaultPushQueueSize    = 64
+	// 默认全量检查订阅数据的周期，数据变更时由缓存的通知唤醒
+	defaultPushSweepInterval = 30 * time.Second
+	// 发送队列满导致推送延迟时，重新检查的间隔
+	pushRetryInterval = time.Second
+)
+
+var (
+	errPusherStopped = errors.New("discover pusher has been stopped")
+)
+
+/**
+ * PushConfig Discover stream服务端推送的配置
+ */
+type PushConfig struct {
+	// 是否开启推送模式
+	Open bool `mapstructure:"open"`
+
+	// 单个stream最大的订阅数
+	MaxSubscriptions int `mapstructure:"maxSubscriptions"`
+
+	// 单个stream待发送的消息队列长度，队列满时推送会延迟到下一次检查
+	QueueSize int `mapstructure:"queueSize"`
+
+	// 全量检查订阅数据的周期，缓存数据变化时会立即检查
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+/**
+ * @brief 解析推送配置
+ */
+func parsePushConfig(raw map[interface{}]interface{}) (*PushConfig, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	config := &PushConfig{}
+	decodeConfig := &mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		Result:     config,
+	}
+	decoder, err := mapstructure.NewDecoder(decodeConfig)
+	if err != nil {
+		log.Errorf("push config new decoder err: %s", err.Error())
+		return nil, err
+	}
+	if err := decoder.Decode(raw); err != nil {
+		log.Errorf("parse push config(%+v) err: %s", raw, err.Error())
+		return nil, err
+	}
+
+	if config.MaxSubscriptions <= 0 {
+		config.MaxSubscriptions = defaultMaxSubscriptions
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultPushQueueSize
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultPushSweepInterval
+	}
+	return config, nil
+}
+
+/**
+ * @brief 判断stream是否使用订阅模式
+ */
+func isSubscribeMode(ctx context.Context) bool {
+	meta, exist := metadata.FromIncomingContext(ctx)
+	if !exist {
+		return false
+	}
+	modes := meta[discoverModeKey]
+	return len(modes) > 0 && modes[0] == discoverModeSubscribe
+}
+
+/**
+ * @brief 判断资源类型是否支持推送
+ */
+func isPushableType(typ api.DiscoverRequest_DiscoverRequestType) bool {
+	switch typ {
+	case api.DiscoverRequest_INSTANCE, api.DiscoverRequest_ROUTING,
+		api.DiscoverRequest_RATE_LIMIT, api.DiscoverRequest_CIRCUIT_BREAKER:
+		return true
+	default:
+		return false
+	}
+}
+
+// 执行一次发现请求的函数定义
+type discoverFunc func(ctx context.Context, in *api.DiscoverRequest) *api.DiscoverResponse
+
+// 获取缓存数据变化通知的函数定义，返回的管道在数据变化后被关闭
+type changedFunc func() <-chan struct{}
+
+// 单个订阅，记录客户端当前持有的数据状态
+type subscription struct {
+	typ       api.DiscoverRequest_DiscoverRequestType
+	name      string
+	namespace string
+	code      uint32
+	revision  string
+}
+
+/**
+ * discoverPusher 单个Discover stream的推送器
+ * @note 所有发往stream的消息都经由发送队列串行发送
+ */
+type discoverPusher struct {
+	ctx      context.Context
+	config   *PushConfig
+	changed  changedFunc
+	discover discoverFunc
+
+	mutex         sync.Mutex
+	subscriptions map[string]*subscription
+
+	sendCh chan *api.DiscoverResponse
+	stopCh chan struct{}
+	once   sync.Once
+	err    error
+}
+
+/**
+ * @brief 创建推送器，changed为空时只按照配置的周期检查
+ */
+func newDiscoverPusher(ctx context.Context, config *PushConfig, changed changedFunc,
+	discover discoverFunc) *discoverPusher {
+	return &discoverPusher{
+		ctx:           ctx,
+		config:        config,
+		changed:       changed,
+		discover:      discover,
+		subscriptions: make(map[string]*subscription),
+		sendCh:        make(chan *api.DiscoverResponse, config.QueueSize),
+		stopCh:        make(chan struct{}),
+	}
+}
+
+/**
+ * @brief 启动发送协程和变更检查协程
+ */
+func (p *discoverPusher) run(server api.PolarisGRPC_DiscoverServer) {
+	go p.sendLoop(server)
+	// 在启动前获取通知，避免遗漏协程启动期间的变化
+	go p.watchLoop(p.changedCh())
+}
+
+/**
+ * @brief 停止推送器，可以重复调用
+ */
+func (p *discoverPusher) stop(err error) {
+	p.once.Do(func() {
+		p.err = err
+		close(p.stopCh)
+	})
+}
+
+/**
+ * @brief 回复客户端的请求，队列满时阻塞等待
+ */
+func (p *discoverPusher) reply(resp *api.DiscoverResponse) error {
+	select {
+	case p.sendCh <- resp:
+		return nil
+	case <-p.stopCh:
+		if p.err != nil {
+			return p.err
+		}
+		return errPusherStopped
+	}
+}
+
+/**
+ * @brief 推送变更数据，队列满时放弃本次推送
+ */
+func (p *discoverPusher) tryPush(resp *api.DiscoverResponse) bool {
+	select {
+	case p.sendCh <- resp:
+		return true
+	default:
+		return false
+	}
+}
+
+/**
+ * @brief 订阅资源，记录客户端当前持有的数据
+ */
+func (p *discoverPusher) subscribe(in *api.DiscoverRequest, out *api.DiscoverResponse) {
+	key := subscriptionKey(in.Type, in.GetService())
+	code := out.GetCode().GetValue()
+	revision := out.GetService().GetRevision().GetValue()
+	if code == api.DataNoChange {
+		code = api.ExecuteSuccess
+		revision = in.GetService().GetRevision().GetValue()
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.subscriptions[key] = &subscription{
+		typ:       in.Type,
+		name:      in.GetService().GetName().GetValue(),
+		namespace: in.GetService().GetNamespace().GetValue(),
+		code:      code,
+		revision:  revision,
+	}
+}
+
+/**
+ * @brief 判断是否还能新增订阅
+ */
+func (p *discoverPusher) allowSubscribe(in *api.DiscoverRequest) bool {
+	key := subscriptionKey(in.Type, in.GetService())
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if _, ok := p.subscriptions[key]; ok {
+		return true
+	}
+	return len(p.subscriptions) < p.config.MaxSubscriptions
+}
+
+// 串行发送队列中的消息
+func (p *discoverPusher) sendLoop(server api.PolarisGRPC_DiscoverServer) {
+	for {
+		select {
+		case resp := <-p.sendCh:
+			if err := server.Send(resp); err != nil {
+				p.stop(err)
+				return
+			}
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+// 缓存数据变化时检查订阅的数据是否发生变更，并定时全量检查兜底
+func (p *discoverPusher) watchLoop(changedCh <-chan struct{}) {
+	interval := p.config.Interval
+	if interval <= 0 {
+		interval = defaultPushSweepInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var retryCh <-chan time.Time
+	for {
+		select {
+		case <-changedCh:
+			// 先获取下一次的通知再检查，避免遗漏检查期间的变化
+			changedCh = p.changedCh()
+		case <-ticker.C:
+		case <-retryCh:
+		case <-p.stopCh:
+			return
+		}
+		retryCh = nil
+		if !p.checkSubscriptions() {
+			retryCh = time.After(pushRetryInterval)
+		}
+	}
+}
+
+// 获取缓存数据变化的通知，没有通知时返回nil，只依赖定时检查
+func (p *discoverPusher) changedCh() <-chan struct{} {
+	if p.changed == nil {
+		return nil
+	}
+	return p.changed()
+}
+
+// 对比每个订阅的最新数据，有变更则推送，发送队列满导致推送延迟时返回false
+func (p *discoverPusher) checkSubscriptions() bool {
+	p.mutex.Lock()
+	subs := make([]*subscription, 0, len(p.subscriptions))
+	for _, sub := range p.subscriptions {
+		subs = append(subs, sub)
+	}
+	p.mutex.Unlock()
+
+	for _, sub := range subs {
+		req := &api.DiscoverRequest{
+			Type: sub.typ,
+			Service: &api.Service{
+				Name:      utils.NewStringValue(sub.name),
+				Namespace: utils.NewStringValue(sub.namespace),
+				Revision:  utils.NewStringValue(sub.revision),
+			},
+		}
+		resp := p.discover(p.ctx, req)
+		code := resp.GetCode().GetValue()
+		if code == api.DataNoChange {
+			continue
+		}
+		revision := resp.GetService().GetRevision().GetValue()
+		if code == sub.code && revision == sub.revision {
+			continue
+		}
+
+		// 发送队列已满，说明客户端消费不过来，本次不再推送，等待下次重新对比
+		if !p.tryPush(resp) {
+			log.Warn("[grpc] discover push queue is full, delay pushing",
+				zap.String("service", sub.name), zap.String("namespace", sub.namespace),
+				zap.String("type", sub.typ.String()))
+			return false
+		}
+
+		p.mutex.Lock()
+		sub.code = code
+		sub.revision = revision
+		p.mutex.Unlock()
+	}
+	return true
+}
+
+// 订阅的唯一标识
+func subscriptionKey(typ api.DiscoverRequest_DiscoverRequestType, service *api.Service) string {
+	return fmt.Sprintf("%d/%s/%s", typ, service.GetNamespace().GetValue(), service.GetName().GetValue())
+}
@@ -0,0 +1,231 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpcserver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// 模拟Discover stream，记录发送的消息
+type mockDiscoverServer struct {
+	grpc.ServerStream
+	sendCh chan *api.DiscoverResponse
+}
+
+func (m *mockDiscoverServer) Send(resp *api.DiscoverResponse) error {
+	m.sendCh <- resp
+	return nil
+}
+
+func (m *mockDiscoverServer) Recv() (*api.DiscoverRequest, error) {
+	return nil, nil
+}
+
+// 模拟缓存中的数据，revision可以随时修改
+type mockDiscoverData struct {
+	mutex    sync.Mutex
+	revision string
+}
+
+func (m *mockDiscoverData) setRevision(revision string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.revision = revision
+}
+
+func (m *mockDiscoverData) discover(_ context.Context, in *api.DiscoverRequest) *api.DiscoverResponse {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if in.GetService().GetRevision().GetValue() == m.revision {
+		return api.NewDiscoverInstanceResponse(api.DataNoChange, in.Service)
+	}
+	return api.NewDiscoverInstanceResponse(api.ExecuteSuccess, &api.Service{
+		Name:      in.GetService().GetName(),
+		Namespace: in.GetService().GetNamespace(),
+		Revision:  utils.NewStringValue(m.revision),
+	})
+}
+
+func newTestDiscoverRequest(name string) *api.DiscoverRequest {
+	return &api.DiscoverRequest{
+		Type: api.DiscoverRequest_INSTANCE,
+		Service: &api.Service{
+			Name:      utils.NewStringValue(name),
+			Namespace: utils.NewStringValue("Test"),
+		},
+	}
+}
+
+// TestParsePushConfig 测试解析推送配置
+func TestParsePushConfig(t *testing.T) {
+	config, err := parsePushConfig(map[interface{}]interface{}{
+		"open":     true,
+		"interval": "2s",
+	})
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	if !config.Open || config.Interval != 2*time.Second {
+		t.Fatalf("push config not match: %+v", config)
+	}
+	if config.MaxSubscriptions != defaultMaxSubscriptions || config.QueueSize != defaultPushQueueSize {
+		t.Fatalf("push config default value not match: %+v", config)
+	}
+}
+
+// TestIsSubscribeMode 测试通过metadata判断订阅模式
+func TestIsSubscribeMode(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs(discoverModeKey, discoverModeSubscribe))
+	if !isSubscribeMode(ctx) {
+		t.Fatalf("expect subscribe mode")
+	}
+	if isSubscribeMode(context.Background()) {
+		t.Fatalf("expect not subscribe mode")
+	}
+}
+
+// TestDiscoverPusher_Push 测试数据变更后推送
+func TestDiscoverPusher_Push(t *testing.T) {
+	data := &mockDiscoverData{revision: "v1"}
+	server := &mockDiscoverServer{sendCh: make(chan *api.DiscoverResponse, 10)}
+	config := &PushConfig{Open: true, MaxSubscriptions: 1, QueueSize: 10, Interval: 10 * time.Millisecond}
+	pusher := newDiscoverPusher(context.Background(), config, nil, data.discover)
+	pusher.run(server)
+	defer pusher.stop(nil)
+
+	in := newTestDiscoverRequest("svc")
+	out := data.discover(context.Background(), in)
+	pusher.subscribe(in, out)
+	if err := pusher.reply(out); err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	resp := <-server.sendCh
+	if resp.GetService().GetRevision().GetValue() != "v1" {
+		t.Fatalf("first response revision not match: %s", resp.GetService().GetRevision().GetValue())
+	}
+
+	// 数据不变更，不会推送
+	select {
+	case resp = <-server.sendCh:
+		t.Fatalf("unexpected push: %+v", resp)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// 数据变更，推送新的数据
+	data.setRevision("v2")
+	select {
+	case resp = <-server.sendCh:
+		if resp.GetService().GetRevision().GetValue() != "v2" {
+			t.Fatalf("push revision not match: %s", resp.GetService().GetRevision().GetValue())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("wait push timeout")
+	}
+
+	// 超过订阅上限
+	if pusher.allowSubscribe(newTestDiscoverRequest("other")) {
+		t.Fatalf("expect subscription exceed limit")
+	}
+	if !pusher.allowSubscribe(in) {
+		t.Fatalf("expect existed subscription allowed")
+	}
+}
+
+// TestDiscoverPusher_QueueFull 测试发送队列满时延迟推送
+func TestDiscoverPusher_QueueFull(t *testing.T) {
+	data := &mockDiscoverData{revision: "v1"}
+	config := &PushConfig{Open: true, MaxSubscriptions: 10, QueueSize: 1, Interval: time.Hour}
+	pusher := newDiscoverPusher(context.Background(), config, nil, data.discover)
+
+	for _, name := range []string{"svc1", "svc2"} {
+		in := newTestDiscoverRequest(name)
+		pusher.subscribe(in, data.discover(context.Background(), in))
+	}
+
+	data.setRevision("v2")
+	if pusher.checkSubscriptions() {
+		t.Fatalf("check should report delayed push")
+	}
+	if len(pusher.sendCh) != 1 {
+		t.Fatalf("expect 1 pending push, got %d", len(pusher.sendCh))
+	}
+
+	// 未推送成功的订阅，保留旧的revision，下个周期重新推送
+	<-pusher.sendCh
+	if !pusher.checkSubscriptions() {
+		t.Fatalf("check should push all changes")
+	}
+	resp := <-pusher.sendCh
+	if resp.GetService().GetRevision().GetValue() != "v2" {
+		t.Fatalf("delayed push revision not match: %s", resp.GetService().GetRevision().GetValue())
+	}
+	for _, sub := range pusher.subscriptions {
+		if sub.revision != "v2" {
+			t.Fatalf("subscription revision not updated: %+v", sub)
+		}
+	}
+}
+
+// TestDiscoverPusher_Changed 测试缓存数据变化的通知唤醒推送，不需要等待全量检查
+func TestDiscoverPusher_Changed(t *testing.T) {
+	data := &mockDiscoverData{revision: "v1"}
+	server := &mockDiscoverServer{sendCh: make(chan *api.DiscoverResponse, 10)}
+	config := &PushConfig{Open: true, MaxSubscriptions: 10, QueueSize: 10, Interval: time.Hour}
+
+	var mutex sync.Mutex
+	changedCh := make(chan struct{})
+	changed := func() <-chan struct{} {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return changedCh
+	}
+	notify := func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		close(changedCh)
+		changedCh = make(chan struct{})
+	}
+
+	pusher := newDiscoverPusher(context.Background(), config, changed, data.discover)
+	in := newTestDiscoverRequest("svc")
+	pusher.subscribe(in, data.discover(context.Background(), in))
+	pusher.run(server)
+	defer pusher.stop(nil)
+
+	for _, revision := range []string{"v2", "v3"} {
+		data.setRevision(revision)
+		notify()
+		select {
+		case resp := <-server.sendCh:
+			if resp.GetService().GetRevision().GetValue() != revision {
+				t.Fatalf("push revision not match: %s", resp.GetService().GetRevision().GetValue())
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("wait push of %s timeout", revision)
+		}
+	}
+}
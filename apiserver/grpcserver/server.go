@@ -23,17 +23,20 @@ import (
 	"io"
 	"net"
 	"net/http"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/polarismesh/polaris-server/apiserver"
 	api "github.com/polarismesh/polaris-server/common/api/v1"
 	"github.com/polarismesh/polaris-server/common/connlimit"
 	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/tlsutil"
+	"github.com/polarismesh/polaris-server/common/utils"
 	"github.com/polarismesh/polaris-server/naming"
 	"github.com/polarismesh/polaris-server/plugin"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
@@ -45,6 +48,8 @@ type GRPCServer struct {
 	listenIP        string
 	listenPort      uint32
 	connLimitConfig *connlimit.Config
+	tlsConfig       *tlsutil.Config
+	pushConfig      *PushConfig
 	start           bool
 	restart         bool
 	exitCh          chan struct{}
@@ -88,6 +93,20 @@ func (g *GRPCServer) Initialize(_ context.Context, option map[string]interface{}
 		}
 		g.connLimitConfig = connConfig
 	}
+	if raw, _ := option["tls"].(map[interface{}]interface{}); raw != nil {
+		tlsConfig, err := tlsutil.ParseTLSConfig(raw)
+		if err != nil {
+			return err
+		}
+		g.tlsConfig = tlsConfig
+	}
+	if raw, _ := option["push"].(map[interface{}]interface{}); raw != nil {
+		pushConfig, err := parsePushConfig(raw)
+		if err != nil {
+			return err
+		}
+		g.pushConfig = pushConfig
+	}
 	if rateLimit := plugin.GetRatelimit(); rateLimit != nil {
 		log.Infof("grpc server open the ratelimit")
 		g.ratelimit = rateLimit
@@ -108,7 +127,7 @@ func (g *GRPCServer) Run(errCh chan error) {
 		g.start = false
 	}()
 
-	address := fmt.Sprintf("%v:%v", g.listenIP, g.listenPort)
+	address := net.JoinHostPort(g.listenIP, strconv.Itoa(int(g.listenPort)))
 	listener, err := net.Listen("tcp", address)
 	if err != nil {
 		log.Errorf("%v", err)
@@ -130,10 +149,23 @@ func (g *GRPCServer) Run(errCh chan error) {
 
 	}
 
-	server := grpc.NewServer(
+	options := []grpc.ServerOption{
 		grpc.UnaryInterceptor(g.unaryInterceptor),
 		grpc.StreamInterceptor(g.streamInterceptor),
-	)
+	}
+	// 开启TLS，证书修改后自动重新加载
+	if g.tlsConfig != nil {
+		reloader, err := tlsutil.NewReloader(g.tlsConfig)
+		if err != nil {
+			log.Errorf("grpc server load tls cert err: %s", err.Error())
+			errCh <- err
+			return
+		}
+		defer reloader.Stop()
+		log.Infof("grpc server use tls, client auth: %s", g.tlsConfig.ClientAuth)
+		options = append(options, grpc.Creds(credentials.NewTLS(reloader.TLSConfig("h2"))))
+	}
+	server := grpc.NewServer(options...)
 
 	for name, config := range g.openAPI {
 		switch name {
@@ -273,10 +305,7 @@ func newVirtualStream(ctx context.Context, method string, stream grpc.ServerStre
 	if exist {
 		clientAddress = p.Addr.String()
 		// 解析获取clientIP
-		items := strings.Split(clientAddress, ":")
-		if len(items) == 2 {
-			clientIP = items[0]
-		}
+		clientIP = utils.ParseClientIP(clientAddress)
 	}
 
 	meta, exist := metadata.FromIncomingContext(ctx)
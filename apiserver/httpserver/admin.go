@@ -19,6 +19,8 @@ package httpserver
 
 import (
 	"github.com/emicklei/go-restful"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/metrics"
 )
 
 /**
@@ -28,6 +30,7 @@ func (h *HTTPServer) GetAdminServer() *restful.WebService {
 	ws := new(restful.WebService)
 
 	ws.Route(ws.GET("/").To(h.index))
+	ws.Route(ws.GET("/metrics").To(h.metrics))
 
 	return ws
 }
@@ -38,3 +41,13 @@ func (h *HTTPServer) GetAdminServer() *restful.WebService {
 func (h *HTTPServer) index(_ *restful.Request, rsp *restful.Response) {
 	rsp.Write([]byte("Polaris Server"))
 }
+
+/**
+ * metrics URL: "/metrics"，Prometheus格式的运行指标
+ */
+func (h *HTTPServer) metrics(_ *restful.Request, rsp *restful.Response) {
+	rsp.AddHeader(restful.HEADER_ContentType, metrics.ContentType)
+	if err := metrics.DefaultRegistry().WriteText(rsp); err != nil {
+		log.Errorf("[HTTPServer] write metrics err: %s", err.Error())
+	}
+}
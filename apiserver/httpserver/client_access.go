@@ -24,6 +24,7 @@ import (
 	"github.com/polarismesh/polaris-server/apiserver"
 	api "github.com/polarismesh/polaris-server/common/api/v1"
 	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/utils"
 	"go.uber.org/zap"
 )
 
@@ -31,7 +32,8 @@ import (
  * GetClientAccessServer get client access server
  */
 func (h *HTTPServer) GetClientAccessServer(include []string) (*restful.WebService, error) {
-	clientAccess := []string{apiserver.DiscoverAccess, apiserver.RegisterAccess, apiserver.HealthcheckAccess}
+	clientAccess := []string{apiserver.DiscoverAccess, apiserver.RegisterAccess, apiserver.HealthcheckAccess,
+		apiserver.WatchAccess}
 
 	ws := new(restful.WebService)
 
@@ -51,6 +53,8 @@ func (h *HTTPServer) GetClientAccessServer(include []string) (*restful.WebServic
 			h.addRegisterAccess(ws)
 		case apiserver.HealthcheckAccess:
 			h.addHealthCheckAccess(ws)
+		case apiserver.WatchAccess:
+			h.addWatchAccess(ws)
 		default:
 			log.Errorf("method %s does not exist in httpserver client access", item)
 			return nil, fmt.Errorf("method %s does not exist in httpserver client access", item)
@@ -66,6 +70,10 @@ func (h *HTTPServer) GetClientAccessServer(include []string) (*restful.WebServic
 func (h *HTTPServer) addDiscoverAccess(ws *restful.WebService) {
 	ws.Route(ws.POST("/ReportClient").To(h.ReportClient))
 	ws.Route(ws.POST("/Discover").To(h.Discover))
+	ws.Route(ws.GET("/services/{namespace}/{service}/instances").To(h.GetServiceInstances))
+	ws.Route(ws.GET("/services/{namespace}/{service}/routings").To(h.GetServiceRoutings))
+	ws.Route(ws.GET("/services/{namespace}/{service}/ratelimits").To(h.GetServiceRateLimits))
+	ws.Route(ws.GET("/services/{namespace}/{service}/circuitbreakers").To(h.GetServiceCircuitBreakers))
 }
 
 /**
@@ -83,6 +91,13 @@ func (h *HTTPServer) addHealthCheckAccess(ws *restful.WebService) {
 	ws.Route(ws.POST("/Heartbeat").To(h.Heartbeat))
 }
 
+/**
+ * @brief 增加变更事件订阅接口
+ */
+func (h *HTTPServer) addWatchAccess(ws *restful.WebService) {
+	ws.Route(ws.GET("/Watch").To(h.Watch).Produces(restful.MIME_JSON, mimeEventStream))
+}
+
 /**
  * ReportClient 客户端上报信息
  */
@@ -155,7 +170,7 @@ func (h *HTTPServer) Discover(req *restful.Request, rsp *restful.Response) {
 	var ret *api.DiscoverResponse
 	switch discoverRequest.Type {
 	case api.DiscoverRequest_INSTANCE:
-		ret = h.namingServer.ServiceInstancesCache(ctx, discoverRequest.Service)
+		ret = h.namingServer.DiscoverInstances(ctx, discoverRequest)
 	case api.DiscoverRequest_ROUTING:
 		ret = h.namingServer.GetRoutingConfigWithCache(ctx, discoverRequest.Service)
 	case api.DiscoverRequest_RATE_LIMIT:
@@ -184,5 +199,10 @@ func (h *HTTPServer) Heartbeat(req *restful.Request, rsp *restful.Response) {
 		return
 	}
 
+	// 其他server转发过来的心跳，直接在本机处理，naming层会校验请求是否来自其他server
+	if req.HeaderParameter(utils.HeartbeatForwardedHeader) != "" {
+		ctx = utils.WithHeartbeatForwarded(ctx)
+	}
+
 	handler.WriteHeaderAndProto(h.namingServer.Heartbeat(ctx, instance))
 }
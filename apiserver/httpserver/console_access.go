@@ -20,7 +20,9 @@ package httpserver
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strconv"
 
 	"github.com/emicklei/go-restful"
 	api "github.com/polarismesh/polaris-server/common/api/v1"
@@ -31,6 +33,9 @@ import (
 const (
 	defaultReadAccess string = "default-read"
 	defaultAccess     string = "default"
+
+	yamlMIME     = "application/x-yaml"
+	yamlTextMIME = "text/yaml"
 )
 
 /**
@@ -91,11 +96,14 @@ func (h *HTTPServer) addDefaultReadAccess(ws *restful.WebService) {
 	ws.Route(ws.GET("/instances/count").To(h.GetInstancesCount))
 
 	ws.Route(ws.POST("/routings").To(h.CreateRoutings))
+	ws.Route(ws.POST("/routings/validate").To(h.ValidateRoutings))
 	ws.Route(ws.GET("/routings").To(h.GetRoutings))
 
 	ws.Route(ws.POST("/ratelimits").To(h.CreateRateLimits))
+	ws.Route(ws.POST("/ratelimits/validate").To(h.ValidateRateLimits))
 	ws.Route(ws.GET("/ratelimits").To(h.GetRateLimits))
 
+	ws.Route(ws.POST("/circuitbreakers/validate").To(h.ValidateCircuitBreakers))
 	ws.Route(ws.GET("/circuitbreaker").To(h.GetCircuitBreaker))
 	ws.Route(ws.GET("/circuitbreaker/versions").To(h.GetCircuitBreakerVersions))
 	ws.Route(ws.GET("/circuitbreakers/master").To(h.GetMasterCircuitBreakers))
@@ -104,6 +112,14 @@ func (h *HTTPServer) addDefaultReadAccess(ws *restful.WebService) {
 
 	ws.Route(ws.GET("/platforms").To(h.GetPlatforms))
 	ws.Route(ws.GET("/platform/token").To(h.GetPlatformToken))
+
+	ws.Route(ws.GET("/history").To(h.GetHistories))
+	ws.Route(ws.GET("/watch").To(h.Watch).Produces(restful.MIME_JSON, mimeEventStream))
+
+	ws.Route(ws.POST("/users/login").To(h.Login))
+	ws.Route(ws.GET("/users").To(h.GetUsers))
+	ws.Route(ws.GET("/usergroups").To(h.GetUserGroups))
+	ws.Route(ws.GET("/roles").To(h.GetRoles))
 }
 
 /**
@@ -143,17 +159,22 @@ func (h *HTTPServer) addDefaultAccess(ws *restful.WebService) {
 	ws.Route(ws.POST("/routings").To(h.CreateRoutings))
 	ws.Route(ws.POST("/routings/delete").To(h.DeleteRoutings))
 	ws.Route(ws.PUT("/routings").To(h.UpdateRoutings))
+	ws.Route(ws.POST("/routings/validate").To(h.ValidateRoutings))
 	ws.Route(ws.GET("/routings").To(h.GetRoutings))
+	ws.Route(ws.POST("/routings/istio/import").To(h.ImportIstioRoutings).
+		Consumes(restful.MIME_JSON, yamlMIME, yamlTextMIME, "text/plain"))
 
 	ws.Route(ws.POST("/ratelimits").To(h.CreateRateLimits))
 	ws.Route(ws.POST("/ratelimits/delete").To(h.DeleteRateLimits))
 	ws.Route(ws.PUT("/ratelimits").To(h.UpdateRateLimits))
+	ws.Route(ws.POST("/ratelimits/validate").To(h.ValidateRateLimits))
 	ws.Route(ws.GET("/ratelimits").To(h.GetRateLimits))
 
 	ws.Route(ws.POST("/circuitbreakers").To(h.CreateCircuitBreakers))
 	ws.Route(ws.POST("/circuitbreakers/version").To(h.CreateCircuitBreakerVersions))
 	ws.Route(ws.POST("/circuitbreakers/delete").To(h.DeleteCircuitBreakers))
 	ws.Route(ws.PUT("/circuitbreakers").To(h.UpdateCircuitBreakers))
+	ws.Route(ws.POST("/circuitbreakers/validate").To(h.ValidateCircuitBreakers))
 	ws.Route(ws.POST("/circuitbreakers/release").To(h.ReleaseCircuitBreakers))
 	ws.Route(ws.POST("/circuitbreakers/unbind").To(h.UnBindCircuitBreakers))
 	ws.Route(ws.GET("/circuitbreaker").To(h.GetCircuitBreaker))
@@ -168,6 +189,24 @@ func (h *HTTPServer) addDefaultAccess(ws *restful.WebService) {
 	ws.Route(ws.GET("/platforms").To(h.GetPlatforms))
 	ws.Route(ws.GET("/platform/token").To(h.GetPlatformToken))
 
+	ws.Route(ws.GET("/history").To(h.GetHistories))
+	ws.Route(ws.GET("/watch").To(h.Watch).Produces(restful.MIME_JSON, mimeEventStream))
+
+	ws.Route(ws.POST("/users/login").To(h.Login))
+	ws.Route(ws.POST("/users").To(h.CreateUsers))
+	ws.Route(ws.POST("/users/delete").To(h.DeleteUsers))
+	ws.Route(ws.PUT("/users").To(h.UpdateUsers))
+	ws.Route(ws.GET("/users").To(h.GetUsers))
+
+	ws.Route(ws.POST("/usergroups").To(h.CreateUserGroups))
+	ws.Route(ws.POST("/usergroups/delete").To(h.DeleteUserGroups))
+	ws.Route(ws.PUT("/usergroups").To(h.UpdateUserGroups))
+	ws.Route(ws.GET("/usergroups").To(h.GetUserGroups))
+
+	ws.Route(ws.POST("/roles").To(h.CreateRoles))
+	ws.Route(ws.POST("/roles/delete").To(h.DeleteRoles))
+	ws.Route(ws.PUT("/roles").To(h.UpdateRoles))
+	ws.Route(ws.GET("/roles").To(h.GetRoles))
 }
 
 /**
@@ -628,6 +667,22 @@ func (h *HTTPServer) UpdateRoutings(req *restful.Request, rsp *restful.Response)
 	handler.WriteHeader(ret.GetCode().GetValue(), http.StatusOK)
 }
 
+/**
+ * ValidateRoutings 校验规则路由，不会落库
+ */
+func (h *HTTPServer) ValidateRoutings(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	var routeAddr RoutingArr
+	ctx, err := handler.Parse(&routeAddr)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewBatchWriteResponseWithMsg(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.namingServer.ValidateRoutingConfigs(ctx, routeAddr))
+}
+
 /**
  * GetRoutings 查询规则路由
  */
@@ -639,6 +694,25 @@ func (h *HTTPServer) GetRoutings(req *restful.Request, rsp *restful.Response) {
 	handler.WriteHeaderAndProto(ret)
 }
 
+/**
+ * ImportIstioRoutings 导入Istio的VirtualService以及DestinationRule为路由规则
+ * 请求体为YAML格式，query参数namespace用于指定服务所在的命名空间，overwrite为true时覆盖已有的路由规则
+ */
+func (h *HTTPServer) ImportIstioRoutings(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	content, err := ioutil.ReadAll(req.Request.Body)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewBatchWriteResponseWithMsg(api.ParseException, err.Error()))
+		return
+	}
+
+	overwrite, _ := strconv.ParseBool(req.QueryParameter("overwrite"))
+	ctx := handler.ParseHeaderContext()
+	ret := h.namingServer.ImportIstioRoutings(ctx, content, req.QueryParameter("namespace"), overwrite)
+	handler.WriteHeaderAndProto(ret)
+}
+
 /**
  * CreateRateLimits 创建限流规则
  */
@@ -698,6 +772,22 @@ func (h *HTTPServer) UpdateRateLimits(req *restful.Request, rsp *restful.Respons
 	handler.WriteHeader(ret.GetCode().GetValue(), http.StatusOK)
 }
 
+/**
+ * ValidateRateLimits 校验限流规则，不会落库
+ */
+func (h *HTTPServer) ValidateRateLimits(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	var rateLimits RateLimitArr
+	ctx, err := handler.Parse(&rateLimits)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewBatchWriteResponseWithMsg(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.namingServer.ValidateRateLimits(ctx, rateLimits))
+}
+
 /**
  * GetRateLimits 查询限流规则
  */
@@ -784,6 +874,22 @@ func (h *HTTPServer) UpdateCircuitBreakers(req *restful.Request, rsp *restful.Re
 	handler.WriteHeader(ret.GetCode().GetValue(), http.StatusOK)
 }
 
+/**
+ * ValidateCircuitBreakers 校验熔断规则，不会落库
+ */
+func (h *HTTPServer) ValidateCircuitBreakers(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	var circuitBreakers CircuitBreakerArr
+	ctx, err := handler.Parse(&circuitBreakers)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewBatchWriteResponseWithMsg(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.namingServer.ValidateCircuitBreakers(ctx, circuitBreakers))
+}
+
 /**
  * ReleaseCircuitBreakers 发布熔断规则
  */
@@ -985,6 +1091,210 @@ func (h *HTTPServer) GetPlatforms(req *restful.Request, rsp *restful.Response) {
 	handler.WriteHeaderAndProto(ret)
 }
 
+/**
+ * GetHistories 查询操作记录
+ */
+func (h *HTTPServer) GetHistories(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	queryParams := parseQueryParams(req)
+	ret := h.namingServer.GetHistories(handler.ParseHeaderContext(), queryParams)
+	handler.WriteHeaderAndProto(ret)
+}
+
+/**
+ * Login 用户登录
+ */
+func (h *HTTPServer) Login(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	user := &api.User{}
+	ctx, err := handler.Parse(user)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewResponseWithMsg(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.namingServer.Login(ctx, user))
+}
+
+/**
+ * CreateUsers 创建用户
+ */
+func (h *HTTPServer) CreateUsers(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	var users UserArr
+	ctx, err := handler.Parse(&users)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewBatchWriteResponseWithMsg(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.namingServer.CreateUsers(ctx, users))
+}
+
+/**
+ * UpdateUsers 修改用户
+ */
+func (h *HTTPServer) UpdateUsers(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	var users UserArr
+	ctx, err := handler.Parse(&users)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewBatchWriteResponseWithMsg(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.namingServer.UpdateUsers(ctx, users))
+}
+
+/**
+ * DeleteUsers 删除用户
+ */
+func (h *HTTPServer) DeleteUsers(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	var users UserArr
+	ctx, err := handler.Parse(&users)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewBatchWriteResponseWithMsg(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.namingServer.DeleteUsers(ctx, users))
+}
+
+/**
+ * GetUsers 查询用户
+ */
+func (h *HTTPServer) GetUsers(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	queryParams := parseQueryParams(req)
+	ret := h.namingServer.GetUsers(handler.ParseHeaderContext(), queryParams)
+	handler.WriteHeaderAndProto(ret)
+}
+
+/**
+ * CreateUserGroups 创建用户组
+ */
+func (h *HTTPServer) CreateUserGroups(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	var userGroups UserGroupArr
+	ctx, err := handler.Parse(&userGroups)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewBatchWriteResponseWithMsg(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.namingServer.CreateUserGroups(ctx, userGroups))
+}
+
+/**
+ * UpdateUserGroups 修改用户组
+ */
+func (h *HTTPServer) UpdateUserGroups(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	var userGroups UserGroupArr
+	ctx, err := handler.Parse(&userGroups)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewBatchWriteResponseWithMsg(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.namingServer.UpdateUserGroups(ctx, userGroups))
+}
+
+/**
+ * DeleteUserGroups 删除用户组
+ */
+func (h *HTTPServer) DeleteUserGroups(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	var userGroups UserGroupArr
+	ctx, err := handler.Parse(&userGroups)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewBatchWriteResponseWithMsg(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.namingServer.DeleteUserGroups(ctx, userGroups))
+}
+
+/**
+ * GetUserGroups 查询用户组
+ */
+func (h *HTTPServer) GetUserGroups(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	queryParams := parseQueryParams(req)
+	ret := h.namingServer.GetUserGroups(handler.ParseHeaderContext(), queryParams)
+	handler.WriteHeaderAndProto(ret)
+}
+
+/**
+ * CreateRoles 创建角色
+ */
+func (h *HTTPServer) CreateRoles(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	var roles RoleArr
+	ctx, err := handler.Parse(&roles)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewBatchWriteResponseWithMsg(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.namingServer.CreateRoles(ctx, roles))
+}
+
+/**
+ * UpdateRoles 修改角色
+ */
+func (h *HTTPServer) UpdateRoles(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	var roles RoleArr
+	ctx, err := handler.Parse(&roles)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewBatchWriteResponseWithMsg(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.namingServer.UpdateRoles(ctx, roles))
+}
+
+/**
+ * DeleteRoles 删除角色
+ */
+func (h *HTTPServer) DeleteRoles(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	var roles RoleArr
+	ctx, err := handler.Parse(&roles)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewBatchWriteResponseWithMsg(api.ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.namingServer.DeleteRoles(ctx, roles))
+}
+
+/**
+ * GetRoles 查询角色
+ */
+func (h *HTTPServer) GetRoles(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	queryParams := parseQueryParams(req)
+	ret := h.namingServer.GetRoles(handler.ParseHeaderContext(), queryParams)
+	handler.WriteHeaderAndProto(ret)
+}
+
 /**
  * GetPlatformToken 查询平台Token
  */
@@ -0,0 +1,257 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// 长轮询的最大等待时间，预留5s给查询和回包，避免超过http.Server的写超时
+	maxDiscoverWait = serverWriteTimeout - 5*time.Second
+	// 长轮询期间检查缓存revision的间隔，与缓存的刷新间隔保持一致
+	discoverPollInterval = time.Second
+	// 实例元数据过滤参数的前缀，例如metadata.env=prod
+	metadataFilterPrefix = "metadata."
+)
+
+// 从缓存中获取服务的某一类数据，service带上客户端已有的revision
+type discoverFunc func(ctx context.Context, service *api.Service) *api.DiscoverResponse
+
+/**
+ * GetServiceInstances 以REST的方式获取服务实例
+ * 支持healthy、isolate以及metadata.<key>的过滤条件
+ */
+func (h *HTTPServer) GetServiceInstances(req *restful.Request, rsp *restful.Response) {
+	filter, err := parseInstanceFilter(req)
+	if err != nil {
+		handler := &Handler{req, rsp}
+		handler.WriteHeaderAndProto(api.NewResponseWithMsg(api.InvalidParameter, err.Error()))
+		return
+	}
+
+	h.discoverWithRevision(req, rsp, func(ctx context.Context, service *api.Service) *api.DiscoverResponse {
+		resp := h.namingServer.ServiceInstancesCache(ctx, service)
+		if resp.GetCode().GetValue() == api.ExecuteSuccess {
+			resp.Instances = filter.apply(resp.Instances)
+		}
+		return resp
+	})
+}
+
+/**
+ * GetServiceRoutings 以REST的方式获取服务的路由配置
+ */
+func (h *HTTPServer) GetServiceRoutings(req *restful.Request, rsp *restful.Response) {
+	h.discoverWithRevision(req, rsp, h.namingServer.GetRoutingConfigWithCache)
+}
+
+/**
+ * GetServiceRateLimits 以REST的方式获取服务的限流规则
+ */
+func (h *HTTPServer) GetServiceRateLimits(req *restful.Request, rsp *restful.Response) {
+	h.discoverWithRevision(req, rsp, h.namingServer.GetRateLimitWithCache)
+}
+
+/**
+ * GetServiceCircuitBreakers 以REST的方式获取服务的熔断规则
+ */
+func (h *HTTPServer) GetServiceCircuitBreakers(req *restful.Request, rsp *restful.Response) {
+	h.discoverWithRevision(req, rsp, h.namingServer.GetCircuitBreakerWithCache)
+}
+
+/**
+ * @brief REST发现接口的公共处理
+ * 请求头If-None-Match与缓存的revision一致时返回304，带上wait参数时等待revision发生变化
+ */
+func (h *HTTPServer) discoverWithRevision(req *restful.Request, rsp *restful.Response, discover discoverFunc) {
+	handler := &Handler{req, rsp}
+
+	wait, err := parseDiscoverWait(req.QueryParameter("wait"))
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewResponseWithMsg(api.InvalidParameter, err.Error()))
+		return
+	}
+
+	ctx := handler.ParseHeaderContext()
+	service := &api.Service{
+		Namespace: utils.NewStringValue(req.PathParameter("namespace")),
+		Name:      utils.NewStringValue(req.PathParameter("service")),
+		Revision:  utils.NewStringValue(parseETag(req.HeaderParameter("If-None-Match"))),
+	}
+	log.Info("receive http rest discover request",
+		zap.String("path", req.Request.URL.Path),
+		zap.String("namespace", service.GetNamespace().GetValue()),
+		zap.String("service", service.GetName().GetValue()),
+		zap.String("client-address", req.Request.RemoteAddr),
+		zap.String("request-id", req.HeaderParameter("Request-Id")),
+	)
+
+	resp := discover(ctx, service)
+	if resp.GetCode().GetValue() == api.DataNoChange && wait > 0 {
+		resp = waitDiscoverChange(req.Request.Context(), wait, func() *api.DiscoverResponse {
+			return discover(ctx, service)
+		})
+	}
+
+	if resp == nil || resp.GetCode().GetValue() == api.DataNoChange {
+		rsp.AddHeader("ETag", formatETag(service.GetRevision().GetValue()))
+		handler.WriteHeader(api.DataNoChange, http.StatusNotModified)
+		return
+	}
+	if revision := resp.GetService().GetRevision().GetValue(); revision != "" {
+		rsp.AddHeader("ETag", formatETag(revision))
+	}
+	handler.WriteHeaderAndProto(resp)
+}
+
+// 等待缓存中的数据发生变化，超时或者连接断开时返回nil
+func waitDiscoverChange(ctx context.Context, wait time.Duration,
+	discover func() *api.DiscoverResponse) *api.DiscoverResponse {
+	ctx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+
+	ticker := time.NewTicker(discoverPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if resp := discover(); resp.GetCode().GetValue() != api.DataNoChange {
+			return resp
+		}
+	}
+}
+
+// 解析长轮询的等待时间，支持30s格式的时长以及秒数，超过上限按上限处理
+func parseDiscoverWait(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	wait, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, atoiErr := strconv.Atoi(value)
+		if atoiErr != nil {
+			return 0, fmt.Errorf("wait(%s) is invalid", value)
+		}
+		wait = time.Duration(seconds) * time.Second
+	}
+	if wait < 0 {
+		return 0, fmt.Errorf("wait(%s) is invalid", value)
+	}
+	if wait > maxDiscoverWait {
+		wait = maxDiscoverWait
+	}
+	return wait, nil
+}
+
+// 从If-None-Match中取出revision，只取第一个ETag
+func parseETag(value string) string {
+	if index := strings.Index(value, ","); index >= 0 {
+		value = value[:index]
+	}
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, "W/")
+	return strings.Trim(value, "\"")
+}
+
+// revision转换为ETag
+func formatETag(revision string) string {
+	return "\"" + revision + "\""
+}
+
+// 服务实例的过滤条件，为nil的条件不过滤
+type instanceFilter struct {
+	healthy  *bool
+	isolate  *bool
+	metadata map[string]string
+}
+
+// 从请求参数中解析服务实例的过滤条件
+func parseInstanceFilter(req *restful.Request) (*instanceFilter, error) {
+	filter := &instanceFilter{metadata: make(map[string]string)}
+	for key, values := range req.Request.URL.Query() {
+		if len(values) == 0 {
+			continue
+		}
+		switch {
+		case key == "healthy":
+			value, err := strconv.ParseBool(values[0])
+			if err != nil {
+				return nil, fmt.Errorf("healthy(%s) is invalid", values[0])
+			}
+			filter.healthy = &value
+		case key == "isolate":
+			value, err := strconv.ParseBool(values[0])
+			if err != nil {
+				return nil, fmt.Errorf("isolate(%s) is invalid", values[0])
+			}
+			filter.isolate = &value
+		case strings.HasPrefix(key, metadataFilterPrefix):
+			name := strings.TrimPrefix(key, metadataFilterPrefix)
+			if name == "" {
+				return nil, fmt.Errorf("metadata key is empty")
+			}
+			filter.metadata[name] = values[0]
+		}
+	}
+	return filter, nil
+}
+
+// 过滤服务实例，返回新的切片，不修改原有的数据
+func (f *instanceFilter) apply(instances []*api.Instance) []*api.Instance {
+	if f.healthy == nil && f.isolate == nil && len(f.metadata) == 0 {
+		return instances
+	}
+	out := make([]*api.Instance, 0, len(instances))
+	for _, instance := range instances {
+		if f.match(instance) {
+			out = append(out, instance)
+		}
+	}
+	return out
+}
+
+// 判断服务实例是否满足全部的过滤条件
+func (f *instanceFilter) match(instance *api.Instance) bool {
+	if f.healthy != nil && instance.GetHealthy().GetValue() != *f.healthy {
+		return false
+	}
+	if f.isolate != nil && instance.GetIsolate().GetValue() != *f.isolate {
+		return false
+	}
+	for key, value := range f.metadata {
+		if actual, ok := instance.GetMetadata()[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
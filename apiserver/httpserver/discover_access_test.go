@@ -0,0 +1,282 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/utils"
+)
+
+// 长轮询的等待时间需要小于http.Server的写超时
+func TestMaxDiscoverWait(t *testing.T) {
+	if maxDiscoverWait >= serverWriteTimeout {
+		t.Fatalf("max discover wait(%s) should be less than write timeout(%s)",
+			maxDiscoverWait, serverWriteTimeout)
+	}
+}
+
+// 测试ETag的解析
+func TestParseETag(t *testing.T) {
+	cases := map[string]string{
+		"":                  "",
+		"abc":               "abc",
+		"\"abc\"":           "abc",
+		"W/\"abc\"":         "abc",
+		" \"abc\" , \"d\"":  "abc",
+		"W/\"abc\",W/\"d\"": "abc",
+	}
+	for value, expect := range cases {
+		if revision := parseETag(value); revision != expect {
+			t.Fatalf("revision of %q should be %q, got %q", value, expect, revision)
+		}
+	}
+	if revision := parseETag(formatETag("abc")); revision != "abc" {
+		t.Fatalf("revision should be abc, got %s", revision)
+	}
+}
+
+// 测试长轮询等待时间的解析
+func TestParseDiscoverWait(t *testing.T) {
+	cases := []struct {
+		value  string
+		expect time.Duration
+	}{
+		{"", 0},
+		{"0", 0},
+		{"30", 30 * time.Second},
+		{"30s", 30 * time.Second},
+		{"500ms", 500 * time.Millisecond},
+		{"10m", maxDiscoverWait},
+		{"3600", maxDiscoverWait},
+	}
+	for _, c := range cases {
+		wait, err := parseDiscoverWait(c.value)
+		if err != nil {
+			t.Fatalf("wait(%s) should be valid, err: %s", c.value, err.Error())
+		}
+		if wait != c.expect {
+			t.Fatalf("wait(%s) should be %s, got %s", c.value, c.expect, wait)
+		}
+	}
+
+	for _, value := range []string{"-1", "-1s", "abc", "1x"} {
+		if _, err := parseDiscoverWait(value); err == nil {
+			t.Fatalf("wait(%s) should be invalid", value)
+		}
+	}
+}
+
+// 测试服务实例的过滤
+func TestInstanceFilter(t *testing.T) {
+	newInstance := func(id string, healthy bool, isolate bool, metadata map[string]string) *api.Instance {
+		return &api.Instance{
+			Id:       utils.NewStringValue(id),
+			Healthy:  utils.NewBoolValue(healthy),
+			Isolate:  utils.NewBoolValue(isolate),
+			Metadata: metadata,
+		}
+	}
+	instances := []*api.Instance{
+		newInstance("a", true, false, map[string]string{"env": "prod", "set": "1"}),
+		newInstance("b", false, false, map[string]string{"env": "prod"}),
+		newInstance("c", true, true, map[string]string{"env": "test"}),
+		newInstance("d", true, false, nil),
+	}
+	trueValue, falseValue := true, false
+
+	cases := []struct {
+		name   string
+		filter *instanceFilter
+		expect []string
+	}{
+		{"healthy", &instanceFilter{healthy: &trueValue}, []string{"a", "c", "d"}},
+		{"unhealthy", &instanceFilter{healthy: &falseValue}, []string{"b"}},
+		{"isolate", &instanceFilter{isolate: &trueValue}, []string{"c"}},
+		{"metadata", &instanceFilter{metadata: map[string]string{"env": "prod"}}, []string{"a", "b"}},
+		{"all metadata", &instanceFilter{metadata: map[string]string{"env": "prod", "set": "1"}}, []string{"a"}},
+		{"combined", &instanceFilter{healthy: &trueValue, isolate: &falseValue,
+			metadata: map[string]string{"env": "prod"}}, []string{"a"}},
+		{"no match", &instanceFilter{metadata: map[string]string{"env": "dev"}}, []string{}},
+	}
+	for _, c := range cases {
+		out := c.filter.apply(instances)
+		if len(out) != len(c.expect) {
+			t.Fatalf("%s: expect %d instances, got %d", c.name, len(c.expect), len(out))
+		}
+		for i, instance := range out {
+			if instance.GetId().GetValue() != c.expect[i] {
+				t.Fatalf("%s: expect instance %s, got %s", c.name, c.expect[i], instance.GetId().GetValue())
+			}
+		}
+	}
+
+	// 没有过滤条件时直接返回原有的切片
+	out := (&instanceFilter{metadata: map[string]string{}}).apply(instances)
+	if len(out) != len(instances) || &out[0] != &instances[0] {
+		t.Fatalf("instances should not be copied without filter")
+	}
+	// 过滤不修改原有的数据
+	if len(instances) != 4 || instances[1].GetId().GetValue() != "b" {
+		t.Fatalf("instances should not be modified")
+	}
+}
+
+// 测试从请求参数中解析过滤条件
+func TestParseInstanceFilter(t *testing.T) {
+	newRequest := func(query string) *restful.Request {
+		return restful.NewRequest(httptest.NewRequest(http.MethodGet, "/instances?"+query, nil))
+	}
+
+	filter, err := parseInstanceFilter(newRequest("healthy=true&isolate=false&metadata.env=prod&other=1"))
+	if err != nil {
+		t.Fatalf("filter should be valid, err: %s", err.Error())
+	}
+	if filter.healthy == nil || !*filter.healthy || filter.isolate == nil || *filter.isolate {
+		t.Fatalf("healthy and isolate are not parsed")
+	}
+	if len(filter.metadata) != 1 || filter.metadata["env"] != "prod" {
+		t.Fatalf("metadata is not parsed: %v", filter.metadata)
+	}
+
+	for _, query := range []string{"healthy=abc", "isolate=abc", "metadata.=prod"} {
+		if _, err := parseInstanceFilter(newRequest(query)); err == nil {
+			t.Fatalf("filter(%s) should be invalid", query)
+		}
+	}
+}
+
+// 测试长轮询的等待
+func TestWaitDiscoverChange(t *testing.T) {
+	noChange := func() *api.DiscoverResponse {
+		return api.NewDiscoverResponse(api.DataNoChange)
+	}
+
+	// 超时返回nil
+	start := time.Now()
+	if resp := waitDiscoverChange(context.Background(), 100*time.Millisecond, noChange); resp != nil {
+		t.Fatalf("response should be nil after timeout")
+	}
+	if time.Since(start) >= discoverPollInterval {
+		t.Fatalf("wait should return after timeout")
+	}
+
+	// 连接断开返回nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if resp := waitDiscoverChange(ctx, maxDiscoverWait, noChange); resp != nil {
+		t.Fatalf("response should be nil after context canceled")
+	}
+
+	// 数据变化后返回新的数据
+	var calls int32
+	resp := waitDiscoverChange(context.Background(), 5*discoverPollInterval, func() *api.DiscoverResponse {
+		if atomic.AddInt32(&calls, 1) < 2 {
+			return noChange()
+		}
+		return api.NewDiscoverResponse(api.ExecuteSuccess)
+	})
+	if resp.GetCode().GetValue() != api.ExecuteSuccess {
+		t.Fatalf("response should be changed, got %v", resp)
+	}
+}
+
+// 测试REST发现接口的304以及长轮询
+func TestDiscoverWithRevision(t *testing.T) {
+	var revision atomic.Value
+	revision.Store("r1")
+	var calls int32
+	discover := func(_ context.Context, service *api.Service) *api.DiscoverResponse {
+		atomic.AddInt32(&calls, 1)
+		current := revision.Load().(string)
+		if service.GetRevision().GetValue() == current {
+			return api.NewDiscoverResponse(api.DataNoChange)
+		}
+		return api.NewDiscoverServiceResponse(api.ExecuteSuccess, &api.Service{
+			Namespace: service.GetNamespace(),
+			Name:      service.GetName(),
+			Revision:  utils.NewStringValue(current),
+		})
+	}
+
+	h := &HTTPServer{}
+	container := restful.NewContainer()
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/{namespace}/{service}").To(func(req *restful.Request, rsp *restful.Response) {
+		h.discoverWithRevision(req, rsp, discover)
+	}))
+	container.Add(ws)
+
+	serve := func(query string, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/Test/svc"+query, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// 没有revision时返回全量数据以及ETag
+	recorder := serve("", "")
+	if recorder.Code != http.StatusOK || recorder.Header().Get("ETag") != "\"r1\"" {
+		t.Fatalf("expect 200 with etag r1, got %d %s", recorder.Code, recorder.Header().Get("ETag"))
+	}
+
+	// revision一致时返回304
+	recorder = serve("", "\"r1\"")
+	if recorder.Code != http.StatusNotModified || recorder.Header().Get("ETag") != "\"r1\"" {
+		t.Fatalf("expect 304 with etag r1, got %d %s", recorder.Code, recorder.Header().Get("ETag"))
+	}
+	if recorder.Body.Len() != 0 {
+		t.Fatalf("304 should not have body")
+	}
+
+	// 长轮询超时后返回304
+	atomic.StoreInt32(&calls, 0)
+	recorder = serve("?wait=100ms", "W/\"r1\"")
+	if recorder.Code != http.StatusNotModified {
+		t.Fatalf("expect 304 after wait timeout, got %d", recorder.Code)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("discover should be called once before timeout, got %d", atomic.LoadInt32(&calls))
+	}
+
+	// 长轮询期间数据发生变化，返回新的数据
+	go func() {
+		time.Sleep(discoverPollInterval / 2)
+		revision.Store("r2")
+	}()
+	recorder = serve("?wait=5s", "\"r1\"")
+	if recorder.Code != http.StatusOK || recorder.Header().Get("ETag") != "\"r2\"" {
+		t.Fatalf("expect 200 with etag r2, got %d %s", recorder.Code, recorder.Header().Get("ETag"))
+	}
+
+	// 非法的wait参数
+	recorder = serve("?wait=abc", "")
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expect 400 with invalid wait, got %d", recorder.Code)
+	}
+}
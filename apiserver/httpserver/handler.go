@@ -21,13 +21,13 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/emicklei/go-restful"
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/proto"
 	api "github.com/polarismesh/polaris-server/common/api/v1"
 	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/tlsutil"
 	"github.com/polarismesh/polaris-server/common/utils"
 	"go.uber.org/zap"
 )
@@ -45,15 +45,23 @@ type Handler struct {
  */
 func (h *Handler) Parse(message proto.Message) (context.Context, error) {
 	requestID := h.Request.HeaderParameter("Request-Id")
-	platformID := h.Request.HeaderParameter("Platform-Id")
-	platformToken := h.Request.HeaderParameter("Platform-Token")
-	token := h.Request.HeaderParameter("Polaris-Token")
-
 	if err := jsonpb.Unmarshal(h.Request.Request.Body, message); err != nil {
 		log.Error(err.Error(), zap.String("request-id", requestID))
 		return nil, err
 	}
 
+	return h.ParseHeaderContext(), nil
+}
+
+/**
+ * ParseHeaderContext 将请求头中的信息解析到context中，用于请求体不是proto的接口
+ */
+func (h *Handler) ParseHeaderContext() context.Context {
+	requestID := h.Request.HeaderParameter("Request-Id")
+	platformID := h.Request.HeaderParameter("Platform-Id")
+	platformToken := h.Request.HeaderParameter("Platform-Token")
+	token := h.Request.HeaderParameter("Polaris-Token")
+
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, utils.StringContext("request-id"), requestID)
 	ctx = context.WithValue(ctx, utils.StringContext("platform-id"), platformID)
@@ -62,20 +70,30 @@ func (h *Handler) Parse(message proto.Message) (context.Context, error) {
 		ctx = context.WithValue(ctx, utils.StringContext("polaris-token"), token)
 	}
 
+	// 开启双向认证时，操作人中记录客户端证书的身份
+	identity := tlsutil.ClientIdentity(h.Request.Request.TLS)
+
 	var operator string
-	addrSlice := strings.Split(h.Request.Request.RemoteAddr, ":")
-	if len(addrSlice) == 2 {
-		operator = "HTTP:" + addrSlice[0]
+	if clientIP := utils.ParseClientIP(h.Request.Request.RemoteAddr); clientIP != "" {
+		ctx = context.WithValue(ctx, utils.StringContext("client-ip"), clientIP)
+		operator = "HTTP:" + clientIP
 		if platformID != "" {
 			operator += "(" + platformID + ")"
+		} else if identity != "" {
+			operator += "(" + identity + ")"
 		}
 	}
 	if staffName := h.Request.HeaderParameter("Staffname"); staffName != "" {
 		operator = staffName
 	}
+	// 登录用户作为操作人
+	if user, ok := h.Request.Attribute("user").(string); ok && user != "" {
+		ctx = context.WithValue(ctx, utils.StringContext("user"), user)
+		operator = user
+	}
 	ctx = context.WithValue(ctx, utils.StringContext("operator"), operator)
 
-	return ctx, nil
+	return ctx
 }
 
 /**
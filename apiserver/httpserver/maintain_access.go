@@ -19,18 +19,25 @@ package httpserver
 
 import (
 	"encoding/json"
+	"io/ioutil"
 	"net/http"
 	"runtime/debug"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/emicklei/go-restful"
+	"github.com/polarismesh/polaris-server/apiserver"
 	api "github.com/polarismesh/polaris-server/common/api/v1"
 	"github.com/polarismesh/polaris-server/common/connlimit"
 	"github.com/polarismesh/polaris-server/common/log"
 	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming"
 )
 
+// 配置备份接口的路径前缀，导出的数据包含全部配置，需要鉴权
+const configBackupPath = "/maintain/v1/config/"
+
 // GetMaintainAccessServer 运维接口
 func (h *HTTPServer) GetMaintainAccessServer() *restful.WebService {
 	ws := new(restful.WebService)
@@ -42,6 +49,10 @@ func (h *HTTPServer) GetMaintainAccessServer() *restful.WebService {
 	ws.Route(ws.POST("/memory/free").To(h.FreeOSMemory))
 	ws.Route(ws.POST("/instance/clean").Consumes(restful.MIME_JSON).To(h.CleanInstance))
 	ws.Route(ws.GET("/instance/heartbeat").To(h.GetLastHeartbeat))
+	ws.Route(ws.GET("/config/export").To(h.ExportConfig).Produces(restful.MIME_JSON, yamlMIME))
+	ws.Route(ws.POST("/config/import").To(h.ImportConfig).
+		Consumes(restful.MIME_JSON, yamlMIME, yamlTextMIME, "text/plain"))
+	ws.Route(ws.POST("/config/reload").To(h.ReloadConfig))
 	return ws
 }
 
@@ -230,3 +241,127 @@ func (h *HTTPServer) GetLastHeartbeat(req *restful.Request, rsp *restful.Respons
 	ret := h.namingServer.GetLastHeartbeat(instance)
 	handler.WriteHeaderAndProto(ret)
 }
+
+// ExportConfig 导出配置备份
+// query参数：namespace，可选，可以指定多个，为空则导出全部命名空间
+//           format，可选，json或者yaml，默认为json
+//           with_token，可选，为true则导出命名空间、服务以及熔断规则的token
+func (h *HTTPServer) ExportConfig(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+	ctx := handler.ParseHeaderContext()
+
+	format := req.QueryParameter("format")
+	withToken, _ := strconv.ParseBool(req.QueryParameter("with_token"))
+	bundle, err := h.namingServer.ExportConfig(ctx, parseListParam(req, "namespace"), withToken)
+	if err != nil {
+		log.Errorf("[HTTP] export config err: %s", err.Error())
+		_ = rsp.WriteErrorString(backupErrorStatus(err), err.Error())
+		return
+	}
+	data, err := naming.EncodeBundle(bundle, format)
+	if err != nil {
+		_ = rsp.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	contentType := restful.MIME_JSON
+	if format == naming.BundleFormatYAML {
+		contentType = yamlMIME
+	}
+	rsp.AddHeader(restful.HEADER_ContentType, contentType)
+	rsp.WriteHeader(http.StatusOK)
+	_, _ = rsp.Write(data)
+}
+
+// ReloadConfig 重新加载配置文件，返回已经生效以及需要重启才能生效的配置项
+func (h *HTTPServer) ReloadConfig(req *restful.Request, rsp *restful.Response) {
+	if apiserver.ConfigReloader == nil {
+		_ = rsp.WriteErrorString(http.StatusServiceUnavailable, "config reload is not supported")
+		return
+	}
+
+	log.Info("[HTTP] start doing reload config")
+	result, err := apiserver.ConfigReloader()
+	if err != nil {
+		log.Errorf("[HTTP] reload config err: %s", err.Error())
+		var out struct {
+			*apiserver.ReloadResult
+			Error string `json:"error"`
+		}
+		out.ReloadResult = result
+		out.Error = err.Error()
+		_ = rsp.WriteHeaderAndJson(http.StatusInternalServerError, out, restful.MIME_JSON)
+		return
+	}
+	_ = rsp.WriteAsJson(result)
+}
+
+// ImportConfig 导入配置备份
+// query参数：dry_run，可选，为true则只检查不修改
+//           conflict，可选，资源已经存在时的策略：skip（默认）、overwrite、fail
+//           namespace，可选，可以指定多个，只导入指定命名空间的资源
+//           format，可选，json或者yaml，为空则根据Content-Type判断
+func (h *HTTPServer) ImportConfig(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+	ctx := handler.ParseHeaderContext()
+
+	conflict, err := naming.ParseConflictPolicy(req.QueryParameter("conflict"))
+	if err != nil {
+		_ = rsp.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	format := req.QueryParameter("format")
+	if format == "" {
+		contentType := req.HeaderParameter(restful.HEADER_ContentType)
+		if strings.Contains(contentType, yamlMIME) || strings.Contains(contentType, yamlTextMIME) {
+			format = naming.BundleFormatYAML
+		}
+	}
+
+	content, err := ioutil.ReadAll(req.Request.Body)
+	if err != nil {
+		log.Errorf("[HTTP] import config read body err: %s", err.Error())
+		_ = rsp.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	bundle, err := naming.DecodeBundle(content, format)
+	if err != nil {
+		log.Errorf("[HTTP] import config decode bundle err: %s", err.Error())
+		_ = rsp.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(req.QueryParameter("dry_run"))
+	opt := &naming.ImportOptions{
+		DryRun:     dryRun,
+		Conflict:   conflict,
+		Namespaces: parseListParam(req, "namespace"),
+	}
+	result, err := h.namingServer.ImportConfig(ctx, bundle, opt)
+	if err != nil {
+		_ = rsp.WriteErrorString(backupErrorStatus(err), err.Error())
+		return
+	}
+	_ = rsp.WriteAsJson(result)
+}
+
+// 配置备份错误对应的HTTP状态码
+func backupErrorStatus(err error) int {
+	if err == naming.ErrBackupNotAllowed {
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// 解析列表参数，支持多个参数以及逗号分隔
+func parseListParam(req *restful.Request, key string) []string {
+	var values []string
+	for _, value := range req.Request.URL.Query()[key] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
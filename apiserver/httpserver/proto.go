@@ -133,3 +133,45 @@ func (m *PlatformArr) String() string { return proto.CompactTextString(m) }
 
 // ProtoMessage proto message
 func (m *PlatformArr) ProtoMessage() {}
+
+/**
+ * UserArr 用户数组定义
+ */
+type UserArr []*api.User
+
+// Reset proto reset
+func (m *UserArr) Reset() { *m = UserArr{} }
+
+// String proto string
+func (m *UserArr) String() string { return proto.CompactTextString(m) }
+
+// ProtoMessage proto message
+func (m *UserArr) ProtoMessage() {}
+
+/**
+ * UserGroupArr 用户组数组定义
+ */
+type UserGroupArr []*api.UserGroup
+
+// Reset proto reset
+func (m *UserGroupArr) Reset() { *m = UserGroupArr{} }
+
+// String proto string
+func (m *UserGroupArr) String() string { return proto.CompactTextString(m) }
+
+// ProtoMessage proto message
+func (m *UserGroupArr) ProtoMessage() {}
+
+/**
+ * RoleArr 角色数组定义
+ */
+type RoleArr []*api.Role
+
+// Reset proto reset
+func (m *RoleArr) Reset() { *m = RoleArr{} }
+
+// String proto string
+func (m *RoleArr) String() string { return proto.CompactTextString(m) }
+
+// ProtoMessage proto message
+func (m *RoleArr) ProtoMessage() {}
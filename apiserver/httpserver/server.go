@@ -19,10 +19,12 @@ package httpserver
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
 	"net/http/pprof"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -33,8 +35,10 @@ import (
 	api "github.com/polarismesh/polaris-server/common/api/v1"
 	"github.com/polarismesh/polaris-server/common/connlimit"
 	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/tlsutil"
 	"github.com/polarismesh/polaris-server/common/utils"
 	"github.com/polarismesh/polaris-server/naming"
+	"github.com/polarismesh/polaris-server/naming/auth"
 	"github.com/polarismesh/polaris-server/plugin"
 	"go.uber.org/zap"
 )
@@ -46,6 +50,7 @@ type HTTPServer struct {
 	listenIP        string
 	listenPort      uint32
 	connLimitConfig *connlimit.Config
+	tlsConfig       *tlsutil.Config
 	option          map[string]interface{}
 	openAPI         map[string]apiserver.APIConfig
 	start           bool
@@ -66,6 +71,15 @@ type HTTPServer struct {
 const (
 	// Discover discover string
 	Discover string = "Discover"
+
+	// 用户Token的请求头前缀
+	bearerPrefix string = "Bearer "
+	// 用户登录接口，不需要携带用户Token
+	userLoginPath string = "/users/login"
+	// 没有匹配到路由的请求，统计时归为一类
+	unmatchedRoute string = "unmatched"
+	// 响应的写超时，长轮询类接口的最大等待时间需要小于该值
+	serverWriteTimeout = time.Minute
 )
 
 /**
@@ -100,6 +114,13 @@ func (h *HTTPServer) Initialize(_ context.Context, option map[string]interface{}
 		}
 		h.connLimitConfig = connLimitConfig
 	}
+	if raw, _ := option["tls"].(map[interface{}]interface{}); raw != nil {
+		tlsConfig, err := tlsutil.ParseTLSConfig(raw)
+		if err != nil {
+			return err
+		}
+		h.tlsConfig = tlsConfig
+	}
 	if rateLimit := plugin.GetRatelimit(); rateLimit != nil {
 		log.Infof("http server open the ratelimit")
 		h.rateLimit = rateLimit
@@ -138,7 +159,7 @@ func (h *HTTPServer) Run(errCh chan error) {
 	h.statis = plugin.GetStatis()
 
 	// 初始化http server
-	address := fmt.Sprintf("%v:%v", h.listenIP, h.listenPort)
+	address := net.JoinHostPort(h.listenIP, strconv.Itoa(int(h.listenPort)))
 
 	var wsContainer *restful.Container
 	wsContainer, err = h.createRestfulContainer()
@@ -147,7 +168,8 @@ func (h *HTTPServer) Run(errCh chan error) {
 		return
 	}
 
-	server := http.Server{Addr: address, Handler: wsContainer, WriteTimeout: 1 * time.Minute}
+	server := http.Server{Addr: address, Handler: wsContainer, WriteTimeout: serverWriteTimeout,
+		ConnContext: withConn}
 	var ln net.Listener
 	ln, err = net.Listen("tcp", address)
 	if err != nil {
@@ -168,6 +190,18 @@ func (h *HTTPServer) Run(errCh chan error) {
 			return
 		}
 	}
+	// 开启TLS，证书修改后自动重新加载
+	if h.tlsConfig != nil {
+		reloader, err := tlsutil.NewReloader(h.tlsConfig)
+		if err != nil {
+			log.Errorf("http server load tls cert err: %s", err.Error())
+			errCh <- err
+			return
+		}
+		defer reloader.Stop()
+		log.Infof("http server use tls, client auth: %s", h.tlsConfig.ClientAuth)
+		ln = tls.NewListener(ln, reloader.TLSConfig("http/1.1"))
+	}
 	h.server = &server
 
 	// 开始对外服务
@@ -337,8 +371,8 @@ func (h *HTTPServer) preprocess(req *restful.Request, rsp *restful.Response) err
 		)
 	}
 
-	// 管理端接口访问鉴权
-	if strings.Contains(requestURL, "naming") {
+	// 管理端接口以及配置备份接口访问鉴权
+	if strings.Contains(requestURL, "naming") || strings.HasPrefix(req.Request.URL.Path, configBackupPath) {
 		if err := h.enterAuth(req, rsp); err != nil {
 			return err
 		}
@@ -359,12 +393,7 @@ func (h *HTTPServer) postProcess(req *restful.Request, rsp *restful.Response) {
 	now := time.Now()
 
 	// 接口调用统计
-	path := req.Request.URL.Path
-	if path != "/" {
-		// 去掉最后一个"/"
-		path = strings.TrimSuffix(path, "/")
-	}
-	method := req.Request.Method + ":" + path
+	method := apiLabel(req)
 	startTime := req.Attribute("start-time").(time.Time)
 	code, ok := req.Attribute(utils.PolarisCode).(uint32)
 	if !ok {
@@ -387,10 +416,29 @@ func (h *HTTPServer) postProcess(req *restful.Request, rsp *restful.Response) {
 	_ = h.statis.AddAPICall(method, int(code), diff.Nanoseconds())
 }
 
+// apiLabel 接口统计的名字，使用路由模板而不是请求路径，避免统计维度无限增长
+func apiLabel(req *restful.Request) string {
+	path := req.SelectedRoutePath()
+	if path == "" {
+		// 方法和路径都由客户端决定，不能作为统计维度
+		return unmatchedRoute
+	}
+	if path != "/" {
+		// 去掉最后一个"/"
+		path = strings.TrimSuffix(path, "/")
+	}
+	return req.Request.Method + ":" + path
+}
+
 /**
  * @brief 访问鉴权
  */
 func (h *HTTPServer) enterAuth(req *restful.Request, rsp *restful.Response) error {
+	// 登录用户鉴权，与鉴权插件无关
+	if err := h.enterUserAuth(req, rsp); err != nil {
+		return err
+	}
+
 	// 判断鉴权插件是否开启
 	if h.auth == nil {
 		return nil
@@ -401,12 +449,12 @@ func (h *HTTPServer) enterAuth(req *restful.Request, rsp *restful.Response) erro
 	pToken := req.HeaderParameter("Platform-Token")
 
 	address := req.Request.RemoteAddr
-	segments := strings.Split(address, ":")
-	if len(segments) != 2 {
+	clientIP := utils.ParseClientIP(address)
+	if clientIP == "" {
 		return nil
 	}
 
-	if !h.auth.IsWhiteList(segments[0]) && !h.auth.Allow(pid, pToken) {
+	if !h.auth.IsWhiteList(clientIP) && !h.auth.Allow(pid, pToken) {
 		log.Error("http access is not allowed",
 			zap.String("client", address),
 			zap.String("request-id", rid),
@@ -418,6 +466,99 @@ func (h *HTTPServer) enterAuth(req *restful.Request, rsp *restful.Response) erro
 	return nil
 }
 
+/**
+ * @brief 登录用户鉴权
+ * 解析Authorization头中的用户Token，并校验读请求的权限，写请求的权限在naming层校验
+ */
+func (h *HTTPServer) enterUserAuth(req *restful.Request, rsp *restful.Response) error {
+	authority := h.namingServer.Authority()
+	rid := req.HeaderParameter("Request-Id")
+
+	header := req.HeaderParameter("Authorization")
+	if !strings.HasPrefix(header, bearerPrefix) {
+		if authority.UserRequired() && !strings.HasSuffix(req.Request.URL.Path, userLoginPath) {
+			log.Error("http access without user token", zap.String("request-id", rid),
+				zap.String("client", req.Request.RemoteAddr))
+			HTTPResponse(req, rsp, api.UserLoginRequired)
+			return errors.New("user login required")
+		}
+		return nil
+	}
+
+	user, err := authority.ParseUserToken(strings.TrimSpace(strings.TrimPrefix(header, bearerPrefix)))
+	if err != nil {
+		log.Error("http access with invalid user token", zap.String("request-id", rid),
+			zap.String("client", req.Request.RemoteAddr), zap.Error(err))
+		HTTPResponse(req, rsp, api.InvalidUserToken)
+		return err
+	}
+	req.SetAttribute("user", user)
+
+	if req.Request.Method != http.MethodGet {
+		return nil
+	}
+	resource, ok := readResource(req.Request.URL.Path)
+	if !ok {
+		return nil
+	}
+	resources := []string{resource}
+	if resource == recordResource {
+		// 操作记录以及变更事件按照请求的资源类型校验，没有指定时由naming层逐条过滤
+		resources = resources[:0]
+		for _, value := range parseListParam(req, "resource") {
+			resources = append(resources, auth.RecordResource(value))
+		}
+	}
+	for _, resource := range resources {
+		permission := &auth.Permission{
+			Resource:  resource,
+			Namespace: req.QueryParameter("namespace"),
+			Service:   req.QueryParameter("service"),
+			Action:    auth.ActionRead,
+		}
+		if resource == auth.ResourceNamespace && req.QueryParameter("name") != "" {
+			permission.Namespace = req.QueryParameter("name")
+		}
+		if !authority.VerifyUser(user, permission) {
+			log.Error("http access is not allowed for user", zap.String("request-id", rid),
+				zap.String("user", user), zap.String("path", req.Request.URL.Path))
+			HTTPResponse(req, rsp, api.Unauthorized)
+			return errors.New("user access is not allowed")
+		}
+	}
+	return nil
+}
+
+// 操作记录以及变更事件的读请求，具体的资源类型由请求参数指定
+const recordResource = "record"
+
+// 根据请求路径获取读请求对应的资源类型
+func readResource(path string) (string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) == 2 && segments[0] == "v1" && segments[1] == "Watch" {
+		return recordResource, true
+	}
+	if len(segments) < 3 {
+		return "", false
+	}
+	switch segments[2] {
+	case "namespaces", "namespace":
+		return auth.ResourceNamespace, true
+	case "services", "service", "instances":
+		return auth.ResourceService, true
+	case "routings":
+		return auth.ResourceRouting, true
+	case "ratelimits":
+		return auth.ResourceRateLimit, true
+	case "circuitbreaker", "circuitbreakers":
+		return auth.ResourceCircuitBreaker, true
+	case "history", "watch":
+		return recordResource, true
+	default:
+		return "", false
+	}
+}
+
 // 访问限制
 func (h *HTTPServer) enterRateLimit(req *restful.Request, rsp *restful.Response) error {
 	// 检查限流插件是否开启
@@ -429,11 +570,11 @@ func (h *HTTPServer) enterRateLimit(req *restful.Request, rsp *restful.Response)
 	// IP级限流
 	// 先获取当前请求的address
 	address := req.Request.RemoteAddr
-	segments := strings.Split(address, ":")
-	if len(segments) != 2 {
+	clientIP := utils.ParseClientIP(address)
+	if clientIP == "" {
 		return nil
 	}
-	if ok := h.rateLimit.Allow(plugin.IPRatelimit, segments[0]); !ok {
+	if ok := h.rateLimit.Allow(plugin.IPRatelimit, clientIP); !ok {
 		log.Error("ip ratelimit is not allow", zap.String("client", address),
 			zap.String("request-id", rid))
 		HTTPResponse(req, rsp, api.IPRateLimit)
@@ -462,6 +603,21 @@ type tcpKeepAliveListener struct {
 	*net.TCPListener
 }
 
+// 请求context中保存底层连接的key
+type connContextKey struct{}
+
+// 把底层连接保存到请求的context中，SSE等长连接需要单独设置写超时
+func withConn(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, conn)
+}
+
+// 重新设置请求所在连接的写超时，覆盖http.Server的全局写超时
+func setWriteDeadline(req *http.Request, deadline time.Time) {
+	if conn, ok := req.Context().Value(connContextKey{}).(net.Conn); ok {
+		_ = conn.SetWriteDeadline(deadline)
+	}
+}
+
 var defaultAlivePeriodTime = 3 * time.Minute
 
 // Accept 来自于net/http
@@ -0,0 +1,123 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httpserver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/polarismesh/polaris-server/naming/auth"
+)
+
+// 接口统计使用路由模板，未匹配的请求归为一类
+func TestAPILabel(t *testing.T) {
+	var label string
+	container := restful.NewContainer()
+	container.Filter(func(req *restful.Request, rsp *restful.Response, chain *restful.FilterChain) {
+		chain.ProcessFilter(req, rsp)
+		label = apiLabel(req)
+	})
+	ws := new(restful.WebService)
+	ws.Path("/naming/v1")
+	ws.Route(ws.GET("/instances/{id}/").To(func(req *restful.Request, rsp *restful.Response) {}))
+	container.Add(ws)
+
+	cases := []struct {
+		method string
+		path   string
+		expect string
+	}{
+		{http.MethodGet, "/naming/v1/instances/abc/", "GET:/naming/v1/instances/{id}"},
+		{http.MethodGet, "/naming/v1/instances/def/", "GET:/naming/v1/instances/{id}"},
+		{http.MethodGet, "/naming/v1/unknown/abc", unmatchedRoute},
+		{"FOO", "/naming/v1/instances/abc/", unmatchedRoute},
+	}
+	for _, c := range cases {
+		label = ""
+		container.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(c.method, c.path, nil))
+		if label != c.expect {
+			t.Fatalf("api label of %s %s should be %s, got %s", c.method, c.path, c.expect, label)
+		}
+	}
+}
+
+// 长轮询的等待时间需要小于http.Server的写超时
+func TestMaxWatchTimeout(t *testing.T) {
+	if maxWatchTimeout >= serverWriteTimeout {
+		t.Fatalf("max watch timeout(%s) should be less than write timeout(%s)",
+			maxWatchTimeout, serverWriteTimeout)
+	}
+}
+
+// 长连接重新设置写超时后，可以持续写入超过http.Server的写超时
+func TestSetWriteDeadline(t *testing.T) {
+	writeTimeout := 200 * time.Millisecond
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		for i := 0; i < 3; i++ {
+			if i > 0 {
+				time.Sleep(writeTimeout)
+			}
+			setWriteDeadline(r, time.Now().Add(writeTimeout))
+			_, _ = fmt.Fprintf(w, "%d", i)
+			flusher.Flush()
+		}
+	}))
+	server.Config.WriteTimeout = writeTimeout
+	server.Config.ConnContext = withConn
+	server.Start()
+	defer server.Close()
+
+	rsp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("get err: %s", err.Error())
+	}
+	defer rsp.Body.Close()
+	body, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		t.Fatalf("read err: %s", err.Error())
+	}
+	if string(body) != "012" {
+		t.Fatalf("body should be 012, got %s", string(body))
+	}
+}
+
+// 测试读请求路径对应的资源类型，操作记录以及变更事件由请求参数指定资源类型
+func TestReadResource(t *testing.T) {
+	cases := map[string]string{
+		"/naming/v1/namespaces":        auth.ResourceNamespace,
+		"/naming/v1/instances":         auth.ResourceService,
+		"/naming/v1/routings":          auth.ResourceRouting,
+		"/naming/v1/history":           recordResource,
+		"/naming/v1/watch":             recordResource,
+		"/v1/Watch":                    recordResource,
+		"/v1/Discover":                 "",
+		"/maintain/v1/log/outputlevel": "",
+	}
+	for path, expect := range cases {
+		resource, ok := readResource(path)
+		if resource != expect || ok != (expect != "") {
+			t.Fatalf("resource of %s should be %q, got %q", path, expect, resource)
+		}
+	}
+}
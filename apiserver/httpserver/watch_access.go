@@ -0,0 +1,181 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/golang/protobuf/jsonpb"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming"
+	"go.uber.org/zap"
+)
+
+const (
+	mimeEventStream = "text/event-stream"
+
+	// 单次返回的最大事件数
+	maxWatchEventsPerResponse = 100
+	// 长轮询默认以及最大的等待时间，最大等待时间需要小于http.Server的写超时
+	defaultWatchTimeout = 30 * time.Second
+	maxWatchTimeout     = serverWriteTimeout - 5*time.Second
+	// SSE连接的心跳间隔
+	watchPingInterval = 15 * time.Second
+	// SSE连接单次推送的写超时，每次推送前重新设置
+	watchWriteTimeout = 10 * time.Second
+)
+
+/**
+ * Watch 订阅配置的变更事件
+ * 请求头Accept为text/event-stream时以SSE推送，否则为长轮询：有事件立即返回，没有则等待至多timeout秒
+ */
+func (h *HTTPServer) Watch(req *restful.Request, rsp *restful.Response) {
+	handler := &Handler{req, rsp}
+
+	watchReq, err := parseWatchRequest(req)
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewWatchResponse(api.InvalidParameter, 0, nil))
+		return
+	}
+	watcher, resp := h.namingServer.Watch(handler.ParseHeaderContext(), watchReq)
+	if resp != nil {
+		handler.WriteHeaderAndProto(api.NewWatchResponse(resp.GetCode().GetValue(), 0, nil))
+		return
+	}
+	defer watcher.Close()
+
+	if strings.Contains(req.HeaderParameter("Accept"), mimeEventStream) {
+		h.watchEventStream(handler, watcher)
+		return
+	}
+
+	events, ok := watcher.Drain(maxWatchEventsPerResponse)
+	if ok && len(events) == 0 {
+		timeout := defaultWatchTimeout
+		if value := req.QueryParameter("timeout"); value != "" {
+			seconds, err := strconv.Atoi(value)
+			if err != nil || seconds < 0 {
+				handler.WriteHeaderAndProto(api.NewWatchResponse(api.InvalidParameter, 0, nil))
+				return
+			}
+			timeout = time.Duration(seconds) * time.Second
+			if timeout > maxWatchTimeout {
+				timeout = maxWatchTimeout
+			}
+		}
+		ctx, cancel := context.WithTimeout(req.Request.Context(), timeout)
+		events, ok = watcher.Next(ctx, maxWatchEventsPerResponse)
+		cancel()
+	}
+	if !ok {
+		handler.WriteHeaderAndProto(api.NewWatchResponse(api.WatchEventOverflow, watcher.Seq(), nil))
+		return
+	}
+	handler.WriteHeaderAndProto(api.NewWatchResponse(api.ExecuteSuccess, watcher.Seq(), events))
+}
+
+// 以SSE的方式推送变更事件，直到连接断开或者订阅结束
+func (h *HTTPServer) watchEventStream(handler *Handler, watcher *naming.Watcher) {
+	requestID := handler.Request.HeaderParameter(utils.PolarisRequestID)
+	flusher, ok := handler.Response.ResponseWriter.(http.Flusher)
+	if !ok {
+		handler.WriteHeaderAndProto(api.NewWatchResponse(api.ExecuteException, 0, nil))
+		return
+	}
+
+	// SSE连接会持续超过http.Server的写超时，改为每次推送前单独设置写超时
+	httpReq := handler.Request.Request
+	setWriteDeadline(httpReq, time.Now().Add(watchWriteTimeout))
+
+	handler.Request.SetAttribute(utils.PolarisCode, api.ExecuteSuccess)
+	handler.Response.AddHeader("Content-Type", mimeEventStream)
+	handler.Response.AddHeader("Cache-Control", "no-cache")
+	handler.Response.AddHeader(utils.PolarisRequestID, requestID)
+	handler.Response.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(watchPingInterval)
+	defer ticker.Stop()
+	marshaler := jsonpb.Marshaler{}
+	done := httpReq.Context().Done()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			setWriteDeadline(httpReq, time.Now().Add(watchWriteTimeout))
+			if _, err := fmt.Fprint(handler.Response, ": ping\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-watcher.Events():
+			setWriteDeadline(httpReq, time.Now().Add(watchWriteTimeout))
+			if !ok {
+				// 订阅者消费过慢被断开，通知客户端从最后收到的序号重新订阅
+				_, _ = fmt.Fprintf(handler.Response, "event: error\ndata: {\"code\":%d,\"info\":%q}\n\n",
+					api.WatchEventOverflow, api.Code2Info(api.WatchEventOverflow))
+				flusher.Flush()
+				return
+			}
+			data, err := marshaler.MarshalToString(event)
+			if err != nil {
+				log.Error(err.Error(), zap.String("request-id", requestID))
+				continue
+			}
+			if _, err := fmt.Fprintf(handler.Response, "id: %d\ndata: %s\n\n",
+				event.GetSeq().GetValue(), data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// 从请求参数中解析订阅条件，未指定seq时取SSE断线重连的Last-Event-ID
+func parseWatchRequest(req *restful.Request) (*api.WatchRequest, error) {
+	watchReq := &api.WatchRequest{Resources: parseListParam(req, "resource")}
+	if namespace := req.QueryParameter("namespace"); namespace != "" {
+		watchReq.Namespace = utils.NewStringValue(namespace)
+	}
+	if service := req.QueryParameter("service"); service != "" {
+		watchReq.Service = utils.NewStringValue(service)
+	}
+	if revision := req.QueryParameter("revision"); revision != "" {
+		watchReq.Revision = utils.NewStringValue(revision)
+	}
+
+	seq := req.QueryParameter("seq")
+	if seq == "" {
+		seq = req.HeaderParameter("Last-Event-ID")
+	}
+	if seq != "" {
+		value, err := strconv.ParseUint(seq, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		watchReq.Seq = utils.NewUInt64Value(value)
+	}
+	return watchReq, nil
+}
@@ -19,8 +19,8 @@ package l5pbserver
 
 import (
 	"context"
-	"fmt"
 	"net"
+	"strconv"
 	"time"
 
 	"github.com/polarismesh/polaris-server/apiserver"
@@ -97,7 +97,7 @@ func (l *L5pbserver) Initialize(_ context.Context, option map[string]interface{}
 func (l *L5pbserver) Run(errCh chan error) {
 	log.Infof("start l5pbserver")
 
-	address := fmt.Sprintf("%v:%v", l.listenIP, l.listenPort)
+	address := net.JoinHostPort(l.listenIP, strconv.Itoa(int(l.listenPort)))
 	listener, err := net.Listen("tcp", address)
 	if err != nil {
 		log.Errorf("listen error: %v", err)
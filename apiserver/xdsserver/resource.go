@@ -0,0 +1,552 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserver
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	ratelimitconf "github.com/envoyproxy/go-control-plane/envoy/config/ratelimit/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	ratelimit "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
+	router "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+)
+
+const (
+	// 出流量的监听器以及路由配置的名字
+	outboundName = "polaris-outbound"
+	// 实例元数据在endpoint中的filter名，用于子集负载均衡
+	lbMetadataFilter = "envoy.lb"
+	// 限流服务的domain
+	rateLimitDomain = "polaris"
+
+	httpConnectionManagerFilter = "envoy.filters.network.http_connection_manager"
+	routerFilter                = "envoy.filters.http.router"
+	rateLimitFilter             = "envoy.filters.http.ratelimit"
+)
+
+/**
+ * @brief xDS资源的数据来源
+ */
+type resourceSource interface {
+	// 全部的服务，不包括服务别名
+	services() []*model.Service
+	// 服务下的全部实例
+	instances(serviceID string) []*model.Instance
+	// 服务的路由配置，不存在返回nil
+	routing(service *model.Service) *api.Routing
+	// 服务的限流规则
+	rateLimits(service *model.Service) []*api.Rule
+	// 服务的熔断规则，不存在返回nil
+	circuitBreaker(service *model.Service) *api.CircuitBreaker
+}
+
+/**
+ * @brief 请求xDS资源的节点
+ * service和namespace来自节点的元数据，用于匹配规则的来源，namespace同时限定下发的服务范围
+ */
+type nodeInfo struct {
+	id        string
+	service   string
+	namespace string
+}
+
+// 节点的标识，标识相同的节点生成的资源相同
+func (n *nodeInfo) key() string {
+	return n.service + "/" + n.namespace
+}
+
+// 规则的来源是否匹配节点，为空或者为*表示匹配全部
+func (n *nodeInfo) matchSource(service string, namespace string) bool {
+	return matchName(service, n.service) && matchName(namespace, n.namespace)
+}
+
+func matchName(rule string, value string) bool {
+	return rule == "" || rule == "*" || rule == value
+}
+
+// 按照类型分组的xDS资源
+type resourceSet [types.UnknownType][]types.Resource
+
+/**
+ * @brief 把缓存中的服务、实例以及规则转换为xDS资源
+ * 每个服务对应一个EDS类型的cluster，名字为<service>.<namespace>
+ * 路由规则转换为出流量路由配置中的virtual host，熔断规则转换为cluster的异常点检测
+ * 配置了限流服务时，限流规则转换为virtual host的限流描述符
+ */
+type resourceBuilder struct {
+	source           resourceSource
+	outboundIP       string
+	outboundPort     uint32
+	connectTimeout   time.Duration
+	rateLimitCluster string
+}
+
+// 为节点生成全部的xDS资源
+func (b *resourceBuilder) build(node *nodeInfo) resourceSet {
+	services := b.source.services()
+	sort.Slice(services, func(i, j int) bool {
+		return clusterName(services[i]) < clusterName(services[j])
+	})
+
+	var set resourceSet
+	subsets := make(map[string]map[string][]string)
+	var hosts []*route.VirtualHost
+	for _, service := range services {
+		if node.namespace != "" && service.Namespace != node.namespace {
+			continue
+		}
+		hosts = append(hosts, b.buildVirtualHost(node, service, subsets))
+	}
+	for _, service := range services {
+		if node.namespace != "" && service.Namespace != node.namespace {
+			continue
+		}
+		name := clusterName(service)
+		set[types.Cluster] = append(set[types.Cluster], b.buildCluster(node, service, subsets[name]))
+		set[types.Endpoint] = append(set[types.Endpoint],
+			buildLoadAssignment(name, b.source.instances(service.ID)))
+	}
+	set[types.Route] = []types.Resource{&route.RouteConfiguration{Name: outboundName, VirtualHosts: hosts}}
+	if outbound := b.buildListener(); outbound != nil {
+		set[types.Listener] = []types.Resource{outbound}
+	}
+	return set
+}
+
+// 服务对应的cluster名
+func clusterName(service *model.Service) string {
+	return service.Name + "." + service.Namespace
+}
+
+// 规则中的目标服务对应的cluster名，为空或者为*时表示规则所属的服务
+func destClusterName(service *model.Service, name string, namespace string) string {
+	if name == "" || name == "*" {
+		name = service.Name
+	}
+	if namespace == "" || namespace == "*" {
+		namespace = service.Namespace
+	}
+	return name + "." + namespace
+}
+
+// 生成服务的cluster，使用ADS获取endpoint
+func (b *resourceBuilder) buildCluster(node *nodeInfo, service *model.Service,
+	selectors map[string][]string) *cluster.Cluster {
+	out := &cluster.Cluster{
+		Name:                 clusterName(service),
+		ClusterDiscoveryType: &cluster.Cluster_Type{Type: cluster.Cluster_EDS},
+		EdsClusterConfig:     &cluster.Cluster_EdsClusterConfig{EdsConfig: adsConfigSource()},
+		ConnectTimeout:       ptypes.DurationProto(b.connectTimeout),
+		LbPolicy:             cluster.Cluster_ROUND_ROBIN,
+		OutlierDetection:     buildOutlierDetection(node, b.source.circuitBreaker(service)),
+	}
+	if len(selectors) > 0 {
+		keys := make([]string, 0, len(selectors))
+		for key := range selectors {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		out.LbSubsetConfig = &cluster.Cluster_LbSubsetConfig{
+			FallbackPolicy: cluster.Cluster_LbSubsetConfig_ANY_ENDPOINT,
+		}
+		for _, key := range keys {
+			out.LbSubsetConfig.SubsetSelectors = append(out.LbSubsetConfig.SubsetSelectors,
+				&cluster.Cluster_LbSubsetConfig_LbSubsetSelector{Keys: selectors[key]})
+		}
+	}
+	return out
+}
+
+/**
+ * @brief 熔断规则转换为异常点检测
+ * 取第一个来源匹配节点的被调规则，连续错误数和错误率分别对应consecutive_5xx和failure_percentage
+ */
+func buildOutlierDetection(node *nodeInfo, cb *api.CircuitBreaker) *cluster.OutlierDetection {
+	for _, rule := range cb.GetInbounds() {
+		if !matchCbSources(node, rule.GetSources()) || len(rule.GetDestinations()) == 0 {
+			continue
+		}
+		dest := rule.GetDestinations()[0]
+		policy := dest.GetPolicy()
+		out := &cluster.OutlierDetection{
+			Interval:           dest.GetMetricWindow(),
+			BaseEjectionTime:   dest.GetRecover().GetSleepWindow(),
+			MaxEjectionPercent: policy.GetMaxEjectionPercent(),
+		}
+		if consecutive := policy.GetConsecutive(); consecutive.GetEnable().GetValue() {
+			out.Consecutive_5Xx = consecutive.GetConsecutiveErrorToOpen()
+		} else {
+			// 未开启连续错误熔断时，关闭envoy默认的连续5xx检测
+			out.EnforcingConsecutive_5Xx = utils.NewUInt32Value(0)
+		}
+		if errorRate := policy.GetErrorRate(); errorRate.GetEnable().GetValue() {
+			out.FailurePercentageThreshold = errorRate.GetErrorRateToOpen()
+			out.FailurePercentageRequestVolume = errorRate.GetRequestVolumeThreshold()
+			out.EnforcingFailurePercentage = utils.NewUInt32Value(100)
+		}
+		return out
+	}
+	return nil
+}
+
+// 熔断规则的来源是否匹配节点，没有来源表示匹配全部
+func matchCbSources(node *nodeInfo, sources []*api.SourceMatcher) bool {
+	if len(sources) == 0 {
+		return true
+	}
+	for _, source := range sources {
+		if node.matchSource(source.GetService().GetValue(), source.GetNamespace().GetValue()) {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * @brief 生成服务的virtual host
+ * 被调路由规则按照顺序转换为路由，来源的元数据匹配请求头，最后为默认路由
+ * 目标的元数据转换为子集负载均衡的条件，记录到subsets中用于生成cluster的子集选择器
+ */
+func (b *resourceBuilder) buildVirtualHost(node *nodeInfo, service *model.Service,
+	subsets map[string]map[string][]string) *route.VirtualHost {
+	name := clusterName(service)
+	host := &route.VirtualHost{
+		Name:       name,
+		Domains:    []string{name, name + ":*"},
+		RateLimits: b.buildRateLimits(b.source.rateLimits(service)),
+	}
+	if node.namespace != "" {
+		host.Domains = append(host.Domains, service.Name, service.Name+":*")
+	}
+
+	for _, rule := range b.source.routing(service).GetInbounds() {
+		action := buildRouteAction(service, rule.GetDestinations(), subsets)
+		if action == nil {
+			continue
+		}
+		sources := rule.GetSources()
+		if len(sources) == 0 {
+			sources = []*api.Source{{}}
+		}
+		for _, source := range sources {
+			if !node.matchSource(source.GetService().GetValue(), source.GetNamespace().GetValue()) {
+				continue
+			}
+			headers, ok := buildHeaderMatchers(source.GetMetadata())
+			if !ok {
+				continue
+			}
+			host.Routes = append(host.Routes, &route.Route{
+				Match: &route.RouteMatch{
+					PathSpecifier: &route.RouteMatch_Prefix{Prefix: "/"},
+					Headers:       headers,
+				},
+				Action: &route.Route_Route{Route: action},
+			})
+		}
+	}
+	host.Routes = append(host.Routes, &route.Route{
+		Match:  &route.RouteMatch{PathSpecifier: &route.RouteMatch_Prefix{Prefix: "/"}},
+		Action: &route.Route_Route{Route: &route.RouteAction{ClusterSpecifier: &route.RouteAction_Cluster{Cluster: name}}},
+	})
+	return host
+}
+
+// 路由的目标转换为按权重分配的cluster，只使用优先级最高的一组目标，没有可用的目标返回nil
+func buildRouteAction(service *model.Service, dests []*api.Destination,
+	subsets map[string]map[string][]string) *route.RouteAction {
+	var selected []*api.Destination
+	for _, dest := range dests {
+		if dest.GetIsolate().GetValue() || dest.GetWeight().GetValue() == 0 {
+			continue
+		}
+		if len(selected) > 0 && dest.GetPriority().GetValue() > selected[0].GetPriority().GetValue() {
+			continue
+		}
+		if len(selected) > 0 && dest.GetPriority().GetValue() < selected[0].GetPriority().GetValue() {
+			selected = selected[:0]
+		}
+		selected = append(selected, dest)
+	}
+	if len(selected) == 0 {
+		return nil
+	}
+
+	weighted := &route.WeightedCluster{}
+	var total uint32
+	for _, dest := range selected {
+		name := destClusterName(service, dest.GetService().GetValue(), dest.GetNamespace().GetValue())
+		metadata, keys := buildMetadataMatch(dest.GetMetadata())
+		if len(keys) > 0 {
+			if subsets[name] == nil {
+				subsets[name] = make(map[string][]string)
+			}
+			subsets[name][strings.Join(keys, ",")] = keys
+		}
+		weighted.Clusters = append(weighted.Clusters, &route.WeightedCluster_ClusterWeight{
+			Name:          name,
+			Weight:        dest.GetWeight(),
+			MetadataMatch: metadata,
+		})
+		total += dest.GetWeight().GetValue()
+	}
+	if len(weighted.Clusters) == 1 {
+		return &route.RouteAction{
+			ClusterSpecifier: &route.RouteAction_Cluster{Cluster: weighted.Clusters[0].Name},
+			MetadataMatch:    weighted.Clusters[0].MetadataMatch,
+		}
+	}
+	weighted.TotalWeight = utils.NewUInt32Value(total)
+	return &route.RouteAction{ClusterSpecifier: &route.RouteAction_WeightedClusters{WeightedClusters: weighted}}
+}
+
+// 来源的元数据转换为请求头的匹配条件，存在无法转换的条件时返回false
+func buildHeaderMatchers(metadata map[string]*api.MatchString) ([]*route.HeaderMatcher, bool) {
+	var out []*route.HeaderMatcher
+	for _, key := range sortedKeys(metadata) {
+		value := metadata[key]
+		if value.GetValueType() != api.MatchString_TEXT {
+			return nil, false
+		}
+		header := &route.HeaderMatcher{Name: key}
+		switch value.GetType() {
+		case api.MatchString_REGEX:
+			header.HeaderMatchSpecifier = &route.HeaderMatcher_SafeRegexMatch{SafeRegexMatch: &matcher.RegexMatcher{
+				EngineType: &matcher.RegexMatcher_GoogleRe2{GoogleRe2: &matcher.RegexMatcher_GoogleRE2{}},
+				Regex:      value.GetValue().GetValue(),
+			}}
+		default:
+			if value.GetValue().GetValue() == "*" {
+				continue
+			}
+			header.HeaderMatchSpecifier = &route.HeaderMatcher_ExactMatch{ExactMatch: value.GetValue().GetValue()}
+		}
+		out = append(out, header)
+	}
+	return out, true
+}
+
+// 目标的元数据转换为子集负载均衡的条件，只支持精确匹配，返回条件以及排好序的key
+func buildMetadataMatch(metadata map[string]*api.MatchString) (*core.Metadata, []string) {
+	fields := make(map[string]*_struct.Value)
+	var keys []string
+	for _, key := range sortedKeys(metadata) {
+		value := metadata[key]
+		if value.GetType() != api.MatchString_EXACT || value.GetValueType() != api.MatchString_TEXT ||
+			value.GetValue().GetValue() == "*" {
+			continue
+		}
+		fields[key] = stringValue(value.GetValue().GetValue())
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return &core.Metadata{FilterMetadata: map[string]*_struct.Struct{lbMetadataFilter: {Fields: fields}}}, keys
+}
+
+// 限流规则转换为限流描述符：generic_key为规则ID，规则的标签对应同名的请求头
+func (b *resourceBuilder) buildRateLimits(rules []*api.Rule) []*route.RateLimit {
+	if b.rateLimitCluster == "" {
+		return nil
+	}
+	var out []*route.RateLimit
+	for _, rule := range rules {
+		if rule.GetDisable().GetValue() {
+			continue
+		}
+		actions := []*route.RateLimit_Action{{
+			ActionSpecifier: &route.RateLimit_Action_GenericKey_{
+				GenericKey: &route.RateLimit_Action_GenericKey{DescriptorValue: rule.GetId().GetValue()},
+			},
+		}}
+		for _, key := range sortedKeys(rule.GetLabels()) {
+			actions = append(actions, &route.RateLimit_Action{
+				ActionSpecifier: &route.RateLimit_Action_RequestHeaders_{
+					RequestHeaders: &route.RateLimit_Action_RequestHeaders{HeaderName: key, DescriptorKey: key},
+				},
+			})
+		}
+		out = append(out, &route.RateLimit{Actions: actions})
+	}
+	return out
+}
+
+/**
+ * @brief 生成cluster的endpoint
+ * 过滤掉隔离以及权重为0的实例，按照优先级和地域分组，实例的元数据用于子集负载均衡
+ */
+func buildLoadAssignment(name string, instances []*model.Instance) *endpoint.ClusterLoadAssignment {
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].ID() < instances[j].ID()
+	})
+
+	groups := make(map[string]*endpoint.LocalityLbEndpoints)
+	for _, instance := range instances {
+		host := utils.NormalizeIP(instance.Host())
+		if host == "" || instance.Isolate() || instance.Weight() == 0 {
+			continue
+		}
+		location := instance.Location()
+		key := strings.Join([]string{strconv.FormatUint(uint64(instance.Priority()), 10), location.GetRegion().GetValue(),
+			location.GetZone().GetValue(), location.GetCampus().GetValue()}, "/")
+		group, ok := groups[key]
+		if !ok {
+			group = &endpoint.LocalityLbEndpoints{
+				Locality: &core.Locality{
+					Region:  location.GetRegion().GetValue(),
+					Zone:    location.GetZone().GetValue(),
+					SubZone: location.GetCampus().GetValue(),
+				},
+				Priority: instance.Priority(),
+			}
+			groups[key] = group
+		}
+
+		status := core.HealthStatus_HEALTHY
+		if !instance.Healthy() {
+			status = core.HealthStatus_UNHEALTHY
+		}
+		lbEndpoint := &endpoint.LbEndpoint{
+			HostIdentifier: &endpoint.LbEndpoint_Endpoint{Endpoint: &endpoint.Endpoint{
+				Address: socketAddress(host, instance.Port()),
+			}},
+			HealthStatus:        status,
+			LoadBalancingWeight: &wrappers.UInt32Value{Value: instance.Weight()},
+		}
+		if len(instance.Metadata()) > 0 {
+			fields := make(map[string]*_struct.Value, len(instance.Metadata()))
+			for key, value := range instance.Metadata() {
+				fields[key] = stringValue(value)
+			}
+			lbEndpoint.Metadata = &core.Metadata{
+				FilterMetadata: map[string]*_struct.Struct{lbMetadataFilter: {Fields: fields}},
+			}
+		}
+		group.LbEndpoints = append(group.LbEndpoints, lbEndpoint)
+	}
+
+	out := &endpoint.ClusterLoadAssignment{ClusterName: name}
+	for _, group := range groups {
+		out.Endpoints = append(out.Endpoints, group)
+	}
+	sort.Slice(out.Endpoints, func(i, j int) bool {
+		a, b := out.Endpoints[i], out.Endpoints[j]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return proto.CompactTextString(a.Locality) < proto.CompactTextString(b.Locality)
+	})
+	return out
+}
+
+// 生成出流量的监听器，使用RDS获取路由配置
+func (b *resourceBuilder) buildListener() *listener.Listener {
+	var filters []*hcm.HttpFilter
+	if b.rateLimitCluster != "" {
+		config, err := ptypes.MarshalAny(&ratelimit.RateLimit{
+			Domain: rateLimitDomain,
+			RateLimitService: &ratelimitconf.RateLimitServiceConfig{
+				GrpcService: &core.GrpcService{TargetSpecifier: &core.GrpcService_EnvoyGrpc_{
+					EnvoyGrpc: &core.GrpcService_EnvoyGrpc{ClusterName: b.rateLimitCluster},
+				}},
+			},
+		})
+		if err != nil {
+			return nil
+		}
+		filters = append(filters, &hcm.HttpFilter{
+			Name: rateLimitFilter, ConfigType: &hcm.HttpFilter_TypedConfig{TypedConfig: config},
+		})
+	}
+	routerConfig, err := ptypes.MarshalAny(&router.Router{})
+	if err != nil {
+		return nil
+	}
+	filters = append(filters, &hcm.HttpFilter{
+		Name: routerFilter, ConfigType: &hcm.HttpFilter_TypedConfig{TypedConfig: routerConfig},
+	})
+
+	manager, err := ptypes.MarshalAny(&hcm.HttpConnectionManager{
+		CodecType:  hcm.HttpConnectionManager_AUTO,
+		StatPrefix: outboundName,
+		RouteSpecifier: &hcm.HttpConnectionManager_Rds{Rds: &hcm.Rds{
+			ConfigSource:    adsConfigSource(),
+			RouteConfigName: outboundName,
+		}},
+		HttpFilters: filters,
+	})
+	if err != nil {
+		return nil
+	}
+	return &listener.Listener{
+		Name:    outboundName,
+		Address: socketAddress(b.outboundIP, b.outboundPort),
+		FilterChains: []*listener.FilterChain{{
+			Filters: []*listener.Filter{{
+				Name:       httpConnectionManagerFilter,
+				ConfigType: &listener.Filter_TypedConfig{TypedConfig: manager},
+			}},
+		}},
+	}
+}
+
+// 通过ADS获取资源的配置源
+func adsConfigSource() *core.ConfigSource {
+	return &core.ConfigSource{
+		ResourceApiVersion:    core.ApiVersion_V3,
+		ConfigSourceSpecifier: &core.ConfigSource_Ads{Ads: &core.AggregatedConfigSource{}},
+	}
+}
+
+func socketAddress(host string, port uint32) *core.Address {
+	return &core.Address{Address: &core.Address_SocketAddress{SocketAddress: &core.SocketAddress{
+		Address:       host,
+		PortSpecifier: &core.SocketAddress_PortValue{PortValue: port},
+	}}}
+}
+
+func stringValue(value string) *_struct.Value {
+	return &_struct.Value{Kind: &_struct.Value_StringValue{StringValue: value}}
+}
+
+func sortedKeys(m map[string]*api.MatchString) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,250 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserver
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/golang/protobuf/ptypes"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+)
+
+// 测试用的数据来源
+type fakeSource struct {
+	mu       sync.Mutex
+	svcs     []*model.Service
+	insts    map[string][]*model.Instance
+	routings map[string]*api.Routing
+	limits   map[string][]*api.Rule
+	breakers map[string]*api.CircuitBreaker
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{
+		insts:    make(map[string][]*model.Instance),
+		routings: make(map[string]*api.Routing),
+		limits:   make(map[string][]*api.Rule),
+		breakers: make(map[string]*api.CircuitBreaker),
+	}
+}
+
+func (f *fakeSource) addService(id string, name string, namespace string) *model.Service {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	service := &model.Service{ID: id, Name: name, Namespace: namespace}
+	f.svcs = append(f.svcs, service)
+	return service
+}
+
+func (f *fakeSource) setInstances(serviceID string, instances ...*model.Instance) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.insts[serviceID] = instances
+}
+
+func (f *fakeSource) services() []*model.Service {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*model.Service(nil), f.svcs...)
+}
+
+func (f *fakeSource) instances(serviceID string) []*model.Instance {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*model.Instance(nil), f.insts[serviceID]...)
+}
+
+func (f *fakeSource) routing(service *model.Service) *api.Routing {
+	return f.routings[service.ID]
+}
+
+func (f *fakeSource) rateLimits(service *model.Service) []*api.Rule {
+	return f.limits[service.ID]
+}
+
+func (f *fakeSource) circuitBreaker(service *model.Service) *api.CircuitBreaker {
+	return f.breakers[service.ID]
+}
+
+func newTestInstance(id string, host string, weight uint32, healthy bool, metadata map[string]string) *model.Instance {
+	return &model.Instance{Proto: &api.Instance{
+		Id:       utils.NewStringValue(id),
+		Host:     utils.NewStringValue(host),
+		Port:     utils.NewUInt32Value(8080),
+		Weight:   utils.NewUInt32Value(weight),
+		Healthy:  utils.NewBoolValue(healthy),
+		Isolate:  utils.NewBoolValue(false),
+		Metadata: metadata,
+	}}
+}
+
+func exactMatch(value string) *api.MatchString {
+	return &api.MatchString{Type: api.MatchString_EXACT, Value: utils.NewStringValue(value)}
+}
+
+func newTestBuilder(source resourceSource) *resourceBuilder {
+	return &resourceBuilder{
+		source:         source,
+		outboundIP:     defaultOutboundIP,
+		outboundPort:   defaultOutboundPort,
+		connectTimeout: defaultConnectTimeout,
+	}
+}
+
+// TestBuild_Endpoints 测试实例转换为endpoint
+func TestBuild_Endpoints(t *testing.T) {
+	source := newFakeSource()
+	source.addService("svc-1", "svc", "Test")
+	source.setInstances("svc-1",
+		newTestInstance("i-1", "10.0.0.1", 100, true, map[string]string{"version": "v1"}),
+		newTestInstance("i-2", "10.0.0.2", 50, false, nil),
+		newTestInstance("i-3", "10.0.0.3", 0, true, nil),
+		newTestInstance("i-4", "db.example.com", 100, true, nil),
+	)
+
+	set := newTestBuilder(source).build(&nodeInfo{id: "node"})
+	if len(set[types.Cluster]) != 1 || len(set[types.Endpoint]) != 1 || len(set[types.Route]) != 1 ||
+		len(set[types.Listener]) != 1 {
+		t.Fatalf("resource count is wrong: %+v", set)
+	}
+	assignment := set[types.Endpoint][0].(*endpoint.ClusterLoadAssignment)
+	if assignment.ClusterName != "svc.Test" || len(assignment.Endpoints) != 1 {
+		t.Fatalf("assignment: %+v", assignment)
+	}
+	endpoints := assignment.Endpoints[0].LbEndpoints
+	if len(endpoints) != 2 {
+		t.Fatalf("isolated, zero weight and domain instances should be filtered: %+v", endpoints)
+	}
+	if endpoints[0].LoadBalancingWeight.GetValue() != 100 ||
+		endpoints[0].Metadata.FilterMetadata[lbMetadataFilter].Fields["version"].GetStringValue() != "v1" {
+		t.Fatalf("endpoint: %+v", endpoints[0])
+	}
+	if endpoints[1].HealthStatus.String() != "UNHEALTHY" {
+		t.Fatalf("unhealthy instance should be marked: %+v", endpoints[1])
+	}
+	if set[types.Cluster][0].(*cluster.Cluster).GetEdsClusterConfig().GetEdsConfig().GetAds() == nil {
+		t.Fatalf("cluster should use ads")
+	}
+}
+
+// TestBuild_Routes 测试路由规则按照节点匹配来源，并生成子集负载均衡的条件
+func TestBuild_Routes(t *testing.T) {
+	source := newFakeSource()
+	service := source.addService("svc-1", "svc", "Test")
+	source.routings[service.ID] = &api.Routing{Inbounds: []*api.Route{
+		{
+			Sources: []*api.Source{{
+				Service:   utils.NewStringValue("caller"),
+				Namespace: utils.NewStringValue("Test"),
+				Metadata:  map[string]*api.MatchString{"env": exactMatch("gray")},
+			}},
+			Destinations: []*api.Destination{
+				{Service: utils.NewStringValue("*"), Metadata: map[string]*api.MatchString{
+					"version": exactMatch("v2")}, Weight: utils.NewUInt32Value(80)},
+				{Service: utils.NewStringValue("*"), Metadata: map[string]*api.MatchString{
+					"version": exactMatch("v1")}, Weight: utils.NewUInt32Value(20)},
+				{Service: utils.NewStringValue("*"), Priority: utils.NewUInt32Value(1),
+					Weight: utils.NewUInt32Value(100)},
+			},
+		},
+	}}
+	builder := newTestBuilder(source)
+
+	set := builder.build(&nodeInfo{id: "caller-1", service: "caller", namespace: "Test"})
+	host := set[types.Route][0].(*route.RouteConfiguration).VirtualHosts[0]
+	if len(host.Routes) != 2 || len(host.Domains) != 4 {
+		t.Fatalf("virtual host: %+v", host)
+	}
+	match := host.Routes[0].Match.Headers
+	if len(match) != 1 || match[0].Name != "env" || match[0].GetExactMatch() != "gray" {
+		t.Fatalf("header match: %+v", match)
+	}
+	weighted := host.Routes[0].GetRoute().GetWeightedClusters()
+	if len(weighted.GetClusters()) != 2 || weighted.GetTotalWeight().GetValue() != 100 {
+		t.Fatalf("weighted clusters: %+v", weighted)
+	}
+	selectors := set[types.Cluster][0].(*cluster.Cluster).GetLbSubsetConfig().GetSubsetSelectors()
+	if len(selectors) != 1 || selectors[0].Keys[0] != "version" {
+		t.Fatalf("subset selectors: %+v", selectors)
+	}
+
+	set = builder.build(&nodeInfo{id: "other-1", service: "other"})
+	host = set[types.Route][0].(*route.RouteConfiguration).VirtualHosts[0]
+	if len(host.Routes) != 1 || len(host.Domains) != 2 {
+		t.Fatalf("route should not match other node: %+v", host)
+	}
+}
+
+// TestBuild_Policies 测试熔断规则以及限流规则的转换
+func TestBuild_Policies(t *testing.T) {
+	source := newFakeSource()
+	service := source.addService("svc-1", "svc", "Test")
+	source.breakers[service.ID] = &api.CircuitBreaker{Inbounds: []*api.CbRule{{
+		Destinations: []*api.DestinationSet{{
+			Policy: &api.CbPolicy{
+				ErrorRate: &api.CbPolicy_ErrRateConfig{
+					Enable:                 utils.NewBoolValue(true),
+					RequestVolumeThreshold: utils.NewUInt32Value(10),
+					ErrorRateToOpen:        utils.NewUInt32Value(50),
+				},
+				Consecutive: &api.CbPolicy_ConsecutiveErrConfig{
+					Enable:                 utils.NewBoolValue(true),
+					ConsecutiveErrorToOpen: utils.NewUInt32Value(5),
+				},
+			},
+			Recover: &api.RecoverConfig{SleepWindow: ptypes.DurationProto(30 * time.Second)},
+		}},
+	}}}
+	source.limits[service.ID] = []*api.Rule{
+		{Id: utils.NewStringValue("rule-1"), Labels: map[string]*api.MatchString{"uid": exactMatch("1")}},
+		{Id: utils.NewStringValue("rule-2"), Disable: utils.NewBoolValue(true)},
+	}
+	builder := newTestBuilder(source)
+
+	set := builder.build(&nodeInfo{id: "node"})
+	outlier := set[types.Cluster][0].(*cluster.Cluster).GetOutlierDetection()
+	if outlier.GetConsecutive_5Xx().GetValue() != 5 || outlier.GetFailurePercentageThreshold().GetValue() != 50 ||
+		outlier.GetFailurePercentageRequestVolume().GetValue() != 10 || outlier.GetBaseEjectionTime().GetSeconds() != 30 {
+		t.Fatalf("outlier detection: %+v", outlier)
+	}
+	host := set[types.Route][0].(*route.RouteConfiguration).VirtualHosts[0]
+	if len(host.RateLimits) != 0 {
+		t.Fatalf("rate limits need rate limit cluster: %+v", host.RateLimits)
+	}
+
+	builder.rateLimitCluster = "ratelimit"
+	set = builder.build(&nodeInfo{id: "node"})
+	host = set[types.Route][0].(*route.RouteConfiguration).VirtualHosts[0]
+	if len(host.RateLimits) != 1 || len(host.RateLimits[0].Actions) != 2 ||
+		host.RateLimits[0].Actions[0].GetGenericKey().GetDescriptorValue() != "rule-1" {
+		t.Fatalf("rate limits: %+v", host.RateLimits)
+	}
+	filters := set[types.Listener][0].(*listener.Listener).FilterChains[0].Filters
+	if len(filters) != 1 || filters[0].Name != httpConnectionManagerFilter {
+		t.Fatalf("listener filters: %+v", filters)
+	}
+}
@@ -0,0 +1,297 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	serverv3 "github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	"github.com/polarismesh/polaris-server/apiserver"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/tlsutil"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	// 出流量监听器默认的地址
+	defaultOutboundIP   = "127.0.0.1"
+	defaultOutboundPort = 15001
+	// cluster默认的连接超时时间
+	defaultConnectTimeout = time.Second
+)
+
+/**
+ * @brief xDS API服务器，以ADS的方式为envoy提供服务发现以及治理规则
+ */
+type XDSServer struct {
+	listenIP   string
+	listenPort uint32
+	tlsConfig  *tlsutil.Config
+	builder    *resourceBuilder
+
+	start   bool
+	restart bool
+	exitCh  chan struct{}
+	cancel  context.CancelFunc
+
+	server       *grpc.Server
+	namingServer *naming.Server
+	snapshots    *snapshotManager
+}
+
+/**
+ * @brief 获取端口
+ */
+func (x *XDSServer) GetPort() uint32 {
+	return x.listenPort
+}
+
+/**
+ * @brief 获取Server的协议
+ */
+func (x *XDSServer) GetProtocol() string {
+	return "xds"
+}
+
+/**
+ * @brief 初始化xDS API服务器
+ */
+func (x *XDSServer) Initialize(_ context.Context, option map[string]interface{},
+	_ map[string]apiserver.APIConfig) error {
+	x.listenIP, _ = option["listenIP"].(string)
+	listenPort, _ := option["listenPort"].(int)
+	if listenPort <= 0 {
+		return fmt.Errorf("xdsserver listenPort(%v) is invalid", option["listenPort"])
+	}
+	x.listenPort = uint32(listenPort)
+
+	x.builder = &resourceBuilder{
+		outboundIP:     defaultOutboundIP,
+		outboundPort:   defaultOutboundPort,
+		connectTimeout: defaultConnectTimeout,
+	}
+	if outboundIP, _ := option["outboundIP"].(string); outboundIP != "" {
+		x.builder.outboundIP = outboundIP
+	}
+	if outboundPort, _ := option["outboundPort"].(int); outboundPort > 0 {
+		x.builder.outboundPort = uint32(outboundPort)
+	}
+	if value, _ := option["connectTimeout"].(string); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("xdsserver connectTimeout(%s) is invalid", value)
+		}
+		x.builder.connectTimeout = timeout
+	}
+	x.builder.rateLimitCluster, _ = option["rateLimitCluster"].(string)
+
+	x.tlsConfig = nil
+	if raw, _ := option["tls"].(map[interface{}]interface{}); raw != nil {
+		tlsConfig, err := tlsutil.ParseTLSConfig(raw)
+		if err != nil {
+			return err
+		}
+		x.tlsConfig = tlsConfig
+	}
+	return nil
+}
+
+/**
+ * @brief 启动xDS API服务器
+ */
+func (x *XDSServer) Run(errCh chan error) {
+	log.Infof("start xdsserver")
+	x.exitCh = make(chan struct{})
+	x.start = true
+	defer func() {
+		close(x.exitCh)
+		x.start = false
+	}()
+
+	var err error
+	x.namingServer, err = naming.GetServer()
+	if err != nil {
+		log.Errorf("%v", err)
+		errCh <- err
+		return
+	}
+	x.builder.source = &namingSource{server: x.namingServer}
+
+	address := net.JoinHostPort(x.listenIP, strconv.Itoa(int(x.listenPort)))
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Errorf("%v", err)
+		errCh <- err
+		return
+	}
+	defer listener.Close()
+
+	var options []grpc.ServerOption
+	if x.tlsConfig != nil {
+		reloader, err := tlsutil.NewReloader(x.tlsConfig)
+		if err != nil {
+			log.Errorf("xds server load tls cert err: %s", err.Error())
+			errCh <- err
+			return
+		}
+		defer reloader.Stop()
+		log.Infof("xds server use tls, client auth: %s", x.tlsConfig.ClientAuth)
+		options = append(options, grpc.Creds(credentials.NewTLS(reloader.TLSConfig("h2"))))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	x.cancel = cancel
+	defer cancel()
+	x.snapshots = newSnapshotManager(cachev3.NewSnapshotCache(true, cachev3.IDHash{}, xdsLogger{}), x.builder)
+	x.server = grpc.NewServer(options...)
+	discovery.RegisterAggregatedDiscoveryServiceServer(x.server,
+		serverv3.NewServer(ctx, x.snapshots.cache, x.snapshots))
+	go x.refresh(ctx, x.namingServer.Cache().GetUpdateCacheInterval())
+
+	if err := x.server.Serve(listener); err != nil {
+		log.Errorf("%v", err)
+		if !x.restart {
+			errCh <- err
+		}
+		return
+	}
+	log.Infof("xdsserver stop")
+}
+
+// 定时根据缓存刷新节点的快照
+func (x *XDSServer) refresh(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			x.snapshots.refresh()
+		}
+	}
+}
+
+// stop server
+func (x *XDSServer) Stop() {
+	if x.cancel != nil {
+		x.cancel()
+	}
+	if x.server != nil {
+		x.server.Stop()
+	}
+}
+
+// restart server
+func (x *XDSServer) Restart(option map[string]interface{}, api map[string]apiserver.APIConfig,
+	errCh chan error) error {
+	log.Infof("restart xds server with new config: %+v", option)
+
+	x.restart = true
+	x.Stop()
+	if x.start {
+		<-x.exitCh
+	}
+
+	log.Infof("old xds server has stopped, begin restarting it")
+	if err := x.Initialize(context.Background(), option, api); err != nil {
+		log.Errorf("restart xds server err: %s", err.Error())
+		return err
+	}
+
+	log.Infof("init xds server successfully, restart it")
+	x.restart = false
+	go x.Run(errCh)
+	return nil
+}
+
+/**
+ * @brief 从naming server的缓存中获取xDS资源的数据
+ */
+type namingSource struct {
+	server *naming.Server
+}
+
+// 全部的服务，不包括服务别名
+func (n *namingSource) services() []*model.Service {
+	var out []*model.Service
+	_ = n.server.Cache().Service().IteratorServices(func(key string, value *model.Service) (bool, error) {
+		if !value.IsAlias() {
+			out = append(out, value)
+		}
+		return true, nil
+	})
+	return out
+}
+
+// 服务下的全部实例
+func (n *namingSource) instances(serviceID string) []*model.Instance {
+	return n.server.Cache().Instance().GetInstancesByServiceID(serviceID)
+}
+
+// 服务的路由配置
+func (n *namingSource) routing(service *model.Service) *api.Routing {
+	return n.server.GetRoutingConfigWithCache(context.Background(), service2API(service)).GetRouting()
+}
+
+// 服务的限流规则
+func (n *namingSource) rateLimits(service *model.Service) []*api.Rule {
+	return n.server.GetRateLimitWithCache(context.Background(), service2API(service)).GetRateLimit().GetRules()
+}
+
+// 服务的熔断规则
+func (n *namingSource) circuitBreaker(service *model.Service) *api.CircuitBreaker {
+	return n.server.GetCircuitBreakerWithCache(context.Background(), service2API(service)).GetCircuitBreaker()
+}
+
+func service2API(service *model.Service) *api.Service {
+	return &api.Service{
+		Name:      utils.NewStringValue(service.Name),
+		Namespace: utils.NewStringValue(service.Namespace),
+	}
+}
+
+// go-control-plane的日志输出到server的日志中
+type xdsLogger struct{}
+
+func (xdsLogger) Debugf(format string, args ...interface{}) {
+	log.Debugf("[XDS] "+format, args...)
+}
+
+func (xdsLogger) Infof(format string, args ...interface{}) {
+	log.Infof("[XDS] "+format, args...)
+}
+
+func (xdsLogger) Warnf(format string, args ...interface{}) {
+	log.Warnf("[XDS] "+format, args...)
+}
+
+func (xdsLogger) Errorf(format string, args ...interface{}) {
+	log.Errorf("[XDS] "+format, args...)
+}
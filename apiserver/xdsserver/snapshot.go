@@ -0,0 +1,289 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserver
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/golang/protobuf/proto"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/polarismesh/polaris-server/common/log"
+)
+
+const (
+	// 节点元数据中的服务名，为空时使用节点的cluster
+	nodeServiceKey = "polaris.service"
+	// 节点元数据中的命名空间，只下发该命名空间下的服务
+	nodeNamespaceKey = "polaris.namespace"
+)
+
+// 下发的资源类型以及对应的type url
+var servedTypes = map[types.ResponseType]string{
+	types.Cluster:  resource.ClusterType,
+	types.Endpoint: resource.EndpointType,
+	types.Route:    resource.RouteType,
+	types.Listener: resource.ListenerType,
+}
+
+// 节点某类资源的版本，内容的摘要发生变化时序号加一
+type typeVersion struct {
+	digest  string
+	seq     uint64
+	version string
+}
+
+// 节点的状态
+type nodeState struct {
+	info     *nodeInfo
+	streams  int
+	versions [types.UnknownType]typeVersion
+	// 节点已经确认的版本，以及最近一次拒绝的原因，key为资源的类型
+	acked  map[string]string
+	nacked map[string]string
+}
+
+// 节点状态的拷贝
+type nodeStatus struct {
+	versions map[string]string
+	acked    map[string]string
+	nacked   map[string]string
+}
+
+/**
+ * @brief 按照节点维护xDS资源的快照
+ * 节点第一次请求时生成快照，之后定时根据缓存刷新；每类资源单独计算版本，内容不变时版本不变，不会重复下发
+ * 同时实现xDS server的回调，记录节点对每类资源的确认以及拒绝
+ */
+type snapshotManager struct {
+	mu      sync.Mutex
+	cache   cachev3.SnapshotCache
+	builder *resourceBuilder
+	nodes   map[string]*nodeState
+	streams map[int64]string
+}
+
+// 新建快照管理器
+func newSnapshotManager(cache cachev3.SnapshotCache, builder *resourceBuilder) *snapshotManager {
+	return &snapshotManager{
+		cache:   cache,
+		builder: builder,
+		nodes:   make(map[string]*nodeState),
+		streams: make(map[int64]string),
+	}
+}
+
+// refresh 根据缓存重新生成全部节点的快照，标识相同的节点共用生成的资源
+func (m *snapshotManager) refresh() {
+	m.mu.Lock()
+	infos := make([]*nodeInfo, 0, len(m.nodes))
+	for _, node := range m.nodes {
+		infos = append(infos, node.info)
+	}
+	m.mu.Unlock()
+
+	built := make(map[string]resourceSet)
+	for _, info := range infos {
+		set, ok := built[info.key()]
+		if !ok {
+			set = m.builder.build(info)
+			built[info.key()] = set
+		}
+		m.update(info.id, set)
+	}
+}
+
+// 更新节点的快照，只有内容发生变化的资源类型才会生成新的版本
+func (m *snapshotManager) update(id string, set resourceSet) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node, ok := m.nodes[id]
+	if !ok {
+		return
+	}
+
+	changed := false
+	var snapshot cachev3.Snapshot
+	for typ := range servedTypes {
+		current := &node.versions[typ]
+		if digest := resourcesDigest(set[typ]); digest != current.digest || current.seq == 0 {
+			current.seq++
+			current.digest = digest
+			current.version = fmt.Sprintf("%d-%s", current.seq, digest[:8])
+			changed = true
+		}
+		snapshot.Resources[typ] = cachev3.NewResources(current.version, set[typ])
+	}
+	if !changed {
+		return
+	}
+	if err := m.cache.SetSnapshot(id, snapshot); err != nil {
+		log.Errorf("[XDS] set node(%s) snapshot err: %s", id, err.Error())
+	}
+}
+
+// 计算一组资源的摘要
+func resourcesDigest(resources []types.Resource) string {
+	h := sha1.New()
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	for _, item := range resources {
+		buf.Reset()
+		if err := buf.Marshal(item); err != nil {
+			log.Errorf("[XDS] marshal resource(%s) err: %s", cachev3.GetResourceName(item), err.Error())
+			continue
+		}
+		_, _ = h.Write(buf.Bytes())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// 获取节点的状态，节点不存在返回nil
+func (m *snapshotManager) nodeStatus(id string) *nodeStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node, ok := m.nodes[id]
+	if !ok {
+		return nil
+	}
+	out := &nodeStatus{
+		versions: make(map[string]string),
+		acked:    make(map[string]string, len(node.acked)),
+		nacked:   make(map[string]string, len(node.nacked)),
+	}
+	for typ, url := range servedTypes {
+		out.versions[url] = node.versions[typ].version
+	}
+	for key, value := range node.acked {
+		out.acked[key] = value
+	}
+	for key, value := range node.nacked {
+		out.nacked[key] = value
+	}
+	return out
+}
+
+// OnStreamOpen stream建立
+func (m *snapshotManager) OnStreamOpen(_ context.Context, streamID int64, typeURL string) error {
+	log.Debugf("[XDS] stream(%d) open, type: %s", streamID, typeURL)
+	return nil
+}
+
+// OnStreamClosed stream关闭，节点的stream全部关闭后清理节点的快照
+func (m *snapshotManager) OnStreamClosed(streamID int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id, ok := m.streams[streamID]
+	if !ok {
+		return
+	}
+	delete(m.streams, streamID)
+	node := m.nodes[id]
+	node.streams--
+	if node.streams > 0 {
+		return
+	}
+	delete(m.nodes, id)
+	m.cache.ClearSnapshot(id)
+	log.Infof("[XDS] node(%s) disconnected", id)
+}
+
+/**
+ * OnStreamRequest 收到节点的请求
+ * 第一次收到节点的请求时生成快照；带有response nonce的请求为确认或者拒绝，拒绝时记录原因
+ */
+func (m *snapshotManager) OnStreamRequest(streamID int64, req *discovery.DiscoveryRequest) error {
+	m.mu.Lock()
+	id, ok := m.streams[streamID]
+	var created *nodeInfo
+	if !ok {
+		info := parseNodeInfo(req)
+		if info.id == "" {
+			m.mu.Unlock()
+			return errors.New("node id is empty")
+		}
+		id = info.id
+		m.streams[streamID] = id
+		node, exist := m.nodes[id]
+		if !exist {
+			node = &nodeState{info: info, acked: make(map[string]string), nacked: make(map[string]string)}
+			m.nodes[id] = node
+			created = info
+			log.Infof("[XDS] node(%s) connected, service: %s, namespace: %s", id, info.service, info.namespace)
+		}
+		node.streams++
+	}
+	if node := m.nodes[id]; req.GetResponseNonce() != "" {
+		if detail := req.GetErrorDetail(); detail != nil {
+			node.nacked[req.GetTypeUrl()] = detail.GetMessage()
+			log.Errorf("[XDS] node(%s) rejected %s, version: %s, nonce: %s, err: %s", id, req.GetTypeUrl(),
+				req.GetVersionInfo(), req.GetResponseNonce(), detail.GetMessage())
+		} else {
+			node.acked[req.GetTypeUrl()] = req.GetVersionInfo()
+			delete(node.nacked, req.GetTypeUrl())
+		}
+	}
+	m.mu.Unlock()
+
+	if created != nil {
+		m.update(id, m.builder.build(created))
+	}
+	return nil
+}
+
+// OnStreamResponse 向节点下发资源
+func (m *snapshotManager) OnStreamResponse(streamID int64, req *discovery.DiscoveryRequest,
+	resp *discovery.DiscoveryResponse) {
+	log.Debugf("[XDS] stream(%d) send %s, version: %s, resources: %d", streamID, resp.GetTypeUrl(),
+		resp.GetVersionInfo(), len(resp.GetResources()))
+}
+
+// OnFetchRequest 只提供ADS，不支持REST方式获取
+func (m *snapshotManager) OnFetchRequest(_ context.Context, _ *discovery.DiscoveryRequest) error {
+	return errors.New("fetch is not supported, use ADS instead")
+}
+
+// OnFetchResponse 只提供ADS，不支持REST方式获取
+func (m *snapshotManager) OnFetchResponse(_ *discovery.DiscoveryRequest, _ *discovery.DiscoveryResponse) {
+}
+
+// 从请求中解析节点的信息
+func parseNodeInfo(req *discovery.DiscoveryRequest) *nodeInfo {
+	node := req.GetNode()
+	info := &nodeInfo{
+		id:        node.GetId(),
+		service:   metadataString(node.GetMetadata(), nodeServiceKey),
+		namespace: metadataString(node.GetMetadata(), nodeNamespaceKey),
+	}
+	if info.service == "" {
+		info.service = node.GetCluster()
+	}
+	return info
+}
+
+func metadataString(metadata *_struct.Struct, key string) string {
+	return metadata.GetFields()[key].GetStringValue()
+}
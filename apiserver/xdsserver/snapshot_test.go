@@ -0,0 +1,221 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	serverv3 "github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+)
+
+// 模拟envoy的ADS客户端
+type fakeEnvoy struct {
+	t      *testing.T
+	node   *core.Node
+	stream discovery.AggregatedDiscoveryService_StreamAggregatedResourcesClient
+}
+
+func (f *fakeEnvoy) send(typeURL string, version string, nonce string, names []string, detail *status.Status) {
+	err := f.stream.Send(&discovery.DiscoveryRequest{
+		Node:          f.node,
+		TypeUrl:       typeURL,
+		VersionInfo:   version,
+		ResponseNonce: nonce,
+		ResourceNames: names,
+		ErrorDetail:   detail,
+	})
+	if err != nil {
+		f.t.Fatalf("send request err: %s", err.Error())
+	}
+}
+
+func (f *fakeEnvoy) recv(typeURL string) *discovery.DiscoveryResponse {
+	resp, err := f.stream.Recv()
+	if err != nil {
+		f.t.Fatalf("recv response err: %s", err.Error())
+	}
+	if resp.GetTypeUrl() != typeURL {
+		f.t.Fatalf("response type should be %s, got %s", typeURL, resp.GetTypeUrl())
+	}
+	return resp
+}
+
+// 启动测试用的ADS服务，返回快照管理器以及连接的地址
+func startTestServer(t *testing.T, source resourceSource) (*snapshotManager, string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen err: %s", err.Error())
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	manager := newSnapshotManager(cachev3.NewSnapshotCache(true, cachev3.IDHash{}, nil), newTestBuilder(source))
+	server := grpc.NewServer()
+	discovery.RegisterAggregatedDiscoveryServiceServer(server, serverv3.NewServer(ctx, manager.cache, manager))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return manager, listener.Addr().String(), func() {
+		cancel()
+		server.Stop()
+	}
+}
+
+// waitFor 等待条件满足
+func waitFor(t *testing.T, cond func() bool, msg string) {
+	for i := 0; i < 100; i++ {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal(msg)
+}
+
+// TestADS_AckNack 测试节点的订阅、确认、拒绝以及资源变化后的增量版本
+func TestADS_AckNack(t *testing.T) {
+	source := newFakeSource()
+	source.addService("svc-1", "svc", "Test")
+	source.setInstances("svc-1", newTestInstance("i-1", "10.0.0.1", 100, true, nil))
+	manager, address, stop := startTestServer(t, source)
+	defer stop()
+
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("dial err: %s", err.Error())
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := discovery.NewAggregatedDiscoveryServiceClient(conn).StreamAggregatedResources(ctx)
+	if err != nil {
+		t.Fatalf("open stream err: %s", err.Error())
+	}
+	envoy := &fakeEnvoy{t: t, node: &core.Node{Id: "node-1", Cluster: "caller"}, stream: stream}
+
+	// 订阅cluster并确认
+	envoy.send(resource.ClusterType, "", "", nil, nil)
+	clusters := envoy.recv(resource.ClusterType)
+	if len(clusters.Resources) != 1 {
+		t.Fatalf("clusters: %+v", clusters)
+	}
+	envoy.send(resource.ClusterType, clusters.VersionInfo, clusters.Nonce, nil, nil)
+
+	// 订阅endpoint，拒绝第一个版本后server会重新下发当前的版本
+	envoy.send(resource.EndpointType, "", "", []string{"svc.Test"}, nil)
+	endpoints := envoy.recv(resource.EndpointType)
+	envoy.send(resource.EndpointType, "", endpoints.Nonce, []string{"svc.Test"},
+		&status.Status{Message: "invalid endpoint"})
+	resent := envoy.recv(resource.EndpointType)
+	if resent.VersionInfo != endpoints.VersionInfo {
+		t.Fatalf("nacked version should be resent, got %s", resent.VersionInfo)
+	}
+	waitFor(t, func() bool {
+		nodeStatus := manager.nodeStatus("node-1")
+		return nodeStatus != nil && nodeStatus.acked[resource.ClusterType] == clusters.VersionInfo &&
+			nodeStatus.nacked[resource.EndpointType] == "invalid endpoint"
+	}, "ack and nack should be recorded")
+	envoy.send(resource.EndpointType, resent.VersionInfo, resent.Nonce, []string{"svc.Test"}, nil)
+
+	// 实例变化后只下发endpoint的新版本，cluster的版本不变
+	source.setInstances("svc-1", newTestInstance("i-1", "10.0.0.1", 100, true, nil),
+		newTestInstance("i-2", "10.0.0.2", 100, true, nil))
+	manager.refresh()
+	updated := envoy.recv(resource.EndpointType)
+	if updated.VersionInfo == endpoints.VersionInfo {
+		t.Fatalf("endpoint version should change, got %s", updated.VersionInfo)
+	}
+	envoy.send(resource.EndpointType, updated.VersionInfo, updated.Nonce, []string{"svc.Test"}, nil)
+	waitFor(t, func() bool {
+		nodeStatus := manager.nodeStatus("node-1")
+		return nodeStatus.acked[resource.EndpointType] == updated.VersionInfo &&
+			nodeStatus.nacked[resource.EndpointType] == ""
+	}, "new endpoint version should be acked")
+	if nodeStatus := manager.nodeStatus("node-1"); nodeStatus.versions[resource.ClusterType] != clusters.VersionInfo {
+		t.Fatalf("cluster version should not change: %+v", nodeStatus.versions)
+	}
+
+	// 没有变化时刷新不会生成新的版本
+	manager.refresh()
+	if nodeStatus := manager.nodeStatus("node-1"); nodeStatus.versions[resource.EndpointType] != updated.VersionInfo {
+		t.Fatalf("endpoint version should not change: %+v", nodeStatus.versions)
+	}
+
+	// stream关闭后清理节点
+	cancel()
+	waitFor(t, func() bool {
+		return manager.nodeStatus("node-1") == nil
+	}, "node should be removed after stream closed")
+}
+
+// TestADS_PerNode 测试按照节点的命名空间生成不同的快照
+func TestADS_PerNode(t *testing.T) {
+	source := newFakeSource()
+	source.addService("svc-1", "svc", "Test")
+	source.addService("svc-2", "svc", "Production")
+	manager, address, stop := startTestServer(t, source)
+	defer stop()
+
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("dial err: %s", err.Error())
+	}
+	defer conn.Close()
+
+	counts := make(map[string]int)
+	for _, node := range []*core.Node{
+		{Id: "node-all"},
+		{Id: "node-test", Metadata: structOf(nodeNamespaceKey, "Test")},
+	} {
+		stream, err := discovery.NewAggregatedDiscoveryServiceClient(conn).
+			StreamAggregatedResources(context.Background())
+		if err != nil {
+			t.Fatalf("open stream err: %s", err.Error())
+		}
+		envoy := &fakeEnvoy{t: t, node: node, stream: stream}
+		envoy.send(resource.ClusterType, "", "", nil, nil)
+		counts[node.Id] = len(envoy.recv(resource.ClusterType).Resources)
+	}
+	if counts["node-all"] != 2 || counts["node-test"] != 1 {
+		t.Fatalf("clusters per node: %+v", counts)
+	}
+	if manager.nodeStatus("node-test") == nil {
+		t.Fatalf("node status should exist")
+	}
+
+	stream, err := discovery.NewAggregatedDiscoveryServiceClient(conn).StreamAggregatedResources(context.Background())
+	if err != nil {
+		t.Fatalf("open stream err: %s", err.Error())
+	}
+	envoy := &fakeEnvoy{t: t, node: &core.Node{}, stream: stream}
+	envoy.send(resource.ClusterType, "", "", nil, nil)
+	if _, err := stream.Recv(); err == nil {
+		t.Fatalf("stream without node id should be closed")
+	}
+}
+
+func structOf(key string, value string) *_struct.Struct {
+	return &_struct.Struct{Fields: map[string]*_struct.Value{key: stringValue(value)}}
+}
@@ -0,0 +1,249 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package bootstrap
+
+import (
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-server/apiserver"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/config"
+	"github.com/polarismesh/polaris-server/naming"
+	"github.com/polarismesh/polaris-server/plugin"
+)
+
+const (
+	// 默认检查配置文件是否修改的间隔，单位为秒
+	defaultConfigWatchInterval = 10
+)
+
+var (
+	reloadMutex sync.Mutex
+	// 正在运行的配置，已经生效的配置项会同步修改
+	runningConfig *config.Config
+	reloadCtx     context.Context
+	reloadErrCh   chan error
+)
+
+// 记录启动完成时的配置，作为热更新比较的基准
+func setRunningConfig(ctx context.Context, cfg *config.Config, errCh chan error) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+	runningConfig = cfg
+	reloadCtx = ctx
+	reloadErrCh = errCh
+	apiserver.ConfigReloader = ReloadConfig
+}
+
+/**
+ * ReloadConfig 重新加载配置文件，与正在运行的配置比较
+ * 能够直接生效的配置项立即生效，其他的配置项需要重启，返回配置项的生效情况
+ */
+func ReloadConfig() (*apiserver.ReloadResult, error) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+	if runningConfig == nil {
+		return nil, errors.New("server is not started")
+	}
+
+	cfg, err := config.Load(ConfigFilePath)
+	if err != nil {
+		log.Errorf("[Bootstrap] reload config(%s) err: %s", ConfigFilePath, err.Error())
+		return nil, err
+	}
+
+	result := &apiserver.ReloadResult{}
+	err = reloadConfig(runningConfig, cfg, result)
+	sort.Strings(result.Applied)
+	sort.Strings(result.Restart)
+	if err != nil {
+		log.Errorf("[Bootstrap] reload config err: %s, applied: %v", err.Error(), result.Applied)
+		return result, err
+	}
+	log.Infof("[Bootstrap] reload config, applied: %v, need restart: %v", result.Applied, result.Restart)
+	return result, nil
+}
+
+// 按照配置的分类依次热更新
+func reloadConfig(running *config.Config, cfg *config.Config, result *apiserver.ReloadResult) error {
+	if err := reloadBootstrap(running, cfg, result); err != nil {
+		return err
+	}
+
+	// 插件先于核心逻辑层生效，核心逻辑层重建的对象使用新的插件配置
+	applied, restart, err := plugin.ReloadPluginConfig(&cfg.Plugin)
+	result.Applied = append(result.Applied, applied...)
+	result.Restart = append(result.Restart, restart...)
+	if err != nil {
+		return err
+	}
+
+	namingServer, err := naming.GetServer()
+	if err != nil {
+		return err
+	}
+	applied, restart, err = namingServer.ReloadConfig(reloadCtx, &running.Naming, &cfg.Naming)
+	result.Applied = append(result.Applied, applied...)
+	result.Restart = append(result.Restart, restart...)
+	if err != nil {
+		return err
+	}
+
+	// 缓存以及存储层在启动时加载全量数据，修改后需要重启
+	if !reflect.DeepEqual(running.Cache, cfg.Cache) {
+		result.Restart = append(result.Restart, "cache")
+	}
+	if !reflect.DeepEqual(running.Store, cfg.Store) {
+		result.Restart = append(result.Restart, "store")
+	}
+
+	return reloadAPIServers(running, cfg, result)
+}
+
+// 日志级别直接生效，其他的启动配置需要重启
+func reloadBootstrap(running *config.Config, cfg *config.Config, result *apiserver.ReloadResult) error {
+	oldLogger := &running.Bootstrap.Logger
+	newLogger := &cfg.Bootstrap.Logger
+	if oldLogger.Level != newLogger.Level {
+		if err := newLogger.SetOutputLevel(log.DefaultScopeName, newLogger.Level); err != nil {
+			return fmt.Errorf("invalid logger level(%s): %s", newLogger.Level, err.Error())
+		}
+		level, _ := newLogger.GetOutputLevel(log.DefaultScopeName)
+		log.FindScope(log.DefaultScopeName).SetOutputLevel(level)
+		oldLogger.Level = newLogger.Level
+		result.Applied = append(result.Applied, "bootstrap.logger.level")
+	}
+	if !reflect.DeepEqual(loggerOutput(oldLogger), loggerOutput(newLogger)) {
+		result.Restart = append(result.Restart, "bootstrap.logger")
+	}
+
+	if !reflect.DeepEqual(running.Bootstrap.StartInOrder, cfg.Bootstrap.StartInOrder) ||
+		!reflect.DeepEqual(running.Bootstrap.PolarisService, cfg.Bootstrap.PolarisService) {
+		result.Restart = append(result.Restart, "bootstrap")
+	}
+	if running.Bootstrap.ConfigWatch != cfg.Bootstrap.ConfigWatch {
+		result.Restart = append(result.Restart, "bootstrap.configWatch")
+	}
+	return nil
+}
+
+// 日志的输出配置，不包含日志级别
+func loggerOutput(opt *log.Options) log.Options {
+	return log.Options{
+		OutputPaths:        opt.OutputPaths,
+		ErrorOutputPaths:   opt.ErrorOutputPaths,
+		RotateOutputPath:   opt.RotateOutputPath,
+		RotationMaxSize:    opt.RotationMaxSize,
+		RotationMaxAge:     opt.RotationMaxAge,
+		RotationMaxBackups: opt.RotationMaxBackups,
+		JSONEncoding:       opt.JSONEncoding,
+		LogGrpc:            opt.LogGrpc,
+	}
+}
+
+// 只重启配置有变化的apiserver，新增或者删除apiserver需要重启
+func reloadAPIServers(running *config.Config, cfg *config.Config, result *apiserver.ReloadResult) error {
+	runningServers := make(map[string]int, len(running.APIServers))
+	for i, protocol := range running.APIServers {
+		runningServers[protocol.Name] = i
+	}
+
+	names := make(map[string]bool, len(cfg.APIServers))
+	for _, protocol := range cfg.APIServers {
+		names[protocol.Name] = true
+		item := "apiservers." + protocol.Name
+		idx, ok := runningServers[protocol.Name]
+		if !ok {
+			result.Restart = append(result.Restart, item)
+			continue
+		}
+		if reflect.DeepEqual(running.APIServers[idx], protocol) {
+			continue
+		}
+
+		server, exist := apiserver.Slots[protocol.Name]
+		if !exist {
+			return fmt.Errorf("apiserver slot %s not exists", protocol.Name)
+		}
+		log.Infof("[Bootstrap] begin restarting server: %s", protocol.Name)
+		if err := server.Restart(protocol.Option, protocol.API, reloadErrCh); err != nil {
+			return fmt.Errorf("restart apiserver %s err: %s", protocol.Name, err.Error())
+		}
+		running.APIServers[idx] = protocol
+		result.Applied = append(result.Applied, item)
+	}
+
+	for name := range runningServers {
+		if !names[name] {
+			result.Restart = append(result.Restart, "apiservers."+name)
+		}
+	}
+	return nil
+}
+
+// 定期检查配置文件的内容，修改后自动热更新
+func watchConfigFile(ctx context.Context, watch *config.ConfigWatch) {
+	interval := watch.Interval
+	if interval <= 0 {
+		interval = defaultConfigWatchInterval
+	}
+	log.Infof("[Bootstrap] watch config file(%s), interval: %ds", ConfigFilePath, interval)
+
+	digest, err := configFileDigest()
+	if err != nil {
+		log.Errorf("[Bootstrap] read config file(%s) err: %s", ConfigFilePath, err.Error())
+	}
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			current, err := configFileDigest()
+			if err != nil {
+				log.Errorf("[Bootstrap] read config file(%s) err: %s", ConfigFilePath, err.Error())
+				continue
+			}
+			if current == digest {
+				continue
+			}
+			log.Infof("[Bootstrap] config file(%s) is modified, reload it", ConfigFilePath)
+			// 重新加载失败时等待配置文件再次修改，错误在ReloadConfig中已经记录
+			digest = current
+			_, _ = ReloadConfig()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// 计算配置文件内容的摘要
+func configFileDigest() (string, error) {
+	data, err := ioutil.ReadFile(ConfigFilePath)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha1.Sum(data)), nil
+}
@@ -28,7 +28,7 @@ import (
 
 var darwinSignals = []os.Signal{
 	syscall.SIGINT, syscall.SIGTERM,
-	syscall.SIGSEGV, syscall.SIGUSR1,
+	syscall.SIGSEGV, syscall.SIGUSR1, syscall.SIGHUP,
 }
 
 // RunMainLoop server主循环
@@ -40,13 +40,20 @@ func RunMainLoop(servers []apiserver.Apiserver, errCh chan error) {
 	for {
 		select {
 		case s := <-ch:
-			// restart信号
-			if s.(syscall.Signal) == syscall.SIGUSR1 {
-				// 注意：重启失败，退出程序
+			switch s.(syscall.Signal) {
+			case syscall.SIGUSR1:
+				// restart信号，注意：重启失败，退出程序
 				if err := RestartServers(errCh); err != nil {
 					log.Errorf("restart servers err: %s", err.Error())
 					return
 				}
+				log.Infof("catch signal(%+v), servers restarted", s)
+				continue
+			case syscall.SIGHUP:
+				// reload信号，热更新失败时继续使用原有配置
+				log.Infof("catch signal(%+v), reload config", s)
+				_, _ = ReloadConfig()
+				continue
 			}
 
 			log.Infof("catch signal(%+v), stop servers", s)
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,6 +33,7 @@ import (
 	"github.com/polarismesh/polaris-server/common/version"
 	"github.com/polarismesh/polaris-server/config"
 	"github.com/polarismesh/polaris-server/naming"
+	"github.com/polarismesh/polaris-server/naming/k8ssync"
 	"github.com/polarismesh/polaris-server/plugin"
 	"github.com/polarismesh/polaris-server/store"
 )
@@ -106,6 +108,9 @@ func Start(configFilePath string) {
 	}
 
 	cfg.Naming.HealthCheck.LocalHost = LocalHost // 补充healthCheck的配置
+	if cfg.Naming.HealthCheck.LocalPeer == "" {
+		cfg.Naming.HealthCheck.LocalPeer = localPeer(cfg.APIServers)
+	}
 	naming.SetHealthCheckConfig(&cfg.Naming.HealthCheck)
 	err = naming.Initialize(ctx, &cfg.Naming, &cfg.Cache)
 	if err != nil {
@@ -113,6 +118,12 @@ func Start(configFilePath string) {
 		return
 	}
 
+	// 启动k8s服务同步
+	if err := k8ssync.Start(ctx, &cfg.K8sSync); err != nil {
+		fmt.Printf("[ERROR] %v\n", err)
+		return
+	}
+
 	errCh := make(chan error, len(cfg.APIServers))
 	servers, err := StartServers(ctx, cfg, errCh)
 	if err != nil {
@@ -126,6 +137,12 @@ func Start(configFilePath string) {
 	_ = FinishBootstrapOrder(tx) // 启动完成，解锁
 	fmt.Println("finish starting server")
 
+	// 记录正在运行的配置，用于配置热更新
+	setRunningConfig(ctx, cfg, errCh)
+	if cfg.Bootstrap.ConfigWatch.Open {
+		go watchConfigFile(ctx, &cfg.Bootstrap.ConfigWatch)
+	}
+
 	RunMainLoop(servers, errCh)
 }
 
@@ -176,6 +193,13 @@ func RestartServers(errCh chan error) error {
 			return err
 		}
 	}
+
+	// 同步修改正在运行的配置，避免热更新时重复重启
+	reloadMutex.Lock()
+	if runningConfig != nil {
+		runningConfig.APIServers = cfg.APIServers
+	}
+	reloadMutex.Unlock()
 	return nil
 }
 
@@ -286,8 +310,10 @@ func polarisServiceRegister(polarisService *config.PolarisService, apiServers []
 	}
 
 	apiServerNames := make(map[string]bool)
+	listenIPs := make(map[string]string)
 	for _, server := range apiServers {
 		apiServerNames[server.Name] = true
+		listenIPs[server.Name], _ = server.Option["listenIP"].(string)
 	}
 
 	// 开始注册每个服务
@@ -308,7 +334,7 @@ func polarisServiceRegister(polarisService *config.PolarisService, apiServers []
 			if !exist {
 				return fmt.Errorf("not exist the server(%s)", name)
 			}
-			host := LocalHost
+			host := registerHost(listenIPs[name])
 			port := slot.GetPort()
 			protocol := slot.GetProtocol()
 			if err := selfRegister(host, port, protocol, polarisService.Isolated, service); err != nil {
@@ -409,11 +435,35 @@ func getLocalHost(vip string) (string, error) {
 	}
 	defer conn.Close()
 
-	localAddr := conn.LocalAddr().String() // ip:port
-	segs := strings.Split(localAddr, ":")
-	if len(segs) != 2 {
+	localAddr := conn.LocalAddr().String() // ip:port或者[ipv6]:port
+	localHost := utils.ParseClientIP(localAddr)
+	if localHost == "" {
 		return "", errors.New("get local address format is invalid")
 	}
 
-	return segs[0], nil
+	return localHost, nil
+}
+
+// 获取本机http apiserver的自注册地址，作为本机在健康检查server集群中的地址
+func localPeer(apiServers []apiserver.Config) string {
+	for _, server := range apiServers {
+		slot, exist := apiserver.Slots[server.Name]
+		if !exist || slot.GetProtocol() != "http" {
+			continue
+		}
+		port, _ := server.Option["listenPort"].(int)
+		listenIP, _ := server.Option["listenIP"].(string)
+		return net.JoinHostPort(registerHost(listenIP), strconv.Itoa(port))
+	}
+	return ""
+}
+
+// 获取自注册使用的host
+// apiserver监听在指定的IP上时，使用监听的IP注册，监听在0.0.0.0或者::等通配地址上时，使用探测到的本机IP注册
+func registerHost(listenIP string) string {
+	ip := net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(listenIP, "["), "]"))
+	if ip == nil || ip.IsUnspecified() {
+		return LocalHost
+	}
+	return ip.String()
 }
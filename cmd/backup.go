@@ -0,0 +1,184 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	exportPath = "/maintain/v1/config/export"
+	importPath = "/maintain/v1/config/import"
+)
+
+var (
+	backupServer     = ""
+	backupNamespaces []string
+	backupFormat     = ""
+	exportOutput     = ""
+	importFile       = ""
+	importDryRun     = false
+	importConflict   = ""
+	backupToken      = ""
+	backupUserToken  = ""
+	exportWithToken  = false
+
+	exportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "export configuration",
+		Long:  "export namespaces, services, instances and rules of a running polaris server",
+		RunE: func(c *cobra.Command, args []string) error {
+			return exportConfig()
+		},
+	}
+
+	importCmd = &cobra.Command{
+		Use:   "import",
+		Short: "import configuration",
+		Long:  "import an exported configuration bundle into a running polaris server",
+		RunE: func(c *cobra.Command, args []string) error {
+			return importConfig()
+		},
+	}
+)
+
+/**
+ * @brief 解析命令参数
+ */
+func init() {
+	for _, c := range []*cobra.Command{exportCmd, importCmd} {
+		c.Flags().StringVar(&backupServer, "server", "127.0.0.1:8090", "http address of polaris server")
+		c.Flags().StringSliceVar(&backupNamespaces, "namespace", nil, "namespaces to export or import, default all")
+		c.Flags().StringVar(&backupFormat, "format", "", "bundle format, json or yaml")
+		c.Flags().StringVar(&backupToken, "token", "", "global token of polaris server")
+		c.Flags().StringVar(&backupUserToken, "user-token", "", "access token of a user granted the auth resource")
+	}
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "output file, default stdout")
+	exportCmd.Flags().BoolVar(&exportWithToken, "with-token", false,
+		"export tokens of namespaces, services and circuit breakers")
+	importCmd.Flags().StringVarP(&importFile, "file", "f", "", "bundle file to import")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "check the bundle without any modification")
+	importCmd.Flags().StringVar(&importConflict, "conflict", "skip",
+		"policy for existed resources: skip, overwrite or fail")
+	_ = importCmd.MarkFlagRequired("file")
+}
+
+// 导出配置，写入到文件或者标准输出
+func exportConfig() error {
+	query := url.Values{}
+	for _, namespace := range backupNamespaces {
+		query.Add("namespace", namespace)
+	}
+	if backupFormat != "" {
+		query.Set("format", backupFormat)
+	}
+	if exportWithToken {
+		query.Set("with_token", "true")
+	}
+
+	data, err := doBackupRequest(http.MethodGet, exportPath, query, "", nil)
+	if err != nil {
+		return err
+	}
+	if exportOutput == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return ioutil.WriteFile(exportOutput, data, 0644)
+}
+
+// 导入配置，输出导入结果
+func importConfig() error {
+	data, err := ioutil.ReadFile(importFile)
+	if err != nil {
+		return err
+	}
+	format := backupFormat
+	if format == "" {
+		format = "json"
+		if ext := strings.ToLower(filepath.Ext(importFile)); ext == ".yaml" || ext == ".yml" {
+			format = "yaml"
+		}
+	}
+
+	query := url.Values{}
+	for _, namespace := range backupNamespaces {
+		query.Add("namespace", namespace)
+	}
+	query.Set("format", format)
+	query.Set("conflict", importConflict)
+	query.Set("dry_run", strconv.FormatBool(importDryRun))
+
+	contentType := "application/json"
+	if format == "yaml" {
+		contentType = "application/x-yaml"
+	}
+	result, err := doBackupRequest(http.MethodPost, importPath, query, contentType, data)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(result))
+	return nil
+}
+
+// 调用polaris server的运维接口
+func doBackupRequest(method, path string, query url.Values, contentType string, body []byte) ([]byte, error) {
+	address := backupServer
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "http://" + address
+	}
+	req, err := http.NewRequest(method, address+path+"?"+query.Encode(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if backupToken != "" {
+		req.Header.Set("Polaris-Token", backupToken)
+	}
+	if backupUserToken != "" {
+		req.Header.Set("Authorization", "Bearer "+backupUserToken)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	rsp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	data, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s, %s", method, path, rsp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
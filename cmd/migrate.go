@@ -0,0 +1,189 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/polarismesh/polaris-server/config"
+	"github.com/polarismesh/polaris-server/plugin"
+	"github.com/polarismesh/polaris-server/store/defaultStore"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateConfigPath = ""
+	migrateUpTarget   = 0
+	migrateBaseline   = 0
+	migrateDownTarget = 0
+	migrateSteps      = 0
+
+	migrateCmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "manage database schema",
+		Long:  "upgrade, downgrade or show the schema version of the mysql store",
+	}
+
+	migrateUpCmd = &cobra.Command{
+		Use:   "up",
+		Short: "upgrade database schema",
+		Long:  "apply pending schema migrations, up to the latest version by default",
+		RunE: func(c *cobra.Command, args []string) error {
+			return migrateUp()
+		},
+	}
+
+	migrateDownCmd = &cobra.Command{
+		Use:   "down",
+		Short: "downgrade database schema",
+		Long:  "revert applied schema migrations, the last one by default",
+		RunE: func(c *cobra.Command, args []string) error {
+			return migrateDown()
+		},
+	}
+
+	migrateStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "show database schema version",
+		Long:  "show applied and pending schema migrations",
+		RunE: func(c *cobra.Command, args []string) error {
+			return migrateStatus()
+		},
+	}
+)
+
+/**
+ * @brief 解析命令参数
+ */
+func init() {
+	migrateCmd.PersistentFlags().StringVarP(&migrateConfigPath, "config", "c", "polaris-server.yaml",
+		"config file path")
+	migrateUpCmd.Flags().IntVar(&migrateUpTarget, "to", 0, "target schema version, default latest")
+	migrateUpCmd.Flags().IntVar(&migrateBaseline, "baseline", 0,
+		"schema version of a database imported without version records, migrations up to it are only recorded")
+	migrateDownCmd.Flags().IntVar(&migrateDownTarget, "to", -1, "target schema version, 0 reverts all")
+	migrateDownCmd.Flags().IntVar(&migrateSteps, "steps", 1, "number of migrations to revert when --to is not set")
+
+	migrateCmd.AddCommand(migrateUpCmd)
+	migrateCmd.AddCommand(migrateDownCmd)
+	migrateCmd.AddCommand(migrateStatusCmd)
+}
+
+// 升级数据库
+func migrateUp() error {
+	migrator, err := newSchemaMigrator()
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	if migrateBaseline > 0 {
+		if err := migrator.Baseline(migrateBaseline); err != nil {
+			return err
+		}
+		fmt.Printf("baseline schema version: %d\n", migrateBaseline)
+	}
+	applied, err := migrator.Up(migrateUpTarget)
+	for _, migration := range applied {
+		fmt.Printf("migrated up: %04d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Println("database schema is up to date")
+	}
+	return nil
+}
+
+// 回滚数据库
+func migrateDown() error {
+	migrator, err := newSchemaMigrator()
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	target := migrateDownTarget
+	if target < 0 {
+		status, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		if target = status.Current - migrateSteps; target < 0 {
+			target = 0
+		}
+	}
+	reverted, err := migrator.Down(target)
+	for _, migration := range reverted {
+		fmt.Printf("migrated down: %04d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(reverted) == 0 {
+		fmt.Println("no migration to revert")
+	}
+	return nil
+}
+
+// 输出数据库的版本状态
+func migrateStatus() error {
+	migrator, err := newSchemaMigrator()
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("current version: %d, latest version: %d\n", status.Current, status.Latest)
+	if status.Legacy {
+		fmt.Println("database has no schema version, run migrate up with --baseline to set it")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+	for _, version := range status.Applied {
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", version.Version, version.Name,
+			version.AppliedTime.Format("2006-01-02 15:04:05"))
+	}
+	for _, migration := range status.Pending {
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", migration.Version, migration.Name, "pending")
+	}
+	return w.Flush()
+}
+
+// 根据配置文件连接数据库
+func newSchemaMigrator() (*defaultStore.SchemaMigrator, error) {
+	cfg, err := config.Load(migrateConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Store.Name != defaultStore.STORENAME {
+		return nil, errors.New("migrate only supports store " + defaultStore.STORENAME)
+	}
+	// 数据库密码可能需要通过插件解析
+	plugin.SetPluginConfig(&cfg.Plugin)
+	return defaultStore.NewSchemaMigrator(cfg.Store.Option)
+}
@@ -0,0 +1,105 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	parseCode "github.com/polarismesh/polaris-server/plugin/parsePassword"
+	"github.com/spf13/cobra"
+)
+
+var (
+	passwordKeyFile = ""
+	passwordKeyEnv  = ""
+
+	passwordCmd = &cobra.Command{
+		Use:   "password",
+		Short: "manage encrypted passwords",
+		Long:  "generate key and encrypt passwords for the aesParse password plugin",
+	}
+
+	passwordGenKeyCmd = &cobra.Command{
+		Use:   "genkey",
+		Short: "generate aes key",
+		Long:  "generate a random base64 encoded 32 bytes aes key",
+		RunE: func(c *cobra.Command, args []string) error {
+			return generatePasswordKey()
+		},
+	}
+
+	passwordEncryptCmd = &cobra.Command{
+		Use:   "encrypt",
+		Short: "encrypt password",
+		Long:  "read password from stdin and print the cipher which can be written to the config file",
+		RunE: func(c *cobra.Command, args []string) error {
+			return encryptPassword()
+		},
+	}
+)
+
+/**
+ * @brief 解析命令参数
+ */
+func init() {
+	passwordEncryptCmd.Flags().StringVar(&passwordKeyFile, "key-file", "", "aes key file")
+	passwordEncryptCmd.Flags().StringVar(&passwordKeyEnv, "key-env", parseCode.DefaultKeyEnv,
+		"env of the aes key, used when key-file is not set")
+
+	passwordCmd.AddCommand(passwordGenKeyCmd)
+	passwordCmd.AddCommand(passwordEncryptCmd)
+}
+
+// 生成随机的密钥
+func generatePasswordKey() error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	fmt.Println(base64.StdEncoding.EncodeToString(key))
+	return nil
+}
+
+// 从标准输入读取密码并且加密
+func encryptPassword() error {
+	key, err := parseCode.LoadKey(passwordKeyFile, passwordKeyEnv)
+	if err != nil {
+		return err
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		if err != nil {
+			return err
+		}
+		return errors.New("password is empty")
+	}
+
+	cipher, err := parseCode.EncryptPassword(key, password)
+	if err != nil {
+		return err
+	}
+	fmt.Println(cipher)
+	return nil
+}
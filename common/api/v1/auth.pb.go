@@ -0,0 +1,376 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: auth.proto
+
+package v1
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import wrappers "github.com/golang/protobuf/ptypes/wrappers"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type User struct {
+	Id                   *wrappers.StringValue `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 *wrappers.StringValue `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Password             *wrappers.StringValue `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Comment              *wrappers.StringValue `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"`
+	Token                *wrappers.StringValue `protobuf:"bytes,5,opt,name=token,proto3" json:"token,omitempty"`
+	Ctime                *wrappers.StringValue `protobuf:"bytes,6,opt,name=ctime,proto3" json:"ctime,omitempty"`
+	Mtime                *wrappers.StringValue `protobuf:"bytes,7,opt,name=mtime,proto3" json:"mtime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *User) Reset()         { *m = User{} }
+func (m *User) String() string { return proto.CompactTextString(m) }
+func (*User) ProtoMessage()    {}
+func (*User) Descriptor() ([]byte, []int) {
+	return fileDescriptor_auth_8485d36b161480c7, []int{0}
+}
+func (m *User) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_User.Unmarshal(m, b)
+}
+func (m *User) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_User.Marshal(b, m, deterministic)
+}
+func (dst *User) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_User.Merge(dst, src)
+}
+func (m *User) XXX_Size() int {
+	return xxx_messageInfo_User.Size(m)
+}
+func (m *User) XXX_DiscardUnknown() {
+	xxx_messageInfo_User.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_User proto.InternalMessageInfo
+
+func (m *User) GetId() *wrappers.StringValue {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *User) GetName() *wrappers.StringValue {
+	if m != nil {
+		return m.Name
+	}
+	return nil
+}
+
+func (m *User) GetPassword() *wrappers.StringValue {
+	if m != nil {
+		return m.Password
+	}
+	return nil
+}
+
+func (m *User) GetComment() *wrappers.StringValue {
+	if m != nil {
+		return m.Comment
+	}
+	return nil
+}
+
+func (m *User) GetToken() *wrappers.StringValue {
+	if m != nil {
+		return m.Token
+	}
+	return nil
+}
+
+func (m *User) GetCtime() *wrappers.StringValue {
+	if m != nil {
+		return m.Ctime
+	}
+	return nil
+}
+
+func (m *User) GetMtime() *wrappers.StringValue {
+	if m != nil {
+		return m.Mtime
+	}
+	return nil
+}
+
+type UserGroup struct {
+	Id                   *wrappers.StringValue `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 *wrappers.StringValue `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Comment              *wrappers.StringValue `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	Users                []string              `protobuf:"bytes,4,rep,name=users,proto3" json:"users,omitempty"`
+	Ctime                *wrappers.StringValue `protobuf:"bytes,5,opt,name=ctime,proto3" json:"ctime,omitempty"`
+	Mtime                *wrappers.StringValue `protobuf:"bytes,6,opt,name=mtime,proto3" json:"mtime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *UserGroup) Reset()         { *m = UserGroup{} }
+func (m *UserGroup) String() string { return proto.CompactTextString(m) }
+func (*UserGroup) ProtoMessage()    {}
+func (*UserGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_auth_8485d36b161480c7, []int{1}
+}
+func (m *UserGroup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UserGroup.Unmarshal(m, b)
+}
+func (m *UserGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UserGroup.Marshal(b, m, deterministic)
+}
+func (dst *UserGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserGroup.Merge(dst, src)
+}
+func (m *UserGroup) XXX_Size() int {
+	return xxx_messageInfo_UserGroup.Size(m)
+}
+func (m *UserGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserGroup proto.InternalMessageInfo
+
+func (m *UserGroup) GetId() *wrappers.StringValue {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *UserGroup) GetName() *wrappers.StringValue {
+	if m != nil {
+		return m.Name
+	}
+	return nil
+}
+
+func (m *UserGroup) GetComment() *wrappers.StringValue {
+	if m != nil {
+		return m.Comment
+	}
+	return nil
+}
+
+func (m *UserGroup) GetUsers() []string {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+func (m *UserGroup) GetCtime() *wrappers.StringValue {
+	if m != nil {
+		return m.Ctime
+	}
+	return nil
+}
+
+func (m *UserGroup) GetMtime() *wrappers.StringValue {
+	if m != nil {
+		return m.Mtime
+	}
+	return nil
+}
+
+type AuthPolicy struct {
+	Resource             *wrappers.StringValue `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
+	Namespace            *wrappers.StringValue `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Service              *wrappers.StringValue `protobuf:"bytes,3,opt,name=service,proto3" json:"service,omitempty"`
+	Actions              []string              `protobuf:"bytes,4,rep,name=actions,proto3" json:"actions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *AuthPolicy) Reset()         { *m = AuthPolicy{} }
+func (m *AuthPolicy) String() string { return proto.CompactTextString(m) }
+func (*AuthPolicy) ProtoMessage()    {}
+func (*AuthPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_auth_8485d36b161480c7, []int{2}
+}
+func (m *AuthPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthPolicy.Unmarshal(m, b)
+}
+func (m *AuthPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthPolicy.Marshal(b, m, deterministic)
+}
+func (dst *AuthPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthPolicy.Merge(dst, src)
+}
+func (m *AuthPolicy) XXX_Size() int {
+	return xxx_messageInfo_AuthPolicy.Size(m)
+}
+func (m *AuthPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthPolicy proto.InternalMessageInfo
+
+func (m *AuthPolicy) GetResource() *wrappers.StringValue {
+	if m != nil {
+		return m.Resource
+	}
+	return nil
+}
+
+func (m *AuthPolicy) GetNamespace() *wrappers.StringValue {
+	if m != nil {
+		return m.Namespace
+	}
+	return nil
+}
+
+func (m *AuthPolicy) GetService() *wrappers.StringValue {
+	if m != nil {
+		return m.Service
+	}
+	return nil
+}
+
+func (m *AuthPolicy) GetActions() []string {
+	if m != nil {
+		return m.Actions
+	}
+	return nil
+}
+
+type Role struct {
+	Id                   *wrappers.StringValue `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 *wrappers.StringValue `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Comment              *wrappers.StringValue `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	Users                []string              `protobuf:"bytes,4,rep,name=users,proto3" json:"users,omitempty"`
+	Groups               []string              `protobuf:"bytes,5,rep,name=groups,proto3" json:"groups,omitempty"`
+	Policies             []*AuthPolicy         `protobuf:"bytes,6,rep,name=policies,proto3" json:"policies,omitempty"`
+	Ctime                *wrappers.StringValue `protobuf:"bytes,7,opt,name=ctime,proto3" json:"ctime,omitempty"`
+	Mtime                *wrappers.StringValue `protobuf:"bytes,8,opt,name=mtime,proto3" json:"mtime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *Role) Reset()         { *m = Role{} }
+func (m *Role) String() string { return proto.CompactTextString(m) }
+func (*Role) ProtoMessage()    {}
+func (*Role) Descriptor() ([]byte, []int) {
+	return fileDescriptor_auth_8485d36b161480c7, []int{3}
+}
+func (m *Role) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Role.Unmarshal(m, b)
+}
+func (m *Role) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Role.Marshal(b, m, deterministic)
+}
+func (dst *Role) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Role.Merge(dst, src)
+}
+func (m *Role) XXX_Size() int {
+	return xxx_messageInfo_Role.Size(m)
+}
+func (m *Role) XXX_DiscardUnknown() {
+	xxx_messageInfo_Role.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Role proto.InternalMessageInfo
+
+func (m *Role) GetId() *wrappers.StringValue {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *Role) GetName() *wrappers.StringValue {
+	if m != nil {
+		return m.Name
+	}
+	return nil
+}
+
+func (m *Role) GetComment() *wrappers.StringValue {
+	if m != nil {
+		return m.Comment
+	}
+	return nil
+}
+
+func (m *Role) GetUsers() []string {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+func (m *Role) GetGroups() []string {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+func (m *Role) GetPolicies() []*AuthPolicy {
+	if m != nil {
+		return m.Policies
+	}
+	return nil
+}
+
+func (m *Role) GetCtime() *wrappers.StringValue {
+	if m != nil {
+		return m.Ctime
+	}
+	return nil
+}
+
+func (m *Role) GetMtime() *wrappers.StringValue {
+	if m != nil {
+		return m.Mtime
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*User)(nil), "v1.User")
+	proto.RegisterType((*UserGroup)(nil), "v1.UserGroup")
+	proto.RegisterType((*AuthPolicy)(nil), "v1.AuthPolicy")
+	proto.RegisterType((*Role)(nil), "v1.Role")
+}
+
+func init() { proto.RegisterFile("auth.proto", fileDescriptor_auth_8485d36b161480c7) }
+
+var fileDescriptor_auth_8485d36b161480c7 = []byte{
+	// 359 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x92, 0xcd, 0x4a, 0xeb, 0x40,
+	0x14, 0x80, 0x69, 0x7e, 0xdb, 0x53, 0xb8, 0x8b, 0xe1, 0x72, 0x19, 0x2e, 0x97, 0x4b, 0xe9, 0xaa,
+	0x88, 0xa4, 0xb6, 0x82, 0x88, 0x3b, 0x57, 0x6e, 0x25, 0xa2, 0xfb, 0x34, 0x3d, 0xa6, 0x83, 0x49,
+	0x26, 0xcc, 0x4f, 0x8b, 0x8f, 0xe3, 0x3b, 0xf9, 0x00, 0x3e, 0x84, 0x0f, 0x20, 0x33, 0x69, 0x1a,
+	0x97, 0x63, 0xc1, 0x85, 0xcb, 0x33, 0xf3, 0x7d, 0x9b, 0xef, 0x1c, 0x80, 0x4c, 0xab, 0x4d, 0xd2,
+	0x08, 0xae, 0x38, 0xf1, 0xb6, 0x8b, 0xbf, 0xff, 0x0b, 0xce, 0x8b, 0x12, 0xe7, 0xf6, 0x65, 0xa5,
+	0x1f, 0xe7, 0x3b, 0x91, 0x35, 0x0d, 0x0a, 0xd9, 0x32, 0xd3, 0x77, 0x0f, 0x82, 0x7b, 0x89, 0x82,
+	0x9c, 0x82, 0xc7, 0xd6, 0x74, 0x30, 0x19, 0xcc, 0xc6, 0xcb, 0x7f, 0x49, 0x6b, 0x25, 0x9d, 0x95,
+	0xdc, 0x29, 0xc1, 0xea, 0xe2, 0x21, 0x2b, 0x35, 0xa6, 0x1e, 0x5b, 0x93, 0x33, 0x08, 0xea, 0xac,
+	0x42, 0xea, 0x39, 0xf0, 0x96, 0x24, 0x97, 0x30, 0x6c, 0x32, 0x29, 0x77, 0x5c, 0xac, 0xa9, 0xef,
+	0x60, 0x1d, 0x68, 0x72, 0x01, 0x71, 0xce, 0xab, 0x0a, 0x6b, 0x45, 0x03, 0x07, 0xb1, 0x83, 0xc9,
+	0x12, 0x42, 0xc5, 0x9f, 0xb0, 0xa6, 0xa1, 0x83, 0xd5, 0xa2, 0xc6, 0xc9, 0x15, 0xab, 0x90, 0x46,
+	0x2e, 0x8e, 0x45, 0x8d, 0x53, 0x59, 0x27, 0x76, 0x71, 0x2c, 0x3a, 0x7d, 0xf1, 0x60, 0x64, 0xb2,
+	0xdf, 0x08, 0xae, 0x9b, 0x6f, 0x6f, 0xff, 0xa9, 0xa0, 0xff, 0x95, 0x82, 0xbf, 0x21, 0xd4, 0x12,
+	0x85, 0xa4, 0xc1, 0xc4, 0x9f, 0x8d, 0xd2, 0x76, 0xe8, 0x1b, 0x85, 0x47, 0x34, 0x8a, 0xdc, 0x1b,
+	0xbd, 0x0e, 0x00, 0xae, 0xb5, 0xda, 0xdc, 0xf2, 0x92, 0xe5, 0xcf, 0xe6, 0x80, 0x04, 0x4a, 0xae,
+	0x45, 0x8e, 0x4e, 0xa9, 0x0e, 0x34, 0xb9, 0x82, 0x91, 0xc9, 0x20, 0x9b, 0x2c, 0x77, 0xab, 0xd6,
+	0xe3, 0x26, 0x9d, 0x44, 0xb1, 0x65, 0x39, 0xba, 0xa5, 0xdb, 0xc3, 0x84, 0x42, 0x9c, 0xe5, 0x8a,
+	0xf1, 0xba, 0x8b, 0xd7, 0x8d, 0xd3, 0x37, 0x0f, 0x82, 0x94, 0x97, 0xf8, 0xc3, 0xb6, 0xfe, 0x07,
+	0xa2, 0xc2, 0x1c, 0xab, 0xa4, 0xa1, 0x7d, 0xde, 0x4f, 0xe4, 0x04, 0x86, 0x8d, 0x59, 0x10, 0x43,
+	0x49, 0xa3, 0x89, 0x3f, 0x1b, 0x2f, 0x7f, 0x25, 0xdb, 0x45, 0xd2, 0x2f, 0x2e, 0x3d, 0xfc, 0xf7,
+	0x97, 0x13, 0x1f, 0x71, 0x39, 0x43, 0xe7, 0xcb, 0x59, 0x45, 0xf6, 0xf3, 0xfc, 0x63, 0x00, 0x4d,
+	0x78, 0x91, 0xd2, 0x0d, 0x05, 0x00, 0x00,
+}
@@ -29,6 +29,9 @@ const (
 	EmptyRequest                           = 400002
 	BatchSizeOverLimit                     = 400003
 	InvalidDiscoverResource                = 400004
+	SubscriptionExceedLimit                = 400005
+	WatchEventExpired                      = 400006
+	WatchEventOverflow                     = 400007
 	InvalidRequestID                       = 400100
 	InvalidUserName                        = 400101
 	InvalidUserToken                       = 400102
@@ -96,6 +99,15 @@ const (
 	InvalidFluxRateLimitQps    = 400191
 	InvalidFluxRateLimitSetKey = 400192
 
+	// 用户权限相关错误码
+	InvalidUserPassword  = 400193
+	InvalidUserGroupName = 400194
+	InvalidRoleName      = 400195
+	InvalidAuthPolicy    = 400196
+
+	// 规则校验相关错误码
+	InvalidRuleValidation = 400197
+
 	ExistedResource                    = 400201
 	NotFoundResource                   = 400202
 	NamespaceExistedServices           = 400203
@@ -132,6 +144,8 @@ const (
 	NotAllowDifferentNamespaceBindRule = 400507
 	Unauthorized                       = 401000
 	NotAllowedAccess                   = 401001
+	UserLoginFailed                    = 401002
+	UserLoginRequired                  = 401003
 	IPRateLimit                        = 403001
 	APIRateLimit                       = 403002
 	CMDBNotFindHost                    = 404001
@@ -157,6 +171,9 @@ var code2info = map[uint32]string{
 	EmptyRequest:                       "empty request",
 	BatchSizeOverLimit:                 "batch size over the limit",
 	InvalidDiscoverResource:            "invalid discover resource",
+	SubscriptionExceedLimit:            "subscription count over the limit",
+	WatchEventExpired:                  "watch seq or revision has expired, please resync",
+	WatchEventOverflow:                 "too many watch events not consumed, please resume from the last seq",
 	InvalidRequestID:                   "invalid request id",
 	InvalidUserName:                    "invalid user name",
 	InvalidUserToken:                   "invalid user token",
@@ -236,6 +253,8 @@ var code2info = map[uint32]string{
 	NotFoundTagConfigOrService:         "not found tag config or service, or relation already exists",
 	Unauthorized:                       "unauthorized",
 	NotAllowedAccess:                   "access is not approved",
+	UserLoginFailed:                    "user name or password is wrong",
+	UserLoginRequired:                  "user login is required",
 	IPRateLimit:                        "server limit the ip access",
 	APIRateLimit:                       "server limit the api access",
 	CMDBNotFindHost:                    "not found the host cmdb",
@@ -264,6 +283,11 @@ var code2info = map[uint32]string{
 	InvalidFluxRateLimitId:             "invalid flux ratelimit id",
 	InvalidFluxRateLimitQps:            "invalid flux ratelimit qps",
 	InvalidFluxRateLimitSetKey:         "invalid flux ratelimit key",
+	InvalidUserPassword:                "invalid user password",
+	InvalidUserGroupName:               "invalid user group name",
+	InvalidRoleName:                    "invalid role name",
+	InvalidAuthPolicy:                  "invalid auth policy",
+	InvalidRuleValidation:              "rule validation failed",
 }
 
 // code to info
@@ -45,6 +45,8 @@ type PolarisGRPCClient interface {
 	Discover(ctx context.Context, opts ...grpc.CallOption) (PolarisGRPC_DiscoverClient, error)
 	// 被调方上报心跳
 	Heartbeat(ctx context.Context, in *Instance, opts ...grpc.CallOption) (*Response, error)
+	// 订阅配置的变更事件
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (PolarisGRPC_WatchClient, error)
 }
 
 type polarisGRPCClient struct {
@@ -122,6 +124,38 @@ func (c *polarisGRPCClient) Heartbeat(ctx context.Context, in *Instance, opts ..
 	return out, nil
 }
 
+func (c *polarisGRPCClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (PolarisGRPC_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_PolarisGRPC_serviceDesc.Streams[1], "/v1.PolarisGRPC/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &polarisGRPCWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PolarisGRPC_WatchClient interface {
+	Recv() (*WatchResponse, error)
+	grpc.ClientStream
+}
+
+type polarisGRPCWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *polarisGRPCWatchClient) Recv() (*WatchResponse, error) {
+	m := new(WatchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // PolarisGRPCServer is the server API for PolarisGRPC service.
 type PolarisGRPCServer interface {
 	// 客户端上报
@@ -134,6 +168,8 @@ type PolarisGRPCServer interface {
 	Discover(PolarisGRPC_DiscoverServer) error
 	// 被调方上报心跳
 	Heartbeat(context.Context, *Instance) (*Response, error)
+	// 订阅配置的变更事件
+	Watch(*WatchRequest, PolarisGRPC_WatchServer) error
 }
 
 func RegisterPolarisGRPCServer(s *grpc.Server, srv PolarisGRPCServer) {
@@ -238,6 +274,27 @@ func _PolarisGRPC_Heartbeat_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PolarisGRPC_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PolarisGRPCServer).Watch(m, &polarisGRPCWatchServer{stream})
+}
+
+type PolarisGRPC_WatchServer interface {
+	Send(*WatchResponse) error
+	grpc.ServerStream
+}
+
+type polarisGRPCWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *polarisGRPCWatchServer) Send(m *WatchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _PolarisGRPC_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "v1.PolarisGRPC",
 	HandlerType: (*PolarisGRPCServer)(nil),
@@ -266,25 +323,32 @@ var _PolarisGRPC_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "Watch",
+			Handler:       _PolarisGRPC_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "grpcapi.proto",
 }
 
-func init() { proto.RegisterFile("grpcapi.proto", fileDescriptor_grpcapi_1bc1b7fe180cc761) }
-
-var fileDescriptor_grpcapi_1bc1b7fe180cc761 = []byte{
-	// 208 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x90, 0xbd, 0x4a, 0x04, 0x31,
-	0x10, 0xc7, 0xbd, 0x2b, 0x44, 0xc7, 0x3d, 0x91, 0xd1, 0x2a, 0xa5, 0x95, 0x5a, 0x2c, 0x77, 0x6b,
-	0x69, 0xb9, 0x0b, 0x6a, 0xb7, 0xe4, 0x0d, 0xb2, 0x61, 0x58, 0x02, 0x4b, 0x12, 0x67, 0x62, 0x5e,
-	0xc1, 0xd7, 0x96, 0xfd, 0x48, 0x21, 0x82, 0x60, 0xf7, 0xff, 0xc8, 0x2f, 0xc3, 0x0c, 0x1c, 0x46,
-	0x8e, 0xd6, 0x44, 0x57, 0x47, 0x0e, 0x29, 0xe0, 0x3e, 0x9f, 0x54, 0x65, 0x27, 0x47, 0x3e, 0xad,
-	0x89, 0x3a, 0x08, 0x71, 0x76, 0x96, 0x8a, 0x65, 0xfa, 0xf8, 0x24, 0x29, 0xed, 0x35, 0x93, 0xc4,
-	0xe0, 0x65, 0xab, 0x9b, 0xaf, 0x3d, 0x5c, 0xf5, 0x61, 0x32, 0xec, 0xe4, 0x55, 0xf7, 0x2d, 0x3e,
-	0x41, 0xa5, 0x29, 0x06, 0x4e, 0xed, 0xf2, 0x27, 0x42, 0x9d, 0x4f, 0xf5, 0xaa, 0x55, 0x35, 0x6b,
-	0xbd, 0xf1, 0xf7, 0x67, 0x78, 0x84, 0x1b, 0x4d, 0xa3, 0x93, 0x44, 0xfc, 0xee, 0x25, 0x19, 0x6f,
-	0x09, 0x97, 0x37, 0xc5, 0xfd, 0x22, 0x1a, 0xc0, 0x8e, 0xf8, 0x7f, 0xcc, 0x0b, 0x5c, 0x74, 0x4e,
-	0x6c, 0xc8, 0xc4, 0x78, 0x3b, 0x77, 0xc5, 0xe9, 0x75, 0x31, 0x75, 0xf7, 0x33, 0x2c, 0xe0, 0xc3,
-	0xee, 0xb8, 0xc3, 0x47, 0xb8, 0x7c, 0x23, 0xc3, 0x69, 0x20, 0x93, 0xfe, 0x9e, 0x33, 0x9c, 0x2f,
-	0x07, 0x79, 0xfe, 0x0e, 0x00, 0x00, 0xff, 0xff, 0xa5, 0x5a, 0x87, 0xc9, 0x61, 0x01, 0x00, 0x00,
+func init() { proto.RegisterFile("grpcapi.proto", fileDescriptor_grpcapi_654174bc9e8ef0f3) }
+
+var fileDescriptor_grpcapi_654174bc9e8ef0f3 = []byte{
+	// 230 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x50, 0x3d, 0x4b, 0x03, 0x41,
+	0x10, 0xf5, 0x02, 0x8a, 0x4e, 0x2e, 0x12, 0x47, 0xab, 0x2b, 0xad, 0xd4, 0xe2, 0xb8, 0xc4, 0xd2,
+	0x32, 0x01, 0xb5, 0x0b, 0xdb, 0x58, 0x6f, 0x96, 0x21, 0x2e, 0x84, 0xdb, 0x75, 0x66, 0x3c, 0x7f,
+	0x9b, 0xff, 0x4e, 0xee, 0xce, 0x01, 0xc5, 0x42, 0xd2, 0xbd, 0x8f, 0x79, 0xfb, 0xd8, 0x07, 0xb3,
+	0x1d, 0xe7, 0xe0, 0x73, 0xac, 0x33, 0x27, 0x4d, 0x38, 0xe9, 0x16, 0x55, 0x19, 0xf6, 0x91, 0x5a,
+	0x1d, 0x95, 0x6a, 0x26, 0xc4, 0x5d, 0x0c, 0x64, 0x94, 0xe9, 0xed, 0x9d, 0xc4, 0xdc, 0x73, 0x26,
+	0xc9, 0xa9, 0x15, 0xb3, 0xa7, 0x1f, 0x5e, 0xc3, 0xeb, 0x48, 0x96, 0x9f, 0x13, 0x98, 0x6e, 0xd2,
+	0xde, 0x73, 0x94, 0x47, 0xb7, 0x59, 0xe1, 0x1d, 0x94, 0x8e, 0x72, 0x62, 0x5d, 0x0d, 0x05, 0x08,
+	0x75, 0xb7, 0xa8, 0x47, 0x5c, 0x95, 0x3d, 0x76, 0xdf, 0x8f, 0x5d, 0x1f, 0x61, 0x03, 0x73, 0x47,
+	0xbb, 0x28, 0x4a, 0xfc, 0xdc, 0x8a, 0xfa, 0x36, 0x10, 0x0e, 0x37, 0xc6, 0xfe, 0x24, 0x96, 0x80,
+	0x6b, 0xe2, 0xc3, 0x32, 0x0f, 0x70, 0xba, 0x8e, 0x12, 0x52, 0x47, 0x8c, 0x97, 0xbd, 0x67, 0xcc,
+	0x8d, 0xbf, 0xac, 0xae, 0x7e, 0x8b, 0x16, 0xbc, 0x29, 0x9a, 0x02, 0x6f, 0xe1, 0xec, 0x89, 0x3c,
+	0xeb, 0x96, 0xbc, 0xfe, 0xd3, 0xd3, 0xc0, 0xf1, 0x4b, 0x3f, 0x0c, 0xce, 0x7b, 0x63, 0x80, 0xd6,
+	0x70, 0xf1, 0x43, 0xb1, 0xfb, 0xa6, 0xd8, 0x9e, 0x0c, 0x13, 0xde, 0x7f, 0x0d, 0x00, 0xfb, 0xbf,
+	0x69, 0x33, 0xa0, 0x01, 0x00, 0x00,
 }
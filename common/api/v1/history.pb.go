@@ -0,0 +1,165 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: history.proto
+
+package v1
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import wrappers "github.com/golang/protobuf/ptypes/wrappers"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type History struct {
+	Id                   *wrappers.UInt64Value `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Resource             *wrappers.StringValue `protobuf:"bytes,2,opt,name=resource,proto3" json:"resource,omitempty"`
+	Operation            *wrappers.StringValue `protobuf:"bytes,3,opt,name=operation,proto3" json:"operation,omitempty"`
+	Namespace            *wrappers.StringValue `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Service              *wrappers.StringValue `protobuf:"bytes,5,opt,name=service,proto3" json:"service,omitempty"`
+	Context              *wrappers.StringValue `protobuf:"bytes,6,opt,name=context,proto3" json:"context,omitempty"`
+	Operator             *wrappers.StringValue `protobuf:"bytes,7,opt,name=operator,proto3" json:"operator,omitempty"`
+	Revision             *wrappers.StringValue `protobuf:"bytes,8,opt,name=revision,proto3" json:"revision,omitempty"`
+	Before               *wrappers.StringValue `protobuf:"bytes,9,opt,name=before,proto3" json:"before,omitempty"`
+	After                *wrappers.StringValue `protobuf:"bytes,10,opt,name=after,proto3" json:"after,omitempty"`
+	Ctime                *wrappers.StringValue `protobuf:"bytes,11,opt,name=ctime,proto3" json:"ctime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *History) Reset()         { *m = History{} }
+func (m *History) String() string { return proto.CompactTextString(m) }
+func (*History) ProtoMessage()    {}
+func (*History) Descriptor() ([]byte, []int) {
+	return fileDescriptor_history_8cabb78fc067a6a9, []int{0}
+}
+func (m *History) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_History.Unmarshal(m, b)
+}
+func (m *History) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_History.Marshal(b, m, deterministic)
+}
+func (dst *History) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_History.Merge(dst, src)
+}
+func (m *History) XXX_Size() int {
+	return xxx_messageInfo_History.Size(m)
+}
+func (m *History) XXX_DiscardUnknown() {
+	xxx_messageInfo_History.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_History proto.InternalMessageInfo
+
+func (m *History) GetId() *wrappers.UInt64Value {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *History) GetResource() *wrappers.StringValue {
+	if m != nil {
+		return m.Resource
+	}
+	return nil
+}
+
+func (m *History) GetOperation() *wrappers.StringValue {
+	if m != nil {
+		return m.Operation
+	}
+	return nil
+}
+
+func (m *History) GetNamespace() *wrappers.StringValue {
+	if m != nil {
+		return m.Namespace
+	}
+	return nil
+}
+
+func (m *History) GetService() *wrappers.StringValue {
+	if m != nil {
+		return m.Service
+	}
+	return nil
+}
+
+func (m *History) GetContext() *wrappers.StringValue {
+	if m != nil {
+		return m.Context
+	}
+	return nil
+}
+
+func (m *History) GetOperator() *wrappers.StringValue {
+	if m != nil {
+		return m.Operator
+	}
+	return nil
+}
+
+func (m *History) GetRevision() *wrappers.StringValue {
+	if m != nil {
+		return m.Revision
+	}
+	return nil
+}
+
+func (m *History) GetBefore() *wrappers.StringValue {
+	if m != nil {
+		return m.Before
+	}
+	return nil
+}
+
+func (m *History) GetAfter() *wrappers.StringValue {
+	if m != nil {
+		return m.After
+	}
+	return nil
+}
+
+func (m *History) GetCtime() *wrappers.StringValue {
+	if m != nil {
+		return m.Ctime
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*History)(nil), "v1.History")
+}
+
+func init() { proto.RegisterFile("history.proto", fileDescriptor_history_8cabb78fc067a6a9) }
+
+var fileDescriptor_history_8cabb78fc067a6a9 = []byte{
+	// 262 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0xd0, 0xc1, 0x4a, 0x3b, 0x31,
+	0x10, 0xc7, 0x71, 0xba, 0xff, 0x76, 0xb7, 0x4d, 0xf9, 0x5f, 0x72, 0x1a, 0x44, 0x44, 0x3c, 0x79,
+	0x90, 0x2d, 0xd6, 0x52, 0xc4, 0x27, 0xd0, 0xab, 0xa2, 0xf7, 0xec, 0x76, 0x76, 0x0d, 0xb4, 0x99,
+	0x30, 0xc9, 0xae, 0xfa, 0xcc, 0xbe, 0x84, 0x64, 0xd3, 0xad, 0x27, 0x21, 0xd7, 0xe4, 0xfb, 0x81,
+	0xe1, 0x27, 0xfe, 0xbf, 0x6b, 0xe7, 0x89, 0xbf, 0x4a, 0xcb, 0xe4, 0x49, 0x66, 0xfd, 0xed, 0xd9,
+	0x45, 0x4b, 0xd4, 0xee, 0x71, 0x35, 0xbc, 0x54, 0x5d, 0xb3, 0xfa, 0x60, 0x65, 0x2d, 0xb2, 0x8b,
+	0xcd, 0xd5, 0xf7, 0x54, 0x14, 0x8f, 0x51, 0xc9, 0x1b, 0x91, 0xe9, 0x1d, 0x4c, 0x2e, 0x27, 0xd7,
+	0xcb, 0xf5, 0x79, 0x19, 0x61, 0x39, 0xc2, 0xf2, 0xf5, 0xc9, 0xf8, 0xed, 0xe6, 0x4d, 0xed, 0x3b,
+	0x7c, 0xce, 0xf4, 0x4e, 0xde, 0x8b, 0x39, 0xa3, 0xa3, 0x8e, 0x6b, 0x84, 0xec, 0x0f, 0xf3, 0xe2,
+	0x59, 0x9b, 0x36, 0x9a, 0x53, 0x2d, 0x1f, 0xc4, 0x82, 0x2c, 0xb2, 0xf2, 0x9a, 0x0c, 0xfc, 0x4b,
+	0xa0, 0xbf, 0x79, 0xb0, 0x46, 0x1d, 0xd0, 0x59, 0x55, 0x23, 0x4c, 0x53, 0xec, 0x29, 0x97, 0x5b,
+	0x51, 0x38, 0xe4, 0x5e, 0xd7, 0x08, 0xb3, 0x04, 0x39, 0xc6, 0xc1, 0xd5, 0x64, 0x3c, 0x7e, 0x7a,
+	0xc8, 0x53, 0xdc, 0x31, 0x0e, 0x0b, 0xc5, 0xc3, 0x89, 0xa1, 0x48, 0x59, 0x68, 0xac, 0xe3, 0xb6,
+	0xbd, 0x76, 0x61, 0xa0, 0x79, 0xda, 0xb6, 0xb1, 0x96, 0x1b, 0x91, 0x57, 0xd8, 0x10, 0x23, 0x2c,
+	0x12, 0xdc, 0xb1, 0x95, 0x6b, 0x31, 0x53, 0x8d, 0x47, 0x06, 0x91, 0x80, 0x62, 0x1a, 0x4c, 0xed,
+	0xf5, 0x01, 0x61, 0x99, 0x62, 0x86, 0xb4, 0xca, 0x87, 0xcf, 0xbb, 0x9f, 0x01, 0x00, 0x34, 0x0b,
+	0x64, 0xe4, 0xa9, 0x02, 0x00, 0x00,
+}
@@ -53,12 +53,43 @@ func (x DiscoverRequest_DiscoverRequestType) String() string {
 	return proto.EnumName(DiscoverRequest_DiscoverRequestType_name, int32(x))
 }
 func (DiscoverRequest_DiscoverRequestType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_request_89a70c847f5ccd5b, []int{0, 0}
+	return fileDescriptor_request_7a110997b1674f0a, []int{0, 0}
+}
+
+type DiscoverRequest_NearbyLevel int32
+
+const (
+	DiscoverRequest_ALL    DiscoverRequest_NearbyLevel = 0
+	DiscoverRequest_CAMPUS DiscoverRequest_NearbyLevel = 1
+	DiscoverRequest_ZONE   DiscoverRequest_NearbyLevel = 2
+	DiscoverRequest_REGION DiscoverRequest_NearbyLevel = 3
+)
+
+var DiscoverRequest_NearbyLevel_name = map[int32]string{
+	0: "ALL",
+	1: "CAMPUS",
+	2: "ZONE",
+	3: "REGION",
+}
+var DiscoverRequest_NearbyLevel_value = map[string]int32{
+	"ALL":    0,
+	"CAMPUS": 1,
+	"ZONE":   2,
+	"REGION": 3,
+}
+
+func (x DiscoverRequest_NearbyLevel) String() string {
+	return proto.EnumName(DiscoverRequest_NearbyLevel_name, int32(x))
+}
+func (DiscoverRequest_NearbyLevel) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_request_7a110997b1674f0a, []int{0, 1}
 }
 
 type DiscoverRequest struct {
 	Type                 DiscoverRequest_DiscoverRequestType `protobuf:"varint,1,opt,name=type,proto3,enum=v1.DiscoverRequest_DiscoverRequestType" json:"type,omitempty"`
 	Service              *Service                            `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	Nearby               DiscoverRequest_NearbyLevel         `protobuf:"varint,5,opt,name=nearby,proto3,enum=v1.DiscoverRequest_NearbyLevel" json:"nearby,omitempty"`
+	Location             *Location                           `protobuf:"bytes,6,opt,name=location,proto3" json:"location,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                            `json:"-"`
 	XXX_unrecognized     []byte                              `json:"-"`
 	XXX_sizecache        int32                               `json:"-"`
@@ -68,7 +99,7 @@ func (m *DiscoverRequest) Reset()         { *m = DiscoverRequest{} }
 func (m *DiscoverRequest) String() string { return proto.CompactTextString(m) }
 func (*DiscoverRequest) ProtoMessage()    {}
 func (*DiscoverRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_request_89a70c847f5ccd5b, []int{0}
+	return fileDescriptor_request_7a110997b1674f0a, []int{0}
 }
 func (m *DiscoverRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DiscoverRequest.Unmarshal(m, b)
@@ -102,29 +133,49 @@ func (m *DiscoverRequest) GetService() *Service {
 	return nil
 }
 
+func (m *DiscoverRequest) GetNearby() DiscoverRequest_NearbyLevel {
+	if m != nil {
+		return m.Nearby
+	}
+	return DiscoverRequest_ALL
+}
+
+func (m *DiscoverRequest) GetLocation() *Location {
+	if m != nil {
+		return m.Location
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*DiscoverRequest)(nil), "v1.DiscoverRequest")
 	proto.RegisterEnum("v1.DiscoverRequest_DiscoverRequestType", DiscoverRequest_DiscoverRequestType_name, DiscoverRequest_DiscoverRequestType_value)
+	proto.RegisterEnum("v1.DiscoverRequest_NearbyLevel", DiscoverRequest_NearbyLevel_name, DiscoverRequest_NearbyLevel_value)
 }
 
-func init() { proto.RegisterFile("request.proto", fileDescriptor_request_89a70c847f5ccd5b) }
-
-var fileDescriptor_request_89a70c847f5ccd5b = []byte{
-	// 242 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x90, 0xbf, 0x4b, 0xc3, 0x40,
-	0x14, 0xc7, 0xbd, 0x34, 0x6d, 0xc2, 0x8b, 0x6d, 0x1f, 0xd7, 0xa5, 0x38, 0x95, 0x80, 0xd8, 0x29,
-	0xd0, 0x3a, 0x3a, 0xc5, 0xf3, 0x90, 0xb3, 0xf5, 0x0a, 0xef, 0x2e, 0x3a, 0x16, 0x2c, 0x37, 0x74,
-	0x4a, 0x4c, 0x62, 0xa0, 0xa3, 0xf8, 0x8f, 0x4b, 0x1a, 0x5d, 0x42, 0xc7, 0xef, 0xaf, 0xcf, 0x83,
-	0x07, 0xe3, 0xd2, 0x7d, 0x7e, 0xb9, 0xaa, 0x4e, 0x8a, 0x32, 0xaf, 0x73, 0xee, 0x35, 0xab, 0x9b,
-	0x71, 0xe5, 0xca, 0xe6, 0x78, 0x70, 0x9d, 0x15, 0x7f, 0x7b, 0x30, 0x7d, 0x3a, 0x56, 0x87, 0xbc,
-	0x71, 0x25, 0x75, 0x65, 0xfe, 0x00, 0x7e, 0x7d, 0x2a, 0xdc, 0x9c, 0x2d, 0xd8, 0x72, 0xb2, 0xbe,
-	0x4b, 0x9a, 0x55, 0xd2, 0xab, 0xf4, 0xb5, 0x3d, 0x15, 0x8e, 0xce, 0x23, 0x7e, 0x0b, 0xc1, 0xdf,
-	0x85, 0xb9, 0xb7, 0x60, 0xcb, 0x68, 0x1d, 0xb5, 0x7b, 0xd3, 0x59, 0xf4, 0x9f, 0xc5, 0x3f, 0x0c,
-	0x66, 0x17, 0x20, 0x3c, 0x82, 0x20, 0xd3, 0x1b, 0xbd, 0x7b, 0xd7, 0x78, 0xc5, 0xaf, 0x21, 0x54,
-	0xda, 0xd8, 0x54, 0x0b, 0x89, 0xac, 0x8d, 0xc4, 0x36, 0x33, 0x56, 0x12, 0x7a, 0xad, 0xa0, 0x5d,
-	0x66, 0x95, 0x7e, 0xc6, 0x01, 0x9f, 0x00, 0x50, 0x6a, 0xe5, 0x7e, 0xab, 0x5e, 0x95, 0x45, 0x9f,
-	0xcf, 0x60, 0x2a, 0x14, 0x89, 0x4c, 0xd9, 0xfd, 0x23, 0xc9, 0x74, 0x23, 0x09, 0x87, 0x2d, 0xcc,
-	0x48, 0x7a, 0x53, 0x42, 0x1a, 0x1c, 0xc5, 0x7e, 0x18, 0x60, 0xf4, 0xe2, 0x87, 0x03, 0x1c, 0x7e,
-	0x8c, 0xce, 0xaf, 0xb8, 0xff, 0x0d, 0x00, 0x00, 0xff, 0xff, 0x54, 0x58, 0xfb, 0xdf, 0x2e, 0x01,
-	0x00, 0x00,
+func init() { proto.RegisterFile("request.proto", fileDescriptor_request_7a110997b1674f0a) }
+
+var fileDescriptor_request_7a110997b1674f0a = []byte{
+	// 331 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xcb, 0x6b, 0xc2, 0x30,
+	0x1c, 0xc7, 0xed, 0xc3, 0xb6, 0xfc, 0xea, 0x23, 0xc4, 0x4b, 0xf1, 0x32, 0x29, 0x8c, 0x79, 0x12,
+	0x74, 0x87, 0x0d, 0x76, 0xea, 0xba, 0x20, 0x99, 0x35, 0x1d, 0x69, 0xba, 0xc1, 0x2e, 0xa2, 0x2e,
+	0x07, 0xc1, 0x59, 0x57, 0xbb, 0x82, 0xe7, 0xfd, 0x1f, 0xfb, 0x5b, 0x47, 0x1f, 0x1b, 0x43, 0x3c,
+	0x7e, 0x5f, 0xf9, 0x24, 0x04, 0xda, 0xa9, 0xfc, 0xf8, 0x94, 0x87, 0x6c, 0xb4, 0x4f, 0x93, 0x2c,
+	0xc1, 0x6a, 0x3e, 0xee, 0xb7, 0x0f, 0x32, 0xcd, 0x37, 0x6b, 0x59, 0x59, 0x7d, 0xfb, 0x3d, 0x79,
+	0x93, 0xdb, 0x4a, 0xb8, 0xdf, 0x1a, 0x74, 0x1f, 0x36, 0x87, 0x75, 0x92, 0xcb, 0x94, 0x57, 0x4b,
+	0x7c, 0x07, 0x7a, 0x76, 0xdc, 0x4b, 0x47, 0x19, 0x28, 0xc3, 0xce, 0xe4, 0x6a, 0x94, 0x8f, 0x47,
+	0x27, 0x95, 0x53, 0x2d, 0x8e, 0x7b, 0xc9, 0xcb, 0x11, 0xbe, 0x04, 0xb3, 0xc6, 0x39, 0xea, 0x40,
+	0x19, 0xda, 0x13, 0xbb, 0xd8, 0x47, 0x95, 0xc5, 0x7f, 0x33, 0x7c, 0x03, 0xc6, 0x4e, 0x2e, 0xd3,
+	0xd5, 0xd1, 0x69, 0x96, 0x94, 0x8b, 0x73, 0x14, 0x56, 0x36, 0x02, 0x99, 0xcb, 0x2d, 0xaf, 0xeb,
+	0x78, 0x08, 0xd6, 0x36, 0x59, 0x2f, 0xb3, 0x4d, 0xb2, 0x73, 0x8c, 0x12, 0xd0, 0x2a, 0xa6, 0x41,
+	0xed, 0xf1, 0xbf, 0xd4, 0xfd, 0x52, 0xa0, 0x77, 0xe6, 0x9e, 0xd8, 0x06, 0x33, 0x66, 0x33, 0x16,
+	0xbe, 0x30, 0xd4, 0xc0, 0x2d, 0xb0, 0x28, 0x8b, 0x84, 0xc7, 0x7c, 0x82, 0x94, 0x22, 0xf2, 0x83,
+	0x38, 0x12, 0x84, 0x23, 0xb5, 0x10, 0x3c, 0x8c, 0x05, 0x65, 0x53, 0xa4, 0xe1, 0x0e, 0x00, 0xf7,
+	0x04, 0x59, 0x04, 0x74, 0x4e, 0x05, 0xd2, 0x71, 0x0f, 0xba, 0x3e, 0xe5, 0x7e, 0x4c, 0xc5, 0xe2,
+	0x9e, 0x13, 0x6f, 0x46, 0x38, 0x6a, 0x16, 0x87, 0x45, 0x84, 0x3f, 0x53, 0x9f, 0x44, 0xc8, 0x70,
+	0x75, 0xcb, 0x44, 0xb6, 0x7b, 0x0b, 0xf6, 0xbf, 0x67, 0x60, 0x13, 0x34, 0x2f, 0x08, 0x50, 0x03,
+	0x03, 0x18, 0xbe, 0x37, 0x7f, 0x8a, 0x23, 0xa4, 0x60, 0x0b, 0xf4, 0xd7, 0x90, 0x11, 0xa4, 0x16,
+	0x2e, 0x27, 0x53, 0x1a, 0x32, 0xa4, 0x3d, 0xea, 0x96, 0x86, 0x9a, 0x2b, 0xa3, 0xfc, 0xa7, 0xeb,
+	0x9f, 0x01, 0x00, 0x3f, 0xf0, 0xaa, 0x43, 0xd8, 0x01, 0x00, 0x00,
 }
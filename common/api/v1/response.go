@@ -218,6 +218,48 @@ func NewPlatformResponseWithMsg(code uint32, platform *Platform, msg string) *Re
 	return response
 }
 
+/**
+ * @brief 创建用户回复
+ */
+func NewUserResponse(code uint32, user *User) *Response {
+	return &Response{
+		Code: &wrappers.UInt32Value{Value: code},
+		Info: &wrappers.StringValue{Value: code2info[code]},
+		User: user,
+	}
+}
+
+/**
+ * @brief 创建用户组回复
+ */
+func NewUserGroupResponse(code uint32, group *UserGroup) *Response {
+	return &Response{
+		Code:      &wrappers.UInt32Value{Value: code},
+		Info:      &wrappers.StringValue{Value: code2info[code]},
+		UserGroup: group,
+	}
+}
+
+/**
+ * @brief 创建角色回复
+ */
+func NewRoleResponse(code uint32, role *Role) *Response {
+	return &Response{
+		Code: &wrappers.UInt32Value{Value: code},
+		Info: &wrappers.StringValue{Value: code2info[code]},
+		Role: role,
+	}
+}
+
+/**
+ * @brief 创建带详细信息的角色回复
+ */
+func NewRoleResponseWithMsg(code uint32, role *Role, msg string) *Response {
+	response := NewRoleResponse(code, role)
+	response.Info.Value += ": " + msg
+	return response
+}
+
 /**
  * @brief 创建批量回复
  */
@@ -327,6 +369,18 @@ func NewDiscoverCircuitBreakerResponse(code uint32, service *Service) *DiscoverR
 	}
 }
 
+/**
+ * @brief 创建配置变更事件的回复
+ */
+func NewWatchResponse(code uint32, seq uint64, events []*WatchEvent) *WatchResponse {
+	return &WatchResponse{
+		Code:   &wrappers.UInt32Value{Value: code},
+		Info:   &wrappers.StringValue{Value: code2info[code]},
+		Seq:    &wrappers.UInt64Value{Value: seq},
+		Events: events,
+	}
+}
+
 // 格式化responses
 // batch操作
 // 如果所有子错误码一致，那么使用子错误码
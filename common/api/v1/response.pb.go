@@ -54,7 +54,7 @@ func (x DiscoverResponse_DiscoverResponseType) String() string {
 	return proto.EnumName(DiscoverResponse_DiscoverResponseType_name, int32(x))
 }
 func (DiscoverResponse_DiscoverResponseType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_response_64a333bb5c94d3ea, []int{4, 0}
+	return fileDescriptor_response_d0089aaa65b56e5a, []int{5, 0}
 }
 
 type SimpleResponse struct {
@@ -69,7 +69,7 @@ func (m *SimpleResponse) Reset()         { *m = SimpleResponse{} }
 func (m *SimpleResponse) String() string { return proto.CompactTextString(m) }
 func (*SimpleResponse) ProtoMessage()    {}
 func (*SimpleResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_response_64a333bb5c94d3ea, []int{0}
+	return fileDescriptor_response_d0089aaa65b56e5a, []int{0}
 }
 func (m *SimpleResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SimpleResponse.Unmarshal(m, b)
@@ -103,6 +103,52 @@ func (m *SimpleResponse) GetInfo() *wrappers.StringValue {
 	return nil
 }
 
+type ValidationError struct {
+	Field                *wrappers.StringValue `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Message              *wrappers.StringValue `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *ValidationError) Reset()         { *m = ValidationError{} }
+func (m *ValidationError) String() string { return proto.CompactTextString(m) }
+func (*ValidationError) ProtoMessage()    {}
+func (*ValidationError) Descriptor() ([]byte, []int) {
+	return fileDescriptor_response_d0089aaa65b56e5a, []int{1}
+}
+func (m *ValidationError) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidationError.Unmarshal(m, b)
+}
+func (m *ValidationError) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidationError.Marshal(b, m, deterministic)
+}
+func (dst *ValidationError) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidationError.Merge(dst, src)
+}
+func (m *ValidationError) XXX_Size() int {
+	return xxx_messageInfo_ValidationError.Size(m)
+}
+func (m *ValidationError) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidationError.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidationError proto.InternalMessageInfo
+
+func (m *ValidationError) GetField() *wrappers.StringValue {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
+func (m *ValidationError) GetMessage() *wrappers.StringValue {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
 type Response struct {
 	Code                 *wrappers.UInt32Value `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
 	Info                 *wrappers.StringValue `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
@@ -116,6 +162,10 @@ type Response struct {
 	CircuitBreaker       *CircuitBreaker       `protobuf:"bytes,10,opt,name=circuitBreaker,proto3" json:"circuitBreaker,omitempty"`
 	ConfigRelease        *ConfigRelease        `protobuf:"bytes,11,opt,name=configRelease,proto3" json:"configRelease,omitempty"`
 	Platform             *Platform             `protobuf:"bytes,15,opt,name=platform,proto3" json:"platform,omitempty"`
+	User                 *User                 `protobuf:"bytes,19,opt,name=user,proto3" json:"user,omitempty"`
+	UserGroup            *UserGroup            `protobuf:"bytes,20,opt,name=userGroup,proto3" json:"userGroup,omitempty"`
+	Role                 *Role                 `protobuf:"bytes,21,opt,name=role,proto3" json:"role,omitempty"`
+	ValidationErrors     []*ValidationError    `protobuf:"bytes,22,rep,name=validationErrors,proto3" json:"validationErrors,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
 	XXX_unrecognized     []byte                `json:"-"`
 	XXX_sizecache        int32                 `json:"-"`
@@ -125,7 +175,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_response_64a333bb5c94d3ea, []int{1}
+	return fileDescriptor_response_d0089aaa65b56e5a, []int{2}
 }
 func (m *Response) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Response.Unmarshal(m, b)
@@ -229,6 +279,34 @@ func (m *Response) GetPlatform() *Platform {
 	return nil
 }
 
+func (m *Response) GetUser() *User {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+func (m *Response) GetUserGroup() *UserGroup {
+	if m != nil {
+		return m.UserGroup
+	}
+	return nil
+}
+
+func (m *Response) GetRole() *Role {
+	if m != nil {
+		return m.Role
+	}
+	return nil
+}
+
+func (m *Response) GetValidationErrors() []*ValidationError {
+	if m != nil {
+		return m.ValidationErrors
+	}
+	return nil
+}
+
 type BatchWriteResponse struct {
 	Code                 *wrappers.UInt32Value `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
 	Info                 *wrappers.StringValue `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
@@ -243,7 +321,7 @@ func (m *BatchWriteResponse) Reset()         { *m = BatchWriteResponse{} }
 func (m *BatchWriteResponse) String() string { return proto.CompactTextString(m) }
 func (*BatchWriteResponse) ProtoMessage()    {}
 func (*BatchWriteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_response_64a333bb5c94d3ea, []int{2}
+	return fileDescriptor_response_d0089aaa65b56e5a, []int{3}
 }
 func (m *BatchWriteResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BatchWriteResponse.Unmarshal(m, b)
@@ -304,6 +382,10 @@ type BatchQueryResponse struct {
 	RateLimits           []*Rule               `protobuf:"bytes,10,rep,name=rateLimits,proto3" json:"rateLimits,omitempty"`
 	ConfigWithServices   []*ConfigWithService  `protobuf:"bytes,11,rep,name=configWithServices,proto3" json:"configWithServices,omitempty"`
 	Platforms            []*Platform           `protobuf:"bytes,15,rep,name=platforms,proto3" json:"platforms,omitempty"`
+	Histories            []*History            `protobuf:"bytes,17,rep,name=histories,proto3" json:"histories,omitempty"`
+	Users                []*User               `protobuf:"bytes,18,rep,name=users,proto3" json:"users,omitempty"`
+	UserGroups           []*UserGroup          `protobuf:"bytes,19,rep,name=userGroups,proto3" json:"userGroups,omitempty"`
+	Roles                []*Role               `protobuf:"bytes,20,rep,name=roles,proto3" json:"roles,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
 	XXX_unrecognized     []byte                `json:"-"`
 	XXX_sizecache        int32                 `json:"-"`
@@ -313,7 +395,7 @@ func (m *BatchQueryResponse) Reset()         { *m = BatchQueryResponse{} }
 func (m *BatchQueryResponse) String() string { return proto.CompactTextString(m) }
 func (*BatchQueryResponse) ProtoMessage()    {}
 func (*BatchQueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_response_64a333bb5c94d3ea, []int{3}
+	return fileDescriptor_response_d0089aaa65b56e5a, []int{4}
 }
 func (m *BatchQueryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BatchQueryResponse.Unmarshal(m, b)
@@ -417,6 +499,34 @@ func (m *BatchQueryResponse) GetPlatforms() []*Platform {
 	return nil
 }
 
+func (m *BatchQueryResponse) GetHistories() []*History {
+	if m != nil {
+		return m.Histories
+	}
+	return nil
+}
+
+func (m *BatchQueryResponse) GetUsers() []*User {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+func (m *BatchQueryResponse) GetUserGroups() []*UserGroup {
+	if m != nil {
+		return m.UserGroups
+	}
+	return nil
+}
+
+func (m *BatchQueryResponse) GetRoles() []*Role {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
 type DiscoverResponse struct {
 	Code                 *wrappers.UInt32Value                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
 	Info                 *wrappers.StringValue                 `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
@@ -436,7 +546,7 @@ func (m *DiscoverResponse) Reset()         { *m = DiscoverResponse{} }
 func (m *DiscoverResponse) String() string { return proto.CompactTextString(m) }
 func (*DiscoverResponse) ProtoMessage()    {}
 func (*DiscoverResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_response_64a333bb5c94d3ea, []int{4}
+	return fileDescriptor_response_d0089aaa65b56e5a, []int{5}
 }
 func (m *DiscoverResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DiscoverResponse.Unmarshal(m, b)
@@ -521,6 +631,7 @@ func (m *DiscoverResponse) GetServices() []*Service {
 
 func init() {
 	proto.RegisterType((*SimpleResponse)(nil), "v1.SimpleResponse")
+	proto.RegisterType((*ValidationError)(nil), "v1.ValidationError")
 	proto.RegisterType((*Response)(nil), "v1.Response")
 	proto.RegisterType((*BatchWriteResponse)(nil), "v1.BatchWriteResponse")
 	proto.RegisterType((*BatchQueryResponse)(nil), "v1.BatchQueryResponse")
@@ -528,61 +639,72 @@ func init() {
 	proto.RegisterEnum("v1.DiscoverResponse_DiscoverResponseType", DiscoverResponse_DiscoverResponseType_name, DiscoverResponse_DiscoverResponseType_value)
 }
 
-func init() { proto.RegisterFile("response.proto", fileDescriptor_response_64a333bb5c94d3ea) }
-
-var fileDescriptor_response_64a333bb5c94d3ea = []byte{
-	// 842 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x55, 0xd1, 0x6e, 0xe3, 0x44,
-	0x14, 0x25, 0xad, 0x93, 0x38, 0x37, 0x6d, 0xe2, 0x9d, 0x2e, 0xd2, 0xa8, 0x42, 0x68, 0x65, 0x89,
-	0xa5, 0x64, 0x45, 0x96, 0xed, 0x22, 0x21, 0x21, 0xf1, 0xd0, 0xa6, 0xee, 0xe2, 0xb6, 0xeb, 0xc2,
-	0x38, 0x69, 0x79, 0xab, 0x5c, 0x33, 0xcd, 0x8e, 0x70, 0x6c, 0x6b, 0xc6, 0x29, 0x2a, 0x5f, 0xc0,
-	0xf7, 0xf0, 0x01, 0x7c, 0x06, 0x1f, 0xc0, 0x97, 0xa0, 0x19, 0xcf, 0xd8, 0x6e, 0x77, 0xc9, 0xf6,
-	0x29, 0x2f, 0x55, 0xe7, 0x9e, 0x73, 0x67, 0xee, 0xbd, 0x3e, 0xe7, 0x06, 0x06, 0x9c, 0x8a, 0x3c,
-	0x4b, 0x05, 0x1d, 0xe7, 0x3c, 0x2b, 0x32, 0xb4, 0x71, 0xfb, 0x6a, 0xf7, 0xf3, 0x79, 0x96, 0xcd,
-	0x13, 0xfa, 0x52, 0x45, 0xae, 0x97, 0x37, 0x2f, 0x7f, 0xe7, 0x51, 0x9e, 0x53, 0x2e, 0x4a, 0xce,
-	0xee, 0xb6, 0xa0, 0xfc, 0x96, 0xc5, 0xd4, 0x1c, 0x79, 0xb6, 0x2c, 0x58, 0x3a, 0xd7, 0xc7, 0xad,
-	0x38, 0x61, 0x34, 0x2d, 0xf4, 0x69, 0xc8, 0xa3, 0x82, 0x26, 0x6c, 0xc1, 0x4c, 0xe0, 0x69, 0xcc,
-	0x78, 0xbc, 0x64, 0xc5, 0x35, 0xa7, 0xd1, 0x6f, 0x94, 0xeb, 0xe8, 0x4e, 0x9c, 0xa5, 0x37, 0x6c,
-	0xce, 0x69, 0x42, 0x23, 0x53, 0xcb, 0xee, 0x20, 0x4f, 0xa2, 0xe2, 0x26, 0xe3, 0x8b, 0xf2, 0xec,
-	0x16, 0x30, 0x08, 0xd9, 0x22, 0x4f, 0x28, 0xd1, 0x35, 0xa3, 0x6f, 0xc0, 0x8a, 0xb3, 0x5f, 0x29,
-	0x6e, 0x3d, 0x6b, 0xed, 0xf5, 0xf7, 0x3f, 0x1b, 0x97, 0x85, 0x8f, 0x4d, 0xe1, 0xe3, 0x99, 0x9f,
-	0x16, 0xaf, 0xf7, 0x2f, 0xa2, 0x64, 0x49, 0x89, 0x62, 0xca, 0x0c, 0x96, 0xde, 0x64, 0x78, 0xe3,
-	0x7f, 0x32, 0xc2, 0x82, 0xb3, 0x74, 0xae, 0x33, 0x24, 0xd3, 0xfd, 0xcb, 0x02, 0x7b, 0x9d, 0x0f,
-	0x22, 0x17, 0x3a, 0xe5, 0x08, 0xf1, 0xa6, 0xca, 0x81, 0xf1, 0xed, 0xab, 0xf1, 0x44, 0x45, 0x88,
-	0x46, 0xd0, 0x0b, 0xe8, 0xa5, 0xd1, 0x82, 0x8a, 0x3c, 0x8a, 0x29, 0xb6, 0x14, 0x6d, 0x5b, 0xd2,
-	0x02, 0x13, 0x24, 0x35, 0x8e, 0xbe, 0x80, 0xae, 0xfe, 0x62, 0xb8, 0xad, 0xa8, 0x7d, 0x49, 0x0d,
-	0xcb, 0x10, 0x31, 0x18, 0xda, 0x03, 0x9b, 0xa5, 0xa2, 0x88, 0xd2, 0x98, 0xe2, 0x8e, 0xe2, 0x6d,
-	0x49, 0x9e, 0xaf, 0x63, 0xa4, 0x42, 0xe5, 0x85, 0xfa, 0x9b, 0xe3, 0x6e, 0x7d, 0x21, 0x29, 0x43,
-	0xc4, 0x60, 0xe8, 0x39, 0xb4, 0xa3, 0x84, 0x45, 0x02, 0xdb, 0x8a, 0xe4, 0x34, 0x5e, 0x3d, 0x90,
-	0x71, 0x52, 0xc2, 0xe8, 0x39, 0xf4, 0xa4, 0x4a, 0xce, 0xa4, 0x4a, 0x70, 0x4f, 0x71, 0x6d, 0x75,
-	0xe1, 0x32, 0xa1, 0xa4, 0x86, 0xd0, 0xf7, 0x30, 0xd0, 0xe2, 0x39, 0x2c, 0xc5, 0x83, 0x41, 0x91,
-	0x91, 0x1a, 0xd0, 0x3d, 0x84, 0x3c, 0x60, 0xa2, 0xef, 0x60, 0xbb, 0x94, 0x18, 0x29, 0x25, 0x86,
-	0xfb, 0x2a, 0xf5, 0x89, 0x4a, 0x6d, 0x02, 0xe4, 0x3e, 0x4f, 0x4e, 0xc5, 0xc8, 0x10, 0x0f, 0xeb,
-	0xa9, 0xfc, 0xa4, 0x63, 0xa4, 0x42, 0x4f, 0x2c, 0x7b, 0xcb, 0x19, 0x9e, 0x58, 0xb6, 0xe3, 0xec,
-	0xb8, 0xff, 0xb4, 0x00, 0x1d, 0x46, 0x45, 0xfc, 0xee, 0x92, 0xb3, 0x62, 0xad, 0x7a, 0x95, 0x19,
-	0x82, 0xfd, 0x41, 0xb5, 0x78, 0x3e, 0xf2, 0x86, 0x64, 0xa2, 0x11, 0xf4, 0xcc, 0x16, 0x10, 0xd8,
-	0x7a, 0xb6, 0x69, 0x7a, 0x34, 0x65, 0x93, 0x1a, 0x76, 0xff, 0xb5, 0x74, 0x63, 0x3f, 0x2f, 0x29,
-	0xbf, 0x5b, 0x6b, 0x63, 0xdf, 0x42, 0x27, 0x5a, 0x64, 0xcb, 0xca, 0x17, 0xab, 0x5f, 0xd1, 0xdc,
-	0x6a, 0x1c, 0xd6, 0xa3, 0xc7, 0xf1, 0x35, 0x40, 0xe5, 0x1d, 0x81, 0xdb, 0x6a, 0x1e, 0x0f, 0xcc,
-	0xd5, 0x20, 0xa0, 0x2f, 0xc1, 0xd6, 0x0e, 0x12, 0xb8, 0xa3, 0xc8, 0xf7, 0xec, 0x55, 0x81, 0x72,
-	0xcc, 0xc6, 0x41, 0x02, 0x77, 0xeb, 0x31, 0x57, 0x06, 0xab, 0x61, 0x79, 0xa9, 0x76, 0x91, 0x74,
-	0xcf, 0xe6, 0x43, 0x8b, 0x55, 0x20, 0x1a, 0x41, 0x57, 0x99, 0x88, 0x0a, 0xdc, 0x53, 0xbc, 0xf7,
-	0x5d, 0x66, 0x08, 0x68, 0x0f, 0xa0, 0x32, 0x93, 0xc0, 0xa0, 0xe8, 0xb5, 0xd1, 0x1a, 0x18, 0xf2,
-	0x00, 0x95, 0x2e, 0xb8, 0x64, 0xc5, 0xbb, 0xd0, 0x74, 0xd7, 0x57, 0x19, 0x9f, 0xd6, 0x96, 0x69,
-	0xa0, 0xe4, 0x03, 0x09, 0xb2, 0x63, 0xe3, 0x0e, 0x81, 0x87, 0x75, 0xc7, 0x95, 0x79, 0x6a, 0xb8,
-	0xe1, 0x9e, 0x27, 0xee, 0x9f, 0x6d, 0x70, 0x8e, 0x98, 0x88, 0xb3, 0x5b, 0xca, 0xd7, 0x2a, 0xb1,
-	0x1f, 0xc0, 0x2a, 0xee, 0xf2, 0xd2, 0x3b, 0x83, 0xfd, 0xaf, 0x64, 0xad, 0x0f, 0xeb, 0x78, 0x2f,
-	0x30, 0xbd, 0xcb, 0x29, 0x51, 0x69, 0xcd, 0x45, 0x6b, 0xad, 0x58, 0xb4, 0xf7, 0x84, 0xd0, 0x5e,
-	0x2d, 0x84, 0xc6, 0xaa, 0xed, 0xac, 0x58, 0xb5, 0x2f, 0x9a, 0x2b, 0xb4, 0x5b, 0xff, 0x1e, 0x10,
-	0x13, 0x5c, 0xbd, 0x47, 0xed, 0x47, 0xef, 0xd1, 0xa6, 0xda, 0x7b, 0x2b, 0xd4, 0xee, 0xfe, 0xdd,
-	0x82, 0xa7, 0x1f, 0x1a, 0x15, 0xea, 0x43, 0x77, 0x16, 0x9c, 0x06, 0xe7, 0x97, 0x81, 0xf3, 0x09,
-	0xda, 0x02, 0xdb, 0x0f, 0xc2, 0xe9, 0x41, 0x30, 0xf1, 0x9c, 0x96, 0x84, 0x26, 0x67, 0xb3, 0x70,
-	0xea, 0x11, 0x67, 0x43, 0x1e, 0xc8, 0xf9, 0x6c, 0xea, 0x07, 0x6f, 0x9c, 0x4d, 0x34, 0x00, 0x20,
-	0x07, 0x53, 0xef, 0xea, 0xcc, 0x7f, 0xeb, 0x4f, 0x1d, 0x0b, 0xed, 0xc0, 0x70, 0xe2, 0x93, 0xc9,
-	0xcc, 0x9f, 0x5e, 0x1d, 0x12, 0xef, 0xe0, 0xd4, 0x23, 0x4e, 0x5b, 0x5e, 0x16, 0x7a, 0xe4, 0xc2,
-	0x9f, 0x78, 0xa1, 0xd3, 0x71, 0x2d, 0xbb, 0xeb, 0xf4, 0x47, 0xd6, 0x5b, 0x2f, 0xfc, 0x71, 0xd4,
-	0x97, 0x7f, 0xaf, 0x26, 0xe7, 0xc1, 0xb1, 0xff, 0x66, 0x34, 0x38, 0x3e, 0x9b, 0xfd, 0x72, 0x75,
-	0x74, 0x48, 0xbc, 0x63, 0x22, 0x41, 0x5b, 0x9d, 0xc3, 0xa3, 0xd3, 0x51, 0xbf, 0xfc, 0xcf, 0x23,
-	0x17, 0x1e, 0x39, 0xb1, 0x6c, 0x70, 0x06, 0xd7, 0x1d, 0xa5, 0x96, 0xd7, 0xff, 0x05, 0x00, 0x00,
-	0xff, 0xff, 0x42, 0xc3, 0xf9, 0x58, 0x28, 0x09, 0x00, 0x00,
+func init() { proto.RegisterFile("response.proto", fileDescriptor_response_d0089aaa65b56e5a) }
+
+var fileDescriptor_response_d0089aaa65b56e5a = []byte{
+	// 1021 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x55, 0x51, 0x4f, 0xe3, 0x46,
+	0x17, 0xfd, 0x82, 0x9d, 0xc4, 0xb9, 0x81, 0xc4, 0x4c, 0xd8, 0x4f, 0x23, 0xb4, 0x5a, 0x45, 0x91,
+	0xba, 0x65, 0xb3, 0x6a, 0xb6, 0xcb, 0x56, 0xad, 0x54, 0xa9, 0xaa, 0x20, 0x18, 0x36, 0xc0, 0x86,
+	0x76, 0x92, 0x40, 0xdf, 0x90, 0x09, 0x43, 0x18, 0xd5, 0xb1, 0xad, 0x19, 0x3b, 0x15, 0x95, 0xfa,
+	0xde, 0x5f, 0xd5, 0x9f, 0xd1, 0xa7, 0xfe, 0x94, 0x3e, 0x54, 0x33, 0xf6, 0xd8, 0x0e, 0xb0, 0x11,
+	0x4f, 0xbc, 0x40, 0xe6, 0x9c, 0x73, 0x67, 0x7c, 0x67, 0xee, 0xb9, 0x17, 0x1a, 0x9c, 0x8a, 0x30,
+	0xf0, 0x05, 0xed, 0x85, 0x3c, 0x88, 0x02, 0xb4, 0xb6, 0x78, 0xbf, 0xfd, 0x6a, 0x16, 0x04, 0x33,
+	0x8f, 0xbe, 0x53, 0xc8, 0x55, 0x7c, 0xf3, 0xee, 0x37, 0xee, 0x86, 0x21, 0xe5, 0x22, 0xd1, 0x6c,
+	0x6f, 0x08, 0xca, 0x17, 0x6c, 0x4a, 0xf5, 0x92, 0x07, 0x71, 0xc4, 0xfc, 0x59, 0xba, 0x5c, 0x9f,
+	0x7a, 0x8c, 0xfa, 0x51, 0xba, 0x6a, 0x72, 0x37, 0xa2, 0x1e, 0x9b, 0x33, 0x0d, 0x6c, 0x4d, 0x19,
+	0x9f, 0xc6, 0x2c, 0xba, 0xe2, 0xd4, 0xfd, 0x95, 0xf2, 0x14, 0x6d, 0x4d, 0x03, 0xff, 0x86, 0xcd,
+	0x38, 0xf5, 0xa8, 0xab, 0xbf, 0x65, 0xbb, 0x11, 0x7a, 0x6e, 0x74, 0x13, 0xf0, 0xb9, 0x3e, 0xe8,
+	0x96, 0x89, 0x28, 0xe0, 0x77, 0xe9, 0x12, 0xdc, 0x38, 0xba, 0x4d, 0x7e, 0x77, 0x22, 0x68, 0x8c,
+	0xd8, 0x3c, 0xf4, 0x28, 0x49, 0xd3, 0x41, 0x5f, 0x83, 0x39, 0x0d, 0xae, 0x29, 0x2e, 0xb5, 0x4b,
+	0x3b, 0xf5, 0xdd, 0x97, 0xbd, 0x24, 0xa7, 0x9e, 0xce, 0xa9, 0x37, 0x19, 0xf8, 0xd1, 0x87, 0xdd,
+	0x73, 0xd7, 0x8b, 0x29, 0x51, 0x4a, 0x19, 0xc1, 0xfc, 0x9b, 0x00, 0xaf, 0x7d, 0x26, 0x62, 0x14,
+	0x71, 0xe6, 0xcf, 0xd2, 0x08, 0xa9, 0xec, 0xfc, 0x01, 0xcd, 0x73, 0xd7, 0x63, 0xd7, 0x6e, 0xc4,
+	0x02, 0xdf, 0xe1, 0x3c, 0xe0, 0x68, 0x17, 0xca, 0x37, 0x8c, 0x7a, 0xd7, 0xb8, 0xf4, 0x84, 0x5d,
+	0x12, 0x29, 0xfa, 0x16, 0xaa, 0x73, 0x2a, 0x84, 0x3b, 0xa3, 0x4f, 0x3a, 0x5b, 0x8b, 0x3b, 0xff,
+	0x94, 0xc1, 0x7a, 0xce, 0x7c, 0x51, 0x07, 0x2a, 0xc9, 0xe3, 0x62, 0x43, 0xc5, 0x40, 0x6f, 0xf1,
+	0xbe, 0xd7, 0x57, 0x08, 0x49, 0x19, 0xf4, 0x16, 0x6a, 0xbe, 0x3b, 0xa7, 0x22, 0x74, 0xa7, 0x14,
+	0x9b, 0x4a, 0xb6, 0x21, 0x65, 0x43, 0x0d, 0x92, 0x9c, 0x47, 0x5f, 0x40, 0x35, 0xad, 0x25, 0x5c,
+	0x56, 0xd2, 0xba, 0x94, 0x8e, 0x12, 0x88, 0x68, 0x0e, 0xed, 0x80, 0xc5, 0x7c, 0x11, 0xb9, 0xfe,
+	0x94, 0xe2, 0x8a, 0xd2, 0xad, 0x4b, 0xdd, 0x20, 0xc5, 0x48, 0xc6, 0xca, 0x0d, 0xd3, 0x6a, 0xc4,
+	0xd5, 0x7c, 0x43, 0x92, 0x40, 0x44, 0x73, 0xe8, 0x35, 0x94, 0x5d, 0x8f, 0xb9, 0x02, 0x5b, 0x4a,
+	0x64, 0x17, 0x4e, 0xdd, 0x93, 0x38, 0x49, 0x68, 0xf4, 0x1a, 0x6a, 0xb2, 0x7e, 0x4f, 0x65, 0xfd,
+	0xe2, 0x9a, 0xd2, 0x5a, 0x6a, 0xc3, 0xd8, 0xa3, 0x24, 0xa7, 0xd0, 0xf7, 0xd0, 0x48, 0xcb, 0x7a,
+	0x3f, 0x29, 0x6b, 0x0c, 0x4a, 0x8c, 0xd4, 0x05, 0x2d, 0x31, 0xe4, 0x9e, 0x12, 0x7d, 0x07, 0x1b,
+	0x49, 0xf1, 0x93, 0xa4, 0xf8, 0x71, 0x5d, 0x85, 0x6e, 0xaa, 0xd0, 0x22, 0x41, 0x96, 0x75, 0xf2,
+	0x56, 0xb4, 0x41, 0x70, 0x33, 0xbf, 0x95, 0x9f, 0x52, 0x8c, 0x64, 0x2c, 0x7a, 0x09, 0x66, 0x2c,
+	0x28, 0xc7, 0xad, 0x3c, 0x83, 0x89, 0xa0, 0x9c, 0x28, 0x54, 0xbe, 0x98, 0xfc, 0x7f, 0xc4, 0x83,
+	0x38, 0xc4, 0x5b, 0xf9, 0x8b, 0x4d, 0x34, 0x48, 0x72, 0x5e, 0x6e, 0xc5, 0x03, 0x8f, 0xe2, 0x17,
+	0x85, 0xcb, 0x08, 0x3c, 0x4a, 0x14, 0x8a, 0x7e, 0x04, 0x7b, 0xb1, 0x6c, 0x08, 0x81, 0xff, 0xdf,
+	0x36, 0x76, 0xea, 0xbb, 0x2d, 0xa9, 0xbc, 0x67, 0x16, 0xf2, 0x40, 0x7c, 0x6c, 0x5a, 0xeb, 0x76,
+	0xf3, 0xd8, 0xb4, 0x6c, 0xbb, 0xd5, 0xf9, 0xbb, 0x04, 0x68, 0xdf, 0x8d, 0xa6, 0xb7, 0x17, 0x9c,
+	0x45, 0xcf, 0x6a, 0x6c, 0x19, 0x21, 0xd8, 0xef, 0x14, 0x1b, 0x9f, 0x89, 0x58, 0x3a, 0x43, 0x2a,
+	0x51, 0x17, 0x6a, 0xba, 0x93, 0x0a, 0x6c, 0xb6, 0x0d, 0xfd, 0x1a, 0xfa, 0xb3, 0x49, 0x4e, 0x77,
+	0xfe, 0x2d, 0xa7, 0x89, 0xfd, 0x1c, 0x53, 0x7e, 0xf7, 0xac, 0x89, 0x7d, 0x03, 0x15, 0x77, 0x1e,
+	0xc4, 0x99, 0x83, 0x57, 0x9f, 0x92, 0x6a, 0xb3, 0xeb, 0x30, 0x9f, 0x7c, 0x1d, 0x5f, 0x01, 0x64,
+	0x2e, 0x17, 0xb8, 0xdc, 0x36, 0x74, 0x51, 0xe5, 0x6d, 0xa0, 0x20, 0x40, 0x5f, 0x82, 0x95, 0x7a,
+	0x5d, 0xe0, 0x4a, 0xdb, 0xd0, 0xbe, 0xd5, 0x8d, 0x20, 0x23, 0xe5, 0x35, 0x6b, 0xaf, 0x0b, 0x5c,
+	0x6d, 0x1b, 0x0f, 0x5a, 0x41, 0x4e, 0xcb, 0x4d, 0x53, 0xbf, 0x4b, 0x9f, 0x1b, 0xf7, 0x9b, 0x41,
+	0x46, 0xa2, 0x2e, 0x54, 0x95, 0xdd, 0xa9, 0xc0, 0xb5, 0xb6, 0xf1, 0x68, 0x3f, 0xd0, 0x02, 0xb4,
+	0x03, 0x90, 0xd9, 0x5e, 0x60, 0x68, 0x1b, 0x99, 0x0b, 0x64, 0x4b, 0x28, 0x70, 0xc8, 0x01, 0x94,
+	0xf8, 0xf5, 0x82, 0x45, 0xb7, 0x23, 0x9d, 0x5d, 0x5d, 0x45, 0xbc, 0xc8, 0xcd, 0x5d, 0x60, 0xc9,
+	0x23, 0x01, 0x32, 0x63, 0xed, 0x63, 0x81, 0x9b, 0x6d, 0xe3, 0x81, 0xcd, 0x73, 0x1a, 0xbd, 0x81,
+	0x5a, 0x32, 0x22, 0x19, 0x15, 0x78, 0x33, 0x4f, 0xf9, 0x63, 0x32, 0x37, 0x49, 0xce, 0xa2, 0x57,
+	0x50, 0x96, 0xa6, 0x16, 0x18, 0xe5, 0x29, 0xa8, 0x9e, 0x90, 0xc0, 0xf2, 0x01, 0x33, 0xd3, 0x0b,
+	0xdc, 0xca, 0x1f, 0x30, 0xef, 0x0a, 0x05, 0x81, 0xdc, 0x4e, 0x36, 0x00, 0x81, 0xb7, 0xda, 0xc6,
+	0x52, 0x5f, 0x48, 0xe0, 0x82, 0xaf, 0x37, 0x3b, 0x7f, 0x96, 0xc1, 0x3e, 0x60, 0x62, 0x1a, 0x2c,
+	0x28, 0x7f, 0xd6, 0xe2, 0xff, 0x01, 0xcc, 0xe8, 0x2e, 0x4c, 0x5c, 0xdd, 0xd8, 0x7d, 0x23, 0xbf,
+	0xf1, 0xfe, 0x77, 0x3c, 0x00, 0xc6, 0x77, 0x21, 0x25, 0x2a, 0xac, 0x38, 0xac, 0xcc, 0x15, 0xc3,
+	0x6a, 0xa9, 0x44, 0xcb, 0xab, 0x4b, 0xb4, 0x30, 0xae, 0x2a, 0x2b, 0xc6, 0xd5, 0xdb, 0xe2, 0x18,
+	0xaa, 0xe6, 0x1d, 0x9a, 0x68, 0x70, 0xf5, 0x2c, 0xb2, 0x9e, 0x3c, 0x8b, 0x8a, 0x3e, 0xac, 0xad,
+	0xf0, 0x61, 0xe7, 0xaf, 0x12, 0x6c, 0x3d, 0x76, 0x55, 0xa8, 0x0e, 0xd5, 0xc9, 0xf0, 0x64, 0x78,
+	0x76, 0x31, 0xb4, 0xff, 0x87, 0xd6, 0xc1, 0x1a, 0x0c, 0x47, 0xe3, 0xbd, 0x61, 0xdf, 0xb1, 0x4b,
+	0x92, 0xea, 0x9f, 0x4e, 0x46, 0x63, 0x87, 0xd8, 0x6b, 0x72, 0x41, 0xce, 0x26, 0xe3, 0xc1, 0xf0,
+	0xc8, 0x36, 0x50, 0x03, 0x80, 0xec, 0x8d, 0x9d, 0xcb, 0xd3, 0xc1, 0xa7, 0xc1, 0xd8, 0x36, 0x51,
+	0x0b, 0x9a, 0xfd, 0x01, 0xe9, 0x4f, 0x06, 0xe3, 0xcb, 0x7d, 0xe2, 0xec, 0x9d, 0x38, 0xc4, 0x2e,
+	0xcb, 0xcd, 0x46, 0x0e, 0x39, 0x1f, 0xf4, 0x9d, 0x91, 0x5d, 0xe9, 0x98, 0x56, 0xd5, 0xae, 0x77,
+	0xcd, 0x4f, 0xce, 0xe8, 0x63, 0xb7, 0x2e, 0xff, 0x5e, 0xf6, 0xcf, 0x86, 0x87, 0x83, 0xa3, 0x6e,
+	0xe3, 0xf0, 0x74, 0xf2, 0xcb, 0xe5, 0xc1, 0x3e, 0x71, 0x0e, 0x89, 0x24, 0x2d, 0xb5, 0x1e, 0x1d,
+	0x9c, 0x74, 0xeb, 0xc9, 0x2f, 0x87, 0x9c, 0x3b, 0xe4, 0xd8, 0xb4, 0xc0, 0x6e, 0x5c, 0x55, 0x54,
+	0xb5, 0x7c, 0xf8, 0x6f, 0x00, 0xc0, 0x8b, 0xe2, 0xa5, 0x06, 0x0b, 0x00, 0x00,
 }
@@ -47,15 +47,21 @@ type HealthCheck_HealthCheckType int32
 const (
 	HealthCheck_UNKNOWN   HealthCheck_HealthCheckType = 0
 	HealthCheck_HEARTBEAT HealthCheck_HealthCheckType = 1
+	HealthCheck_TCP       HealthCheck_HealthCheckType = 2
+	HealthCheck_HTTP      HealthCheck_HealthCheckType = 3
 )
 
 var HealthCheck_HealthCheckType_name = map[int32]string{
 	0: "UNKNOWN",
 	1: "HEARTBEAT",
+	2: "TCP",
+	3: "HTTP",
 }
 var HealthCheck_HealthCheckType_value = map[string]int32{
 	"UNKNOWN":   0,
 	"HEARTBEAT": 1,
+	"TCP":       2,
+	"HTTP":      3,
 }
 
 func (x HealthCheck_HealthCheckType) String() string {
@@ -604,6 +610,8 @@ func (m *Instance) GetServiceToken() *wrappers.StringValue {
 type HealthCheck struct {
 	Type                 HealthCheck_HealthCheckType `protobuf:"varint,1,opt,name=type,proto3,enum=v1.HealthCheck_HealthCheckType" json:"type,omitempty"`
 	Heartbeat            *HeartbeatHealthCheck       `protobuf:"bytes,2,opt,name=heartbeat,proto3" json:"heartbeat,omitempty"`
+	Tcp                  *TcpHealthCheck             `protobuf:"bytes,3,opt,name=tcp,proto3" json:"tcp,omitempty"`
+	Http                 *HttpHealthCheck            `protobuf:"bytes,4,opt,name=http,proto3" json:"http,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
 	XXX_unrecognized     []byte                      `json:"-"`
 	XXX_sizecache        int32                       `json:"-"`
@@ -647,6 +655,20 @@ func (m *HealthCheck) GetHeartbeat() *HeartbeatHealthCheck {
 	return nil
 }
 
+func (m *HealthCheck) GetTcp() *TcpHealthCheck {
+	if m != nil {
+		return m.Tcp
+	}
+	return nil
+}
+
+func (m *HealthCheck) GetHttp() *HttpHealthCheck {
+	if m != nil {
+		return m.Http
+	}
+	return nil
+}
+
 type HeartbeatHealthCheck struct {
 	Ttl                  *wrappers.UInt32Value `protobuf:"bytes,1,opt,name=ttl,proto3" json:"ttl,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
@@ -685,6 +707,114 @@ func (m *HeartbeatHealthCheck) GetTtl() *wrappers.UInt32Value {
 	return nil
 }
 
+type TcpHealthCheck struct {
+	Interval             *wrappers.UInt32Value `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"`
+	Timeout              *wrappers.UInt32Value `protobuf:"bytes,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *TcpHealthCheck) Reset()         { *m = TcpHealthCheck{} }
+func (m *TcpHealthCheck) String() string { return proto.CompactTextString(m) }
+func (*TcpHealthCheck) ProtoMessage()    {}
+func (*TcpHealthCheck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_413f75d8eac84e7c, []int{6}
+}
+func (m *TcpHealthCheck) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TcpHealthCheck.Unmarshal(m, b)
+}
+func (m *TcpHealthCheck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TcpHealthCheck.Marshal(b, m, deterministic)
+}
+func (dst *TcpHealthCheck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TcpHealthCheck.Merge(dst, src)
+}
+func (m *TcpHealthCheck) XXX_Size() int {
+	return xxx_messageInfo_TcpHealthCheck.Size(m)
+}
+func (m *TcpHealthCheck) XXX_DiscardUnknown() {
+	xxx_messageInfo_TcpHealthCheck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TcpHealthCheck proto.InternalMessageInfo
+
+func (m *TcpHealthCheck) GetInterval() *wrappers.UInt32Value {
+	if m != nil {
+		return m.Interval
+	}
+	return nil
+}
+
+func (m *TcpHealthCheck) GetTimeout() *wrappers.UInt32Value {
+	if m != nil {
+		return m.Timeout
+	}
+	return nil
+}
+
+type HttpHealthCheck struct {
+	Interval             *wrappers.UInt32Value `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"`
+	Timeout              *wrappers.UInt32Value `protobuf:"bytes,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Path                 *wrappers.StringValue `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	ExpectedStatus       *wrappers.UInt32Value `protobuf:"bytes,4,opt,name=expected_status,proto3" json:"expected_status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *HttpHealthCheck) Reset()         { *m = HttpHealthCheck{} }
+func (m *HttpHealthCheck) String() string { return proto.CompactTextString(m) }
+func (*HttpHealthCheck) ProtoMessage()    {}
+func (*HttpHealthCheck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_413f75d8eac84e7c, []int{7}
+}
+func (m *HttpHealthCheck) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HttpHealthCheck.Unmarshal(m, b)
+}
+func (m *HttpHealthCheck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HttpHealthCheck.Marshal(b, m, deterministic)
+}
+func (dst *HttpHealthCheck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HttpHealthCheck.Merge(dst, src)
+}
+func (m *HttpHealthCheck) XXX_Size() int {
+	return xxx_messageInfo_HttpHealthCheck.Size(m)
+}
+func (m *HttpHealthCheck) XXX_DiscardUnknown() {
+	xxx_messageInfo_HttpHealthCheck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HttpHealthCheck proto.InternalMessageInfo
+
+func (m *HttpHealthCheck) GetInterval() *wrappers.UInt32Value {
+	if m != nil {
+		return m.Interval
+	}
+	return nil
+}
+
+func (m *HttpHealthCheck) GetTimeout() *wrappers.UInt32Value {
+	if m != nil {
+		return m.Timeout
+	}
+	return nil
+}
+
+func (m *HttpHealthCheck) GetPath() *wrappers.StringValue {
+	if m != nil {
+		return m.Path
+	}
+	return nil
+}
+
+func (m *HttpHealthCheck) GetExpectedStatus() *wrappers.UInt32Value {
+	if m != nil {
+		return m.ExpectedStatus
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Namespace)(nil), "v1.Namespace")
 	proto.RegisterType((*Service)(nil), "v1.Service")
@@ -694,6 +824,8 @@ func init() {
 	proto.RegisterMapType((map[string]string)(nil), "v1.Instance.MetadataEntry")
 	proto.RegisterType((*HealthCheck)(nil), "v1.HealthCheck")
 	proto.RegisterType((*HeartbeatHealthCheck)(nil), "v1.HeartbeatHealthCheck")
+	proto.RegisterType((*TcpHealthCheck)(nil), "v1.TcpHealthCheck")
+	proto.RegisterType((*HttpHealthCheck)(nil), "v1.HttpHealthCheck")
 	proto.RegisterEnum("v1.AliasType", AliasType_name, AliasType_value)
 	proto.RegisterEnum("v1.HealthCheck_HealthCheckType", HealthCheck_HealthCheckType_name, HealthCheck_HealthCheckType_value)
 }
@@ -701,61 +833,68 @@ func init() {
 func init() { proto.RegisterFile("service.proto", fileDescriptor_service_413f75d8eac84e7c) }
 
 var fileDescriptor_service_413f75d8eac84e7c = []byte{
-	// 892 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0xd1, 0x6e, 0xdb, 0x36,
-	0x14, 0xad, 0xed, 0xc4, 0xb6, 0xae, 0xec, 0xc4, 0x65, 0x33, 0x40, 0x33, 0x86, 0xad, 0x33, 0xf6,
-	0x10, 0x0c, 0x9b, 0xba, 0xc8, 0x69, 0x50, 0x64, 0xc3, 0x00, 0xa7, 0x75, 0xd1, 0x6c, 0x99, 0x07,
-	0x28, 0xce, 0xf6, 0x68, 0xd0, 0x12, 0x6b, 0x0b, 0x91, 0x44, 0x41, 0x62, 0x1c, 0xf8, 0x93, 0xf6,
-	0x25, 0x7b, 0xdc, 0xd3, 0xbe, 0x64, 0x3f, 0x30, 0x90, 0x94, 0x64, 0xa6, 0xad, 0x0b, 0x5a, 0x7d,
-	0xb3, 0xa4, 0x73, 0xae, 0xe4, 0x7b, 0xcf, 0xb9, 0x87, 0xd0, 0xcd, 0x48, 0xba, 0x0a, 0x3c, 0x62,
-	0x27, 0x29, 0x65, 0x14, 0xd5, 0x57, 0x27, 0xfd, 0x2f, 0x17, 0x94, 0x2e, 0x42, 0xf2, 0x4c, 0xdc,
-	0x99, 0xdf, 0xbd, 0x7d, 0x76, 0x9f, 0xe2, 0x24, 0x21, 0x69, 0x26, 0x31, 0x7d, 0x33, 0xa2, 0x3e,
-	0x09, 0xe5, 0xc5, 0xe0, 0xef, 0x3a, 0x18, 0x13, 0x1c, 0x91, 0x2c, 0xc1, 0x1e, 0x41, 0x3f, 0xc0,
-	0x5e, 0x8c, 0x23, 0x62, 0xd5, 0x9e, 0xd6, 0x8e, 0x4d, 0xe7, 0x0b, 0x5b, 0x56, 0xb2, 0x8b, 0x4a,
-	0xf6, 0x35, 0x4b, 0x83, 0x78, 0xf1, 0x07, 0x0e, 0xef, 0x88, 0x2b, 0x90, 0xe8, 0x0c, 0x5a, 0x1e,
-	0x8d, 0x22, 0x12, 0x33, 0xab, 0xae, 0x41, 0x2a, 0xc0, 0xe8, 0x14, 0x9a, 0xf4, 0x3e, 0x26, 0x69,
-	0x66, 0x35, 0x34, 0x68, 0x39, 0x16, 0x39, 0xb0, 0xcf, 0xe8, 0x2d, 0x89, 0xad, 0x3d, 0x0d, 0x92,
-	0x84, 0x72, 0x8e, 0xc7, 0x82, 0x88, 0x58, 0xfb, 0x3a, 0x1c, 0x01, 0xe5, 0x9c, 0x48, 0x70, 0x9a,
-	0x3a, 0x1c, 0x01, 0x1d, 0xfc, 0xd3, 0x82, 0xd6, 0xb5, 0x1c, 0x46, 0x85, 0x3e, 0x9e, 0x83, 0x11,
-	0x17, 0x63, 0xd0, 0xea, 0xe4, 0x06, 0x8e, 0x9e, 0x43, 0x3b, 0x22, 0x0c, 0xfb, 0x98, 0x61, 0xab,
-	0xf1, 0xb4, 0x71, 0x6c, 0x3a, 0x9f, 0xdb, 0xab, 0x13, 0x3b, 0xff, 0x18, 0xfb, 0xb7, 0xfc, 0xd9,
-	0x38, 0x66, 0xe9, 0xda, 0x2d, 0xa1, 0xfc, 0x4f, 0x26, 0x34, 0x65, 0x99, 0x5e, 0x33, 0x05, 0x14,
-	0xbd, 0x80, 0xf6, 0xfc, 0x2e, 0x0b, 0x62, 0x92, 0x65, 0x5a, 0xfd, 0x2c, 0xd1, 0xe8, 0x27, 0x00,
-	0x9f, 0x24, 0x38, 0x65, 0x42, 0x2b, 0x3a, 0x7d, 0x55, 0xf0, 0xbc, 0x3d, 0x5e, 0xe4, 0xcf, 0x67,
-	0x11, 0xf5, 0x4f, 0xac, 0x96, 0x4e, 0x7b, 0x4a, 0xb8, 0xca, 0x75, 0xac, 0xf6, 0x2e, 0x5c, 0x47,
-	0xe5, 0x0e, 0x2d, 0x63, 0x17, 0xee, 0x50, 0xb5, 0x06, 0x54, 0xb3, 0x86, 0x59, 0xc5, 0x1a, 0x9d,
-	0x0a, 0xd6, 0xe8, 0x56, 0xb0, 0xc6, 0x81, 0xb6, 0x35, 0xb8, 0x6a, 0x52, 0xb2, 0x0a, 0xb2, 0x80,
-	0xc6, 0xd6, 0xa1, 0x8e, 0x6a, 0x0a, 0x34, 0xfa, 0x19, 0xcc, 0x24, 0xc4, 0xec, 0x2d, 0x4d, 0xa3,
-	0x59, 0xe0, 0x5b, 0x3d, 0x0d, 0xb2, 0x4a, 0xe8, 0xff, 0x08, 0xdd, 0x07, 0xf2, 0x47, 0x3d, 0x68,
-	0xdc, 0x92, 0xb5, 0x30, 0xa6, 0xe1, 0xf2, 0x9f, 0xe8, 0x08, 0xf6, 0x57, 0x9c, 0x28, 0x5c, 0x67,
-	0xb8, 0xf2, 0xe2, 0xbc, 0xfe, 0xa2, 0x36, 0xf8, 0xaf, 0x01, 0x9d, 0xdc, 0x44, 0xa3, 0x30, 0xc0,
-	0x19, 0x9f, 0x68, 0xbe, 0x6e, 0xb5, 0x9c, 0x5d, 0x80, 0x3f, 0xc9, 0xdc, 0x0e, 0xec, 0x63, 0xfe,
-	0x72, 0xad, 0x3d, 0x29, 0xa1, 0xe8, 0x6b, 0xd8, 0x63, 0xeb, 0x84, 0x08, 0x63, 0x1f, 0x38, 0x5d,
-	0xbe, 0x0c, 0xc4, 0x1f, 0x98, 0xae, 0x13, 0xe2, 0x8a, 0x47, 0x8a, 0xc8, 0xda, 0x3b, 0x88, 0x4c,
-	0x91, 0xb4, 0xb1, 0x8b, 0xa4, 0x2f, 0xca, 0x9c, 0x9a, 0x49, 0x91, 0xea, 0xec, 0x8e, 0x87, 0x94,
-	0x8d, 0x58, 0x9b, 0x15, 0xc4, 0xda, 0xd2, 0xdf, 0xe3, 0xff, 0x1a, 0xd0, 0xbe, 0x8c, 0x33, 0x86,
-	0x63, 0x8f, 0xa0, 0xef, 0xa0, 0x1e, 0xf8, 0x5a, 0xc3, 0xae, 0x07, 0xbe, 0xaa, 0x8f, 0x7a, 0x65,
-	0x7d, 0x34, 0x76, 0xd3, 0xc7, 0x29, 0x34, 0x57, 0x89, 0xc7, 0xcd, 0xf1, 0x99, 0xce, 0x20, 0x25,
-	0x96, 0x07, 0xd4, 0x92, 0x66, 0x4c, 0x6b, 0xf5, 0x0b, 0x24, 0x67, 0xf0, 0x08, 0xd8, 0x3a, 0xb9,
-	0x9b, 0xcb, 0x98, 0x0d, 0x9d, 0x9c, 0xc1, 0x91, 0xdc, 0xf5, 0xe2, 0xa9, 0x47, 0x43, 0xad, 0x99,
-	0x95, 0x68, 0xde, 0xc7, 0x15, 0x49, 0xc5, 0xba, 0xd0, 0x19, 0x5c, 0x01, 0x96, 0x6f, 0x0c, 0x68,
-	0x1a, 0xb0, 0xf5, 0x56, 0x59, 0xab, 0xdf, 0x59, 0xa2, 0x79, 0x17, 0xef, 0x49, 0xb0, 0x58, 0x6e,
-	0xd7, 0xb5, 0xca, 0xcb, 0xb1, 0xe8, 0x17, 0x78, 0x42, 0x62, 0x3c, 0x0f, 0xc9, 0x6c, 0x49, 0x70,
-	0xc8, 0x96, 0x33, 0x6f, 0x49, 0xbc, 0x5b, 0xeb, 0x48, 0x94, 0xe8, 0xbf, 0x57, 0xe2, 0x82, 0xd2,
-	0x50, 0x16, 0x78, 0x2c, 0x69, 0x6f, 0x04, 0xeb, 0x25, 0x27, 0x21, 0x07, 0x3a, 0x0f, 0x8a, 0xc8,
-	0xc8, 0x38, 0xe4, 0xde, 0x55, 0x60, 0xae, 0xb9, 0x54, 0x38, 0xa7, 0xd0, 0x92, 0x97, 0xeb, 0x3c,
-	0x2a, 0x3e, 0xf6, 0xce, 0x02, 0xca, 0x59, 0x41, 0x46, 0x43, 0xcc, 0x48, 0x9e, 0x15, 0x1f, 0x65,
-	0xe5, 0x50, 0x74, 0x0c, 0xed, 0x90, 0x7a, 0x98, 0xf1, 0xa1, 0xc8, 0xb8, 0xe8, 0xf0, 0x6f, 0xbb,
-	0xca, 0xef, 0xb9, 0xe5, 0x53, 0x74, 0xa6, 0x1c, 0x47, 0x0e, 0xc4, 0x71, 0xa4, 0xcf, 0x91, 0x85,
-	0xa7, 0xb6, 0x9e, 0x47, 0xce, 0xc1, 0x08, 0xe9, 0x22, 0xf0, 0x66, 0x19, 0x61, 0x5a, 0x31, 0xb1,
-	0x81, 0x6f, 0x96, 0x43, 0xaf, 0xc2, 0x72, 0x78, 0x5c, 0x2d, 0xc9, 0xd0, 0x4e, 0x49, 0xf6, 0xde,
-	0x0a, 0x7c, 0xb2, 0xf3, 0x0a, 0xfc, 0xb4, 0x34, 0xfb, 0xab, 0x06, 0xa6, 0x2a, 0xb8, 0x61, 0x1e,
-	0x12, 0x35, 0x11, 0x12, 0x5f, 0xbd, 0x23, 0x34, 0xf5, 0xb7, 0x12, 0x1b, 0x67, 0x60, 0x2c, 0x09,
-	0x4e, 0xd9, 0x9c, 0xe0, 0xe2, 0xc0, 0x6f, 0xe5, 0x4c, 0x79, 0x53, 0xd5, 0xea, 0x06, 0x3a, 0xf8,
-	0x1e, 0x0e, 0xdf, 0x29, 0x88, 0x4c, 0x68, 0xdd, 0x4c, 0x7e, 0x9d, 0xfc, 0xfe, 0xe7, 0xa4, 0xf7,
-	0x08, 0x75, 0xc1, 0x78, 0x33, 0x1e, 0xb9, 0xd3, 0x8b, 0xf1, 0x68, 0xda, 0xab, 0x0d, 0x5e, 0xc3,
-	0xd1, 0x87, 0x2a, 0x22, 0x1b, 0x1a, 0x8c, 0x85, 0x5b, 0xf7, 0xb1, 0xea, 0x51, 0x0e, 0xfc, 0xf6,
-	0x1b, 0x30, 0xca, 0xe0, 0xe3, 0x2f, 0x7c, 0x35, 0x7e, 0x3d, 0xba, 0xb9, 0x9a, 0xf6, 0x1e, 0x21,
-	0x80, 0xe6, 0xcb, 0xab, 0xe7, 0xd7, 0x97, 0xaf, 0x7a, 0xb5, 0x79, 0x53, 0x14, 0x18, 0xfe, 0x1f,
-	0x00, 0x00, 0xff, 0xff, 0xc0, 0x18, 0xe5, 0x5a, 0x4c, 0x0d, 0x00, 0x00,
+	// 1008 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x97, 0xcf, 0x6f, 0xe3, 0x44,
+	0x14, 0xc7, 0x37, 0x71, 0x9a, 0xc4, 0x2f, 0x49, 0x93, 0x9d, 0x16, 0xc9, 0x44, 0x08, 0x96, 0x68,
+	0x25, 0x2a, 0x84, 0xb2, 0xd4, 0xe9, 0x56, 0xab, 0x82, 0x10, 0x6d, 0xb7, 0x55, 0x0b, 0xa5, 0xac,
+	0xd2, 0x14, 0x8e, 0xd1, 0xc4, 0x9e, 0x8d, 0xad, 0xda, 0x1e, 0xcb, 0x9e, 0xa4, 0xe4, 0xca, 0x95,
+	0x3f, 0x8c, 0x23, 0x27, 0xfe, 0x12, 0xce, 0x48, 0x68, 0xc6, 0x3f, 0x32, 0xe9, 0x12, 0xed, 0xc4,
+	0x95, 0xf6, 0x16, 0xdb, 0x9f, 0xef, 0x78, 0xfc, 0xde, 0xf7, 0xbd, 0x37, 0x81, 0x56, 0x4c, 0xa2,
+	0xb9, 0x6b, 0x91, 0x7e, 0x18, 0x51, 0x46, 0x51, 0x79, 0xbe, 0xdf, 0xfd, 0x74, 0x4a, 0xe9, 0xd4,
+	0x23, 0x2f, 0xc4, 0x9d, 0xc9, 0xec, 0xed, 0x8b, 0xfb, 0x08, 0x87, 0x21, 0x89, 0xe2, 0x84, 0xe9,
+	0x36, 0x7c, 0x6a, 0x13, 0x2f, 0xb9, 0xe8, 0xfd, 0x59, 0x06, 0xfd, 0x1a, 0xfb, 0x24, 0x0e, 0xb1,
+	0x45, 0xd0, 0xd7, 0x50, 0x09, 0xb0, 0x4f, 0x8c, 0xd2, 0xb3, 0xd2, 0x5e, 0xc3, 0xfc, 0xa4, 0x9f,
+	0xac, 0xd4, 0xcf, 0x56, 0xea, 0xdf, 0xb0, 0xc8, 0x0d, 0xa6, 0xbf, 0x60, 0x6f, 0x46, 0x86, 0x82,
+	0x44, 0x87, 0x50, 0xb3, 0xa8, 0xef, 0x93, 0x80, 0x19, 0x65, 0x05, 0x51, 0x06, 0xa3, 0x03, 0xa8,
+	0xd2, 0xfb, 0x80, 0x44, 0xb1, 0xa1, 0x29, 0xc8, 0x52, 0x16, 0x99, 0xb0, 0xc5, 0xe8, 0x1d, 0x09,
+	0x8c, 0x8a, 0x82, 0x28, 0x41, 0xb9, 0xc6, 0x62, 0xae, 0x4f, 0x8c, 0x2d, 0x15, 0x8d, 0x40, 0xb9,
+	0xc6, 0x17, 0x9a, 0xaa, 0x8a, 0x46, 0xa0, 0xbd, 0xbf, 0x6a, 0x50, 0xbb, 0x49, 0x92, 0x51, 0x20,
+	0x8e, 0x47, 0xa0, 0x07, 0x59, 0x1a, 0x94, 0x22, 0xb9, 0xc4, 0xd1, 0x4b, 0xa8, 0xfb, 0x84, 0x61,
+	0x1b, 0x33, 0x6c, 0x68, 0xcf, 0xb4, 0xbd, 0x86, 0xf9, 0x71, 0x7f, 0xbe, 0xdf, 0x4f, 0x37, 0xd3,
+	0xff, 0x29, 0x7d, 0x76, 0x16, 0xb0, 0x68, 0x31, 0xcc, 0x51, 0xfe, 0x91, 0x21, 0x8d, 0x58, 0xac,
+	0x16, 0x4c, 0x81, 0xa2, 0x57, 0x50, 0x9f, 0xcc, 0x62, 0x37, 0x20, 0x71, 0xac, 0x14, 0xcf, 0x9c,
+	0x46, 0xdf, 0x02, 0xd8, 0x24, 0xc4, 0x11, 0x13, 0x5e, 0x51, 0x89, 0xab, 0xc4, 0xf3, 0xf0, 0x58,
+	0xbe, 0x3d, 0x19, 0xfb, 0xd4, 0xde, 0x37, 0x6a, 0x2a, 0xe1, 0xc9, 0x71, 0x59, 0x6b, 0x1a, 0xf5,
+	0x4d, 0xb4, 0xa6, 0xac, 0x1d, 0x18, 0xfa, 0x26, 0xda, 0x81, 0x5c, 0x1a, 0x50, 0xac, 0x34, 0x1a,
+	0x45, 0x4a, 0xa3, 0x59, 0xa0, 0x34, 0x5a, 0x05, 0x4a, 0x63, 0x5b, 0xb9, 0x34, 0xb8, 0x6b, 0x22,
+	0x32, 0x77, 0x63, 0x97, 0x06, 0x46, 0x5b, 0xc5, 0x35, 0x19, 0x8d, 0xbe, 0x83, 0x46, 0xe8, 0x61,
+	0xf6, 0x96, 0x46, 0xfe, 0xd8, 0xb5, 0x8d, 0x8e, 0x82, 0x58, 0x16, 0x74, 0xbf, 0x81, 0xd6, 0x8a,
+	0xfd, 0x51, 0x07, 0xb4, 0x3b, 0xb2, 0x10, 0x85, 0xa9, 0x0f, 0xf9, 0x4f, 0xb4, 0x0b, 0x5b, 0x73,
+	0x2e, 0x14, 0x55, 0xa7, 0x0f, 0x93, 0x8b, 0xa3, 0xf2, 0xab, 0x52, 0xef, 0x1f, 0x0d, 0x9a, 0x69,
+	0x11, 0x1d, 0x7b, 0x2e, 0x8e, 0x79, 0x46, 0xd3, 0x76, 0xab, 0x54, 0xd9, 0x19, 0xfc, 0xa8, 0xe2,
+	0x36, 0x61, 0x0b, 0xf3, 0x97, 0x2b, 0xf5, 0xc9, 0x04, 0x45, 0x9f, 0x43, 0x85, 0x2d, 0x42, 0x22,
+	0x0a, 0x7b, 0xdb, 0x6c, 0xf1, 0x66, 0x20, 0x3e, 0x60, 0xb4, 0x08, 0xc9, 0x50, 0x3c, 0x92, 0x4c,
+	0x56, 0xdf, 0xc0, 0x64, 0x92, 0xa5, 0xf5, 0x4d, 0x2c, 0x7d, 0x92, 0xcf, 0xa9, 0x71, 0x62, 0x52,
+	0x95, 0xde, 0xb1, 0x2a, 0x59, 0x9a, 0xb5, 0x5a, 0xc0, 0xac, 0x35, 0xf5, 0x3e, 0xfe, 0xb7, 0x0e,
+	0xf5, 0xcb, 0x20, 0x66, 0x38, 0xb0, 0x08, 0xfa, 0x0a, 0xca, 0xae, 0xad, 0x94, 0xec, 0xb2, 0x6b,
+	0xcb, 0xfe, 0x28, 0x17, 0xf6, 0x87, 0xb6, 0x99, 0x3f, 0x0e, 0xa0, 0x3a, 0x0f, 0x2d, 0x5e, 0x1c,
+	0x1f, 0xa9, 0x24, 0x32, 0x61, 0xf9, 0x80, 0x72, 0x68, 0xcc, 0x94, 0x5a, 0xbf, 0x20, 0xb9, 0x82,
+	0x8f, 0x80, 0xb5, 0x99, 0xbb, 0xbd, 0x0c, 0xd8, 0xc0, 0x4c, 0x15, 0x9c, 0xe4, 0x55, 0x2f, 0x9e,
+	0x5a, 0xd4, 0x53, 0xca, 0x59, 0x4e, 0xf3, 0x38, 0xce, 0x49, 0x24, 0xda, 0x85, 0x4a, 0xe2, 0x32,
+	0x38, 0x79, 0xa3, 0x4b, 0x23, 0x97, 0x2d, 0x8c, 0xba, 0xc2, 0x3e, 0x73, 0x9a, 0x47, 0xf1, 0x9e,
+	0xb8, 0x53, 0x67, 0xbd, 0xaf, 0x65, 0x5d, 0xca, 0xa2, 0x1f, 0x60, 0x87, 0x04, 0x78, 0xe2, 0x91,
+	0xb1, 0x43, 0xb0, 0xc7, 0x9c, 0xb1, 0xe5, 0x10, 0xeb, 0xce, 0xd8, 0x15, 0x4b, 0x74, 0xdf, 0x59,
+	0xe2, 0x84, 0x52, 0x2f, 0x59, 0xe0, 0x69, 0x22, 0xbb, 0x10, 0xaa, 0x53, 0x2e, 0x42, 0x26, 0x34,
+	0x57, 0x16, 0x49, 0x46, 0x46, 0x9b, 0xd7, 0xae, 0x84, 0x0d, 0x1b, 0x8e, 0xa4, 0x39, 0x80, 0x5a,
+	0x72, 0xb9, 0x30, 0x1a, 0xef, 0x7d, 0x67, 0x86, 0x72, 0x95, 0x1b, 0x53, 0x0f, 0x33, 0x62, 0x34,
+	0xdf, 0xaf, 0x4a, 0x51, 0xb4, 0x07, 0x75, 0x8f, 0x5a, 0x98, 0xf1, 0xa4, 0x24, 0xe3, 0xa2, 0xc9,
+	0xf7, 0x76, 0x95, 0xde, 0x1b, 0xe6, 0x4f, 0xd1, 0xa1, 0x74, 0x1c, 0xd9, 0x16, 0xc7, 0x91, 0x2e,
+	0x27, 0xb3, 0x9a, 0x5a, 0x7b, 0x1e, 0x39, 0x02, 0xdd, 0xa3, 0x53, 0xd7, 0x1a, 0xc7, 0x84, 0x29,
+	0x8d, 0x89, 0x25, 0xbe, 0x6c, 0x0e, 0x9d, 0x02, 0xcd, 0xe1, 0x69, 0xb1, 0x49, 0x86, 0x36, 0x9a,
+	0x64, 0xef, 0xb4, 0xc0, 0x9d, 0x8d, 0x5b, 0xe0, 0xe3, 0xa6, 0xd9, 0x1f, 0x65, 0x68, 0xc8, 0x86,
+	0x1b, 0xa4, 0x43, 0xa2, 0x24, 0x86, 0xc4, 0x67, 0x0f, 0x8c, 0x26, 0xff, 0x96, 0xc6, 0xc6, 0x21,
+	0xe8, 0x0e, 0xc1, 0x11, 0x9b, 0x10, 0x9c, 0x1d, 0xf8, 0x8d, 0x54, 0x99, 0xdc, 0x94, 0xbd, 0xba,
+	0x44, 0xd1, 0x73, 0xd0, 0x98, 0x15, 0xa6, 0xbd, 0x0d, 0x71, 0xc5, 0xc8, 0x0a, 0x65, 0x96, 0x3f,
+	0x46, 0x5f, 0x40, 0xc5, 0x61, 0x2c, 0x4c, 0xbb, 0xd2, 0x8e, 0x58, 0x98, 0xb1, 0x15, 0x4e, 0x00,
+	0xbd, 0xef, 0xa1, 0xfd, 0x60, 0x7f, 0xa8, 0x01, 0xb5, 0xdb, 0xeb, 0x1f, 0xaf, 0x7f, 0xfe, 0xf5,
+	0xba, 0xf3, 0x04, 0xb5, 0x40, 0xbf, 0x38, 0x3b, 0x1e, 0x8e, 0x4e, 0xce, 0x8e, 0x47, 0x9d, 0x12,
+	0xaa, 0x81, 0x36, 0x3a, 0x7d, 0xd3, 0x29, 0xa3, 0x3a, 0x54, 0x2e, 0x46, 0xa3, 0x37, 0x1d, 0xad,
+	0x77, 0x0e, 0xbb, 0xff, 0xb7, 0x67, 0xd4, 0x07, 0x8d, 0x31, 0xcf, 0x28, 0x29, 0x74, 0x01, 0x0e,
+	0xf6, 0x7e, 0x2f, 0xc1, 0xf6, 0xea, 0xa7, 0x70, 0x8f, 0xb8, 0x01, 0x23, 0xd1, 0x1c, 0xab, 0xad,
+	0x93, 0xd3, 0xbc, 0xef, 0x71, 0x97, 0xd1, 0xd9, 0xfa, 0x3f, 0x53, 0xb2, 0x30, 0x83, 0x7b, 0xff,
+	0x96, 0xa0, 0xfd, 0x20, 0x50, 0x1f, 0x7e, 0x17, 0x62, 0x42, 0x60, 0xe6, 0x28, 0x0d, 0x30, 0x41,
+	0xa2, 0x73, 0x68, 0x93, 0xdf, 0x42, 0x62, 0x31, 0x62, 0x8f, 0x63, 0x86, 0xd9, 0x6c, 0xfd, 0x7f,
+	0x11, 0xf9, 0x8d, 0x0f, 0x45, 0x5f, 0x3e, 0x07, 0x3d, 0x3f, 0xdf, 0x70, 0x23, 0xbc, 0x3e, 0x3b,
+	0x3f, 0xbe, 0xbd, 0x1a, 0x75, 0x9e, 0x20, 0x80, 0xea, 0xe9, 0xd5, 0xcb, 0x9b, 0xcb, 0xd7, 0x9d,
+	0xd2, 0xa4, 0x2a, 0x16, 0x1b, 0xfc, 0x37, 0x00, 0x7f, 0xff, 0xf6, 0xa2, 0x33, 0x0f, 0x00, 0x00,
 }
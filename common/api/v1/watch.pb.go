@@ -0,0 +1,291 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: watch.proto
+
+package v1
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import any "github.com/golang/protobuf/ptypes/any"
+import wrappers "github.com/golang/protobuf/ptypes/wrappers"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type WatchRequest struct {
+	Namespace            *wrappers.StringValue `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Service              *wrappers.StringValue `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	Resources            []string              `protobuf:"bytes,3,rep,name=resources,proto3" json:"resources,omitempty"`
+	Seq                  *wrappers.UInt64Value `protobuf:"bytes,4,opt,name=seq,proto3" json:"seq,omitempty"`
+	Revision             *wrappers.StringValue `protobuf:"bytes,5,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_watch_66838219ecb7bddd, []int{0}
+}
+func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRequest.Unmarshal(m, b)
+}
+func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRequest.Merge(dst, src)
+}
+func (m *WatchRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRequest.Size(m)
+}
+func (m *WatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+
+func (m *WatchRequest) GetNamespace() *wrappers.StringValue {
+	if m != nil {
+		return m.Namespace
+	}
+	return nil
+}
+
+func (m *WatchRequest) GetService() *wrappers.StringValue {
+	if m != nil {
+		return m.Service
+	}
+	return nil
+}
+
+func (m *WatchRequest) GetResources() []string {
+	if m != nil {
+		return m.Resources
+	}
+	return nil
+}
+
+func (m *WatchRequest) GetSeq() *wrappers.UInt64Value {
+	if m != nil {
+		return m.Seq
+	}
+	return nil
+}
+
+func (m *WatchRequest) GetRevision() *wrappers.StringValue {
+	if m != nil {
+		return m.Revision
+	}
+	return nil
+}
+
+type WatchEvent struct {
+	Seq                  *wrappers.UInt64Value `protobuf:"bytes,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Resource             *wrappers.StringValue `protobuf:"bytes,2,opt,name=resource,proto3" json:"resource,omitempty"`
+	Operation            *wrappers.StringValue `protobuf:"bytes,3,opt,name=operation,proto3" json:"operation,omitempty"`
+	Namespace            *wrappers.StringValue `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Service              *wrappers.StringValue `protobuf:"bytes,5,opt,name=service,proto3" json:"service,omitempty"`
+	Revision             *wrappers.StringValue `protobuf:"bytes,6,opt,name=revision,proto3" json:"revision,omitempty"`
+	Operator             *wrappers.StringValue `protobuf:"bytes,7,opt,name=operator,proto3" json:"operator,omitempty"`
+	Ctime                *wrappers.StringValue `protobuf:"bytes,8,opt,name=ctime,proto3" json:"ctime,omitempty"`
+	Object               *any.Any              `protobuf:"bytes,9,opt,name=object,proto3" json:"object,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *WatchEvent) Reset()         { *m = WatchEvent{} }
+func (m *WatchEvent) String() string { return proto.CompactTextString(m) }
+func (*WatchEvent) ProtoMessage()    {}
+func (*WatchEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_watch_66838219ecb7bddd, []int{1}
+}
+func (m *WatchEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchEvent.Unmarshal(m, b)
+}
+func (m *WatchEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchEvent.Marshal(b, m, deterministic)
+}
+func (dst *WatchEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchEvent.Merge(dst, src)
+}
+func (m *WatchEvent) XXX_Size() int {
+	return xxx_messageInfo_WatchEvent.Size(m)
+}
+func (m *WatchEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchEvent proto.InternalMessageInfo
+
+func (m *WatchEvent) GetSeq() *wrappers.UInt64Value {
+	if m != nil {
+		return m.Seq
+	}
+	return nil
+}
+
+func (m *WatchEvent) GetResource() *wrappers.StringValue {
+	if m != nil {
+		return m.Resource
+	}
+	return nil
+}
+
+func (m *WatchEvent) GetOperation() *wrappers.StringValue {
+	if m != nil {
+		return m.Operation
+	}
+	return nil
+}
+
+func (m *WatchEvent) GetNamespace() *wrappers.StringValue {
+	if m != nil {
+		return m.Namespace
+	}
+	return nil
+}
+
+func (m *WatchEvent) GetService() *wrappers.StringValue {
+	if m != nil {
+		return m.Service
+	}
+	return nil
+}
+
+func (m *WatchEvent) GetRevision() *wrappers.StringValue {
+	if m != nil {
+		return m.Revision
+	}
+	return nil
+}
+
+func (m *WatchEvent) GetOperator() *wrappers.StringValue {
+	if m != nil {
+		return m.Operator
+	}
+	return nil
+}
+
+func (m *WatchEvent) GetCtime() *wrappers.StringValue {
+	if m != nil {
+		return m.Ctime
+	}
+	return nil
+}
+
+func (m *WatchEvent) GetObject() *any.Any {
+	if m != nil {
+		return m.Object
+	}
+	return nil
+}
+
+type WatchResponse struct {
+	Code                 *wrappers.UInt32Value `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Info                 *wrappers.StringValue `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	Seq                  *wrappers.UInt64Value `protobuf:"bytes,3,opt,name=seq,proto3" json:"seq,omitempty"`
+	Events               []*WatchEvent         `protobuf:"bytes,4,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *WatchResponse) Reset()         { *m = WatchResponse{} }
+func (m *WatchResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchResponse) ProtoMessage()    {}
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_watch_66838219ecb7bddd, []int{2}
+}
+func (m *WatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchResponse.Unmarshal(m, b)
+}
+func (m *WatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchResponse.Marshal(b, m, deterministic)
+}
+func (dst *WatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchResponse.Merge(dst, src)
+}
+func (m *WatchResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchResponse.Size(m)
+}
+func (m *WatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchResponse proto.InternalMessageInfo
+
+func (m *WatchResponse) GetCode() *wrappers.UInt32Value {
+	if m != nil {
+		return m.Code
+	}
+	return nil
+}
+
+func (m *WatchResponse) GetInfo() *wrappers.StringValue {
+	if m != nil {
+		return m.Info
+	}
+	return nil
+}
+
+func (m *WatchResponse) GetSeq() *wrappers.UInt64Value {
+	if m != nil {
+		return m.Seq
+	}
+	return nil
+}
+
+func (m *WatchResponse) GetEvents() []*WatchEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*WatchRequest)(nil), "v1.WatchRequest")
+	proto.RegisterType((*WatchEvent)(nil), "v1.WatchEvent")
+	proto.RegisterType((*WatchResponse)(nil), "v1.WatchResponse")
+}
+
+func init() { proto.RegisterFile("watch.proto", fileDescriptor_watch_66838219ecb7bddd) }
+
+var fileDescriptor_watch_66838219ecb7bddd = []byte{
+	// 374 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x93, 0x4f, 0x4f, 0xf2, 0x40,
+	0x10, 0xc6, 0x53, 0x5a, 0x0a, 0x1d, 0xde, 0xd7, 0xc3, 0xc6, 0xc3, 0x4a, 0x88, 0x21, 0x1c, 0x0c,
+	0x07, 0x53, 0xa4, 0x18, 0x62, 0xbc, 0x79, 0xf0, 0xe0, 0xb5, 0x46, 0x3d, 0x97, 0x3a, 0x60, 0x0d,
+	0xec, 0x96, 0xdd, 0x6d, 0x09, 0x5f, 0xc1, 0xaf, 0xe6, 0x57, 0xf2, 0x60, 0xda, 0xa5, 0xe0, 0x9f,
+	0x68, 0x16, 0xaf, 0x3b, 0xbf, 0x67, 0x66, 0xfa, 0xcc, 0x53, 0x68, 0xad, 0x22, 0x15, 0x3f, 0xf9,
+	0xa9, 0xe0, 0x8a, 0x93, 0x5a, 0x3e, 0x6c, 0x1f, 0xcf, 0x38, 0x9f, 0xcd, 0x71, 0x50, 0xbe, 0x4c,
+	0xb2, 0xe9, 0x60, 0x25, 0xa2, 0x34, 0x45, 0x21, 0x35, 0xd3, 0x3e, 0xfa, 0x5a, 0x8f, 0xd8, 0x5a,
+	0x97, 0x7a, 0x2f, 0x35, 0xf8, 0xf7, 0x50, 0xb4, 0x0b, 0x71, 0x99, 0xa1, 0x54, 0xe4, 0x12, 0x3c,
+	0x16, 0x2d, 0x50, 0xa6, 0x51, 0x8c, 0xd4, 0xea, 0x5a, 0xfd, 0x56, 0xd0, 0xf1, 0xb5, 0xde, 0xaf,
+	0xf4, 0xfe, 0xad, 0x12, 0x09, 0x9b, 0xdd, 0x47, 0xf3, 0x0c, 0xc3, 0x1d, 0x4e, 0xc6, 0xd0, 0x90,
+	0x28, 0xf2, 0x24, 0x46, 0x5a, 0x33, 0x50, 0x56, 0x30, 0xe9, 0x80, 0x27, 0x50, 0xf2, 0x4c, 0xc4,
+	0x28, 0xa9, 0xdd, 0xb5, 0xfb, 0x5e, 0xb8, 0x7b, 0x20, 0x3e, 0xd8, 0x12, 0x97, 0xd4, 0xf9, 0xa1,
+	0xe3, 0xdd, 0x0d, 0x53, 0xe3, 0x73, 0xdd, 0xb1, 0x00, 0xc9, 0x05, 0x34, 0x05, 0xe6, 0x89, 0x4c,
+	0x38, 0xa3, 0x75, 0x83, 0x35, 0xb6, 0x74, 0xef, 0xcd, 0x06, 0x28, 0xcd, 0xb8, 0xce, 0x91, 0xa9,
+	0x6a, 0xb0, 0xb5, 0xd7, 0x60, 0xbd, 0xb5, 0xd1, 0xf7, 0x6f, 0xe9, 0xc2, 0x74, 0x9e, 0xa2, 0x88,
+	0x54, 0xb1, 0xb3, 0x6d, 0x62, 0xfa, 0x16, 0xff, 0x7c, 0x30, 0xe7, 0xcf, 0x07, 0xab, 0xef, 0x73,
+	0xb0, 0x8f, 0x16, 0xbb, 0xfb, 0x58, 0x5c, 0x28, 0xf5, 0xea, 0x5c, 0xd0, 0x86, 0x89, 0xb2, 0xa2,
+	0x49, 0x00, 0xf5, 0x58, 0x25, 0x0b, 0xa4, 0x4d, 0x03, 0x99, 0x46, 0xc9, 0x29, 0xb8, 0x7c, 0xf2,
+	0x8c, 0xb1, 0xa2, 0x5e, 0x29, 0x3a, 0xfc, 0x26, 0xba, 0x62, 0xeb, 0x70, 0xc3, 0xf4, 0x5e, 0x2d,
+	0xf8, 0xbf, 0xf9, 0x17, 0x64, 0xca, 0x99, 0x44, 0x72, 0x06, 0x4e, 0xcc, 0x1f, 0xf1, 0xd7, 0x08,
+	0x8c, 0x02, 0x3d, 0xb2, 0x24, 0x0b, 0x45, 0xc2, 0xa6, 0xdc, 0xe8, 0xfe, 0x25, 0x59, 0xa5, 0xcc,
+	0x36, 0x4d, 0xd9, 0x09, 0xb8, 0x58, 0xc4, 0x53, 0x52, 0xa7, 0x6b, 0xf7, 0x5b, 0xc1, 0x81, 0x9f,
+	0x0f, 0xfd, 0x5d, 0x6a, 0xc3, 0x4d, 0x75, 0xe2, 0x96, 0x2d, 0x46, 0xef, 0x03, 0x00, 0x76, 0x1f,
+	0x9a, 0xdf, 0x2e, 0x04, 0x00, 0x00,
+}
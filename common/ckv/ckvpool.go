@@ -21,6 +21,8 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"net"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -111,7 +113,7 @@ func newNode(index, connNum int, kvPasswd string, ins *model.Instance) *Node {
 
 	node := &Node{
 		index:    index,
-		addr:     fmt.Sprintf("%s:%d", ins.Host(), ins.Port()),
+		addr:     net.JoinHostPort(ins.Host(), strconv.Itoa(int(ins.Port()))),
 		stopCh:   make(chan struct{}, 1),
 		changeCh: make(chan struct{}, 1),
 		conns:    make([]*Conn, 0, connIndexEnd), // pre-allocated in advance
@@ -22,6 +22,7 @@ import (
 	"sync"
 
 	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/metrics"
 )
 
 // limitListener limit obj for Listener
@@ -36,6 +37,31 @@ var (
 	}
 )
 
+// 注册连接数指标
+func init() {
+	registry := metrics.DefaultRegistry()
+	registry.NewGaugeFunc("polaris_conn_limit_connections", "Number of connections held by the listener.",
+		func() []*metrics.GaugeValue {
+			return collectListenerMetrics(func(lis *Listener) int32 { return lis.GetListenerConnCount() })
+		}, "protocol")
+	registry.NewGaugeFunc("polaris_conn_limit_hosts", "Number of distinct client hosts of the listener.",
+		func() []*metrics.GaugeValue {
+			return collectListenerMetrics(func(lis *Listener) int32 { return lis.GetDistinctHostCount() })
+		}, "protocol")
+}
+
+// 遍历所有协议的listener，获取指标值
+func collectListenerMetrics(fn func(lis *Listener) int32) []*metrics.GaugeValue {
+	limitEntry.mu.RLock()
+	defer limitEntry.mu.RUnlock()
+
+	out := make([]*metrics.GaugeValue, 0, len(limitEntry.listenerMap))
+	for protocol, lis := range limitEntry.listenerMap {
+		out = append(out, &metrics.GaugeValue{LabelValues: []string{protocol}, Value: float64(fn(lis))})
+	}
+	return out
+}
+
 // GetLimitListener 获取当前的listener
 func GetLimitListener(protocol string) *Listener {
 	limitEntry.mu.RLock()
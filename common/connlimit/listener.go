@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/utils"
 )
 
 const (
@@ -103,7 +105,7 @@ func NewListener(l net.Listener, protocol string, config *Config) (net.Listener,
 			continue
 		}
 
-		whiteList[entry] = true
+		whiteList[normalizeHost(entry)] = true
 	}
 	log.Infof("[ConnLimit] host conn limit white list: %+v", whites)
 
@@ -147,7 +149,7 @@ func (l *Listener) Close() error {
 // GetHostConnCount 查看对应ip的连接数
 func (l *Listener) GetHostConnCount(host string) int32 {
 	var connNum int32
-	if value, ok := l.conns.Load(host); ok {
+	if value, ok := l.conns.Load(normalizeHost(host)); ok {
 		c := value.(*counter)
 		c.mu.Lock()
 		connNum = c.size
@@ -182,7 +184,7 @@ func (l *Listener) GetDistinctHostCount() int32 {
 
 // GetHostActiveConns 获取指定host的活跃的连接
 func (l *Listener) GetHostActiveConns(host string) map[string]*Conn {
-	obj, ok := l.conns.Load(host)
+	obj, ok := l.conns.Load(normalizeHost(host))
 	if !ok {
 		return nil
 	}
@@ -218,6 +220,7 @@ func (l *Listener) GetHostConnStats(host string) []*HostConnStat {
 	var out []*HostConnStat
 	// 只获取一个，推荐每次只获取一个
 	if host != "" {
+		host = normalizeHost(host)
 		if obj, ok := l.conns.Load(host); ok {
 			out = append(out, loadStat(host, obj.(*counter)))
 			return out
@@ -235,13 +238,14 @@ func (l *Listener) GetHostConnStats(host string) []*HostConnStat {
 
 // GetHostConnection 获取指定host和port的连接
 func (l *Listener) GetHostConnection(host string, port int) *Conn {
+	host = normalizeHost(host)
 	obj, ok := l.conns.Load(host)
 	if !ok {
 		return nil
 	}
 
 	ct := obj.(*counter)
-	target := fmt.Sprintf("%s:%d", host, port)
+	target := net.JoinHostPort(host, strconv.Itoa(port))
 	ct.mu.Lock()
 	defer ct.mu.Unlock()
 	for address, conn := range ct.actives {
@@ -257,11 +261,11 @@ func (l *Listener) GetHostConnection(host string, port int) *Conn {
 func (l *Listener) accept(conn net.Conn) net.Conn {
 	address := conn.RemoteAddr().String()
 	// addr解析失败, 不做限制
-	ipPort := strings.Split(address, ":")
-	if len(ipPort) != 2 || ipPort[0] == "" {
+	host := utils.ParseClientIP(address)
+	if host == "" {
 		return conn
 	}
-	return l.acquire(conn, address, ipPort[0])
+	return l.acquire(conn, address, host)
 }
 
 // 包裹一下conn
@@ -360,6 +364,14 @@ func (l *Listener) descConnCount() {
 	atomic.AddInt32(&l.connCount, -1)
 }
 
+// 把host转换为与连接计数一致的IP格式，兼容IPv6的不同写法，非IP的host保持不变
+func normalizeHost(host string) string {
+	if ip := utils.NormalizeIP(host); ip != "" {
+		return ip
+	}
+	return host
+}
+
 // 判断host是否在白名单中
 // 如果host在白名单中，则忽略host连接限制
 func (l *Listener) ignoreHostConnLimit(host string) bool {
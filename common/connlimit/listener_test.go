@@ -231,6 +231,20 @@ func TestListener_Accept(t *testing.T) {
 		lis := NewTestLimitListener(100, 10)
 		So(lis.accept(conn).(*Conn).isValid(), ShouldBeTrue)
 	})
+	Convey("IPv6地址按照标准格式的IP计数", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		addr := mock_net.NewMockAddr(ctrl)
+		conn := mock_net.NewMockConn(ctrl)
+		conn.EXPECT().Close().Return(nil).AnyTimes()
+		addr.EXPECT().String().Return("[2001:db8::1]:8080").AnyTimes()
+		conn.EXPECT().RemoteAddr().Return(addr).AnyTimes()
+		lis := NewTestLimitListener(100, 1)
+		So(lis.accept(conn).(*Conn).isValid(), ShouldBeTrue)
+		So(lis.GetHostConnCount("2001:DB8:0::1"), ShouldEqual, 1)
+		So(lis.GetHostConnection("[2001:db8::1]", 8080), ShouldNotBeNil)
+		So(lis.accept(conn).(*Conn).isValid(), ShouldBeFalse)
+	})
 }
 
 // TestLimitListener_Acquire 测试acquire
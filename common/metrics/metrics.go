@@ -0,0 +1,393 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// ContentType Prometheus文本格式的Content-Type
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+var (
+	// DefaultBuckets 默认的耗时直方图分桶，单位为秒
+	DefaultBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+	defaultRegistry = NewRegistry()
+)
+
+/**
+ * DefaultRegistry 获取全局的指标注册中心
+ */
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// 指标的统一接口，按照Prometheus文本格式输出
+type collector interface {
+	name() string
+	write(w *bufio.Writer)
+}
+
+/**
+ * Registry 指标注册中心
+ * @note 同名的指标只会注册一次，重复注册返回已存在的指标
+ */
+type Registry struct {
+	mutex      sync.RWMutex
+	collectors map[string]collector
+}
+
+/**
+ * NewRegistry 新建指标注册中心
+ */
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+/**
+ * NewCounterVec 注册带标签的计数器
+ */
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := r.register(&CounterVec{
+		vec: newVec(name, help, labelNames),
+	})
+	return c.(*CounterVec)
+}
+
+/**
+ * NewHistogramVec 注册带标签的直方图，buckets为空则使用默认分桶
+ */
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+	h := r.register(&HistogramVec{
+		vec:     newVec(name, help, labelNames),
+		buckets: sorted,
+	})
+	return h.(*HistogramVec)
+}
+
+/**
+ * NewGaugeFunc 注册带标签的瞬时值指标，每次输出时调用fn获取最新的值
+ */
+func (r *Registry) NewGaugeFunc(name, help string, fn func() []*GaugeValue, labelNames ...string) {
+	r.register(&GaugeFunc{
+		vec: newVec(name, help, labelNames),
+		fn:  fn,
+	})
+}
+
+/**
+ * Unregister 注销指标
+ */
+func (r *Registry) Unregister(name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.collectors, name)
+}
+
+/**
+ * WriteText 按照Prometheus文本格式输出所有的指标
+ */
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mutex.RLock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	collectors := make([]collector, 0, len(names))
+	for _, name := range names {
+		collectors = append(collectors, r.collectors[name])
+	}
+	r.mutex.RUnlock()
+
+	writer := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(writer)
+	}
+	return writer.Flush()
+}
+
+// 注册指标，同名指标已存在则返回已存在的
+func (r *Registry) register(c collector) collector {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if exist, ok := r.collectors[c.name()]; ok {
+		return exist
+	}
+	r.collectors[c.name()] = c
+	return c
+}
+
+// 带标签指标的公共部分
+type vec struct {
+	metricName string
+	help       string
+	labelNames []string
+}
+
+func newVec(name, help string, labelNames []string) vec {
+	return vec{metricName: name, help: help, labelNames: labelNames}
+}
+
+func (v *vec) name() string {
+	return v.metricName
+}
+
+// 输出HELP和TYPE
+func (v *vec) writeHeader(w *bufio.Writer, typ string) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n", v.metricName, escapeHelp(v.help))
+	_, _ = fmt.Fprintf(w, "# TYPE %s %s\n", v.metricName, typ)
+}
+
+// 输出一行样本
+func (v *vec) writeSample(w *bufio.Writer, suffix string, labelValues []string,
+	extraName, extraValue string, value float64) {
+	_, _ = w.WriteString(v.metricName)
+	_, _ = w.WriteString(suffix)
+	labels := formatLabels(v.labelNames, labelValues, extraName, extraValue)
+	_, _ = w.WriteString(labels)
+	_ = w.WriteByte(' ')
+	_, _ = w.WriteString(formatFloat(value))
+	_ = w.WriteByte('\n')
+}
+
+/**
+ * CounterVec 带标签的计数器
+ */
+type CounterVec struct {
+	vec
+	mutex  sync.Mutex
+	values map[string]*counterValue
+}
+
+type counterValue struct {
+	labelValues []string
+	value       float64
+}
+
+/**
+ * Add 计数器增加delta，labelValues需要与注册时的标签一一对应
+ */
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	key := labelKey(labelValues)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.values == nil {
+		c.values = make(map[string]*counterValue)
+	}
+	item, ok := c.values[key]
+	if !ok {
+		item = &counterValue{labelValues: labelValues}
+		c.values[key] = item
+	}
+	item.value += delta
+}
+
+/**
+ * Inc 计数器加1
+ */
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.writeHeader(w, "counter")
+	for _, key := range sortedKeys(c.values) {
+		item := c.values[key]
+		c.writeSample(w, "", item.labelValues, "", "", item.value)
+	}
+}
+
+/**
+ * HistogramVec 带标签的直方图
+ */
+type HistogramVec struct {
+	vec
+	buckets []float64
+	mutex   sync.Mutex
+	values  map[string]*histogramValue
+}
+
+type histogramValue struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+/**
+ * Observe 记录一个观测值，labelValues需要与注册时的标签一一对应
+ */
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := labelKey(labelValues)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.values == nil {
+		h.values = make(map[string]*histogramValue)
+	}
+	item, ok := h.values[key]
+	if !ok {
+		item = &histogramValue{labelValues: labelValues, counts: make([]uint64, len(h.buckets))}
+		h.values[key] = item
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			item.counts[i]++
+		}
+	}
+	item.count++
+	item.sum += value
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.writeHeader(w, "histogram")
+	for _, key := range sortedKeys(h.values) {
+		item := h.values[key]
+		for i, bound := range h.buckets {
+			h.writeSample(w, "_bucket", item.labelValues, "le", formatFloat(bound), float64(item.counts[i]))
+		}
+		h.writeSample(w, "_bucket", item.labelValues, "le", "+Inf", float64(item.count))
+		h.writeSample(w, "_sum", item.labelValues, "", "", item.sum)
+		h.writeSample(w, "_count", item.labelValues, "", "", float64(item.count))
+	}
+}
+
+/**
+ * GaugeValue 瞬时值指标的一个样本
+ */
+type GaugeValue struct {
+	LabelValues []string
+	Value       float64
+}
+
+/**
+ * GaugeFunc 通过回调获取的瞬时值指标
+ */
+type GaugeFunc struct {
+	vec
+	fn func() []*GaugeValue
+}
+
+func (g *GaugeFunc) write(w *bufio.Writer) {
+	g.writeHeader(w, "gauge")
+	for _, item := range g.fn() {
+		g.writeSample(w, "", item.LabelValues, "", "", item.Value)
+	}
+}
+
+// 标签值拼接为map的key
+func labelKey(labelValues []string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+// map的key排序，保证输出稳定
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch values := m.(type) {
+	case map[string]*counterValue:
+		for key := range values {
+			keys = append(keys, key)
+		}
+	case map[string]*histogramValue:
+		for key := range values {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// 格式化标签，形如{k1="v1",k2="v2"}
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+	var builder strings.Builder
+	builder.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		builder.WriteString(name)
+		builder.WriteString(`="`)
+		builder.WriteString(escapeLabelValue(value))
+		builder.WriteByte('"')
+	}
+	if extraName != "" {
+		if len(names) > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteString(extraName)
+		builder.WriteString(`="`)
+		builder.WriteString(extraValue)
+		builder.WriteByte('"')
+	}
+	builder.WriteByte('}')
+	return builder.String()
+}
+
+var (
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+func escapeHelp(help string) string {
+	return helpReplacer.Replace(help)
+}
+
+// 格式化浮点数
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
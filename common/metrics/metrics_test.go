@@ -0,0 +1,76 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestRegistry_WriteText 测试指标的文本格式输出
+func TestRegistry_WriteText(t *testing.T) {
+	r := NewRegistry()
+	counter := r.NewCounterVec("test_requests_total", "test requests", "api", "code")
+	counter.Inc("GET:/v1", "200000")
+	counter.Inc("GET:/v1", "200000")
+	counter.Inc(`POST:"x"`, "400001")
+
+	histogram := r.NewHistogramVec("test_duration_seconds", "test duration", []float64{0.1, 1}, "api")
+	histogram.Observe(0.05, "GET:/v1")
+	histogram.Observe(0.5, "GET:/v1")
+
+	r.NewGaugeFunc("test_size", "test size", func() []*GaugeValue {
+		return []*GaugeValue{{Value: 3}}
+	})
+
+	// 重复注册返回已存在的指标
+	if r.NewCounterVec("test_requests_total", "test requests", "api", "code") != counter {
+		t.Fatalf("duplicate register should return existed counter")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := r.WriteText(buf); err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	text := buf.String()
+	expects := []string{
+		"# TYPE test_requests_total counter\n",
+		`test_requests_total{api="GET:/v1",code="200000"} 2` + "\n",
+		`test_requests_total{api="POST:\"x\"",code="400001"} 1` + "\n",
+		"# TYPE test_duration_seconds histogram\n",
+		`test_duration_seconds_bucket{api="GET:/v1",le="0.1"} 1` + "\n",
+		`test_duration_seconds_bucket{api="GET:/v1",le="1"} 2` + "\n",
+		`test_duration_seconds_bucket{api="GET:/v1",le="+Inf"} 2` + "\n",
+		`test_duration_seconds_sum{api="GET:/v1"} 0.55` + "\n",
+		`test_duration_seconds_count{api="GET:/v1"} 2` + "\n",
+		"# TYPE test_size gauge\ntest_size 3\n",
+	}
+	for _, expect := range expects {
+		if !strings.Contains(text, expect) {
+			t.Fatalf("output not contains %q:\n%s", expect, text)
+		}
+	}
+
+	r.Unregister("test_size")
+	buf.Reset()
+	_ = r.WriteText(buf)
+	if strings.Contains(buf.String(), "test_size") {
+		t.Fatalf("unregistered metric should not be output")
+	}
+}
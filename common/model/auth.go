@@ -0,0 +1,70 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package model
+
+import (
+	"time"
+)
+
+/**
+ * User 用户，Password保存的是加盐后的摘要
+ */
+type User struct {
+	ID         string
+	Name       string
+	Password   string
+	Comment    string
+	CreateTime time.Time
+	ModifyTime time.Time
+}
+
+/**
+ * UserGroup 用户组，Users为组内的用户名
+ */
+type UserGroup struct {
+	ID         string
+	Name       string
+	Comment    string
+	Users      []string
+	CreateTime time.Time
+	ModifyTime time.Time
+}
+
+/**
+ * Role 角色，把权限策略授予用户以及用户组
+ */
+type Role struct {
+	ID         string
+	Name       string
+	Comment    string
+	Users      []string
+	Groups     []string
+	Policies   []*AuthPolicy
+	CreateTime time.Time
+	ModifyTime time.Time
+}
+
+/**
+ * AuthPolicy 权限策略
+ * Resource为资源类型，Namespace和Service为空或者*时表示全部，Actions为read或者write
+ */
+type AuthPolicy struct {
+	Resource  string   `json:"resource"`
+	Namespace string   `json:"namespace,omitempty"`
+	Service   string   `json:"service,omitempty"`
+	Actions   []string `json:"actions"`
+}
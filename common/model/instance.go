@@ -223,6 +223,11 @@ type InstanceStore struct {
 	EnableHealthCheck int
 	CheckType         int32
 	TTL               uint32
+	// 主动探测的健康检查配置，TCP/HTTP类型有效
+	ProbeInterval       uint32
+	ProbeTimeout        uint32
+	ProbePath           string
+	ProbeExpectedStatus uint32
 	Priority          uint32
 	Revision          string
 	LogicSet          string
@@ -271,12 +276,7 @@ func Store2Instance(is *InstanceStore) *Instance {
 	}
 	// 如果不存在checkType，即checkType==-1。HealthCheck置为nil
 	if is.CheckType != -1 {
-		ins.Proto.HealthCheck = &api.HealthCheck{
-			Type: api.HealthCheck_HealthCheckType(is.CheckType),
-			Heartbeat: &api.HeartbeatHealthCheck{
-				Ttl: &wrappers.UInt32Value{Value: is.TTL},
-			},
-		}
+		ins.Proto.HealthCheck = store2HealthCheck(is)
 	}
 	// 如果location不为空，那么填充一下location
 	if is.Region != "" {
@@ -290,6 +290,30 @@ func Store2Instance(is *InstanceStore) *Instance {
 	return ins
 }
 
+// 根据健康检查类型，转换store中的健康检查配置
+func store2HealthCheck(is *InstanceStore) *api.HealthCheck {
+	check := &api.HealthCheck{Type: api.HealthCheck_HealthCheckType(is.CheckType)}
+	switch check.Type {
+	case api.HealthCheck_TCP:
+		check.Tcp = &api.TcpHealthCheck{
+			Interval: &wrappers.UInt32Value{Value: is.ProbeInterval},
+			Timeout:  &wrappers.UInt32Value{Value: is.ProbeTimeout},
+		}
+	case api.HealthCheck_HTTP:
+		check.Http = &api.HttpHealthCheck{
+			Interval:       &wrappers.UInt32Value{Value: is.ProbeInterval},
+			Timeout:        &wrappers.UInt32Value{Value: is.ProbeTimeout},
+			Path:           &wrappers.StringValue{Value: is.ProbePath},
+			ExpectedStatus: &wrappers.UInt32Value{Value: is.ProbeExpectedStatus},
+		}
+	default:
+		check.Heartbeat = &api.HeartbeatHealthCheck{
+			Ttl: &wrappers.UInt32Value{Value: is.TTL},
+		}
+	}
+	return check
+}
+
 // ExpandStore2Instance 扩展store转换
 func ExpandStore2Instance(es *ExpandInstanceStore) *Instance {
 	out := Store2Instance(es.ServiceInstance)
@@ -20,6 +20,7 @@ package model
 import (
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	v1 "github.com/polarismesh/polaris-server/common/api/v1"
 )
@@ -359,6 +360,12 @@ const (
 
 	// OUpdateToken 更新token
 	OUpdateToken OperationType = "UpdateToken" // nolint
+
+	// ORelease 发布规则
+	ORelease OperationType = "Release"
+
+	// OUnbind 解绑规则
+	OUnbind OperationType = "Unbind"
 )
 
 // Resource 操作资源
@@ -366,15 +373,16 @@ type Resource string
 
 // 定义包含的资源类型
 const (
-	RNamespace     Resource = "Namespace"
-	RService       Resource = "Service"
-	RRouting       Resource = "Routing"
-	RInstance      Resource = "Instance"
-	RRateLimit     Resource = "RateLimit"
-	RMeshResource  Resource = "MeshResource"
-	RMesh          Resource = "Mesh"
-	RMeshService   Resource = "MeshService"
-	RFluxRateLimit Resource = "FluxRateLimit"
+	RNamespace      Resource = "Namespace"
+	RService        Resource = "Service"
+	RRouting        Resource = "Routing"
+	RInstance       Resource = "Instance"
+	RRateLimit      Resource = "RateLimit"
+	RMeshResource   Resource = "MeshResource"
+	RMesh           Resource = "Mesh"
+	RMeshService    Resource = "MeshService"
+	RFluxRateLimit  Resource = "FluxRateLimit"
+	RCircuitBreaker Resource = "CircuitBreaker"
 )
 
 // ResourceType 资源类型
@@ -389,14 +397,15 @@ const (
 
 // ResourceTypeMap resource type map
 var ResourceTypeMap = map[Resource]ResourceType{
-	RNamespace:    ServiceType,
-	RService:      ServiceType,
-	RRouting:      ServiceType,
-	RInstance:     ServiceType,
-	RRateLimit:    ServiceType,
-	RMesh:         MeshType,
-	RMeshResource: MeshType,
-	RMeshService:  MeshType,
+	RNamespace:      ServiceType,
+	RService:        ServiceType,
+	RRouting:        ServiceType,
+	RInstance:       ServiceType,
+	RRateLimit:      ServiceType,
+	RCircuitBreaker: ServiceType,
+	RMesh:           MeshType,
+	RMeshResource:   MeshType,
+	RMeshService:    MeshType,
 }
 
 // GetResourceType 获取资源的大类型
@@ -404,6 +413,22 @@ func GetResourceType(r Resource) ResourceType {
 	return ResourceTypeMap[r]
 }
 
+// History 持久化的操作记录
+type History struct {
+	ID            uint64
+	ResourceType  string
+	OperationType string
+	Namespace     string
+	Service       string
+	Context       string
+	Operator      string
+	Revision      string
+	// 变更前后资源的json快照
+	Before     string
+	After      string
+	CreateTime time.Time
+}
+
 // RecordEntry 操作记录entry
 type RecordEntry struct {
 	ResourceType  Resource
@@ -416,4 +441,8 @@ type RecordEntry struct {
 	Operator      string
 	Revision      string
 	CreateTime    time.Time
+	// 变更后的资源，用于推送变更事件，删除操作为空
+	Object proto.Message
+	// 变更前的资源，用于记录变更前后的快照，新建操作为空
+	Before proto.Message
 }
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"hash/crc32"
 	"math/rand"
+	"net"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -136,8 +137,8 @@ func genRedisPool(insConnNum int, kvPasswd string, instance *model.Instance, max
 		MaxActive:   0,
 		IdleTimeout: time.Duration(idleTimeout),
 		Dial: func() (redis.Conn, error) {
-			conn, err := redis.Dial("tcp", instance.Host()+":"+
-				strconv.Itoa(int(instance.Port())), redis.DialPassword(kvPasswd))
+			conn, err := redis.Dial("tcp", net.JoinHostPort(instance.Host(),
+				strconv.Itoa(int(instance.Port()))), redis.DialPassword(kvPasswd))
 			if err != nil {
 				log.Infof("ERROR: fail init redis: %s", err.Error())
 				return nil, err
@@ -260,6 +261,9 @@ func (p *Pool) Set(id string, status int, beatTime int64, ch chan *Resp) { // no
  * Del 使用连接池，向redis发起Del请求
  */
 func (p *Pool) Del(id string, ch chan *Resp) { // nolint
+	if p.checkHasKvInstances(ch) {
+		return
+	}
 	task := &Task{
 		taskType: Del,
 		id:       id,
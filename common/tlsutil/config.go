@@ -0,0 +1,128 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package tlsutil
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/polarismesh/polaris-server/common/log"
+)
+
+const (
+	// ClientAuthNone 不校验客户端证书
+	ClientAuthNone = "none"
+	// ClientAuthOptional 客户端提供了证书时校验
+	ClientAuthOptional = "optional"
+	// ClientAuthRequire 客户端必须提供合法的证书
+	ClientAuthRequire = "require"
+
+	// 默认检查证书文件是否修改的间隔
+	defaultReloadInterval = 10 * time.Second
+)
+
+// Config TLS配置
+type Config struct {
+	// 开启TLS
+	Open bool `mapstructure:"open"`
+
+	// 服务端证书以及私钥，PEM格式
+	CertFile string `mapstructure:"certFile"`
+	KeyFile  string `mapstructure:"keyFile"`
+
+	// 校验客户端证书的CA，PEM格式
+	ClientCAFile string `mapstructure:"clientCAFile"`
+
+	// 客户端认证方式：none、optional、require，配置了clientCAFile时默认为require
+	ClientAuth string `mapstructure:"clientAuth"`
+
+	// 检查证书文件是否修改的间隔，小于0时不检查
+	ReloadInterval time.Duration `mapstructure:"reloadInterval"`
+}
+
+// ParseTLSConfig 解析配置，未开启TLS时返回nil
+func ParseTLSConfig(raw map[interface{}]interface{}) (*Config, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	config := &Config{}
+	decodeConfig := &mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		Result:     config,
+	}
+	decoder, err := mapstructure.NewDecoder(decodeConfig)
+	if err != nil {
+		log.Errorf("tls new decoder err: %s", err.Error())
+		return nil, err
+	}
+	if err := decoder.Decode(raw); err != nil {
+		log.Errorf("parse tls config(%+v) err: %s", raw, err.Error())
+		return nil, err
+	}
+	if !config.Open {
+		return nil, nil
+	}
+
+	if err := config.check(); err != nil {
+		log.Errorf("tls config(%+v) is invalid: %s", raw, err.Error())
+		return nil, err
+	}
+	return config, nil
+}
+
+// 检查配置并且填充默认值
+func (c *Config) check() error {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return errors.New("certFile and keyFile are required")
+	}
+	if c.ClientAuth == "" {
+		c.ClientAuth = ClientAuthNone
+		if c.ClientCAFile != "" {
+			c.ClientAuth = ClientAuthRequire
+		}
+	}
+	switch c.ClientAuth {
+	case ClientAuthNone:
+	case ClientAuthOptional, ClientAuthRequire:
+		if c.ClientCAFile == "" {
+			return fmt.Errorf("clientCAFile is required when clientAuth is %s", c.ClientAuth)
+		}
+	default:
+		return fmt.Errorf("clientAuth(%s) is invalid", c.ClientAuth)
+	}
+	if c.ReloadInterval == 0 {
+		c.ReloadInterval = defaultReloadInterval
+	}
+	return nil
+}
+
+// 转换为tls的客户端认证方式
+func (c *Config) clientAuthType() tls.ClientAuthType {
+	switch c.ClientAuth {
+	case ClientAuthOptional:
+		return tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		return tls.RequireAndVerifyClientCert
+	default:
+		return tls.NoClientCert
+	}
+}
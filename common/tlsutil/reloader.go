@@ -0,0 +1,174 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package tlsutil
+
+import (
+	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/log"
+)
+
+/**
+ * Reloader 加载服务端证书以及客户端CA，定期检查文件，修改后自动重新加载
+ * 每个新建的连接使用最新的证书，已经建立的连接不受影响
+ */
+type Reloader struct {
+	config *Config
+
+	mutex     sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	digest    string
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewReloader 加载证书并且启动检查协程
+func NewReloader(config *Config) (*Reloader, error) {
+	r := &Reloader{
+		config: config,
+		stopCh: make(chan struct{}),
+	}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+	if config.ReloadInterval > 0 {
+		go r.run()
+	}
+	return r, nil
+}
+
+// TLSConfig 生成服务端的TLS配置，nextProtos为ALPN协商的协议
+func (r *Reloader) TLSConfig(nextProtos ...string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mutex.RLock()
+			defer r.mutex.RUnlock()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   nextProtos,
+				Certificates: []tls.Certificate{*r.cert},
+				ClientCAs:    r.clientCAs,
+				ClientAuth:   r.config.clientAuthType(),
+			}, nil
+		},
+	}
+}
+
+// Stop 停止检查协程
+func (r *Reloader) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+	})
+}
+
+// 定期检查证书文件
+func (r *Reloader) run() {
+	ticker := time.NewTicker(r.config.ReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			changed, err := r.reload()
+			if err != nil {
+				log.Errorf("[TLS] reload cert(%s) err: %s, keep the old cert", r.config.CertFile, err.Error())
+				continue
+			}
+			if changed {
+				log.Infof("[TLS] cert(%s) is modified, reload it", r.config.CertFile)
+			}
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+// 证书文件修改后重新加载，返回是否重新加载，加载失败时保持原有的证书
+func (r *Reloader) reload() (bool, error) {
+	files := []string{r.config.CertFile, r.config.KeyFile}
+	if r.config.ClientCAFile != "" {
+		files = append(files, r.config.ClientCAFile)
+	}
+	contents := make([][]byte, len(files))
+	h := sha1.New()
+	for i, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return false, err
+		}
+		contents[i] = data
+		_, _ = h.Write(data)
+	}
+	digest := fmt.Sprintf("%x", h.Sum(nil))
+	r.mutex.RLock()
+	unchanged := digest == r.digest
+	r.mutex.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(contents[0], contents[1])
+	if err != nil {
+		return false, err
+	}
+	var clientCAs *x509.CertPool
+	if r.config.ClientCAFile != "" {
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(contents[2]) {
+			return false, errors.New("no valid cert in client ca file " + r.config.ClientCAFile)
+		}
+	}
+
+	r.mutex.Lock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.digest = digest
+	r.mutex.Unlock()
+	return true, nil
+}
+
+/**
+ * ClientIdentity 获取已经校验的客户端证书的身份，未校验客户端证书时返回空
+ * 优先使用URI SAN（例如SPIFFE ID），其次为证书的CN，最后为DNS SAN
+ */
+func ClientIdentity(state *tls.ConnectionState) string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	cert := state.VerifiedChains[0][0]
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0]
+	}
+	return ""
+}
@@ -0,0 +1,222 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package tlsutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// 测试用的证书
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+	kpem []byte
+}
+
+// 生成证书，parent为空时生成自签名的CA
+func newTestCert(t *testing.T, name string, parent *testCert, isCA bool) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key err: %s", err.Error())
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		IsCA:         isCA,
+
+		BasicConstraintsValid: true,
+	}
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("create cert err: %s", err.Error())
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDer, _ := x509.MarshalECPrivateKey(key)
+	return &testCert{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		kpem: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+	}
+}
+
+func writeTestFile(t *testing.T, path string, data []byte) {
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("write file(%s) err: %s", path, err.Error())
+	}
+}
+
+// 使用客户端证书发起握手，返回服务端看到的客户端身份以及服务端证书的CN
+func handshake(t *testing.T, config *tls.Config, ca *testCert, client *testCert) (string, string, error) {
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	if err != nil {
+		t.Fatalf("listen err: %s", err.Error())
+	}
+	defer ln.Close()
+
+	identityCh := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			identityCh <- ""
+			return
+		}
+		defer conn.Close()
+		tlsConn := conn.(*tls.Conn)
+		if err := tlsConn.Handshake(); err != nil {
+			identityCh <- ""
+			return
+		}
+		state := tlsConn.ConnectionState()
+		identityCh <- ClientIdentity(&state)
+		// 等待客户端读取，确认握手的结果
+		_, _ = conn.Write([]byte("ok"))
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientConfig := &tls.Config{RootCAs: roots, ServerName: "127.0.0.1"}
+	if client != nil {
+		pair, _ := tls.X509KeyPair(client.pem, client.kpem)
+		clientConfig.Certificates = []tls.Certificate{pair}
+	}
+	conn, err := tls.Dial("tcp", ln.Addr().String(), clientConfig)
+	if err != nil {
+		<-identityCh
+		return "", "", err
+	}
+	defer conn.Close()
+	if _, err := conn.Read(make([]byte, 2)); err != nil {
+		<-identityCh
+		return "", "", err
+	}
+	return <-identityCh, conn.ConnectionState().PeerCertificates[0].Subject.CommonName, nil
+}
+
+// TestReloader 测试双向认证、客户端身份以及证书热更新
+func TestReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatalf("create temp dir err: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	ca := newTestCert(t, "ca", nil, true)
+	server := newTestCert(t, "server-1", ca, false)
+	client := newTestCert(t, "client-1", ca, false)
+	other := newTestCert(t, "other", nil, true)
+	certFile, keyFile, caFile := filepath.Join(dir, "cert"), filepath.Join(dir, "key"), filepath.Join(dir, "ca")
+	writeTestFile(t, certFile, server.pem)
+	writeTestFile(t, keyFile, server.kpem)
+	writeTestFile(t, caFile, ca.pem)
+
+	config, err := ParseTLSConfig(map[interface{}]interface{}{
+		"open":           true,
+		"certFile":       certFile,
+		"keyFile":        keyFile,
+		"clientCAFile":   caFile,
+		"reloadInterval": "-1s",
+	})
+	if err != nil {
+		t.Fatalf("parse config err: %s", err.Error())
+	}
+	if config.ClientAuth != ClientAuthRequire {
+		t.Fatalf("client auth should be require by default, got %s", config.ClientAuth)
+	}
+	reloader, err := NewReloader(config)
+	if err != nil {
+		t.Fatalf("new reloader err: %s", err.Error())
+	}
+	defer reloader.Stop()
+
+	identity, serverName, err := handshake(t, reloader.TLSConfig(), ca, client)
+	if err != nil || identity != "client-1" || serverName != "server-1" {
+		t.Fatalf("handshake: %s, %s, %v", identity, serverName, err)
+	}
+	if _, _, err := handshake(t, reloader.TLSConfig(), ca, nil); err == nil {
+		t.Fatalf("client without cert should be rejected")
+	}
+	if _, _, err := handshake(t, reloader.TLSConfig(), ca, newTestCert(t, "client-2", other, false)); err == nil {
+		t.Fatalf("client cert of another ca should be rejected")
+	}
+
+	// 证书修改后，新的连接使用新的证书
+	writeTestFile(t, certFile, newTestCert(t, "server-2", ca, false).pem)
+	if _, err := reloader.reload(); err == nil {
+		t.Fatalf("cert and key are not matched, reload should fail")
+	}
+	if _, serverName, _ := handshake(t, reloader.TLSConfig(), ca, client); serverName != "server-1" {
+		t.Fatalf("old cert should be kept when reload failed, got %s", serverName)
+	}
+	server = newTestCert(t, "server-3", ca, false)
+	writeTestFile(t, certFile, server.pem)
+	writeTestFile(t, keyFile, server.kpem)
+	if changed, err := reloader.reload(); err != nil || !changed {
+		t.Fatalf("reload: %v, %v", changed, err)
+	}
+	if _, serverName, _ := handshake(t, reloader.TLSConfig(), ca, client); serverName != "server-3" {
+		t.Fatalf("new cert should be used, got %s", serverName)
+	}
+	if changed, _ := reloader.reload(); changed {
+		t.Fatalf("files are not modified, should not reload")
+	}
+}
+
+// TestParseTLSConfig 测试配置校验
+func TestParseTLSConfig(t *testing.T) {
+	if config, err := ParseTLSConfig(map[interface{}]interface{}{"open": false}); config != nil || err != nil {
+		t.Fatalf("tls is not open, config should be nil")
+	}
+	for _, raw := range []map[interface{}]interface{}{
+		{"open": true},
+		{"open": true, "certFile": "cert", "keyFile": "key", "clientAuth": "require"},
+		{"open": true, "certFile": "cert", "keyFile": "key", "clientAuth": "unknown"},
+	} {
+		if _, err := ParseTLSConfig(raw); err == nil {
+			t.Fatalf("config(%+v) should be invalid", raw)
+		}
+	}
+	config, err := ParseTLSConfig(map[interface{}]interface{}{"open": true, "certFile": "cert", "keyFile": "key"})
+	if err != nil || config.ClientAuth != ClientAuthNone || config.ReloadInterval != defaultReloadInterval {
+		t.Fatalf("default config: %+v, %v", config, err)
+	}
+}
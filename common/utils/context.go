@@ -43,3 +43,26 @@ func ValueLocalhost(ctx context.Context) string {
 
 	return value
 }
+
+const (
+	// HeartbeatForwardedHeader 被其他server转发的心跳请求，携带该header
+	HeartbeatForwardedHeader = "Polaris-Heartbeat-Forwarded"
+)
+
+// heartbeatForwardedCtx 标记心跳请求已经被转发过
+type heartbeatForwardedCtx struct{}
+
+// WithHeartbeatForwarded 标记心跳请求已经被转发过，不再继续转发
+func WithHeartbeatForwarded(ctx context.Context) context.Context {
+	return context.WithValue(ctx, heartbeatForwardedCtx{}, true)
+}
+
+// ValueHeartbeatForwarded 判断心跳请求是否已经被转发过
+func ValueHeartbeatForwarded(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+
+	value, ok := ctx.Value(heartbeatForwardedCtx{}).(bool)
+	return ok && value
+}
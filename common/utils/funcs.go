@@ -21,6 +21,7 @@ import (
 	"encoding/hex"
 	"strings"
 
+	"github.com/golang/protobuf/ptypes/wrappers"
 	uuid "github.com/google/uuid"
 	api "github.com/polarismesh/polaris-server/common/api/v1"
 	"github.com/polarismesh/polaris-server/common/model"
@@ -68,20 +69,12 @@ func CreateInstanceModel(serviceID string, req *api.Instance) *model.Instance {
 	}
 
 	// health Check，healthCheck不能为空，且没有显示把enable_health_check置为false
-	// 如果create的时候，打开了healthCheck，那么实例模式是unhealthy，必须要一次心跳才会healthy
-	if req.GetHealthCheck().GetHeartbeat() != nil &&
+	// 如果create的时候，打开了healthCheck，那么实例模式是unhealthy，必须要一次心跳或者探测成功才会healthy
+	if HasHealthCheck(req.GetHealthCheck()) &&
 		(req.GetEnableHealthCheck() == nil || req.GetEnableHealthCheck().GetValue()) {
 		protoIns.EnableHealthCheck = NewBoolValue(true)
 		protoIns.HealthCheck = req.HealthCheck
-		protoIns.HealthCheck.Type = api.HealthCheck_HEARTBEAT
-		// ttl range: (0, 60]
-		ttl := protoIns.GetHealthCheck().GetHeartbeat().GetTtl().GetValue()
-		if ttl == 0 || ttl > 60 {
-			if protoIns.HealthCheck.Heartbeat.Ttl == nil {
-				protoIns.HealthCheck.Heartbeat.Ttl = NewUInt32Value(5)
-			}
-			protoIns.HealthCheck.Heartbeat.Ttl.Value = 5
-		}
+		NormalizeHealthCheck(protoIns.HealthCheck)
 		// 开启健康检查，且没有代入健康状态，则健康状态默认都是false
 		protoIns.Healthy.Value = false
 	}
@@ -120,3 +113,98 @@ func IsWildName(name string) bool {
 	length := len(name)
 	return length >= 1 && name[length-1:length] == "*"
 }
+
+const (
+	// DefaultHeartbeatTTL 默认的心跳TTL，单位秒
+	DefaultHeartbeatTTL = 5
+	// MaxHeartbeatTTL 心跳TTL的最大值
+	MaxHeartbeatTTL = 60
+	// DefaultProbeInterval 默认的主动探测间隔，单位秒
+	DefaultProbeInterval = 5
+	// MaxProbeInterval 主动探测间隔的最大值
+	MaxProbeInterval = 300
+	// DefaultProbeTimeout 默认的主动探测超时时间，单位秒
+	DefaultProbeTimeout = 1
+	// DefaultProbePath 默认的HTTP探测路径
+	DefaultProbePath = "/"
+	// DefaultProbeExpectedStatus 默认的HTTP探测期望状态码
+	DefaultProbeExpectedStatus = 200
+)
+
+// HasHealthCheck 判断健康检查配置中是否携带了心跳、TCP或者HTTP的检查参数
+func HasHealthCheck(check *api.HealthCheck) bool {
+	return check.GetHeartbeat() != nil || check.GetTcp() != nil || check.GetHttp() != nil
+}
+
+/**
+ * NormalizeHealthCheck 确定健康检查的类型，并把检查参数修正到合法范围
+ * @note 显式指定的type优先，type对应的参数不存在时，按照heartbeat、tcp、http的顺序确定类型
+ *       只保留当前类型的检查参数
+ */
+func NormalizeHealthCheck(check *api.HealthCheck) {
+	if check == nil {
+		return
+	}
+	checkType := check.GetType()
+	switch {
+	case checkType == api.HealthCheck_TCP && check.GetTcp() != nil:
+	case checkType == api.HealthCheck_HTTP && check.GetHttp() != nil:
+	case check.GetHeartbeat() != nil:
+		checkType = api.HealthCheck_HEARTBEAT
+	case check.GetTcp() != nil:
+		checkType = api.HealthCheck_TCP
+	case check.GetHttp() != nil:
+		checkType = api.HealthCheck_HTTP
+	default:
+		checkType = api.HealthCheck_HEARTBEAT
+		check.Heartbeat = &api.HeartbeatHealthCheck{}
+	}
+	check.Type = checkType
+
+	switch checkType {
+	case api.HealthCheck_TCP:
+		check.Heartbeat = nil
+		check.Http = nil
+		check.Tcp.Interval, check.Tcp.Timeout = normalizeProbeTime(check.Tcp.Interval, check.Tcp.Timeout)
+	case api.HealthCheck_HTTP:
+		check.Heartbeat = nil
+		check.Tcp = nil
+		check.Http.Interval, check.Http.Timeout = normalizeProbeTime(check.Http.Interval, check.Http.Timeout)
+		path := strings.TrimSpace(check.Http.GetPath().GetValue())
+		if !strings.HasPrefix(path, "/") {
+			path = DefaultProbePath + path
+		}
+		check.Http.Path = NewStringValue(path)
+		// 期望状态码需要是合法的HTTP状态码
+		status := check.Http.GetExpectedStatus().GetValue()
+		if status < 100 || status > 599 {
+			status = DefaultProbeExpectedStatus
+		}
+		check.Http.ExpectedStatus = NewUInt32Value(status)
+	default:
+		check.Tcp = nil
+		check.Http = nil
+		// ttl range: (0, 60]
+		ttl := check.Heartbeat.GetTtl().GetValue()
+		if ttl == 0 || ttl > MaxHeartbeatTTL {
+			ttl = DefaultHeartbeatTTL
+		}
+		check.Heartbeat.Ttl = NewUInt32Value(ttl)
+	}
+}
+
+// 主动探测的间隔范围：(0, 300]，超时时间范围：(0, interval]
+func normalizeProbeTime(interval, timeout *wrappers.UInt32Value) (*wrappers.UInt32Value, *wrappers.UInt32Value) {
+	intervalValue := interval.GetValue()
+	if intervalValue == 0 || intervalValue > MaxProbeInterval {
+		intervalValue = DefaultProbeInterval
+	}
+	timeoutValue := timeout.GetValue()
+	if timeoutValue == 0 {
+		timeoutValue = DefaultProbeTimeout
+	}
+	if timeoutValue > intervalValue {
+		timeoutValue = intervalValue
+	}
+	return NewUInt32Value(intervalValue), NewUInt32Value(timeoutValue)
+}
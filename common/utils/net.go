@@ -0,0 +1,54 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"net"
+	"strings"
+)
+
+// ParseClientIP 从ip:port格式的客户端地址中解析出IP，兼容[ipv6]:port格式，解析失败返回空
+func ParseClientIP(address string) string {
+	host := address
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		host = h
+	}
+	return NormalizeIP(host)
+}
+
+// NormalizeIP 把IP转换为标准格式，非法的IP返回空
+// IPv6地址会去掉方括号以及zone，IPv4映射的IPv6地址转换为IPv4地址，保证双栈环境下同一个IP只有一种写法
+func NormalizeIP(host string) string {
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if pos := strings.LastIndex(host, "%"); pos >= 0 {
+		host = host[:pos]
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
+
+// NormalizeHost 把IP类型的host转换为标准格式，域名等其他类型的host保持不变
+func NormalizeHost(host string) string {
+	if ip := net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")); ip != nil {
+		return ip.String()
+	}
+	return host
+}
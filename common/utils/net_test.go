@@ -0,0 +1,49 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"testing"
+)
+
+// TestParseClientIP 测试IPv4以及IPv6客户端地址的解析
+func TestParseClientIP(t *testing.T) {
+	tests := map[string]string{
+		"127.0.0.1:8080":         "127.0.0.1",
+		"127.0.0.1":              "127.0.0.1",
+		"[::1]:8080":             "::1",
+		"[2001:DB8:0::1]:8080":   "2001:db8::1",
+		"[fe80::1%eth0]:8080":    "fe80::1",
+		"[::ffff:10.0.0.1]:8080": "10.0.0.1",
+		"2001:db8::1":            "2001:db8::1",
+		"polaris.example.com:80": "",
+		"@":                      "",
+	}
+	for address, expect := range tests {
+		if ip := ParseClientIP(address); ip != expect {
+			t.Fatalf("address(%s) parsed ip: %s, expect: %s", address, ip, expect)
+		}
+	}
+
+	if host := NormalizeHost("[2001:db8:0:0::1]"); host != "2001:db8::1" {
+		t.Fatalf("normalized ipv6 host: %s", host)
+	}
+	if host := NormalizeHost("polaris.example.com"); host != "polaris.example.com" {
+		t.Fatalf("domain host should not be changed: %s", host)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/polarismesh/polaris-server/common/log"
 	"github.com/polarismesh/polaris-server/naming"
 	"github.com/polarismesh/polaris-server/naming/cache"
+	"github.com/polarismesh/polaris-server/naming/k8ssync"
 	"github.com/polarismesh/polaris-server/plugin"
 	"github.com/polarismesh/polaris-server/store"
 	yaml "gopkg.in/yaml.v2"
@@ -39,6 +40,7 @@ type Config struct {
 	APIServers []apiserver.Config `yaml:"apiservers"`
 	Cache      cache.Config       `yaml:"cache"`
 	Naming     naming.Config      `yaml:"naming"`
+	K8sSync    k8ssync.Config     `yaml:"k8sSync"`
 	Store      store.Config       `yaml:"store"`
 	Plugin     plugin.Config      `yaml:"plugin"`
 }
@@ -50,6 +52,16 @@ type Bootstrap struct {
 	Logger         log.Options
 	StartInOrder   map[string]interface{} `yaml:"startInOrder"`
 	PolarisService PolarisService         `yaml:"polaris_service"`
+	ConfigWatch    ConfigWatch            `yaml:"configWatch"`
+}
+
+/**
+ * ConfigWatch 配置文件的监听配置，文件修改后自动热更新
+ */
+type ConfigWatch struct {
+	Open bool `yaml:"open"`
+	// 检查配置文件是否修改的间隔，单位为秒
+	Interval int `yaml:"interval"`
 }
 
 /**
@@ -17,6 +17,11 @@
 
 package auth
 
+import (
+	"errors"
+	"strings"
+)
+
 /**
 * Authority 内部鉴权接口
 *
@@ -44,4 +49,85 @@ type Authority interface {
 
 	// VerifyMesh 校验网格权限是否合法
 	VerifyMesh(expectToken string, actualToken string) bool
+
+	// VerifyGlobalToken 校验是否为全局Token，全局Token可以管理用户、用户组以及角色
+	VerifyGlobalToken(actualToken string) bool
+
+	// UserRequired 控制台接口是否必须由登录的用户访问
+	UserRequired() bool
+
+	// Login 校验用户名和密码，返回用户的访问Token
+	Login(name string, password string) (string, error)
+
+	// ParseUserToken 解析用户的访问Token，返回用户名
+	ParseUserToken(token string) (string, error)
+
+	// VerifyUser 根据用户被授予的角色，校验用户对资源的操作权限
+	VerifyUser(user string, permission *Permission) bool
+
+	// Reload 从存储层重新加载用户、用户组以及角色
+	Reload() error
+
+	// UpdateOptions 热更新鉴权配置
+	UpdateOptions(opt map[string]interface{})
+}
+
+// Action 资源的操作类型
+type Action string
+
+const (
+	// ActionRead 读操作
+	ActionRead Action = "read"
+	// ActionWrite 写操作，包含读操作
+	ActionWrite Action = "write"
+)
+
+// 权限策略的资源类型
+const (
+	ResourceAll            = "*"
+	ResourceNamespace      = "namespace"
+	ResourceService        = "service"
+	ResourceRouting        = "routing"
+	ResourceRateLimit      = "ratelimit"
+	ResourceCircuitBreaker = "circuitbreaker"
+	// ResourceAuth 用户、用户组以及角色
+	ResourceAuth = "auth"
+)
+
+// 操作记录以及变更事件的资源类型，与权限策略资源类型的对应关系
+var recordResources = map[string]string{
+	"namespace":      ResourceNamespace,
+	"service":        ResourceService,
+	"instance":       ResourceService,
+	"routing":        ResourceRouting,
+	"ratelimit":      ResourceRateLimit,
+	"fluxratelimit":  ResourceRateLimit,
+	"circuitbreaker": ResourceCircuitBreaker,
+}
+
+/**
+ * RecordResource 获取操作记录或者变更事件的资源类型对应的权限策略资源类型
+ * 没有对应关系的资源（例如网格）返回原有的类型，只有全部资源的权限策略才能匹配
+ */
+func RecordResource(resource string) string {
+	resource = strings.ToLower(resource)
+	if out, ok := recordResources[resource]; ok {
+		return out
+	}
+	return resource
 }
+
+// Permission 需要校验的操作权限，Namespace和Service为空表示全部
+type Permission struct {
+	Resource  string
+	Namespace string
+	Service   string
+	Action    Action
+}
+
+var (
+	// ErrLoginFailed 用户名或者密码错误
+	ErrLoginFailed = errors.New("user name or password is wrong")
+	// ErrInvalidUserToken 用户Token非法或者已经过期
+	ErrInvalidUserToken = errors.New("user token is invalid or expired")
+)
@@ -19,14 +19,20 @@ package auth
 
 import (
 	"strings"
+	"sync"
+
+	"github.com/polarismesh/polaris-server/store"
 )
 
 /**
 * @brief 鉴权数据来源类
  */
 type authority struct {
-	global string
-	open   bool
+	mutex        sync.RWMutex
+	global       string
+	open         bool
+	userRequired bool
+	rbac         *rbac
 }
 
 const (
@@ -39,14 +45,46 @@ var _ Authority = (*authority)(nil)
 
 /**
 * NewAuthority 新建一个缓存类
+* storage用于加载用户、用户组以及角色，为空时只支持Token鉴权
+ */
+func NewAuthority(opt map[string]interface{}, storage store.AuthStore) (Authority, error) {
+	r, err := newRBAC(opt, storage)
+	if err != nil {
+		return nil, err
+	}
+	au := &authority{rbac: r}
+	au.setOptions(opt)
+	return au, nil
+}
+
+/**
+ * UpdateOptions 热更新鉴权配置：开关、全局Token、是否必须登录以及用户Token的有效期
  */
-func NewAuthority(opt map[string]interface{}) (Authority, error) {
+func (a *authority) UpdateOptions(opt map[string]interface{}) {
+	a.rbac.updateOptions(opt)
+	a.setOptions(opt)
+}
+
+// 解析并设置鉴权开关以及全局Token
+func (a *authority) setOptions(opt map[string]interface{}) {
 	global, _ := opt["global-token"].(string)
 	if global == "" {
 		global = globalToken
 	}
-	au := &authority{global: global, open: parseOpen(opt)}
-	return au, nil
+	userRequired, _ := opt["user-required"].(bool)
+
+	a.mutex.Lock()
+	a.global = global
+	a.open = parseOpen(opt)
+	a.userRequired = userRequired
+	a.mutex.Unlock()
+}
+
+// 获取鉴权开关以及全局Token
+func (a *authority) options() (bool, string) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.open, a.global
 }
 
 /**
@@ -67,7 +105,7 @@ func parseOpen(opt map[string]interface{}) bool {
 
 // VerifyToken 检查Token格式是否合法
 func (a *authority) VerifyToken(actualToken string) bool {
-	if !a.open {
+	if open, _ := a.options(); !open {
 		return true
 	}
 	return len(actualToken) > 0
@@ -77,14 +115,14 @@ func (a *authority) VerifyToken(actualToken string) bool {
 * VerifyNamespace 校验命名空间是否合法
  */
 func (a *authority) VerifyNamespace(expectToken string, actualToken string) bool {
-	return (!a.open || a.global == actualToken) || expectToken == actualToken
+	return a.VerifyGlobalToken(actualToken) || expectToken == actualToken
 }
 
 /**
 * VerifyService 校验服务是否合法
  */
 func (a *authority) VerifyService(expectToken string, actualToken string) bool {
-	if !a.open || a.global == actualToken {
+	if a.VerifyGlobalToken(actualToken) {
 		return true
 	}
 
@@ -97,7 +135,7 @@ func (a *authority) VerifyService(expectToken string, actualToken string) bool {
 * VerifyInstance 校验实例是否合法
  */
 func (a *authority) VerifyInstance(expectToken string, actualToken string) bool {
-	if !a.open || a.global == actualToken {
+	if a.VerifyGlobalToken(actualToken) {
 		return true
 	}
 
@@ -110,7 +148,7 @@ func (a *authority) VerifyInstance(expectToken string, actualToken string) bool
  * VerifyRule 校验规则是否合法
  */
 func (a *authority) VerifyRule(expectToken string, actualToken string) bool {
-	if !a.open || a.global == actualToken {
+	if a.VerifyGlobalToken(actualToken) {
 		return true
 	}
 
@@ -123,7 +161,7 @@ func (a *authority) VerifyRule(expectToken string, actualToken string) bool {
  * VerifyMesh 校验网格规则是否合法
  */
 func (a *authority) VerifyMesh(expectToken string, actualToken string) bool {
-	if !a.open || a.global == actualToken {
+	if a.VerifyGlobalToken(actualToken) {
 		return true
 	}
 
@@ -136,7 +174,7 @@ func (a *authority) VerifyMesh(expectToken string, actualToken string) bool {
  * VerifyPlatform 校验平台是否合法
  */
 func (a *authority) VerifyPlatform(expectToken string, actualToken string) bool {
-	if !a.open || a.global == actualToken {
+	if a.VerifyGlobalToken(actualToken) {
 		return true
 	}
 
@@ -145,6 +183,54 @@ func (a *authority) VerifyPlatform(expectToken string, actualToken string) bool
 	return ok
 }
 
+/**
+ * VerifyGlobalToken 校验是否为全局Token
+ */
+func (a *authority) VerifyGlobalToken(actualToken string) bool {
+	open, global := a.options()
+	return !open || global == actualToken
+}
+
+/**
+ * UserRequired 控制台接口是否必须由登录的用户访问
+ */
+func (a *authority) UserRequired() bool {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.open && a.userRequired
+}
+
+/**
+ * Login 校验用户名和密码，返回用户的访问Token
+ */
+func (a *authority) Login(name string, password string) (string, error) {
+	return a.rbac.login(name, password)
+}
+
+/**
+ * ParseUserToken 解析用户的访问Token，返回用户名
+ */
+func (a *authority) ParseUserToken(token string) (string, error) {
+	return a.rbac.parseToken(token)
+}
+
+/**
+ * VerifyUser 校验用户对资源的操作权限，鉴权关闭时全部放通
+ */
+func (a *authority) VerifyUser(user string, permission *Permission) bool {
+	if open, _ := a.options(); !open {
+		return true
+	}
+	return a.rbac.verify(user, permission)
+}
+
+/**
+ * Reload 从存储层重新加载用户、用户组以及角色
+ */
+func (a *authority) Reload() error {
+	return a.rbac.reload()
+}
+
 /**
 * convertToken 将string类型的token转化为map类型
  */
@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	auth "github.com/polarismesh/polaris-server/naming/auth"
 )
 
 // MockAuthority is a mock of Authority interface
@@ -137,3 +138,108 @@ func (mr *MockAuthorityMockRecorder) VerifyMesh(expectToken, actualToken interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMesh",
 		reflect.TypeOf((*MockAuthority)(nil).VerifyMesh), expectToken, actualToken)
 }
+
+// VerifyGlobalToken mocks base method
+func (m *MockAuthority) VerifyGlobalToken(actualToken string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyGlobalToken", actualToken)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// VerifyGlobalToken indicates an expected call of VerifyGlobalToken
+func (mr *MockAuthorityMockRecorder) VerifyGlobalToken(actualToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyGlobalToken",
+		reflect.TypeOf((*MockAuthority)(nil).VerifyGlobalToken), actualToken)
+}
+
+// UserRequired mocks base method
+func (m *MockAuthority) UserRequired() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserRequired")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// UserRequired indicates an expected call of UserRequired
+func (mr *MockAuthorityMockRecorder) UserRequired() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserRequired",
+		reflect.TypeOf((*MockAuthority)(nil).UserRequired))
+}
+
+// Login mocks base method
+func (m *MockAuthority) Login(name, password string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", name, password)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login
+func (mr *MockAuthorityMockRecorder) Login(name, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login",
+		reflect.TypeOf((*MockAuthority)(nil).Login), name, password)
+}
+
+// ParseUserToken mocks base method
+func (m *MockAuthority) ParseUserToken(token string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseUserToken", token)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseUserToken indicates an expected call of ParseUserToken
+func (mr *MockAuthorityMockRecorder) ParseUserToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseUserToken",
+		reflect.TypeOf((*MockAuthority)(nil).ParseUserToken), token)
+}
+
+// VerifyUser mocks base method
+func (m *MockAuthority) VerifyUser(user string, permission *auth.Permission) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyUser", user, permission)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// VerifyUser indicates an expected call of VerifyUser
+func (mr *MockAuthorityMockRecorder) VerifyUser(user, permission interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyUser",
+		reflect.TypeOf((*MockAuthority)(nil).VerifyUser), user, permission)
+}
+
+// Reload mocks base method
+func (m *MockAuthority) Reload() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reload")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reload indicates an expected call of Reload
+func (mr *MockAuthorityMockRecorder) Reload() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reload",
+		reflect.TypeOf((*MockAuthority)(nil).Reload))
+}
+
+// UpdateOptions mocks base method
+func (m *MockAuthority) UpdateOptions(opt map[string]interface{}) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateOptions", opt)
+}
+
+// UpdateOptions indicates an expected call of UpdateOptions
+func (mr *MockAuthorityMockRecorder) UpdateOptions(opt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOptions",
+		reflect.TypeOf((*MockAuthority)(nil).UpdateOptions), opt)
+}
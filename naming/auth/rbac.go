@@ -0,0 +1,360 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/plugin"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+const (
+	// 用户Token默认的有效时间，单位为小时
+	defaultTokenTTL = 24
+	// 默认从存储层重新加载用户、用户组以及角色的间隔，单位为秒
+	defaultReloadInterval = 10
+
+	// 密码摘要的迭代次数
+	passwordIterations = 10000
+	passwordSaltLen    = 16
+	passwordHashPrefix = "pbkdf2-sha256"
+)
+
+/**
+ * @brief 基于角色的访问控制，缓存用户以及用户被授予的权限策略
+ */
+type rbac struct {
+	storage  store.AuthStore
+	secret   []byte
+	tokenTTL time.Duration
+
+	mutex    sync.RWMutex
+	users    map[string]*model.User
+	policies map[string][]*model.AuthPolicy
+}
+
+/**
+ * @brief 新建rbac对象，并且定期从存储层重新加载
+ */
+func newRBAC(opt map[string]interface{}, storage store.AuthStore) (*rbac, error) {
+	r := &rbac{
+		storage:  storage,
+		tokenTTL: time.Duration(parseIntOption(opt, "token-ttl", defaultTokenTTL)) * time.Hour,
+		users:    make(map[string]*model.User),
+		policies: make(map[string][]*model.AuthPolicy),
+	}
+
+	secret, err := parseTokenSecret(opt)
+	if err != nil {
+		log.Errorf("[Auth] parse token-secret err: %s", err.Error())
+		return nil, err
+	}
+	if secret == "" {
+		// 未配置时随机生成，多个server之间的用户Token不通用
+		log.Warnf("[Auth] token-secret is not configured, user token is only valid on this server")
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(buf)
+	}
+	r.secret = []byte(secret)
+
+	if storage == nil {
+		return r, nil
+	}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	interval := parseIntOption(opt, "reload-interval", defaultReloadInterval)
+	if interval > 0 {
+		go r.run(time.Duration(interval) * time.Second)
+	}
+	return r, nil
+}
+
+// 读取用户Token的签名密钥，密钥可能需要通过密码插件解析
+func parseTokenSecret(opt map[string]interface{}) (string, error) {
+	secret, _ := opt["token-secret"].(string)
+	return plugin.ParseSecret(secret)
+}
+
+// 热更新用户Token的有效期以及签名密钥，密钥未配置时保持不变
+func (r *rbac) updateOptions(opt map[string]interface{}) {
+	tokenTTL := time.Duration(parseIntOption(opt, "token-ttl", defaultTokenTTL)) * time.Hour
+	secret, err := parseTokenSecret(opt)
+	if err != nil {
+		log.Errorf("[Auth] parse token-secret err: %s, keep the old secret", err.Error())
+		secret = ""
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.tokenTTL = tokenTTL
+	if secret != "" {
+		r.secret = []byte(secret)
+	}
+}
+
+// 定期重新加载，保证多个server之间的数据一致
+func (r *rbac) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := r.reload(); err != nil {
+			log.Errorf("[Auth] reload users and roles err: %s", err.Error())
+		}
+	}
+}
+
+/**
+ * @brief 从存储层加载用户、用户组以及角色，计算每个用户的权限策略
+ */
+func (r *rbac) reload() error {
+	if r.storage == nil {
+		return nil
+	}
+	users, err := r.storage.GetUsers()
+	if err != nil {
+		return err
+	}
+	groups, err := r.storage.GetUserGroups()
+	if err != nil {
+		return err
+	}
+	roles, err := r.storage.GetRoles()
+	if err != nil {
+		return err
+	}
+
+	groupUsers := make(map[string][]string, len(groups))
+	for _, group := range groups {
+		groupUsers[group.Name] = group.Users
+	}
+	policies := make(map[string][]*model.AuthPolicy)
+	for _, role := range roles {
+		members := make(map[string]bool)
+		for _, user := range role.Users {
+			members[user] = true
+		}
+		for _, group := range role.Groups {
+			for _, user := range groupUsers[group] {
+				members[user] = true
+			}
+		}
+		for user := range members {
+			policies[user] = append(policies[user], role.Policies...)
+		}
+	}
+	userMap := make(map[string]*model.User, len(users))
+	for _, user := range users {
+		userMap[user.Name] = user
+	}
+
+	r.mutex.Lock()
+	r.users = userMap
+	r.policies = policies
+	r.mutex.Unlock()
+	return nil
+}
+
+/**
+ * @brief 校验用户名和密码，生成用户Token
+ * Token由用户名、过期时间以及签名构成，签名包含密码摘要，修改密码后旧的Token失效
+ */
+func (r *rbac) login(name string, password string) (string, error) {
+	r.mutex.RLock()
+	user, ok := r.users[name]
+	tokenTTL := r.tokenTTL
+	r.mutex.RUnlock()
+	if !ok || !CheckPassword(user.Password, password) {
+		return "", ErrLoginFailed
+	}
+
+	expire := strconv.FormatInt(time.Now().Add(tokenTTL).Unix(), 10)
+	payload := name + ":" + expire
+	token := payload + ":" + r.sign(payload, user.Password)
+	return base64.RawURLEncoding.EncodeToString([]byte(token)), nil
+}
+
+/**
+ * @brief 校验用户Token，返回用户名
+ */
+func (r *rbac) parseToken(token string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", ErrInvalidUserToken
+	}
+	// 用户名中可能包含冒号，从后往前解析签名以及过期时间
+	payload := string(raw)
+	pos := strings.LastIndex(payload, ":")
+	if pos < 0 {
+		return "", ErrInvalidUserToken
+	}
+	payload, signature := payload[:pos], payload[pos+1:]
+	pos = strings.LastIndex(payload, ":")
+	if pos < 0 {
+		return "", ErrInvalidUserToken
+	}
+	name := payload[:pos]
+	expire, err := strconv.ParseInt(payload[pos+1:], 10, 64)
+	if err != nil || time.Now().Unix() > expire {
+		return "", ErrInvalidUserToken
+	}
+
+	r.mutex.RLock()
+	user, ok := r.users[name]
+	r.mutex.RUnlock()
+	if !ok {
+		return "", ErrInvalidUserToken
+	}
+	if !hmac.Equal([]byte(r.sign(payload, user.Password)), []byte(signature)) {
+		return "", ErrInvalidUserToken
+	}
+	return name, nil
+}
+
+func (r *rbac) sign(payload string, passwordHash string) string {
+	r.mutex.RLock()
+	mac := hmac.New(sha256.New, r.secret)
+	r.mutex.RUnlock()
+	_, _ = mac.Write([]byte(payload + ":" + passwordHash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+/**
+ * @brief 校验用户是否有指定的权限，任意一条权限策略匹配即可
+ */
+func (r *rbac) verify(user string, permission *Permission) bool {
+	r.mutex.RLock()
+	policies := r.policies[user]
+	r.mutex.RUnlock()
+	for _, policy := range policies {
+		if matchPolicy(policy, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * @brief 判断权限策略是否匹配
+ * 权限需要的命名空间或者服务为空时，表示需要全部命名空间或者服务的权限
+ */
+func matchPolicy(policy *model.AuthPolicy, permission *Permission) bool {
+	if policy == nil {
+		return false
+	}
+	if policy.Resource != ResourceAll && policy.Resource != permission.Resource {
+		return false
+	}
+	if !matchPolicyValue(policy.Namespace, permission.Namespace) ||
+		!matchPolicyValue(policy.Service, permission.Service) {
+		return false
+	}
+	for _, action := range policy.Actions {
+		if action == string(ActionWrite) || action == string(permission.Action) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPolicyValue(policyValue string, value string) bool {
+	if policyValue == "" || policyValue == "*" {
+		return true
+	}
+	return policyValue == value
+}
+
+/**
+ * HashPassword 计算密码的加盐摘要，格式为pbkdf2-sha256$迭代次数$盐$摘要
+ */
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	digest := pbkdf2SHA256([]byte(password), salt, passwordIterations)
+	return fmt.Sprintf("%s$%d$%s$%s", passwordHashPrefix, passwordIterations,
+		hex.EncodeToString(salt), hex.EncodeToString(digest)), nil
+}
+
+/**
+ * CheckPassword 校验密码与摘要是否匹配
+ */
+func CheckPassword(hash string, password string) bool {
+	segments := strings.Split(hash, "$")
+	if len(segments) != 4 || segments[0] != passwordHashPrefix {
+		return false
+	}
+	iterations, err := strconv.Atoi(segments[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	salt, err := hex.DecodeString(segments[2])
+	if err != nil {
+		return false
+	}
+	expect, err := hex.DecodeString(segments[3])
+	if err != nil {
+		return false
+	}
+	digest := pbkdf2SHA256([]byte(password), salt, iterations)
+	return subtle.ConstantTimeCompare(digest, expect) == 1
+}
+
+// PBKDF2算法，摘要长度与sha256一致，只需要计算一个分块
+func pbkdf2SHA256(password []byte, salt []byte, iterations int) []byte {
+	mac := hmac.New(sha256.New, password)
+	_, _ = mac.Write(salt)
+	_, _ = mac.Write([]byte{0, 0, 0, 1})
+	u := mac.Sum(nil)
+	out := make([]byte, len(u))
+	copy(out, u)
+	for i := 1; i < iterations; i++ {
+		mac.Reset()
+		_, _ = mac.Write(u)
+		u = mac.Sum(u[:0])
+		for j := range out {
+			out[j] ^= u[j]
+		}
+	}
+	return out
+}
+
+// 读取整型配置项，不存在时返回默认值
+func parseIntOption(opt map[string]interface{}, key string, defaultValue int) int {
+	value, ok := opt[key].(int)
+	if !ok {
+		return defaultValue
+	}
+	return value
+}
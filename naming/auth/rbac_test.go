@@ -0,0 +1,116 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package auth
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store/mock"
+)
+
+// TestHashPassword 测试密码摘要的生成以及校验
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("polaris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !CheckPassword(hash, "polaris") {
+		t.Fatalf("password should match")
+	}
+	if CheckPassword(hash, "polaris1") || CheckPassword("polaris", "polaris") {
+		t.Fatalf("password should not match")
+	}
+	other, _ := HashPassword("polaris")
+	if other == hash {
+		t.Fatalf("password hash should be salted")
+	}
+}
+
+// TestRBAC 测试用户登录、Token校验以及权限策略的匹配
+func TestRBAC(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	hash, _ := HashPassword("123456")
+	storage := mock.NewMockAuthStore(ctl)
+	storage.EXPECT().GetUsers().Return([]*model.User{
+		{Name: "ns:admin", Password: hash},
+		{Name: "bob", Password: hash},
+	}, nil).AnyTimes()
+	storage.EXPECT().GetUserGroups().Return([]*model.UserGroup{
+		{Name: "ops", Users: []string{"bob"}},
+	}, nil).AnyTimes()
+	storage.EXPECT().GetRoles().Return([]*model.Role{
+		{
+			Name:  "admin",
+			Users: []string{"ns:admin"},
+			Policies: []*model.AuthPolicy{
+				{Resource: ResourceAll, Actions: []string{string(ActionWrite)}},
+			},
+		},
+		{
+			Name:   "reader",
+			Groups: []string{"ops"},
+			Policies: []*model.AuthPolicy{
+				{Resource: ResourceService, Namespace: "Test", Service: "svc", Actions: []string{string(ActionRead)}},
+			},
+		},
+	}, nil).AnyTimes()
+
+	r, err := newRBAC(map[string]interface{}{"token-secret": "secret", "reload-interval": 0}, storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.login("ns:admin", "654321"); err != ErrLoginFailed {
+		t.Fatalf("login with wrong password should fail")
+	}
+	token, err := r.login("ns:admin", "123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, err := r.parseToken(token); err != nil || name != "ns:admin" {
+		t.Fatalf("parse token got %s, %v", name, err)
+	}
+	raw, _ := base64.RawURLEncoding.DecodeString(token)
+	raw[len(raw)-1]++
+	if _, err := r.parseToken(base64.RawURLEncoding.EncodeToString(raw)); err != ErrInvalidUserToken {
+		t.Fatalf("token with wrong signature should be invalid")
+	}
+
+	tests := []struct {
+		user       string
+		permission *Permission
+		allow      bool
+	}{
+		{"ns:admin", &Permission{Resource: ResourceRouting, Namespace: "Prod", Action: ActionWrite}, true},
+		{"bob", &Permission{Resource: ResourceService, Namespace: "Test", Service: "svc", Action: ActionRead}, true},
+		{"bob", &Permission{Resource: ResourceService, Namespace: "Test", Service: "svc", Action: ActionWrite}, false},
+		{"bob", &Permission{Resource: ResourceService, Namespace: "Test", Service: "other", Action: ActionRead}, false},
+		{"bob", &Permission{Resource: ResourceRouting, Namespace: "Test", Service: "svc", Action: ActionRead}, false},
+		{"nobody", &Permission{Resource: ResourceService, Action: ActionRead}, false},
+	}
+	for _, item := range tests {
+		if allow := r.verify(item.user, item.permission); allow != item.allow {
+			t.Fatalf("user(%s) permission(%+v) should be %v", item.user, item.permission, item.allow)
+		}
+	}
+}
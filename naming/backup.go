@@ -0,0 +1,522 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/common/version"
+	"github.com/polarismesh/polaris-server/naming/auth"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// BundleVersion 配置备份文件的格式版本
+	BundleVersion = "v1"
+
+	// BundleFormatJSON JSON格式的备份文件
+	BundleFormatJSON = "json"
+	// BundleFormatYAML YAML格式的备份文件
+	BundleFormatYAML = "yaml"
+
+	// 导出时分页查询的大小
+	exportPageSize = 100
+)
+
+// ErrBackupNotAllowed 没有导出或者导入配置的权限
+var ErrBackupNotAllowed = errors.New("configuration backup is not allowed")
+
+/**
+ * Bundle 配置备份文件，包含北极星的各类配置资源
+ * @note 资源按照依赖顺序导入：命名空间、服务、别名、实例、路由、限流、熔断规则、熔断规则的发布
+ */
+type Bundle struct {
+	Version         string
+	ServerVersion   string
+	ExportTime      string
+	Namespaces      []*api.Namespace
+	Services        []*api.Service
+	Aliases         []*api.ServiceAlias
+	Instances       []*api.Instance
+	Routings        []*api.Routing
+	RateLimits      []*api.Rule
+	CircuitBreakers []*api.CircuitBreaker
+	// 熔断规则与服务的绑定关系
+	CircuitBreakerReleases []*api.ConfigRelease
+}
+
+// 备份文件的序列化结构，资源使用jsonpb序列化，与HTTP接口的格式保持一致
+type bundleJSON struct {
+	Version                string            `json:"version"`
+	ServerVersion          string            `json:"server_version,omitempty"`
+	ExportTime             string            `json:"export_time,omitempty"`
+	Namespaces             []json.RawMessage `json:"namespaces,omitempty"`
+	Services               []json.RawMessage `json:"services,omitempty"`
+	Aliases                []json.RawMessage `json:"aliases,omitempty"`
+	Instances              []json.RawMessage `json:"instances,omitempty"`
+	Routings               []json.RawMessage `json:"routings,omitempty"`
+	RateLimits             []json.RawMessage `json:"rate_limits,omitempty"`
+	CircuitBreakers        []json.RawMessage `json:"circuit_breakers,omitempty"`
+	CircuitBreakerReleases []json.RawMessage `json:"circuit_breaker_releases,omitempty"`
+}
+
+// 序列化字段与备份文件字段的对应关系
+func (b *Bundle) sections(out *bundleJSON) []*bundleSection {
+	return []*bundleSection{
+		{&b.Namespaces, &out.Namespaces},
+		{&b.Services, &out.Services},
+		{&b.Aliases, &out.Aliases},
+		{&b.Instances, &out.Instances},
+		{&b.Routings, &out.Routings},
+		{&b.RateLimits, &out.RateLimits},
+		{&b.CircuitBreakers, &out.CircuitBreakers},
+		{&b.CircuitBreakerReleases, &out.CircuitBreakerReleases},
+	}
+}
+
+type bundleSection struct {
+	// 指向[]*api.Xxx的指针
+	messages interface{}
+	raws     *[]json.RawMessage
+}
+
+/**
+ * MarshalJSON 序列化为JSON
+ */
+func (b *Bundle) MarshalJSON() ([]byte, error) {
+	out := &bundleJSON{
+		Version:       b.Version,
+		ServerVersion: b.ServerVersion,
+		ExportTime:    b.ExportTime,
+	}
+	marshaler := &jsonpb.Marshaler{}
+	for _, section := range b.sections(out) {
+		list := reflect.ValueOf(section.messages).Elem()
+		for i := 0; i < list.Len(); i++ {
+			data, err := marshaler.MarshalToString(list.Index(i).Interface().(proto.Message))
+			if err != nil {
+				return nil, err
+			}
+			*section.raws = append(*section.raws, json.RawMessage(data))
+		}
+	}
+	return json.Marshal(out)
+}
+
+/**
+ * UnmarshalJSON 从JSON反序列化
+ */
+func (b *Bundle) UnmarshalJSON(data []byte) error {
+	in := &bundleJSON{}
+	if err := json.Unmarshal(data, in); err != nil {
+		return err
+	}
+	if in.Version != BundleVersion {
+		return fmt.Errorf("unsupported bundle version: %q", in.Version)
+	}
+	b.Version = in.Version
+	b.ServerVersion = in.ServerVersion
+	b.ExportTime = in.ExportTime
+
+	unmarshaler := &jsonpb.Unmarshaler{AllowUnknownFields: true}
+	for _, section := range b.sections(in) {
+		list := reflect.ValueOf(section.messages).Elem()
+		elemType := list.Type().Elem().Elem()
+		for _, raw := range *section.raws {
+			msg := reflect.New(elemType)
+			if err := unmarshaler.Unmarshal(bytes.NewReader(raw), msg.Interface().(proto.Message)); err != nil {
+				return err
+			}
+			list.Set(reflect.Append(list, msg))
+		}
+	}
+	return nil
+}
+
+/**
+ * EncodeBundle 按照指定的格式序列化备份文件，format为空则使用JSON
+ */
+func EncodeBundle(bundle *Bundle, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "", BundleFormatJSON:
+		return data, nil
+	case BundleFormatYAML:
+		// JSON是YAML的子集，使用MapSlice保持顶层字段的顺序
+		var obj yaml.MapSlice
+		if err := yaml.Unmarshal(data, &obj); err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(obj)
+	default:
+		return nil, fmt.Errorf("unsupported bundle format: %s", format)
+	}
+}
+
+/**
+ * DecodeBundle 按照指定的格式反序列化备份文件，format为空则使用JSON
+ */
+func DecodeBundle(data []byte, format string) (*Bundle, error) {
+	switch format {
+	case "", BundleFormatJSON:
+	case BundleFormatYAML:
+		var obj interface{}
+		if err := yaml.Unmarshal(data, &obj); err != nil {
+			return nil, err
+		}
+		jsonData, err := json.Marshal(yaml2JSONValue(obj))
+		if err != nil {
+			return nil, err
+		}
+		data = jsonData
+	default:
+		return nil, fmt.Errorf("unsupported bundle format: %s", format)
+	}
+
+	bundle := &Bundle{}
+	if err := json.Unmarshal(data, bundle); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// YAML解析出来的map的key为interface{}，转换为JSON可以序列化的结构
+func yaml2JSONValue(value interface{}) interface{} {
+	switch item := value.(type) {
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(item))
+		for k, v := range item {
+			out[fmt.Sprintf("%v", k)] = yaml2JSONValue(v)
+		}
+		return out
+	case []interface{}:
+		for i, v := range item {
+			item[i] = yaml2JSONValue(v)
+		}
+		return item
+	default:
+		return value
+	}
+}
+
+/**
+ * ExportConfig 导出配置，namespaces为空则导出全部命名空间
+ * @note 需要全局Token或者auth资源的读权限
+ *       withToken为true时导出的数据包含token，用于在其他集群恢复后保持鉴权不变，需要auth资源的写权限
+ */
+func (s *Server) ExportConfig(ctx context.Context, namespaces []string, withToken bool) (*Bundle, error) {
+	if !s.verifyAuthManage(ctx, auth.ActionRead) {
+		return nil, ErrBackupNotAllowed
+	}
+	if withToken && !s.verifyAuthManage(ctx, auth.ActionWrite) {
+		return nil, ErrBackupNotAllowed
+	}
+
+	bundle := &Bundle{
+		Version:       BundleVersion,
+		ServerVersion: version.Get(),
+		ExportTime:    time2String(time.Now()),
+	}
+
+	nsList, err := s.exportNamespaces(namespaces)
+	if err != nil {
+		return nil, err
+	}
+	for _, namespace := range nsList {
+		bundle.Namespaces = append(bundle.Namespaces, &api.Namespace{
+			Name:    utils.NewStringValue(namespace.Name),
+			Comment: utils.NewStringValue(namespace.Comment),
+			Owners:  utils.NewStringValue(namespace.Owner),
+			Token:   utils.NewStringValue(namespace.Token),
+		})
+		exporters := []func(string, *Bundle) error{
+			s.exportServices,
+			s.exportAliases,
+			s.exportInstances,
+			s.exportRoutings,
+			s.exportRateLimits,
+			s.exportCircuitBreakers,
+		}
+		for _, exporter := range exporters {
+			if err := exporter(namespace.Name, bundle); err != nil {
+				log.Errorf("[Server][Backup] export namespace(%s) err: %s", namespace.Name, err.Error())
+				return nil, err
+			}
+		}
+	}
+	bundle.CircuitBreakerReleases = filterReleases(bundle.CircuitBreakerReleases, namespaces)
+	if !withToken {
+		bundle.stripTokens()
+	}
+	return bundle, nil
+}
+
+// 去掉命名空间、服务以及熔断规则的token
+func (b *Bundle) stripTokens() {
+	for _, namespace := range b.Namespaces {
+		namespace.Token = nil
+	}
+	for _, service := range b.Services {
+		service.Token = nil
+	}
+	for _, rule := range b.CircuitBreakers {
+		rule.Token = nil
+	}
+}
+
+// 查询需要导出的命名空间
+func (s *Server) exportNamespaces(names []string) ([]*model.Namespace, error) {
+	filter := make(map[string][]string)
+	if len(names) > 0 {
+		filter["name"] = names
+	}
+	var out []*model.Namespace
+	for offset := 0; ; offset += exportPageSize {
+		namespaces, total, err := s.storage.GetNamespaces(filter, offset, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, namespaces...)
+		if len(namespaces) == 0 || uint32(offset+len(namespaces)) >= total {
+			break
+		}
+	}
+	// 获取token需要查询单个命名空间
+	for i, namespace := range out {
+		detail, err := s.storage.GetNamespace(namespace.Name)
+		if err != nil {
+			return nil, err
+		}
+		if detail != nil {
+			out[i] = detail
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// 导出命名空间下的服务，不包括别名
+func (s *Server) exportServices(namespace string, bundle *Bundle) error {
+	for offset := uint32(0); ; offset += exportPageSize {
+		total, services, err := s.storage.GetServices(map[string]string{"namespace": namespace},
+			nil, nil, offset, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, service := range services {
+			// 列表中不包含token，需要查询单个服务
+			detail, err := s.storage.GetService(service.Name, service.Namespace)
+			if err != nil {
+				return err
+			}
+			if detail == nil {
+				continue
+			}
+			out := service2Api(detail)
+			out.Token = utils.NewStringValue(detail.Token)
+			bundle.Services = append(bundle.Services, out)
+		}
+		if len(services) == 0 || offset+uint32(len(services)) >= total {
+			return nil
+		}
+	}
+}
+
+// 导出命名空间下的服务别名
+func (s *Server) exportAliases(namespace string, bundle *Bundle) error {
+	for offset := uint32(0); ; offset += exportPageSize {
+		total, aliases, err := s.storage.GetServiceAliases(map[string]string{"namespace": namespace},
+			offset, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, alias := range aliases {
+			bundle.Aliases = append(bundle.Aliases, &api.ServiceAlias{
+				Service:   utils.NewStringValue(alias.Service),
+				Namespace: utils.NewStringValue(alias.Namespace),
+				Alias:     utils.NewStringValue(alias.Alias),
+				Owners:    utils.NewStringValue(alias.Owner),
+				Comment:   utils.NewStringValue(alias.Comment),
+			})
+		}
+		if len(aliases) == 0 || offset+uint32(len(aliases)) >= total {
+			return nil
+		}
+	}
+}
+
+// 导出命名空间下的服务实例
+func (s *Server) exportInstances(namespace string, bundle *Bundle) error {
+	for offset := uint32(0); ; offset += exportPageSize {
+		total, instances, err := s.storage.GetExpandInstances(map[string]string{"namespace": namespace},
+			map[string]string{}, offset, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, instance := range instances {
+			bundle.Instances = append(bundle.Instances, instance.Proto)
+		}
+		if len(instances) == 0 || offset+uint32(len(instances)) >= total {
+			return nil
+		}
+	}
+}
+
+// 导出命名空间下的路由配置
+func (s *Server) exportRoutings(namespace string, bundle *Bundle) error {
+	for offset := uint32(0); ; offset += exportPageSize {
+		total, routings, err := s.storage.GetRoutingConfigs(map[string]string{"namespace": namespace},
+			offset, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, entry := range routings {
+			routing, err := routingConfig2API(entry.Config, entry.ServiceName, entry.NamespaceName)
+			if err != nil {
+				return err
+			}
+			bundle.Routings = append(bundle.Routings, routing)
+		}
+		if len(routings) == 0 || offset+uint32(len(routings)) >= total {
+			return nil
+		}
+	}
+}
+
+// 导出命名空间下的限流规则
+func (s *Server) exportRateLimits(namespace string, bundle *Bundle) error {
+	for offset := uint32(0); ; offset += exportPageSize {
+		total, rateLimits, err := s.storage.GetExtendRateLimits(map[string]string{"namespace": namespace},
+			offset, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, entry := range rateLimits {
+			rule, err := rateLimit2api(entry.ServiceName, entry.NamespaceName, entry.RateLimit)
+			if err != nil {
+				return err
+			}
+			bundle.RateLimits = append(bundle.RateLimits, rule)
+		}
+		if len(rateLimits) == 0 || offset+uint32(len(rateLimits)) >= total {
+			return nil
+		}
+	}
+}
+
+// 导出命名空间下的熔断规则的全部版本，以及规则的发布关系
+func (s *Server) exportCircuitBreakers(namespace string, bundle *Bundle) error {
+	for offset := uint32(0); ; offset += exportPageSize {
+		details, err := s.storage.ListMasterCircuitBreakers(map[string]string{"namespace": namespace},
+			offset, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, info := range details.CircuitBreakerInfos {
+			if err := s.exportCircuitBreaker(info.CircuitBreaker.ID, bundle); err != nil {
+				return err
+			}
+		}
+		if len(details.CircuitBreakerInfos) == 0 || offset+uint32(len(details.CircuitBreakerInfos)) >= details.Total {
+			return nil
+		}
+	}
+}
+
+// 导出单个熔断规则，master版本在前
+func (s *Server) exportCircuitBreaker(id string, bundle *Bundle) error {
+	versions, err := s.storage.GetCircuitBreakerVersions(id)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(versions, func(i, j int) bool { return versions[i] == Master && versions[j] != Master })
+	for _, ruleVersion := range versions {
+		rule, err := s.storage.GetCircuitBreaker(id, ruleVersion)
+		if err != nil {
+			return err
+		}
+		if rule == nil {
+			continue
+		}
+		out, err := circuitBreaker2API(rule)
+		if err != nil {
+			return err
+		}
+		out.Token = utils.NewStringValue(rule.Token)
+		bundle.CircuitBreakers = append(bundle.CircuitBreakers, out)
+	}
+
+	for offset := uint32(0); ; offset += exportPageSize {
+		details, err := s.storage.ListReleaseCircuitBreakers(map[string]string{"rule_id": id},
+			offset, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, info := range details.CircuitBreakerInfos {
+			for _, service := range info.Services {
+				bundle.CircuitBreakerReleases = append(bundle.CircuitBreakerReleases, &api.ConfigRelease{
+					Service: &api.Service{
+						Name:      utils.NewStringValue(service.Name),
+						Namespace: utils.NewStringValue(service.Namespace),
+					},
+					CircuitBreaker: &api.CircuitBreaker{
+						Id:      utils.NewStringValue(info.CircuitBreaker.ID),
+						Version: utils.NewStringValue(info.CircuitBreaker.Version),
+					},
+				})
+			}
+		}
+		if len(details.CircuitBreakerInfos) == 0 || offset+uint32(len(details.CircuitBreakerInfos)) >= details.Total {
+			return nil
+		}
+	}
+}
+
+// 熔断规则可以发布到其他命名空间的服务，只保留指定命名空间内的服务的发布关系
+func filterReleases(releases []*api.ConfigRelease, namespaces []string) []*api.ConfigRelease {
+	if len(namespaces) == 0 {
+		return releases
+	}
+	allowed := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		allowed[namespace] = true
+	}
+	var out []*api.ConfigRelease
+	for _, release := range releases {
+		if allowed[release.GetService().GetNamespace().GetValue()] {
+			out = append(out, release)
+		}
+	}
+	return out
+}
@@ -0,0 +1,752 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming/auth"
+)
+
+// ConflictPolicy 导入时资源已经存在的处理策略
+type ConflictPolicy string
+
+const (
+	// ConflictSkip 跳过已经存在的资源
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictOverwrite 使用备份文件中的数据覆盖已经存在的资源
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictFail 存在冲突则整个导入失败，不做任何修改
+	ConflictFail ConflictPolicy = "fail"
+)
+
+// 导入的资源类型
+const (
+	KindNamespace             = "namespace"
+	KindService               = "service"
+	KindServiceAlias          = "alias"
+	KindInstance              = "instance"
+	KindRouting               = "routing"
+	KindRateLimit             = "ratelimit"
+	KindCircuitBreaker        = "circuitbreaker"
+	KindCircuitBreakerRelease = "circuitbreaker_release"
+)
+
+// 单个资源的导入动作
+const (
+	ActionCreate    = "create"
+	ActionOverwrite = "overwrite"
+	ActionSkip      = "skip"
+	ActionConflict  = "conflict"
+	ActionError     = "error"
+)
+
+/**
+ * ParseConflictPolicy 解析冲突策略，为空则默认为skip
+ */
+func ParseConflictPolicy(value string) (ConflictPolicy, error) {
+	switch policy := ConflictPolicy(value); policy {
+	case "":
+		return ConflictSkip, nil
+	case ConflictSkip, ConflictOverwrite, ConflictFail:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid conflict policy: %s", value)
+	}
+}
+
+// ImportOptions 导入的参数
+type ImportOptions struct {
+	// 只检查，不做修改
+	DryRun   bool
+	Conflict ConflictPolicy
+	// 只导入指定命名空间的资源，为空则导入全部
+	Namespaces []string
+}
+
+// ImportItem 单个资源的导入结果
+type ImportItem struct {
+	Kind   string `json:"kind"`
+	Key    string `json:"key"`
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	DryRun   bool           `json:"dry_run"`
+	Conflict ConflictPolicy `json:"conflict"`
+	Success  bool           `json:"success"`
+	Summary  map[string]int `json:"summary"`
+	Items    []*ImportItem  `json:"items"`
+}
+
+// 导入的单个操作，apply为空表示不需要修改
+type importOp struct {
+	item  *ImportItem
+	apply func() error
+}
+
+// 导入计划，记录已经存在以及即将创建的资源，用于检查资源之间的依赖
+type importPlan struct {
+	s          *Server
+	ctx        context.Context
+	opt        *ImportOptions
+	namespaces map[string]bool
+	filter     map[string]bool
+	// 服务名 -> 服务，包括已经存在的服务以及即将创建的服务
+	services map[string]*model.Service
+	// 熔断规则id/version -> 是否存在或者即将创建
+	circuitBreakers map[string]bool
+	ops             []*importOp
+}
+
+/**
+ * ImportConfig 导入配置备份文件
+ * @note 需要全局Token或者auth资源的写权限
+ *       先检查所有资源生成导入计划，再按照依赖顺序写入存储层，每次写入都会记录操作历史
+ *       冲突策略为fail时，存在冲突或者错误则不做任何修改
+ */
+func (s *Server) ImportConfig(ctx context.Context, bundle *Bundle, opt *ImportOptions) (*ImportResult, error) {
+	rid := ParseRequestID(ctx)
+	if !s.verifyAuthManage(ctx, auth.ActionWrite) {
+		return nil, ErrBackupNotAllowed
+	}
+	if bundle == nil {
+		return nil, errors.New("bundle is empty")
+	}
+	if opt.Conflict == "" {
+		opt.Conflict = ConflictSkip
+	}
+
+	plan := &importPlan{
+		s:               s,
+		ctx:             ctx,
+		opt:             opt,
+		namespaces:      make(map[string]bool),
+		filter:          make(map[string]bool),
+		services:        make(map[string]*model.Service),
+		circuitBreakers: make(map[string]bool),
+	}
+	for _, namespace := range opt.Namespaces {
+		plan.filter[namespace] = true
+	}
+	if err := plan.build(bundle); err != nil {
+		log.Error(err.Error(), ZapRequestID(rid))
+		return nil, err
+	}
+
+	result := &ImportResult{
+		DryRun:   opt.DryRun,
+		Conflict: opt.Conflict,
+		Success:  true,
+		Summary:  make(map[string]int),
+	}
+	for _, op := range plan.ops {
+		if op.item.Action == ActionConflict || op.item.Action == ActionError {
+			result.Success = false
+		}
+	}
+
+	// 冲突策略为fail时，存在冲突则不做修改
+	if !opt.DryRun && (result.Success || opt.Conflict != ConflictFail) {
+		for _, op := range plan.ops {
+			if op.apply == nil {
+				continue
+			}
+			if err := op.apply(); err != nil {
+				log.Error(fmt.Sprintf("[Server][Backup] import %s(%s) err: %s",
+					op.item.Kind, op.item.Key, err.Error()), ZapRequestID(rid))
+				op.item.Action = ActionError
+				op.item.Error = err.Error()
+				result.Success = false
+				continue
+			}
+			log.Info(fmt.Sprintf("[Server][Backup] import %s(%s), action: %s",
+				op.item.Kind, op.item.Key, op.item.Action), ZapRequestID(rid))
+		}
+	}
+
+	for _, op := range plan.ops {
+		result.Items = append(result.Items, op.item)
+		result.Summary[op.item.Action]++
+	}
+	return result, nil
+}
+
+// 按照依赖顺序生成导入计划
+func (p *importPlan) build(bundle *Bundle) error {
+	builders := []func(*Bundle) error{
+		p.planNamespaces,
+		p.planServices,
+		p.planAliases,
+		p.planInstances,
+		p.planRoutings,
+		p.planRateLimits,
+		p.planCircuitBreakers,
+		p.planReleases,
+	}
+	for _, builder := range builders {
+		if err := builder(bundle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 判断命名空间是否需要导入
+func (p *importPlan) allowed(namespace string) bool {
+	return len(p.filter) == 0 || p.filter[namespace]
+}
+
+// 添加一个操作，existed表示资源已经存在，根据冲突策略决定动作
+func (p *importPlan) add(kind, key string, existed bool, create, overwrite func() error) {
+	item := &ImportItem{Kind: kind, Key: key, Action: ActionCreate}
+	op := &importOp{item: item, apply: create}
+	if existed {
+		op.apply = nil
+		switch p.opt.Conflict {
+		case ConflictOverwrite:
+			item.Action = ActionOverwrite
+			op.apply = overwrite
+		case ConflictFail:
+			item.Action = ActionConflict
+		default:
+			item.Action = ActionSkip
+		}
+	}
+	p.ops = append(p.ops, op)
+}
+
+// 添加一个无法导入的资源
+func (p *importPlan) fail(kind, key string, format string, args ...interface{}) {
+	p.ops = append(p.ops, &importOp{item: &ImportItem{
+		Kind:   kind,
+		Key:    key,
+		Action: ActionError,
+		Error:  fmt.Sprintf(format, args...),
+	}})
+}
+
+// 写入成功后记录操作历史，同时发布配置变更事件
+func (p *importPlan) recorded(write func() error, entries ...func() *model.RecordEntry) func() error {
+	return func() error {
+		if err := write(); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			p.s.RecordHistory(entry())
+		}
+		return nil
+	}
+}
+
+// 依赖的命名空间是否存在
+func (p *importPlan) hasNamespace(name string) (bool, error) {
+	if existed, ok := p.namespaces[name]; ok {
+		return existed, nil
+	}
+	namespace, err := p.s.storage.GetNamespace(name)
+	if err != nil {
+		return false, err
+	}
+	p.namespaces[name] = namespace != nil
+	return namespace != nil, nil
+}
+
+// 查询依赖的服务，返回nil表示服务不存在
+func (p *importPlan) getService(name, namespace string) (*model.Service, error) {
+	key := serviceKey(name, namespace)
+	if service, ok := p.services[key]; ok {
+		return service, nil
+	}
+	service, err := p.s.storage.GetService(name, namespace)
+	if err != nil {
+		return nil, err
+	}
+	p.services[key] = service
+	return service, nil
+}
+
+// 查询依赖的服务，服务不存在或者为别名则记录错误
+func (p *importPlan) getSourceService(kind, key, name, namespace string) (*model.Service, error) {
+	service, err := p.getService(name, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if service == nil {
+		p.fail(kind, key, "service(%s) not found", serviceKey(name, namespace))
+		return nil, nil
+	}
+	if service.IsAlias() {
+		p.fail(kind, key, "service(%s) is an alias", serviceKey(name, namespace))
+		return nil, nil
+	}
+	return service, nil
+}
+
+func (p *importPlan) planNamespaces(bundle *Bundle) error {
+	for _, req := range bundle.Namespaces {
+		name := req.GetName().GetValue()
+		if !p.allowed(name) {
+			continue
+		}
+		if resp := checkCreateNamespace(req); resp != nil {
+			p.fail(KindNamespace, name, "%s", resp.GetInfo().GetValue())
+			continue
+		}
+		old, err := p.s.storage.GetNamespace(name)
+		if err != nil {
+			return err
+		}
+		data := p.s.createNamespaceModel(req)
+		if token := req.GetToken().GetValue(); token != "" {
+			data.Token = token
+		} else if old != nil {
+			// 备份文件中没有token时保持原有的token不变
+			data.Token = old.Token
+		}
+		p.namespaces[name] = true
+		p.add(KindNamespace, name, old != nil, p.recorded(func() error {
+			return p.s.storage.AddNamespace(data)
+		}, func() *model.RecordEntry {
+			return namespaceRecordEntry(p.ctx, req, model.OCreate)
+		}), func() error {
+			if err := p.s.storage.UpdateNamespace(data); err != nil {
+				return err
+			}
+			p.s.RecordHistory(withBefore(namespaceRecordEntry(p.ctx, req, model.OUpdate), namespaceSnapshot(old)))
+			if data.Token == old.Token {
+				return nil
+			}
+			if err := p.s.storage.UpdateNamespaceToken(data.Name, data.Token); err != nil {
+				return err
+			}
+			p.s.RecordHistory(namespaceRecordEntry(p.ctx, req, model.OUpdateToken))
+			return nil
+		})
+	}
+	return nil
+}
+
+func (p *importPlan) planServices(bundle *Bundle) error {
+	for _, req := range bundle.Services {
+		namespace := req.GetNamespace().GetValue()
+		key := serviceKey(req.GetName().GetValue(), namespace)
+		if !p.allowed(namespace) {
+			continue
+		}
+		if resp := checkCreateService(req); resp != nil {
+			p.fail(KindService, key, "%s", resp.GetInfo().GetValue())
+			continue
+		}
+		if ok, err := p.hasNamespace(namespace); err != nil {
+			return err
+		} else if !ok {
+			p.fail(KindService, key, "namespace(%s) not found", namespace)
+			continue
+		}
+		old, err := p.getService(req.GetName().GetValue(), namespace)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.IsAlias() {
+			p.fail(KindService, key, "service is an alias")
+			continue
+		}
+
+		data := p.s.createServiceModel(req)
+		if token := req.GetToken().GetValue(); token != "" {
+			data.Token = token
+		} else if old != nil {
+			// 备份文件中没有token时保持原有的token不变
+			data.Token = old.Token
+		}
+		if old != nil {
+			data.ID = old.ID
+		}
+		p.services[key] = data
+		p.add(KindService, key, old != nil, p.recorded(func() error {
+			return p.s.storage.AddService(data)
+		}, func() *model.RecordEntry {
+			return serviceRecordEntry(p.ctx, req, data, model.OCreate)
+		}), func() error {
+			if err := p.s.storage.UpdateService(data, true); err != nil {
+				return err
+			}
+			p.s.RecordHistory(withBefore(serviceRecordEntry(p.ctx, req, data, model.OUpdate), service2Api(old)))
+			if data.Token == old.Token {
+				return nil
+			}
+			if err := p.s.storage.UpdateServiceToken(data.ID, data.Token, data.Revision); err != nil {
+				return err
+			}
+			p.s.RecordHistory(serviceRecordEntry(p.ctx, req, data, model.OUpdateToken))
+			return nil
+		})
+	}
+	return nil
+}
+
+func (p *importPlan) planAliases(bundle *Bundle) error {
+	for _, req := range bundle.Aliases {
+		namespace := req.GetNamespace().GetValue()
+		key := serviceKey(req.GetAlias().GetValue(), namespace)
+		if !p.allowed(namespace) {
+			continue
+		}
+		if req.GetAlias().GetValue() == "" {
+			p.fail(KindServiceAlias, key, "alias is empty")
+			continue
+		}
+		if resp := checkServiceAliasReqWithNoAuth(req); resp != nil {
+			p.fail(KindServiceAlias, key, "%s", resp.GetInfo().GetValue())
+			continue
+		}
+		service, err := p.getSourceService(KindServiceAlias, key, req.GetService().GetValue(), namespace)
+		if err != nil {
+			return err
+		}
+		if service == nil {
+			continue
+		}
+		old, err := p.getService(req.GetAlias().GetValue(), namespace)
+		if err != nil {
+			return err
+		}
+		if old != nil && !old.IsAlias() {
+			p.fail(KindServiceAlias, key, "alias is a service")
+			continue
+		}
+
+		var data *model.Service
+		if old == nil {
+			var resp *api.Response
+			data, resp = p.s.createServiceAliasModel(req, service, service.Token, req.GetOwners().GetValue())
+			if resp != nil {
+				p.fail(KindServiceAlias, key, "%s", resp.GetInfo().GetValue())
+				continue
+			}
+			p.services[key] = data
+		} else {
+			copied := *old
+			data = &copied
+			data.Reference = service.ID
+			data.Owner = req.GetOwners().GetValue()
+			data.Comment = req.GetComment().GetValue()
+			data.Revision = NewUUID()
+		}
+		record := &api.Service{Name: req.GetAlias(), Namespace: req.GetNamespace()}
+		p.add(KindServiceAlias, key, old != nil, p.recorded(func() error {
+			return p.s.storage.AddService(data)
+		}, func() *model.RecordEntry {
+			return serviceRecordEntry(p.ctx, record, data, model.OCreate)
+		}), p.recorded(func() error {
+			return p.s.storage.UpdateServiceAlias(data, true)
+		}, func() *model.RecordEntry {
+			return serviceRecordEntry(p.ctx, record, data, model.OUpdate)
+		}))
+	}
+	return nil
+}
+
+func (p *importPlan) planInstances(bundle *Bundle) error {
+	for _, req := range bundle.Instances {
+		namespace := req.GetNamespace().GetValue()
+		if !p.allowed(namespace) {
+			continue
+		}
+		key := fmt.Sprintf("%s:%d", req.GetHost().GetValue(), req.GetPort().GetValue())
+		id, resp := checkCreateInstance(req)
+		if resp != nil {
+			p.fail(KindInstance, key, "%s", resp.GetInfo().GetValue())
+			continue
+		}
+		if req.GetId().GetValue() == "" {
+			req.Id = utils.NewStringValue(id)
+		}
+		key = req.GetId().GetValue()
+		service, err := p.getSourceService(KindInstance, key, req.GetService().GetValue(), namespace)
+		if err != nil {
+			return err
+		}
+		if service == nil {
+			continue
+		}
+		old, err := p.s.storage.GetInstance(key)
+		if err != nil {
+			return err
+		}
+
+		data := utils.CreateInstanceModel(service.ID, req)
+		p.add(KindInstance, key, old != nil, p.recorded(func() error {
+			return p.s.storage.AddInstance(data)
+		}, func() *model.RecordEntry {
+			return instanceRecordEntry(p.ctx, service, data, model.OCreate)
+		}), p.recorded(func() error {
+			return p.s.storage.UpdateInstance(data)
+		}, func() *model.RecordEntry {
+			return withBefore(instanceRecordEntry(p.ctx, service, data, model.OUpdate), instanceSnapshot(old))
+		}))
+	}
+	return nil
+}
+
+func (p *importPlan) planRoutings(bundle *Bundle) error {
+	for _, req := range bundle.Routings {
+		namespace := req.GetNamespace().GetValue()
+		key := serviceKey(req.GetService().GetValue(), namespace)
+		if !p.allowed(namespace) {
+			continue
+		}
+		if resp := checkRoutingConfig(req); resp != nil {
+			p.fail(KindRouting, key, "%s", resp.GetInfo().GetValue())
+			continue
+		}
+		service, err := p.getSourceService(KindRouting, key, req.GetService().GetValue(), namespace)
+		if err != nil {
+			return err
+		}
+		if service == nil {
+			continue
+		}
+		old, err := p.s.storage.GetRoutingConfigWithService(req.GetService().GetValue(), namespace)
+		if err != nil {
+			return err
+		}
+
+		data, err := api2RoutingConfig(service.ID, req)
+		if err != nil {
+			p.fail(KindRouting, key, "%s", err.Error())
+			continue
+		}
+		p.add(KindRouting, key, old != nil, p.recorded(func() error {
+			return p.s.storage.CreateRoutingConfig(data)
+		}, func() *model.RecordEntry {
+			return routingRecordEntry(p.ctx, req, data, model.OCreate)
+		}), p.recorded(func() error {
+			return p.s.storage.UpdateRoutingConfig(data)
+		}, func() *model.RecordEntry {
+			return routingRecordEntry(p.ctx, req, data, model.OUpdate)
+		}))
+	}
+	return nil
+}
+
+func (p *importPlan) planRateLimits(bundle *Bundle) error {
+	for _, req := range bundle.RateLimits {
+		namespace := req.GetNamespace().GetValue()
+		if !p.allowed(namespace) {
+			continue
+		}
+		id := req.GetId().GetValue()
+		if id == "" {
+			id = NewUUID()
+		}
+		if resp := checkRateLimitParams(req); resp != nil {
+			p.fail(KindRateLimit, id, "%s", resp.GetInfo().GetValue())
+			continue
+		}
+		if resp := checkRateLimitRuleParams(ParseRequestID(p.ctx), req); resp != nil {
+			p.fail(KindRateLimit, id, "%s", resp.GetInfo().GetValue())
+			continue
+		}
+		service, err := p.getSourceService(KindRateLimit, id, req.GetService().GetValue(), namespace)
+		if err != nil {
+			return err
+		}
+		if service == nil {
+			continue
+		}
+		old, err := p.s.storage.GetRateLimitWithID(id)
+		if err != nil {
+			return err
+		}
+
+		data, err := api2RateLimit(service.ID, "", req)
+		if err != nil {
+			p.fail(KindRateLimit, id, "%s", err.Error())
+			continue
+		}
+		data.ID = id
+		name := req.GetService().GetValue()
+		p.add(KindRateLimit, id, old != nil, p.recorded(func() error {
+			return p.s.storage.CreateRateLimit(data)
+		}, func() *model.RecordEntry {
+			return rateLimitRecordEntry(p.ctx, namespace, name, data, model.OCreate)
+		}), p.recorded(func() error {
+			return p.s.storage.UpdateRateLimit(data)
+		}, func() *model.RecordEntry {
+			return rateLimitRecordEntry(p.ctx, namespace, name, data, model.OUpdate)
+		}))
+	}
+	return nil
+}
+
+func (p *importPlan) planCircuitBreakers(bundle *Bundle) error {
+	for _, req := range bundle.CircuitBreakers {
+		if !p.allowed(req.GetNamespace().GetValue()) {
+			continue
+		}
+		id := req.GetId().GetValue()
+		ruleVersion := req.GetVersion().GetValue()
+		if ruleVersion == "" {
+			ruleVersion = Master
+		}
+		key := circuitBreakerKey(id, ruleVersion)
+		if id == "" {
+			p.fail(KindCircuitBreaker, key, "circuit breaker id is empty")
+			continue
+		}
+		if _, resp := checkCreateCircuitBreaker(req); resp != nil {
+			p.fail(KindCircuitBreaker, key, "%s", resp.GetInfo().GetValue())
+			continue
+		}
+		if ok, err := p.hasNamespace(req.GetNamespace().GetValue()); err != nil {
+			return err
+		} else if !ok {
+			p.fail(KindCircuitBreaker, key, "namespace(%s) not found", req.GetNamespace().GetValue())
+			continue
+		}
+		old, err := p.s.storage.GetCircuitBreaker(id, ruleVersion)
+		if err != nil {
+			return err
+		}
+		if ruleVersion != Master && !p.circuitBreakers[circuitBreakerKey(id, Master)] {
+			master, err := p.s.storage.GetCircuitBreaker(id, Master)
+			if err != nil {
+				return err
+			}
+			if master == nil {
+				p.fail(KindCircuitBreaker, key, "master version not found")
+				continue
+			}
+		}
+
+		data, err := api2CircuitBreaker(req, id, req.GetToken().GetValue(), ruleVersion)
+		if err != nil {
+			p.fail(KindCircuitBreaker, key, "%s", err.Error())
+			continue
+		}
+		if data.Token == "" {
+			// 备份文件中没有token时保持原有的token不变
+			if old != nil {
+				data.Token = old.Token
+			} else {
+				data.Token = NewUUID()
+			}
+		}
+		p.circuitBreakers[key] = true
+
+		// 已经发布的版本不允许修改
+		if ruleVersion != Master {
+			if old != nil && p.opt.Conflict == ConflictOverwrite {
+				p.ops = append(p.ops, &importOp{item: &ImportItem{
+					Kind: KindCircuitBreaker, Key: key, Action: ActionSkip}})
+				continue
+			}
+			p.add(KindCircuitBreaker, key, old != nil, p.recorded(func() error {
+				return p.s.storage.TagCircuitBreaker(data)
+			}, func() *model.RecordEntry {
+				return circuitBreakerRecordEntry(p.ctx, data, model.OCreate)
+			}), nil)
+			continue
+		}
+		p.add(KindCircuitBreaker, key, old != nil, p.recorded(func() error {
+			return p.s.storage.CreateCircuitBreaker(data)
+		}, func() *model.RecordEntry {
+			return circuitBreakerRecordEntry(p.ctx, data, model.OCreate)
+		}), p.recorded(func() error {
+			return p.s.storage.UpdateCircuitBreaker(data)
+		}, func() *model.RecordEntry {
+			return circuitBreakerRecordEntry(p.ctx, data, model.OUpdate)
+		}))
+	}
+	return nil
+}
+
+func (p *importPlan) planReleases(bundle *Bundle) error {
+	for _, req := range bundle.CircuitBreakerReleases {
+		name := req.GetService().GetName().GetValue()
+		namespace := req.GetService().GetNamespace().GetValue()
+		if !p.allowed(namespace) {
+			continue
+		}
+		ruleID := req.GetCircuitBreaker().GetId().GetValue()
+		ruleVersion := req.GetCircuitBreaker().GetVersion().GetValue()
+		key := fmt.Sprintf("%s@%s", circuitBreakerKey(ruleID, ruleVersion), serviceKey(name, namespace))
+
+		service, err := p.getSourceService(KindCircuitBreakerRelease, key, name, namespace)
+		if err != nil {
+			return err
+		}
+		if service == nil {
+			continue
+		}
+		if !p.circuitBreakers[circuitBreakerKey(ruleID, ruleVersion)] {
+			rule, err := p.s.storage.GetCircuitBreaker(ruleID, ruleVersion)
+			if err != nil {
+				return err
+			}
+			if rule == nil {
+				p.fail(KindCircuitBreakerRelease, key, "circuit breaker(%s) not found",
+					circuitBreakerKey(ruleID, ruleVersion))
+				continue
+			}
+		}
+		old, err := p.s.storage.GetCircuitBreakersByService(name, namespace)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.ID == ruleID && old.Version == ruleVersion {
+			p.ops = append(p.ops, &importOp{item: &ImportItem{
+				Kind: KindCircuitBreakerRelease, Key: key, Action: ActionSkip}})
+			continue
+		}
+
+		relation := api2CircuitBreakerRelation(service.ID, ruleID, ruleVersion)
+		released := func() *model.RecordEntry {
+			return circuitBreakerReleaseRecordEntry(p.ctx, service, ruleID, ruleVersion, model.ORelease)
+		}
+		p.add(KindCircuitBreakerRelease, key, old != nil, p.recorded(func() error {
+			return p.s.storage.ReleaseCircuitBreaker(relation)
+		}, released), p.recorded(func() error {
+			// 服务只能绑定一个熔断规则，先解绑旧的规则
+			if err := p.s.storage.UnbindCircuitBreaker(service.ID, old.ID, old.Version); err != nil {
+				return err
+			}
+			p.s.RecordHistory(circuitBreakerReleaseRecordEntry(p.ctx, service, old.ID, old.Version, model.OUnbind))
+			return p.s.storage.ReleaseCircuitBreaker(relation)
+		}, released))
+	}
+	return nil
+}
+
+func serviceKey(name, namespace string) string {
+	return namespace + "/" + name
+}
+
+func circuitBreakerKey(id, version string) string {
+	return id + "/" + version
+}
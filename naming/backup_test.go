@@ -0,0 +1,230 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming/auth"
+	authmock "github.com/polarismesh/polaris-server/naming/auth/mock"
+	"github.com/polarismesh/polaris-server/plugin"
+	"github.com/polarismesh/polaris-server/store/mock"
+)
+
+// 记录操作历史的history插件
+type testHistory struct {
+	entries []*model.RecordEntry
+}
+
+func (h *testHistory) Name() string                           { return "test" }
+func (h *testHistory) Initialize(c *plugin.ConfigEntry) error { return nil }
+func (h *testHistory) Destroy() error                         { return nil }
+func (h *testHistory) Record(entry *model.RecordEntry)        { h.entries = append(h.entries, entry) }
+
+func newTestBundle() *Bundle {
+	return &Bundle{
+		Version:    BundleVersion,
+		ExportTime: "2021-01-01 00:00:00",
+		Namespaces: []*api.Namespace{{
+			Name:  utils.NewStringValue("Test"),
+			Token: utils.NewStringValue("ns-token"),
+		}},
+		Services: []*api.Service{{
+			Name:      utils.NewStringValue("svc"),
+			Namespace: utils.NewStringValue("Test"),
+			Metadata:  map[string]string{"env": "prod"},
+			Token:     utils.NewStringValue("svc-token"),
+		}},
+		Instances: []*api.Instance{{
+			Service:   utils.NewStringValue("svc"),
+			Namespace: utils.NewStringValue("Test"),
+			Host:      utils.NewStringValue("127.0.0.1"),
+			Port:      utils.NewUInt32Value(8080),
+			Weight:    utils.NewUInt32Value(100),
+			HealthCheck: &api.HealthCheck{
+				Type:      api.HealthCheck_HEARTBEAT,
+				Heartbeat: &api.HeartbeatHealthCheck{Ttl: utils.NewUInt32Value(5)},
+			},
+		}},
+		CircuitBreakerReleases: []*api.ConfigRelease{{
+			Service: &api.Service{
+				Name:      utils.NewStringValue("svc"),
+				Namespace: utils.NewStringValue("Test"),
+			},
+			CircuitBreaker: &api.CircuitBreaker{
+				Id:      utils.NewStringValue("rule-id"),
+				Version: utils.NewStringValue("v1"),
+			},
+		}},
+	}
+}
+
+// TestEncodeBundle 测试备份文件的序列化以及反序列化
+func TestEncodeBundle(t *testing.T) {
+	for _, format := range []string{BundleFormatJSON, BundleFormatYAML} {
+		bundle := newTestBundle()
+		data, err := EncodeBundle(bundle, format)
+		if err != nil {
+			t.Fatalf("format(%s) encode error: %s", format, err.Error())
+		}
+		out, err := DecodeBundle(data, format)
+		if err != nil {
+			t.Fatalf("format(%s) decode error: %s\n%s", format, err.Error(), string(data))
+		}
+		if out.Version != BundleVersion || out.ExportTime != bundle.ExportTime {
+			t.Fatalf("format(%s) header not match: %+v", format, out)
+		}
+		if len(out.Services) != 1 || !proto.Equal(out.Services[0], bundle.Services[0]) {
+			t.Fatalf("format(%s) services not match: %+v", format, out.Services)
+		}
+		if len(out.Instances) != 1 || !proto.Equal(out.Instances[0], bundle.Instances[0]) {
+			t.Fatalf("format(%s) instances not match: %+v", format, out.Instances)
+		}
+		if len(out.CircuitBreakerReleases) != 1 ||
+			!proto.Equal(out.CircuitBreakerReleases[0], bundle.CircuitBreakerReleases[0]) {
+			t.Fatalf("format(%s) releases not match: %+v", format, out.CircuitBreakerReleases)
+		}
+	}
+}
+
+// TestDecodeBundle_Invalid 测试非法的备份文件
+func TestDecodeBundle_Invalid(t *testing.T) {
+	if _, err := DecodeBundle([]byte(`{"version": "v0"}`), BundleFormatJSON); err == nil ||
+		!strings.Contains(err.Error(), "version") {
+		t.Fatalf("unknown version should fail")
+	}
+	if _, err := DecodeBundle([]byte(`{"version": "v1"}`), "xml"); err == nil {
+		t.Fatalf("unknown format should fail")
+	}
+	if _, err := DecodeBundle([]byte(`{"version": "v1", "services": [{"name": 1}]}`), ""); err == nil {
+		t.Fatalf("invalid service should fail")
+	}
+}
+
+// TestFilterReleases 测试按照命名空间过滤熔断规则的发布关系
+func TestFilterReleases(t *testing.T) {
+	releases := newTestBundle().CircuitBreakerReleases
+	if len(filterReleases(releases, nil)) != 1 {
+		t.Fatalf("releases should not be filtered")
+	}
+	if len(filterReleases(releases, []string{"Production"})) != 0 {
+		t.Fatalf("releases should be filtered")
+	}
+	if _, err := ParseConflictPolicy("replace"); err == nil {
+		t.Fatalf("invalid conflict policy should fail")
+	}
+}
+
+// TestStripTokens 测试导出时去掉token
+func TestStripTokens(t *testing.T) {
+	bundle := newTestBundle()
+	bundle.CircuitBreakers = []*api.CircuitBreaker{{Token: utils.NewStringValue("cb-token")}}
+	bundle.stripTokens()
+	if bundle.Namespaces[0].GetToken() != nil || bundle.Services[0].GetToken() != nil ||
+		bundle.CircuitBreakers[0].GetToken() != nil {
+		t.Fatalf("tokens should be stripped")
+	}
+}
+
+// TestBackup_NotAllowed 测试没有权限时拒绝导出以及导入
+func TestBackup_NotAllowed(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	authority := authmock.NewMockAuthority(ctl)
+	authority.EXPECT().VerifyGlobalToken("bad-token").Return(false).Times(2)
+	authority.EXPECT().VerifyGlobalToken("global-token").Return(true)
+	authority.EXPECT().VerifyUser("reader", gomock.Any()).DoAndReturn(
+		func(user string, permission *auth.Permission) bool { return permission.Action == auth.ActionRead }).Times(2)
+	s := &Server{authority: authority}
+
+	ctx := context.WithValue(context.Background(), utils.StringContext("polaris-token"), "bad-token")
+	if _, err := s.ExportConfig(ctx, nil, false); err != ErrBackupNotAllowed {
+		t.Fatalf("export should not be allowed, err: %v", err)
+	}
+	if _, err := s.ImportConfig(ctx, newTestBundle(), &ImportOptions{}); err != ErrBackupNotAllowed {
+		t.Fatalf("import should not be allowed, err: %v", err)
+	}
+
+	// 只有读权限的用户不能导出token
+	ctx = context.WithValue(context.Background(), utils.StringContext("user"), "reader")
+	if _, err := s.ExportConfig(ctx, nil, true); err != ErrBackupNotAllowed {
+		t.Fatalf("export with token should not be allowed, err: %v", err)
+	}
+	ctx = context.WithValue(context.Background(), utils.StringContext("polaris-token"), "global-token")
+	if _, err := s.ImportConfig(ctx, nil, &ImportOptions{}); err == nil || err == ErrBackupNotAllowed {
+		t.Fatalf("import with global token should be allowed, err: %v", err)
+	}
+}
+
+// TestImportConfig_KeepToken 测试覆盖时保持原有的token，并且记录操作历史
+func TestImportConfig_KeepToken(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	authority := authmock.NewMockAuthority(ctl)
+	authority.EXPECT().VerifyGlobalToken(gomock.Any()).Return(true)
+	storage := mock.NewMockStore(ctl)
+	storage.EXPECT().GetNamespace("Test").Return(&model.Namespace{Name: "Test", Owner: "polaris",
+		Token: "ns-token"}, nil)
+	storage.EXPECT().GetService("svc", "Test").Return(&model.Service{ID: "svc-id", Name: "svc",
+		Namespace: "Test", Owner: "polaris", Token: "svc-token"}, nil)
+	storage.EXPECT().UpdateNamespace(gomock.Any()).DoAndReturn(func(namespace *model.Namespace) error {
+		if namespace.Token != "ns-token" {
+			t.Fatalf("namespace token should be kept, got %s", namespace.Token)
+		}
+		return nil
+	})
+	storage.EXPECT().UpdateService(gomock.Any(), true).DoAndReturn(func(service *model.Service, _ bool) error {
+		if service.ID != "svc-id" || service.Token != "svc-token" {
+			t.Fatalf("service token should be kept, got %s", service.Token)
+		}
+		return nil
+	})
+	history := &testHistory{}
+	s := &Server{authority: authority, storage: storage, history: history}
+
+	bundle := &Bundle{
+		Version: BundleVersion,
+		Namespaces: []*api.Namespace{{
+			Name:   utils.NewStringValue("Test"),
+			Owners: utils.NewStringValue("polaris"),
+		}},
+		Services: []*api.Service{{
+			Name:      utils.NewStringValue("svc"),
+			Namespace: utils.NewStringValue("Test"),
+			Owners:    utils.NewStringValue("polaris"),
+		}},
+	}
+	result, err := s.ImportConfig(context.Background(), bundle, &ImportOptions{Conflict: ConflictOverwrite})
+	if err != nil {
+		t.Fatalf("import err: %s", err.Error())
+	}
+	if !result.Success || result.Summary[ActionOverwrite] != 2 {
+		t.Fatalf("unexpected import result: %+v", result)
+	}
+	if len(history.entries) != 2 || history.entries[0].ResourceType != model.RNamespace ||
+		history.entries[1].ResourceType != model.RService {
+		t.Fatalf("import should record history, got %d entries", len(history.entries))
+	}
+}
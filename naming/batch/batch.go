@@ -31,6 +31,7 @@ import (
 type Controller struct {
 	register   *InstanceCtrl
 	deregister *InstanceCtrl
+	cancel     context.CancelFunc
 }
 
 // NewBatchCtrlWithConfig 根据配置文件创建一个批量控制器
@@ -63,6 +64,7 @@ func NewBatchCtrlWithConfig(storage store.Store, authority auth.Authority, auth
 // Start 开启批量控制器
 // 启动多个协程，接受外部create/delete请求
 func (bc *Controller) Start(ctx context.Context) {
+	ctx, bc.cancel = context.WithCancel(ctx)
 	if bc.CreateInstanceOpen() {
 		bc.register.Start(ctx)
 	}
@@ -71,6 +73,13 @@ func (bc *Controller) Start(ctx context.Context) {
 	}
 }
 
+// Stop 停止批量控制器，队列中未处理的请求返回失败
+func (bc *Controller) Stop() {
+	if bc.cancel != nil {
+		bc.cancel()
+	}
+}
+
 // CreateInstanceOpen 创建是否开启
 func (bc *Controller) CreateInstanceOpen() bool {
 	return bc.register != nil
@@ -81,6 +90,22 @@ func (bc *Controller) DeleteInstanceOpen() bool {
 	return bc.deregister != nil
 }
 
+// CreateInstanceQueueLen 获取注册请求队列中等待处理的请求数
+func (bc *Controller) CreateInstanceQueueLen() int {
+	if bc.register == nil {
+		return 0
+	}
+	return len(bc.register.queue)
+}
+
+// DeleteInstanceQueueLen 获取反注册请求队列中等待处理的请求数
+func (bc *Controller) DeleteInstanceQueueLen() int {
+	if bc.deregister == nil {
+		return 0
+	}
+	return len(bc.deregister.queue)
+}
+
 // AsyncCreateInstance 异步创建实例，返回一个future，根据future获取创建结果
 func (bc *Controller) AsyncCreateInstance(instance *api.Instance, platformID, platformToken string) *InstanceFuture {
 	future := &InstanceFuture{
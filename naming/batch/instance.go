@@ -160,6 +160,7 @@ func (ctrl *InstanceCtrl) mainLoop(ctx context.Context) {
 			case <-ticker.C:
 				triggerConsume(futures[0:idx])
 			case <-ctx.Done():
+				ctrl.discard(futures[0:idx])
 				log.Infof("[Batch] %s main loop exited", ctrl.label)
 				return
 			}
@@ -167,6 +168,20 @@ func (ctrl *InstanceCtrl) mainLoop(ctx context.Context) {
 	}()
 }
 
+// 批量控制器停止时，答复尚未处理的请求
+func (ctrl *InstanceCtrl) discard(futures []*InstanceFuture) {
+	err := fmt.Errorf("batch %s controller is stopped", ctrl.label)
+	SendReply(futures, api.ExecuteException, err)
+	for {
+		select {
+		case future := <-ctrl.queue:
+			future.Reply(api.ExecuteException, err)
+		default:
+			return
+		}
+	}
+}
+
 // store写协程的主循环
 // 从chan中获取数据，直接写数据库
 // 每次写完，设置协程为空闲
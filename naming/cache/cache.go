@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/polarismesh/polaris-server/common/log"
@@ -65,8 +66,20 @@ type Cache interface {
 const (
 	// UpdateCacheInterval 缓存更新时间间隔
 	UpdateCacheInterval = time.Second
+	// DefaultSweepInterval 存储层能够通知所有的数据变更时，默认的全量轮询间隔
+	DefaultSweepInterval = 30 * time.Second
 )
 
+// 数据变更的资源与缓存的对应关系
+var changeResource2Cache = map[store.ChangeResource]int{
+	store.ChangeService:        CacheService,
+	store.ChangeInstance:       CacheInstance,
+	store.ChangeRoutingConfig:  CacheRoutingConfig,
+	store.ChangeL5:             CacheCL5,
+	store.ChangeRateLimit:      CacheRateLimit,
+	store.ChangeCircuitBreaker: CacheCircuitBreaker,
+}
+
 const (
 	// RevisionConcurrenceCount Revision计算的并发线程数
 	RevisionConcurrenceCount = 64
@@ -97,6 +110,15 @@ type NamingCache struct {
 
 	comRevisionCh chan *revisionNotify
 	revisions     *sync.Map
+	lastUpdates   *sync.Map
+
+	// 发生变更待更新的缓存，按照缓存序号标记
+	changed  []int32
+	changeCh chan struct{}
+
+	// 缓存数据可能发生变化时关闭并替换，用于唤醒等待数据变化的订阅者
+	notifyMutex sync.Mutex
+	notifyCh    chan struct{}
 }
 
 /**
@@ -108,6 +130,10 @@ func NewNamingCache(storage store.Store) (*NamingCache, error) {
 		caches:        make([]Cache, CacheLast),
 		comRevisionCh: make(chan *revisionNotify, RevisionChanCount),
 		revisions:     new(sync.Map),
+		lastUpdates:   new(sync.Map),
+		changed:       make([]int32, CacheLast),
+		changeCh:      make(chan struct{}, 1),
+		notifyCh:      make(chan struct{}),
 	}
 
 	sc := newServiceCache(storage, nc.comRevisionCh)
@@ -155,16 +181,39 @@ func (nc *NamingCache) initialize() error {
  * update 缓存更新
  */
 func (nc *NamingCache) update() error {
+	return nc.updateCaches(func(index int) bool {
+		return true
+	})
+}
+
+/**
+ * updateChanged 只更新发生了变更的缓存
+ */
+func (nc *NamingCache) updateChanged() error {
+	return nc.updateCaches(func(index int) bool {
+		return atomic.SwapInt32(&nc.changed[index], 0) == 1
+	})
+}
+
+/**
+ * updateCaches 并发更新filter选中的缓存
+ */
+func (nc *NamingCache) updateCaches(filter func(index int) bool) error {
 	var wg sync.WaitGroup
 	for _, entry := range config.Resources {
 		index, exist := cacheSet[entry.Name]
 		if !exist {
 			return fmt.Errorf("cache resource %s not exists", entry.Name)
 		}
+		if !filter(index) {
+			continue
+		}
 		wg.Add(1)
 		go func(c Cache) {
 			defer wg.Done()
-			_ = c.update()
+			if err := c.update(); err == nil {
+				nc.lastUpdates.Store(c.name(), time.Now()) // string -> time.Time
+			}
 		}(nc.caches[index])
 	}
 
@@ -193,6 +242,16 @@ func (nc *NamingCache) Start(ctx context.Context) error {
 	// 先启动revision计算协程
 	go nc.revisionWorker(ctx)
 
+	// 先订阅数据变更，避免遗漏首次更新期间的写入
+	sweepInterval := nc.GetUpdateCacheInterval()
+	if nc.storage.WatchChanges(nc.onChange) {
+		sweepInterval = DefaultSweepInterval
+		if config != nil && config.SweepInterval > 0 {
+			sweepInterval = time.Duration(config.SweepInterval) * time.Second
+		}
+	}
+	log.Infof("[Cache] cache update on changes, sweep interval: %v", sweepInterval)
+
 	// 启动的时候，先更新一版缓存
 	log.Infof("[Cache] cache update now first time")
 	if err := nc.update(); err != nil {
@@ -200,15 +259,19 @@ func (nc *NamingCache) Start(ctx context.Context) error {
 	}
 	log.Infof("[Cache] cache update done")
 
-	// 启动协程，开始定时更新缓存数据
+	// 启动协程，收到变更通知时更新对应的缓存，并定时全量更新保证一致
 	go func() {
-		ticker := time.NewTicker(nc.GetUpdateCacheInterval())
+		ticker := time.NewTicker(sweepInterval)
 		defer ticker.Stop()
 
 		for {
 			select {
+			case <-nc.changeCh:
+				_ = nc.updateChanged()
+				nc.notifyChanged()
 			case <-ticker.C:
 				_ = nc.update()
+				nc.notifyChanged()
 			case <-ctx.Done():
 				return
 			}
@@ -218,6 +281,39 @@ func (nc *NamingCache) Start(ctx context.Context) error {
 	return nil
 }
 
+/**
+ * onChange 存储层的数据变更回调，标记对应的缓存并唤醒更新协程
+ */
+func (nc *NamingCache) onChange(resource store.ChangeResource) {
+	index, ok := changeResource2Cache[resource]
+	if !ok {
+		return
+	}
+	atomic.StoreInt32(&nc.changed[index], 1)
+	select {
+	case nc.changeCh <- struct{}{}:
+	default:
+	}
+}
+
+/**
+ * Changed 获取缓存数据变化的通知，缓存更新或者服务实例的revision重新计算后，返回的管道会被关闭
+ * 订阅者需要在读取数据之前获取管道，避免遗漏读取期间的变化
+ */
+func (nc *NamingCache) Changed() <-chan struct{} {
+	nc.notifyMutex.Lock()
+	defer nc.notifyMutex.Unlock()
+	return nc.notifyCh
+}
+
+// 唤醒所有等待缓存数据变化的订阅者
+func (nc *NamingCache) notifyChanged() {
+	nc.notifyMutex.Lock()
+	close(nc.notifyCh)
+	nc.notifyCh = make(chan struct{})
+	nc.notifyMutex.Unlock()
+}
+
 /**
  * Clear 主动清除缓存数据
  */
@@ -270,6 +366,7 @@ func (nc *NamingCache) processRevisionWorker(req *revisionNotify) bool {
 	if !req.valid {
 		// log.Infof("[Cache][Revision] service(%s) revision has all been removed", req.serviceID)
 		nc.revisions.Delete(req.serviceID)
+		nc.notifyChanged()
 		return true
 	}
 
@@ -285,7 +382,11 @@ func (nc *NamingCache) processRevisionWorker(req *revisionNotify) bool {
 		log.Errorf("[Cache] compute service id(%s) instances revision err: %s", req.serviceID, err.Error())
 		return false
 	}
+	old, ok := nc.revisions.Load(req.serviceID)
 	nc.revisions.Store(req.serviceID, revision) // string -> string
+	if !ok || old.(string) != revision {
+		nc.notifyChanged()
+	}
 	return true
 }
 
@@ -294,6 +395,19 @@ func (nc *NamingCache) GetUpdateCacheInterval() time.Duration {
 	return UpdateCacheInterval
 }
 
+/**
+ * GetLastUpdateTimes 获取每个缓存最近一次成功更新的时间
+ */
+func (nc *NamingCache) GetLastUpdateTimes() map[string]time.Time {
+	out := make(map[string]time.Time)
+	nc.lastUpdates.Range(func(key, value interface{}) bool {
+		out[key.(string)] = value.(time.Time)
+		return true
+	})
+
+	return out
+}
+
 /**
  * GetServiceInstanceRevision 获取服务实例计算之后的revision
  */
@@ -27,6 +27,7 @@ import (
 	v1 "github.com/polarismesh/polaris-server/common/api/v1"
 	"github.com/polarismesh/polaris-server/common/model"
 	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/store"
 	"github.com/polarismesh/polaris-server/store/mock"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -82,6 +83,7 @@ func TestNamingCache_Start(t *testing.T) {
 		storage.EXPECT().GetRateLimitsForCache(beg, false).Return(nil, nil, nil).MaxTimes(3)
 		storage.EXPECT().GetCircuitBreakerForCache(beg, true).Return(nil, nil).MaxTimes(1)
 		storage.EXPECT().GetCircuitBreakerForCache(beg, false).Return(nil, nil).MaxTimes(3)
+		storage.EXPECT().WatchChanges(gomock.Any()).Return(false)
 
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
@@ -97,6 +99,54 @@ func TestNamingCache_Start(t *testing.T) {
 	})
 }
 
+// TestNamingCache_ChangeNotify 测试收到变更通知时只更新对应的缓存
+func TestNamingCache_ChangeNotify(t *testing.T) {
+	ctl := gomock.NewController(t)
+	storage := mock.NewMockStore(ctl)
+	defer ctl.Finish()
+
+	SetCacheConfig(&Config{
+		Open:      true,
+		Resources: []ConfigEntry{{Name: "service"}, {Name: "instance"}},
+	})
+
+	Convey("变更通知只触发实例缓存的更新", t, func() {
+		c, err := NewNamingCache(storage)
+		So(err, ShouldBeNil)
+
+		var listener store.ChangeListener
+		storage.EXPECT().WatchChanges(gomock.Any()).DoAndReturn(func(l store.ChangeListener) bool {
+			listener = l
+			return true
+		})
+		beg := time.Unix(0, 0).Add(DefaultTimeDiff)
+		storage.EXPECT().GetMoreServices(beg, true, false, false).Return(nil, nil).Times(1)
+		storage.EXPECT().GetMoreInstances(beg, true, false, nil).Return(nil, nil).Times(1)
+		storage.EXPECT().GetMoreInstances(beg, false, false, nil).Return(nil, nil).Times(1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		So(c.Start(ctx), ShouldBeNil)
+		So(listener, ShouldNotBeNil)
+
+		// 与缓存无关的资源不会触发更新
+		changed := c.Changed()
+		listener(store.ChangeResource("namespace"))
+		listener(store.ChangeInstance)
+		time.Sleep(200 * time.Millisecond)
+
+		// 更新完成后唤醒等待数据变化的订阅者
+		notified := false
+		select {
+		case <-changed:
+			notified = true
+		default:
+		}
+		So(notified, ShouldBeTrue)
+		So(c.Changed(), ShouldNotEqual, changed)
+	})
+}
+
 // TestRevisionWorker 测试revision的管道是否正常
 func TestRevisionWorker(t *testing.T) {
 	ctl := gomock.NewController(t)
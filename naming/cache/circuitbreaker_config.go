@@ -37,6 +37,9 @@ type CircuitBreakerCache interface {
 
 	// GetCircuitBreakerConfig 根据ServiceID获取熔断配置
 	GetCircuitBreakerConfig(id string) *model.ServiceWithCircuitBreaker
+
+	// GetCircuitBreakerCount 遍历熔断配置
+	GetCircuitBreakerCount(f func(k, v interface{}) bool)
 }
 
 /**
@@ -23,6 +23,8 @@ package cache
 type Config struct {
 	Open      bool `yaml:"open"`
 	Resources []ConfigEntry
+	// 存储层能够通知所有server的数据变更时，全量轮询的间隔，单位秒
+	SweepInterval int `yaml:"sweepInterval"`
 }
 
 /*
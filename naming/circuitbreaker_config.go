@@ -21,11 +21,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	api "github.com/polarismesh/polaris-server/common/api/v1"
 	"github.com/polarismesh/polaris-server/common/log"
 	"github.com/polarismesh/polaris-server/common/model"
 	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming/auth"
 	"go.uber.org/zap"
 )
 
@@ -98,6 +100,12 @@ func (s *Server) CreateCircuitBreaker(ctx context.Context, req *api.CircuitBreak
 		return resp
 	}
 
+	// 带有登录用户时校验用户的角色权限
+	namespace := req.GetNamespace().GetValue()
+	if found, ok := s.verifyAuthByUser(ctx, auth.ResourceCircuitBreaker, namespace, ""); found && !ok {
+		return api.NewCircuitBreakerResponse(api.Unauthorized, req)
+	}
+
 	// 生成version
 	version := Master
 
@@ -130,8 +138,7 @@ func (s *Server) CreateCircuitBreaker(ctx context.Context, req *api.CircuitBreak
 	msg := fmt.Sprintf("create circuit breaker: id=%v, version=%v, name=%v, namespace=%v",
 		data.ID, data.Version, data.Name, data.Namespace)
 	log.Info(msg, ZapRequestID(requestID))
-
-	// todo 记录操作记录
+	s.RecordHistory(circuitBreakerRecordEntry(ctx, data, model.OCreate))
 
 	// 返回请求结果
 	req.Id = utils.NewStringValue(data.ID)
@@ -224,8 +231,7 @@ func (s *Server) CreateCircuitBreakerVersion(ctx context.Context, req *api.Circu
 	msg := fmt.Sprintf("tag circuit breaker: id=%v, version=%v, name=%v, namespace=%v",
 		data.ID, data.Version, data.Name, data.Namespace)
 	log.Info(msg, ZapRequestID(requestID))
-
-	// todo 记录操作记录
+	s.RecordHistory(circuitBreakerRecordEntry(ctx, data, model.OCreate))
 
 	return api.NewCircuitBreakerResponse(api.ExecuteSuccess, req)
 }
@@ -259,18 +265,24 @@ func (s *Server) DeleteCircuitBreaker(ctx context.Context, req *api.CircuitBreak
 	}
 
 	// 检查熔断规则是否存在并鉴权
-	if _, resp := s.checkCircuitBreakerValid(ctx, req, id, req.GetVersion().GetValue()); resp != nil {
+	circuitBreaker, resp := s.checkCircuitBreakerValid(ctx, req, id, req.GetVersion().GetValue())
+	if resp != nil {
 		if resp.GetCode().GetValue() == api.NotFoundCircuitBreaker {
 			return api.NewCircuitBreakerResponse(api.ExecuteSuccess, req)
 		}
 		return resp
 	}
 
+	var ret *api.Response
 	if req.GetVersion().GetValue() == Master {
-		return s.deleteMasterCircuitBreaker(requestID, id, req)
+		ret = s.deleteMasterCircuitBreaker(requestID, id, req)
+	} else {
+		ret = s.deleteTagCircuitBreaker(requestID, id, req)
 	}
-
-	return s.deleteTagCircuitBreaker(requestID, id, req)
+	if ret.GetCode().GetValue() == api.ExecuteSuccess {
+		s.RecordHistory(circuitBreakerRecordEntry(ctx, circuitBreaker, model.ODelete))
+	}
+	return ret
 }
 
 /**
@@ -298,8 +310,6 @@ func (s *Server) deleteMasterCircuitBreaker(requestID string, id string, req *ap
 	msg := fmt.Sprintf("delete master circuit breaker: id=%v", id)
 	log.Info(msg, ZapRequestID(requestID))
 
-	// todo 操作记录
-
 	return api.NewCircuitBreakerResponse(api.ExecuteSuccess, req)
 }
 
@@ -328,8 +338,6 @@ func (s *Server) deleteTagCircuitBreaker(requestID string, id string, req *api.C
 	msg := fmt.Sprintf("delete circuit breaker version: id=%v, version=%v", id, req.GetVersion().GetValue())
 	log.Info(msg, ZapRequestID(requestID))
 
-	// todo 操作记录
-
 	return api.NewCircuitBreakerResponse(api.ExecuteSuccess, req)
 }
 
@@ -371,6 +379,7 @@ func (s *Server) UpdateCircuitBreaker(ctx context.Context, req *api.CircuitBreak
 		return resp
 	}
 
+	before, beforeErr := circuitBreaker2API(circuitBreaker)
 	// 修改
 	err, needUpdate := s.updateCircuitBreakerAttribute(req, circuitBreaker)
 	if err != nil {
@@ -392,8 +401,11 @@ func (s *Server) UpdateCircuitBreaker(ctx context.Context, req *api.CircuitBreak
 	msg := fmt.Sprintf("update circuit breaker: id=%v, version=%v, name=%v, namespace=%v",
 		circuitBreaker.ID, circuitBreaker.Version, circuitBreaker.Name, circuitBreaker.Namespace)
 	log.Info(msg, ZapRequestID(requestID))
-
-	// todo 记录操作记录
+	entry := circuitBreakerRecordEntry(ctx, circuitBreaker, model.OUpdate)
+	if beforeErr == nil {
+		entry.Before = before
+	}
+	s.RecordHistory(entry)
 
 	return api.NewCircuitBreakerResponse(api.ExecuteSuccess, req)
 }
@@ -524,8 +536,7 @@ func (s *Server) ReleaseCircuitBreaker(ctx context.Context, req *api.ConfigRelea
 	msg := fmt.Sprintf("release circuit breaker: ruleID=%s, ruleVersion=%s, namespace=%s, service=%s",
 		ruleID, ruleVersion, service.Namespace, service.Name)
 	log.Info(msg, zap.String("request-id", requestID))
-
-	// todo 操作记录
+	s.RecordHistory(circuitBreakerReleaseRecordEntry(ctx, service, ruleID, ruleVersion, model.ORelease))
 
 	return api.NewConfigResponse(api.ExecuteSuccess, req)
 }
@@ -595,8 +606,7 @@ func (s *Server) UnBindCircuitBreaker(ctx context.Context, req *api.ConfigReleas
 	msg := fmt.Sprintf("unbind circuit breaker: ruleID=%s, ruleVersion=%s, namespace=%s, service=%s",
 		ruleID, ruleVersion, service.Namespace, service.Name)
 	log.Info(msg, zap.String("request-id", requestID))
-
-	// todo 操作记录
+	s.RecordHistory(circuitBreakerReleaseRecordEntry(ctx, service, ruleID, ruleVersion, model.OUnbind))
 
 	return api.NewConfigResponse(api.ExecuteSuccess, req)
 }
@@ -991,7 +1001,7 @@ func (s *Server) checkCircuitBreakerValid(ctx context.Context, req *api.CircuitB
 	}
 
 	// 鉴权
-	if ok := s.authority.VerifyRule(circuitBreaker.Token, parseCircuitBreakerToken(ctx, req)); !ok {
+	if ok := s.verifyCircuitBreakerAuth(ctx, circuitBreaker, parseCircuitBreakerToken(ctx, req)); !ok {
 		return nil, api.NewCircuitBreakerResponse(api.Unauthorized, req)
 	}
 	return circuitBreaker, nil
@@ -1017,6 +1027,14 @@ func (s *Server) checkService(ctx context.Context, req *api.ConfigRelease) (*mod
 		return nil, api.NewConfigResponse(api.NotAllowAliasBindRule, req)
 	}
 
+	// 带有登录用户时使用用户的角色鉴权
+	if found, ok := s.verifyAuthByUser(ctx, auth.ResourceCircuitBreaker, service.Namespace, service.Name); found {
+		if !ok {
+			return nil, api.NewConfigResponse(api.Unauthorized, req)
+		}
+		return service, nil
+	}
+
 	// 使用平台id以及token鉴权
 	if ok := s.verifyAuthByPlatform(ctx, service.PlatformID); !ok {
 		// 检查token是否存在
@@ -1034,6 +1052,17 @@ func (s *Server) checkService(ctx context.Context, req *api.ConfigRelease) (*mod
 	return service, nil
 }
 
+/**
+ * @brief 熔断规则鉴权，带有登录用户时使用用户的角色鉴权，否则校验规则的Token
+ */
+func (s *Server) verifyCircuitBreakerAuth(ctx context.Context, circuitBreaker *model.CircuitBreaker,
+	token string) bool {
+	if found, ok := s.verifyAuthByUser(ctx, auth.ResourceCircuitBreaker, circuitBreaker.Namespace, ""); found {
+		return ok
+	}
+	return s.authority.VerifyRule(circuitBreaker.Token, token)
+}
+
 /**
  * @brief 获取熔断规则的token信息
  */
@@ -1045,6 +1074,60 @@ func parseCircuitBreakerToken(ctx context.Context, req *api.CircuitBreaker) stri
 	return ParseToken(ctx)
 }
 
+/**
+ * @brief 构建熔断规则的记录entry
+ */
+func circuitBreakerRecordEntry(ctx context.Context, md *model.CircuitBreaker,
+	opt model.OperationType) *model.RecordEntry {
+	if md == nil {
+		return nil
+	}
+	entry := &model.RecordEntry{
+		ResourceType:  model.RCircuitBreaker,
+		OperationType: opt,
+		Namespace:     md.Namespace,
+		Context:       fmt.Sprintf("id:%s,version:%s,name:%s,revision:%s", md.ID, md.Version, md.Name, md.Revision),
+		Operator:      ParseOperator(ctx),
+		Revision:      md.Revision,
+		CreateTime:    time.Now(),
+	}
+	if rule, err := circuitBreaker2API(md); err == nil {
+		if opt == model.ODelete {
+			entry.Before = rule
+		} else {
+			entry.Object = rule
+		}
+	}
+	return entry
+}
+
+/**
+ * @brief 构建熔断规则发布以及解绑的记录entry
+ */
+func circuitBreakerReleaseRecordEntry(ctx context.Context, service *model.Service, ruleID, ruleVersion string,
+	opt model.OperationType) *model.RecordEntry {
+	entry := &model.RecordEntry{
+		ResourceType:  model.RCircuitBreaker,
+		OperationType: opt,
+		Namespace:     service.Namespace,
+		Service:       service.Name,
+		Context:       fmt.Sprintf("ruleID:%s,ruleVersion:%s", ruleID, ruleVersion),
+		Operator:      ParseOperator(ctx),
+		CreateTime:    time.Now(),
+	}
+	entry.Object = &api.ConfigRelease{
+		Service: &api.Service{
+			Name:      utils.NewStringValue(service.Name),
+			Namespace: utils.NewStringValue(service.Namespace),
+		},
+		CircuitBreaker: &api.CircuitBreaker{
+			Id:      utils.NewStringValue(ruleID),
+			Version: utils.NewStringValue(ruleVersion),
+		},
+	}
+	return entry
+}
+
 /**
  * @brief 创建存储层熔断规则模型
  */
@@ -137,6 +137,23 @@ func checkServiceMetadata(requestMeta map[string]string, service *model.Service,
  * ServiceInstancesCache 根据服务名查询服务实例列表
  */
 func (s *Server) ServiceInstancesCache(ctx context.Context, req *api.Service) *api.DiscoverResponse {
+	return s.serviceInstancesCache(ctx, req, api.DiscoverRequest_ALL, nil)
+}
+
+/**
+ * DiscoverInstances 根据发现请求查询服务实例列表
+ * 请求中指定了就近范围时，只返回与调用方在同一范围内的实例
+ */
+func (s *Server) DiscoverInstances(ctx context.Context, req *api.DiscoverRequest) *api.DiscoverResponse {
+	if req.GetNearby() == api.DiscoverRequest_ALL {
+		return s.ServiceInstancesCache(ctx, req.GetService())
+	}
+	return s.serviceInstancesCache(ctx, req.GetService(), req.GetNearby(), s.getCallerLocation(ctx, req))
+}
+
+// 查询服务实例列表，nearby不为ALL时，按照调用方的地理位置就近过滤
+func (s *Server) serviceInstancesCache(ctx context.Context, req *api.Service, nearby api.DiscoverRequest_NearbyLevel,
+	caller *api.Location) *api.DiscoverResponse {
 	if req == nil {
 		return api.NewDiscoverInstanceResponse(api.EmptyRequest, req)
 	}
@@ -177,6 +194,21 @@ func (s *Server) ServiceInstancesCache(ctx context.Context, req *api.Service) *a
 			return api.NewDiscoverInstanceResponse(api.ExecuteException, req)
 		}
 	}
+
+	// 就近过滤，revision按照实际使用的范围重新计算
+	var nearbyInstances []*model.Instance
+	if nearby != api.DiscoverRequest_ALL {
+		scope, scopeErr := s.getNearbyScope(service, revision, nearby, caller)
+		if scopeErr != nil {
+			log.Errorf("[Server][Service][Instance] compute nearby revision service(%s) err: %s",
+				service.ID, scopeErr.Error())
+			return api.NewDiscoverInstanceResponse(api.ExecuteException, req)
+		}
+		if scope != nil {
+			revision = scope.revision
+			nearbyInstances = scope.instances
+		}
+	}
 	if revision == req.GetRevision().GetValue() {
 		return api.NewDiscoverInstanceResponse(api.DataNoChange, req)
 	}
@@ -189,6 +221,12 @@ func (s *Server) ServiceInstancesCache(ctx context.Context, req *api.Service) *a
 	resp.Service.Name = req.GetName() // 别名场景，response需要保持和request的服务名一致
 	// 填充instance数据
 	resp.Instances = make([]*api.Instance, 0) // TODO
+	if nearbyInstances != nil {
+		for _, instance := range nearbyInstances {
+			resp.Instances = append(resp.Instances, s.getInstance(req, instance.Proto))
+		}
+		return resp
+	}
 	_ = s.caches.Instance().
 		IteratorInstancesWithService(service.ID, // service已经是源服务
 			func(key string, value *model.Instance) (b bool, e error) {
@@ -19,9 +19,8 @@ package naming
 
 import (
 	"context"
-	"errors"
+	"net"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
@@ -31,6 +30,7 @@ import (
 	"github.com/polarismesh/polaris-server/common/redispool"
 	"github.com/polarismesh/polaris-server/common/timewheel"
 	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/plugin"
 	"go.uber.org/zap"
 )
 
@@ -47,6 +47,19 @@ type HealthCheckConfig struct {
 	LocalHost     string `yaml:"localHost"`
 	MaxIdle       int    `yaml:"maxIdle"`
 	IdleTimeout   int    `yaml:"idleTimeout"`
+	// 健康检查模式：redis（默认）、memory、peer
+	Mode string `yaml:"mode"`
+	// peer模式下健康检查server集群的服务名，默认为polaris.healthcheck
+	PeerService   string `yaml:"peerService"`
+	PeerNamespace string `yaml:"peerNamespace"`
+	// peer模式下本机在健康检查server集群中的地址host:port，为空则使用http apiserver的自注册地址
+	LocalPeer string `yaml:"localPeer"`
+	// peer模式下转发心跳的超时时间，单位为秒
+	PeerTimeout int `yaml:"peerTimeout"`
+	// TCP、HTTP主动探测的并发数，默认为64
+	ProbeWorkers int `yaml:"probeWorkers"`
+	// 连续探测失败多少次后把实例置为不健康，默认为2
+	ProbeFailThreshold int `yaml:"probeFailThreshold"`
 }
 
 /**
@@ -61,7 +74,7 @@ type HbInfo struct {
 
 /**
  * HeartBeatMgr 心跳管理器结构体
- * 包括时间轮、ckv连接池、存储实例心跳信息的map
+ * 包括时间轮、心跳状态的共享存储、存储实例心跳信息的map
  */
 type HeartBeatMgr struct {
 	ctx   context.Context
@@ -71,6 +84,8 @@ type HeartBeatMgr struct {
 	dbTw  *timewheel.TimeWheel
 	// ckvPool   *ckv.Pool
 	redisPool *redispool.Pool
+	backend   heartbeatBackend
+	peers     *peerCluster
 }
 
 /**
@@ -102,45 +117,33 @@ var (
 		if lastBeatTime < task.hbInfo.beatTime {
 			return
 		}
+		// 实例的心跳已经不归本机处理
+		if !hbMgr.ownedByLocal(task.hbInfo.id) {
+			hbMgr.removeHbInfo(task.hbInfo.id)
+			return
+		}
 
 		// 更新上报心跳时间
 		now := time.Now().Unix()
 		task.lastBeatTime = now
 
 		// 从ckv获取实例状态，看其他server这段时间有没有收到心跳
-		respCh := make(chan *redispool.Resp)
-		hbMgr.redisPool.Get(task.hbInfo.id, respCh)
-		resp := <-respCh
-		if !resp.Local {
-			if resp.Err != nil {
-				// 获取ckv失败，不能退出，直接进入set ckv unhealthy & dbCallback流程
-				log.Errorf("[health check] addr:%s id:%s 1ttl get redis err:%s",
-					task.hbInfo.addr, task.hbInfo.id, resp.Err)
-			} else {
-				// ckv中value格式 健康状态(1健康 0不健康):心跳时间戳:写者ip
-				// 如: 1:timestamp:10.60.31.22
-				// 基于解析性能考虑，没有使用json
-				res := strings.Split(resp.Value, ":")
-				kvBeatTime, err := strconv.ParseInt(res[1], 10, 64)
-				if err != nil {
-					log.Errorf("[health check] addr:%s id:%s redis beat time parse err:%s",
-						task.hbInfo.addr, task.hbInfo.id, err)
-				}
-
-				// ckv中的实例状态为已经被改为不健康
-				// 或ckv心跳时间 > 本地上次心跳时间，说明其他server收到了心跳，不做任何处理
-				if res[0] == "0" || kvBeatTime > lastBeatTime {
-					return
-				}
-			}
+		record, err := hbMgr.backend.Get(task.hbInfo.id)
+		if err != nil {
+			// 获取ckv失败，不能退出，直接进入set ckv unhealthy & dbCallback流程
+			log.Errorf("[health check] addr:%s id:%s 1ttl get redis err:%s",
+				task.hbInfo.addr, task.hbInfo.id, err)
+		}
+		// ckv中的实例状态为已经被改为不健康
+		// 或ckv心跳时间 > 本地上次心跳时间，说明其他server收到了心跳，不做任何处理
+		if record != nil && (record.status == NotHealthy || record.beatTime > lastBeatTime) {
+			return
+		}
 
-			// 将ckv中状态改为不健康
-			log.Infof("[health check] addr:%s id:%s 1ttl overtime, set redis not healthy", task.hbInfo.addr, task.hbInfo.id)
-			hbMgr.redisPool.Set(task.hbInfo.id, NotHealthy, now, respCh)
-			resp = <-respCh
-			if resp.Err != nil {
-				log.Errorf("[health check] addr:%s id:%s set redis err:%s", task.hbInfo.addr, task.hbInfo.id, resp.Err)
-			}
+		// 将ckv中状态改为不健康
+		log.Infof("[health check] addr:%s id:%s 1ttl overtime, set redis not healthy", task.hbInfo.addr, task.hbInfo.id)
+		if err := hbMgr.backend.Set(task.hbInfo.id, NotHealthy, now); err != nil {
+			log.Errorf("[health check] addr:%s id:%s set redis err:%s", task.hbInfo.addr, task.hbInfo.id, err)
 		}
 		// 添加时间轮任务：再过2ttl后仍未收到心跳，改写db实例状态为不健康
 		_ = hbMgr.dbTw.AddTask(time.Duration(2*task.hbInfo.ttl-1)*time.Second, task, dbCallback)
@@ -156,31 +159,28 @@ var (
 		if task.lastBeatTime < task.hbInfo.beatTime {
 			return
 		}
+		// 实例的心跳已经不归本机处理
+		if !hbMgr.ownedByLocal(task.hbInfo.id) {
+			hbMgr.removeHbInfo(task.hbInfo.id)
+			return
+		}
 
 		// 从ckv获取下key状态，看其他server有没有收到心跳
-		respCh := make(chan *redispool.Resp)
-		hbMgr.redisPool.Get(task.hbInfo.id, respCh)
-		resp := <-respCh
-		if !resp.Local {
-			if resp.Err != nil {
-				log.Errorf("[healthCheck] dbCallback get addr(%s) id(%s) from redis err: %s",
-					task.hbInfo.addr, task.hbInfo.id, resp.Err)
-			} else {
-				res := strings.Split(resp.Value, ":")
-				if res[0] == "1" {
-					log.Infof("[health check] addr: %s id: %s redis status is healthy, ignore set db unhealthy",
-						task.hbInfo.addr, task.hbInfo.id)
-					return
-				}
-			}
+		record, err := hbMgr.backend.Get(task.hbInfo.id)
+		if err != nil {
+			log.Errorf("[healthCheck] dbCallback get addr(%s) id(%s) from redis err: %s",
+				task.hbInfo.addr, task.hbInfo.id, err)
+		}
+		if record != nil && record.status == Healthy {
+			log.Infof("[health check] addr: %s id: %s redis status is healthy, ignore set db unhealthy",
+				task.hbInfo.addr, task.hbInfo.id)
+			return
+		}
 
-			// 删除kv
-			log.Infof("[health check] del redis id:%s", task.hbInfo.id)
-			hbMgr.redisPool.Del(task.hbInfo.id, respCh)
-			resp = <-respCh
-			if resp.Err != nil {
-				log.Errorf("[health check] addr:%s id:%s del redis err:%s", task.hbInfo.addr, task.hbInfo.id, resp.Err)
-			}
+		// 删除kv
+		log.Infof("[health check] del redis id:%s", task.hbInfo.id)
+		if err := hbMgr.backend.Del(task.hbInfo.id); err != nil {
+			log.Errorf("[health check] addr:%s id:%s del redis err:%s", task.hbInfo.addr, task.hbInfo.id, err)
 		}
 
 		insCache := server.caches.Instance().GetInstance(task.hbInfo.id)
@@ -197,9 +197,7 @@ var (
 		}
 
 		// 从本机map中删除
-		hbMgr.mu.Lock()
-		delete(hbMgr.hbMap, task.hbInfo.id)
-		hbMgr.mu.Unlock()
+		hbMgr.removeHbInfo(task.hbInfo.id)
 	}
 )
 
@@ -214,29 +212,50 @@ func SetHealthCheckConfig(conf *HealthCheckConfig) {
  * NewHeartBeatMgr 初始化心跳管理器
  */
 func NewHeartBeatMgr(ctx context.Context) (*HeartBeatMgr, error) {
-	kvService := server.caches.Service().
-		GetServiceByName(healthCheckConf.KvServiceName, healthCheckConf.KvNamespace)
-	var kvInstances []*model.Instance
+	if healthCheckConf.Mode == "" {
+		healthCheckConf.Mode = HealthCheckRedis
+	}
+	log.Infof("[health check] health check mode: %s", healthCheckConf.Mode)
 
-	if kvService != nil {
-		kvInstances = server.caches.Instance().GetInstancesByServiceID(kvService.ID)
+	// redis的密码可能需要通过密码插件解析
+	kvPasswd, err := plugin.ParseSecret(healthCheckConf.KvPasswd)
+	if err != nil {
+		log.Errorf("[health check] parse kvPasswd err: %s", err.Error())
+		return nil, err
 	}
-	// if len(kvInstances) == 0 {
-	//	return nil, fmt.Errorf("no available ckv instance, serviceId:%s", kvService.ID)
-	// }
 
-	redisPool, err := redispool.NewPool(healthCheckConf.KvConnNum, healthCheckConf.KvPasswd,
-		healthCheckConf.LocalHost, kvInstances, healthCheckConf.MaxIdle, healthCheckConf.IdleTimeout)
+	var kvService *model.Service
+	backend, err := newHeartbeatBackend(healthCheckConf, func() (*redispool.Pool, error) {
+		kvService = server.caches.Service().
+			GetServiceByName(healthCheckConf.KvServiceName, healthCheckConf.KvNamespace)
+		var kvInstances []*model.Instance
+
+		if kvService != nil {
+			kvInstances = server.caches.Instance().GetInstancesByServiceID(kvService.ID)
+		}
+		// if len(kvInstances) == 0 {
+		//	return nil, fmt.Errorf("no available ckv instance, serviceId:%s", kvService.ID)
+		// }
+
+		return redispool.NewPool(healthCheckConf.KvConnNum, kvPasswd,
+			healthCheckConf.LocalHost, kvInstances, healthCheckConf.MaxIdle, healthCheckConf.IdleTimeout)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	mgr := &HeartBeatMgr{
-		ctx:       ctx,
-		hbMap:     make(map[string]*HbInfo),
-		ckvTw:     timewheel.New(time.Second, healthCheckConf.SlotNum, "ckv task timewheel"),
-		dbTw:      timewheel.New(time.Second, healthCheckConf.SlotNum, "db task timewheel"),
-		redisPool: redisPool,
+		ctx:     ctx,
+		hbMap:   make(map[string]*HbInfo),
+		ckvTw:   timewheel.New(time.Second, healthCheckConf.SlotNum, "ckv task timewheel"),
+		dbTw:    timewheel.New(time.Second, healthCheckConf.SlotNum, "db task timewheel"),
+		backend: backend,
+	}
+	if redis, ok := backend.(*redisBackend); ok {
+		mgr.redisPool = redis.pool
+	}
+	if healthCheckConf.Mode == HealthCheckPeer {
+		mgr.peers = newPeerCluster(healthCheckConf)
 	}
 	if kvService != nil {
 		go mgr.watchCkvService(kvService.ID)
@@ -248,9 +267,36 @@ func NewHeartBeatMgr(ctx context.Context) (*HeartBeatMgr, error) {
  * Start 启动心跳管理器，启动健康检查功能
  */
 func (hb *HeartBeatMgr) Start() {
-	hb.redisPool.Start()
+	hb.backend.Start()
 	hb.ckvTw.Start()
 	hb.dbTw.Start()
+	if hb.peers != nil {
+		go hb.peers.watch(hb.ctx)
+	}
+}
+
+// 判断实例的心跳是否由本机处理，非peer模式都由本机处理
+func (hb *HeartBeatMgr) ownedByLocal(id string) bool {
+	if hb.peers == nil {
+		return true
+	}
+	return hb.peers.ownedByLocal(id)
+}
+
+// 从本机map中删除实例心跳
+func (hb *HeartBeatMgr) removeHbInfo(id string) {
+	hb.mu.Lock()
+	delete(hb.hbMap, id)
+	hb.mu.Unlock()
+}
+
+/**
+ * GetHbMapSize 获取本机记录的实例心跳数
+ */
+func (hb *HeartBeatMgr) GetHbMapSize() int {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+	return len(hb.hbMap)
 }
 
 /**
@@ -287,6 +333,18 @@ func (hb *HeartBeatMgr) watchCkvService(id string) {
 	}
 }
 
+// 判断心跳是否由集群中的其他server转发，转发header只信任来自其他server的请求
+func (hb *HeartBeatMgr) forwardedByPeer(ctx context.Context) bool {
+	if !utils.ValueHeartbeatForwarded(ctx) {
+		return false
+	}
+	if !hb.peers.fromPeer(ctx) {
+		log.Warn("[health check] ignore forwarded header from non-peer client", ZapRequestID(ParseRequestID(ctx)))
+		return false
+	}
+	return true
+}
+
 /**
 * @brief 心跳处理函数
  */
@@ -296,6 +354,18 @@ func (hb *HeartBeatMgr) healthCheck(ctx context.Context, instance *api.Instance)
 		return errRsp
 	}
 	instance.Id = utils.NewStringValue(id)
+
+	// peer模式下，心跳转发给实例所属的server处理，转发失败则返回错误由客户端重试
+	if hb.peers != nil && !hb.forwardedByPeer(ctx) {
+		if peer, ok := hb.peers.forwardTarget(id); ok {
+			resp, err := hb.peers.forward(ctx, peer, instance)
+			if err != nil {
+				return api.NewInstanceResponse(api.HeartbeatException, instance)
+			}
+			return resp
+		}
+	}
+
 	insCache := server.caches.Instance().GetInstance(id)
 	if insCache == nil {
 		return api.NewInstanceResponse(api.NotFoundResource, instance)
@@ -315,8 +385,9 @@ func (hb *HeartBeatMgr) healthCheck(ctx context.Context, instance *api.Instance)
 		return api.NewInstanceResponse(api.Unauthorized, instance)
 	}
 
-	// 如果实例未开启健康检查，返回
-	if !insCache.EnableHealthCheck() || insCache.HealthCheck() == nil {
+	// 如果实例未开启健康检查，或者使用的是主动探测，返回
+	if !insCache.EnableHealthCheck() || insCache.HealthCheck() == nil ||
+		insCache.HealthCheck().GetType() != api.HealthCheck_HEARTBEAT {
 		return api.NewInstanceResponse(api.HeartbeatOnDisabledIns, instance)
 	}
 
@@ -324,11 +395,10 @@ func (hb *HeartBeatMgr) healthCheck(ctx context.Context, instance *api.Instance)
 	log.Info("receive heartbeat", ZapRequestID(ParseRequestID(ctx)), zap.String("id", id),
 		zap.String("service", service.Namespace+":"+service.Name),
 		zap.String("host", insCache.Host()), zap.Uint32("port", insCache.Port()))
-	addr := insCache.Host() + ":" + strconv.Itoa(int(insCache.Port()))
+	addr := net.JoinHostPort(insCache.Host(), strconv.Itoa(int(insCache.Port())))
 	ttl := insCache.HealthCheck().GetHeartbeat().GetTtl().GetValue()
 	now := time.Now().Unix()
 	var hbInfo *HbInfo
-	respCh := make(chan *redispool.Resp)
 
 	hb.mu.Lock()
 	hbInfo, ok = hb.hbMap[id]
@@ -339,10 +409,8 @@ func (hb *HeartBeatMgr) healthCheck(ctx context.Context, instance *api.Instance)
 
 		// hbMap中没有找到该实例，说明这是实例近期第一次上报心跳，set ckv中实例状态为健康
 		log.Infof("[health check] addr:%s id:%s ttl:%d heartbeat first time, set redis", addr, id, ttl)
-		hbMgr.redisPool.Set(id, Healthy, now, respCh)
-		resp := <-respCh
-		if resp.Err != nil {
-			log.Errorf("[health check] addr:%s id:%s set redis err:%s", addr, id, resp.Err)
+		if err := hb.backend.Set(id, Healthy, now); err != nil {
+			log.Errorf("[health check] addr:%s id:%s set redis err:%s", addr, id, err)
 			return api.NewInstanceResponse(api.HeartbeatException, instance)
 		}
 	} else {
@@ -360,11 +428,8 @@ func (hb *HeartBeatMgr) healthCheck(ctx context.Context, instance *api.Instance)
 		// 本机超过1 ttl + 1s未收到心跳，set一次ckv状态
 		if now-lastBeatTime >= int64(ttl+1) {
 			log.Infof("[health check] addr:%s, id:%s receive heart beat after ttl + 1s, set redis healthy", addr, id)
-			hbMgr.redisPool.Set(id, Healthy, now, respCh)
-			resp := <-respCh
-
-			if resp.Err != nil {
-				log.Errorf("[health check] addr:%s id:%s set redis err:%s", addr, id, resp.Err)
+			if err := hb.backend.Set(id, Healthy, now); err != nil {
+				log.Errorf("[health check] addr:%s id:%s set redis err:%s", addr, id, err)
 				return api.NewInstanceResponse(api.HeartbeatException, instance)
 			}
 		}
@@ -399,38 +464,26 @@ func (hb *HeartBeatMgr) acquireLastHeartbeat(instance *api.Instance) error {
 		instance.Metadata["system-time"] = time2String(time.Now())
 	}
 
-	// 获取ckv记录的时间
-	respCh := make(chan *redispool.Resp)
-	hbMgr.redisPool.Get(id, respCh)
-	resp := <-respCh
-	if resp.Err != nil {
-		if resp.Err.Error() == RedisNoKeyErr {
-			return nil
-		}
-
-		log.Errorf("[health check] get id(%s) from redis err: %s", id, resp.Err.Error())
-		return resp.Err
-	}
-	if resp.Local {
-		return nil
+	// peer模式下，记录实例心跳所属的server
+	if hb.peers != nil {
+		instance.Metadata["heartbeat-owner-server"] = hb.peers.owner(id)
 	}
 
-	res := strings.Split(resp.Value, ":")
-	if len(res) != 3 {
-		log.Errorf("[health check] id(%s) redis record invalid(%s)", id, resp.Value)
-		return errors.New("invalid ckv record")
-	}
-	tm, err := strconv.ParseInt(res[1], 10, 64)
+	// 获取ckv记录的时间
+	record, err := hb.backend.Get(id)
 	if err != nil {
-		log.Errorf("[health check] id(%s) redis record heartbeat time(%s) is invalid", id, res[1])
+		log.Errorf("[health check] get id(%s) from redis err: %s", id, err.Error())
 		return err
 	}
+	if record == nil {
+		return nil
+	}
 
 	// ckv记录的心跳时间与心跳server，
 	// 根据这个心跳server可以获取到实例上报到哪台心跳server
-	instance.Metadata["ckv-record-healthy"] = res[0]
-	instance.Metadata["ckv-record-heartbeat-time"] = time2String(time.Unix(tm, 0))
-	instance.Metadata["ckv-record-heartbeat-server"] = res[2]
+	instance.Metadata["ckv-record-healthy"] = strconv.Itoa(record.status)
+	instance.Metadata["ckv-record-heartbeat-time"] = time2String(time.Unix(record.beatTime, 0))
+	instance.Metadata["ckv-record-heartbeat-server"] = record.server
 	return nil
 }
 
@@ -0,0 +1,186 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/polarismesh/polaris-server/common/redispool"
+)
+
+const (
+	// HealthCheckRedis 通过redis共享心跳状态，默认的模式
+	HealthCheckRedis = "redis"
+	// HealthCheckMemory 心跳状态只保存在本机内存，适用于单机部署
+	HealthCheckMemory = "memory"
+	// HealthCheckPeer 心跳转发到实例所属的server，server之间不依赖redis
+	HealthCheckPeer = "peer"
+)
+
+/**
+ * heartbeatRecord 共享存储中的实例心跳记录
+ */
+type heartbeatRecord struct {
+	status   int
+	beatTime int64
+	server   string
+}
+
+/**
+ * heartbeatBackend 心跳状态的共享存储
+ * @note Get在记录不存在或者没有可用的共享存储时，返回nil
+ */
+type heartbeatBackend interface {
+	Start()
+	Get(id string) (*heartbeatRecord, error)
+	Set(id string, status int, beatTime int64) error
+	Del(id string) error
+}
+
+/**
+ * @brief 根据健康检查模式创建心跳存储
+ */
+func newHeartbeatBackend(conf *HealthCheckConfig, redisPool func() (*redispool.Pool, error)) (
+	heartbeatBackend, error) {
+	switch conf.Mode {
+	case HealthCheckRedis:
+		pool, err := redisPool()
+		if err != nil {
+			return nil, err
+		}
+		return &redisBackend{pool: pool}, nil
+	case HealthCheckMemory, HealthCheckPeer:
+		return newMemoryBackend(conf.LocalHost), nil
+	default:
+		return nil, fmt.Errorf("health check mode(%s) is not supported", conf.Mode)
+	}
+}
+
+/**
+ * redisBackend 基于redis连接池的心跳存储
+ */
+type redisBackend struct {
+	pool *redispool.Pool
+}
+
+// Start 启动连接池
+func (r *redisBackend) Start() {
+	r.pool.Start()
+}
+
+// Get 获取心跳记录
+func (r *redisBackend) Get(id string) (*heartbeatRecord, error) {
+	respCh := make(chan *redispool.Resp)
+	r.pool.Get(id, respCh)
+	resp := <-respCh
+	if resp.Local {
+		return nil, nil
+	}
+	if resp.Err != nil {
+		if resp.Err.Error() == RedisNoKeyErr {
+			return nil, nil
+		}
+		return nil, resp.Err
+	}
+
+	// ckv中value格式 健康状态(1健康 0不健康):心跳时间戳:写者ip
+	// 如: 1:timestamp:10.60.31.22
+	// 基于解析性能考虑，没有使用json，写者ip可能是带有冒号的IPv6地址
+	res := strings.SplitN(resp.Value, ":", 3)
+	if len(res) != 3 {
+		return nil, fmt.Errorf("invalid redis record(%s)", resp.Value)
+	}
+	status, err := strconv.Atoi(res[0])
+	if err != nil {
+		return nil, err
+	}
+	beatTime, err := strconv.ParseInt(res[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &heartbeatRecord{status: status, beatTime: beatTime, server: res[2]}, nil
+}
+
+// Set 写入心跳记录
+func (r *redisBackend) Set(id string, status int, beatTime int64) error {
+	respCh := make(chan *redispool.Resp)
+	r.pool.Set(id, status, beatTime, respCh)
+	return (<-respCh).Err
+}
+
+// Del 删除心跳记录
+func (r *redisBackend) Del(id string) error {
+	respCh := make(chan *redispool.Resp)
+	r.pool.Del(id, respCh)
+	return (<-respCh).Err
+}
+
+/**
+ * memoryBackend 基于本机内存的心跳存储
+ */
+type memoryBackend struct {
+	localHost string
+	mutex     sync.RWMutex
+	records   map[string]*heartbeatRecord
+}
+
+// 新建内存心跳存储
+func newMemoryBackend(localHost string) *memoryBackend {
+	return &memoryBackend{
+		localHost: localHost,
+		records:   make(map[string]*heartbeatRecord),
+	}
+}
+
+// Start 内存存储不需要启动
+func (m *memoryBackend) Start() {}
+
+// Get 获取心跳记录
+func (m *memoryBackend) Get(id string) (*heartbeatRecord, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	record, ok := m.records[id]
+	if !ok {
+		return nil, nil
+	}
+	out := *record
+	return &out, nil
+}
+
+// Set 写入心跳记录
+func (m *memoryBackend) Set(id string, status int, beatTime int64) error {
+	if id == "" {
+		return errors.New("empty heartbeat id")
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.records[id] = &heartbeatRecord{status: status, beatTime: beatTime, server: m.localHost}
+	return nil
+}
+
+// Del 删除心跳记录
+func (m *memoryBackend) Del(id string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.records, id)
+	return nil
+}
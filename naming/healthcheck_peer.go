@@ -0,0 +1,294 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// 默认的健康检查server集群，即server自注册的服务
+	defaultPeerService = "polaris.healthcheck"
+	// 转发心跳使用的协议
+	peerProtocol = "http"
+	// 每个server在一致性hash环上的虚拟节点数
+	peerVirtualNodes = 64
+	// 默认的心跳转发超时时间，单位为秒
+	defaultPeerTimeout = 3
+	// 刷新server列表的周期
+	peerRefreshInterval = 10 * time.Second
+)
+
+/**
+ * peerRing server的一致性hash环
+ */
+type peerRing struct {
+	mutex  sync.RWMutex
+	hashes []uint32
+	nodes  map[uint32]string
+	peers  []string
+}
+
+/**
+ * @brief 新建一致性hash环
+ */
+func newPeerRing() *peerRing {
+	return &peerRing{nodes: make(map[uint32]string)}
+}
+
+/**
+ * @brief 更新hash环上的server，server列表未变化返回false
+ */
+func (r *peerRing) update(peers []string) bool {
+	sorted := make([]string, len(peers))
+	copy(sorted, peers)
+	sort.Strings(sorted)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if strings.Join(sorted, ",") == strings.Join(r.peers, ",") {
+		return false
+	}
+
+	hashes := make([]uint32, 0, len(sorted)*peerVirtualNodes)
+	nodes := make(map[uint32]string, len(sorted)*peerVirtualNodes)
+	for _, peer := range sorted {
+		for i := 0; i < peerVirtualNodes; i++ {
+			hash := crc32.ChecksumIEEE([]byte(peer + "#" + strconv.Itoa(i)))
+			if _, ok := nodes[hash]; ok {
+				continue
+			}
+			nodes[hash] = peer
+			hashes = append(hashes, hash)
+		}
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+
+	r.hashes = hashes
+	r.nodes = nodes
+	r.peers = sorted
+	return true
+}
+
+/**
+ * @brief 获取id所属的server
+ */
+func (r *peerRing) owner(id string) string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if len(r.hashes) == 0 {
+		return ""
+	}
+
+	hash := crc32.ChecksumIEEE([]byte(id))
+	start := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= hash })
+	return r.nodes[r.hashes[start%len(r.hashes)]]
+}
+
+/**
+ * @brief 判断host是否为hash环上某个server的地址
+ */
+func (r *peerRing) containsHost(host string) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for _, peer := range r.peers {
+		if peerHost, _, err := net.SplitHostPort(peer); err == nil && peerHost == host {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * peerCluster 健康检查server集群
+ * 每个实例的心跳由一致性hash选出的server处理，其他server收到心跳后转发给它
+ * @note 实例的所属server只随着健康检查server列表的变化而变化，转发失败不会改变所属关系，
+ *       否则两个server会同时认为自己负责该实例，导致健康状态来回变化
+ */
+type peerCluster struct {
+	ring      *peerRing
+	localHost string
+	localPeer string
+	service   string
+	namespace string
+	client    *http.Client
+}
+
+/**
+ * @brief 新建健康检查server集群
+ */
+func newPeerCluster(conf *HealthCheckConfig) *peerCluster {
+	service := conf.PeerService
+	if service == "" {
+		service = defaultPeerService
+	}
+	namespace := conf.PeerNamespace
+	if namespace == "" {
+		namespace = SystemNamespace
+	}
+	timeout := conf.PeerTimeout
+	if timeout <= 0 {
+		timeout = defaultPeerTimeout
+	}
+
+	return &peerCluster{
+		ring:      newPeerRing(),
+		localHost: conf.LocalHost,
+		localPeer: conf.LocalPeer,
+		service:   service,
+		namespace: namespace,
+		client:    &http.Client{Timeout: time.Duration(timeout) * time.Second},
+	}
+}
+
+/**
+ * @brief 判断实例的心跳是否由本机处理
+ */
+func (p *peerCluster) ownedByLocal(id string) bool {
+	owner := p.owner(id)
+	return owner == "" || p.isLocal(owner)
+}
+
+/**
+ * @brief 获取实例心跳需要转发的server，由本机处理则返回false
+ */
+func (p *peerCluster) forwardTarget(id string) (string, bool) {
+	owner := p.owner(id)
+	if owner == "" || p.isLocal(owner) {
+		return "", false
+	}
+	return owner, true
+}
+
+// 获取实例心跳所属的server
+func (p *peerCluster) owner(id string) string {
+	return p.ring.owner(id)
+}
+
+/**
+ * @brief 判断心跳请求是否来自集群中的其他server
+ * 只有server之间转发的心跳才能跳过所属关系的判断，客户端携带转发header的请求仍然按照所属关系转发
+ */
+func (p *peerCluster) fromPeer(ctx context.Context) bool {
+	clientIP, _ := ctx.Value(utils.StringContext("client-ip")).(string)
+	return clientIP != "" && p.ring.containsHost(clientIP)
+}
+
+// 判断server是否为本机，没有配置本机地址时只比较host
+func (p *peerCluster) isLocal(peer string) bool {
+	if p.localPeer != "" {
+		return peer == p.localPeer
+	}
+	host, _, err := net.SplitHostPort(peer)
+	if err != nil {
+		return false
+	}
+	return host == p.localHost
+}
+
+/**
+ * @brief 定时从缓存中获取健康检查server列表
+ */
+func (p *peerCluster) watch(ctx context.Context) {
+	p.refresh()
+
+	ticker := time.NewTicker(peerRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.refresh()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// 刷新健康检查server列表
+func (p *peerCluster) refresh() {
+	service := server.caches.Service().GetServiceByName(p.service, p.namespace)
+	if service == nil {
+		log.Errorf("[health check] peer service(%s) namespace(%s) not found", p.service, p.namespace)
+		return
+	}
+
+	var peers []string
+	for _, instance := range server.caches.Instance().GetInstancesByServiceID(service.ID) {
+		if instance.Protocol() != peerProtocol {
+			continue
+		}
+		peers = append(peers, net.JoinHostPort(instance.Host(), strconv.Itoa(int(instance.Port()))))
+	}
+	if p.ring.update(peers) {
+		log.Infof("[health check] peers changed: %v", peers)
+	}
+}
+
+/**
+ * @brief 转发心跳到所属的server
+ */
+func (p *peerCluster) forward(ctx context.Context, peer string, instance *api.Instance) (*api.Response, error) {
+	marshaler := &jsonpb.Marshaler{}
+	body, err := marshaler.MarshalToString(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("http://%s/v1/Heartbeat", peer)
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Request-Id", ParseRequestID(ctx))
+	req.Header.Set(utils.HeartbeatForwardedHeader, "true")
+
+	rsp, err := p.client.Do(req)
+	if err != nil {
+		log.Errorf("[health check] forward heartbeat to peer(%s) err: %s", peer, err.Error())
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	// 业务错误码对应的http状态码不是200，但是回包中带有错误码，需要原样返回给客户端
+	out := &api.Response{}
+	unmarshaler := &jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err := unmarshaler.Unmarshal(rsp.Body, out); err != nil || out.GetCode() == nil {
+		log.Errorf("[health check] parse peer(%s) heartbeat response status: %s, err: %v", peer, rsp.Status, err)
+		return nil, fmt.Errorf("peer(%s) response status: %s", peer, rsp.Status)
+	}
+
+	log.Debug("[health check] forward heartbeat", ZapRequestID(ParseRequestID(ctx)),
+		zap.String("id", instance.GetId().GetValue()), zap.String("peer", peer))
+	return out, nil
+}
@@ -0,0 +1,208 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/utils"
+)
+
+// TestPeerRing_Owner 测试一致性hash选择server
+func TestPeerRing_Owner(t *testing.T) {
+	ring := newPeerRing()
+	if ring.owner("id") != "" {
+		t.Fatalf("empty ring should have no owner")
+	}
+
+	peers := []string{"127.0.0.1:8090", "127.0.0.2:8090", "127.0.0.3:8090"}
+	if !ring.update(peers) {
+		t.Fatalf("ring should be changed")
+	}
+	if ring.update([]string{peers[2], peers[1], peers[0]}) {
+		t.Fatalf("ring should not be changed with same peers")
+	}
+
+	owners := make(map[string]string)
+	counts := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		id := fmt.Sprintf("instance-%d", i)
+		owners[id] = ring.owner(id)
+		counts[owners[id]]++
+	}
+	for _, peer := range peers {
+		if counts[peer] == 0 {
+			t.Fatalf("peer(%s) owns no instance: %v", peer, counts)
+		}
+	}
+
+	// 移除一个server，只有它的实例会迁移
+	ring.update(peers[:2])
+	for id, owner := range owners {
+		newOwner := ring.owner(id)
+		if owner != peers[2] && newOwner != owner {
+			t.Fatalf("instance(%s) moved from %s to %s", id, owner, newOwner)
+		}
+	}
+}
+
+// TestPeerCluster_Forward 测试心跳转发，转发失败不改变实例的所属server
+func TestPeerCluster_Forward(t *testing.T) {
+	var forwarded bool
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		forwarded = r.URL.Path == "/v1/Heartbeat" && r.Header.Get(utils.HeartbeatForwardedHeader) != "" &&
+			strings.Contains(string(body), "ins-1")
+		_, _ = w.Write([]byte(`{"code":200000,"info":"execute success"}`))
+	}))
+	defer remote.Close()
+	remoteAddr := strings.TrimPrefix(remote.URL, "http://")
+
+	cluster := newPeerCluster(&HealthCheckConfig{LocalHost: "10.0.0.1"})
+	cluster.ring.update([]string{remoteAddr})
+	peer, ok := cluster.forwardTarget("ins-1")
+	if !ok || peer != remoteAddr {
+		t.Fatalf("expect forward to %s, got %s", remoteAddr, peer)
+	}
+	resp, err := cluster.forward(context.Background(), peer, &api.Instance{Id: utils.NewStringValue("ins-1")})
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	if resp.GetCode().GetValue() != api.ExecuteSuccess || !forwarded {
+		t.Fatalf("forward heartbeat failed: %+v", resp)
+	}
+
+	// 本机作为所属server时不转发
+	cluster.ring.update([]string{"10.0.0.1:8090"})
+	if _, ok := cluster.forwardTarget("ins-1"); ok || !cluster.ownedByLocal("ins-1") {
+		t.Fatalf("local instance should not be forwarded")
+	}
+
+	// 转发失败后所属server不变，不能由本机接管
+	remote.Close()
+	cluster.ring.update([]string{remoteAddr})
+	if _, err := cluster.forward(context.Background(), remoteAddr, &api.Instance{}); err == nil {
+		t.Fatalf("forward to closed peer should fail")
+	}
+	if peer, ok := cluster.forwardTarget("ins-1"); !ok || peer != remoteAddr || cluster.ownedByLocal("ins-1") {
+		t.Fatalf("owner should not change after forward failure")
+	}
+}
+
+// TestPeerCluster_ForwardStatus 测试所属server返回非200状态码时，带有错误码的回包原样返回，无法解析的回包转发失败
+func TestPeerCluster_ForwardStatus(t *testing.T) {
+	cases := []struct {
+		status int
+		body   string
+		code   uint32
+	}{
+		{http.StatusNotFound, `{"code":400202,"info":"not found resource"}`, api.NotFoundResource},
+		{http.StatusInternalServerError, `{"code":500000,"info":"execute exception"}`, api.ExecuteException},
+		{http.StatusTooManyRequests, "too many requests", 0},
+		{http.StatusBadGateway, "bad gateway", 0},
+	}
+	for _, c := range cases {
+		remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(c.status)
+			_, _ = w.Write([]byte(c.body))
+		}))
+		remoteAddr := strings.TrimPrefix(remote.URL, "http://")
+
+		cluster := newPeerCluster(&HealthCheckConfig{LocalHost: "10.0.0.1"})
+		resp, err := cluster.forward(context.Background(), remoteAddr, &api.Instance{})
+		remote.Close()
+		if c.code == 0 {
+			if err == nil {
+				t.Fatalf("status(%d) without response body should fail", c.status)
+			}
+			continue
+		}
+		if err != nil || resp.GetCode().GetValue() != c.code {
+			t.Fatalf("status(%d) should return code %d, got %v, err: %v", c.status, c.code, resp, err)
+		}
+	}
+}
+
+// TestPeerCluster_IsLocal 测试配置本机地址时同时比较host和port
+func TestPeerCluster_IsLocal(t *testing.T) {
+	cluster := newPeerCluster(&HealthCheckConfig{LocalHost: "10.0.0.1", LocalPeer: "10.0.0.1:8090"})
+	if !cluster.isLocal("10.0.0.1:8090") {
+		t.Fatalf("local peer should match")
+	}
+	if cluster.isLocal("10.0.0.1:8091") {
+		t.Fatalf("peer with the same host and another port should not be local")
+	}
+
+	cluster = newPeerCluster(&HealthCheckConfig{LocalHost: "10.0.0.1"})
+	if !cluster.isLocal("10.0.0.1:8091") || cluster.isLocal("10.0.0.2:8090") {
+		t.Fatalf("peer should be matched by host without local peer")
+	}
+}
+
+// TestMemoryBackend 测试内存心跳存储
+func TestMemoryBackend(t *testing.T) {
+	backend, err := newHeartbeatBackend(&HealthCheckConfig{Mode: HealthCheckMemory, LocalHost: "10.0.0.1"}, nil)
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	if record, _ := backend.Get("id"); record != nil {
+		t.Fatalf("record should not exist")
+	}
+	_ = backend.Set("id", Healthy, 100)
+	record, _ := backend.Get("id")
+	if record == nil || record.status != Healthy || record.beatTime != 100 || record.server != "10.0.0.1" {
+		t.Fatalf("record not match: %+v", record)
+	}
+	_ = backend.Del("id")
+	if record, _ := backend.Get("id"); record != nil {
+		t.Fatalf("record should be deleted")
+	}
+
+	if _, err := newHeartbeatBackend(&HealthCheckConfig{Mode: "unknown"}, nil); err == nil {
+		t.Fatalf("unknown mode should fail")
+	}
+}
+
+// TestPeerCluster_FromPeer 测试只信任来自集群中其他server的转发心跳
+func TestPeerCluster_FromPeer(t *testing.T) {
+	cluster := newPeerCluster(&HealthCheckConfig{LocalHost: "10.0.0.1"})
+	cluster.ring.update([]string{"10.0.0.1:8090", "10.0.0.2:8090"})
+
+	cases := map[string]bool{
+		"10.0.0.2":  true,
+		"10.0.0.3":  false,
+		"":          false,
+		"127.0.0.1": false,
+	}
+	for clientIP, expect := range cases {
+		ctx := context.WithValue(context.Background(), utils.StringContext("client-ip"), clientIP)
+		if cluster.fromPeer(ctx) != expect {
+			t.Fatalf("client(%s) from peer should be %v", clientIP, expect)
+		}
+	}
+	if cluster.fromPeer(context.Background()) {
+		t.Fatalf("request without client ip should not be from peer")
+	}
+}
@@ -0,0 +1,356 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/timewheel"
+	"github.com/polarismesh/polaris-server/common/utils"
+)
+
+const (
+	// 主动探测任务与实例缓存的同步间隔
+	probeSyncInterval = 5 * time.Second
+	// 默认的主动探测并发数
+	defaultProbeWorkers = 64
+	// 默认连续探测失败多少次后把实例置为不健康
+	defaultProbeFailThreshold = 2
+)
+
+// 探测不复用连接，保证每次探测都是一次完整的建连
+var probeTransport = &http.Transport{DisableKeepAlives: true}
+
+/**
+ * probeTask 实例的主动探测任务
+ */
+type probeTask struct {
+	id    string
+	addr  string
+	check *api.HealthCheck
+	// 连续探测失败的次数
+	failures int
+	stopped  int32
+}
+
+// 停止探测任务，时间轮中剩余的任务在到期后丢弃
+func (t *probeTask) stop() {
+	atomic.StoreInt32(&t.stopped, 1)
+}
+
+func (t *probeTask) isStopped() bool {
+	return atomic.LoadInt32(&t.stopped) == 1
+}
+
+// 探测间隔
+func (t *probeTask) interval() time.Duration {
+	return time.Duration(probeInterval(t.check)) * time.Second
+}
+
+/**
+ * HealthProber 主动健康探测器
+ * 对开启了TCP、HTTP健康检查的实例进行定时探测，
+ * 探测任务按照健康检查server集群的一致性hash分片，每个实例只由一台server探测
+ */
+type HealthProber struct {
+	ctx           context.Context
+	tw            *timewheel.TimeWheel
+	peers         *peerCluster
+	watchPeers    bool
+	workers       chan struct{}
+	failThreshold int
+
+	mutex sync.Mutex
+	tasks map[string]*probeTask
+}
+
+/**
+ * NewHealthProber 初始化主动健康探测器
+ * @note peers为空时，创建新的健康检查server集群用于探测任务分片
+ */
+func NewHealthProber(ctx context.Context, peers *peerCluster) *HealthProber {
+	workers := healthCheckConf.ProbeWorkers
+	if workers <= 0 {
+		workers = defaultProbeWorkers
+	}
+	failThreshold := healthCheckConf.ProbeFailThreshold
+	if failThreshold <= 0 {
+		failThreshold = defaultProbeFailThreshold
+	}
+
+	prober := &HealthProber{
+		ctx:           ctx,
+		tw:            timewheel.New(time.Second, healthCheckConf.SlotNum, "probe task timewheel"),
+		peers:         peers,
+		workers:       make(chan struct{}, workers),
+		failThreshold: failThreshold,
+		tasks:         make(map[string]*probeTask),
+	}
+	if prober.peers == nil {
+		prober.peers = newPeerCluster(healthCheckConf)
+		prober.watchPeers = true
+	}
+	return prober
+}
+
+/**
+ * UpdateConfig 热更新探测的并发数以及失败阈值
+ */
+func (p *HealthProber) UpdateConfig(conf *HealthCheckConfig) {
+	workers := conf.ProbeWorkers
+	if workers <= 0 {
+		workers = defaultProbeWorkers
+	}
+	failThreshold := conf.ProbeFailThreshold
+	if failThreshold <= 0 {
+		failThreshold = defaultProbeFailThreshold
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	// 正在执行的探测仍然归还到旧的并发控制管道
+	if cap(p.workers) != workers {
+		p.workers = make(chan struct{}, workers)
+	}
+	p.failThreshold = failThreshold
+}
+
+// 获取探测的并发控制管道以及失败阈值
+func (p *HealthProber) limits() (chan struct{}, int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.workers, p.failThreshold
+}
+
+/**
+ * Start 启动主动健康探测
+ */
+func (p *HealthProber) Start() {
+	p.tw.Start()
+	if p.watchPeers {
+		go p.peers.watch(p.ctx)
+	}
+	go p.run()
+}
+
+/**
+ * GetTaskSize 获取本机负责的探测任务数
+ */
+func (p *HealthProber) GetTaskSize() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return len(p.tasks)
+}
+
+// 定时同步探测任务
+func (p *HealthProber) run() {
+	p.sync()
+
+	ticker := time.NewTicker(probeSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.sync()
+		case <-p.ctx.Done():
+			p.tw.Stop()
+			return
+		}
+	}
+}
+
+/**
+ * @brief 根据实例缓存同步探测任务
+ * 新增本机负责的实例的任务，停止实例已删除、配置已变更或者不再归本机负责的任务
+ */
+func (p *HealthProber) sync() {
+	current := make(map[string]*model.Instance)
+	_ = server.caches.Instance().IteratorInstances(func(key string, value *model.Instance) (bool, error) {
+		if needProbe(value) && p.peers.ownedByLocal(key) {
+			current[key] = value
+		}
+		return true, nil
+	})
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for id, task := range p.tasks {
+		instance, ok := current[id]
+		if ok && task.addr == probeAddr(instance) && proto.Equal(task.check, instance.HealthCheck()) {
+			continue
+		}
+		task.stop()
+		delete(p.tasks, id)
+	}
+
+	for id, instance := range current {
+		if _, ok := p.tasks[id]; ok {
+			continue
+		}
+		task := &probeTask{
+			id:    id,
+			addr:  probeAddr(instance),
+			check: proto.Clone(instance.HealthCheck()).(*api.HealthCheck),
+		}
+		p.tasks[id] = task
+		// 首次探测的时间随机打散，避免同一时刻集中探测
+		delay := time.Duration(rand.Int63n(int64(task.interval()))) + time.Second
+		_ = p.tw.AddTask(delay, task, p.probeCallback)
+	}
+}
+
+/**
+ * @brief 时间轮回调函数：执行一次探测，并放入下一次探测任务
+ */
+func (p *HealthProber) probeCallback(data interface{}) {
+	task := data.(*probeTask)
+	if task.isStopped() {
+		return
+	}
+
+	workers, _ := p.limits()
+	select {
+	case workers <- struct{}{}:
+	case <-p.ctx.Done():
+		return
+	}
+	err := probeInstance(task.check, task.addr)
+	<-workers
+
+	if task.isStopped() {
+		return
+	}
+	p.handleResult(task, err)
+	_ = p.tw.AddTask(task.interval(), task, p.probeCallback)
+}
+
+// 处理探测结果，健康状态有变化时修改db状态
+func (p *HealthProber) handleResult(task *probeTask, probeErr error) {
+	insCache := server.caches.Instance().GetInstance(task.id)
+	if insCache == nil || !insCache.EnableHealthCheck() {
+		return
+	}
+
+	if probeErr == nil {
+		task.failures = 0
+		if insCache.Healthy() != true {
+			setInsDbStatus(task.id, task.addr, Healthy)
+		}
+		return
+	}
+
+	task.failures++
+	log.Debugf("[health check] probe addr:%s id:%s failed %d times, err: %s",
+		task.addr, task.id, task.failures, probeErr.Error())
+	if _, failThreshold := p.limits(); task.failures >= failThreshold && insCache.Healthy() != false {
+		setInsDbStatus(task.id, task.addr, NotHealthy)
+	}
+}
+
+// 判断实例是否需要主动探测
+func needProbe(instance *model.Instance) bool {
+	if !instance.EnableHealthCheck() {
+		return false
+	}
+	checkType := instance.HealthCheck().GetType()
+	return checkType == api.HealthCheck_TCP || checkType == api.HealthCheck_HTTP
+}
+
+// 实例的探测地址
+func probeAddr(instance *model.Instance) string {
+	return net.JoinHostPort(instance.Host(), strconv.Itoa(int(instance.Port())))
+}
+
+// 探测间隔，单位秒
+func probeInterval(check *api.HealthCheck) uint32 {
+	var interval uint32
+	switch check.GetType() {
+	case api.HealthCheck_TCP:
+		interval = check.GetTcp().GetInterval().GetValue()
+	case api.HealthCheck_HTTP:
+		interval = check.GetHttp().GetInterval().GetValue()
+	}
+	if interval == 0 {
+		interval = utils.DefaultProbeInterval
+	}
+	return interval
+}
+
+/**
+ * @brief 对实例执行一次探测
+ * TCP探测建连成功即为健康，HTTP探测返回码与期望的状态码一致才为健康
+ */
+func probeInstance(check *api.HealthCheck, addr string) error {
+	switch check.GetType() {
+	case api.HealthCheck_TCP:
+		timeout := probeTimeout(check.GetTcp().GetTimeout().GetValue())
+		conn, err := net.DialTimeout("tcp", addr, timeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case api.HealthCheck_HTTP:
+		httpCheck := check.GetHttp()
+		client := &http.Client{
+			Transport: probeTransport,
+			Timeout:   probeTimeout(httpCheck.GetTimeout().GetValue()),
+		}
+		path := httpCheck.GetPath().GetValue()
+		if path == "" {
+			path = utils.DefaultProbePath
+		}
+		expectedStatus := httpCheck.GetExpectedStatus().GetValue()
+		if expectedStatus == 0 {
+			expectedStatus = utils.DefaultProbeExpectedStatus
+		}
+		rsp, err := client.Get("http://" + addr + path)
+		if err != nil {
+			return err
+		}
+		_, _ = io.Copy(ioutil.Discard, rsp.Body)
+		_ = rsp.Body.Close()
+		if uint32(rsp.StatusCode) != expectedStatus {
+			return fmt.Errorf("unexpected status code %d, expected %d", rsp.StatusCode, expectedStatus)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported probe type: %s", check.GetType().String())
+	}
+}
+
+// 探测超时时间
+func probeTimeout(seconds uint32) time.Duration {
+	if seconds == 0 {
+		seconds = utils.DefaultProbeTimeout
+	}
+	return time.Duration(seconds) * time.Second
+}
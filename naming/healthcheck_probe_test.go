@@ -0,0 +1,121 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/utils"
+)
+
+// TestProbeInstance_TCP 测试TCP探测
+func TestProbeInstance_TCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	addr := listener.Addr().String()
+
+	check := &api.HealthCheck{Tcp: &api.TcpHealthCheck{}}
+	utils.NormalizeHealthCheck(check)
+	if check.GetType() != api.HealthCheck_TCP {
+		t.Fatalf("health check type should be tcp, got %s", check.GetType())
+	}
+	if err := probeInstance(check, addr); err != nil {
+		t.Fatalf("probe listening addr should success: %s", err.Error())
+	}
+
+	_ = listener.Close()
+	if err := probeInstance(check, addr); err == nil {
+		t.Fatalf("probe closed addr should fail")
+	}
+}
+
+// TestProbeInstance_HTTP 测试HTTP探测
+func TestProbeInstance_HTTP(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer svr.Close()
+	addr := strings.TrimPrefix(svr.URL, "http://")
+
+	check := &api.HealthCheck{
+		Type: api.HealthCheck_HTTP,
+		Http: &api.HttpHealthCheck{
+			Path:           utils.NewStringValue("health"),
+			ExpectedStatus: utils.NewUInt32Value(http.StatusNoContent),
+		},
+	}
+	utils.NormalizeHealthCheck(check)
+	if check.GetHttp().GetPath().GetValue() != "/health" {
+		t.Fatalf("path should be normalized, got %s", check.GetHttp().GetPath().GetValue())
+	}
+	if check.GetHttp().GetInterval().GetValue() != utils.DefaultProbeInterval {
+		t.Fatalf("interval should be default, got %d", check.GetHttp().GetInterval().GetValue())
+	}
+	if err := probeInstance(check, addr); err != nil {
+		t.Fatalf("probe should success: %s", err.Error())
+	}
+
+	// 状态码与期望的不一致
+	check.Http.ExpectedStatus = utils.NewUInt32Value(http.StatusOK)
+	if err := probeInstance(check, addr); err == nil {
+		t.Fatalf("probe with unexpected status should fail")
+	}
+	check.Http.Path = utils.NewStringValue("/not-exist")
+	if err := probeInstance(check, addr); err == nil {
+		t.Fatalf("probe not exist path should fail")
+	}
+}
+
+// TestNormalizeHealthCheck 测试健康检查类型的确定
+func TestNormalizeHealthCheck(t *testing.T) {
+	check := &api.HealthCheck{
+		Type:      api.HealthCheck_HTTP,
+		Heartbeat: &api.HeartbeatHealthCheck{Ttl: utils.NewUInt32Value(100)},
+	}
+	utils.NormalizeHealthCheck(check)
+	if check.GetType() != api.HealthCheck_HEARTBEAT || check.GetHeartbeat().GetTtl().GetValue() != DefaultTLL {
+		t.Fatalf("health check should fallback to heartbeat: %+v", check)
+	}
+
+	check = &api.HealthCheck{
+		Type: api.HealthCheck_TCP,
+		Tcp: &api.TcpHealthCheck{
+			Interval: utils.NewUInt32Value(3),
+			Timeout:  utils.NewUInt32Value(10),
+		},
+		Heartbeat: &api.HeartbeatHealthCheck{},
+	}
+	utils.NormalizeHealthCheck(check)
+	if check.GetType() != api.HealthCheck_TCP || check.GetHeartbeat() != nil {
+		t.Fatalf("health check should be tcp only: %+v", check)
+	}
+	if check.GetTcp().GetTimeout().GetValue() != 3 {
+		t.Fatalf("timeout should not exceed interval, got %d", check.GetTcp().GetTimeout().GetValue())
+	}
+}
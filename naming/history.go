@@ -0,0 +1,203 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming/auth"
+)
+
+// 用户只能读取部分操作记录时，每次从存储层扫描的记录数
+const historyScanBatch = 200
+
+// 操作记录支持的查询条件
+var historyFilterAttributes = map[string]bool{
+	"resource":  true,
+	"operation": true,
+	"namespace": true,
+	"service":   true,
+	"operator":  true,
+	"offset":    true,
+	"limit":     true,
+}
+
+/**
+ * GetHistories 查询操作记录，按照时间倒序
+ * 时间范围通过start_time和end_time指定，格式为2006-01-02 15:04:05或者unix时间戳
+ * 登录用户只能查询有读权限的命名空间以及服务的记录
+ */
+func (s *Server) GetHistories(ctx context.Context, query map[string]string) *api.BatchQueryResponse {
+	allow, code := s.recordReadFilter(ctx)
+	if code != api.ExecuteSuccess {
+		return api.NewBatchQueryResponse(code)
+	}
+
+	start, err := parseHistoryTime(query["start_time"])
+	if err != nil {
+		return api.NewBatchQueryResponseWithMsg(api.InvalidParameter, "start_time is invalid")
+	}
+	end, err := parseHistoryTime(query["end_time"])
+	if err != nil {
+		return api.NewBatchQueryResponseWithMsg(api.InvalidParameter, "end_time is invalid")
+	}
+	delete(query, "start_time")
+	delete(query, "end_time")
+
+	for key := range query {
+		if _, ok := historyFilterAttributes[key]; !ok {
+			log.Errorf("[Server][History] attribute(%s) is not allowed", key)
+			return api.NewBatchQueryResponseWithMsg(api.InvalidParameter, key+" is not allowed")
+		}
+	}
+	offset, limit, err := ParseOffsetAndLimit(query)
+	if err != nil {
+		return api.NewBatchQueryResponseWithMsg(api.InvalidParameter, err.Error())
+	}
+
+	var total uint32
+	var histories []*model.History
+	if allow == nil {
+		total, histories, err = s.storage.GetHistories(query, start, end, offset, limit)
+	} else {
+		total, histories, err = s.scanHistories(query, start, end, offset, limit, allow)
+	}
+	if err != nil {
+		log.Errorf("[Server][History] get histories store err: %s", err.Error())
+		return api.NewBatchQueryResponse(api.StoreLayerException)
+	}
+
+	resp := api.NewBatchQueryResponse(api.ExecuteSuccess)
+	resp.Amount = utils.NewUInt32Value(total)
+	resp.Size = utils.NewUInt32Value(uint32(len(histories)))
+	resp.Histories = make([]*api.History, 0, len(histories))
+	for _, history := range histories {
+		resp.Histories = append(resp.Histories, history2API(history))
+	}
+	return resp
+}
+
+// 分批扫描全部满足条件的记录，过滤掉没有权限的记录后再分页，保证总数以及分页准确
+func (s *Server) scanHistories(query map[string]string, start, end time.Time, offset, limit uint32,
+	allow recordFilter) (uint32, []*model.History, error) {
+	var total uint32
+	var out []*model.History
+	for scanned := uint32(0); ; scanned += historyScanBatch {
+		_, histories, err := s.storage.GetHistories(query, start, end, scanned, historyScanBatch)
+		if err != nil {
+			return 0, nil, err
+		}
+		for _, history := range histories {
+			if !allow(history.ResourceType, history.Namespace, history.Service) {
+				continue
+			}
+			if total >= offset && uint32(len(out)) < limit {
+				out = append(out, history)
+			}
+			total++
+		}
+		if len(histories) < historyScanBatch {
+			return total, out, nil
+		}
+	}
+}
+
+// 判断是否可以读取资源的操作记录或者变更事件
+type recordFilter func(resource string, namespace string, service string) bool
+
+/**
+ * @brief 根据登录用户的权限生成操作记录以及变更事件的过滤函数，返回nil表示不需要过滤
+ * 没有登录用户时与其他的读接口一致，只有开启了必须登录才拒绝
+ */
+func (s *Server) recordReadFilter(ctx context.Context) (recordFilter, uint32) {
+	if s.authority == nil {
+		return nil, api.ExecuteSuccess
+	}
+	user := ParseUser(ctx)
+	if user == "" {
+		if s.authority.UserRequired() {
+			return nil, api.UserLoginRequired
+		}
+		return nil, api.ExecuteSuccess
+	}
+	// 拥有全部资源读权限的用户不需要逐条过滤
+	if s.authority.VerifyUser(user, &auth.Permission{Resource: auth.ResourceAll, Action: auth.ActionRead}) {
+		return nil, api.ExecuteSuccess
+	}
+	return func(resource string, namespace string, service string) bool {
+		return s.authority.VerifyUser(user, &auth.Permission{
+			Resource:  auth.RecordResource(resource),
+			Namespace: namespace,
+			Service:   service,
+			Action:    auth.ActionRead,
+		})
+	}, api.ExecuteSuccess
+}
+
+// 解析操作记录查询的时间，为空返回零值
+func parseHistoryTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("time(%s) is invalid", value)
+	}
+	return t, nil
+}
+
+// 把操作记录转换为API结构
+func history2API(history *model.History) *api.History {
+	out := &api.History{
+		Id:        utils.NewUInt64Value(history.ID),
+		Resource:  utils.NewStringValue(history.ResourceType),
+		Operation: utils.NewStringValue(history.OperationType),
+		Namespace: utils.NewStringValue(history.Namespace),
+		Service:   utils.NewStringValue(history.Service),
+		Context:   utils.NewStringValue(history.Context),
+		Operator:  utils.NewStringValue(history.Operator),
+		Revision:  utils.NewStringValue(history.Revision),
+		Ctime:     utils.NewStringValue(time2String(history.CreateTime)),
+	}
+	if history.Before != "" {
+		out.Before = utils.NewStringValue(history.Before)
+	}
+	if history.After != "" {
+		out.After = utils.NewStringValue(history.After)
+	}
+	return out
+}
+
+// 为操作记录补充变更前的资源快照
+func withBefore(entry *model.RecordEntry, before proto.Message) *model.RecordEntry {
+	if entry != nil {
+		entry.Before = before
+	}
+	return entry
+}
@@ -0,0 +1,61 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming/auth"
+	authmock "github.com/polarismesh/polaris-server/naming/auth/mock"
+	"github.com/polarismesh/polaris-server/store/mock"
+)
+
+// TestServer_GetHistoriesPermission 测试登录用户只能查询有读权限的操作记录，并且总数以及分页按照过滤后的记录计算
+func TestServer_GetHistoriesPermission(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	authority := authmock.NewMockAuthority(ctl)
+	authority.EXPECT().VerifyUser("reader", gomock.Any()).DoAndReturn(
+		func(_ string, permission *auth.Permission) bool {
+			return permission.Resource == auth.ResourceService && permission.Namespace == "Test"
+		}).AnyTimes()
+	storage := mock.NewMockStore(ctl)
+	storage.EXPECT().GetHistories(gomock.Any(), gomock.Any(), gomock.Any(), uint32(0), uint32(historyScanBatch)).
+		Return(uint32(4), []*model.History{
+			{ID: 4, ResourceType: string(model.RInstance), Namespace: "Test", Service: "svc"},
+			{ID: 3, ResourceType: string(model.RInstance), Namespace: "Production", Service: "svc"},
+			{ID: 2, ResourceType: string(model.RRouting), Namespace: "Test", Service: "svc"},
+			{ID: 1, ResourceType: string(model.RService), Namespace: "Test", Service: "svc"},
+		}, nil)
+	s := &Server{authority: authority, storage: storage}
+
+	ctx := context.WithValue(context.Background(), utils.StringContext("user"), "reader")
+	resp := s.GetHistories(ctx, map[string]string{"offset": "1", "limit": "1"})
+	if resp.GetCode().GetValue() != api.ExecuteSuccess {
+		t.Fatalf("get histories code: %d", resp.GetCode().GetValue())
+	}
+	if resp.GetAmount().GetValue() != 2 || len(resp.GetHistories()) != 1 ||
+		resp.GetHistories()[0].GetId().GetValue() != 1 {
+		t.Fatalf("histories should be filtered by permission: %+v", resp)
+	}
+}
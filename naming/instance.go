@@ -22,10 +22,13 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	api "github.com/polarismesh/polaris-server/common/api/v1"
 	"github.com/polarismesh/polaris-server/common/log"
 	"github.com/polarismesh/polaris-server/common/model"
 	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming/auth"
+	"github.com/polarismesh/polaris-server/naming/batch"
 	"go.uber.org/zap"
 )
 
@@ -127,22 +130,23 @@ func (s *Server) CreateInstance(ctx context.Context, req *api.Instance) *api.Res
 // store operate
 func (s *Server) createInstance(ctx context.Context, req *api.Instance, ins *api.Instance) (
 	*model.Instance, *api.Response) {
-	if server.bc == nil || !server.bc.CreateInstanceOpen() {
+	bc := s.batchCtrl()
+	if bc == nil || !bc.CreateInstanceOpen() {
 		return s.serialCreateInstance(ctx, req, ins) // 单个同步
 	}
 
-	return s.asyncCreateInstance(ctx, req, ins) // 批量异步
+	return s.asyncCreateInstance(ctx, bc, req, ins) // 批量异步
 }
 
 // 异步新建实例
 // 底层函数会合并create请求，增加并发创建的吞吐
 // req 原始请求
 // ins 包含了req数据与instanceID，serviceToken
-func (s *Server) asyncCreateInstance(ctx context.Context, req *api.Instance, ins *api.Instance) (
-	*model.Instance, *api.Response) {
+func (s *Server) asyncCreateInstance(ctx context.Context, bc *batch.Controller, req *api.Instance,
+	ins *api.Instance) (*model.Instance, *api.Response) {
 	rid := ParseRequestID(ctx)
 	pid := ParsePlatformID(ctx)
-	future := s.bc.AsyncCreateInstance(ins, ParsePlatformID(ctx), ParsePlatformToken(ctx))
+	future := bc.AsyncCreateInstance(ins, ParsePlatformID(ctx), ParsePlatformToken(ctx))
 	if err := future.Wait(); err != nil {
 		log.Error(err.Error(), ZapRequestID(rid), ZapPlatformID(pid))
 		if future.Code() == api.ExistedResource {
@@ -234,11 +238,12 @@ func (s *Server) DeleteInstance(ctx context.Context, req *api.Instance) *api.Res
 // req 原始请求
 // ins 填充了instanceID与serviceToken
 func (s *Server) deleteInstance(ctx context.Context, req *api.Instance, ins *api.Instance) *api.Response {
-	if s.bc == nil || !s.bc.DeleteInstanceOpen() {
+	bc := s.batchCtrl()
+	if bc == nil || !bc.DeleteInstanceOpen() {
 		return s.serialDeleteInstance(ctx, req, ins)
 	}
 
-	return s.asyncDeleteInstance(ctx, req, ins)
+	return s.asyncDeleteInstance(ctx, bc, req, ins)
 }
 
 // 串行删除实例
@@ -279,11 +284,12 @@ func (s *Server) serialDeleteInstance(ctx context.Context, req *api.Instance, in
 
 // 异步删除实例
 // 返回实例所属的服务和resp
-func (s *Server) asyncDeleteInstance(ctx context.Context, req *api.Instance, ins *api.Instance) *api.Response {
+func (s *Server) asyncDeleteInstance(ctx context.Context, bc *batch.Controller, req *api.Instance,
+	ins *api.Instance) *api.Response {
 	start := time.Now()
 	rid := ParseRequestID(ctx)
 	pid := ParsePlatformID(ctx)
-	future := s.bc.AsyncDeleteInstance(ins, ParsePlatformID(ctx), ParsePlatformToken(ctx))
+	future := bc.AsyncDeleteInstance(ins, ParsePlatformID(ctx), ParsePlatformToken(ctx))
 	if err := future.Wait(); err != nil {
 		// 如果发现不存在资源，意味着实例已经被删除，直接返回成功
 		if future.Code() == api.NotFoundResource {
@@ -384,6 +390,7 @@ func (s *Server) UpdateInstance(ctx context.Context, req *api.Instance) *api.Res
 	requestID := ParseRequestID(ctx)
 	platformID := ParsePlatformID(ctx)
 	log.Info(fmt.Sprintf("old instance: %+v", instance), ZapRequestID(requestID), ZapPlatformID(platformID))
+	before := instanceSnapshot(instance)
 
 	// 存储层操作
 	if needUpdate := s.updateInstanceAttribute(req, instance); !needUpdate {
@@ -400,7 +407,7 @@ func (s *Server) UpdateInstance(ctx context.Context, req *api.Instance) *api.Res
 		instance.ID(), service.Namespace, service.Name, instance.Host(),
 		instance.Port(), instance.Healthy())
 	log.Info(msg, ZapRequestID(requestID), ZapPlatformID(platformID))
-	s.RecordHistory(instanceRecordEntry(ctx, service, instance, model.OUpdate))
+	s.RecordHistory(withBefore(instanceRecordEntry(ctx, service, instance, model.OUpdate), before))
 
 	return api.NewInstanceResponse(api.ExecuteSuccess, req)
 }
@@ -602,10 +609,10 @@ func updateHealthCheck(req *api.Instance, instance *model.Instance) bool {
 	needUpdate := false
 	insProto := instance.Proto
 	// health Check，healthCheck不能为空，且没有把enable_health_check置为false
-	if req.GetHealthCheck().GetHeartbeat() != nil &&
+	if utils.HasHealthCheck(req.GetHealthCheck()) &&
 		(req.GetEnableHealthCheck() == nil || req.GetEnableHealthCheck().GetValue()) {
 		// 如果数据库中实例原有是不打开健康检查，
-		// 那么一旦打开，status需置为false，等待一次心跳成功才能变成true
+		// 那么一旦打开，status需置为false，等待一次心跳或者探测成功才能变成true
 		if instance.EnableHealthCheck() == false {
 			// 需要重置healthy，则认为有变更
 			insProto.Healthy = utils.NewBoolValue(false)
@@ -613,24 +620,13 @@ func updateHealthCheck(req *api.Instance, instance *model.Instance) bool {
 			needUpdate = true
 		}
 
-		ttl := req.GetHealthCheck().GetHeartbeat().GetTtl().GetValue()
-		if ttl == 0 || ttl > 60 {
-			ttl = DefaultTLL
-		}
-		if ttl != instance.HealthCheck().GetHeartbeat().GetTtl().GetValue() {
-			// ttl有变更
+		healthCheck := req.GetHealthCheck()
+		utils.NormalizeHealthCheck(healthCheck)
+		if !proto.Equal(healthCheck, instance.HealthCheck()) {
+			// health check的类型或者参数有变更
 			needUpdate = true
 		}
-		if api.HealthCheck_HEARTBEAT != instance.HealthCheck().GetType() {
-			// health check type有变更
-			needUpdate = true
-		}
-		insProto.HealthCheck = req.GetHealthCheck()
-		insProto.HealthCheck.Type = api.HealthCheck_HEARTBEAT
-		if insProto.HealthCheck.Heartbeat.Ttl == nil {
-			insProto.HealthCheck.Heartbeat.Ttl = utils.NewUInt32Value(0)
-		}
-		insProto.HealthCheck.Heartbeat.Ttl.Value = ttl
+		insProto.HealthCheck = healthCheck
 	}
 
 	// update的时候，修改了enableHealthCheck的值
@@ -822,6 +818,14 @@ func (s *Server) instanceAuth(ctx context.Context, req *api.Instance, serviceID
  * @brief 实例鉴权
  */
 func (s *Server) verifyInstanceAuth(ctx context.Context, service *model.Service, req *api.Instance) *api.Response {
+	// 带有登录用户时使用用户的角色鉴权
+	if found, ok := s.verifyAuthByUser(ctx, auth.ResourceService, service.Namespace, service.Name); found {
+		if !ok {
+			return api.NewInstanceResponse(api.Unauthorized, req)
+		}
+		return nil
+	}
+
 	if ok := s.verifyAuthByPlatform(ctx, service.PlatformID); !ok {
 		// 检查token是否存在
 		serviceToken := parseInstanceReqToken(ctx, req)
@@ -1169,9 +1173,25 @@ func instanceRecordEntry(ctx context.Context, service *model.Service, ins *model
 	} else {
 		entry.Context = fmt.Sprintf("host:%s,port:%d", ins.Host(), ins.Port())
 	}
+	if opt == model.ODelete {
+		entry.Before = instanceSnapshot(ins)
+	} else if ins.Proto != nil {
+		entry.Revision = ins.Revision()
+		entry.Object = instanceSnapshot(ins)
+	}
 	return entry
 }
 
+// 实例的快照，不包括token
+func instanceSnapshot(ins *model.Instance) *api.Instance {
+	if ins == nil || ins.Proto == nil {
+		return nil
+	}
+	out := proto.Clone(ins.Proto).(*api.Instance)
+	out.ServiceToken = nil
+	return out
+}
+
 // CheckDbInstanceFieldLen 检查DB中service表对应的入参字段合法性
 func CheckDbInstanceFieldLen(req *api.Instance) (*api.Response, bool) {
 	if err := CheckDbStrFieldLen(req.GetService(), MaxDbServiceNameLength); err != nil {
@@ -0,0 +1,227 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package k8ssync
+
+import (
+	"context"
+	"encoding/json"
+)
+
+const (
+	// ResourceServices k8s的Service资源
+	ResourceServices = "services"
+	// ResourceEndpoints k8s的Endpoints资源
+	ResourceEndpoints = "endpoints"
+	// ResourceEndpointSlices k8s的EndpointSlice资源
+	ResourceEndpointSlices = "endpointslices"
+
+	// WatchError watch的错误事件，需要重新开始watch
+	WatchError = "ERROR"
+	// WatchBookmark watch的书签事件，只更新resourceVersion
+	WatchBookmark = "BOOKMARK"
+)
+
+/**
+ * Client 访问k8s API server的客户端，只包含同步需要的操作
+ */
+type Client interface {
+	// 查询全部命名空间的Service
+	ListServices(ctx context.Context) (*ServiceList, error)
+	// 查询全部命名空间的Endpoints
+	ListEndpoints(ctx context.Context) (*EndpointsList, error)
+	// 查询全部命名空间的EndpointSlice
+	ListEndpointSlices(ctx context.Context) (*EndpointSliceList, error)
+	// 从resourceVersion开始watch资源的变更，ctx结束或者连接断开时关闭channel
+	Watch(ctx context.Context, resource string, resourceVersion string) (<-chan WatchEvent, error)
+	// 创建或者更新Service
+	ApplyService(ctx context.Context, service *Service) error
+	// 创建或者更新Endpoints
+	ApplyEndpoints(ctx context.Context, endpoints *Endpoints) error
+	// 删除Service，不存在时不返回错误
+	DeleteService(ctx context.Context, namespace string, name string) error
+	// 删除Endpoints，不存在时不返回错误
+	DeleteEndpoints(ctx context.Context, namespace string, name string) error
+}
+
+/**
+ * WatchEvent watch的事件
+ */
+type WatchEvent struct {
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// resourceVersion 事件中对象的resourceVersion
+func (e *WatchEvent) resourceVersion() string {
+	var object struct {
+		Metadata ObjectMeta `json:"metadata"`
+	}
+	if err := json.Unmarshal(e.Object, &object); err != nil {
+		return ""
+	}
+	return object.Metadata.ResourceVersion
+}
+
+/**
+ * ObjectMeta k8s对象的元数据
+ */
+type ObjectMeta struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace,omitempty"`
+	ResourceVersion string            `json:"resourceVersion,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+}
+
+/**
+ * ListMeta k8s列表的元数据
+ */
+type ListMeta struct {
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+/**
+ * ObjectReference 引用的k8s对象，一般为pod
+ */
+type ObjectReference struct {
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+/**
+ * Service k8s的Service
+ */
+type Service struct {
+	Kind       string      `json:"kind,omitempty"`
+	APIVersion string      `json:"apiVersion,omitempty"`
+	Metadata   ObjectMeta  `json:"metadata"`
+	Spec       ServiceSpec `json:"spec"`
+}
+
+/**
+ * ServiceSpec Service的定义
+ */
+type ServiceSpec struct {
+	Type      string            `json:"type,omitempty"`
+	ClusterIP string            `json:"clusterIP,omitempty"`
+	Selector  map[string]string `json:"selector,omitempty"`
+	Ports     []ServicePort     `json:"ports,omitempty"`
+}
+
+/**
+ * ServicePort Service的端口
+ */
+type ServicePort struct {
+	Name     string `json:"name,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+	Port     int32  `json:"port"`
+}
+
+/**
+ * ServiceList Service列表
+ */
+type ServiceList struct {
+	Metadata ListMeta  `json:"metadata"`
+	Items    []Service `json:"items"`
+}
+
+/**
+ * Endpoints k8s的Endpoints
+ */
+type Endpoints struct {
+	Kind       string     `json:"kind,omitempty"`
+	APIVersion string     `json:"apiVersion,omitempty"`
+	Metadata   ObjectMeta `json:"metadata"`
+	// 不能省略，更新时为空表示清空全部地址
+	Subsets []EndpointSubset `json:"subsets"`
+}
+
+/**
+ * EndpointSubset 一组相同端口的地址
+ */
+type EndpointSubset struct {
+	Addresses         []EndpointAddress `json:"addresses,omitempty"`
+	NotReadyAddresses []EndpointAddress `json:"notReadyAddresses,omitempty"`
+	Ports             []EndpointPort    `json:"ports,omitempty"`
+}
+
+/**
+ * EndpointAddress Endpoints的地址
+ */
+type EndpointAddress struct {
+	IP        string           `json:"ip"`
+	Hostname  string           `json:"hostname,omitempty"`
+	NodeName  string           `json:"nodeName,omitempty"`
+	TargetRef *ObjectReference `json:"targetRef,omitempty"`
+}
+
+/**
+ * EndpointPort Endpoints的端口
+ */
+type EndpointPort struct {
+	Name        string `json:"name,omitempty"`
+	Port        int32  `json:"port"`
+	Protocol    string `json:"protocol,omitempty"`
+	AppProtocol string `json:"appProtocol,omitempty"`
+}
+
+/**
+ * EndpointsList Endpoints列表
+ */
+type EndpointsList struct {
+	Metadata ListMeta    `json:"metadata"`
+	Items    []Endpoints `json:"items"`
+}
+
+/**
+ * EndpointSlice k8s的EndpointSlice，discovery.k8s.io/v1
+ */
+type EndpointSlice struct {
+	Metadata    ObjectMeta     `json:"metadata"`
+	AddressType string         `json:"addressType"`
+	Endpoints   []Endpoint     `json:"endpoints"`
+	Ports       []EndpointPort `json:"ports"`
+}
+
+/**
+ * Endpoint EndpointSlice中的地址
+ */
+type Endpoint struct {
+	Addresses  []string           `json:"addresses"`
+	Conditions EndpointConditions `json:"conditions"`
+	Hostname   string             `json:"hostname,omitempty"`
+	NodeName   string             `json:"nodeName,omitempty"`
+	Zone       string             `json:"zone,omitempty"`
+	TargetRef  *ObjectReference   `json:"targetRef,omitempty"`
+}
+
+/**
+ * EndpointConditions 地址的状态，ready为空时表示就绪
+ */
+type EndpointConditions struct {
+	Ready *bool `json:"ready,omitempty"`
+}
+
+/**
+ * EndpointSliceList EndpointSlice列表
+ */
+type EndpointSliceList struct {
+	Metadata ListMeta        `json:"metadata"`
+	Items    []EndpointSlice `json:"items"`
+}
@@ -0,0 +1,160 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package k8ssync
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// MetaSyncSource 同步来源的元数据，由k8s同步创建的服务和实例带有该标记
+	MetaSyncSource = "polaris.sync.source"
+	// MetaSyncCluster 同步来源的k8s集群名
+	MetaSyncCluster = "polaris.sync.cluster"
+	// 同步来源的取值
+	syncSourceKubernetes = "kubernetes"
+
+	// AnnotationSync k8s服务上的注解，取值为false时不同步该服务
+	AnnotationSync = "polaris.io/sync"
+	// LabelManagedBy 反向同步创建的k8s对象的标签
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+	// 反向同步创建的k8s对象的标签取值
+	managedByPolaris = "polaris-server"
+	// 反向同步的对象内容摘要，内容没有变化时不需要写k8s
+	annotationRevision = "polaris.io/revision"
+
+	// EndpointSourceEndpoints 从Endpoints获取服务的实例
+	EndpointSourceEndpoints = "endpoints"
+	// EndpointSourceSlices 从EndpointSlices获取服务的实例
+	EndpointSourceSlices = "endpointslices"
+)
+
+const (
+	defaultClusterName = "kubernetes"
+	defaultOwner       = "polaris"
+	// 默认全量同步的间隔，单位为秒
+	defaultResyncInterval = 60
+	// 默认反向同步的间隔，单位为秒
+	defaultReverseInterval = 30
+)
+
+// 默认不同步的k8s命名空间
+var defaultExcludeNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+// 默认不同步到元数据的注解前缀
+var defaultIgnoreAnnotations = []string{"kubectl.kubernetes.io/"}
+
+/**
+ * Config k8s服务同步的配置
+ */
+type Config struct {
+	Open bool `yaml:"open"`
+	// k8s集群名，写入同步的服务和实例的元数据中，用于区分同步的归属
+	ClusterName string `yaml:"clusterName"`
+	// k8s API server的地址，为空时使用集群内的service account访问
+	APIServer string `yaml:"apiServer"`
+	// 访问k8s API server的token，可以配置为密码插件加密后的密文，为空时读取tokenFile
+	Token     string `yaml:"token"`
+	TokenFile string `yaml:"tokenFile"`
+	// 校验k8s API server证书的CA，PEM格式
+	CAFile             string `yaml:"caFile"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify"`
+	// 实例的来源：endpoints、endpointslices，默认为endpoints
+	EndpointSource string `yaml:"endpointSource"`
+	// 全量同步的间隔，单位为秒
+	ResyncInterval int `yaml:"resyncInterval"`
+	// 同步创建的服务的负责人
+	Owner string `yaml:"owner"`
+	// k8s命名空间到北极星命名空间的映射规则，按顺序匹配，为空时使用同名的命名空间
+	NamespaceMapping []NamespaceRule `yaml:"namespaceMapping"`
+	// 不同步的k8s命名空间
+	ExcludeNamespaces []string `yaml:"excludeNamespaces"`
+	// 不同步到元数据的注解前缀
+	IgnoreAnnotations []string `yaml:"ignoreAnnotations"`
+	// 把北极星的服务同步到k8s
+	ReverseSync ReverseSyncConfig `yaml:"reverseSync"`
+}
+
+/**
+ * NamespaceRule 命名空间的映射规则
+ */
+type NamespaceRule struct {
+	// k8s的命名空间，*表示匹配全部
+	Kubernetes string `yaml:"kubernetes"`
+	// 北极星的命名空间，为空表示与k8s的命名空间同名
+	Polaris string `yaml:"polaris"`
+}
+
+/**
+ * ReverseSyncConfig 反向同步的配置
+ * 北极星的服务会以headless service以及Endpoints的形式写入k8s
+ */
+type ReverseSyncConfig struct {
+	Open bool `yaml:"open"`
+	// 同步的间隔，单位为秒
+	Interval int `yaml:"interval"`
+	// 需要同步的北极星命名空间，为空时同步全部可以映射到k8s的命名空间
+	Namespaces []string `yaml:"namespaces"`
+}
+
+// 检查配置并且填充默认值
+func (c *Config) check() error {
+	if c.ClusterName == "" {
+		c.ClusterName = defaultClusterName
+	}
+	if c.Owner == "" {
+		c.Owner = defaultOwner
+	}
+	switch c.EndpointSource {
+	case "":
+		c.EndpointSource = EndpointSourceEndpoints
+	case EndpointSourceEndpoints, EndpointSourceSlices:
+	default:
+		return fmt.Errorf("endpointSource(%s) is invalid", c.EndpointSource)
+	}
+	if c.ResyncInterval <= 0 {
+		c.ResyncInterval = defaultResyncInterval
+	}
+	if c.ReverseSync.Interval <= 0 {
+		c.ReverseSync.Interval = defaultReverseInterval
+	}
+	if c.ExcludeNamespaces == nil {
+		c.ExcludeNamespaces = defaultExcludeNamespaces
+	}
+	if c.IgnoreAnnotations == nil {
+		c.IgnoreAnnotations = defaultIgnoreAnnotations
+	}
+	for _, rule := range c.NamespaceMapping {
+		if rule.Kubernetes == "" {
+			return errors.New("kubernetes namespace of the mapping rule is empty")
+		}
+	}
+	return nil
+}
+
+// 全量同步的间隔
+func (c *Config) resyncInterval() time.Duration {
+	return time.Duration(c.ResyncInterval) * time.Second
+}
+
+// 反向同步的间隔
+func (c *Config) reverseInterval() time.Duration {
+	return time.Duration(c.ReverseSync.Interval) * time.Second
+}
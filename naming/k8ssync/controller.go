@@ -0,0 +1,563 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package k8ssync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+const (
+	// 收到变更事件后，等待一段时间合并后续的事件再同步
+	syncDelay = time.Second
+	// watch失败后重试的间隔
+	watchRetryInterval = 5 * time.Second
+	// 同步操作记录的操作人
+	syncOperator = "k8s-sync"
+)
+
+/**
+ * Start 启动k8s服务同步，没有开启时直接返回
+ * 依赖naming server的缓存，需要在naming server初始化之后调用
+ */
+func Start(ctx context.Context, conf *Config) error {
+	if conf == nil || !conf.Open {
+		log.Infof("[K8sSync] kubernetes sync is not open")
+		return nil
+	}
+	if err := conf.check(); err != nil {
+		log.Errorf("[K8sSync] check config err: %s", err.Error())
+		return err
+	}
+
+	server, err := naming.GetServer()
+	if err != nil {
+		return err
+	}
+	if server.Cache() == nil {
+		return errors.New("kubernetes sync requires the naming cache to be open")
+	}
+	storage, err := store.GetStore()
+	if err != nil {
+		return err
+	}
+	client, err := NewClient(conf)
+	if err != nil {
+		log.Errorf("[K8sSync] new kubernetes client err: %s", err.Error())
+		return err
+	}
+
+	ctrl := newController(conf, client, &namingRegistry{server: server, storage: storage})
+	go ctrl.run(ctx)
+	return nil
+}
+
+/**
+ * @brief 同步k8s的服务以及实例到北极星
+ * 以全量对比的方式同步，watch到的变更事件只用于触发同步
+ */
+type controller struct {
+	conf     *Config
+	client   Client
+	registry registry
+	mapper   *namespaceMapper
+	trigger  chan struct{}
+}
+
+// 创建同步控制器，配置需要已经检查过
+func newController(conf *Config, client Client, registry registry) *controller {
+	return &controller{
+		conf:     conf,
+		client:   client,
+		registry: registry,
+		mapper:   newNamespaceMapper(conf),
+		trigger:  make(chan struct{}, 1),
+	}
+}
+
+// 同步的主循环，ctx结束时退出
+func (c *controller) run(ctx context.Context) {
+	log.Infof("[K8sSync] start syncing kubernetes cluster(%s), endpoint source: %s, reverse sync: %v",
+		c.conf.ClusterName, c.conf.EndpointSource, c.conf.ReverseSync.Open)
+	go c.watch(ctx, ResourceServices)
+	go c.watch(ctx, c.endpointResource())
+
+	resync := time.NewTicker(c.conf.resyncInterval())
+	defer resync.Stop()
+	var reverse <-chan time.Time
+	if c.conf.ReverseSync.Open {
+		ticker := time.NewTicker(c.conf.reverseInterval())
+		defer ticker.Stop()
+		reverse = ticker.C
+	}
+
+	c.syncOnce(ctx)
+	var delay <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			log.Infof("[K8sSync] stop syncing kubernetes cluster(%s)", c.conf.ClusterName)
+			return
+		case <-c.trigger:
+			if delay == nil {
+				delay = time.After(syncDelay)
+			}
+		case <-delay:
+			delay = nil
+			c.syncOnce(ctx)
+		case <-resync.C:
+			c.syncOnce(ctx)
+		case <-reverse:
+			if err := c.reverseSync(ctx); err != nil {
+				log.Errorf("[K8sSync] reverse sync err: %s", err.Error())
+			}
+		}
+	}
+}
+
+// 执行一次同步，失败等待下一次触发
+func (c *controller) syncOnce(ctx context.Context) {
+	if err := c.sync(ctx); err != nil {
+		log.Errorf("[K8sSync] sync kubernetes cluster(%s) err: %s", c.conf.ClusterName, err.Error())
+	}
+}
+
+// 通知主循环进行同步
+func (c *controller) notify() {
+	select {
+	case c.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// watch资源的变更，连接断开后重新watch
+func (c *controller) watch(ctx context.Context, resource string) {
+	resourceVersion := ""
+	for {
+		events, err := c.client.Watch(ctx, resource, resourceVersion)
+		if err != nil {
+			log.Errorf("[K8sSync] watch %s err: %s", resource, err.Error())
+			resourceVersion = ""
+			if !sleepWithContext(ctx, watchRetryInterval) {
+				return
+			}
+			continue
+		}
+		for event := range events {
+			switch event.Type {
+			case WatchError:
+				// resourceVersion已经过期，重新watch时API server会返回全部的对象
+				resourceVersion = ""
+			case WatchBookmark:
+				resourceVersion = event.resourceVersion()
+			default:
+				resourceVersion = event.resourceVersion()
+				c.notify()
+			}
+		}
+		if !sleepWithContext(ctx, time.Second) {
+			return
+		}
+	}
+}
+
+// 等待一段时间，ctx结束时返回false
+func sleepWithContext(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+/**
+ * @brief 一个需要同步到北极星的服务，多个k8s命名空间可以映射到同一个北极星服务
+ */
+type syncService struct {
+	namespace string
+	name      string
+	metadata  map[string]string
+	instances map[string]*api.Instance
+	order     []string
+}
+
+// 添加实例，相同地址的实例只保留第一个
+func (s *syncService) addInstance(instance *api.Instance) {
+	key := fmt.Sprintf("%s:%d", instance.GetHost().GetValue(), instance.GetPort().GetValue())
+	if _, ok := s.instances[key]; ok {
+		return
+	}
+	s.instances[key] = instance
+	s.order = append(s.order, key)
+}
+
+// 全量同步k8s的服务以及实例
+func (c *controller) sync(ctx context.Context) error {
+	services, err := c.client.ListServices(ctx)
+	if err != nil {
+		return err
+	}
+	endpoints, err := c.listEndpoints(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx = withSyncContext(ctx)
+	desired := make(map[string]*syncService)
+	var order []string
+	for i := range services.Items {
+		service := &services.Items[i]
+		namespace, ok := c.shouldSync(service)
+		if !ok {
+			continue
+		}
+		key := objectKey(namespace, service.Metadata.Name)
+		target, ok := desired[key]
+		if !ok {
+			target = &syncService{
+				namespace: namespace,
+				name:      service.Metadata.Name,
+				metadata:  c.serviceMetadata(service),
+				instances: make(map[string]*api.Instance),
+			}
+			desired[key] = target
+			order = append(order, key)
+		}
+		for _, item := range endpoints[objectKey(service.Metadata.Namespace, service.Metadata.Name)] {
+			target.addInstance(c.buildInstance(namespace, service, item))
+		}
+	}
+
+	for _, key := range order {
+		c.syncService(ctx, desired[key])
+	}
+	c.removeStale(ctx, desired)
+	return nil
+}
+
+// 查询全部的实例，按照k8s的Service分组
+func (c *controller) listEndpoints(ctx context.Context) (map[string][]*endpoint, error) {
+	if c.conf.EndpointSource == EndpointSourceSlices {
+		list, err := c.client.ListEndpointSlices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return endpointsFromSlices(list), nil
+	}
+
+	list, err := c.client.ListEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return endpointsFromEndpoints(list), nil
+}
+
+// 实例来源对应的k8s资源
+func (c *controller) endpointResource() string {
+	if c.conf.EndpointSource == EndpointSourceSlices {
+		return ResourceEndpointSlices
+	}
+	return ResourceEndpoints
+}
+
+// 判断k8s的Service是否需要同步，需要同步时返回映射的北极星命名空间
+func (c *controller) shouldSync(service *Service) (string, bool) {
+	meta := service.Metadata
+	// 反向同步创建的Service，不再同步回北极星
+	if meta.Labels[LabelManagedBy] == managedByPolaris {
+		return "", false
+	}
+	if strings.EqualFold(meta.Annotations[AnnotationSync], "false") {
+		return "", false
+	}
+	if service.Spec.Type == "ExternalName" {
+		return "", false
+	}
+	return c.mapper.toPolaris(meta.Namespace)
+}
+
+// 判断北极星的服务或者实例是否由当前集群同步创建
+func (c *controller) owned(meta map[string]string) bool {
+	return meta[MetaSyncSource] == syncSourceKubernetes && meta[MetaSyncCluster] == c.conf.ClusterName
+}
+
+// 不同步到元数据的注解
+func (c *controller) ignoreAnnotation(key string) bool {
+	if key == AnnotationSync {
+		return true
+	}
+	for _, prefix := range c.conf.IgnoreAnnotations {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 服务的元数据：同步标记、k8s的命名空间和服务名、标签、注解
+func (c *controller) serviceMetadata(service *Service) map[string]string {
+	return newMetadataBuilder(c.conf.ClusterName).
+		set(metaK8sNamespace, service.Metadata.Namespace).
+		set(metaK8sService, service.Metadata.Name).
+		merge(service.Metadata.Labels, nil).
+		merge(service.Metadata.Annotations, c.ignoreAnnotation).
+		build()
+}
+
+// 根据k8s的地址构造北极星的实例
+// 不填充权重以及隔离状态，允许在北极星上调整
+func (c *controller) buildInstance(namespace string, service *Service, item *endpoint) *api.Instance {
+	metadata := newMetadataBuilder(c.conf.ClusterName).
+		set(metaK8sNamespace, service.Metadata.Namespace).
+		set(metaK8sService, service.Metadata.Name).
+		set(metaK8sPod, item.pod).
+		set(metaK8sNode, item.node).
+		set(metaK8sPortName, item.portName).
+		merge(service.Metadata.Labels, nil).
+		merge(service.Metadata.Annotations, c.ignoreAnnotation).
+		build()
+	instance := &api.Instance{
+		Service:   utils.NewStringValue(service.Metadata.Name),
+		Namespace: utils.NewStringValue(namespace),
+		Host:      utils.NewStringValue(item.ip),
+		Port:      utils.NewUInt32Value(item.port),
+		Protocol:  utils.NewStringValue(item.protocol),
+		Healthy:   utils.NewBoolValue(item.ready),
+		Metadata:  metadata,
+	}
+	if item.zone != "" {
+		instance.Location = &api.Location{Zone: utils.NewStringValue(item.zone)}
+	}
+	return instance
+}
+
+// 同步一个服务：服务不存在则创建，再对比实例
+func (c *controller) syncService(ctx context.Context, target *syncService) {
+	service, err := c.registry.getService(target.namespace, target.name)
+	if err != nil {
+		log.Errorf("[K8sSync] get service(%s/%s) err: %s", target.namespace, target.name, err.Error())
+		return
+	}
+
+	switch {
+	case service == nil:
+		req := &api.Service{
+			Name:      utils.NewStringValue(target.name),
+			Namespace: utils.NewStringValue(target.namespace),
+			Owners:    utils.NewStringValue(c.conf.Owner),
+			Comment:   utils.NewStringValue("synced from kubernetes cluster " + c.conf.ClusterName),
+			Metadata:  target.metadata,
+		}
+		resp := c.registry.createService(ctx, req)
+		if resp.GetCode().GetValue() != api.ExecuteSuccess {
+			log.Errorf("[K8sSync] create service(%s/%s) err: %s",
+				target.namespace, target.name, resp.GetInfo().GetValue())
+			return
+		}
+		log.Infof("[K8sSync] create service(%s/%s)", target.namespace, target.name)
+		service = &model.Service{
+			Name:      target.name,
+			Namespace: target.namespace,
+			Token:     resp.GetService().GetToken().GetValue(),
+			Meta:      target.metadata,
+		}
+	case service.IsAlias():
+		log.Warnf("[K8sSync] service(%s/%s) is an alias, skip syncing", target.namespace, target.name)
+		return
+	case c.owned(service.Meta) && !equalMetadata(service.Meta, target.metadata):
+		req := &api.Service{
+			Name:      utils.NewStringValue(target.name),
+			Namespace: utils.NewStringValue(target.namespace),
+			Token:     utils.NewStringValue(service.Token),
+			Metadata:  target.metadata,
+		}
+		if resp := c.registry.updateService(ctx, req); resp.GetCode().GetValue() != api.ExecuteSuccess {
+			log.Errorf("[K8sSync] update service(%s/%s) err: %s",
+				target.namespace, target.name, resp.GetInfo().GetValue())
+		}
+	}
+
+	var existing []*model.Instance
+	if service.ID != "" {
+		existing = c.registry.instances(service.ID)
+	}
+	c.syncInstances(ctx, service, target, existing)
+}
+
+// 对比实例，创建、更新以及删除当前集群同步的实例，不修改其他来源的实例
+func (c *controller) syncInstances(ctx context.Context, service *model.Service, target *syncService,
+	existing []*model.Instance) {
+	index := make(map[string]*model.Instance, len(existing))
+	for _, instance := range existing {
+		index[instance.ID()] = instance
+	}
+
+	var creates, updates []*api.Instance
+	seen := make(map[string]bool, len(target.order))
+	for _, key := range target.order {
+		instance := target.instances[key]
+		id, err := naming.CalculateInstanceID(target.namespace, target.name, "",
+			instance.GetHost().GetValue(), instance.GetPort().GetValue())
+		if err != nil {
+			log.Errorf("[K8sSync] calculate instance id err: %s", err.Error())
+			continue
+		}
+		seen[id] = true
+		instance.ServiceToken = utils.NewStringValue(service.Token)
+		current, ok := index[id]
+		switch {
+		case !ok:
+			creates = append(creates, instance)
+		case !c.owned(current.Metadata()):
+			log.Debugf("[K8sSync] instance(%s) of service(%s/%s) is not synced from cluster(%s), skip it",
+				id, target.namespace, target.name, c.conf.ClusterName)
+		case instanceChanged(current, instance):
+			updates = append(updates, instance)
+		}
+	}
+
+	var deletes []*api.Instance
+	for _, instance := range existing {
+		if seen[instance.ID()] || !c.owned(instance.Metadata()) {
+			continue
+		}
+		deletes = append(deletes, deleteRequest(service, instance))
+	}
+
+	c.batchWrite(ctx, "create", creates, c.registry.createInstances)
+	c.batchWrite(ctx, "update", updates, c.registry.updateInstances)
+	c.batchWrite(ctx, "delete", deletes, c.registry.deleteInstances)
+}
+
+// 删除k8s中已经不存在的服务下，当前集群同步的实例以及服务
+func (c *controller) removeStale(ctx context.Context, desired map[string]*syncService) {
+	for _, service := range c.registry.services() {
+		if _, ok := desired[objectKey(service.Namespace, service.Name)]; ok {
+			continue
+		}
+		instances := c.registry.instances(service.ID)
+		var owned []*model.Instance
+		for _, instance := range instances {
+			if c.owned(instance.Metadata()) {
+				owned = append(owned, instance)
+			}
+		}
+		ownService := c.owned(service.Meta)
+		if len(owned) == 0 && !ownService {
+			continue
+		}
+
+		// 缓存中不一定有服务的token，从存储层查询
+		current, err := c.registry.getService(service.Namespace, service.Name)
+		if err != nil {
+			log.Errorf("[K8sSync] get service(%s/%s) err: %s", service.Namespace, service.Name, err.Error())
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		var deletes []*api.Instance
+		for _, instance := range owned {
+			deletes = append(deletes, deleteRequest(current, instance))
+		}
+		c.batchWrite(ctx, "delete", deletes, c.registry.deleteInstances)
+
+		// 服务下还有其他来源的实例时保留服务
+		if !ownService || len(instances) > len(owned) {
+			continue
+		}
+		req := &api.Service{
+			Name:      utils.NewStringValue(current.Name),
+			Namespace: utils.NewStringValue(current.Namespace),
+			Token:     utils.NewStringValue(current.Token),
+		}
+		if resp := c.registry.deleteService(ctx, req); resp.GetCode().GetValue() != api.ExecuteSuccess {
+			log.Errorf("[K8sSync] delete service(%s/%s) err: %s",
+				current.Namespace, current.Name, resp.GetInfo().GetValue())
+			continue
+		}
+		log.Infof("[K8sSync] delete service(%s/%s)", current.Namespace, current.Name)
+	}
+}
+
+// 分批写实例，记录失败的实例
+func (c *controller) batchWrite(ctx context.Context, operation string, reqs []*api.Instance,
+	handler func(ctx context.Context, reqs []*api.Instance) *api.BatchWriteResponse) {
+	for start := 0; start < len(reqs); start += naming.MaxBatchSize {
+		end := start + naming.MaxBatchSize
+		if end > len(reqs) {
+			end = len(reqs)
+		}
+		batch := reqs[start:end]
+		resp := handler(ctx, batch)
+		if len(resp.GetResponses()) == 0 && resp.GetCode().GetValue() != api.ExecuteSuccess {
+			log.Errorf("[K8sSync] %s instances err: %s", operation, resp.GetInfo().GetValue())
+			continue
+		}
+		for i, item := range resp.GetResponses() {
+			code := item.GetCode().GetValue()
+			// 缓存还没有刷新时，之前创建的实例可能会重复创建，等待下一次同步
+			if code == api.ExecuteSuccess || (code == api.ExistedResource && operation == "create") {
+				continue
+			}
+			req := batch[i]
+			log.Errorf("[K8sSync] %s instance(%s:%d) of service(%s/%s) err: %s", operation,
+				req.GetHost().GetValue(), req.GetPort().GetValue(), req.GetNamespace().GetValue(),
+				req.GetService().GetValue(), item.GetInfo().GetValue())
+		}
+		log.Infof("[K8sSync] %s %d instances", operation, len(batch))
+	}
+}
+
+// 判断同步的字段是否有变化
+func instanceChanged(current *model.Instance, target *api.Instance) bool {
+	return current.Protocol() != target.GetProtocol().GetValue() ||
+		current.Healthy() != target.GetHealthy().GetValue() ||
+		!equalMetadata(current.Metadata(), target.GetMetadata())
+}
+
+// 删除实例的请求
+func deleteRequest(service *model.Service, instance *model.Instance) *api.Instance {
+	return &api.Instance{
+		Id:           utils.NewStringValue(instance.ID()),
+		Service:      utils.NewStringValue(service.Name),
+		Namespace:    utils.NewStringValue(service.Namespace),
+		Host:         utils.NewStringValue(instance.Host()),
+		Port:         utils.NewUInt32Value(instance.Port()),
+		ServiceToken: utils.NewStringValue(service.Token),
+	}
+}
+
+// 同步操作的请求上下文
+func withSyncContext(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, utils.StringContext("request-id"), fmt.Sprintf("k8s-sync-%d", time.Now().UnixNano()))
+	return context.WithValue(ctx, utils.StringContext("operator"), syncOperator)
+}
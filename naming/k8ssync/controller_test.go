@@ -0,0 +1,524 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package k8ssync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming"
+)
+
+// 测试用的k8s客户端，数据保存在内存中
+type fakeClient struct {
+	mu        sync.Mutex
+	services  map[string]*Service
+	endpoints map[string]*Endpoints
+	slices    []EndpointSlice
+	applied   []string
+	deleted   []string
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		services:  make(map[string]*Service),
+		endpoints: make(map[string]*Endpoints),
+	}
+}
+
+func (f *fakeClient) addService(namespace string, name string, labels map[string]string,
+	annotations map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.services[objectKey(namespace, name)] = &Service{Metadata: ObjectMeta{
+		Name: name, Namespace: namespace, Labels: labels, Annotations: annotations}}
+}
+
+func (f *fakeClient) setEndpoints(namespace string, name string, subsets ...EndpointSubset) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.endpoints[objectKey(namespace, name)] = &Endpoints{
+		Metadata: ObjectMeta{Name: name, Namespace: namespace}, Subsets: subsets}
+}
+
+func (f *fakeClient) removeService(namespace string, name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.services, objectKey(namespace, name))
+	delete(f.endpoints, objectKey(namespace, name))
+}
+
+func (f *fakeClient) ListServices(_ context.Context) (*ServiceList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := &ServiceList{}
+	for _, item := range f.services {
+		out.Items = append(out.Items, *item)
+	}
+	return out, nil
+}
+
+func (f *fakeClient) ListEndpoints(_ context.Context) (*EndpointsList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := &EndpointsList{}
+	for _, item := range f.endpoints {
+		out.Items = append(out.Items, *item)
+	}
+	return out, nil
+}
+
+func (f *fakeClient) ListEndpointSlices(_ context.Context) (*EndpointSliceList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &EndpointSliceList{Items: f.slices}, nil
+}
+
+func (f *fakeClient) Watch(ctx context.Context, _ string, _ string) (<-chan WatchEvent, error) {
+	ch := make(chan WatchEvent)
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch, nil
+}
+
+func (f *fakeClient) ApplyService(_ context.Context, service *Service) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := objectKey(service.Metadata.Namespace, service.Metadata.Name)
+	copied := *service
+	f.services[key] = &copied
+	f.applied = append(f.applied, "service:"+key)
+	return nil
+}
+
+func (f *fakeClient) ApplyEndpoints(_ context.Context, endpoints *Endpoints) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := objectKey(endpoints.Metadata.Namespace, endpoints.Metadata.Name)
+	copied := *endpoints
+	f.endpoints[key] = &copied
+	f.applied = append(f.applied, "endpoints:"+key)
+	return nil
+}
+
+func (f *fakeClient) DeleteService(_ context.Context, namespace string, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.services, objectKey(namespace, name))
+	f.deleted = append(f.deleted, "service:"+objectKey(namespace, name))
+	return nil
+}
+
+func (f *fakeClient) DeleteEndpoints(_ context.Context, namespace string, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.endpoints, objectKey(namespace, name))
+	f.deleted = append(f.deleted, "endpoints:"+objectKey(namespace, name))
+	return nil
+}
+
+// 测试用的北极星注册中心，校验写操作带上了服务的token
+type fakeRegistry struct {
+	seq    int
+	svcs   map[string]*model.Service
+	insts  map[string]map[string]*model.Instance
+	writes int
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{
+		svcs:  make(map[string]*model.Service),
+		insts: make(map[string]map[string]*model.Instance),
+	}
+}
+
+func (f *fakeRegistry) addService(namespace string, name string, meta map[string]string) *model.Service {
+	f.seq++
+	service := &model.Service{
+		ID:        fmt.Sprintf("svc-%d", f.seq),
+		Name:      name,
+		Namespace: namespace,
+		Token:     fmt.Sprintf("token-%d", f.seq),
+		Meta:      meta,
+	}
+	f.svcs[objectKey(namespace, name)] = service
+	f.insts[service.ID] = make(map[string]*model.Instance)
+	return service
+}
+
+func (f *fakeRegistry) addInstance(service *model.Service, host string, port uint32, meta map[string]string) {
+	id, _ := naming.CalculateInstanceID(service.Namespace, service.Name, "", host, port)
+	f.insts[service.ID][id] = &model.Instance{
+		ServiceID: service.ID,
+		Proto: &api.Instance{
+			Id:       utils.NewStringValue(id),
+			Host:     utils.NewStringValue(host),
+			Port:     utils.NewUInt32Value(port),
+			Healthy:  utils.NewBoolValue(true),
+			Metadata: meta,
+		},
+	}
+}
+
+func (f *fakeRegistry) instance(namespace string, name string, host string, port uint32) *model.Instance {
+	service := f.svcs[objectKey(namespace, name)]
+	if service == nil {
+		return nil
+	}
+	id, _ := naming.CalculateInstanceID(namespace, name, "", host, port)
+	return f.insts[service.ID][id]
+}
+
+func (f *fakeRegistry) services() []*model.Service {
+	var out []*model.Service
+	for _, service := range f.svcs {
+		out = append(out, service)
+	}
+	return out
+}
+
+func (f *fakeRegistry) instances(serviceID string) []*model.Instance {
+	var out []*model.Instance
+	for _, instance := range f.insts[serviceID] {
+		out = append(out, instance)
+	}
+	return out
+}
+
+func (f *fakeRegistry) getService(namespace string, name string) (*model.Service, error) {
+	return f.svcs[objectKey(namespace, name)], nil
+}
+
+func (f *fakeRegistry) createService(_ context.Context, req *api.Service) *api.Response {
+	f.writes++
+	if req.GetOwners().GetValue() == "" {
+		return api.NewServiceResponse(api.InvalidServiceOwners, req)
+	}
+	service := f.addService(req.GetNamespace().GetValue(), req.GetName().GetValue(), req.GetMetadata())
+	return api.NewServiceResponse(api.ExecuteSuccess, &api.Service{Token: utils.NewStringValue(service.Token)})
+}
+
+func (f *fakeRegistry) updateService(_ context.Context, req *api.Service) *api.Response {
+	f.writes++
+	service := f.svcs[objectKey(req.GetNamespace().GetValue(), req.GetName().GetValue())]
+	if service.Token != req.GetToken().GetValue() {
+		return api.NewServiceResponse(api.Unauthorized, req)
+	}
+	service.Meta = req.GetMetadata()
+	return api.NewServiceResponse(api.ExecuteSuccess, req)
+}
+
+func (f *fakeRegistry) deleteService(_ context.Context, req *api.Service) *api.Response {
+	f.writes++
+	key := objectKey(req.GetNamespace().GetValue(), req.GetName().GetValue())
+	service := f.svcs[key]
+	if service.Token != req.GetToken().GetValue() {
+		return api.NewServiceResponse(api.Unauthorized, req)
+	}
+	if len(f.insts[service.ID]) > 0 {
+		return api.NewServiceResponse(api.ServiceExistedInstances, req)
+	}
+	delete(f.svcs, key)
+	return api.NewServiceResponse(api.ExecuteSuccess, req)
+}
+
+func (f *fakeRegistry) createInstances(_ context.Context, reqs []*api.Instance) *api.BatchWriteResponse {
+	return f.batch(reqs, func(service *model.Service, id string, req *api.Instance) uint32 {
+		if _, ok := f.insts[service.ID][id]; ok {
+			return api.ExistedResource
+		}
+		proto := *req
+		proto.Id = utils.NewStringValue(id)
+		f.insts[service.ID][id] = &model.Instance{ServiceID: service.ID, Proto: &proto}
+		return api.ExecuteSuccess
+	})
+}
+
+func (f *fakeRegistry) updateInstances(_ context.Context, reqs []*api.Instance) *api.BatchWriteResponse {
+	return f.batch(reqs, func(service *model.Service, id string, req *api.Instance) uint32 {
+		instance, ok := f.insts[service.ID][id]
+		if !ok {
+			return api.NotFoundInstance
+		}
+		instance.Proto.Protocol = req.GetProtocol()
+		instance.Proto.Healthy = req.GetHealthy()
+		instance.Proto.Metadata = req.GetMetadata()
+		return api.ExecuteSuccess
+	})
+}
+
+func (f *fakeRegistry) deleteInstances(_ context.Context, reqs []*api.Instance) *api.BatchWriteResponse {
+	return f.batch(reqs, func(service *model.Service, id string, req *api.Instance) uint32 {
+		if req.GetId().GetValue() != id {
+			return api.InvalidInstanceID
+		}
+		delete(f.insts[service.ID], id)
+		return api.ExecuteSuccess
+	})
+}
+
+func (f *fakeRegistry) batch(reqs []*api.Instance,
+	handler func(service *model.Service, id string, req *api.Instance) uint32) *api.BatchWriteResponse {
+	f.writes++
+	responses := api.NewBatchWriteResponse(api.ExecuteSuccess)
+	for _, req := range reqs {
+		service := f.svcs[objectKey(req.GetNamespace().GetValue(), req.GetService().GetValue())]
+		if service == nil {
+			responses.Collect(api.NewInstanceResponse(api.NotFoundResource, req))
+			continue
+		}
+		if service.Token != req.GetServiceToken().GetValue() {
+			responses.Collect(api.NewInstanceResponse(api.Unauthorized, req))
+			continue
+		}
+		id, _ := naming.CalculateInstanceID(service.Namespace, service.Name, "",
+			req.GetHost().GetValue(), req.GetPort().GetValue())
+		responses.Collect(api.NewInstanceResponse(handler(service, id, req), req))
+	}
+	return responses
+}
+
+func newTestController(t *testing.T, conf *Config) (*controller, *fakeClient, *fakeRegistry) {
+	if err := conf.check(); err != nil {
+		t.Fatalf("check config err: %s", err.Error())
+	}
+	client := newFakeClient()
+	registry := newFakeRegistry()
+	return newController(conf, client, registry), client, registry
+}
+
+func httpSubset(port int32, ready []string, notReady []string) EndpointSubset {
+	subset := EndpointSubset{Ports: []EndpointPort{{Name: "http", Port: port, Protocol: "TCP"}}}
+	for i, ip := range ready {
+		subset.Addresses = append(subset.Addresses, EndpointAddress{IP: ip, NodeName: "node-1",
+			TargetRef: &ObjectReference{Kind: "Pod", Name: fmt.Sprintf("echo-%d", i)}})
+	}
+	for _, ip := range notReady {
+		subset.NotReadyAddresses = append(subset.NotReadyAddresses, EndpointAddress{IP: ip})
+	}
+	return subset
+}
+
+// 测试同步创建服务和实例，以及实例的更新和删除
+func TestSync_Upsert(t *testing.T) {
+	ctrl, client, registry := newTestController(t, &Config{
+		ClusterName:      "test-cluster",
+		NamespaceMapping: []NamespaceRule{{Kubernetes: "default", Polaris: "Test"}},
+	})
+	client.addService("default", "echo", map[string]string{"app": "echo"}, map[string]string{
+		"kubectl.kubernetes.io/last-applied-configuration": "{}",
+		"team": "a",
+	})
+	client.setEndpoints("default", "echo", httpSubset(8080, []string{"10.0.0.1", "10.0.0.2"}, []string{"10.0.0.3"}))
+	if err := ctrl.sync(context.Background()); err != nil {
+		t.Fatalf("sync err: %s", err.Error())
+	}
+
+	service := registry.svcs["Test/echo"]
+	if service == nil {
+		t.Fatalf("service is not created")
+	}
+	if !ctrl.owned(service.Meta) || service.Meta["app"] != "echo" || service.Meta["team"] != "a" ||
+		service.Meta[metaK8sNamespace] != "default" {
+		t.Fatalf("service metadata is wrong: %+v", service.Meta)
+	}
+	if _, ok := service.Meta["kubectl.kubernetes.io/last-applied-configuration"]; ok {
+		t.Fatalf("ignored annotation is synced: %+v", service.Meta)
+	}
+	if len(registry.insts[service.ID]) != 3 {
+		t.Fatalf("instances count is %d", len(registry.insts[service.ID]))
+	}
+	first := registry.instance("Test", "echo", "10.0.0.1", 8080)
+	if !first.Healthy() || first.Protocol() != "http" || first.Metadata()[metaK8sPod] != "echo-0" ||
+		first.Metadata()[metaK8sNode] != "node-1" || first.Metadata()["app"] != "echo" {
+		t.Fatalf("instance is wrong: %+v", first.Proto)
+	}
+	if registry.instance("Test", "echo", "10.0.0.3", 8080).Healthy() {
+		t.Fatalf("not ready address should be unhealthy")
+	}
+
+	// 没有变化时不需要写
+	writes := registry.writes
+	if err := ctrl.sync(context.Background()); err != nil {
+		t.Fatalf("sync err: %s", err.Error())
+	}
+	if registry.writes != writes {
+		t.Fatalf("sync without changes writes the registry %d times", registry.writes-writes)
+	}
+
+	client.setEndpoints("default", "echo", httpSubset(8080, []string{"10.0.0.1", "10.0.0.3"}, nil))
+	if err := ctrl.sync(context.Background()); err != nil {
+		t.Fatalf("sync err: %s", err.Error())
+	}
+	if registry.instance("Test", "echo", "10.0.0.2", 8080) != nil {
+		t.Fatalf("removed address is not deleted")
+	}
+	if !registry.instance("Test", "echo", "10.0.0.3", 8080).Healthy() {
+		t.Fatalf("ready address is not updated")
+	}
+}
+
+// 测试不修改、不删除手工注册的实例
+func TestSync_ManualInstances(t *testing.T) {
+	ctrl, client, registry := newTestController(t, &Config{})
+	service := registry.addService("default", "echo", map[string]string{"owner": "manual"})
+	registry.addInstance(service, "10.0.0.1", 8080, map[string]string{"source": "sdk"})
+	registry.addInstance(service, "10.0.0.9", 8080, nil)
+
+	client.addService("default", "echo", nil, nil)
+	client.setEndpoints("default", "echo", httpSubset(8080, []string{"10.0.0.1", "10.0.0.2"}, nil))
+	if err := ctrl.sync(context.Background()); err != nil {
+		t.Fatalf("sync err: %s", err.Error())
+	}
+	if meta := registry.instance("default", "echo", "10.0.0.1", 8080).Metadata(); ctrl.owned(meta) {
+		t.Fatalf("manual instance is overwritten: %+v", meta)
+	}
+	if !ctrl.owned(registry.instance("default", "echo", "10.0.0.2", 8080).Metadata()) {
+		t.Fatalf("synced instance is not tagged")
+	}
+	if !equalMetadata(service.Meta, map[string]string{"owner": "manual"}) {
+		t.Fatalf("manual service metadata is overwritten: %+v", service.Meta)
+	}
+
+	// k8s的服务删除后，只删除同步的实例，保留手工注册的服务和实例
+	client.removeService("default", "echo")
+	if err := ctrl.sync(context.Background()); err != nil {
+		t.Fatalf("sync err: %s", err.Error())
+	}
+	if registry.svcs["default/echo"] == nil || len(registry.insts[service.ID]) != 2 ||
+		registry.instance("default", "echo", "10.0.0.2", 8080) != nil {
+		t.Fatalf("manual service or instances are changed")
+	}
+}
+
+// 测试k8s的服务删除后，删除同步创建的服务，不删除其他集群同步的服务
+func TestSync_RemoveOwnedService(t *testing.T) {
+	ctrl, client, registry := newTestController(t, &Config{ClusterName: "a"})
+	other := registry.addService("default", "other", map[string]string{
+		MetaSyncSource: syncSourceKubernetes, MetaSyncCluster: "b"})
+
+	client.addService("default", "echo", nil, nil)
+	client.setEndpoints("default", "echo", httpSubset(8080, []string{"10.0.0.1"}, nil))
+	if err := ctrl.sync(context.Background()); err != nil {
+		t.Fatalf("sync err: %s", err.Error())
+	}
+	if registry.svcs["default/echo"] == nil {
+		t.Fatalf("service is not created")
+	}
+
+	client.removeService("default", "echo")
+	if err := ctrl.sync(context.Background()); err != nil {
+		t.Fatalf("sync err: %s", err.Error())
+	}
+	if registry.svcs["default/echo"] != nil {
+		t.Fatalf("owned service is not deleted")
+	}
+	if registry.svcs["default/other"] != other {
+		t.Fatalf("service synced by other cluster is deleted")
+	}
+}
+
+// 测试不同步的k8s服务
+func TestSync_Skip(t *testing.T) {
+	ctrl, client, registry := newTestController(t, &Config{})
+	client.addService("kube-system", "kube-dns", nil, nil)
+	client.addService("default", "disabled", nil, map[string]string{AnnotationSync: "false"})
+	client.addService("default", "mirror", map[string]string{LabelManagedBy: managedByPolaris}, nil)
+	client.addService("default", "echo", nil, nil)
+	if err := ctrl.sync(context.Background()); err != nil {
+		t.Fatalf("sync err: %s", err.Error())
+	}
+	if len(registry.svcs) != 1 || registry.svcs["default/echo"] == nil {
+		t.Fatalf("synced services are wrong: %+v", registry.svcs)
+	}
+}
+
+// 测试反向同步北极星的服务到k8s
+func TestReverseSync(t *testing.T) {
+	ctrl, client, registry := newTestController(t, &Config{
+		ReverseSync: ReverseSyncConfig{Open: true, Namespaces: []string{"Test", "default"}},
+		NamespaceMapping: []NamespaceRule{
+			{Kubernetes: "test", Polaris: "Test"},
+			{Kubernetes: "*"},
+		},
+	})
+	payment := registry.addService("Test", "payment", nil)
+	registry.addInstance(payment, "10.0.0.2", 8080, nil)
+	registry.addInstance(payment, "10.0.0.1", 8080, nil)
+	registry.addInstance(payment, "10.0.0.1", 9090, nil)
+	registry.addInstance(payment, "payment.example.com", 8080, nil)
+	registry.instance("Test", "payment", "10.0.0.2", 8080).Proto.Healthy = utils.NewBoolValue(false)
+	// 不符合k8s命名规则、从k8s同步过来以及k8s中已经存在的服务都不反向同步
+	registry.addService("Test", "Invalid_Name", nil)
+	registry.addService("default", "synced", map[string]string{MetaSyncSource: syncSourceKubernetes})
+	registry.addService("default", "exist", nil)
+	client.addService("default", "exist", nil, nil)
+
+	if err := ctrl.reverseSync(context.Background()); err != nil {
+		t.Fatalf("reverse sync err: %s", err.Error())
+	}
+	if len(client.applied) != 2 {
+		t.Fatalf("applied objects are wrong: %v", client.applied)
+	}
+	service := client.services["test/payment"]
+	if service == nil || service.Spec.ClusterIP != "None" || len(service.Spec.Ports) != 2 ||
+		service.Metadata.Annotations[annotationPolarisNamespace] != "Test" {
+		t.Fatalf("kubernetes service is wrong: %+v", service)
+	}
+	data, _ := json.Marshal(client.endpoints["test/payment"].Subsets)
+	expect := `[{"addresses":[{"ip":"10.0.0.1"}],"notReadyAddresses":[{"ip":"10.0.0.2"}],` +
+		`"ports":[{"name":"port-8080","port":8080,"protocol":"TCP"}]},` +
+		`{"addresses":[{"ip":"10.0.0.1"}],"ports":[{"name":"port-9090","port":9090,"protocol":"TCP"}]}]`
+	if string(data) != expect {
+		t.Fatalf("kubernetes endpoints are wrong: %s", data)
+	}
+
+	// 内容没有变化时不需要写k8s
+	if err := ctrl.reverseSync(context.Background()); err != nil {
+		t.Fatalf("reverse sync err: %s", err.Error())
+	}
+	if len(client.applied) != 2 {
+		t.Fatalf("unchanged service is applied again: %v", client.applied)
+	}
+
+	// 北极星的服务删除后，删除反向同步创建的k8s对象
+	delete(registry.svcs, "Test/payment")
+	if err := ctrl.reverseSync(context.Background()); err != nil {
+		t.Fatalf("reverse sync err: %s", err.Error())
+	}
+	if client.services["test/payment"] != nil || client.endpoints["test/payment"] != nil ||
+		client.services["default/exist"] == nil {
+		t.Fatalf("kubernetes objects are not cleaned: %v", client.deleted)
+	}
+
+	// 反向同步创建的k8s服务不会再同步回北极星
+	if err := ctrl.sync(context.Background()); err != nil {
+		t.Fatalf("sync err: %s", err.Error())
+	}
+	if registry.svcs["default/exist"].Meta != nil {
+		t.Fatalf("existing service is changed")
+	}
+}
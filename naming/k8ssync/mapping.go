@@ -0,0 +1,276 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package k8ssync
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/polarismesh/polaris-server/naming"
+)
+
+const (
+	// 同步到实例元数据中的k8s信息
+	metaK8sNamespace = "k8s.namespace"
+	metaK8sService   = "k8s.service"
+	metaK8sPod       = "k8s.pod"
+	metaK8sNode      = "k8s.node"
+	metaK8sPortName  = "k8s.port-name"
+
+	// EndpointSlice上记录所属Service的标签
+	labelServiceName = "kubernetes.io/service-name"
+	// 匹配全部命名空间的规则
+	matchAllNamespaces = "*"
+)
+
+/**
+ * @brief k8s命名空间与北极星命名空间的映射
+ */
+type namespaceMapper struct {
+	rules    []NamespaceRule
+	excludes map[string]bool
+}
+
+// 根据配置创建命名空间的映射，没有配置规则时使用同名的命名空间
+func newNamespaceMapper(conf *Config) *namespaceMapper {
+	mapper := &namespaceMapper{
+		rules:    conf.NamespaceMapping,
+		excludes: make(map[string]bool, len(conf.ExcludeNamespaces)),
+	}
+	if len(mapper.rules) == 0 {
+		mapper.rules = []NamespaceRule{{Kubernetes: matchAllNamespaces}}
+	}
+	for _, namespace := range conf.ExcludeNamespaces {
+		mapper.excludes[namespace] = true
+	}
+	return mapper
+}
+
+// toPolaris k8s命名空间对应的北极星命名空间，按顺序匹配第一条规则
+func (m *namespaceMapper) toPolaris(namespace string) (string, bool) {
+	if m.excludes[namespace] {
+		return "", false
+	}
+	for _, rule := range m.rules {
+		if rule.Kubernetes != matchAllNamespaces && rule.Kubernetes != namespace {
+			continue
+		}
+		if rule.Polaris == "" {
+			return namespace, true
+		}
+		return rule.Polaris, true
+	}
+	return "", false
+}
+
+// toKubernetes 北极星命名空间对应的k8s命名空间，用于反向同步
+// 只有正向映射回同一个北极星命名空间的k8s命名空间才是有效的
+func (m *namespaceMapper) toKubernetes(namespace string) (string, bool) {
+	for _, rule := range m.rules {
+		candidate := rule.Kubernetes
+		if candidate == matchAllNamespaces {
+			if rule.Polaris != "" {
+				continue
+			}
+			candidate = namespace
+		}
+		if target, ok := m.toPolaris(candidate); ok && target == namespace {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+/**
+ * @brief 从k8s的Endpoints或者EndpointSlice中解析出的实例
+ */
+type endpoint struct {
+	ip       string
+	port     uint32
+	portName string
+	protocol string
+	ready    bool
+	zone     string
+	node     string
+	pod      string
+}
+
+// 实例的唯一标识
+func (e *endpoint) key() string {
+	return fmt.Sprintf("%s:%d", e.ip, e.port)
+}
+
+// k8s对象的唯一标识
+func objectKey(namespace string, name string) string {
+	return namespace + "/" + name
+}
+
+// 解析Endpoints中的实例，按照Service分组
+func endpointsFromEndpoints(list *EndpointsList) map[string][]*endpoint {
+	out := make(map[string][]*endpoint, len(list.Items))
+	for i := range list.Items {
+		item := &list.Items[i]
+		var endpoints []*endpoint
+		for _, subset := range item.Subsets {
+			for _, port := range subset.Ports {
+				for _, address := range subset.Addresses {
+					endpoints = append(endpoints, newEndpoint(address, port, true))
+				}
+				for _, address := range subset.NotReadyAddresses {
+					endpoints = append(endpoints, newEndpoint(address, port, false))
+				}
+			}
+		}
+		out[objectKey(item.Metadata.Namespace, item.Metadata.Name)] = endpoints
+	}
+	return out
+}
+
+// Endpoints中的一个地址
+func newEndpoint(address EndpointAddress, port EndpointPort, ready bool) *endpoint {
+	out := &endpoint{
+		ip:       address.IP,
+		port:     uint32(port.Port),
+		portName: port.Name,
+		protocol: portProtocol(port),
+		ready:    ready,
+		node:     address.NodeName,
+	}
+	if address.TargetRef != nil && address.TargetRef.Kind == "Pod" {
+		out.pod = address.TargetRef.Name
+	}
+	return out
+}
+
+// 解析EndpointSlice中的实例，按照Service分组
+// 变更过程中同一个地址可能出现在多个EndpointSlice中，任意一个就绪即认为就绪
+func endpointsFromSlices(list *EndpointSliceList) map[string][]*endpoint {
+	indexes := make(map[string]map[string]*endpoint)
+	out := make(map[string][]*endpoint)
+	for i := range list.Items {
+		item := &list.Items[i]
+		service := item.Metadata.Labels[labelServiceName]
+		if service == "" || (item.AddressType != "IPv4" && item.AddressType != "IPv6") {
+			continue
+		}
+		key := objectKey(item.Metadata.Namespace, service)
+		index, ok := indexes[key]
+		if !ok {
+			index = make(map[string]*endpoint)
+			indexes[key] = index
+		}
+		for _, port := range item.Ports {
+			for _, value := range item.Endpoints {
+				ready := value.Conditions.Ready == nil || *value.Conditions.Ready
+				for _, ip := range value.Addresses {
+					current := &endpoint{
+						ip:       ip,
+						port:     uint32(port.Port),
+						portName: port.Name,
+						protocol: portProtocol(port),
+						ready:    ready,
+						zone:     value.Zone,
+						node:     value.NodeName,
+					}
+					if value.TargetRef != nil && value.TargetRef.Kind == "Pod" {
+						current.pod = value.TargetRef.Name
+					}
+					if exist, ok := index[current.key()]; ok {
+						exist.ready = exist.ready || current.ready
+						continue
+					}
+					index[current.key()] = current
+					out[key] = append(out[key], current)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// 实例的协议，优先使用appProtocol，其次为端口名
+func portProtocol(port EndpointPort) string {
+	if port.AppProtocol != "" {
+		return port.AppProtocol
+	}
+	if port.Name != "" {
+		return port.Name
+	}
+	return strings.ToLower(port.Protocol)
+}
+
+/**
+ * @brief 元数据的构造，超过北极星的元数据个数上限时丢弃多余的标签和注解
+ */
+type metadataBuilder struct {
+	meta map[string]string
+}
+
+// 以同步的归属标记作为初始的元数据
+func newMetadataBuilder(cluster string) *metadataBuilder {
+	return &metadataBuilder{meta: map[string]string{
+		MetaSyncSource:  syncSourceKubernetes,
+		MetaSyncCluster: cluster,
+	}}
+}
+
+// 添加一个元数据，已经存在或者超过上限时忽略
+func (b *metadataBuilder) set(key string, value string) *metadataBuilder {
+	if key == "" || value == "" {
+		return b
+	}
+	if _, ok := b.meta[key]; ok || len(b.meta) >= naming.MaxMetadataLength {
+		return b
+	}
+	b.meta[key] = value
+	return b
+}
+
+// 按照key的顺序添加，保证超过上限时丢弃的元数据是稳定的
+func (b *metadataBuilder) merge(values map[string]string, ignore func(key string) bool) *metadataBuilder {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if ignore == nil || !ignore(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		b.set(key, values[key])
+	}
+	return b
+}
+
+// 同步的元数据
+func (b *metadataBuilder) build() map[string]string {
+	return b.meta
+}
+
+// 判断元数据是否一致
+func equalMetadata(a map[string]string, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if current, ok := b[key]; !ok || current != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package k8ssync
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/polarismesh/polaris-server/naming"
+)
+
+// 测试命名空间的映射规则
+func TestNamespaceMapper(t *testing.T) {
+	conf := &Config{NamespaceMapping: []NamespaceRule{
+		{Kubernetes: "prod", Polaris: "Production"},
+		{Kubernetes: "staging", Polaris: "Test"},
+		{Kubernetes: "qa", Polaris: "Test"},
+		{Kubernetes: "*"},
+	}}
+	if err := conf.check(); err != nil {
+		t.Fatalf("check config err: %s", err.Error())
+	}
+	mapper := newNamespaceMapper(conf)
+
+	toPolaris := map[string]string{
+		"prod":    "Production",
+		"staging": "Test",
+		"qa":      "Test",
+		"default": "default",
+	}
+	for namespace, expect := range toPolaris {
+		if target, ok := mapper.toPolaris(namespace); !ok || target != expect {
+			t.Fatalf("namespace(%s) is mapped to %s, expect %s", namespace, target, expect)
+		}
+	}
+	if _, ok := mapper.toPolaris("kube-system"); ok {
+		t.Fatalf("excluded namespace is mapped")
+	}
+
+	toKubernetes := map[string]string{
+		"Production": "prod",
+		"Test":       "staging",
+		"default":    "default",
+	}
+	for namespace, expect := range toKubernetes {
+		if target, ok := mapper.toKubernetes(namespace); !ok || target != expect {
+			t.Fatalf("namespace(%s) is mapped back to %s, expect %s", namespace, target, expect)
+		}
+	}
+	// prod映射到了Production，同名的命名空间不能再映射回prod
+	if target, ok := mapper.toKubernetes("prod"); ok {
+		t.Fatalf("namespace(prod) is mapped back to %s", target)
+	}
+	if _, ok := mapper.toKubernetes("kube-system"); ok {
+		t.Fatalf("excluded namespace is mapped back")
+	}
+
+	// 没有匹配全部的规则时，只同步配置的命名空间
+	mapper = newNamespaceMapper(&Config{NamespaceMapping: []NamespaceRule{{Kubernetes: "prod", Polaris: "Production"}}})
+	if _, ok := mapper.toPolaris("default"); ok {
+		t.Fatalf("namespace without rule is mapped")
+	}
+}
+
+// 测试从EndpointSlice解析实例
+func TestEndpointsFromSlices(t *testing.T) {
+	ready, notReady := true, false
+	slice := func(name string, endpoints ...Endpoint) EndpointSlice {
+		return EndpointSlice{
+			Metadata:    ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{labelServiceName: "echo"}},
+			AddressType: "IPv4",
+			Endpoints:   endpoints,
+			Ports:       []EndpointPort{{Name: "grpc", Port: 9090, Protocol: "TCP", AppProtocol: "grpc"}},
+		}
+	}
+	list := &EndpointSliceList{Items: []EndpointSlice{
+		slice("echo-a",
+			Endpoint{Addresses: []string{"10.0.0.1"}, Zone: "zone-a",
+				TargetRef: &ObjectReference{Kind: "Pod", Name: "echo-1"}},
+			Endpoint{Addresses: []string{"10.0.0.2"}, Conditions: EndpointConditions{Ready: &notReady}}),
+		// 变更过程中同一个地址出现在两个EndpointSlice中
+		slice("echo-b", Endpoint{Addresses: []string{"10.0.0.2"}, Conditions: EndpointConditions{Ready: &ready}}),
+		{
+			Metadata:    ObjectMeta{Name: "echo-fqdn", Namespace: "default", Labels: map[string]string{labelServiceName: "echo"}},
+			AddressType: "FQDN",
+			Endpoints:   []Endpoint{{Addresses: []string{"echo.example.com"}}},
+		},
+	}}
+
+	endpoints := endpointsFromSlices(list)["default/echo"]
+	if len(endpoints) != 2 {
+		t.Fatalf("endpoints count is %d", len(endpoints))
+	}
+	first, second := endpoints[0], endpoints[1]
+	if first.ip != "10.0.0.1" || first.port != 9090 || first.protocol != "grpc" || !first.ready ||
+		first.zone != "zone-a" || first.pod != "echo-1" {
+		t.Fatalf("endpoint is wrong: %+v", first)
+	}
+	if second.ip != "10.0.0.2" || !second.ready {
+		t.Fatalf("endpoint ready in any slice should be ready: %+v", second)
+	}
+}
+
+// 测试元数据超过上限时保留同步标记
+func TestMetadataBuilder(t *testing.T) {
+	labels := make(map[string]string)
+	for i := 0; i < naming.MaxMetadataLength+10; i++ {
+		labels[fmt.Sprintf("label-%03d", i)] = "value"
+	}
+	meta := newMetadataBuilder("cluster").set(metaK8sPod, "echo-1").merge(labels, nil).build()
+	if len(meta) != naming.MaxMetadataLength {
+		t.Fatalf("metadata count is %d", len(meta))
+	}
+	if meta[MetaSyncSource] != syncSourceKubernetes || meta[MetaSyncCluster] != "cluster" ||
+		meta[metaK8sPod] != "echo-1" || meta["label-000"] != "value" {
+		t.Fatalf("metadata is wrong: %+v", meta)
+	}
+	if _, ok := meta[fmt.Sprintf("label-%03d", naming.MaxMetadataLength+9)]; ok {
+		t.Fatalf("metadata over the limit should be dropped in order")
+	}
+}
@@ -0,0 +1,101 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package k8ssync
+
+import (
+	"context"
+
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/naming"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+/**
+ * @brief 同步读写北极星服务和实例的接口
+ */
+type registry interface {
+	// 缓存中的全部服务，不包括服务别名
+	services() []*model.Service
+	// 缓存中服务的全部实例
+	instances(serviceID string) []*model.Instance
+	// 从存储层查询服务，包括服务的token，不存在时返回nil
+	getService(namespace string, name string) (*model.Service, error)
+
+	createService(ctx context.Context, req *api.Service) *api.Response
+	updateService(ctx context.Context, req *api.Service) *api.Response
+	deleteService(ctx context.Context, req *api.Service) *api.Response
+
+	createInstances(ctx context.Context, reqs []*api.Instance) *api.BatchWriteResponse
+	updateInstances(ctx context.Context, reqs []*api.Instance) *api.BatchWriteResponse
+	deleteInstances(ctx context.Context, reqs []*api.Instance) *api.BatchWriteResponse
+}
+
+/**
+ * @brief 通过naming server读写服务和实例
+ */
+type namingRegistry struct {
+	server  *naming.Server
+	storage store.Store
+}
+
+// 缓存中的全部服务
+func (n *namingRegistry) services() []*model.Service {
+	var out []*model.Service
+	_ = n.server.Cache().Service().IteratorServices(func(key string, value *model.Service) (bool, error) {
+		if !value.IsAlias() {
+			out = append(out, value)
+		}
+		return true, nil
+	})
+	return out
+}
+
+// 缓存中服务的全部实例
+func (n *namingRegistry) instances(serviceID string) []*model.Instance {
+	return n.server.Cache().Instance().GetInstancesByServiceID(serviceID)
+}
+
+// 从存储层查询服务
+func (n *namingRegistry) getService(namespace string, name string) (*model.Service, error) {
+	return n.storage.GetService(name, namespace)
+}
+
+func (n *namingRegistry) createService(ctx context.Context, req *api.Service) *api.Response {
+	return n.server.CreateService(ctx, req)
+}
+
+func (n *namingRegistry) updateService(ctx context.Context, req *api.Service) *api.Response {
+	return n.server.UpdateService(ctx, req)
+}
+
+func (n *namingRegistry) deleteService(ctx context.Context, req *api.Service) *api.Response {
+	return n.server.DeleteService(ctx, req)
+}
+
+func (n *namingRegistry) createInstances(ctx context.Context, reqs []*api.Instance) *api.BatchWriteResponse {
+	return n.server.CreateInstances(ctx, reqs)
+}
+
+func (n *namingRegistry) updateInstances(ctx context.Context, reqs []*api.Instance) *api.BatchWriteResponse {
+	return n.server.UpdateInstances(ctx, reqs)
+}
+
+func (n *namingRegistry) deleteInstances(ctx context.Context, reqs []*api.Instance) *api.BatchWriteResponse {
+	return n.server.DeleteInstances(ctx, reqs)
+}
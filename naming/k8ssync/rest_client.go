@@ -0,0 +1,376 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package k8ssync
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/plugin"
+)
+
+const (
+	// 集群内service account的token以及CA
+	inClusterTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	inClusterCAFile    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+
+	// 非watch请求的超时时间
+	requestTimeout = 10 * time.Second
+	// 单次watch的最长时间，到期后由API server断开，重新发起watch
+	watchTimeoutSeconds = 300
+	// token文件重新读取的间隔，service account的token会定期轮换
+	tokenReloadInterval = time.Minute
+	// 错误响应最多读取的长度
+	maxErrorBodySize = 4096
+)
+
+// 全部命名空间的资源的路径
+var resourcePaths = map[string]string{
+	ResourceServices:       "/api/v1/services",
+	ResourceEndpoints:      "/api/v1/endpoints",
+	ResourceEndpointSlices: "/apis/discovery.k8s.io/v1/endpointslices",
+}
+
+/**
+ * @brief 通过REST接口访问k8s API server的客户端
+ */
+type restClient struct {
+	host       string
+	token      string
+	tokenFile  string
+	httpClient *http.Client
+
+	tokenMutex  sync.Mutex
+	cachedToken string
+	tokenExpire time.Time
+}
+
+/**
+ * NewClient 根据配置创建访问k8s API server的客户端
+ * 没有配置apiServer时，使用集群内的环境变量以及service account
+ */
+func NewClient(conf *Config) (Client, error) {
+	// token可能需要通过密码插件解析
+	token, err := plugin.ParseSecret(conf.Token)
+	if err != nil {
+		return nil, fmt.Errorf("parse token err: %s", err.Error())
+	}
+	client := &restClient{
+		host:      strings.TrimSuffix(conf.APIServer, "/"),
+		token:     token,
+		tokenFile: conf.TokenFile,
+	}
+	caFile := conf.CAFile
+	if client.host == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return nil, errors.New("apiServer is empty and not running in a kubernetes cluster")
+		}
+		client.host = "https://" + net.JoinHostPort(host, port)
+		if client.token == "" && client.tokenFile == "" {
+			client.tokenFile = inClusterTokenFile
+		}
+		if caFile == "" {
+			caFile = inClusterCAFile
+		}
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: conf.InsecureSkipVerify}
+	if caFile != "" {
+		data, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificate found in caFile(%s)", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if _, err := client.bearerToken(); err != nil {
+		return nil, err
+	}
+
+	client.httpClient = &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSClientConfig:     tlsConfig,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConnsPerHost: 8,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+	return client, nil
+}
+
+// ListServices 查询全部命名空间的Service
+func (r *restClient) ListServices(ctx context.Context) (*ServiceList, error) {
+	out := &ServiceList{}
+	if err := r.request(ctx, http.MethodGet, resourcePaths[ResourceServices], "", nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListEndpoints 查询全部命名空间的Endpoints
+func (r *restClient) ListEndpoints(ctx context.Context) (*EndpointsList, error) {
+	out := &EndpointsList{}
+	if err := r.request(ctx, http.MethodGet, resourcePaths[ResourceEndpoints], "", nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListEndpointSlices 查询全部命名空间的EndpointSlice
+func (r *restClient) ListEndpointSlices(ctx context.Context) (*EndpointSliceList, error) {
+	out := &EndpointSliceList{}
+	if err := r.request(ctx, http.MethodGet, resourcePaths[ResourceEndpointSlices], "", nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Watch 从resourceVersion开始watch资源的变更
+func (r *restClient) Watch(ctx context.Context, resource string, resourceVersion string) (
+	<-chan WatchEvent, error) {
+	path, ok := resourcePaths[resource]
+	if !ok {
+		return nil, fmt.Errorf("resource(%s) is not supported", resource)
+	}
+	query := url.Values{}
+	query.Set("watch", "1")
+	query.Set("allowWatchBookmarks", "true")
+	query.Set("timeoutSeconds", fmt.Sprintf("%d", watchTimeoutSeconds))
+	if resourceVersion != "" {
+		query.Set("resourceVersion", resourceVersion)
+	}
+
+	resp, err := r.do(ctx, http.MethodGet, path+"?"+query.Encode(), "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, parseStatusError(resp)
+	}
+
+	ch := make(chan WatchEvent, 16)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var event WatchEvent
+			if err := decoder.Decode(&event); err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					log.Warnf("[K8sSync] watch %s err: %s", resource, err.Error())
+				}
+				return
+			}
+			select {
+			case ch <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// ApplyService 创建或者更新Service
+func (r *restClient) ApplyService(ctx context.Context, service *Service) error {
+	return r.apply(ctx, ResourceServices, &service.Metadata, service)
+}
+
+// ApplyEndpoints 创建或者更新Endpoints
+func (r *restClient) ApplyEndpoints(ctx context.Context, endpoints *Endpoints) error {
+	return r.apply(ctx, ResourceEndpoints, &endpoints.Metadata, endpoints)
+}
+
+// DeleteService 删除Service
+func (r *restClient) DeleteService(ctx context.Context, namespace string, name string) error {
+	return r.delete(ctx, ResourceServices, namespace, name)
+}
+
+// DeleteEndpoints 删除Endpoints
+func (r *restClient) DeleteEndpoints(ctx context.Context, namespace string, name string) error {
+	return r.delete(ctx, ResourceEndpoints, namespace, name)
+}
+
+// 先以merge patch的方式更新，对象不存在再创建
+// merge patch只修改提交的字段，不会覆盖API server填充的其他字段
+func (r *restClient) apply(ctx context.Context, resource string, meta *ObjectMeta, object interface{}) error {
+	err := r.request(ctx, http.MethodPatch, namespacedPath(resource, meta.Namespace, meta.Name),
+		"application/merge-patch+json", object, nil)
+	if !isNotFound(err) {
+		return err
+	}
+	return r.request(ctx, http.MethodPost, namespacedPath(resource, meta.Namespace, ""),
+		"application/json", object, nil)
+}
+
+// 删除对象，不存在时不返回错误
+func (r *restClient) delete(ctx context.Context, resource string, namespace string, name string) error {
+	err := r.request(ctx, http.MethodDelete, namespacedPath(resource, namespace, name), "", nil, nil)
+	if isNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// 发起非watch的请求，in不为空时作为请求体，out不为空时解析响应体
+func (r *restClient) request(ctx context.Context, method string, path string, contentType string,
+	in interface{}, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	resp, err := r.do(ctx, method, path, contentType, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return parseStatusError(resp)
+	}
+	if out == nil {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// 发起请求，填充鉴权信息
+func (r *restClient) do(ctx context.Context, method string, path string, contentType string,
+	body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, r.host+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	token, err := r.bearerToken()
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r.httpClient.Do(req)
+}
+
+// 获取访问的token，token文件定期重新读取
+func (r *restClient) bearerToken() (string, error) {
+	if r.token != "" || r.tokenFile == "" {
+		return r.token, nil
+	}
+
+	r.tokenMutex.Lock()
+	defer r.tokenMutex.Unlock()
+	if r.cachedToken != "" && time.Now().Before(r.tokenExpire) {
+		return r.cachedToken, nil
+	}
+	data, err := ioutil.ReadFile(r.tokenFile)
+	if err != nil {
+		// 读取失败时继续使用上一次的token
+		if r.cachedToken != "" {
+			log.Errorf("[K8sSync] read token file(%s) err: %s", r.tokenFile, err.Error())
+			return r.cachedToken, nil
+		}
+		return "", err
+	}
+	r.cachedToken = strings.TrimSpace(string(data))
+	r.tokenExpire = time.Now().Add(tokenReloadInterval)
+	return r.cachedToken, nil
+}
+
+// 命名空间下资源的路径，name为空时为资源列表的路径
+func namespacedPath(resource string, namespace string, name string) string {
+	prefix := "/api/v1"
+	if resource == ResourceEndpointSlices {
+		prefix = "/apis/discovery.k8s.io/v1"
+	}
+	path := prefix + "/namespaces/" + url.PathEscape(namespace) + "/" + resource
+	if name != "" {
+		path += "/" + url.PathEscape(name)
+	}
+	return path
+}
+
+/**
+ * @brief API server返回的错误
+ */
+type statusError struct {
+	code    int
+	reason  string
+	message string
+}
+
+// Error 实现error接口
+func (e *statusError) Error() string {
+	return fmt.Sprintf("kubernetes api server return %d(%s): %s", e.code, e.reason, e.message)
+}
+
+// 解析API server返回的Status对象
+func parseStatusError(resp *http.Response) error {
+	data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	status := struct {
+		Reason  string `json:"reason"`
+		Message string `json:"message"`
+	}{}
+	if err := json.Unmarshal(data, &status); err != nil || status.Message == "" {
+		status.Message = strings.TrimSpace(string(data))
+	}
+	return &statusError{code: resp.StatusCode, reason: status.Reason, message: status.Message}
+}
+
+// 对象不存在的错误
+func isNotFound(err error) bool {
+	statusErr, ok := err.(*statusError)
+	return ok && statusErr.code == http.StatusNotFound
+}
@@ -0,0 +1,154 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package k8ssync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 测试用的k8s API server，只保存Service
+type fakeAPIServer struct {
+	mu       sync.Mutex
+	services map[string]json.RawMessage
+	requests []string
+}
+
+func (f *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Header.Get("Authorization") != "Bearer test-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"kind":"Status","reason":"Unauthorized","message":"invalid token"}`))
+		return
+	}
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	notFound := func() {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"kind":"Status","reason":"NotFound","message":"not found"}`))
+	}
+
+	const item = "/api/v1/namespaces/default/services/echo"
+	switch {
+	case r.URL.Path == "/api/v1/services" && r.URL.Query().Get("watch") == "1":
+		flusher := w.(http.Flusher)
+		for i := 1; i <= 2; i++ {
+			_, _ = fmt.Fprintf(w, `{"type":"ADDED","object":{"metadata":{"name":"s%d","resourceVersion":"%d"}}}`+"\n", i, i)
+			flusher.Flush()
+		}
+		_, _ = w.Write([]byte(`{"type":"BOOKMARK","object":{"metadata":{"resourceVersion":"10"}}}`))
+	case r.URL.Path == "/api/v1/services":
+		var items []json.RawMessage
+		for _, service := range f.services {
+			items = append(items, service)
+		}
+		data, _ := json.Marshal(map[string]interface{}{"metadata": map[string]string{"resourceVersion": "1"},
+			"items": items})
+		_, _ = w.Write(data)
+	case r.URL.Path == item && r.Method == http.MethodPatch:
+		if _, ok := f.services["echo"]; !ok || r.Header.Get("Content-Type") != "application/merge-patch+json" {
+			notFound()
+			return
+		}
+		f.services["echo"], _ = ioutil.ReadAll(r.Body)
+	case r.URL.Path == "/api/v1/namespaces/default/services" && r.Method == http.MethodPost:
+		f.services["echo"], _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	case r.URL.Path == item && r.Method == http.MethodDelete:
+		if _, ok := f.services["echo"]; !ok {
+			notFound()
+			return
+		}
+		delete(f.services, "echo")
+	default:
+		notFound()
+	}
+}
+
+// 测试REST客户端的查询、写入以及watch
+func TestRestClient(t *testing.T) {
+	apiServer := &fakeAPIServer{services: make(map[string]json.RawMessage)}
+	server := httptest.NewServer(apiServer)
+	defer server.Close()
+	ctx := context.Background()
+
+	if _, err := NewClient(&Config{APIServer: server.URL}); err != nil {
+		t.Fatalf("new client err: %s", err.Error())
+	}
+	client, _ := NewClient(&Config{APIServer: server.URL, Token: "wrong"})
+	if _, err := client.ListServices(ctx); err == nil || err.(*statusError).reason != "Unauthorized" {
+		t.Fatalf("list with wrong token should fail: %v", err)
+	}
+
+	client, err := NewClient(&Config{APIServer: server.URL + "/", Token: "test-token"})
+	if err != nil {
+		t.Fatalf("new client err: %s", err.Error())
+	}
+	service := &Service{Kind: "Service", APIVersion: "v1",
+		Metadata: ObjectMeta{Name: "echo", Namespace: "default"}, Spec: ServiceSpec{ClusterIP: "None"}}
+	// 第一次不存在，patch失败后创建；第二次直接patch
+	for i := 0; i < 2; i++ {
+		if err := client.ApplyService(ctx, service); err != nil {
+			t.Fatalf("apply service err: %s", err.Error())
+		}
+	}
+	list, err := client.ListServices(ctx)
+	if err != nil {
+		t.Fatalf("list services err: %s", err.Error())
+	}
+	if len(list.Items) != 1 || list.Items[0].Metadata.Name != "echo" || list.Items[0].Spec.ClusterIP != "None" {
+		t.Fatalf("services are wrong: %+v", list)
+	}
+	for i := 0; i < 2; i++ {
+		if err := client.DeleteService(ctx, "default", "echo"); err != nil {
+			t.Fatalf("delete service err: %s", err.Error())
+		}
+	}
+	expect := []string{
+		"PATCH /api/v1/namespaces/default/services/echo",
+		"POST /api/v1/namespaces/default/services",
+		"PATCH /api/v1/namespaces/default/services/echo",
+		"GET /api/v1/services",
+		"DELETE /api/v1/namespaces/default/services/echo",
+		"DELETE /api/v1/namespaces/default/services/echo",
+	}
+	if fmt.Sprint(apiServer.requests) != fmt.Sprint(expect) {
+		t.Fatalf("requests are wrong: %v", apiServer.requests)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	events, err := client.Watch(ctx, ResourceServices, "")
+	if err != nil {
+		t.Fatalf("watch err: %s", err.Error())
+	}
+	var versions []string
+	for event := range events {
+		versions = append(versions, event.Type+":"+event.resourceVersion())
+	}
+	if fmt.Sprint(versions) != "[ADDED:1 ADDED:2 BOOKMARK:10]" {
+		t.Fatalf("watch events are wrong: %v", versions)
+	}
+}
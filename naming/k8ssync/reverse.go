@@ -0,0 +1,189 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package k8ssync
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+)
+
+// 反向同步的k8s对象上记录来源的北极星命名空间
+const annotationPolarisNamespace = "polaris.io/namespace"
+
+// k8s的Service名需要符合DNS-1035的规则
+var serviceNameRegexp = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+
+// 反向同步：把北极星的服务以headless service以及Endpoints的形式写入k8s
+// 不会覆盖k8s中已经存在的、不是反向同步创建的Service，也不会写回从k8s同步过来的服务
+func (c *controller) reverseSync(ctx context.Context) error {
+	list, err := c.client.ListServices(ctx)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*Service, len(list.Items))
+	for i := range list.Items {
+		item := &list.Items[i]
+		existing[objectKey(item.Metadata.Namespace, item.Metadata.Name)] = item
+	}
+	namespaces := make(map[string]bool, len(c.conf.ReverseSync.Namespaces))
+	for _, namespace := range c.conf.ReverseSync.Namespaces {
+		namespaces[namespace] = true
+	}
+
+	desired := make(map[string]bool)
+	for _, service := range c.registry.services() {
+		if service.Meta[MetaSyncSource] == syncSourceKubernetes {
+			continue
+		}
+		if len(namespaces) > 0 && !namespaces[service.Namespace] {
+			continue
+		}
+		namespace, ok := c.mapper.toKubernetes(service.Namespace)
+		if !ok || len(service.Name) > 63 || !serviceNameRegexp.MatchString(service.Name) {
+			continue
+		}
+		key := objectKey(namespace, service.Name)
+		current, ok := existing[key]
+		if ok && current.Metadata.Labels[LabelManagedBy] != managedByPolaris {
+			log.Debugf("[K8sSync] kubernetes service(%s) is not managed by polaris, skip reverse sync", key)
+			continue
+		}
+		desired[key] = true
+
+		k8sService, endpoints := buildKubernetesObjects(namespace, service, c.registry.instances(service.ID))
+		if ok && current.Metadata.Annotations[annotationRevision] == k8sService.Metadata.Annotations[annotationRevision] {
+			continue
+		}
+		// 先写Endpoints再写Service，Service上的revision表示全部写入成功
+		if err := c.client.ApplyEndpoints(ctx, endpoints); err != nil {
+			log.Errorf("[K8sSync] apply kubernetes endpoints(%s) err: %s", key, err.Error())
+			continue
+		}
+		if err := c.client.ApplyService(ctx, k8sService); err != nil {
+			log.Errorf("[K8sSync] apply kubernetes service(%s) err: %s", key, err.Error())
+			continue
+		}
+		log.Infof("[K8sSync] reverse sync service(%s/%s) to kubernetes service(%s)",
+			service.Namespace, service.Name, key)
+	}
+
+	for key, item := range existing {
+		if desired[key] || item.Metadata.Labels[LabelManagedBy] != managedByPolaris {
+			continue
+		}
+		if err := c.client.DeleteService(ctx, item.Metadata.Namespace, item.Metadata.Name); err != nil {
+			log.Errorf("[K8sSync] delete kubernetes service(%s) err: %s", key, err.Error())
+			continue
+		}
+		if err := c.client.DeleteEndpoints(ctx, item.Metadata.Namespace, item.Metadata.Name); err != nil {
+			log.Errorf("[K8sSync] delete kubernetes endpoints(%s) err: %s", key, err.Error())
+			continue
+		}
+		log.Infof("[K8sSync] delete reverse synced kubernetes service(%s)", key)
+	}
+	return nil
+}
+
+// 构造北极星服务对应的k8s Service以及Endpoints
+// 只有IP地址的实例可以写入Endpoints，隔离的实例不写入，不健康的实例作为未就绪的地址
+func buildKubernetesObjects(namespace string, service *model.Service, instances []*model.Instance) (
+	*Service, *Endpoints) {
+	subsets := make(map[uint32]*EndpointSubset)
+	for _, instance := range instances {
+		if instance.Isolate() || net.ParseIP(instance.Host()) == nil {
+			continue
+		}
+		port := instance.Port()
+		subset, ok := subsets[port]
+		if !ok {
+			protocol := "TCP"
+			if strings.EqualFold(instance.Protocol(), "udp") {
+				protocol = "UDP"
+			}
+			subset = &EndpointSubset{
+				Ports: []EndpointPort{{Name: fmt.Sprintf("port-%d", port), Port: int32(port), Protocol: protocol}},
+			}
+			subsets[port] = subset
+		}
+		address := EndpointAddress{IP: instance.Host()}
+		if instance.Healthy() {
+			subset.Addresses = append(subset.Addresses, address)
+		} else {
+			subset.NotReadyAddresses = append(subset.NotReadyAddresses, address)
+		}
+	}
+
+	ports := make([]uint32, 0, len(subsets))
+	for port := range subsets {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+
+	k8sService := &Service{
+		Kind:       "Service",
+		APIVersion: "v1",
+		Metadata: ObjectMeta{
+			Name:      service.Name,
+			Namespace: namespace,
+			Labels:    map[string]string{LabelManagedBy: managedByPolaris},
+		},
+		// 使用headless service，DNS直接返回实例的地址
+		Spec: ServiceSpec{ClusterIP: "None"},
+	}
+	endpoints := &Endpoints{
+		Kind:       "Endpoints",
+		APIVersion: "v1",
+		Metadata: ObjectMeta{
+			Name:      service.Name,
+			Namespace: namespace,
+			Labels:    map[string]string{LabelManagedBy: managedByPolaris},
+		},
+	}
+	for _, port := range ports {
+		subset := subsets[port]
+		sortAddresses(subset.Addresses)
+		sortAddresses(subset.NotReadyAddresses)
+		servicePort := subset.Ports[0]
+		k8sService.Spec.Ports = append(k8sService.Spec.Ports,
+			ServicePort{Name: servicePort.Name, Protocol: servicePort.Protocol, Port: servicePort.Port})
+		endpoints.Subsets = append(endpoints.Subsets, *subset)
+	}
+
+	data, _ := json.Marshal(endpoints.Subsets)
+	digest := sha1.Sum(data)
+	k8sService.Metadata.Annotations = map[string]string{
+		annotationPolarisNamespace: service.Namespace,
+		annotationRevision:         hex.EncodeToString(digest[:8]),
+	}
+	return k8sService, endpoints
+}
+
+// 地址按照IP排序，保证内容的摘要是稳定的
+func sortAddresses(addresses []EndpointAddress) {
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].IP < addresses[j].IP })
+}
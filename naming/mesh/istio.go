@@ -0,0 +1,561 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package mesh
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// KindVirtualService Istio VirtualService
+	KindVirtualService = "VirtualService"
+	// KindDestinationRule Istio DestinationRule
+	KindDestinationRule = "DestinationRule"
+
+	// 请求标签的key，与SDK传入的路由标签保持一致
+	// HeaderLabelPrefix 请求头的标签前缀
+	HeaderLabelPrefix = "$header."
+	// QueryLabelPrefix 请求参数的标签前缀
+	QueryLabelPrefix = "$query."
+	// PathLabel 请求路径的标签
+	PathLabel = "$path"
+	// MethodLabel 请求方法的标签
+	MethodLabel = "$method"
+
+	// 匹配全部的服务名和命名空间
+	matchAll = "*"
+	// 默认的命名空间
+	defaultNamespace = "default"
+	// 单个目标的默认权重
+	defaultWeight = 100
+)
+
+/**
+ * Unsupported 无法转换的网格资源
+ */
+type Unsupported struct {
+	Kind   string
+	Name   string
+	Reason string
+}
+
+// String 输出可读的描述
+func (u *Unsupported) String() string {
+	return fmt.Sprintf("%s(%s): %s", u.Kind, u.Name, u.Reason)
+}
+
+/**
+ * Result Istio配置的转换结果
+ * Routings按照服务合并，同一个服务的多个VirtualService规则按照出现的顺序追加
+ */
+type Result struct {
+	Routings    []*api.Routing
+	Unsupported []*Unsupported
+}
+
+// 资源的公共部分，spec按照kind再次解析
+type istioResource struct {
+	APIVersion string        `yaml:"apiVersion"`
+	Kind       string        `yaml:"kind"`
+	Metadata   istioMetadata `yaml:"metadata"`
+	Spec       interface{}   `yaml:"spec"`
+}
+
+type istioMetadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+}
+
+type virtualServiceSpec struct {
+	Hosts    []string      `yaml:"hosts"`
+	Gateways []string      `yaml:"gateways"`
+	HTTP     []*httpRoute  `yaml:"http"`
+	TCP      []interface{} `yaml:"tcp"`
+	TLS      []interface{} `yaml:"tls"`
+	// 北极星的服务对所有命名空间可见，忽略exportTo
+	ExportTo []string `yaml:"exportTo"`
+}
+
+type httpRoute struct {
+	Name       string                  `yaml:"name"`
+	Match      []*httpMatchRequest     `yaml:"match"`
+	Route      []*httpRouteDestination `yaml:"route"`
+	Redirect   interface{}             `yaml:"redirect"`
+	Rewrite    interface{}             `yaml:"rewrite"`
+	Timeout    interface{}             `yaml:"timeout"`
+	Retries    interface{}             `yaml:"retries"`
+	Fault      interface{}             `yaml:"fault"`
+	Mirror     interface{}             `yaml:"mirror"`
+	CorsPolicy interface{}             `yaml:"corsPolicy"`
+	Headers    interface{}             `yaml:"headers"`
+}
+
+type httpMatchRequest struct {
+	Name            string                  `yaml:"name"`
+	URI             *stringMatch            `yaml:"uri"`
+	Method          *stringMatch            `yaml:"method"`
+	Headers         map[string]*stringMatch `yaml:"headers"`
+	QueryParams     map[string]*stringMatch `yaml:"queryParams"`
+	SourceLabels    map[string]string       `yaml:"sourceLabels"`
+	SourceNamespace string                  `yaml:"sourceNamespace"`
+	Scheme          interface{}             `yaml:"scheme"`
+	Authority       interface{}             `yaml:"authority"`
+	Port            interface{}             `yaml:"port"`
+	Gateways        interface{}             `yaml:"gateways"`
+	IgnoreURICase   interface{}             `yaml:"ignoreUriCase"`
+	WithoutHeaders  interface{}             `yaml:"withoutHeaders"`
+}
+
+type stringMatch struct {
+	Exact  string `yaml:"exact"`
+	Prefix string `yaml:"prefix"`
+	Regex  string `yaml:"regex"`
+}
+
+type httpRouteDestination struct {
+	Destination *destination `yaml:"destination"`
+	Weight      uint32       `yaml:"weight"`
+	Headers     interface{}  `yaml:"headers"`
+}
+
+type destination struct {
+	Host   string `yaml:"host"`
+	Subset string `yaml:"subset"`
+}
+
+type destinationRuleSpec struct {
+	Host          string      `yaml:"host"`
+	Subsets       []*subset   `yaml:"subsets"`
+	TrafficPolicy interface{} `yaml:"trafficPolicy"`
+	ExportTo      []string    `yaml:"exportTo"`
+}
+
+type subset struct {
+	Name          string            `yaml:"name"`
+	Labels        map[string]string `yaml:"labels"`
+	TrafficPolicy interface{}       `yaml:"trafficPolicy"`
+}
+
+// 服务的唯一标识
+type serviceKey struct {
+	name      string
+	namespace string
+}
+
+/**
+ * TranslateIstio 把Istio的VirtualService以及DestinationRule转换为北极星的路由规则
+ * content为YAML格式，支持使用---分隔的多个资源
+ * namespace不为空时，所有的服务都使用该命名空间，否则根据host以及资源的命名空间推断
+ */
+func TranslateIstio(content []byte, namespace string) (*Result, error) {
+	resources, err := decodeResources(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(resources) == 0 {
+		return nil, errors.New("no istio resource found")
+	}
+
+	t := &translator{
+		namespace: namespace,
+		subsets:   make(map[serviceKey]map[string]map[string]string),
+		routings:  make(map[serviceKey]*api.Routing),
+		result:    &Result{},
+	}
+	// 先收集DestinationRule的subset，VirtualService的目标需要引用
+	for _, resource := range resources {
+		if resource.Kind == KindDestinationRule {
+			t.addDestinationRule(resource)
+		}
+	}
+	for _, resource := range resources {
+		switch resource.Kind {
+		case KindDestinationRule:
+		case KindVirtualService:
+			t.addVirtualService(resource)
+		default:
+			t.unsupported(resource, "kind is not supported")
+		}
+	}
+	return t.result, nil
+}
+
+// 解析YAML中的全部资源
+func decodeResources(content []byte) ([]*istioResource, error) {
+	var resources []*istioResource
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		resource := &istioResource{}
+		err := decoder.Decode(resource)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		// 空文档
+		if resource.Kind == "" && resource.Spec == nil {
+			continue
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+// 把spec解析为具体的结构
+func decodeSpec(spec interface{}, out interface{}) error {
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	return yaml.UnmarshalStrict(data, out)
+}
+
+// 转换过程的状态
+type translator struct {
+	namespace string
+	// 服务 -> subset名 -> 实例标签
+	subsets  map[serviceKey]map[string]map[string]string
+	routings map[serviceKey]*api.Routing
+	result   *Result
+}
+
+// 记录无法转换的资源
+func (t *translator) unsupported(resource *istioResource, format string, args ...interface{}) {
+	t.result.Unsupported = append(t.result.Unsupported, &Unsupported{
+		Kind:   resource.Kind,
+		Name:   resource.Metadata.Name,
+		Reason: fmt.Sprintf(format, args...),
+	})
+}
+
+// 把Istio的host转换为北极星的服务
+// 形如name.namespace.svc.cluster.local或者name.namespace的host，取前两段作为服务名和命名空间
+func (t *translator) parseHost(host string, resourceNamespace string) (serviceKey, bool) {
+	if host == "" || strings.Contains(host, matchAll) {
+		return serviceKey{}, false
+	}
+	key := serviceKey{name: host, namespace: resourceNamespace}
+	parts := strings.Split(host, ".")
+	if len(parts) == 2 || (len(parts) > 2 && parts[2] == "svc") {
+		key.name = parts[0]
+		key.namespace = parts[1]
+	}
+	if key.namespace == "" {
+		key.namespace = defaultNamespace
+	}
+	if t.namespace != "" {
+		key.namespace = t.namespace
+	}
+	return key, true
+}
+
+// 收集DestinationRule的subset
+func (t *translator) addDestinationRule(resource *istioResource) {
+	spec := &destinationRuleSpec{}
+	if err := decodeSpec(resource.Spec, spec); err != nil {
+		t.unsupported(resource, "invalid spec: %s", err.Error())
+		return
+	}
+	key, ok := t.parseHost(spec.Host, resource.Metadata.Namespace)
+	if !ok {
+		t.unsupported(resource, "host(%s) is not supported", spec.Host)
+		return
+	}
+	if spec.TrafficPolicy != nil {
+		t.unsupported(resource, "trafficPolicy is not supported, ignored")
+	}
+
+	subsets, ok := t.subsets[key]
+	if !ok {
+		subsets = make(map[string]map[string]string)
+		t.subsets[key] = subsets
+	}
+	for _, item := range spec.Subsets {
+		if item == nil {
+			continue
+		}
+		if item.TrafficPolicy != nil {
+			t.unsupported(resource, "subset(%s) trafficPolicy is not supported, ignored", item.Name)
+		}
+		subsets[item.Name] = item.Labels
+	}
+}
+
+// 转换VirtualService，每个host对应一个服务的入站路由
+func (t *translator) addVirtualService(resource *istioResource) {
+	spec := &virtualServiceSpec{}
+	if err := decodeSpec(resource.Spec, spec); err != nil {
+		t.unsupported(resource, "invalid spec: %s", err.Error())
+		return
+	}
+	if len(spec.Gateways) > 0 {
+		t.unsupported(resource, "gateways is not supported, ignored")
+	}
+	if len(spec.TCP) > 0 {
+		t.unsupported(resource, "tcp routes are not supported")
+	}
+	if len(spec.TLS) > 0 {
+		t.unsupported(resource, "tls routes are not supported")
+	}
+
+	var routes []*api.Route
+	for i, rule := range spec.HTTP {
+		if rule == nil {
+			continue
+		}
+		if route := t.translateHTTPRoute(resource, httpRouteName(i, rule), rule); route != nil {
+			routes = append(routes, route)
+		}
+	}
+	if len(routes) == 0 {
+		t.unsupported(resource, "no http route can be translated")
+		return
+	}
+
+	for _, host := range spec.Hosts {
+		key, ok := t.parseHost(host, resource.Metadata.Namespace)
+		if !ok {
+			t.unsupported(resource, "host(%s) is not supported", host)
+			continue
+		}
+		routing, ok := t.routings[key]
+		if !ok {
+			routing = &api.Routing{
+				Service:   &wrappers.StringValue{Value: key.name},
+				Namespace: &wrappers.StringValue{Value: key.namespace},
+			}
+			t.routings[key] = routing
+			t.result.Routings = append(t.result.Routings, routing)
+		}
+		routing.Inbounds = append(routing.Inbounds, routes...)
+	}
+}
+
+// http路由的名字，用于定位无法转换的规则
+func httpRouteName(index int, rule *httpRoute) string {
+	if rule.Name != "" {
+		return rule.Name
+	}
+	return fmt.Sprintf("http[%d]", index)
+}
+
+// 转换一条http路由，match之间为或的关系，对应多个Source
+func (t *translator) translateHTTPRoute(resource *istioResource, name string, rule *httpRoute) *api.Route {
+	ignored := map[string]interface{}{
+		"redirect":   rule.Redirect,
+		"rewrite":    rule.Rewrite,
+		"timeout":    rule.Timeout,
+		"retries":    rule.Retries,
+		"fault":      rule.Fault,
+		"mirror":     rule.Mirror,
+		"corsPolicy": rule.CorsPolicy,
+		"headers":    rule.Headers,
+	}
+	for _, field := range sortedFields(ignored) {
+		t.unsupported(resource, "http route(%s) %s is not supported, ignored", name, field)
+	}
+
+	route := &api.Route{}
+	if len(rule.Match) == 0 {
+		route.Sources = append(route.Sources, &api.Source{
+			Service:   &wrappers.StringValue{Value: matchAll},
+			Namespace: &wrappers.StringValue{Value: matchAll},
+		})
+	}
+	for _, match := range rule.Match {
+		if match == nil {
+			continue
+		}
+		source, err := t.translateMatch(match)
+		if err != nil {
+			t.unsupported(resource, "http route(%s) match is not supported: %s", name, err.Error())
+			return nil
+		}
+		route.Sources = append(route.Sources, source)
+	}
+
+	for _, item := range rule.Route {
+		if item == nil || item.Destination == nil {
+			continue
+		}
+		if item.Headers != nil {
+			t.unsupported(resource, "http route(%s) destination headers is not supported, ignored", name)
+		}
+		dest, err := t.translateDestination(resource, item)
+		if err != nil {
+			t.unsupported(resource, "http route(%s) destination is not supported: %s", name, err.Error())
+			continue
+		}
+		route.Destinations = append(route.Destinations, dest)
+	}
+	if len(route.Destinations) == 0 {
+		t.unsupported(resource, "http route(%s) has no destination can be translated", name)
+		return nil
+	}
+	// 只有一个目标并且没有设置权重时，流量全部路由到该目标
+	if len(route.Destinations) == 1 && route.Destinations[0].GetWeight().GetValue() == 0 {
+		route.Destinations[0].Weight = &wrappers.UInt32Value{Value: defaultWeight}
+	}
+	return route
+}
+
+// 把match转换为主调方的标签匹配
+func (t *translator) translateMatch(match *httpMatchRequest) (*api.Source, error) {
+	ignored := map[string]interface{}{
+		"scheme":         match.Scheme,
+		"authority":      match.Authority,
+		"port":           match.Port,
+		"gateways":       match.Gateways,
+		"ignoreUriCase":  match.IgnoreURICase,
+		"withoutHeaders": match.WithoutHeaders,
+	}
+	if fields := sortedFields(ignored); len(fields) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(fields, ","))
+	}
+
+	namespace := matchAll
+	if match.SourceNamespace != "" {
+		namespace = match.SourceNamespace
+	}
+	source := &api.Source{
+		Service:   &wrappers.StringValue{Value: matchAll},
+		Namespace: &wrappers.StringValue{Value: namespace},
+		Metadata:  make(map[string]*api.MatchString),
+	}
+	if match.URI != nil {
+		value, err := translateStringMatch(match.URI)
+		if err != nil {
+			return nil, fmt.Errorf("uri %s", err.Error())
+		}
+		source.Metadata[PathLabel] = value
+	}
+	if match.Method != nil {
+		value, err := translateStringMatch(match.Method)
+		if err != nil {
+			return nil, fmt.Errorf("method %s", err.Error())
+		}
+		source.Metadata[MethodLabel] = value
+	}
+	for key, header := range match.Headers {
+		value, err := translateStringMatch(header)
+		if err != nil {
+			return nil, fmt.Errorf("header(%s) %s", key, err.Error())
+		}
+		source.Metadata[HeaderLabelPrefix+key] = value
+	}
+	for key, param := range match.QueryParams {
+		value, err := translateStringMatch(param)
+		if err != nil {
+			return nil, fmt.Errorf("query param(%s) %s", key, err.Error())
+		}
+		source.Metadata[QueryLabelPrefix+key] = value
+	}
+	// 主调方实例标签
+	for key, label := range match.SourceLabels {
+		source.Metadata[key] = exactMatch(label)
+	}
+	return source, nil
+}
+
+// 把Istio的StringMatch转换为北极星的MatchString，前缀匹配转换为正则匹配
+// Istio的正则要求完整匹配，北极星的正则为部分匹配，因此需要加上首尾的锚点
+func translateStringMatch(match *stringMatch) (*api.MatchString, error) {
+	switch {
+	case match == nil:
+		return nil, errors.New("is empty")
+	case match.Exact != "":
+		return exactMatch(match.Exact), nil
+	case match.Prefix != "":
+		return &api.MatchString{
+			Type:  api.MatchString_REGEX,
+			Value: &wrappers.StringValue{Value: "^" + regexp.QuoteMeta(match.Prefix) + ".*"},
+		}, nil
+	case match.Regex != "":
+		if _, err := regexp.Compile(match.Regex); err != nil {
+			return nil, fmt.Errorf("regex is invalid: %s", err.Error())
+		}
+		return &api.MatchString{
+			Type:  api.MatchString_REGEX,
+			Value: &wrappers.StringValue{Value: "^(?:" + match.Regex + ")$"},
+		}, nil
+	default:
+		return nil, errors.New("has no exact, prefix or regex")
+	}
+}
+
+func exactMatch(value string) *api.MatchString {
+	return &api.MatchString{
+		Type:  api.MatchString_EXACT,
+		Value: &wrappers.StringValue{Value: value},
+	}
+}
+
+// 把路由目标转换为北极星的Destination，subset转换为实例标签
+func (t *translator) translateDestination(resource *istioResource,
+	item *httpRouteDestination) (*api.Destination, error) {
+	key, ok := t.parseHost(item.Destination.Host, resource.Metadata.Namespace)
+	if !ok {
+		return nil, fmt.Errorf("host(%s) is invalid", item.Destination.Host)
+	}
+
+	dest := &api.Destination{
+		Service:   &wrappers.StringValue{Value: key.name},
+		Namespace: &wrappers.StringValue{Value: key.namespace},
+		Priority:  &wrappers.UInt32Value{Value: 0},
+	}
+	if item.Weight > 0 {
+		dest.Weight = &wrappers.UInt32Value{Value: item.Weight}
+	}
+	if item.Destination.Subset == "" {
+		return dest, nil
+	}
+
+	labels, ok := t.subsets[key][item.Destination.Subset]
+	if !ok {
+		return nil, fmt.Errorf("subset(%s) of host(%s) not found in destination rules",
+			item.Destination.Subset, item.Destination.Host)
+	}
+	dest.Metadata = make(map[string]*api.MatchString, len(labels))
+	for label, value := range labels {
+		dest.Metadata[label] = exactMatch(value)
+	}
+	return dest, nil
+}
+
+// 返回设置了值的字段名，按字母序排列，保证输出稳定
+func sortedFields(fields map[string]interface{}) []string {
+	var names []string
+	for name, value := range fields {
+		if value != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,204 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package mesh
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+)
+
+const testdataDir = "../test/testdata/mesh"
+
+func readTestdata(t *testing.T, names ...string) []byte {
+	var docs []string
+	for _, name := range names {
+		data, err := ioutil.ReadFile(filepath.Join(testdataDir, name))
+		if err != nil {
+			t.Fatalf("error: %s", err.Error())
+		}
+		docs = append(docs, string(data))
+	}
+	return []byte(strings.Join(docs, "\n---\n"))
+}
+
+// TestTranslateIstio 测试VirtualService以及DestinationRule的转换
+func TestTranslateIstio(t *testing.T) {
+	content := readTestdata(t, "normal-destinationRule.yaml", "normal-virtualService.yaml", "normal-gateway.yaml")
+	result, err := TranslateIstio(content, "")
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	if len(result.Routings) != 1 {
+		t.Fatalf("expect 1 routing, got %d", len(result.Routings))
+	}
+	routing := result.Routings[0]
+	if routing.GetService().GetValue() != "reviews" || routing.GetNamespace().GetValue() != "prod" {
+		t.Fatalf("routing service not match: %s", routing.String())
+	}
+	if len(routing.GetInbounds()) != 2 {
+		t.Fatalf("expect 2 inbounds, got %d", len(routing.GetInbounds()))
+	}
+
+	// 多个uri匹配转换为多个source
+	first := routing.GetInbounds()[0]
+	if len(first.GetSources()) != 2 {
+		t.Fatalf("expect 2 sources, got %d", len(first.GetSources()))
+	}
+	path := first.GetSources()[0].GetMetadata()[PathLabel]
+	if path.GetType() != api.MatchString_REGEX || path.GetValue().GetValue() != "^/wpcatalog.*" {
+		t.Fatalf("uri prefix not match: %s", path.String())
+	}
+	dest := first.GetDestinations()[0]
+	if dest.GetMetadata()["version"].GetValue().GetValue() != "v2" || dest.GetWeight().GetValue() != defaultWeight {
+		t.Fatalf("destination not match: %s", dest.String())
+	}
+
+	// 没有match的路由匹配全部主调方
+	second := routing.GetInbounds()[1]
+	if second.GetSources()[0].GetService().GetValue() != matchAll {
+		t.Fatalf("source should match all: %s", second.String())
+	}
+
+	// rewrite以及Gateway无法转换
+	var reasons []string
+	for _, item := range result.Unsupported {
+		reasons = append(reasons, item.String())
+	}
+	text := strings.Join(reasons, "\n")
+	if !strings.Contains(text, "rewrite is not supported") || !strings.Contains(text, "Gateway(my-gateway)") {
+		t.Fatalf("unsupported not match:\n%s", text)
+	}
+}
+
+// TestTranslateIstio_HeaderAndWeight 测试header匹配以及权重
+func TestTranslateIstio_HeaderAndWeight(t *testing.T) {
+	content := []byte(`
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: ratings
+  namespace: prod
+spec:
+  hosts:
+    - ratings
+  http:
+    - match:
+        - headers:
+            end-user:
+              exact: jason
+          sourceLabels:
+            app: reviews
+      route:
+        - destination:
+            host: ratings
+            subset: v1
+          weight: 80
+        - destination:
+            host: ratings
+            subset: v3
+          weight: 20
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: DestinationRule
+metadata:
+  name: ratings
+  namespace: prod
+spec:
+  host: ratings.prod.svc.cluster.local
+  subsets:
+    - name: v1
+      labels:
+        version: v1
+`)
+	result, err := TranslateIstio(content, "Test")
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	if len(result.Routings) != 1 || result.Routings[0].GetNamespace().GetValue() != "Test" {
+		t.Fatalf("routing not match: %+v", result.Routings)
+	}
+	route := result.Routings[0].GetInbounds()[0]
+	source := route.GetSources()[0]
+	if source.GetMetadata()[HeaderLabelPrefix+"end-user"].GetValue().GetValue() != "jason" {
+		t.Fatalf("header match not match: %s", source.String())
+	}
+	if source.GetMetadata()["app"].GetValue().GetValue() != "reviews" {
+		t.Fatalf("source labels not match: %s", source.String())
+	}
+	// subset v3不存在，只保留v1
+	if len(route.GetDestinations()) != 1 || route.GetDestinations()[0].GetWeight().GetValue() != 80 {
+		t.Fatalf("destinations not match: %s", route.String())
+	}
+	if len(result.Unsupported) != 1 || !strings.Contains(result.Unsupported[0].Reason, "subset(v3)") {
+		t.Fatalf("unsupported not match: %+v", result.Unsupported)
+	}
+}
+
+// TestTranslateIstio_Invalid 测试非法的YAML
+func TestTranslateIstio_Invalid(t *testing.T) {
+	if _, err := TranslateIstio(readTestdata(t, "invalid-virtualService.yaml"), ""); err == nil {
+		t.Fatalf("invalid yaml should fail")
+	}
+	if _, err := TranslateIstio([]byte("---\n"), ""); err == nil {
+		t.Fatalf("empty content should fail")
+	}
+}
+
+// TestTranslateStringMatch 测试正则按照Istio的语义完整匹配
+func TestTranslateStringMatch(t *testing.T) {
+	cases := []struct {
+		match   *stringMatch
+		value   string
+		matched []string
+		missed  []string
+	}{
+		{&stringMatch{Prefix: "/api"}, "^/api.*", []string{"/api", "/api/v1"}, []string{"/v1/api"}},
+		{&stringMatch{Regex: "v1|v2"}, "^(?:v1|v2)$", []string{"v1", "v2"}, []string{"v10", "xv2", "v1v2"}},
+		{&stringMatch{Regex: "/users/[0-9]+"}, "^(?:/users/[0-9]+)$", []string{"/users/12"},
+			[]string{"/users/12/orders", "/v1/users/12"}},
+	}
+	for _, c := range cases {
+		result, err := translateStringMatch(c.match)
+		if err != nil {
+			t.Fatalf("error: %s", err.Error())
+		}
+		if result.GetType() != api.MatchString_REGEX || result.GetValue().GetValue() != c.value {
+			t.Fatalf("expect regex %s, got %s", c.value, result.String())
+		}
+		re := regexp.MustCompile(result.GetValue().GetValue())
+		for _, value := range c.matched {
+			if !re.MatchString(value) {
+				t.Fatalf("%s should match %s", c.value, value)
+			}
+		}
+		for _, value := range c.missed {
+			if re.MatchString(value) {
+				t.Fatalf("%s should not match %s", c.value, value)
+			}
+		}
+	}
+
+	if _, err := translateStringMatch(&stringMatch{Regex: "("}); err == nil {
+		t.Fatalf("invalid regex should return error")
+	}
+}
@@ -0,0 +1,83 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/naming/mesh"
+	"go.uber.org/zap"
+)
+
+/**
+ * ImportIstioRoutings 导入Istio的VirtualService以及DestinationRule，转换为服务的路由规则
+ * 服务已经存在路由规则的，默认返回ExistedResource；overwrite为true时使用转换结果覆盖inbounds，保留原有的outbounds
+ * 无法转换的资源以InvalidMeshParameter的回复返回，Info中为具体的原因
+ */
+func (s *Server) ImportIstioRoutings(ctx context.Context, content []byte, namespace string,
+	overwrite bool) *api.BatchWriteResponse {
+	rid := ParseRequestID(ctx)
+	if len(content) == 0 {
+		return api.NewBatchWriteResponse(api.EmptyRequest)
+	}
+
+	result, err := mesh.TranslateIstio(content, namespace)
+	if err != nil {
+		log.Error(err.Error(), ZapRequestID(rid))
+		return api.NewBatchWriteResponseWithMsg(api.InvalidMeshParameter, err.Error())
+	}
+
+	resp := api.NewBatchWriteResponse(api.ExecuteSuccess)
+	for _, routing := range result.Routings {
+		ret := s.CreateRoutingConfig(ctx, routing)
+		if ret.GetCode().GetValue() == api.ExistedResource && overwrite {
+			ret = s.overwriteIstioRouting(ctx, routing)
+		}
+		resp.Collect(ret)
+	}
+	for _, item := range result.Unsupported {
+		log.Warn("[Mesh] istio resource can not be translated", ZapRequestID(rid),
+			zap.String("resource", item.String()))
+		resp.Collect(api.NewResponseWithMsg(api.InvalidMeshParameter, item.String()))
+	}
+
+	return api.FormatBatchWriteResponse(resp)
+}
+
+// 使用导入的inbounds覆盖服务已有的路由规则，outbounds不是由Istio资源转换的，保持不变
+func (s *Server) overwriteIstioRouting(ctx context.Context, routing *api.Routing) *api.Response {
+	rid := ParseRequestID(ctx)
+	service := routing.GetService().GetValue()
+	namespace := routing.GetNamespace().GetValue()
+	conf, err := s.storage.GetRoutingConfigWithService(service, namespace)
+	if err != nil {
+		log.Error(err.Error(), ZapRequestID(rid))
+		return api.NewRoutingResponse(api.StoreLayerException, routing)
+	}
+	current, err := routingConfig2API(conf, service, namespace)
+	if err != nil {
+		log.Error(err.Error(), ZapRequestID(rid))
+		return api.NewRoutingResponse(api.ParseRoutingException, routing)
+	}
+	if current != nil {
+		routing.Outbounds = current.GetOutbounds()
+	}
+	return s.UpdateRoutingConfig(ctx, routing)
+}
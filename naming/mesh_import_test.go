@@ -0,0 +1,88 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	authmock "github.com/polarismesh/polaris-server/naming/auth/mock"
+	"github.com/polarismesh/polaris-server/store/mock"
+)
+
+// TestServer_ImportIstioRoutings 测试已经存在路由规则时，默认返回冲突，覆盖时保留原有的outbounds
+func TestServer_ImportIstioRoutings(t *testing.T) {
+	var docs []string
+	for _, name := range []string{"normal-destinationRule.yaml", "normal-virtualService.yaml"} {
+		data, err := ioutil.ReadFile("test/testdata/mesh/" + name)
+		if err != nil {
+			t.Fatalf("error: %s", err.Error())
+		}
+		docs = append(docs, string(data))
+	}
+	content := []byte(strings.Join(docs, "\n---\n"))
+
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	authority := authmock.NewMockAuthority(ctl)
+	authority.EXPECT().VerifyUser("admin", gomock.Any()).Return(true).AnyTimes()
+
+	service := &model.Service{ID: "svc-id", Name: "reviews", Namespace: "prod"}
+	inBounds, outBounds, err := marshalRoutingConfig(
+		[]*api.Route{{Sources: []*api.Source{{Service: utils.NewStringValue("manual")}}}},
+		[]*api.Route{{Sources: []*api.Source{{Service: utils.NewStringValue("reviews")}}}})
+	if err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	existed := &model.RoutingConfig{ID: service.ID, InBounds: string(inBounds), OutBounds: string(outBounds)}
+
+	storage := mock.NewMockStore(ctl)
+	tx := mock.NewMockTransaction(ctl)
+	storage.EXPECT().CreateTransaction().Return(tx, nil).Times(2)
+	tx.EXPECT().RLockService("reviews", "prod").Return(service, nil).Times(2)
+	tx.EXPECT().Commit().Return(nil).Times(2)
+	storage.EXPECT().GetRoutingConfigWithService("reviews", "prod").Return(existed, nil).AnyTimes()
+	storage.EXPECT().GetService("reviews", "prod").Return(service, nil)
+	var updated *model.RoutingConfig
+	storage.EXPECT().UpdateRoutingConfig(gomock.Any()).DoAndReturn(func(conf *model.RoutingConfig) error {
+		updated = conf
+		return nil
+	})
+	s := &Server{authority: authority, storage: storage}
+	ctx := context.WithValue(context.Background(), utils.StringContext("user"), "admin")
+
+	// 不支持的rewrite在路由规则之后以InvalidMeshParameter返回
+	routingCode := func(resp *api.BatchWriteResponse) uint32 {
+		return resp.GetResponses()[0].GetCode().GetValue()
+	}
+	if code := routingCode(s.ImportIstioRoutings(ctx, content, "", false)); code != api.ExistedResource {
+		t.Fatalf("existed routing should be reported, got %d", code)
+	}
+	if code := routingCode(s.ImportIstioRoutings(ctx, content, "", true)); code != api.ExecuteSuccess || updated == nil {
+		t.Fatalf("existed routing should be overwritten, got %d", code)
+	}
+	if updated.OutBounds != existed.OutBounds || updated.InBounds == existed.InBounds {
+		t.Fatalf("only inbounds should be overwritten, in: %s, out: %s", updated.InBounds, updated.OutBounds)
+	}
+}
@@ -0,0 +1,90 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/metrics"
+)
+
+/**
+ * @brief 注册核心逻辑层的运行指标
+ */
+func registerMetrics(s *Server) {
+	registry := metrics.DefaultRegistry()
+
+	if s.caches != nil {
+		registry.NewGaugeFunc("polaris_cache_size", "Number of objects in the naming cache.",
+			s.cacheSizeMetrics, "resource")
+		registry.NewGaugeFunc("polaris_cache_update_lag_seconds",
+			"Seconds since the last successful update of the naming cache.",
+			s.cacheLagMetrics, "resource")
+	}
+
+	// 批量控制器可以热更新，每次采集时重新获取
+	registry.NewGaugeFunc("polaris_batch_queue_depth", "Number of requests waiting in the batch queue.",
+		func() []*metrics.GaugeValue {
+			bc := s.batchCtrl()
+			if bc == nil {
+				return nil
+			}
+			return []*metrics.GaugeValue{
+				{LabelValues: []string{"register"}, Value: float64(bc.CreateInstanceQueueLen())},
+				{LabelValues: []string{"deregister"}, Value: float64(bc.DeleteInstanceQueueLen())},
+			}
+		}, "queue")
+
+	if s.hbMgr != nil {
+		registry.NewGaugeFunc("polaris_heartbeat_instances", "Number of instances in the local heartbeat map.",
+			func() []*metrics.GaugeValue {
+				return []*metrics.GaugeValue{{Value: float64(s.hbMgr.GetHbMapSize())}}
+			})
+	}
+}
+
+// 缓存中各类资源的数量
+func (s *Server) cacheSizeMetrics() []*metrics.GaugeValue {
+	circuitBreakers := 0
+	s.caches.CircuitBreaker().GetCircuitBreakerCount(func(k, v interface{}) bool {
+		circuitBreakers++
+		return true
+	})
+
+	return []*metrics.GaugeValue{
+		{LabelValues: []string{"service"}, Value: float64(s.caches.Service().GetServicesCount())},
+		{LabelValues: []string{"instance"}, Value: float64(s.caches.Instance().GetInstancesCount())},
+		{LabelValues: []string{"routingConfig"}, Value: float64(s.caches.RoutingConfig().GetRoutingConfigCount())},
+		{LabelValues: []string{"rateLimitConfig"}, Value: float64(s.caches.RateLimit().GetRateLimitsCount())},
+		{LabelValues: []string{"circuitBreakerConfig"}, Value: float64(circuitBreakers)},
+		{LabelValues: []string{"serviceRevision"}, Value: float64(s.caches.GetServiceRevisionCount())},
+	}
+}
+
+// 每个缓存距离最近一次成功更新的时间
+func (s *Server) cacheLagMetrics() []*metrics.GaugeValue {
+	now := time.Now()
+	var out []*metrics.GaugeValue
+	for name, lastUpdate := range s.caches.GetLastUpdateTimes() {
+		out = append(out, &metrics.GaugeValue{
+			LabelValues: []string{name},
+			Value:       now.Sub(lastUpdate).Seconds(),
+		})
+	}
+	return out
+}
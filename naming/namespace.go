@@ -26,6 +26,7 @@ import (
 	"github.com/polarismesh/polaris-server/common/log"
 	"github.com/polarismesh/polaris-server/common/model"
 	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming/auth"
 	"go.uber.org/zap"
 )
 
@@ -59,6 +60,11 @@ func (s *Server) CreateNamespace(ctx context.Context, req *api.Namespace) *api.R
 
 	namespaceName := req.GetName().GetValue()
 
+	// 带有登录用户时校验用户的角色权限
+	if found, ok := s.verifyAuthByUser(ctx, auth.ResourceNamespace, namespaceName, ""); found && !ok {
+		return api.NewNamespaceResponse(api.Unauthorized, req)
+	}
+
 	// 检查是否存在
 	namespace, err := s.storage.GetNamespace(namespaceName)
 	if err != nil {
@@ -150,7 +156,7 @@ func (s *Server) DeleteNamespace(ctx context.Context, req *api.Namespace) *api.R
 	}
 
 	// 鉴权
-	if ok := s.authority.VerifyNamespace(namespace.Token, parseNamespaceToken(ctx, req)); !ok {
+	if ok := s.verifyNamespaceAuth(ctx, namespace, parseNamespaceToken(ctx, req)); !ok {
 		return api.NewNamespaceResponse(api.Unauthorized, req)
 	}
 
@@ -188,7 +194,7 @@ func (s *Server) DeleteNamespace(ctx context.Context, req *api.Namespace) *api.R
 
 	msg := fmt.Sprintf("delete namepsace: name=%v", namespace.Name)
 	log.Info(msg, zap.String("request-id", requestID))
-	s.RecordHistory(namespaceRecordEntry(ctx, req, model.ODelete))
+	s.RecordHistory(withBefore(namespaceRecordEntry(ctx, req, model.ODelete), namespaceSnapshot(namespace)))
 
 	return api.NewNamespaceResponse(api.ExecuteSuccess, req)
 }
@@ -226,6 +232,7 @@ func (s *Server) UpdateNamespace(ctx context.Context, req *api.Namespace) *api.R
 	}
 
 	rid := ParseRequestID(ctx)
+	before := namespaceSnapshot(namespace)
 	// 修改
 	s.updateNamespaceAttribute(req, namespace)
 
@@ -237,7 +244,7 @@ func (s *Server) UpdateNamespace(ctx context.Context, req *api.Namespace) *api.R
 
 	msg := fmt.Sprintf("update namepsace: name=%v", namespace.Name)
 	log.Info(msg, zap.String("request-id", rid))
-	s.RecordHistory(namespaceRecordEntry(ctx, req, model.OUpdate))
+	s.RecordHistory(withBefore(namespaceRecordEntry(ctx, req, model.OUpdate), before))
 
 	return api.NewNamespaceResponse(api.ExecuteSuccess, req)
 }
@@ -376,7 +383,7 @@ func (s *Server) checkNamespaceAuthority(ctx context.Context, req *api.Namespace
 	}
 
 	// 鉴权
-	if ok := s.authority.VerifyNamespace(namespace.Token, namespaceToken); !ok {
+	if ok := s.verifyNamespaceAuth(ctx, namespace, namespaceToken); !ok {
 		return nil, api.NewNamespaceResponse(api.Unauthorized, req)
 	}
 
@@ -479,11 +486,41 @@ func parseNamespaceToken(ctx context.Context, req *api.Namespace) string {
 
 // 生成命名空间的记录entry
 func namespaceRecordEntry(ctx context.Context, req *api.Namespace, opt model.OperationType) *model.RecordEntry {
-	return &model.RecordEntry{
+	entry := &model.RecordEntry{
 		ResourceType:  model.RNamespace,
 		OperationType: opt,
 		Namespace:     req.GetName().GetValue(),
 		Operator:      ParseOperator(ctx),
 		CreateTime:    time.Now(),
 	}
+	if opt != model.ODelete {
+		entry.Object = &api.Namespace{
+			Name:    req.GetName(),
+			Comment: req.GetComment(),
+			Owners:  req.GetOwners(),
+		}
+	}
+	return entry
+}
+
+// 命名空间的快照，不包括token
+func namespaceSnapshot(namespace *model.Namespace) *api.Namespace {
+	if namespace == nil {
+		return nil
+	}
+	return &api.Namespace{
+		Name:    utils.NewStringValue(namespace.Name),
+		Comment: utils.NewStringValue(namespace.Comment),
+		Owners:  utils.NewStringValue(namespace.Owner),
+	}
+}
+
+/**
+ * @brief 命名空间鉴权，带有登录用户时使用用户的角色鉴权，否则校验命名空间的Token
+ */
+func (s *Server) verifyNamespaceAuth(ctx context.Context, namespace *model.Namespace, token string) bool {
+	if found, ok := s.verifyAuthByUser(ctx, auth.ResourceNamespace, namespace.Name, ""); found {
+		return ok
+	}
+	return s.authority.VerifyNamespace(namespace.Token, token)
 }
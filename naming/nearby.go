@@ -0,0 +1,289 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming/cache"
+)
+
+/**
+ * NearbyConfig 就近过滤配置
+ * 就近范围内健康实例的占比低于MinHealthyPercent时，扩大到更大的范围
+ * 为0时，只有就近范围内没有健康实例才扩大范围
+ */
+type NearbyConfig struct {
+	MinHealthyPercent uint32 `yaml:"minHealthyPercent"`
+}
+
+const (
+	// 就近缓存的有效期，过期后重新查询实例的地理位置，使CMDB的变化生效
+	nearbyCacheTTL = time.Minute
+	// 每个服务缓存的就近范围的最大个数，超过时清空
+	maxNearbyScopes = 1024
+)
+
+// 就近范围从小到大的顺序，最后返回全部实例
+var nearbyLevels = []api.DiscoverRequest_NearbyLevel{
+	api.DiscoverRequest_CAMPUS,
+	api.DiscoverRequest_ZONE,
+	api.DiscoverRequest_REGION,
+}
+
+// 获取就近过滤配置，就近过滤配置可以热更新
+func (s *Server) nearbyConfig() NearbyConfig {
+	s.nearbyMutex.RLock()
+	defer s.nearbyMutex.RUnlock()
+	return s.nearbyConf
+}
+
+// 更新就近过滤配置
+func (s *Server) setNearbyConfig(conf NearbyConfig) {
+	s.nearbyMutex.Lock()
+	s.nearbyConf = conf
+	s.nearbyMutex.Unlock()
+}
+
+// 获取调用方的地理位置，请求中没有带上时，通过CMDB根据调用方的IP查询
+func (s *Server) getCallerLocation(ctx context.Context, req *api.DiscoverRequest) *api.Location {
+	if location := req.GetLocation(); !isEmptyLocation(location) {
+		return location
+	}
+
+	clientIP, _ := ctx.Value(utils.StringContext("client-ip")).(string)
+	if clientIP == "" {
+		return nil
+	}
+	if location := s.getLocation(clientIP); location != nil {
+		return location.Proto
+	}
+	return nil
+}
+
+// 获取实例的地理位置，实例没有注册地理位置时，通过CMDB查询
+func (s *Server) getInstanceLocation(instance *model.Instance) *api.Location {
+	if location := instance.Location(); location != nil {
+		return location
+	}
+	if location := s.getLocation(instance.Host()); location != nil {
+		return location.Proto
+	}
+	return nil
+}
+
+/**
+ * @brief 服务的就近过滤缓存
+ * 实例的地理位置在服务实例的revision变化时重新查询，相同调用方位置的过滤结果直接复用
+ */
+type nearbyCache struct {
+	revision  string
+	expire    time.Time
+	instances []*model.Instance
+	locations []*api.Location
+
+	mutex  sync.Mutex
+	scopes map[nearbyScopeKey]*nearbyScope
+}
+
+// 就近范围的缓存key
+type nearbyScopeKey struct {
+	level      api.DiscoverRequest_NearbyLevel
+	region     string
+	zone       string
+	campus     string
+	minPercent uint32
+}
+
+// 就近过滤的结果，level为实际使用的范围
+type nearbyScope struct {
+	level     api.DiscoverRequest_NearbyLevel
+	instances []*model.Instance
+	revision  string
+}
+
+// 获取服务的就近缓存，服务实例的revision变化或者缓存过期时重建
+func (s *Server) getNearbyCache(service *model.Service, revision string) *nearbyCache {
+	now := time.Now()
+	if value, ok := s.nearbyCaches.Load(service.ID); ok {
+		entry := value.(*nearbyCache)
+		if entry.revision == revision && now.Before(entry.expire) {
+			return entry
+		}
+	}
+
+	instances := s.caches.Instance().GetInstancesByServiceID(service.ID)
+	entry := &nearbyCache{
+		revision:  revision,
+		expire:    now.Add(nearbyCacheTTL),
+		instances: instances,
+		locations: s.getInstanceLocations(instances),
+		scopes:    make(map[nearbyScopeKey]*nearbyScope),
+	}
+	s.nearbyCaches.Store(service.ID, entry)
+	s.cleanNearbyCaches(now)
+	return entry
+}
+
+// 定期删除过期的就近缓存，避免已经删除的服务一直占用内存
+func (s *Server) cleanNearbyCaches(now time.Time) {
+	last := atomic.LoadInt64(&s.nearbyCleanTime)
+	if now.UnixNano()-last < int64(nearbyCacheTTL) ||
+		!atomic.CompareAndSwapInt64(&s.nearbyCleanTime, last, now.UnixNano()) {
+		return
+	}
+	s.nearbyCaches.Range(func(key, value interface{}) bool {
+		if !now.Before(value.(*nearbyCache).expire) {
+			s.nearbyCaches.Delete(key)
+		}
+		return true
+	})
+}
+
+/**
+ * @brief 获取调用方位置对应的就近过滤结果
+ * revision为服务实例的revision，返回nil表示扩大到了全部实例
+ */
+func (s *Server) getNearbyScope(service *model.Service, revision string, level api.DiscoverRequest_NearbyLevel,
+	caller *api.Location) (*nearbyScope, error) {
+	if level == api.DiscoverRequest_ALL || isEmptyLocation(caller) {
+		return nil, nil
+	}
+
+	entry := s.getNearbyCache(service, revision)
+	key := nearbyScopeKey{
+		level:      level,
+		region:     caller.GetRegion().GetValue(),
+		zone:       caller.GetZone().GetValue(),
+		campus:     caller.GetCampus().GetValue(),
+		minPercent: s.nearbyConfig().MinHealthyPercent,
+	}
+	entry.mutex.Lock()
+	scope, ok := entry.scopes[key]
+	entry.mutex.Unlock()
+	if ok {
+		return scope, nil
+	}
+
+	scope = &nearbyScope{}
+	scope.level, scope.instances = filterNearbyInstances(level, caller, entry.instances, entry.locations,
+		key.minPercent)
+	if scope.level == api.DiscoverRequest_ALL {
+		scope = nil
+	} else {
+		var err error
+		if scope.revision, err = computeNearbyRevision(service, scope.level, caller, scope.instances); err != nil {
+			return nil, err
+		}
+	}
+
+	entry.mutex.Lock()
+	if len(entry.scopes) >= maxNearbyScopes {
+		entry.scopes = make(map[nearbyScopeKey]*nearbyScope)
+	}
+	entry.scopes[key] = scope
+	entry.mutex.Unlock()
+	return scope, nil
+}
+
+// 查询实例的地理位置，与实例一一对应
+func (s *Server) getInstanceLocations(instances []*model.Instance) []*api.Location {
+	locations := make([]*api.Location, len(instances))
+	for i, instance := range instances {
+		locations[i] = s.getInstanceLocation(instance)
+	}
+	return locations
+}
+
+/**
+ * @brief 按照调用方的地理位置就近过滤实例，locations为实例对应的地理位置
+ * 从请求的范围开始，健康实例的占比低于minPercent时逐级扩大范围，返回实际使用的范围以及过滤后的实例
+ */
+func filterNearbyInstances(level api.DiscoverRequest_NearbyLevel, caller *api.Location, instances []*model.Instance,
+	locations []*api.Location, minPercent uint32) (api.DiscoverRequest_NearbyLevel, []*model.Instance) {
+	if level == api.DiscoverRequest_ALL || isEmptyLocation(caller) {
+		return api.DiscoverRequest_ALL, instances
+	}
+
+	for _, current := range nearbyLevels {
+		if current < level {
+			continue
+		}
+		var matched []*model.Instance
+		var healthy uint32
+		for i, instance := range instances {
+			if !matchNearby(current, caller, locations[i]) {
+				continue
+			}
+			matched = append(matched, instance)
+			if isAvailableInstance(instance) {
+				healthy++
+			}
+		}
+		if healthy > 0 && healthy*100 >= minPercent*uint32(len(matched)) {
+			return current, matched
+		}
+	}
+	return api.DiscoverRequest_ALL, instances
+}
+
+// 计算就近范围的revision，只和范围内的实例以及调用方的位置有关
+func computeNearbyRevision(service *model.Service, level api.DiscoverRequest_NearbyLevel, caller *api.Location,
+	instances []*model.Instance) (string, error) {
+	seed := service.Revision + "|" + level.String() + "|" + caller.GetRegion().GetValue() + "|" +
+		caller.GetZone().GetValue() + "|" + caller.GetCampus().GetValue()
+	return cache.ComputeRevision(seed, instances)
+}
+
+// 判断实例是否与调用方在指定的范围内，调用方缺少该范围的位置信息时不匹配
+func matchNearby(level api.DiscoverRequest_NearbyLevel, caller *api.Location, target *api.Location) bool {
+	switch level {
+	case api.DiscoverRequest_CAMPUS:
+		if caller.GetCampus().GetValue() == "" || caller.GetCampus().GetValue() != target.GetCampus().GetValue() {
+			return false
+		}
+		fallthrough
+	case api.DiscoverRequest_ZONE:
+		if caller.GetZone().GetValue() == "" || caller.GetZone().GetValue() != target.GetZone().GetValue() {
+			return false
+		}
+		fallthrough
+	case api.DiscoverRequest_REGION:
+		if caller.GetRegion().GetValue() == "" || caller.GetRegion().GetValue() != target.GetRegion().GetValue() {
+			return false
+		}
+	}
+	return true
+}
+
+// 实例健康、未隔离并且权重大于0，才认为可以承接流量
+func isAvailableInstance(instance *model.Instance) bool {
+	return instance.Healthy() && !instance.Isolate() && instance.Weight() > 0
+}
+
+// 地理位置信息是否为空
+func isEmptyLocation(location *api.Location) bool {
+	return location.GetRegion().GetValue() == "" && location.GetZone().GetValue() == "" &&
+		location.GetCampus().GetValue() == ""
+}
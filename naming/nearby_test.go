@@ -0,0 +1,196 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+)
+
+func newNearbyInstance(id string, region, zone, campus string, healthy bool) *model.Instance {
+	return &model.Instance{Proto: &api.Instance{
+		Id:       utils.NewStringValue(id),
+		Host:     utils.NewStringValue("127.0.0.1"),
+		Weight:   utils.NewUInt32Value(100),
+		Healthy:  utils.NewBoolValue(healthy),
+		Revision: utils.NewStringValue(id),
+		Location: &api.Location{
+			Region: utils.NewStringValue(region),
+			Zone:   utils.NewStringValue(zone),
+			Campus: utils.NewStringValue(campus),
+		},
+	}}
+}
+
+func nearbyInstanceIDs(instances []*model.Instance) []string {
+	ids := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		ids = append(ids, instance.ID())
+	}
+	return ids
+}
+
+// TestFilterNearbyInstances 测试就近过滤以及健康实例不足时扩大范围
+func TestFilterNearbyInstances(t *testing.T) {
+	caller := &api.Location{
+		Region: utils.NewStringValue("south"),
+		Zone:   utils.NewStringValue("sz"),
+		Campus: utils.NewStringValue("sz-1"),
+	}
+	instances := []*model.Instance{
+		newNearbyInstance("a", "south", "sz", "sz-1", true),
+		newNearbyInstance("b", "south", "sz", "sz-1", false),
+		newNearbyInstance("c", "south", "sz", "sz-2", true),
+		newNearbyInstance("d", "south", "gz", "gz-1", true),
+		newNearbyInstance("e", "north", "bj", "bj-1", true),
+	}
+
+	s := &Server{}
+	locations := s.getInstanceLocations(instances)
+	tests := []struct {
+		level      api.DiscoverRequest_NearbyLevel
+		minPercent uint32
+		caller     *api.Location
+		expect     api.DiscoverRequest_NearbyLevel
+		count      int
+	}{
+		{api.DiscoverRequest_CAMPUS, 0, caller, api.DiscoverRequest_CAMPUS, 2},
+		{api.DiscoverRequest_CAMPUS, 50, caller, api.DiscoverRequest_CAMPUS, 2},
+		{api.DiscoverRequest_CAMPUS, 60, caller, api.DiscoverRequest_ZONE, 3},
+		{api.DiscoverRequest_ZONE, 0, caller, api.DiscoverRequest_ZONE, 3},
+		{api.DiscoverRequest_REGION, 0, caller, api.DiscoverRequest_REGION, 4},
+		{api.DiscoverRequest_REGION, 100, caller, api.DiscoverRequest_ALL, 5},
+		{api.DiscoverRequest_CAMPUS, 0, nil, api.DiscoverRequest_ALL, 5},
+		{api.DiscoverRequest_CAMPUS, 0, &api.Location{Region: utils.NewStringValue("north")},
+			api.DiscoverRequest_REGION, 1},
+	}
+	for i, item := range tests {
+		level, out := filterNearbyInstances(item.level, item.caller, instances, locations, item.minPercent)
+		if level != item.expect || len(out) != item.count {
+			t.Fatalf("case %d: expect %s(%d), got %s%v", i, item.expect, item.count, level, nearbyInstanceIDs(out))
+		}
+	}
+}
+
+// TestComputeNearbyRevision 测试不同范围的revision互不影响
+func TestComputeNearbyRevision(t *testing.T) {
+	service := &model.Service{ID: "svc", Revision: "rev"}
+	caller := &api.Location{Zone: utils.NewStringValue("sz"), Region: utils.NewStringValue("south")}
+	local := []*model.Instance{newNearbyInstance("a", "south", "sz", "sz-1", true)}
+
+	zone, err := computeNearbyRevision(service, api.DiscoverRequest_ZONE, caller, local)
+	if err != nil {
+		t.Fatalf("compute revision err: %s", err.Error())
+	}
+	region, _ := computeNearbyRevision(service, api.DiscoverRequest_REGION, caller, local)
+	if zone == region {
+		t.Fatalf("revision of different levels should be different")
+	}
+	// 范围外的实例变更，不影响范围内的revision
+	again, _ := computeNearbyRevision(service, api.DiscoverRequest_ZONE, caller,
+		[]*model.Instance{newNearbyInstance("a", "south", "sz", "sz-1", true)})
+	if again != zone {
+		t.Fatalf("revision should be stable, %s != %s", again, zone)
+	}
+}
+
+// TestGetNearbyScope 测试相同调用方位置的过滤结果复用，服务实例的revision变化后重建
+func TestGetNearbyScope(t *testing.T) {
+	service := &model.Service{ID: "svc", Revision: "rev"}
+	instances := []*model.Instance{
+		newNearbyInstance("a", "south", "sz", "sz-1", true),
+		newNearbyInstance("b", "south", "sz", "sz-2", true),
+		newNearbyInstance("c", "north", "bj", "bj-1", true),
+	}
+	s := &Server{}
+	s.nearbyCaches.Store(service.ID, &nearbyCache{
+		revision:  "r1",
+		expire:    time.Now().Add(nearbyCacheTTL),
+		instances: instances,
+		locations: s.getInstanceLocations(instances),
+		scopes:    make(map[nearbyScopeKey]*nearbyScope),
+	})
+	caller := &api.Location{
+		Region: utils.NewStringValue("south"),
+		Zone:   utils.NewStringValue("sz"),
+		Campus: utils.NewStringValue("sz-1"),
+	}
+
+	scope, err := s.getNearbyScope(service, "r1", api.DiscoverRequest_CAMPUS, caller)
+	if err != nil || scope == nil || scope.level != api.DiscoverRequest_CAMPUS || len(scope.instances) != 1 {
+		t.Fatalf("campus scope not match: %+v, err: %v", scope, err)
+	}
+	again, _ := s.getNearbyScope(service, "r1", api.DiscoverRequest_CAMPUS, &api.Location{
+		Region: utils.NewStringValue("south"),
+		Zone:   utils.NewStringValue("sz"),
+		Campus: utils.NewStringValue("sz-1"),
+	})
+	if again != scope {
+		t.Fatalf("scope of the same caller location should be reused")
+	}
+
+	zone, _ := s.getNearbyScope(service, "r1", api.DiscoverRequest_ZONE, caller)
+	if zone == nil || zone.level != api.DiscoverRequest_ZONE || len(zone.instances) != 2 || zone.revision == scope.revision {
+		t.Fatalf("zone scope not match: %+v", zone)
+	}
+
+	// 就近配置变化后重新过滤
+	s.setNearbyConfig(NearbyConfig{MinHealthyPercent: 50})
+	instances[0].Proto.Healthy = utils.NewBoolValue(false)
+	fallback, _ := s.getNearbyScope(service, "r1", api.DiscoverRequest_CAMPUS, caller)
+	if fallback == nil || fallback.level != api.DiscoverRequest_ZONE {
+		t.Fatalf("scope should be refiltered after config changed: %+v", fallback)
+	}
+
+	// 没有满足条件的范围时返回nil
+	all, err := s.getNearbyScope(service, "r1", api.DiscoverRequest_CAMPUS,
+		&api.Location{Region: utils.NewStringValue("west")})
+	if err != nil || all != nil {
+		t.Fatalf("scope should be nil, got %+v", all)
+	}
+	if scope, _ := s.getNearbyScope(service, "r1", api.DiscoverRequest_ALL, caller); scope != nil {
+		t.Fatalf("scope of all should be nil")
+	}
+}
+
+// TestCleanNearbyCaches 测试过期的就近缓存被删除
+func TestCleanNearbyCaches(t *testing.T) {
+	s := &Server{}
+	now := time.Now()
+	s.nearbyCaches.Store("expired", &nearbyCache{expire: now.Add(-time.Second)})
+	s.nearbyCaches.Store("valid", &nearbyCache{expire: now.Add(nearbyCacheTTL)})
+
+	s.cleanNearbyCaches(now)
+	if _, ok := s.nearbyCaches.Load("expired"); ok {
+		t.Fatalf("expired cache should be deleted")
+	}
+	if _, ok := s.nearbyCaches.Load("valid"); !ok {
+		t.Fatalf("valid cache should be kept")
+	}
+
+	// 清理间隔内不重复清理
+	s.nearbyCaches.Store("expired", &nearbyCache{expire: now.Add(-time.Second)})
+	s.cleanNearbyCaches(now.Add(time.Second))
+	if _, ok := s.nearbyCaches.Load("expired"); !ok {
+		t.Fatalf("caches should not be cleaned within interval")
+	}
+}
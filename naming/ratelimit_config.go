@@ -28,6 +28,7 @@ import (
 	"github.com/polarismesh/polaris-server/common/log"
 	"github.com/polarismesh/polaris-server/common/model"
 	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming/auth"
 )
 
 var (
@@ -248,7 +249,11 @@ func (s *Server) UpdateRateLimit(ctx context.Context, req *api.Rule) *api.Respon
 		rateLimit.ID, service.Namespace, service.Name, rateLimit.Labels)
 	log.Info(msg, ZapRequestID(requestID), ZapPlatformID(platformID))
 
-	s.RecordHistory(rateLimitRecordEntry(ctx, service.Namespace, service.Name, rateLimit, model.OUpdate))
+	entry := rateLimitRecordEntry(ctx, service.Namespace, service.Name, rateLimit, model.OUpdate)
+	if before, err := rateLimit2api(service.Name, service.Namespace, data); err == nil {
+		entry.Before = before
+	}
+	s.RecordHistory(entry)
 	return api.NewRateLimitResponse(api.ExecuteSuccess, req)
 }
 
@@ -425,6 +430,14 @@ func parseRateLimitReqToken(ctx context.Context, req *api.Rule) string {
  * @brief 限流鉴权
  */
 func (s *Server) verifyRateLimitAuth(ctx context.Context, service *model.Service, req *api.Rule) *api.Response {
+	// 带有登录用户时使用用户的角色鉴权
+	if found, ok := s.verifyAuthByUser(ctx, auth.ResourceRateLimit, service.Namespace, service.Name); found {
+		if !ok {
+			return api.NewRateLimitResponse(api.Unauthorized, req)
+		}
+		return nil
+	}
+
 	// 使用平台id及token鉴权
 	if ok := s.verifyAuthByPlatform(ctx, service.PlatformID); !ok {
 		// 检查token是否存在
@@ -555,6 +568,14 @@ func rateLimitRecordEntry(ctx context.Context, namespace string, service string,
 	if md != nil {
 		entry.Context = fmt.Sprintf("id:%s,label:%s,priority:%d,rule:%s,revision:%s",
 			md.ID, md.Labels, md.Priority, md.Rule, md.Revision)
+		entry.Revision = md.Revision
+		if rule, err := rateLimit2api(service, namespace, md); err == nil {
+			if opt == model.ODelete {
+				entry.Before = rule
+			} else {
+				entry.Object = rule
+			}
+		}
 	}
 	return entry
 }
@@ -0,0 +1,142 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/naming/batch"
+)
+
+const (
+	// 批量控制器热更新后，旧的控制器继续处理已经入队的请求，延迟停止
+	batchStopDelay = 10 * time.Second
+)
+
+/**
+ * ReloadConfig 热更新核心逻辑层配置
+ * running为正在使用的配置，已经生效的配置项会同步修改到running中
+ * 返回已经生效的配置项，以及需要重启才能生效的配置项
+ */
+func (s *Server) ReloadConfig(ctx context.Context, running *Config, newConf *Config) ([]string, []string, error) {
+	var applied, restart []string
+
+	// 先检查配置是否合法，避免只生效了一部分
+	var batchConfig *batch.Config
+	batchChanged := !reflect.DeepEqual(running.Batch, newConf.Batch)
+	if batchChanged {
+		var err error
+		if batchConfig, err = batch.ParseBatchConfig(newConf.Batch); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if !reflect.DeepEqual(running.Auth, newConf.Auth) {
+		s.authority.UpdateOptions(newConf.Auth)
+		auth := make(map[string]interface{}, len(newConf.Auth))
+		for key, value := range newConf.Auth {
+			auth[key] = value
+		}
+		// 用户数据的定时加载协程在启动时创建，修改加载间隔需要重启
+		if !reflect.DeepEqual(running.Auth["reload-interval"], newConf.Auth["reload-interval"]) {
+			restart = append(restart, "naming.auth.reload-interval")
+			auth["reload-interval"] = running.Auth["reload-interval"]
+		}
+		running.Auth = auth
+		applied = append(applied, "naming.auth")
+	}
+
+	if batchChanged {
+		if err := s.reloadBatch(ctx, batchConfig); err != nil {
+			return applied, restart, err
+		}
+		running.Batch = newConf.Batch
+		applied = append(applied, "naming.batch")
+	}
+
+	applied, restart = s.reloadHealthCheck(running, newConf, applied, restart)
+
+	if running.Watch != newConf.Watch {
+		s.watchHub.updateConfig(&newConf.Watch)
+		running.Watch = newConf.Watch
+		applied = append(applied, "naming.watch")
+	}
+
+	if running.Nearby != newConf.Nearby {
+		s.setNearbyConfig(newConf.Nearby)
+		running.Nearby = newConf.Nearby
+		applied = append(applied, "naming.nearby")
+	}
+
+	return applied, restart, nil
+}
+
+// 重建批量控制器，旧的控制器处理完已经入队的请求后停止
+func (s *Server) reloadBatch(ctx context.Context, config *batch.Config) error {
+	bc, err := batch.NewBatchCtrlWithConfig(s.storage, s.authority, s.auth, config)
+	if err != nil {
+		log.Errorf("[Server][Reload] new batch ctrl with config err: %s", err.Error())
+		return err
+	}
+	if bc != nil {
+		bc.Start(ctx)
+	}
+
+	s.bcMutex.Lock()
+	old := s.bc
+	s.bc = bc
+	s.bcMutex.Unlock()
+
+	if old != nil {
+		time.AfterFunc(batchStopDelay, old.Stop)
+	}
+	log.Infof("[Server][Reload] batch ctrl is reloaded, config: %+v", config)
+	return nil
+}
+
+// 主动探测的并发数以及失败阈值支持热更新，其他的健康检查配置需要重启
+func (s *Server) reloadHealthCheck(running *Config, newConf *Config, applied []string, restart []string) (
+	[]string, []string) {
+	oldConf := running.HealthCheck
+	conf := newConf.HealthCheck
+	// localHost、默认的localPeer以及mode由启动流程填充
+	conf.LocalHost = oldConf.LocalHost
+	if conf.LocalPeer == "" {
+		conf.LocalPeer = oldConf.LocalPeer
+	}
+	if conf.Mode == "" {
+		conf.Mode = oldConf.Mode
+	}
+
+	probeChanged := oldConf.ProbeWorkers != conf.ProbeWorkers ||
+		oldConf.ProbeFailThreshold != conf.ProbeFailThreshold
+	if probeChanged && s.prober != nil {
+		s.prober.UpdateConfig(&conf)
+		running.HealthCheck.ProbeWorkers = conf.ProbeWorkers
+		running.HealthCheck.ProbeFailThreshold = conf.ProbeFailThreshold
+		applied = append(applied, "naming.healthcheck.probe")
+	}
+
+	if conf != running.HealthCheck {
+		restart = append(restart, "naming.healthcheck")
+	}
+	return applied, restart
+}
@@ -0,0 +1,43 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"testing"
+)
+
+// TestServer_ReloadHealthCheck 测试启动流程填充的配置不会导致健康检查需要重启
+func TestServer_ReloadHealthCheck(t *testing.T) {
+	running := &Config{HealthCheck: HealthCheckConfig{
+		Open:      true,
+		Mode:      HealthCheckMemory,
+		LocalHost: "10.0.0.1",
+		LocalPeer: "10.0.0.1:8090",
+	}}
+	s := &Server{}
+
+	_, restart := s.reloadHealthCheck(running, &Config{HealthCheck: HealthCheckConfig{Open: true}}, nil, nil)
+	if len(restart) != 0 {
+		t.Fatalf("unchanged health check should not restart: %v", restart)
+	}
+
+	newConf := &Config{HealthCheck: HealthCheckConfig{Open: true, LocalPeer: "10.0.0.1:8091"}}
+	if _, restart := s.reloadHealthCheck(running, newConf, nil, nil); len(restart) != 1 {
+		t.Fatalf("changed local peer should restart: %v", restart)
+	}
+}
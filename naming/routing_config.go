@@ -27,6 +27,7 @@ import (
 	"github.com/polarismesh/polaris-server/common/log"
 	"github.com/polarismesh/polaris-server/common/model"
 	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming/auth"
 )
 
 var (
@@ -143,7 +144,13 @@ func (s *Server) DeleteRoutingConfig(ctx context.Context, req *api.Routing) *api
 		return wrapperRoutingStoreResponse(req, err)
 	}
 
-	s.RecordHistory(routingRecordEntry(ctx, req, nil, model.ODelete))
+	entry := routingRecordEntry(ctx, req, nil, model.ODelete)
+	if conf := s.caches.RoutingConfig().GetRoutingConfig(service.ID); conf != nil {
+		if before, err := routingConfig2API(conf, service.Name, service.Namespace); err == nil {
+			entry.Before = before
+		}
+	}
+	s.RecordHistory(entry)
 	return api.NewRoutingResponse(api.ExecuteSuccess, req)
 }
 
@@ -193,7 +200,11 @@ func (s *Server) UpdateRoutingConfig(ctx context.Context, req *api.Routing) *api
 		return wrapperRoutingStoreResponse(req, err)
 	}
 
-	s.RecordHistory(routingRecordEntry(ctx, req, reqModel, model.OUpdate))
+	entry := routingRecordEntry(ctx, req, reqModel, model.OUpdate)
+	if before, err := routingConfig2API(conf, service.Name, service.Namespace); err == nil {
+		entry.Before = before
+	}
+	s.RecordHistory(entry)
 	return api.NewRoutingResponse(api.ExecuteSuccess, req)
 }
 
@@ -404,6 +415,10 @@ func routingRecordEntry(ctx context.Context, req *api.Routing, md *model.Routing
 	if md != nil {
 		entry.Context = fmt.Sprintf("inBounds:%s,outBounds:%s,revision:%s",
 			md.InBounds, md.OutBounds, md.Revision)
+		entry.Revision = md.Revision
+		if routing, err := routingConfig2API(md, entry.Service, entry.Namespace); err == nil {
+			entry.Object = routing
+		}
 	}
 	return entry
 }
@@ -424,6 +439,14 @@ func wrapperRoutingStoreResponse(routing *api.Routing, err error) *api.Response
  * @brief 路由鉴权
  */
 func (s *Server) verifyRoutingAuth(ctx context.Context, service *model.Service, req *api.Routing) *api.Response {
+	// 带有登录用户时使用用户的角色鉴权
+	if found, ok := s.verifyAuthByUser(ctx, auth.ResourceRouting, service.Namespace, service.Name); found {
+		if !ok {
+			return api.NewRoutingResponse(api.Unauthorized, req)
+		}
+		return nil
+	}
+
 	// 使用平台id及token鉴权
 	if ok := s.verifyAuthByPlatform(ctx, service.PlatformID); !ok {
 		// 检查token是否存在
@@ -0,0 +1,674 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+)
+
+const (
+	// 路由目标的优先级范围为[0, 9]，0为最高优先级
+	maxRoutingPriority = 9
+	// 百分比类型阈值的上限
+	maxPercent = 100
+	// 通配所有的服务或者命名空间
+	matchAll = "*"
+)
+
+// 基础参数检查的错误码与字段的对应关系
+var validateCode2Field = map[uint32]string{
+	api.InvalidServiceName:              "service",
+	api.InvalidNamespaceName:            "namespace",
+	api.InvalidServiceToken:             "service_token",
+	api.InvalidRateLimitLabels:          "labels",
+	api.InvalidRateLimitAmounts:         "amounts",
+	api.InvalidCircuitBreakerName:       "name",
+	api.InvalidCircuitBreakerNamespace:  "namespace",
+	api.InvalidCircuitBreakerOwners:     "owners",
+	api.InvalidCircuitBreakerBusiness:   "business",
+	api.InvalidCircuitBreakerDepartment: "department",
+	api.InvalidCircuitBreakerComment:    "comment",
+}
+
+// 规则校验器，收集字段级的错误，校验过程不会修改任何数据
+type ruleValidator struct {
+	errors []*api.ValidationError
+}
+
+// 记录一个字段错误
+func (v *ruleValidator) addf(field string, format string, args ...interface{}) {
+	v.errors = append(v.errors, &api.ValidationError{
+		Field:   utils.NewStringValue(field),
+		Message: utils.NewStringValue(fmt.Sprintf(format, args...)),
+	})
+}
+
+// 把基础参数检查返回的错误码转换为字段错误
+func (v *ruleValidator) addResponse(resp *api.Response) {
+	v.addf(validateCode2Field[resp.GetCode().GetValue()], "%s", resp.GetInfo().GetValue())
+}
+
+// 校验结果对应的错误码
+func (v *ruleValidator) code() uint32 {
+	if len(v.errors) == 0 {
+		return api.ExecuteSuccess
+	}
+	return api.InvalidRuleValidation
+}
+
+// 把收集到的字段错误填充到回复中
+func (v *ruleValidator) fill(resp *api.Response) *api.Response {
+	resp.ValidationErrors = v.errors
+	return resp
+}
+
+// ValidateRoutingConfigs 批量校验路由配置，只做检查不会落库
+func (s *Server) ValidateRoutingConfigs(ctx context.Context, req []*api.Routing) *api.BatchWriteResponse {
+	if err := checkBatchRoutingConfig(req); err != nil {
+		return err
+	}
+
+	resp := api.NewBatchWriteResponse(api.ExecuteSuccess)
+	for _, entry := range req {
+		resp.Collect(s.ValidateRoutingConfig(ctx, entry))
+	}
+	return api.FormatBatchWriteResponse(resp)
+}
+
+// ValidateRoutingConfig 校验路由配置
+// 除了写入时的基础检查外，还会检查正则表达式、目标服务、优先级与权重，以及路由规则之间的覆盖
+func (s *Server) ValidateRoutingConfig(ctx context.Context, req *api.Routing) *api.Response {
+	if req == nil {
+		return api.NewRoutingResponse(api.EmptyRequest, req)
+	}
+	v := &ruleValidator{}
+	if resp := checkRoutingConfig(req); resp != nil {
+		v.addResponse(resp)
+		return v.fill(api.NewRoutingResponse(v.code(), req))
+	}
+
+	if err := s.validateRuleService(v, req.GetService().GetValue(), req.GetNamespace().GetValue()); err != nil {
+		log.Error(err.Error(), ZapRequestID(ParseRequestID(ctx)))
+		return api.NewRoutingResponse(api.StoreLayerException, req)
+	}
+	if err := s.validateRoutes(v, "inbounds", req.GetInbounds()); err != nil {
+		log.Error(err.Error(), ZapRequestID(ParseRequestID(ctx)))
+		return api.NewRoutingResponse(api.StoreLayerException, req)
+	}
+	if err := s.validateRoutes(v, "outbounds", req.GetOutbounds()); err != nil {
+		log.Error(err.Error(), ZapRequestID(ParseRequestID(ctx)))
+		return api.NewRoutingResponse(api.StoreLayerException, req)
+	}
+	return v.fill(api.NewRoutingResponse(v.code(), req))
+}
+
+// 检查路由规则，规则从上到下匹配，命中即终止，因此被前面规则完全覆盖的规则永远不会生效
+func (s *Server) validateRoutes(v *ruleValidator, field string, routes []*api.Route) error {
+	matchers := make([][]*ruleMatcher, 0, len(routes))
+	for i, route := range routes {
+		path := fmt.Sprintf("%s[%d]", field, i)
+		current := make([]*ruleMatcher, 0, len(route.GetSources()))
+		for j, source := range route.GetSources() {
+			validateMatchStrings(v, fmt.Sprintf("%s.sources[%d].metadata", path, j), source.GetMetadata())
+			current = append(current, &ruleMatcher{
+				service:   source.GetService().GetValue(),
+				namespace: source.GetNamespace().GetValue(),
+				labels:    source.GetMetadata(),
+			})
+		}
+		if len(current) == 0 {
+			current = append(current, &ruleMatcher{})
+		}
+		if err := s.validateRouteDestinations(v, path, route.GetDestinations()); err != nil {
+			return err
+		}
+
+		for j, prev := range matchers {
+			if matchersCover(prev, current) {
+				v.addf(path+".sources", "shadowed by %s[%d]", field, j)
+				break
+			}
+		}
+		matchers = append(matchers, current)
+	}
+	return nil
+}
+
+// 检查路由目标
+func (s *Server) validateRouteDestinations(v *ruleValidator, path string, destinations []*api.Destination) error {
+	if len(destinations) == 0 {
+		v.addf(path+".destinations", "at least one destination is required")
+		return nil
+	}
+
+	keys := make(map[string]int, len(destinations))
+	for i, destination := range destinations {
+		destPath := fmt.Sprintf("%s.destinations[%d]", path, i)
+		validateMatchStrings(v, destPath+".metadata", destination.GetMetadata())
+		err := s.validateReferredService(v, destPath+".service", destination.GetService().GetValue(),
+			destination.GetNamespace().GetValue())
+		if err != nil {
+			return err
+		}
+		if priority := destination.GetPriority(); priority != nil && priority.GetValue() > maxRoutingPriority {
+			v.addf(destPath+".priority", "priority must be in [0, %d]", maxRoutingPriority)
+		}
+
+		key := fmt.Sprintf("%s/%s/%s", destination.GetNamespace().GetValue(), destination.GetService().GetValue(),
+			matchStringsKey(destination.GetMetadata()))
+		if prev, ok := keys[key]; ok {
+			v.addf(destPath, "overlaps with destinations[%d]", prev)
+			continue
+		}
+		keys[key] = i
+	}
+	validateRouteWeights(v, path, destinations)
+	return nil
+}
+
+// 检查路由目标的权重
+// 部分设置了权重时，没有设置的权重为0
+// 同一优先级内没有被隔离的目标权重之和不能为0，否则该优先级不会分配到流量
+func validateRouteWeights(v *ruleValidator, path string, destinations []*api.Destination) {
+	weighted := false
+	available := false
+	weights := make(map[string]uint64)
+	for _, destination := range destinations {
+		if destination.GetWeight() != nil {
+			weighted = true
+		}
+		if destination.GetIsolate().GetValue() {
+			continue
+		}
+		available = true
+		// 没有设置优先级的目标优先级最低
+		priority := "unset"
+		if destination.GetPriority() != nil {
+			priority = fmt.Sprintf("%d", destination.GetPriority().GetValue())
+		}
+		weights[priority] += uint64(destination.GetWeight().GetValue())
+	}
+	if !available {
+		v.addf(path+".destinations", "all destinations are isolated")
+		return
+	}
+	if !weighted {
+		return
+	}
+
+	priorities := make([]string, 0, len(weights))
+	for priority := range weights {
+		priorities = append(priorities, priority)
+	}
+	sort.Strings(priorities)
+	for _, priority := range priorities {
+		if weights[priority] == 0 {
+			v.addf(path+".destinations", "total weight of destinations with priority %s is 0", priority)
+		}
+	}
+}
+
+// ValidateRateLimits 批量校验限流规则，只做检查不会落库
+func (s *Server) ValidateRateLimits(ctx context.Context, req []*api.Rule) *api.BatchWriteResponse {
+	if err := checkBatchRateLimits(req); err != nil {
+		return err
+	}
+
+	resp := api.NewBatchWriteResponse(api.ExecuteSuccess)
+	for i, entry := range req {
+		resp.Collect(s.validateRateLimit(ctx, entry, req[:i]))
+	}
+	return api.FormatBatchWriteResponse(resp)
+}
+
+// ValidateRateLimit 校验限流规则
+// 除了写入时的基础检查外，还会检查正则表达式、配额，以及与同一服务下其他规则的重叠和覆盖
+func (s *Server) ValidateRateLimit(ctx context.Context, req *api.Rule) *api.Response {
+	return s.validateRateLimit(ctx, req, nil)
+}
+
+// 校验限流规则，peers为同一批次中排在前面的规则，同样参与重叠和覆盖的检查
+func (s *Server) validateRateLimit(ctx context.Context, req *api.Rule, peers []*api.Rule) *api.Response {
+	if req == nil {
+		return api.NewRateLimitResponse(api.EmptyRequest, req)
+	}
+	requestID := ParseRequestID(ctx)
+	v := &ruleValidator{}
+	if resp := checkRateLimitParams(req); resp != nil {
+		v.addResponse(resp)
+		return v.fill(api.NewRateLimitResponse(v.code(), req))
+	}
+	if resp := checkRateLimitRuleParams(requestID, req); resp != nil {
+		v.addResponse(resp)
+	}
+	validateMatchStrings(v, "labels", req.GetLabels())
+	validateMatchStrings(v, "subset", req.GetSubset())
+	validateMatchString(v, "method", req.GetMethod())
+	validateRateLimitAmounts(v, req.GetAmounts())
+
+	service, err := s.storage.GetService(req.GetService().GetValue(), req.GetNamespace().GetValue())
+	if err != nil {
+		log.Error(err.Error(), ZapRequestID(requestID))
+		return api.NewRateLimitResponse(api.StoreLayerException, req)
+	}
+	if service == nil {
+		v.addf("service", "service(%s/%s) not found", req.GetNamespace().GetValue(), req.GetService().GetValue())
+	} else if service.IsAlias() {
+		v.addf("service", "rate limit rule can not be bound to service alias")
+	}
+	if cluster := req.GetCluster(); cluster.GetService().GetValue() != "" {
+		err := s.validateReferredService(v, "cluster.service", cluster.GetService().GetValue(),
+			cluster.GetNamespace().GetValue())
+		if err != nil {
+			log.Error(err.Error(), ZapRequestID(requestID))
+			return api.NewRateLimitResponse(api.StoreLayerException, req)
+		}
+	}
+	s.validateRateLimitOverlap(v, service, req, peers)
+	return v.fill(api.NewRateLimitResponse(v.code(), req))
+}
+
+// 检查限流配额
+func validateRateLimitAmounts(v *ruleValidator, amounts []*api.Amount) {
+	if len(amounts) == 0 {
+		v.addf("amounts", "at least one amount is required")
+		return
+	}
+	for i, amount := range amounts {
+		path := fmt.Sprintf("amounts[%d]", i)
+		maxAmount := amount.GetMaxAmount().GetValue()
+		if duration, err := ptypes.Duration(amount.GetValidDuration()); err != nil || duration < time.Second {
+			v.addf(path+".validDuration", "validDuration must be at least 1s")
+		}
+		if amount.GetStartAmount().GetValue() > maxAmount {
+			v.addf(path+".startAmount", "startAmount must not be greater than maxAmount")
+		}
+		if amount.GetMinAmount().GetValue() > maxAmount {
+			v.addf(path+".minAmount", "minAmount must not be greater than maxAmount")
+		}
+	}
+}
+
+// 带名字的限流规则，名字用于在错误信息中指明是哪一条规则
+type namedRateLimit struct {
+	name string
+	rule *api.Rule
+}
+
+// 检查限流规则是否与同一服务下其他规则重叠或者被其覆盖，优先级数值越小越先匹配
+func (s *Server) validateRateLimitOverlap(v *ruleValidator, service *model.Service, req *api.Rule,
+	peers []*api.Rule) {
+	if req.GetDisable().GetValue() {
+		return
+	}
+
+	var others []namedRateLimit
+	if service != nil && s.caches != nil {
+		for _, rateLimit := range s.caches.RateLimit().GetRateLimitByServiceID(service.ID) {
+			if !rateLimit.Valid || rateLimit.ID == req.GetId().GetValue() {
+				continue
+			}
+			rule, err := rateLimit2api(service.Name, service.Namespace, rateLimit)
+			if err != nil || rule == nil {
+				continue
+			}
+			others = append(others, namedRateLimit{name: fmt.Sprintf("rule(%s)", rateLimit.ID), rule: rule})
+		}
+	}
+	for i, peer := range peers {
+		if peer.GetService().GetValue() == req.GetService().GetValue() &&
+			peer.GetNamespace().GetValue() == req.GetNamespace().GetValue() {
+			others = append(others, namedRateLimit{name: fmt.Sprintf("rules[%d]", i), rule: peer})
+		}
+	}
+
+	matcher := rateLimitMatcher(req)
+	priority := req.GetPriority().GetValue()
+	for _, other := range others {
+		rule := other.rule
+		if rule.GetDisable().GetValue() || rule.GetResource() != req.GetResource() ||
+			rule.GetPriority().GetValue() > priority {
+			continue
+		}
+		if !rateLimitMatcher(rule).covers(matcher) {
+			continue
+		}
+		if rule.GetPriority().GetValue() == priority {
+			v.addf("labels", "overlaps with %s of the same priority", other.name)
+			continue
+		}
+		v.addf("priority", "shadowed by %s of higher priority", other.name)
+	}
+}
+
+// 限流规则的匹配条件，包括业务标签、SUBSET以及接口
+func rateLimitMatcher(rule *api.Rule) *ruleMatcher {
+	labels := make(map[string]*api.MatchString, len(rule.GetLabels())+len(rule.GetSubset())+1)
+	for key, match := range rule.GetLabels() {
+		labels["labels."+key] = match
+	}
+	for key, match := range rule.GetSubset() {
+		labels["subset."+key] = match
+	}
+	if rule.GetMethod().GetValue().GetValue() != "" {
+		labels["method"] = rule.GetMethod()
+	}
+	return &ruleMatcher{labels: labels}
+}
+
+// ValidateCircuitBreakers 批量校验熔断规则，只做检查不会落库
+func (s *Server) ValidateCircuitBreakers(ctx context.Context, req []*api.CircuitBreaker) *api.BatchWriteResponse {
+	if err := checkBatchCircuitBreakers(req); err != nil {
+		return err
+	}
+
+	resp := api.NewBatchWriteResponse(api.ExecuteSuccess)
+	for _, entry := range req {
+		resp.Collect(s.ValidateCircuitBreaker(ctx, entry))
+	}
+	return api.FormatBatchWriteResponse(resp)
+}
+
+// ValidateCircuitBreaker 校验熔断规则
+// 除了写入时的基础检查外，还会检查正则表达式、目标服务、熔断阈值，以及熔断规则之间的重叠和覆盖
+func (s *Server) ValidateCircuitBreaker(ctx context.Context, req *api.CircuitBreaker) *api.Response {
+	if req == nil {
+		return api.NewCircuitBreakerResponse(api.EmptyRequest, req)
+	}
+	v := &ruleValidator{}
+	if _, resp := checkCreateCircuitBreaker(req); resp != nil {
+		v.addResponse(resp)
+		return v.fill(api.NewCircuitBreakerResponse(v.code(), req))
+	}
+
+	err := s.validateReferredServiceIfSet(v, "service", req.GetService().GetValue(),
+		req.GetServiceNamespace().GetValue())
+	if err == nil {
+		err = s.validateCbRules(v, "inbounds", req.GetInbounds())
+	}
+	if err == nil {
+		err = s.validateCbRules(v, "outbounds", req.GetOutbounds())
+	}
+	if err != nil {
+		log.Error(err.Error(), ZapRequestID(ParseRequestID(ctx)))
+		return api.NewCircuitBreakerResponse(api.StoreLayerException, req)
+	}
+	return v.fill(api.NewCircuitBreakerResponse(v.code(), req))
+}
+
+// 检查熔断规则，多条规则按顺序匹配，被前面规则完全覆盖的规则永远不会生效
+func (s *Server) validateCbRules(v *ruleValidator, field string, rules []*api.CbRule) error {
+	matchers := make([][]*ruleMatcher, 0, len(rules))
+	for i, rule := range rules {
+		path := fmt.Sprintf("%s[%d]", field, i)
+		current := make([]*ruleMatcher, 0, len(rule.GetSources()))
+		for j, source := range rule.GetSources() {
+			validateMatchStrings(v, fmt.Sprintf("%s.sources[%d].labels", path, j), source.GetLabels())
+			current = append(current, &ruleMatcher{
+				service:   source.GetService().GetValue(),
+				namespace: source.GetNamespace().GetValue(),
+				labels:    source.GetLabels(),
+			})
+		}
+		if len(current) == 0 {
+			current = append(current, &ruleMatcher{})
+		}
+
+		keys := make(map[string]int, len(rule.GetDestinations()))
+		for k, destination := range rule.GetDestinations() {
+			destPath := fmt.Sprintf("%s.destinations[%d]", path, k)
+			validateMatchStrings(v, destPath+".metadata", destination.GetMetadata())
+			validateMatchString(v, destPath+".method", destination.GetMethod())
+			err := s.validateReferredServiceIfSet(v, destPath+".service", destination.GetService().GetValue(),
+				destination.GetNamespace().GetValue())
+			if err != nil {
+				return err
+			}
+			validateCbPolicy(v, destPath+".policy", destination.GetPolicy())
+			validateCbRecover(v, destPath+".recover", destination.GetRecover())
+
+			key := fmt.Sprintf("%s/%s/%s/%s", destination.GetNamespace().GetValue(),
+				destination.GetService().GetValue(), matchStringsKey(destination.GetMetadata()),
+				matchStringKey(destination.GetMethod()))
+			if prev, ok := keys[key]; ok {
+				v.addf(destPath, "overlaps with destinations[%d]", prev)
+				continue
+			}
+			keys[key] = k
+		}
+
+		for j, prev := range matchers {
+			if matchersCover(prev, current) {
+				v.addf(path+".sources", "shadowed by %s[%d]", field, j)
+				break
+			}
+		}
+		matchers = append(matchers, current)
+	}
+	return nil
+}
+
+// 检查熔断策略的阈值
+func validateCbPolicy(v *ruleValidator, path string, policy *api.CbPolicy) {
+	if policy == nil {
+		return
+	}
+	if errorRate := policy.GetErrorRate(); errorRate.GetEnable().GetValue() {
+		validateThresholds(v, path+".errorRate", "errorRate", errorRate.GetErrorRateToPreserved().GetValue(),
+			errorRate.GetErrorRateToOpen().GetValue(), maxPercent)
+		for i, special := range errorRate.GetSpecials() {
+			validateThresholds(v, fmt.Sprintf("%s.errorRate.specials[%d]", path, i), "errorRate",
+				special.GetErrorRateToPreserved().GetValue(), special.GetErrorRateToOpen().GetValue(), maxPercent)
+		}
+	}
+	if slowRate := policy.GetSlowRate(); slowRate.GetEnable().GetValue() {
+		validateThresholds(v, path+".slowRate", "slowRate", slowRate.GetSlowRateToPreserved().GetValue(),
+			slowRate.GetSlowRateToOpen().GetValue(), maxPercent)
+		if maxRt, err := ptypes.Duration(slowRate.GetMaxRt()); err != nil || maxRt <= 0 {
+			v.addf(path+".slowRate.maxRt", "maxRt must be greater than 0")
+		}
+	}
+	if consecutive := policy.GetConsecutive(); consecutive.GetEnable().GetValue() {
+		validateThresholds(v, path+".consecutive", "consecutiveError",
+			consecutive.GetConsecutiveErrorToPreserved().GetValue(),
+			consecutive.GetConsecutiveErrorToOpen().GetValue(), 0)
+	}
+	if policy.GetMaxEjectionPercent().GetValue() > maxPercent {
+		v.addf(path+".maxEjectionPercent", "maxEjectionPercent must not be greater than %d", maxPercent)
+	}
+}
+
+// 检查进入保持状态以及熔断状态的阈值，熔断阈值必须大于0且不小于保持阈值，limit为0时不检查上限
+func validateThresholds(v *ruleValidator, path string, name string, preserved uint32, open uint32, limit uint32) {
+	preservedField, openField := name+"ToPreserved", name+"ToOpen"
+	if open == 0 {
+		v.addf(path+"."+openField, "%s must be greater than 0", openField)
+	}
+	if limit > 0 && open > limit {
+		v.addf(path+"."+openField, "%s must not be greater than %d", openField, limit)
+	}
+	if preserved > open {
+		v.addf(path+"."+preservedField, "%s must not be greater than %s", preservedField, openField)
+	}
+}
+
+// 检查熔断恢复配置的百分比
+func validateCbRecover(v *ruleValidator, path string, recover *api.RecoverConfig) {
+	if recover == nil {
+		return
+	}
+	if recover.GetSuccessRateToClose().GetValue() > maxPercent {
+		v.addf(path+".successRateToClose", "successRateToClose must not be greater than %d", maxPercent)
+	}
+	for i, rate := range recover.GetRequestRateAfterHalfOpen() {
+		if rate.GetValue() > maxPercent {
+			v.addf(fmt.Sprintf("%s.requestRateAfterHalfOpen[%d]", path, i),
+				"requestRateAfterHalfOpen must not be greater than %d", maxPercent)
+		}
+	}
+}
+
+// 检查规则所属的服务是否存在，规则不能绑定到服务别名
+func (s *Server) validateRuleService(v *ruleValidator, name string, namespace string) error {
+	service, err := s.storage.GetService(name, namespace)
+	if err != nil {
+		return err
+	}
+	if service == nil {
+		v.addf("service", "service(%s/%s) not found", namespace, name)
+	} else if service.IsAlias() {
+		v.addf("service", "rule can not be bound to service alias")
+	}
+	return nil
+}
+
+// 检查规则引用的服务是否存在，服务名或者命名空间为*时不检查
+func (s *Server) validateReferredService(v *ruleValidator, field string, name string, namespace string) error {
+	if name == matchAll || namespace == matchAll {
+		return nil
+	}
+	if name == "" || namespace == "" {
+		v.addf(field, "service and namespace are required")
+		return nil
+	}
+	service, err := s.storage.GetService(name, namespace)
+	if err != nil {
+		return err
+	}
+	if service == nil {
+		v.addf(field, "service(%s/%s) not found", namespace, name)
+	}
+	return nil
+}
+
+// 服务名不为空时，检查规则引用的服务是否存在
+func (s *Server) validateReferredServiceIfSet(v *ruleValidator, field string, name string, namespace string) error {
+	if name == "" {
+		return nil
+	}
+	return s.validateReferredService(v, field, name, namespace)
+}
+
+// 检查MatchString中的正则表达式能否编译
+func validateMatchStrings(v *ruleValidator, field string, matches map[string]*api.MatchString) {
+	keys := make([]string, 0, len(matches))
+	for key := range matches {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		validateMatchString(v, field+"."+key, matches[key])
+	}
+}
+
+// 检查单个MatchString，只有文本类型的正则表达式需要编译
+func validateMatchString(v *ruleValidator, field string, match *api.MatchString) {
+	if match.GetType() != api.MatchString_REGEX || match.GetValueType() != api.MatchString_TEXT {
+		return
+	}
+	if _, err := regexp.Compile(match.GetValue().GetValue()); err != nil {
+		v.addf(field, "invalid regex: %s", err.Error())
+	}
+}
+
+// MatchString集合的唯一标识，用于判断两组匹配条件是否完全相同
+func matchStringsKey(matches map[string]*api.MatchString) string {
+	parts := make([]string, 0, len(matches))
+	for key, match := range matches {
+		parts = append(parts, key+"="+matchStringKey(match))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// MatchString的唯一标识
+func matchStringKey(match *api.MatchString) string {
+	if match == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d:%s", match.GetType(), match.GetValueType(), match.GetValue().GetValue())
+}
+
+// 规则的匹配条件，用于判断规则之间的覆盖关系
+// 服务名以及命名空间为空或者*时匹配全部，labels中的条件需要全部满足
+type ruleMatcher struct {
+	service   string
+	namespace string
+	labels    map[string]*api.MatchString
+}
+
+// 判断是否覆盖了other，即命中other的请求一定会命中当前的匹配条件
+func (m *ruleMatcher) covers(other *ruleMatcher) bool {
+	if !nameCovers(m.service, other.service) || !nameCovers(m.namespace, other.namespace) {
+		return false
+	}
+	for key, match := range m.labels {
+		otherMatch, ok := other.labels[key]
+		if !ok || !matchStringCovers(match, otherMatch) {
+			return false
+		}
+	}
+	return true
+}
+
+// 多个匹配条件之间为或的关系，判断matchers是否覆盖了others中的每一个匹配条件
+func matchersCover(matchers []*ruleMatcher, others []*ruleMatcher) bool {
+	for _, other := range others {
+		covered := false
+		for _, matcher := range matchers {
+			if matcher.covers(other) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// 判断服务名或者命名空间是否覆盖
+func nameCovers(name string, other string) bool {
+	return name == "" || name == matchAll || name == other
+}
+
+// 判断MatchString是否覆盖，正则表达式只有在能匹配对方的精确值或者与对方完全相同时才认为覆盖
+func matchStringCovers(match *api.MatchString, other *api.MatchString) bool {
+	if match.GetValueType() != other.GetValueType() {
+		return false
+	}
+	value, otherValue := match.GetValue().GetValue(), other.GetValue().GetValue()
+	if match.GetType() == api.MatchString_EXACT || match.GetValueType() != api.MatchString_TEXT ||
+		other.GetType() == api.MatchString_REGEX {
+		return match.GetType() == other.GetType() && value == otherValue
+	}
+	re, err := regexp.Compile(value)
+	return err == nil && re.MatchString(otherValue)
+}
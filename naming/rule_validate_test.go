@@ -0,0 +1,263 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes/duration"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/store/mock"
+)
+
+func newValidateServer(t *testing.T) (*Server, *gomock.Controller) {
+	ctl := gomock.NewController(t)
+	storage := mock.NewMockStore(ctl)
+	storage.EXPECT().GetService(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(name string, namespace string) (*model.Service, error) {
+			if name == "svc" || name == "callee" {
+				return &model.Service{ID: name, Name: name, Namespace: namespace}, nil
+			}
+			return nil, nil
+		}).AnyTimes()
+	return &Server{storage: storage}, ctl
+}
+
+func exactMatch(value string) *api.MatchString {
+	return &api.MatchString{Type: api.MatchString_EXACT, Value: utils.NewStringValue(value)}
+}
+
+func regexMatch(value string) *api.MatchString {
+	return &api.MatchString{Type: api.MatchString_REGEX, Value: utils.NewStringValue(value)}
+}
+
+func validationFields(resp *api.Response) []string {
+	fields := make([]string, 0, len(resp.GetValidationErrors()))
+	for _, err := range resp.GetValidationErrors() {
+		fields = append(fields, err.GetField().GetValue())
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func expectFields(t *testing.T, resp *api.Response, expect ...string) {
+	fields := validationFields(resp)
+	sort.Strings(expect)
+	if len(fields) != len(expect) {
+		t.Fatalf("validation fields: %v, expect: %v, errors: %+v", fields, expect, resp.GetValidationErrors())
+	}
+	for i := range fields {
+		if fields[i] != expect[i] {
+			t.Fatalf("validation fields: %v, expect: %v", fields, expect)
+		}
+	}
+	code := uint32(api.ExecuteSuccess)
+	if len(expect) > 0 {
+		code = api.InvalidRuleValidation
+	}
+	if resp.GetCode().GetValue() != code {
+		t.Fatalf("validation code: %d, expect: %d", resp.GetCode().GetValue(), code)
+	}
+}
+
+// TestValidateRoutingConfig 测试路由规则的正则、目标服务、优先级权重以及覆盖检查
+func TestValidateRoutingConfig(t *testing.T) {
+	s, ctl := newValidateServer(t)
+	defer ctl.Finish()
+
+	routing := &api.Routing{
+		Service:   utils.NewStringValue("svc"),
+		Namespace: utils.NewStringValue("Test"),
+		Inbounds: []*api.Route{
+			{
+				Sources: []*api.Source{{
+					Service:   utils.NewStringValue("*"),
+					Namespace: utils.NewStringValue("Test"),
+					Metadata:  map[string]*api.MatchString{"env": regexMatch("^(dev|test)$")},
+				}},
+				Destinations: []*api.Destination{
+					{
+						Service:   utils.NewStringValue("svc"),
+						Namespace: utils.NewStringValue("Test"),
+						Priority:  utils.NewUInt32Value(0),
+						Weight:    utils.NewUInt32Value(100),
+					},
+					{
+						Service:   utils.NewStringValue("svc"),
+						Namespace: utils.NewStringValue("Test"),
+						Metadata:  map[string]*api.MatchString{"set": exactMatch("b")},
+						Priority:  utils.NewUInt32Value(1),
+					},
+				},
+			},
+		},
+	}
+	expectFields(t, s.ValidateRoutingConfig(context.Background(), routing),
+		"inbounds[0].destinations")
+
+	routing.Inbounds[0].Destinations[1].Weight = utils.NewUInt32Value(10)
+	expectFields(t, s.ValidateRoutingConfig(context.Background(), routing))
+
+	routing.Inbounds = append(routing.Inbounds,
+		&api.Route{
+			Sources: []*api.Source{{
+				Service:   utils.NewStringValue("caller"),
+				Namespace: utils.NewStringValue("Test"),
+				Metadata:  map[string]*api.MatchString{"env": exactMatch("dev"), "uid": regexMatch("(")},
+			}},
+			Destinations: []*api.Destination{
+				{
+					Service:   utils.NewStringValue("missing"),
+					Namespace: utils.NewStringValue("Test"),
+					Priority:  utils.NewUInt32Value(10),
+				},
+				{
+					Service:   utils.NewStringValue("missing"),
+					Namespace: utils.NewStringValue("Test"),
+				},
+			},
+		})
+	expectFields(t, s.ValidateRoutingConfig(context.Background(), routing),
+		"inbounds[1].sources",
+		"inbounds[1].sources[0].metadata.uid",
+		"inbounds[1].destinations[0].service",
+		"inbounds[1].destinations[0].priority",
+		"inbounds[1].destinations[1].service",
+		"inbounds[1].destinations[1]")
+
+	routing.Service = utils.NewStringValue("missing")
+	routing.Inbounds = nil
+	expectFields(t, s.ValidateRoutingConfig(context.Background(), routing), "service")
+}
+
+// TestValidateRateLimits 测试限流规则的配额检查以及同一批次内规则的覆盖检查
+func TestValidateRateLimits(t *testing.T) {
+	s, ctl := newValidateServer(t)
+	defer ctl.Finish()
+
+	newRule := func(priority uint32, labels map[string]*api.MatchString) *api.Rule {
+		return &api.Rule{
+			Service:   utils.NewStringValue("svc"),
+			Namespace: utils.NewStringValue("Test"),
+			Priority:  utils.NewUInt32Value(priority),
+			Labels:    labels,
+			Amounts: []*api.Amount{{
+				MaxAmount:     utils.NewUInt32Value(100),
+				ValidDuration: &duration.Duration{Seconds: 1},
+			}},
+		}
+	}
+	rules := []*api.Rule{
+		newRule(0, map[string]*api.MatchString{"uin": regexMatch("^1")}),
+		newRule(1, map[string]*api.MatchString{"uin": exactMatch("100"), "method": exactMatch("get")}),
+		newRule(0, map[string]*api.MatchString{"uin": regexMatch("^1")}),
+		newRule(2, map[string]*api.MatchString{"uin": exactMatch("200")}),
+	}
+	rules[3].Amounts = append(rules[3].Amounts, &api.Amount{
+		MaxAmount:     utils.NewUInt32Value(10),
+		MinAmount:     utils.NewUInt32Value(20),
+		ValidDuration: &duration.Duration{Nanos: 500000000},
+	})
+
+	resp := s.ValidateRateLimits(context.Background(), rules)
+	if len(resp.GetResponses()) != len(rules) {
+		t.Fatalf("responses size: %d", len(resp.GetResponses()))
+	}
+	expectFields(t, resp.GetResponses()[0])
+	expectFields(t, resp.GetResponses()[1], "priority")
+	expectFields(t, resp.GetResponses()[2], "labels")
+	expectFields(t, resp.GetResponses()[3], "amounts[1].validDuration", "amounts[1].minAmount")
+}
+
+// TestValidateCircuitBreaker 测试熔断规则的阈值以及覆盖检查
+func TestValidateCircuitBreaker(t *testing.T) {
+	s, ctl := newValidateServer(t)
+	defer ctl.Finish()
+
+	cb := &api.CircuitBreaker{
+		Name:      utils.NewStringValue("cb"),
+		Namespace: utils.NewStringValue("Test"),
+		Owners:    utils.NewStringValue("polaris"),
+		Inbounds: []*api.CbRule{
+			{
+				Destinations: []*api.DestinationSet{{
+					Service:   utils.NewStringValue("callee"),
+					Namespace: utils.NewStringValue("Test"),
+					Method:    regexMatch("[a-"),
+					Policy: &api.CbPolicy{
+						ErrorRate: &api.CbPolicy_ErrRateConfig{
+							Enable:               utils.NewBoolValue(true),
+							ErrorRateToPreserved: utils.NewUInt32Value(60),
+							ErrorRateToOpen:      utils.NewUInt32Value(50),
+						},
+						MaxEjectionPercent: utils.NewUInt32Value(120),
+					},
+				}},
+			},
+			{
+				Sources: []*api.SourceMatcher{{
+					Service:   utils.NewStringValue("caller"),
+					Namespace: utils.NewStringValue("Test"),
+				}},
+				Destinations: []*api.DestinationSet{{Service: utils.NewStringValue("*")}},
+			},
+		},
+	}
+	expectFields(t, s.ValidateCircuitBreaker(context.Background(), cb),
+		"inbounds[0].destinations[0].method",
+		"inbounds[0].destinations[0].policy.errorRate.errorRateToPreserved",
+		"inbounds[0].destinations[0].policy.maxEjectionPercent",
+		"inbounds[1].sources")
+
+	cb.Owners = nil
+	expectFields(t, s.ValidateCircuitBreaker(context.Background(), cb), "owners")
+}
+
+// TestMatchStringCovers 测试匹配条件之间的覆盖关系
+func TestMatchStringCovers(t *testing.T) {
+	tests := []struct {
+		match  *api.MatchString
+		other  *api.MatchString
+		covers bool
+	}{
+		{exactMatch("a"), exactMatch("a"), true},
+		{exactMatch("a"), exactMatch("b"), false},
+		{exactMatch("a"), regexMatch("a"), false},
+		{regexMatch("^a"), exactMatch("abc"), true},
+		{regexMatch("^a"), exactMatch("cba"), false},
+		{regexMatch("^a"), regexMatch("^a"), true},
+		{regexMatch("^a"), regexMatch("^ab"), false},
+	}
+	for i, test := range tests {
+		if covers := matchStringCovers(test.match, test.other); covers != test.covers {
+			t.Fatalf("case %d: covers %v, expect %v", i, covers, test.covers)
+		}
+	}
+
+	wildcard := &ruleMatcher{namespace: "Test"}
+	specific := &ruleMatcher{service: "svc", namespace: "Test",
+		labels: map[string]*api.MatchString{"env": exactMatch("dev")}}
+	if !wildcard.covers(specific) || specific.covers(wildcard) {
+		t.Fatalf("wildcard matcher should cover specific matcher only")
+	}
+}
@@ -64,6 +64,8 @@ type Config struct {
 	Auth        map[string]interface{} `yaml:"auth"`
 	HealthCheck HealthCheckConfig      `yaml:"healthcheck"`
 	Batch       map[string]interface{} `yaml:"batch"`
+	Watch       WatchConfig            `yaml:"watch"`
+	Nearby      NearbyConfig           `yaml:"nearby"`
 }
 
 /**
@@ -75,6 +77,8 @@ type Server struct {
 	caches    *cache.NamingCache
 	authority auth.Authority
 	hbMgr     *HeartBeatMgr
+	prober    *HealthProber
+	bcMutex   sync.RWMutex
 	bc        *batch.Controller
 
 	cmdb           plugin.CMDB
@@ -84,6 +88,13 @@ type Server struct {
 	auth           plugin.Auth
 
 	l5service *l5service
+	watchHub  *watchHub
+
+	nearbyMutex sync.RWMutex
+	nearbyConf  NearbyConfig
+	// 服务ID -> *nearbyCache
+	nearbyCaches    sync.Map
+	nearbyCleanTime int64
 }
 
 /**
@@ -119,6 +130,13 @@ func (s *Server) Authority() auth.Authority {
 	return s.authority
 }
 
+// 获取批量控制器，批量控制器可以热更新
+func (s *Server) batchCtrl() *batch.Controller {
+	s.bcMutex.RLock()
+	defer s.bcMutex.RUnlock()
+	return s.bc
+}
+
 /**
  * Cache 返回Cache
  */
@@ -126,16 +144,18 @@ func (s *Server) Cache() *cache.NamingCache {
 	return s.caches
 }
 
-// RecordHistory server对外提供history插件的简单封装
+// RecordHistory server对外提供history插件的简单封装，同时发布配置变更事件
 func (s *Server) RecordHistory(entry *model.RecordEntry) {
-	// 如果插件没有初始化，那么不记录history
-	if s.history == nil {
-		return
-	}
 	// 如果数据为空，则不需要打印了
 	if entry == nil {
 		return
 	}
+	s.publishWatchEvent(entry)
+
+	// 如果插件没有初始化，那么不记录history
+	if s.history == nil {
+		return
+	}
 
 	// 调用插件记录history
 	s.history.Record(entry)
@@ -211,13 +231,17 @@ func initialize(ctx context.Context, namingOpt *Config, cacheOpt *cache.Config)
 	server.storage = s
 
 	// 初始化鉴权模块
-	authority, err := auth.NewAuthority(namingOpt.Auth)
+	authority, err := auth.NewAuthority(namingOpt.Auth, s)
 	if err != nil {
 		log.Errorf("[Naming][Server] new auth err: %s", err.Error())
 		return err
 	}
 	server.authority = authority
 
+	// 配置变更事件的分发
+	server.watchHub = newWatchHub(&namingOpt.Watch)
+	server.setNearbyConfig(namingOpt.Nearby)
+
 	// cache模块，可以不开启
 	// 对于控制台集群，只访问控制台接口的，可以不开启cache
 	if cacheOpt.Open {
@@ -245,6 +269,10 @@ func initialize(ctx context.Context, namingOpt *Config, cacheOpt *cache.Config)
 
 		server.hbMgr = hbMgr
 		server.hbMgr.Start()
+
+		// TCP、HTTP主动探测与心跳共用健康检查server集群
+		server.prober = NewHealthProber(ctx, hbMgr.peers)
+		server.prober.Start()
 	}
 	// 批量控制器
 	batchConfig, err := batch.ParseBatchConfig(namingOpt.Batch)
@@ -267,6 +295,9 @@ func initialize(ctx context.Context, namingOpt *Config, cacheOpt *cache.Config)
 	// 插件初始化
 	pluginInitialize()
 
+	// 注册运行指标
+	registerMetrics(server)
+
 	return nil
 }
 
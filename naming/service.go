@@ -26,6 +26,7 @@ import (
 	"github.com/polarismesh/polaris-server/common/log"
 	"github.com/polarismesh/polaris-server/common/model"
 	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming/auth"
 	"go.uber.org/zap"
 )
 
@@ -85,6 +86,10 @@ func (s *Server) CreateService(ctx context.Context, req *api.Service) *api.Respo
 
 	namespaceName := req.GetNamespace().GetValue()
 	serviceName := req.GetName().GetValue()
+	// 带有登录用户时校验用户的角色权限
+	if found, ok := s.verifyAuthByUser(ctx, auth.ResourceService, namespaceName, serviceName); found && !ok {
+		return api.NewServiceResponse(api.Unauthorized, req)
+	}
 	// 检查命名空间是否存在
 	namespace, err := s.storage.GetNamespace(namespaceName)
 	if err != nil {
@@ -186,7 +191,7 @@ func (s *Server) DeleteService(ctx context.Context, req *api.Service) *api.Respo
 
 	msg := fmt.Sprintf("delete service: namespace=%v, name=%v", namespaceName, serviceName)
 	log.Info(msg, ZapRequestID(requestID), ZapPlatformID(platformID))
-	s.RecordHistory(serviceRecordEntry(ctx, req, nil, model.ODelete))
+	s.RecordHistory(withBefore(serviceRecordEntry(ctx, req, nil, model.ODelete), service2Api(service)))
 
 	return api.NewServiceResponse(api.ExecuteSuccess, req)
 }
@@ -231,6 +236,7 @@ func (s *Server) UpdateService(ctx context.Context, req *api.Service) *api.Respo
 	}
 
 	log.Info(fmt.Sprintf("old service: %+v", service), ZapRequestID(requestID), ZapPlatformID(platformID))
+	before := service2Api(service)
 
 	// 修改
 	err, needUpdate, needUpdateOwner := s.updateServiceAttribute(req, service)
@@ -252,7 +258,7 @@ func (s *Server) UpdateService(ctx context.Context, req *api.Service) *api.Respo
 
 	msg := fmt.Sprintf("update service: namespace=%v, name=%v", service.Namespace, service.Name)
 	log.Info(msg, ZapRequestID(requestID), ZapPlatformID(platformID))
-	s.RecordHistory(serviceRecordEntry(ctx, req, service, model.OUpdate))
+	s.RecordHistory(withBefore(serviceRecordEntry(ctx, req, service, model.OUpdate), before))
 
 	return api.NewServiceResponse(api.ExecuteSuccess, req)
 }
@@ -680,6 +686,14 @@ func (s *Server) checkServiceAuthority(ctx context.Context, req *api.Service) (*
  * @brief 服务鉴权
  */
 func (s *Server) verifyServiceAuth(ctx context.Context, service *model.Service, req *api.Service) *api.Response {
+	// 带有登录用户时使用用户的角色鉴权
+	if found, ok := s.verifyAuthByUser(ctx, auth.ResourceService, service.Namespace, service.Name); found {
+		if !ok {
+			return api.NewServiceResponse(api.Unauthorized, req)
+		}
+		return nil
+	}
+
 	// 使用平台id及token鉴权
 	if ok := s.verifyAuthByPlatform(ctx, service.PlatformID); !ok {
 		// 检查token是否存在
@@ -946,6 +960,8 @@ func serviceRecordEntry(ctx context.Context, req *api.Service, md *model.Service
 	}
 	if md != nil {
 		entry.Context = fmt.Sprintf("platformID:%s,meta:%+v,revision:%s", md.PlatformID, md.Meta, md.Revision)
+		entry.Revision = md.Revision
+		entry.Object = service2Api(md)
 	}
 
 	return entry
@@ -26,6 +26,7 @@ import (
 	"github.com/polarismesh/polaris-server/common/log"
 	"github.com/polarismesh/polaris-server/common/model"
 	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming/auth"
 	"github.com/polarismesh/polaris-server/store"
 	"go.uber.org/zap"
 )
@@ -63,7 +64,7 @@ func (s *Server) CreateServiceAlias(ctx context.Context, req *api.ServiceAlias)
 
 	// 鉴权
 	actualToken := parseRequestToken(ctx, req.GetServiceToken().GetValue())
-	if ok := s.authority.VerifyService(service.Token, actualToken); !ok {
+	if ok := s.verifyServiceAliasAuth(ctx, service, actualToken); !ok {
 		return api.NewServiceAliasResponse(api.Unauthorized, req)
 	}
 
@@ -205,7 +206,7 @@ func (s *Server) DeleteServiceAlias(ctx context.Context, req *api.ServiceAlias)
 	}
 	// 鉴权
 	actualToken := parseRequestToken(ctx, req.GetServiceToken().GetValue())
-	if ok := s.authority.VerifyService(service.Token, actualToken); !ok {
+	if ok := s.verifyServiceAliasAuth(ctx, service, actualToken); !ok {
 		return api.NewServiceAliasResponse(api.Unauthorized, req)
 	}
 
@@ -247,7 +248,7 @@ func (s *Server) UpdateServiceAlias(ctx context.Context, req *api.ServiceAlias)
 
 	// 鉴权
 	actualToken := parseRequestToken(ctx, req.GetServiceToken().GetValue())
-	if ok := s.authority.VerifyService(alias.Token, actualToken); !ok {
+	if ok := s.verifyServiceAliasAuth(ctx, alias, actualToken); !ok {
 		return api.NewServiceAliasResponse(api.Unauthorized, req)
 	}
 
@@ -558,3 +559,13 @@ func CheckDbServiceAliasFieldLen(req *api.ServiceAlias) (*api.Response, bool) {
 	}
 	return nil, false
 }
+
+/**
+ * @brief 服务别名鉴权，带有登录用户时使用用户的角色鉴权，否则校验服务的Token
+ */
+func (s *Server) verifyServiceAliasAuth(ctx context.Context, service *model.Service, token string) bool {
+	if found, ok := s.verifyAuthByUser(ctx, auth.ResourceService, service.Namespace, service.Name); found {
+		return ok
+	}
+	return s.authority.VerifyService(service.Token, token)
+}
@@ -0,0 +1,819 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming/auth"
+	"github.com/polarismesh/polaris-server/naming/batch"
+)
+
+const (
+	minPasswordLength = 6
+	maxPasswordLength = 64
+)
+
+var (
+	// 用户、用户组以及角色支持的查询条件
+	authFilterAttributes = map[string]bool{
+		"name":   true,
+		"offset": true,
+		"limit":  true,
+	}
+
+	// 权限策略支持的资源类型
+	policyResources = map[string]bool{
+		auth.ResourceAll:            true,
+		auth.ResourceNamespace:      true,
+		auth.ResourceService:        true,
+		auth.ResourceRouting:        true,
+		auth.ResourceRateLimit:      true,
+		auth.ResourceCircuitBreaker: true,
+		auth.ResourceAuth:           true,
+	}
+)
+
+/**
+ * Login 用户登录，校验用户名和密码，返回带有访问Token的用户信息
+ */
+func (s *Server) Login(ctx context.Context, req *api.User) *api.Response {
+	if req == nil {
+		return api.NewUserResponse(api.EmptyRequest, req)
+	}
+	name := req.GetName().GetValue()
+	token, err := s.authority.Login(name, req.GetPassword().GetValue())
+	if err != nil {
+		log.Errorf("[Server][User] user(%s) login failed: %s", name, err.Error())
+		return api.NewUserResponse(api.UserLoginFailed, &api.User{Name: req.GetName()})
+	}
+
+	log.Infof("[Server][User] user(%s) login, request-id: %s", name, ParseRequestID(ctx))
+	return api.NewUserResponse(api.ExecuteSuccess, &api.User{
+		Name:  req.GetName(),
+		Token: utils.NewStringValue(token),
+	})
+}
+
+/**
+ * CreateUsers 批量创建用户
+ */
+func (s *Server) CreateUsers(ctx context.Context, req []*api.User) *api.BatchWriteResponse {
+	if checkErr := checkBatchAuthRequest(len(req)); checkErr != nil {
+		return checkErr
+	}
+	if !s.verifyAuthManage(ctx, auth.ActionWrite) {
+		return api.NewBatchWriteResponse(api.Unauthorized)
+	}
+
+	responses := api.NewBatchWriteResponse(api.ExecuteSuccess)
+	for _, user := range req {
+		responses.Collect(s.createUser(ctx, user))
+	}
+	s.reloadAuthority()
+	return api.FormatBatchWriteResponse(responses)
+}
+
+// 创建单个用户
+func (s *Server) createUser(ctx context.Context, req *api.User) *api.Response {
+	if resp := checkUserParams(req); resp != nil {
+		return resp
+	}
+	hash, err := auth.HashPassword(req.GetPassword().GetValue())
+	if err != nil {
+		log.Errorf("[Server][User] hash password err: %s", err.Error())
+		return api.NewUserResponse(api.ExecuteException, userWithoutPassword(req))
+	}
+
+	user := &model.User{
+		ID:       NewUUID(),
+		Name:     req.GetName().GetValue(),
+		Password: hash,
+		Comment:  req.GetComment().GetValue(),
+	}
+	if err := s.storage.AddUser(user); err != nil {
+		log.Errorf("[Server][User] create user(%s) err: %s", user.Name, err.Error())
+		return api.NewUserResponse(batch.StoreCode2APICode(err), userWithoutPassword(req))
+	}
+
+	log.Infof("[Server][User] create user(%s), operator: %s", user.Name, ParseOperator(ctx))
+	return api.NewUserResponse(api.ExecuteSuccess, user2API(user))
+}
+
+/**
+ * UpdateUsers 批量修改用户的密码以及描述，用户可以修改自己的密码
+ */
+func (s *Server) UpdateUsers(ctx context.Context, req []*api.User) *api.BatchWriteResponse {
+	if checkErr := checkBatchAuthRequest(len(req)); checkErr != nil {
+		return checkErr
+	}
+
+	responses := api.NewBatchWriteResponse(api.ExecuteSuccess)
+	for _, user := range req {
+		responses.Collect(s.updateUser(ctx, user))
+	}
+	s.reloadAuthority()
+	return api.FormatBatchWriteResponse(responses)
+}
+
+// 修改单个用户
+func (s *Server) updateUser(ctx context.Context, req *api.User) *api.Response {
+	if resp := checkUserParams(req); resp != nil {
+		return resp
+	}
+	name := req.GetName().GetValue()
+	if ParseUser(ctx) != name && !s.verifyAuthManage(ctx, auth.ActionWrite) {
+		return api.NewUserResponse(api.Unauthorized, userWithoutPassword(req))
+	}
+
+	user, resp := s.getUser(name)
+	if resp != nil {
+		return resp
+	}
+	hash, err := auth.HashPassword(req.GetPassword().GetValue())
+	if err != nil {
+		log.Errorf("[Server][User] hash password err: %s", err.Error())
+		return api.NewUserResponse(api.ExecuteException, userWithoutPassword(req))
+	}
+	user.Password = hash
+	if req.GetComment() != nil {
+		user.Comment = req.GetComment().GetValue()
+	}
+	if err := s.storage.UpdateUser(user); err != nil {
+		log.Errorf("[Server][User] update user(%s) err: %s", name, err.Error())
+		return api.NewUserResponse(batch.StoreCode2APICode(err), userWithoutPassword(req))
+	}
+
+	log.Infof("[Server][User] update user(%s), operator: %s", name, ParseOperator(ctx))
+	return api.NewUserResponse(api.ExecuteSuccess, user2API(user))
+}
+
+/**
+ * DeleteUsers 批量删除用户，同时把用户从用户组以及角色中移除
+ */
+func (s *Server) DeleteUsers(ctx context.Context, req []*api.User) *api.BatchWriteResponse {
+	if checkErr := checkBatchAuthRequest(len(req)); checkErr != nil {
+		return checkErr
+	}
+	if !s.verifyAuthManage(ctx, auth.ActionWrite) {
+		return api.NewBatchWriteResponse(api.Unauthorized)
+	}
+
+	responses := api.NewBatchWriteResponse(api.ExecuteSuccess)
+	for _, user := range req {
+		responses.Collect(s.deleteUser(ctx, user))
+	}
+	s.reloadAuthority()
+	return api.FormatBatchWriteResponse(responses)
+}
+
+// 删除单个用户
+func (s *Server) deleteUser(ctx context.Context, req *api.User) *api.Response {
+	if req == nil {
+		return api.NewUserResponse(api.EmptyRequest, req)
+	}
+	name := req.GetName().GetValue()
+	out := &api.User{Name: req.GetName()}
+	if err := checkResourceName(req.GetName()); err != nil {
+		return api.NewUserResponse(api.InvalidUserName, out)
+	}
+
+	groups, err := s.storage.GetUserGroups()
+	if err != nil {
+		log.Errorf("[Server][User] get user groups err: %s", err.Error())
+		return api.NewUserResponse(api.StoreLayerException, out)
+	}
+	for _, group := range groups {
+		if users, ok := removeName(group.Users, name); ok {
+			group.Users = users
+			if err := s.storage.UpdateUserGroup(group); err != nil {
+				log.Errorf("[Server][User] remove user(%s) from group(%s) err: %s", name, group.Name, err.Error())
+				return api.NewUserResponse(api.StoreLayerException, out)
+			}
+		}
+	}
+	roles, err := s.storage.GetRoles()
+	if err != nil {
+		log.Errorf("[Server][User] get roles err: %s", err.Error())
+		return api.NewUserResponse(api.StoreLayerException, out)
+	}
+	for _, role := range roles {
+		if users, ok := removeName(role.Users, name); ok {
+			role.Users = users
+			if err := s.storage.UpdateRole(role); err != nil {
+				log.Errorf("[Server][User] remove user(%s) from role(%s) err: %s", name, role.Name, err.Error())
+				return api.NewUserResponse(api.StoreLayerException, out)
+			}
+		}
+	}
+
+	if err := s.storage.DeleteUser(name); err != nil {
+		log.Errorf("[Server][User] delete user(%s) err: %s", name, err.Error())
+		return api.NewUserResponse(api.StoreLayerException, out)
+	}
+	log.Infof("[Server][User] delete user(%s), operator: %s", name, ParseOperator(ctx))
+	return api.NewUserResponse(api.ExecuteSuccess, out)
+}
+
+/**
+ * GetUsers 查询用户，支持按照名字过滤
+ */
+func (s *Server) GetUsers(ctx context.Context, query map[string]string) *api.BatchQueryResponse {
+	if !s.verifyAuthManage(ctx, auth.ActionRead) {
+		return api.NewBatchQueryResponse(api.Unauthorized)
+	}
+	offset, limit, resp := parseAuthQuery(query)
+	if resp != nil {
+		return resp
+	}
+
+	users, err := s.storage.GetUsers()
+	if err != nil {
+		log.Errorf("[Server][User] get users err: %s", err.Error())
+		return api.NewBatchQueryResponse(api.StoreLayerException)
+	}
+	out := make([]*api.User, 0, len(users))
+	for _, user := range users {
+		if name, ok := query["name"]; ok && name != user.Name {
+			continue
+		}
+		out = append(out, user2API(user))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].GetName().GetValue() < out[j].GetName().GetValue()
+	})
+
+	total := uint32(len(out))
+	first, last := pageRange(total, offset, limit)
+	resp = api.NewBatchQueryResponse(api.ExecuteSuccess)
+	resp.Amount = utils.NewUInt32Value(total)
+	resp.Size = utils.NewUInt32Value(last - first)
+	resp.Users = out[first:last]
+	return resp
+}
+
+/**
+ * CreateUserGroups 批量创建用户组
+ */
+func (s *Server) CreateUserGroups(ctx context.Context, req []*api.UserGroup) *api.BatchWriteResponse {
+	if checkErr := checkBatchAuthRequest(len(req)); checkErr != nil {
+		return checkErr
+	}
+	if !s.verifyAuthManage(ctx, auth.ActionWrite) {
+		return api.NewBatchWriteResponse(api.Unauthorized)
+	}
+
+	responses := api.NewBatchWriteResponse(api.ExecuteSuccess)
+	for _, group := range req {
+		responses.Collect(s.saveUserGroup(ctx, group, true))
+	}
+	s.reloadAuthority()
+	return api.FormatBatchWriteResponse(responses)
+}
+
+/**
+ * UpdateUserGroups 批量修改用户组的成员以及描述
+ */
+func (s *Server) UpdateUserGroups(ctx context.Context, req []*api.UserGroup) *api.BatchWriteResponse {
+	if checkErr := checkBatchAuthRequest(len(req)); checkErr != nil {
+		return checkErr
+	}
+	if !s.verifyAuthManage(ctx, auth.ActionWrite) {
+		return api.NewBatchWriteResponse(api.Unauthorized)
+	}
+
+	responses := api.NewBatchWriteResponse(api.ExecuteSuccess)
+	for _, group := range req {
+		responses.Collect(s.saveUserGroup(ctx, group, false))
+	}
+	s.reloadAuthority()
+	return api.FormatBatchWriteResponse(responses)
+}
+
+// 创建或者修改单个用户组
+func (s *Server) saveUserGroup(ctx context.Context, req *api.UserGroup, create bool) *api.Response {
+	if req == nil {
+		return api.NewUserGroupResponse(api.EmptyRequest, req)
+	}
+	if err := checkResourceName(req.GetName()); err != nil {
+		return api.NewUserGroupResponse(api.InvalidUserGroupName, req)
+	}
+	if err := checkAuthComment(req.GetComment()); err != nil {
+		return api.NewUserGroupResponse(api.InvalidParameter, req)
+	}
+	if resp := s.checkUsersExist(req.GetUsers()); resp != nil {
+		resp.UserGroup = req
+		return resp
+	}
+
+	group := &model.UserGroup{
+		Name:    req.GetName().GetValue(),
+		Comment: req.GetComment().GetValue(),
+		Users:   req.GetUsers(),
+	}
+	var err error
+	if create {
+		group.ID = NewUUID()
+		err = s.storage.AddUserGroup(group)
+	} else {
+		err = s.storage.UpdateUserGroup(group)
+	}
+	if err != nil {
+		log.Errorf("[Server][User] save user group(%s) err: %s", group.Name, err.Error())
+		return api.NewUserGroupResponse(batch.StoreCode2APICode(err), req)
+	}
+
+	log.Infof("[Server][User] save user group(%s), users: %v, operator: %s",
+		group.Name, group.Users, ParseOperator(ctx))
+	return api.NewUserGroupResponse(api.ExecuteSuccess, req)
+}
+
+/**
+ * DeleteUserGroups 批量删除用户组，同时把用户组从角色中移除
+ */
+func (s *Server) DeleteUserGroups(ctx context.Context, req []*api.UserGroup) *api.BatchWriteResponse {
+	if checkErr := checkBatchAuthRequest(len(req)); checkErr != nil {
+		return checkErr
+	}
+	if !s.verifyAuthManage(ctx, auth.ActionWrite) {
+		return api.NewBatchWriteResponse(api.Unauthorized)
+	}
+
+	responses := api.NewBatchWriteResponse(api.ExecuteSuccess)
+	for _, group := range req {
+		responses.Collect(s.deleteUserGroup(ctx, group))
+	}
+	s.reloadAuthority()
+	return api.FormatBatchWriteResponse(responses)
+}
+
+// 删除单个用户组
+func (s *Server) deleteUserGroup(ctx context.Context, req *api.UserGroup) *api.Response {
+	if req == nil {
+		return api.NewUserGroupResponse(api.EmptyRequest, req)
+	}
+	if err := checkResourceName(req.GetName()); err != nil {
+		return api.NewUserGroupResponse(api.InvalidUserGroupName, req)
+	}
+	name := req.GetName().GetValue()
+
+	roles, err := s.storage.GetRoles()
+	if err != nil {
+		log.Errorf("[Server][User] get roles err: %s", err.Error())
+		return api.NewUserGroupResponse(api.StoreLayerException, req)
+	}
+	for _, role := range roles {
+		if groups, ok := removeName(role.Groups, name); ok {
+			role.Groups = groups
+			if err := s.storage.UpdateRole(role); err != nil {
+				log.Errorf("[Server][User] remove group(%s) from role(%s) err: %s", name, role.Name, err.Error())
+				return api.NewUserGroupResponse(api.StoreLayerException, req)
+			}
+		}
+	}
+
+	if err := s.storage.DeleteUserGroup(name); err != nil {
+		log.Errorf("[Server][User] delete user group(%s) err: %s", name, err.Error())
+		return api.NewUserGroupResponse(api.StoreLayerException, req)
+	}
+	log.Infof("[Server][User] delete user group(%s), operator: %s", name, ParseOperator(ctx))
+	return api.NewUserGroupResponse(api.ExecuteSuccess, req)
+}
+
+/**
+ * GetUserGroups 查询用户组，支持按照名字过滤
+ */
+func (s *Server) GetUserGroups(ctx context.Context, query map[string]string) *api.BatchQueryResponse {
+	if !s.verifyAuthManage(ctx, auth.ActionRead) {
+		return api.NewBatchQueryResponse(api.Unauthorized)
+	}
+	offset, limit, resp := parseAuthQuery(query)
+	if resp != nil {
+		return resp
+	}
+
+	groups, err := s.storage.GetUserGroups()
+	if err != nil {
+		log.Errorf("[Server][User] get user groups err: %s", err.Error())
+		return api.NewBatchQueryResponse(api.StoreLayerException)
+	}
+	out := make([]*api.UserGroup, 0, len(groups))
+	for _, group := range groups {
+		if name, ok := query["name"]; ok && name != group.Name {
+			continue
+		}
+		out = append(out, &api.UserGroup{
+			Id:      utils.NewStringValue(group.ID),
+			Name:    utils.NewStringValue(group.Name),
+			Comment: utils.NewStringValue(group.Comment),
+			Users:   group.Users,
+			Ctime:   utils.NewStringValue(time2String(group.CreateTime)),
+			Mtime:   utils.NewStringValue(time2String(group.ModifyTime)),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].GetName().GetValue() < out[j].GetName().GetValue()
+	})
+
+	total := uint32(len(out))
+	first, last := pageRange(total, offset, limit)
+	resp = api.NewBatchQueryResponse(api.ExecuteSuccess)
+	resp.Amount = utils.NewUInt32Value(total)
+	resp.Size = utils.NewUInt32Value(last - first)
+	resp.UserGroups = out[first:last]
+	return resp
+}
+
+/**
+ * CreateRoles 批量创建角色
+ */
+func (s *Server) CreateRoles(ctx context.Context, req []*api.Role) *api.BatchWriteResponse {
+	if checkErr := checkBatchAuthRequest(len(req)); checkErr != nil {
+		return checkErr
+	}
+	if !s.verifyAuthManage(ctx, auth.ActionWrite) {
+		return api.NewBatchWriteResponse(api.Unauthorized)
+	}
+
+	responses := api.NewBatchWriteResponse(api.ExecuteSuccess)
+	for _, role := range req {
+		responses.Collect(s.saveRole(ctx, role, true))
+	}
+	s.reloadAuthority()
+	return api.FormatBatchWriteResponse(responses)
+}
+
+/**
+ * UpdateRoles 批量修改角色的成员、权限策略以及描述
+ */
+func (s *Server) UpdateRoles(ctx context.Context, req []*api.Role) *api.BatchWriteResponse {
+	if checkErr := checkBatchAuthRequest(len(req)); checkErr != nil {
+		return checkErr
+	}
+	if !s.verifyAuthManage(ctx, auth.ActionWrite) {
+		return api.NewBatchWriteResponse(api.Unauthorized)
+	}
+
+	responses := api.NewBatchWriteResponse(api.ExecuteSuccess)
+	for _, role := range req {
+		responses.Collect(s.saveRole(ctx, role, false))
+	}
+	s.reloadAuthority()
+	return api.FormatBatchWriteResponse(responses)
+}
+
+// 创建或者修改单个角色
+func (s *Server) saveRole(ctx context.Context, req *api.Role, create bool) *api.Response {
+	if req == nil {
+		return api.NewRoleResponse(api.EmptyRequest, req)
+	}
+	if err := checkResourceName(req.GetName()); err != nil {
+		return api.NewRoleResponse(api.InvalidRoleName, req)
+	}
+	if err := checkAuthComment(req.GetComment()); err != nil {
+		return api.NewRoleResponse(api.InvalidParameter, req)
+	}
+	policies, err := api2Policies(req.GetPolicies())
+	if err != nil {
+		return api.NewRoleResponseWithMsg(api.InvalidAuthPolicy, req, err.Error())
+	}
+	if resp := s.checkUsersExist(req.GetUsers()); resp != nil {
+		resp.Role = req
+		return resp
+	}
+	if resp := s.checkUserGroupsExist(req.GetGroups()); resp != nil {
+		resp.Role = req
+		return resp
+	}
+
+	role := &model.Role{
+		Name:     req.GetName().GetValue(),
+		Comment:  req.GetComment().GetValue(),
+		Users:    req.GetUsers(),
+		Groups:   req.GetGroups(),
+		Policies: policies,
+	}
+	if create {
+		role.ID = NewUUID()
+		err = s.storage.AddRole(role)
+	} else {
+		err = s.storage.UpdateRole(role)
+	}
+	if err != nil {
+		log.Errorf("[Server][User] save role(%s) err: %s", role.Name, err.Error())
+		return api.NewRoleResponse(batch.StoreCode2APICode(err), req)
+	}
+
+	log.Infof("[Server][User] save role(%s), users: %v, groups: %v, operator: %s",
+		role.Name, role.Users, role.Groups, ParseOperator(ctx))
+	return api.NewRoleResponse(api.ExecuteSuccess, req)
+}
+
+/**
+ * DeleteRoles 批量删除角色
+ */
+func (s *Server) DeleteRoles(ctx context.Context, req []*api.Role) *api.BatchWriteResponse {
+	if checkErr := checkBatchAuthRequest(len(req)); checkErr != nil {
+		return checkErr
+	}
+	if !s.verifyAuthManage(ctx, auth.ActionWrite) {
+		return api.NewBatchWriteResponse(api.Unauthorized)
+	}
+
+	responses := api.NewBatchWriteResponse(api.ExecuteSuccess)
+	for _, role := range req {
+		if role == nil {
+			responses.Collect(api.NewRoleResponse(api.EmptyRequest, role))
+			continue
+		}
+		if err := checkResourceName(role.GetName()); err != nil {
+			responses.Collect(api.NewRoleResponse(api.InvalidRoleName, role))
+			continue
+		}
+		if err := s.storage.DeleteRole(role.GetName().GetValue()); err != nil {
+			log.Errorf("[Server][User] delete role(%s) err: %s", role.GetName().GetValue(), err.Error())
+			responses.Collect(api.NewRoleResponse(api.StoreLayerException, role))
+			continue
+		}
+		log.Infof("[Server][User] delete role(%s), operator: %s", role.GetName().GetValue(), ParseOperator(ctx))
+		responses.Collect(api.NewRoleResponse(api.ExecuteSuccess, role))
+	}
+	s.reloadAuthority()
+	return api.FormatBatchWriteResponse(responses)
+}
+
+/**
+ * GetRoles 查询角色，支持按照名字过滤
+ */
+func (s *Server) GetRoles(ctx context.Context, query map[string]string) *api.BatchQueryResponse {
+	if !s.verifyAuthManage(ctx, auth.ActionRead) {
+		return api.NewBatchQueryResponse(api.Unauthorized)
+	}
+	offset, limit, resp := parseAuthQuery(query)
+	if resp != nil {
+		return resp
+	}
+
+	roles, err := s.storage.GetRoles()
+	if err != nil {
+		log.Errorf("[Server][User] get roles err: %s", err.Error())
+		return api.NewBatchQueryResponse(api.StoreLayerException)
+	}
+	out := make([]*api.Role, 0, len(roles))
+	for _, role := range roles {
+		if name, ok := query["name"]; ok && name != role.Name {
+			continue
+		}
+		out = append(out, role2API(role))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].GetName().GetValue() < out[j].GetName().GetValue()
+	})
+
+	total := uint32(len(out))
+	first, last := pageRange(total, offset, limit)
+	resp = api.NewBatchQueryResponse(api.ExecuteSuccess)
+	resp.Amount = utils.NewUInt32Value(total)
+	resp.Size = utils.NewUInt32Value(last - first)
+	resp.Roles = out[first:last]
+	return resp
+}
+
+/**
+ * @brief 用户、用户组以及角色的管理鉴权
+ * 带有登录用户时需要auth资源的权限，否则需要全局Token
+ */
+func (s *Server) verifyAuthManage(ctx context.Context, action auth.Action) bool {
+	if user := ParseUser(ctx); user != "" {
+		return s.authority.VerifyUser(user, &auth.Permission{Resource: auth.ResourceAuth, Action: action})
+	}
+	return s.authority.VerifyGlobalToken(ParseToken(ctx))
+}
+
+// 用户、用户组以及角色变更后，重新加载鉴权数据
+func (s *Server) reloadAuthority() {
+	if err := s.authority.Reload(); err != nil {
+		log.Errorf("[Server][User] reload authority err: %s", err.Error())
+	}
+}
+
+// 查询用户
+func (s *Server) getUser(name string) (*model.User, *api.Response) {
+	users, err := s.storage.GetUsers()
+	if err != nil {
+		log.Errorf("[Server][User] get users err: %s", err.Error())
+		return nil, api.NewUserResponse(api.StoreLayerException, &api.User{Name: utils.NewStringValue(name)})
+	}
+	for _, user := range users {
+		if user.Name == name {
+			return user, nil
+		}
+	}
+	return nil, api.NewUserResponse(api.NotFoundResource, &api.User{Name: utils.NewStringValue(name)})
+}
+
+// 检查用户是否都存在
+func (s *Server) checkUsersExist(names []string) *api.Response {
+	if len(names) == 0 {
+		return nil
+	}
+	users, err := s.storage.GetUsers()
+	if err != nil {
+		log.Errorf("[Server][User] get users err: %s", err.Error())
+		return api.NewResponse(api.StoreLayerException)
+	}
+	exists := make(map[string]bool, len(users))
+	for _, user := range users {
+		exists[user.Name] = true
+	}
+	for _, name := range names {
+		if !exists[name] {
+			return api.NewResponseWithMsg(api.NotFoundResource, fmt.Sprintf("user(%s) is not found", name))
+		}
+	}
+	return nil
+}
+
+// 检查用户组是否都存在
+func (s *Server) checkUserGroupsExist(names []string) *api.Response {
+	if len(names) == 0 {
+		return nil
+	}
+	groups, err := s.storage.GetUserGroups()
+	if err != nil {
+		log.Errorf("[Server][User] get user groups err: %s", err.Error())
+		return api.NewResponse(api.StoreLayerException)
+	}
+	exists := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		exists[group.Name] = true
+	}
+	for _, name := range names {
+		if !exists[name] {
+			return api.NewResponseWithMsg(api.NotFoundResource, fmt.Sprintf("user group(%s) is not found", name))
+		}
+	}
+	return nil
+}
+
+// 检查批量请求
+func checkBatchAuthRequest(size int) *api.BatchWriteResponse {
+	if size == 0 {
+		return api.NewBatchWriteResponse(api.EmptyRequest)
+	}
+	if size > MaxBatchSize {
+		return api.NewBatchWriteResponse(api.BatchSizeOverLimit)
+	}
+	return nil
+}
+
+// 检查创建或者修改用户的参数
+func checkUserParams(req *api.User) *api.Response {
+	if req == nil {
+		return api.NewUserResponse(api.EmptyRequest, req)
+	}
+	if err := checkResourceName(req.GetName()); err != nil {
+		return api.NewUserResponse(api.InvalidUserName, userWithoutPassword(req))
+	}
+	length := utf8.RuneCountInString(req.GetPassword().GetValue())
+	if length < minPasswordLength || length > maxPasswordLength {
+		return api.NewUserResponse(api.InvalidUserPassword, userWithoutPassword(req))
+	}
+	if err := checkAuthComment(req.GetComment()); err != nil {
+		return api.NewUserResponse(api.InvalidParameter, userWithoutPassword(req))
+	}
+	return nil
+}
+
+// 检查描述信息
+func checkAuthComment(comment *wrappers.StringValue) error {
+	if utf8.RuneCountInString(comment.GetValue()) > MaxCommentLength {
+		return fmt.Errorf("comment too long")
+	}
+	return nil
+}
+
+// 解析查询条件以及分页参数
+func parseAuthQuery(query map[string]string) (uint32, uint32, *api.BatchQueryResponse) {
+	for key := range query {
+		if _, ok := authFilterAttributes[key]; !ok {
+			log.Errorf("[Server][User] attribute(%s) is not allowed", key)
+			return 0, 0, api.NewBatchQueryResponseWithMsg(api.InvalidParameter, key+" is not allowed")
+		}
+	}
+	offset, limit, err := ParseOffsetAndLimit(query)
+	if err != nil {
+		return 0, 0, api.NewBatchQueryResponseWithMsg(api.InvalidParameter, err.Error())
+	}
+	return offset, limit, nil
+}
+
+// 计算分页的范围
+func pageRange(total uint32, offset uint32, limit uint32) (uint32, uint32) {
+	if offset >= total {
+		return total, total
+	}
+	last := offset + limit
+	if last > total {
+		last = total
+	}
+	return offset, last
+}
+
+// 从名字列表中移除指定的名字，返回是否存在
+func removeName(names []string, name string) ([]string, bool) {
+	out := make([]string, 0, len(names))
+	for _, item := range names {
+		if item != name {
+			out = append(out, item)
+		}
+	}
+	return out, len(out) != len(names)
+}
+
+// 把权限策略转换为存储结构，并检查资源类型以及操作类型
+func api2Policies(policies []*api.AuthPolicy) ([]*model.AuthPolicy, error) {
+	out := make([]*model.AuthPolicy, 0, len(policies))
+	for _, policy := range policies {
+		resource := policy.GetResource().GetValue()
+		if !policyResources[resource] {
+			return nil, fmt.Errorf("resource(%s) is not supported", resource)
+		}
+		if len(policy.GetActions()) == 0 {
+			return nil, fmt.Errorf("actions of resource(%s) is empty", resource)
+		}
+		for _, action := range policy.GetActions() {
+			if action != string(auth.ActionRead) && action != string(auth.ActionWrite) {
+				return nil, fmt.Errorf("action(%s) is not supported", action)
+			}
+		}
+		out = append(out, &model.AuthPolicy{
+			Resource:  resource,
+			Namespace: policy.GetNamespace().GetValue(),
+			Service:   policy.GetService().GetValue(),
+			Actions:   policy.GetActions(),
+		})
+	}
+	return out, nil
+}
+
+// 用户转换为API结构，不返回密码
+func user2API(user *model.User) *api.User {
+	return &api.User{
+		Id:      utils.NewStringValue(user.ID),
+		Name:    utils.NewStringValue(user.Name),
+		Comment: utils.NewStringValue(user.Comment),
+		Ctime:   utils.NewStringValue(time2String(user.CreateTime)),
+		Mtime:   utils.NewStringValue(time2String(user.ModifyTime)),
+	}
+}
+
+// 角色转换为API结构
+func role2API(role *model.Role) *api.Role {
+	out := &api.Role{
+		Id:      utils.NewStringValue(role.ID),
+		Name:    utils.NewStringValue(role.Name),
+		Comment: utils.NewStringValue(role.Comment),
+		Users:   role.Users,
+		Groups:  role.Groups,
+		Ctime:   utils.NewStringValue(time2String(role.CreateTime)),
+		Mtime:   utils.NewStringValue(time2String(role.ModifyTime)),
+	}
+	for _, policy := range role.Policies {
+		out.Policies = append(out.Policies, &api.AuthPolicy{
+			Resource:  utils.NewStringValue(policy.Resource),
+			Namespace: utils.NewStringValue(policy.Namespace),
+			Service:   utils.NewStringValue(policy.Service),
+			Actions:   policy.Actions,
+		})
+	}
+	return out
+}
+
+// 回复中去掉请求的密码
+func userWithoutPassword(req *api.User) *api.User {
+	return &api.User{Id: req.GetId(), Name: req.GetName(), Comment: req.GetComment()}
+}
@@ -24,6 +24,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"regexp"
 	"strconv"
 	"strings"
@@ -33,6 +34,7 @@ import (
 	api "github.com/polarismesh/polaris-server/common/api/v1"
 	"github.com/polarismesh/polaris-server/common/log"
 	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming/auth"
 	"github.com/polarismesh/polaris-server/naming/batch"
 	"github.com/polarismesh/polaris-server/store"
 	"go.uber.org/zap"
@@ -133,6 +135,7 @@ func checkResourceOwners(owners *wrappers.StringValue) error {
 
 /*
  * 检查服务实例Host
+ * IP类型的Host会转换为标准格式，保证同一个IPv6地址的不同写法计算出相同的实例ID
  */
 func checkInstanceHost(host *wrappers.StringValue) error {
 	if host == nil {
@@ -143,6 +146,12 @@ func checkInstanceHost(host *wrappers.StringValue) error {
 		return errors.New("empty")
 	}
 
+	value := utils.NormalizeHost(host.GetValue())
+	// 带有冒号的Host只能是IPv6地址
+	if strings.Contains(value, ":") && net.ParseIP(value) == nil {
+		return errors.New("invalid ipv6 address")
+	}
+	host.Value = value
 	return nil
 }
 
@@ -398,6 +407,17 @@ func ParseOperator(ctx context.Context) string {
 	return defaultOperator
 }
 
+/**
+ * ParseUser 从ctx中获取登录的用户名
+ */
+func ParseUser(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	user, _ := ctx.Value(utils.StringContext("user")).(string)
+	return user
+}
+
 /**
  * ParsePlatformID 从ctx中获取Platform-Id
  */
@@ -472,3 +492,27 @@ func (s *Server) verifyAuthByPlatform(ctx context.Context, sPlatformID string) b
 	}
 	return false
 }
+
+/**
+ * @brief 使用登录用户被授予的角色鉴权
+ * 返回请求是否带有登录用户，以及用户是否有写权限；不带登录用户的请求继续使用Token鉴权
+ */
+func (s *Server) verifyAuthByUser(ctx context.Context, resource string, namespace string, service string) (
+	bool, bool) {
+	user := ParseUser(ctx)
+	if user == "" {
+		return false, false
+	}
+	permission := &auth.Permission{
+		Resource:  resource,
+		Namespace: namespace,
+		Service:   service,
+		Action:    auth.ActionWrite,
+	}
+	if !s.authority.VerifyUser(user, permission) {
+		log.Errorf("[Server][Auth] user(%s) has no permission to write %s(%s/%s)",
+			user, resource, namespace, service)
+		return true, false
+	}
+	return true, true
+}
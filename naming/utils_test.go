@@ -0,0 +1,62 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-server/common/utils"
+)
+
+// TestCheckInstanceHost 测试IPv6实例Host的校验以及标准化
+func TestCheckInstanceHost(t *testing.T) {
+	tests := []struct {
+		host   string
+		expect string
+		valid  bool
+	}{
+		{"10.0.0.1", "10.0.0.1", true},
+		{"polaris.example.com", "polaris.example.com", true},
+		{"2001:DB8:0:0::1", "2001:db8::1", true},
+		{"[2001:db8::1]", "2001:db8::1", true},
+		{"2001:db8::zz", "", false},
+		{"10.0.0.1:8080", "", false},
+	}
+	for _, test := range tests {
+		host := utils.NewStringValue(test.host)
+		err := checkInstanceHost(host)
+		if (err == nil) != test.valid {
+			t.Fatalf("host(%s) valid: %v, err: %v", test.host, test.valid, err)
+		}
+		if test.valid && host.GetValue() != test.expect {
+			t.Fatalf("host(%s) normalized: %s, expect: %s", test.host, host.GetValue(), test.expect)
+		}
+	}
+
+	// 同一个IPv6地址的不同写法，实例ID相同
+	ids := make(map[string]bool)
+	for _, value := range []string{"2001:db8::1", "2001:DB8:0::1", "[2001:db8:0:0:0:0:0:1]"} {
+		host := utils.NewStringValue(value)
+		_ = checkInstanceHost(host)
+		id, _ := CalculateInstanceID("Test", "svc", "", host.GetValue(), 8080)
+		ids[id] = true
+	}
+	if len(ids) != 1 {
+		t.Fatalf("ipv6 hosts should have the same instance id, got %d ids", len(ids))
+	}
+}
@@ -0,0 +1,385 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+)
+
+const (
+	// 默认保留的事件数
+	defaultWatchBufferSize = 4096
+	// 默认的最大订阅者数量
+	defaultMaxWatchers = 1024
+	// 单个订阅者未消费的事件上限，超过则断开，由订阅者从最后收到的序号重新订阅
+	watcherQueueSize = 256
+)
+
+/**
+ * WatchConfig 配置变更事件订阅的配置
+ */
+type WatchConfig struct {
+	// 保留最近的事件数，用于订阅者从指定的序号或者revision继续订阅
+	BufferSize int `yaml:"bufferSize"`
+	// 最大的订阅者数量
+	MaxWatchers int `yaml:"maxWatchers"`
+}
+
+/**
+ * Watcher 配置变更事件的订阅者
+ */
+type Watcher struct {
+	hub       *watchHub
+	namespace string
+	service   string
+	resources map[string]bool
+	// 订阅者有读权限的事件，为nil表示不过滤
+	allow recordFilter
+	// 已经返回的最后一个事件的序号
+	seq uint64
+	ch  chan *api.WatchEvent
+
+	closeOnce sync.Once
+}
+
+/**
+ * Events 返回变更事件的channel
+ * @note channel被关闭表示订阅结束，可能是订阅者消费过慢，需要从最后收到的序号重新订阅
+ */
+func (w *Watcher) Events() <-chan *api.WatchEvent {
+	return w.ch
+}
+
+/**
+ * Seq 已经返回的最后一个事件的序号，没有事件则为订阅时的最新序号
+ */
+func (w *Watcher) Seq() uint64 {
+	return w.seq
+}
+
+/**
+ * Next 等待事件到达，返回已经到达的至多limit个事件
+ * ctx结束时返回空；订阅已经结束则返回false
+ */
+func (w *Watcher) Next(ctx context.Context, limit int) ([]*api.WatchEvent, bool) {
+	select {
+	case event, ok := <-w.ch:
+		if !ok {
+			return nil, false
+		}
+		events, _ := w.Drain(limit - 1)
+		return w.ack(append([]*api.WatchEvent{event}, events...)), true
+	case <-ctx.Done():
+		return nil, true
+	}
+}
+
+/**
+ * Drain 不等待，返回已经到达的至多limit个事件
+ */
+func (w *Watcher) Drain(limit int) ([]*api.WatchEvent, bool) {
+	var events []*api.WatchEvent
+	for len(events) < limit {
+		select {
+		case event, ok := <-w.ch:
+			if !ok {
+				return w.ack(events), len(events) > 0
+			}
+			events = append(events, event)
+		default:
+			return w.ack(events), true
+		}
+	}
+	return w.ack(events), true
+}
+
+// 记录已经返回的最后一个事件的序号
+func (w *Watcher) ack(events []*api.WatchEvent) []*api.WatchEvent {
+	if len(events) > 0 {
+		w.seq = events[len(events)-1].GetSeq().GetValue()
+	}
+	return events
+}
+
+/**
+ * Close 取消订阅
+ */
+func (w *Watcher) Close() {
+	w.hub.remove(w)
+}
+
+// 关闭channel，只能在持有hub锁的时候调用
+func (w *Watcher) closeChannel() {
+	w.closeOnce.Do(func() { close(w.ch) })
+}
+
+// 判断事件是否满足订阅条件
+func (w *Watcher) match(event *api.WatchEvent) bool {
+	if w.namespace != "" && w.namespace != event.GetNamespace().GetValue() {
+		return false
+	}
+	if w.service != "" && w.service != event.GetService().GetValue() {
+		return false
+	}
+	if len(w.resources) > 0 && !w.resources[strings.ToLower(event.GetResource().GetValue())] {
+		return false
+	}
+	if w.allow != nil && !w.allow(event.GetResource().GetValue(), event.GetNamespace().GetValue(),
+		event.GetService().GetValue()) {
+		return false
+	}
+	return true
+}
+
+/**
+ * watchHub 变更事件的分发中心
+ * 最近的事件保存在环形缓冲区中，用于订阅者断线后继续订阅
+ */
+type watchHub struct {
+	mutex       sync.Mutex
+	seq         uint64
+	events      []*api.WatchEvent
+	start       int
+	size        int
+	maxWatchers int
+	watchers    map[*Watcher]struct{}
+}
+
+// 新建watchHub
+func newWatchHub(cfg *WatchConfig) *watchHub {
+	bufferSize := cfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultWatchBufferSize
+	}
+	maxWatchers := cfg.MaxWatchers
+	if maxWatchers <= 0 {
+		maxWatchers = defaultMaxWatchers
+	}
+	return &watchHub{
+		events:      make([]*api.WatchEvent, bufferSize),
+		maxWatchers: maxWatchers,
+		watchers:    make(map[*Watcher]struct{}),
+	}
+}
+
+// 热更新订阅者数量上限以及缓冲区大小，缩小缓冲区时保留最近的事件
+func (h *watchHub) updateConfig(cfg *WatchConfig) {
+	bufferSize := cfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultWatchBufferSize
+	}
+	maxWatchers := cfg.MaxWatchers
+	if maxWatchers <= 0 {
+		maxWatchers = defaultMaxWatchers
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.maxWatchers = maxWatchers
+	if bufferSize == len(h.events) {
+		return
+	}
+	size := h.size
+	if size > bufferSize {
+		size = bufferSize
+	}
+	events := make([]*api.WatchEvent, bufferSize)
+	for i := 0; i < size; i++ {
+		events[i] = h.events[(h.start+h.size-size+i)%len(h.events)]
+	}
+	h.events = events
+	h.start = 0
+	h.size = size
+}
+
+// 发布事件，分配序号并分发给订阅者
+func (h *watchHub) publish(event *api.WatchEvent) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.seq++
+	event.Seq = utils.NewUInt64Value(h.seq)
+	if h.size < len(h.events) {
+		h.events[(h.start+h.size)%len(h.events)] = event
+		h.size++
+	} else {
+		h.events[h.start] = event
+		h.start = (h.start + 1) % len(h.events)
+	}
+
+	for watcher := range h.watchers {
+		if !watcher.match(event) {
+			continue
+		}
+		select {
+		case watcher.ch <- event:
+		default:
+			log.Warnf("[Server][Watch] watcher is too slow, close it at seq(%d)", h.seq)
+			delete(h.watchers, watcher)
+			watcher.closeChannel()
+		}
+	}
+}
+
+// 新增订阅者，返回订阅者以及错误码，allow为nil时不按照权限过滤
+func (h *watchHub) add(req *api.WatchRequest, allow recordFilter) (*Watcher, uint32) {
+	watcher := &Watcher{
+		hub:       h,
+		allow:     allow,
+		namespace: req.GetNamespace().GetValue(),
+		service:   req.GetService().GetValue(),
+		resources: make(map[string]bool),
+	}
+	for _, resource := range req.GetResources() {
+		watcher.resources[strings.ToLower(resource)] = true
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if len(h.watchers) >= h.maxWatchers {
+		return nil, api.SubscriptionExceedLimit
+	}
+
+	backlog, ok := h.backlog(req)
+	if !ok {
+		return nil, api.WatchEventExpired
+	}
+	var matched []*api.WatchEvent
+	for _, event := range backlog {
+		if watcher.match(event) {
+			matched = append(matched, event)
+		}
+	}
+
+	// 有补发的事件时，序号为补发的第一个事件之前
+	watcher.seq = h.seq
+	if len(matched) > 0 {
+		watcher.seq = matched[0].GetSeq().GetValue() - 1
+	}
+	watcher.ch = make(chan *api.WatchEvent, len(matched)+watcherQueueSize)
+	for _, event := range matched {
+		watcher.ch <- event
+	}
+	h.watchers[watcher] = struct{}{}
+	return watcher, api.ExecuteSuccess
+}
+
+// 查询需要补发的事件，如果指定的序号或者revision已经不在缓冲区中则返回false
+func (h *watchHub) backlog(req *api.WatchRequest) ([]*api.WatchEvent, bool) {
+	if req.GetSeq() != nil {
+		seq := req.GetSeq().GetValue()
+		// 序号大于当前序号，说明server已经重启
+		if seq > h.seq {
+			return nil, false
+		}
+		oldest := h.seq - uint64(h.size) + 1
+		if seq+1 < oldest {
+			return nil, false
+		}
+		return h.since(int(seq + 1 - oldest)), true
+	}
+
+	if revision := req.GetRevision().GetValue(); revision != "" {
+		for i := h.size - 1; i >= 0; i-- {
+			if h.at(i).GetRevision().GetValue() == revision {
+				return h.since(i + 1), true
+			}
+		}
+		return nil, false
+	}
+	return nil, true
+}
+
+// 缓冲区中第i个事件，0为最旧的事件
+func (h *watchHub) at(i int) *api.WatchEvent {
+	return h.events[(h.start+i)%len(h.events)]
+}
+
+// 缓冲区中从第i个开始的事件
+func (h *watchHub) since(i int) []*api.WatchEvent {
+	var out []*api.WatchEvent
+	for ; i < h.size; i++ {
+		out = append(out, h.at(i))
+	}
+	return out
+}
+
+// 删除订阅者
+func (h *watchHub) remove(watcher *Watcher) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	delete(h.watchers, watcher)
+	watcher.closeChannel()
+}
+
+// 把操作记录转换为变更事件
+func recordEntry2WatchEvent(entry *model.RecordEntry) *api.WatchEvent {
+	event := &api.WatchEvent{
+		Resource:  utils.NewStringValue(string(entry.ResourceType)),
+		Operation: utils.NewStringValue(string(entry.OperationType)),
+		Namespace: utils.NewStringValue(entry.Namespace),
+		Service:   utils.NewStringValue(entry.Service),
+		Revision:  utils.NewStringValue(entry.Revision),
+		Operator:  utils.NewStringValue(entry.Operator),
+		Ctime:     utils.NewStringValue(time2String(entry.CreateTime)),
+	}
+	if entry.Object != nil {
+		object, err := ptypes.MarshalAny(entry.Object)
+		if err != nil {
+			log.Errorf("[Server][Watch] marshal %s object err: %s", entry.ResourceType, err.Error())
+		} else {
+			event.Object = object
+		}
+	}
+	return event
+}
+
+/**
+ * Watch 订阅配置的变更事件
+ * 指定seq或者revision时，先补发缓冲区中之后的事件；登录用户只能收到有读权限的事件
+ */
+func (s *Server) Watch(ctx context.Context, req *api.WatchRequest) (*Watcher, *api.Response) {
+	if s.watchHub == nil {
+		return nil, api.NewResponse(api.ClientAPINotOpen)
+	}
+	allow, code := s.recordReadFilter(ctx)
+	if code != api.ExecuteSuccess {
+		return nil, api.NewResponse(code)
+	}
+	watcher, code := s.watchHub.add(req, allow)
+	if code != api.ExecuteSuccess {
+		return nil, api.NewResponse(code)
+	}
+	return watcher, nil
+}
+
+// 发布变更事件
+func (s *Server) publishWatchEvent(entry *model.RecordEntry) {
+	if s.watchHub == nil || model.GetResourceType(entry.ResourceType) != model.ServiceType {
+		return
+	}
+	s.watchHub.publish(recordEntry2WatchEvent(entry))
+}
@@ -0,0 +1,211 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package naming
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/naming/auth"
+	authmock "github.com/polarismesh/polaris-server/naming/auth/mock"
+)
+
+func publishTestEvent(hub *watchHub, resource model.Resource, service string, revision string) {
+	hub.publish(recordEntry2WatchEvent(&model.RecordEntry{
+		ResourceType:  resource,
+		OperationType: model.OUpdate,
+		Namespace:     "Test",
+		Service:       service,
+		Revision:      revision,
+		Operator:      "test",
+		Object:        &api.Service{Name: utils.NewStringValue(service)},
+		CreateTime:    time.Now(),
+	}))
+}
+
+// TestWatchHub_Publish 测试事件的分发以及过滤
+func TestWatchHub_Publish(t *testing.T) {
+	hub := newWatchHub(&WatchConfig{})
+	all, code := hub.add(&api.WatchRequest{}, nil)
+	if code != api.ExecuteSuccess {
+		t.Fatalf("add watcher code: %d", code)
+	}
+	defer all.Close()
+	filtered, _ := hub.add(&api.WatchRequest{
+		Service:   utils.NewStringValue("svc-1"),
+		Resources: []string{"instance"},
+	}, nil)
+	defer filtered.Close()
+
+	publishTestEvent(hub, model.RService, "svc-1", "rev-1")
+	publishTestEvent(hub, model.RInstance, "svc-1", "rev-2")
+	publishTestEvent(hub, model.RInstance, "svc-2", "rev-3")
+
+	events, ok := all.Next(context.Background(), 10)
+	if !ok || len(events) != 3 || all.Seq() != 3 {
+		t.Fatalf("all watcher events: %d, seq: %d", len(events), all.Seq())
+	}
+	if events[0].GetObject() == nil || events[0].GetOperator().GetValue() != "test" {
+		t.Fatalf("event is not complete: %+v", events[0])
+	}
+	events, _ = filtered.Drain(10)
+	if len(events) != 1 || events[0].GetRevision().GetValue() != "rev-2" || filtered.Seq() != 2 {
+		t.Fatalf("filtered watcher events: %+v", events)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if events, ok := all.Next(ctx, 10); !ok || len(events) != 0 {
+		t.Fatalf("watcher should return nothing when timeout")
+	}
+}
+
+// TestWatchHub_Resume 测试从指定的序号以及revision继续订阅
+func TestWatchHub_Resume(t *testing.T) {
+	hub := newWatchHub(&WatchConfig{BufferSize: 4})
+	for i := 1; i <= 6; i++ {
+		publishTestEvent(hub, model.RService, "svc", fmt.Sprintf("rev-%d", i))
+	}
+
+	watcher, code := hub.add(&api.WatchRequest{Seq: utils.NewUInt64Value(4)}, nil)
+	if code != api.ExecuteSuccess {
+		t.Fatalf("resume by seq code: %d", code)
+	}
+	if watcher.Seq() != 4 {
+		t.Fatalf("watcher seq should be 4, got %d", watcher.Seq())
+	}
+	events, _ := watcher.Drain(10)
+	if len(events) != 2 || events[0].GetSeq().GetValue() != 5 {
+		t.Fatalf("resume by seq events: %+v", events)
+	}
+	watcher.Close()
+
+	watcher, code = hub.add(&api.WatchRequest{Revision: utils.NewStringValue("rev-3")}, nil)
+	if code != api.ExecuteSuccess {
+		t.Fatalf("resume by revision code: %d", code)
+	}
+	events, _ = watcher.Drain(10)
+	if len(events) != 3 || events[0].GetRevision().GetValue() != "rev-4" {
+		t.Fatalf("resume by revision events: %+v", events)
+	}
+	watcher.Close()
+
+	for _, req := range []*api.WatchRequest{
+		{Seq: utils.NewUInt64Value(1)},
+		{Seq: utils.NewUInt64Value(7)},
+		{Revision: utils.NewStringValue("rev-1")},
+	} {
+		if _, code := hub.add(req, nil); code != api.WatchEventExpired {
+			t.Fatalf("request(%+v) should be expired, got %d", req, code)
+		}
+	}
+}
+
+// TestWatchHub_Overflow 测试消费过慢的订阅者被断开
+func TestWatchHub_Overflow(t *testing.T) {
+	hub := newWatchHub(&WatchConfig{MaxWatchers: 1})
+	watcher, _ := hub.add(&api.WatchRequest{}, nil)
+	if _, code := hub.add(&api.WatchRequest{}, nil); code != api.SubscriptionExceedLimit {
+		t.Fatalf("watchers should exceed limit, got %d", code)
+	}
+
+	for i := 0; i <= watcherQueueSize; i++ {
+		publishTestEvent(hub, model.RService, "svc", "")
+	}
+	events, ok := watcher.Drain(watcherQueueSize * 2)
+	if !ok || len(events) != watcherQueueSize {
+		t.Fatalf("watcher should receive %d events, got %d", watcherQueueSize, len(events))
+	}
+	if _, ok := watcher.Next(context.Background(), 10); ok {
+		t.Fatalf("slow watcher should be closed")
+	}
+	watcher.Close()
+	if len(hub.watchers) != 0 {
+		t.Fatalf("watcher should be removed")
+	}
+}
+
+// TestWatchHub_UpdateConfig 测试热更新缓冲区大小以及订阅者数量上限
+func TestWatchHub_UpdateConfig(t *testing.T) {
+	hub := newWatchHub(&WatchConfig{BufferSize: 4, MaxWatchers: 1})
+	for i := 1; i <= 6; i++ {
+		publishTestEvent(hub, model.RService, "svc", fmt.Sprintf("rev-%d", i))
+	}
+
+	hub.updateConfig(&WatchConfig{BufferSize: 2, MaxWatchers: 2})
+	watcher, code := hub.add(&api.WatchRequest{Seq: utils.NewUInt64Value(4)}, nil)
+	if code != api.ExecuteSuccess {
+		t.Fatalf("resume by seq code: %d", code)
+	}
+	defer watcher.Close()
+	events, _ := watcher.Drain(10)
+	if len(events) != 2 || events[0].GetRevision().GetValue() != "rev-5" {
+		t.Fatalf("events after shrink: %+v", events)
+	}
+	if _, code := hub.add(&api.WatchRequest{Seq: utils.NewUInt64Value(3)}, nil); code != api.WatchEventExpired {
+		t.Fatalf("seq 3 should be expired after shrink, got %d", code)
+	}
+	another, code := hub.add(&api.WatchRequest{}, nil)
+	if code != api.ExecuteSuccess {
+		t.Fatalf("max watchers should be updated, got %d", code)
+	}
+	another.Close()
+
+	hub.updateConfig(&WatchConfig{BufferSize: 8, MaxWatchers: 2})
+	publishTestEvent(hub, model.RService, "svc", "rev-7")
+	if hub.size != 3 || len(hub.events) != 8 {
+		t.Fatalf("buffer should keep events after grow, size: %d", hub.size)
+	}
+}
+
+// TestServer_WatchPermission 测试登录用户只能收到有读权限的事件
+func TestServer_WatchPermission(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	authority := authmock.NewMockAuthority(ctl)
+	authority.EXPECT().UserRequired().Return(true).AnyTimes()
+	authority.EXPECT().VerifyUser("reader", gomock.Any()).DoAndReturn(
+		func(_ string, permission *auth.Permission) bool {
+			return permission.Resource == auth.ResourceService && permission.Service == "svc-1"
+		}).AnyTimes()
+	s := &Server{authority: authority, watchHub: newWatchHub(&WatchConfig{})}
+
+	if _, resp := s.Watch(context.Background(), &api.WatchRequest{}); resp.GetCode().GetValue() != api.UserLoginRequired {
+		t.Fatalf("watch without user should be rejected, got %v", resp)
+	}
+	ctx := context.WithValue(context.Background(), utils.StringContext("user"), "reader")
+	watcher, resp := s.Watch(ctx, &api.WatchRequest{})
+	if resp != nil {
+		t.Fatalf("watch with user err: %v", resp)
+	}
+	defer watcher.Close()
+
+	publishTestEvent(s.watchHub, model.RInstance, "svc-1", "rev-1")
+	publishTestEvent(s.watchHub, model.RInstance, "svc-2", "rev-2")
+	publishTestEvent(s.watchHub, model.RRouting, "svc-1", "rev-3")
+	events, _ := watcher.Drain(10)
+	if len(events) != 1 || events[0].GetRevision().GetValue() != "rev-1" {
+		t.Fatalf("watcher should only receive allowed events: %+v", events)
+	}
+}
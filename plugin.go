@@ -18,21 +18,27 @@
 package main
 
 import (
+	_ "github.com/polarismesh/polaris-server/apiserver/dnsserver"
 	_ "github.com/polarismesh/polaris-server/apiserver/grpcserver"
 	_ "github.com/polarismesh/polaris-server/apiserver/httpserver"
 	_ "github.com/polarismesh/polaris-server/apiserver/l5pbserver"
+	_ "github.com/polarismesh/polaris-server/apiserver/xdsserver"
 
 	_ "github.com/polarismesh/polaris-server/naming/cache"
 	_ "github.com/polarismesh/polaris-server/store/boltdbStore"
 	_ "github.com/polarismesh/polaris-server/store/defaultStore"
+	_ "github.com/polarismesh/polaris-server/store/postgresqlStore"
 
+	_ "github.com/polarismesh/polaris-server/plugin/cmdb/file"
 	_ "github.com/polarismesh/polaris-server/plugin/cmdb/memory"
 
 	_ "github.com/polarismesh/polaris-server/plugin/auth/platform"
 	_ "github.com/polarismesh/polaris-server/plugin/discoverStatis/discoverLocal"
 	_ "github.com/polarismesh/polaris-server/plugin/history/logger"
+	_ "github.com/polarismesh/polaris-server/plugin/history/storage"
 	_ "github.com/polarismesh/polaris-server/plugin/parsePassword"
 	_ "github.com/polarismesh/polaris-server/plugin/ratelimit/lrurate"
 	_ "github.com/polarismesh/polaris-server/plugin/ratelimit/tokenBucket"
 	_ "github.com/polarismesh/polaris-server/plugin/statis/local"
+	_ "github.com/polarismesh/polaris-server/plugin/statis/prometheus"
 )
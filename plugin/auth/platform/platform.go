@@ -25,6 +25,7 @@ import (
 
 	"github.com/polarismesh/polaris-server/common/log"
 	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
 	"github.com/polarismesh/polaris-server/plugin"
 	"go.uber.org/zap"
 )
@@ -84,6 +85,10 @@ func (a *Auth) Initialize(conf *plugin.ConfigEntry) error {
 	a.dbType = dbType
 	a.dbSourceName = dbAddr + "/" + dbName
 	a.interval = time.Duration(interval) * time.Second
+	// 客户端IP统一为标准格式，白名单也转换为相同的格式
+	if ip := utils.NormalizeIP(whiteList); ip != "" {
+		whiteList = ip
+	}
 	a.whiteList = whiteList
 	a.ids = new(sync.Map)
 	a.lastMtime = time.Unix(0, 0)
@@ -0,0 +1,348 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/plugin"
+)
+
+const (
+	// PluginName plugin name
+	PluginName = "file"
+
+	// 默认检查本地文件是否修改的间隔，单位为秒
+	defaultInterval = 10
+	// 默认从HTTP接口拉取数据的间隔，单位为秒
+	defaultPullInterval = 60
+	// 默认的HTTP请求超时时间，单位为秒
+	defaultTimeout = 5
+)
+
+// 数据来源
+const (
+	sourceFile = iota
+	sourceURL
+)
+
+// 自注册到插件列表
+func init() {
+	plugin.RegisterPlugin(PluginName, &FileCMDB{})
+}
+
+// 插件的配置项
+type options struct {
+	path         string
+	format       string
+	interval     time.Duration
+	url          string
+	urlFormat    string
+	pullInterval time.Duration
+	timeout      time.Duration
+}
+
+/**
+ * FileCMDB 从本地文件以及HTTP接口加载IP、CIDR与地理位置的对应关系
+ * 本地文件修改后自动重新加载，HTTP接口定期拉取，相同的前缀以HTTP接口的数据为准
+ */
+type FileCMDB struct {
+	mutex       sync.RWMutex
+	data        *table
+	fileEntries []*entry
+	httpEntries []*entry
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// Name 返回插件名
+func (f *FileCMDB) Name() string {
+	return PluginName
+}
+
+// Initialize 初始化函数
+// 支持的配置项：path本地文件，format文件格式（yaml、csv，默认按照扩展名判断），interval文件检查间隔（秒）
+// url HTTP接口，urlFormat接口数据格式（默认yaml），pullInterval拉取间隔（秒），timeout请求超时（秒）
+func (f *FileCMDB) Initialize(c *plugin.ConfigEntry) error {
+	return f.start(c)
+}
+
+// Reload 实现Reloadable接口，重新加载数据成功后才替换原有的配置
+func (f *FileCMDB) Reload(c *plugin.ConfigEntry) error {
+	return f.start(c)
+}
+
+// Destroy 销毁函数
+func (f *FileCMDB) Destroy() error {
+	f.stop()
+	return nil
+}
+
+// GetLocation 实现CMDB插件接口，按照最长前缀匹配查询地理位置
+func (f *FileCMDB) GetLocation(host string) (*model.Location, error) {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, nil
+	}
+	return f.table().tree.lookup(ip), nil
+}
+
+// Range 实现CMDB插件接口，host为配置的IP或者CIDR
+func (f *FileCMDB) Range(handler func(host string, location *model.Location) (bool, error)) error {
+	data := f.table()
+	for _, key := range data.keys {
+		cont, err := handler(key, data.locations[key])
+		if err != nil {
+			return err
+		}
+		if !cont {
+			return nil
+		}
+	}
+	return nil
+}
+
+// Size 实现CMDB插件接口
+func (f *FileCMDB) Size() int32 {
+	return int32(len(f.table().keys))
+}
+
+// 获取当前的数据表
+func (f *FileCMDB) table() *table {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	if f.data == nil {
+		return &table{tree: newRadixTree()}
+	}
+	return f.data
+}
+
+// 加载数据并且启动文件检查以及拉取协程，加载失败时保持原有的数据
+func (f *FileCMDB) start(c *plugin.ConfigEntry) error {
+	opts, err := parseOptions(c)
+	if err != nil {
+		return err
+	}
+
+	var fileEntries, httpEntries []*entry
+	var digest string
+	if opts.path != "" {
+		if fileEntries, digest, err = loadFile(opts); err != nil {
+			log.Errorf("[CMDB][File] load file(%s) err: %s", opts.path, err.Error())
+			return err
+		}
+	}
+	client := &http.Client{Timeout: opts.timeout}
+	if opts.url != "" {
+		// 启动时接口不可用，不影响server启动，等待下次拉取
+		if httpEntries, err = loadURL(client, opts); err != nil {
+			log.Errorf("[CMDB][File] pull url(%s) err: %s", opts.url, err.Error())
+		}
+	}
+	data, err := buildTable(fileEntries, httpEntries)
+	if err != nil {
+		log.Errorf("[CMDB][File] build table err: %s", err.Error())
+		return err
+	}
+
+	f.stop()
+	f.mutex.Lock()
+	f.data = data
+	f.fileEntries = fileEntries
+	f.httpEntries = httpEntries
+	f.mutex.Unlock()
+
+	f.stopCh = make(chan struct{})
+	if opts.path != "" {
+		f.wg.Add(1)
+		go f.watchFile(opts, digest)
+	}
+	if opts.url != "" {
+		f.wg.Add(1)
+		go f.pullURL(client, opts)
+	}
+	log.Infof("[CMDB][File] load %d entries, path: %s, url: %s", len(data.keys), opts.path, opts.url)
+	return nil
+}
+
+// 停止文件检查以及拉取协程
+func (f *FileCMDB) stop() {
+	if f.stopCh == nil {
+		return
+	}
+	close(f.stopCh)
+	f.wg.Wait()
+	f.stopCh = nil
+}
+
+// 定期检查本地文件，内容修改后重新加载
+func (f *FileCMDB) watchFile(opts *options, digest string) {
+	defer f.wg.Done()
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			digest = f.reloadFile(opts, digest)
+		case <-f.stopCh:
+			return
+		}
+	}
+}
+
+// 重新加载本地文件，返回最新的摘要，文件内容不合法时保持原有的数据
+func (f *FileCMDB) reloadFile(opts *options, digest string) string {
+	data, current, err := readFile(opts.path)
+	if err != nil {
+		log.Errorf("[CMDB][File] read file(%s) err: %s", opts.path, err.Error())
+		return digest
+	}
+	if current == digest {
+		return digest
+	}
+	entries, err := decodeEntries(data, opts.format)
+	if err != nil {
+		log.Errorf("[CMDB][File] load file(%s) err: %s", opts.path, err.Error())
+		return current
+	}
+	f.update(sourceFile, entries)
+	log.Infof("[CMDB][File] file(%s) is modified, reload %d entries", opts.path, len(entries))
+	return current
+}
+
+// 定期从HTTP接口拉取数据
+func (f *FileCMDB) pullURL(client *http.Client, opts *options) {
+	defer f.wg.Done()
+	ticker := time.NewTicker(opts.pullInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			f.reloadURL(client, opts)
+		case <-f.stopCh:
+			return
+		}
+	}
+}
+
+// 重新拉取HTTP接口的数据，拉取失败时保持原有的数据
+func (f *FileCMDB) reloadURL(client *http.Client, opts *options) {
+	entries, err := loadURL(client, opts)
+	if err != nil {
+		log.Errorf("[CMDB][File] pull url(%s) err: %s", opts.url, err.Error())
+		return
+	}
+	f.update(sourceURL, entries)
+}
+
+// 替换指定数据源的数据，并且重建数据表
+func (f *FileCMDB) update(source int, entries []*entry) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	fileEntries, httpEntries := f.fileEntries, f.httpEntries
+	if source == sourceFile {
+		fileEntries = entries
+	} else {
+		httpEntries = entries
+	}
+	data, err := buildTable(fileEntries, httpEntries)
+	if err != nil {
+		log.Errorf("[CMDB][File] build table err: %s", err.Error())
+		return
+	}
+	f.data = data
+	f.fileEntries = fileEntries
+	f.httpEntries = httpEntries
+}
+
+// 加载本地文件
+func loadFile(opts *options) ([]*entry, string, error) {
+	data, digest, err := readFile(opts.path)
+	if err != nil {
+		return nil, "", err
+	}
+	entries, err := decodeEntries(data, opts.format)
+	if err != nil {
+		return nil, digest, err
+	}
+	return entries, digest, nil
+}
+
+// 拉取HTTP接口的数据
+func loadURL(client *http.Client, opts *options) ([]*entry, error) {
+	data, err := fetch(client, opts.url)
+	if err != nil {
+		return nil, err
+	}
+	return decodeEntries(data, opts.urlFormat)
+}
+
+// 解析数据并且检查IP、CIDR是否合法
+func decodeEntries(data []byte, format string) ([]*entry, error) {
+	entries, err := parseEntries(data, format)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := buildTable(entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// 解析插件配置
+func parseOptions(c *plugin.ConfigEntry) (*options, error) {
+	opts := &options{
+		interval:     defaultInterval * time.Second,
+		urlFormat:    FormatYAML,
+		pullInterval: defaultPullInterval * time.Second,
+		timeout:      defaultTimeout * time.Second,
+	}
+	if c == nil || c.Option == nil {
+		return nil, errors.New("cmdb file plugin need path or url option")
+	}
+	opts.path, _ = c.Option["path"].(string)
+	opts.url, _ = c.Option["url"].(string)
+	if opts.path == "" && opts.url == "" {
+		return nil, errors.New("cmdb file plugin need path or url option")
+	}
+
+	opts.format = detectFormat(opts.path)
+	if format, _ := c.Option["format"].(string); format != "" {
+		opts.format = format
+	}
+	if format, _ := c.Option["urlFormat"].(string); format != "" {
+		opts.urlFormat = format
+	}
+	if value, ok := c.Option["interval"].(int); ok && value > 0 {
+		opts.interval = time.Duration(value) * time.Second
+	}
+	if value, ok := c.Option["pullInterval"].(int); ok && value > 0 {
+		opts.pullInterval = time.Duration(value) * time.Second
+	}
+	if value, ok := c.Option["timeout"].(int); ok && value > 0 {
+		opts.timeout = time.Duration(value) * time.Second
+	}
+	return opts, nil
+}
@@ -0,0 +1,169 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/plugin"
+)
+
+func newTestCMDB(t *testing.T, option map[string]interface{}) *FileCMDB {
+	cmdb := &FileCMDB{}
+	if err := cmdb.Initialize(&plugin.ConfigEntry{Name: PluginName, Option: option}); err != nil {
+		t.Fatalf("initialize err: %s", err.Error())
+	}
+	return cmdb
+}
+
+func assertCampus(t *testing.T, cmdb *FileCMDB, host string, campus string) {
+	location, err := cmdb.GetLocation(host)
+	if err != nil {
+		t.Fatalf("get location(%s) err: %s", host, err.Error())
+	}
+	if got := campusOf(location); got != campus {
+		t.Fatalf("host(%s) should be campus(%s), got %s", host, campus, got)
+	}
+}
+
+// TestFileCMDB_File 测试从yaml、csv文件加载以及文件修改后重新加载
+func TestFileCMDB_File(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cmdb")
+	if err != nil {
+		t.Fatalf("create temp dir err: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cmdb.csv")
+	data := "ip,region,zone,campus\n# comment\n10.0.0.0/8,south,sz,sz-1\n10.1.0.0/16, south, sz, sz-2\n"
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("write file err: %s", err.Error())
+	}
+	cmdb := newTestCMDB(t, map[string]interface{}{"path": path})
+	defer cmdb.Destroy()
+
+	assertCampus(t, cmdb, "10.2.0.1", "sz-1")
+	assertCampus(t, cmdb, "10.1.0.1", "sz-2")
+	assertCampus(t, cmdb, "127.0.0.1", "")
+	assertCampus(t, cmdb, "not-an-ip", "")
+	if cmdb.Size() != 2 {
+		t.Fatalf("size should be 2, got %d", cmdb.Size())
+	}
+
+	opts, _ := parseOptions(&plugin.ConfigEntry{Option: map[string]interface{}{"path": path}})
+	_, digest, _ := readFile(path)
+	if err := ioutil.WriteFile(path, []byte("10.0.0.0/8,north,bj,bj-1\n"), 0644); err != nil {
+		t.Fatalf("write file err: %s", err.Error())
+	}
+	digest = cmdb.reloadFile(opts, digest)
+	assertCampus(t, cmdb, "10.1.0.1", "bj-1")
+
+	// 文件内容不合法时保持原有的数据
+	if err := ioutil.WriteFile(path, []byte("10.0.0/8,north,bj,bj-2\n"), 0644); err != nil {
+		t.Fatalf("write file err: %s", err.Error())
+	}
+	cmdb.reloadFile(opts, digest)
+	assertCampus(t, cmdb, "10.1.0.1", "bj-1")
+
+	yamlPath := filepath.Join(dir, "cmdb.yaml")
+	data = "- ip: 192.168.0.0/16\n  region: east\n  zone: sh\n  campus: sh-1\n- ip: 2001:db8::/32\n  campus: v6\n"
+	if err := ioutil.WriteFile(yamlPath, []byte(data), 0644); err != nil {
+		t.Fatalf("write file err: %s", err.Error())
+	}
+	if err := cmdb.Reload(&plugin.ConfigEntry{Option: map[string]interface{}{"path": yamlPath}}); err != nil {
+		t.Fatalf("reload err: %s", err.Error())
+	}
+	assertCampus(t, cmdb, "192.168.1.1", "sh-1")
+	assertCampus(t, cmdb, "2001:db8::1", "v6")
+	assertCampus(t, cmdb, "10.1.0.1", "")
+
+	if err := cmdb.Reload(&plugin.ConfigEntry{Option: map[string]interface{}{"path": path}}); err == nil {
+		t.Fatalf("reload with invalid file should fail")
+	}
+	assertCampus(t, cmdb, "192.168.1.1", "sh-1")
+}
+
+// TestFileCMDB_URL 测试从HTTP接口拉取数据，相同的前缀以接口的数据为准
+func TestFileCMDB_URL(t *testing.T) {
+	body := "- ip: 10.0.0.0/8\n  campus: remote\n- ip: 172.16.0.1\n  campus: host\n"
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "cmdb")
+	if err != nil {
+		t.Fatalf("create temp dir err: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cmdb.yaml")
+	if err := ioutil.WriteFile(path, []byte("[{ip: 10.0.0.0/8, campus: local}, {ip: 11.0.0.0/8, campus: local}]"),
+		0644); err != nil {
+		t.Fatalf("write file err: %s", err.Error())
+	}
+
+	option := map[string]interface{}{"path": path, "url": server.URL}
+	cmdb := newTestCMDB(t, option)
+	defer cmdb.Destroy()
+	assertCampus(t, cmdb, "10.0.0.1", "remote")
+	assertCampus(t, cmdb, "11.0.0.1", "local")
+	assertCampus(t, cmdb, "172.16.0.1", "host")
+
+	hosts := make(map[string]string)
+	_ = cmdb.Range(func(host string, location *model.Location) (bool, error) {
+		hosts[host] = location.Proto.GetCampus().GetValue()
+		return true, nil
+	})
+	if len(hosts) != 3 || hosts["10.0.0.0/8"] != "remote" {
+		t.Fatalf("range result: %+v", hosts)
+	}
+
+	// 拉取失败时保持原有的数据
+	opts, _ := parseOptions(&plugin.ConfigEntry{Option: option})
+	status = http.StatusInternalServerError
+	cmdb.reloadURL(http.DefaultClient, opts)
+	assertCampus(t, cmdb, "172.16.0.1", "host")
+
+	status = http.StatusOK
+	body = "[]"
+	cmdb.reloadURL(http.DefaultClient, opts)
+	assertCampus(t, cmdb, "10.0.0.1", "local")
+	assertCampus(t, cmdb, "172.16.0.1", "")
+}
+
+// TestFileCMDB_Options 测试缺少数据源时初始化失败
+func TestFileCMDB_Options(t *testing.T) {
+	cmdb := &FileCMDB{}
+	if err := cmdb.Initialize(&plugin.ConfigEntry{Option: map[string]interface{}{}}); err == nil {
+		t.Fatalf("initialize without path and url should fail")
+	}
+	if cmdb.Size() != 0 {
+		t.Fatalf("size should be 0")
+	}
+	if location, err := cmdb.GetLocation("10.0.0.1"); location != nil || err != nil {
+		t.Fatalf("empty cmdb should not find host")
+	}
+}
@@ -0,0 +1,171 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// FormatYAML yaml格式的数据，json格式也可以按照yaml解析
+	FormatYAML = "yaml"
+	// FormatCSV csv格式的数据，每行依次为ip,region,zone,campus
+	FormatCSV = "csv"
+)
+
+// 数据中的一条记录，ip可以是单个IP或者CIDR
+type entry struct {
+	IP     string `yaml:"ip"`
+	Region string `yaml:"region"`
+	Zone   string `yaml:"zone"`
+	Campus string `yaml:"campus"`
+}
+
+/**
+ * @brief 地理位置数据表，构建完成后只读，更新时整体替换
+ */
+type table struct {
+	tree      *radixTree
+	keys      []string
+	locations map[string]*model.Location
+}
+
+// 根据文件的扩展名判断数据格式，默认为yaml
+func detectFormat(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return FormatCSV
+	}
+	return FormatYAML
+}
+
+// 按照格式解析数据
+func parseEntries(data []byte, format string) ([]*entry, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(data)
+	case FormatYAML:
+		var entries []*entry
+		if err := yaml.Unmarshal(data, &entries); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	default:
+		return nil, fmt.Errorf("format(%s) is not supported", format)
+	}
+}
+
+// 解析csv数据，支持#开头的注释行，第一列为ip时认为是表头
+func parseCSV(data []byte) ([]*entry, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var entries []*entry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "ip") {
+			continue
+		}
+		for len(record) < 4 {
+			record = append(record, "")
+		}
+		entries = append(entries, &entry{
+			IP:     strings.TrimSpace(record[0]),
+			Region: strings.TrimSpace(record[1]),
+			Zone:   strings.TrimSpace(record[2]),
+			Campus: strings.TrimSpace(record[3]),
+		})
+	}
+	return entries, nil
+}
+
+// 读取本地文件，同时返回内容的摘要，用于判断文件是否修改
+func readFile(path string) ([]byte, string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, fmt.Sprintf("%x", sha1.Sum(data)), nil
+}
+
+// 从HTTP接口拉取数据
+func fetch(client *http.Client, url string) ([]byte, error) {
+	rsp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get %s status: %s", url, rsp.Status)
+	}
+	return ioutil.ReadAll(rsp.Body)
+}
+
+// 使用多个数据源构建数据表，后面的数据源覆盖前面相同的前缀
+func buildTable(sources ...[]*entry) (*table, error) {
+	t := &table{
+		tree:      newRadixTree(),
+		locations: make(map[string]*model.Location),
+	}
+	for _, entries := range sources {
+		for _, item := range entries {
+			if item == nil {
+				continue
+			}
+			name := strings.TrimSpace(item.IP)
+			key, bits, err := parsePrefix(name)
+			if err != nil {
+				return nil, err
+			}
+			location := &model.Location{
+				Proto: &api.Location{
+					Region: utils.NewStringValue(item.Region),
+					Zone:   utils.NewStringValue(item.Zone),
+					Campus: utils.NewStringValue(item.Campus),
+				},
+				Valid: true,
+			}
+			t.tree.insert(key, bits, location)
+			if _, ok := t.locations[name]; !ok {
+				t.keys = append(t.keys, name)
+			}
+			t.locations[name] = location
+		}
+	}
+	return t, nil
+}
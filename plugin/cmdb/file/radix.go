@@ -0,0 +1,177 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/polarismesh/polaris-server/common/model"
+)
+
+const (
+	// 统一转换为16字节的IPv6地址，IPv4地址的前缀长度需要加上96
+	ipBits   = 128
+	ipv4Bits = 96
+)
+
+// radix树的节点，节点保存的前缀为key的前bits位
+type radixNode struct {
+	key      net.IP
+	bits     int
+	location *model.Location
+	children [2]*radixNode
+}
+
+/**
+ * @brief 按照IP前缀组织的二叉radix树，路径经过压缩
+ * 查询时返回最长前缀匹配的地理位置
+ */
+type radixTree struct {
+	root *radixNode
+	size int
+}
+
+// 新建radix树
+func newRadixTree() *radixTree {
+	return &radixTree{root: &radixNode{key: make(net.IP, net.IPv6len)}}
+}
+
+// 解析IP或者CIDR，返回16字节的前缀以及前缀长度
+func parsePrefix(value string) (net.IP, int, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, 0, fmt.Errorf("ip(%s) is invalid", value)
+		}
+		return ip.To16(), ipBits, nil
+	}
+
+	ip, ipNet, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, 0, fmt.Errorf("cidr(%s) is invalid", value)
+	}
+	// 按照掩码的长度区分地址族，::ffff:a.b.c.d/n形式的前缀长度已经是IPv6的长度
+	ones, _ := ipNet.Mask.Size()
+	if len(ipNet.Mask) == net.IPv4len {
+		ones += ipv4Bits
+	}
+	if ones > ipBits {
+		return nil, 0, fmt.Errorf("cidr(%s) is invalid", value)
+	}
+	return maskIP(ip.To16(), ones), ones, nil
+}
+
+// 只保留IP的前bits位
+func maskIP(ip net.IP, bits int) net.IP {
+	out := make(net.IP, net.IPv6len)
+	for i := 0; i < net.IPv6len; i++ {
+		switch {
+		case bits >= (i+1)*8:
+			out[i] = ip[i]
+		case bits > i*8:
+			out[i] = ip[i] & (0xff << uint(8-(bits-i*8)))
+		}
+	}
+	return out
+}
+
+// 获取IP第index位的值
+func bitAt(ip net.IP, index int) int {
+	return int(ip[index/8]>>uint(7-index%8)) & 1
+}
+
+// 计算两个IP在前max位中相同前缀的长度
+func commonPrefixLen(a net.IP, b net.IP, max int) int {
+	for i := 0; i < max; i++ {
+		if bitAt(a, i) != bitAt(b, i) {
+			return i
+		}
+	}
+	return max
+}
+
+// 插入前缀，前缀已经存在时覆盖原有的地理位置
+func (t *radixTree) insert(key net.IP, bits int, location *model.Location) {
+	node := t.root
+	for {
+		if node.bits == bits {
+			if node.location == nil {
+				t.size++
+			}
+			node.location = location
+			return
+		}
+
+		branch := bitAt(key, node.bits)
+		child := node.children[branch]
+		if child == nil {
+			node.children[branch] = &radixNode{key: key, bits: bits, location: location}
+			t.size++
+			return
+		}
+
+		max := child.bits
+		if bits < max {
+			max = bits
+		}
+		common := commonPrefixLen(child.key, key, max)
+		if common == child.bits {
+			node = child
+			continue
+		}
+
+		// 子节点与新前缀部分相同，拆分出公共前缀的中间节点
+		middle := &radixNode{key: maskIP(key, common), bits: common}
+		middle.children[bitAt(child.key, common)] = child
+		node.children[branch] = middle
+		if common == bits {
+			middle.location = location
+		} else {
+			middle.children[bitAt(key, common)] = &radixNode{key: key, bits: bits, location: location}
+		}
+		t.size++
+		return
+	}
+}
+
+// 最长前缀匹配，没有匹配的前缀返回nil
+func (t *radixTree) lookup(ip net.IP) *model.Location {
+	key := ip.To16()
+	if key == nil {
+		return nil
+	}
+
+	var found *model.Location
+	node := t.root
+	for node != nil {
+		if commonPrefixLen(node.key, key, node.bits) < node.bits {
+			break
+		}
+		if node.location != nil {
+			found = node.location
+		}
+		if node.bits == ipBits {
+			break
+		}
+		node = node.children[bitAt(key, node.bits)]
+	}
+	return found
+}
@@ -0,0 +1,124 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"net"
+	"testing"
+
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+)
+
+func newTestLocation(campus string) *model.Location {
+	return &model.Location{Proto: &api.Location{Campus: utils.NewStringValue(campus)}, Valid: true}
+}
+
+func campusOf(location *model.Location) string {
+	if location == nil {
+		return ""
+	}
+	return location.Proto.GetCampus().GetValue()
+}
+
+// TestRadixTree_Lookup 测试最长前缀匹配
+func TestRadixTree_Lookup(t *testing.T) {
+	tree := newRadixTree()
+	for _, item := range []struct {
+		prefix string
+		campus string
+	}{
+		{"10.0.0.0/8", "a"},
+		{"10.1.0.0/16", "b"},
+		{"10.1.2.0/24", "c"},
+		{"10.1.2.3", "d"},
+		{"10.128.0.0/9", "e"},
+		{"192.168.0.0/16", "f"},
+		{"2001:db8::/32", "g"},
+		{"2001:db8:1::/48", "h"},
+	} {
+		key, bits, err := parsePrefix(item.prefix)
+		if err != nil {
+			t.Fatalf("parse prefix(%s) err: %s", item.prefix, err.Error())
+		}
+		tree.insert(key, bits, newTestLocation(item.campus))
+	}
+	if tree.size != 8 {
+		t.Fatalf("tree size should be 8, got %d", tree.size)
+	}
+
+	for ip, campus := range map[string]string{
+		"10.2.0.1":      "a",
+		"10.1.3.1":      "b",
+		"10.1.2.4":      "c",
+		"10.1.2.3":      "d",
+		"10.200.0.1":    "e",
+		"192.168.10.10": "f",
+		"2001:db8:2::1": "g",
+		"2001:db8:1::1": "h",
+		"11.0.0.1":      "",
+		"2001:db9::1":   "",
+	} {
+		if got := campusOf(tree.lookup(net.ParseIP(ip))); got != campus {
+			t.Fatalf("ip(%s) should match campus(%s), got %s", ip, campus, got)
+		}
+	}
+
+	// 相同的前缀覆盖原有的地理位置
+	key, bits, _ := parsePrefix("10.1.0.0/16")
+	tree.insert(key, bits, newTestLocation("x"))
+	if tree.size != 8 || campusOf(tree.lookup(net.ParseIP("10.1.3.1"))) != "x" {
+		t.Fatalf("prefix should be overwritten")
+	}
+}
+
+// TestParsePrefix 测试非法的IP以及CIDR
+func TestParsePrefix(t *testing.T) {
+	for _, value := range []string{"", "10.0.0", "10.0.0.0/33", "::ffff:10.0.0.0/129", "host.example.com"} {
+		if _, _, err := parsePrefix(value); err == nil {
+			t.Fatalf("prefix(%s) should be invalid", value)
+		}
+	}
+	key, bits, err := parsePrefix("10.1.2.3/16")
+	if err != nil || bits != 112 || !key.Equal(net.ParseIP("10.1.0.0")) {
+		t.Fatalf("prefix should be masked, got %s/%d", key, bits)
+	}
+
+	// IPv4映射的IPv6前缀按照IPv6的前缀长度处理
+	for value, expect := range map[string]int{
+		"::ffff:10.1.0.0/112": 112,
+		"::ffff:10.1.2.3/128": 128,
+		"::ffff:0.0.0.0/96":   96,
+		"10.1.2.3/32":         128,
+	} {
+		key, bits, err := parsePrefix(value)
+		if err != nil || bits != expect {
+			t.Fatalf("prefix(%s) should have %d bits, got %d, err: %v", value, expect, bits, err)
+		}
+		if expect == 112 && !key.Equal(net.ParseIP("10.1.0.0")) {
+			t.Fatalf("prefix(%s) should be 10.1.0.0, got %s", value, key)
+		}
+	}
+	tree := newRadixTree()
+	key, bits, _ = parsePrefix("::ffff:10.1.0.0/112")
+	tree.insert(key, bits, newTestLocation("a"))
+	if campusOf(tree.lookup(net.ParseIP("10.1.2.3"))) != "a" || tree.lookup(net.ParseIP("10.2.0.1")) != nil {
+		t.Fatalf("ipv4 mapped prefix should match ipv4 address")
+	}
+}
@@ -0,0 +1,217 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package storage
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/plugin"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 把操作记录持久化到存储层中，支持通过控制台接口查询
+const (
+	// PluginName plugin name
+	PluginName = "HistoryStore"
+
+	// 默认的写入队列长度
+	defaultQueueSize = 10240
+	// 默认的操作记录保留天数
+	defaultRetention = 30
+	// 默认的过期记录清理间隔，单位为分钟
+	defaultCleanInterval = 60
+)
+
+// 初始化注册函数
+func init() {
+	plugin.RegisterPlugin(PluginName, &HistoryStore{})
+}
+
+// HistoryStore 存储层历史记录插件
+type HistoryStore struct {
+	storage       store.Store
+	queue         chan *model.History
+	retention     time.Duration
+	cleanInterval time.Duration
+	marshaler     *jsonpb.Marshaler
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// Name 返回插件名字
+func (h *HistoryStore) Name() string {
+	return PluginName
+}
+
+// Initialize 插件初始化
+// 支持的配置项：queueSize写入队列长度，retention保留天数（0表示不清理），cleanInterval清理间隔（分钟）
+func (h *HistoryStore) Initialize(c *plugin.ConfigEntry) error {
+	storage, err := store.GetStore()
+	if err != nil {
+		return err
+	}
+	h.storage = storage
+
+	queueSize := parseIntOption(c, "queueSize", defaultQueueSize)
+	retention := parseIntOption(c, "retention", defaultRetention)
+	cleanInterval := parseIntOption(c, "cleanInterval", defaultCleanInterval)
+	if cleanInterval <= 0 {
+		cleanInterval = defaultCleanInterval
+	}
+
+	h.queue = make(chan *model.History, queueSize)
+	h.retention = time.Duration(retention) * 24 * time.Hour
+	h.cleanInterval = time.Duration(cleanInterval) * time.Minute
+	h.marshaler = &jsonpb.Marshaler{OrigName: true}
+	h.stopCh = make(chan struct{})
+
+	h.wg.Add(1)
+	go h.write()
+	if h.retention > 0 {
+		h.wg.Add(1)
+		go h.clean()
+	}
+	return nil
+}
+
+// Destroy 销毁插件，等待队列中的记录写入完成
+func (h *HistoryStore) Destroy() error {
+	if h.stopCh == nil {
+		return nil
+	}
+	close(h.stopCh)
+	h.wg.Wait()
+	return nil
+}
+
+// Record 把操作记录放入写入队列，队列满时丢弃
+func (h *HistoryStore) Record(entry *model.RecordEntry) {
+	if entry == nil {
+		return
+	}
+	history := &model.History{
+		ResourceType:  string(entry.ResourceType),
+		OperationType: string(entry.OperationType),
+		Namespace:     entry.Namespace,
+		Service:       entry.Service,
+		Context:       entry.Context,
+		Operator:      entry.Operator,
+		Revision:      entry.Revision,
+		Before:        h.snapshot(entry.Before),
+		After:         h.snapshot(entry.Object),
+		CreateTime:    entry.CreateTime,
+	}
+	if model.GetResourceType(entry.ResourceType) == model.MeshType {
+		history.Service = entry.MeshName
+	}
+	if history.CreateTime.IsZero() {
+		history.CreateTime = time.Now()
+	}
+
+	select {
+	case h.queue <- history:
+	default:
+		log.Errorf("[History][Store] queue is full, history(%s, %s, %s, %s) is dropped",
+			history.ResourceType, history.OperationType, history.Namespace, history.Service)
+	}
+}
+
+// 把资源序列化为json快照，资源为空时返回空字符串
+func (h *HistoryStore) snapshot(object proto.Message) string {
+	if object == nil {
+		return ""
+	}
+	if value := reflect.ValueOf(object); value.Kind() == reflect.Ptr && value.IsNil() {
+		return ""
+	}
+	str, err := h.marshaler.MarshalToString(object)
+	if err != nil {
+		log.Errorf("[History][Store] marshal snapshot err: %s", err.Error())
+		return ""
+	}
+	return str
+}
+
+// 异步写入操作记录
+func (h *HistoryStore) write() {
+	defer h.wg.Done()
+	for {
+		select {
+		case history := <-h.queue:
+			h.add(history)
+		case <-h.stopCh:
+			// 退出前把队列中剩余的记录写完
+			for {
+				select {
+				case history := <-h.queue:
+					h.add(history)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (h *HistoryStore) add(history *model.History) {
+	if err := h.storage.AddHistory(history); err != nil {
+		log.Errorf("[History][Store] add history(%s, %s, %s, %s) err: %s", history.ResourceType,
+			history.OperationType, history.Namespace, history.Service, err.Error())
+	}
+}
+
+// 定期清理超过保留时间的操作记录
+func (h *HistoryStore) clean() {
+	defer h.wg.Done()
+	ticker := time.NewTicker(h.cleanInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			before := time.Now().Add(-h.retention)
+			count, err := h.storage.DeleteHistories(before)
+			if err != nil {
+				log.Errorf("[History][Store] delete histories before(%s) err: %s", before, err.Error())
+				continue
+			}
+			if count > 0 {
+				log.Infof("[History][Store] delete %d expired histories before(%s)", count, before)
+			}
+		case <-h.stopCh:
+			return
+		}
+	}
+}
+
+// 读取整型配置项，不存在时返回默认值
+func parseIntOption(c *plugin.ConfigEntry, key string, defaultValue int) int {
+	if c == nil || c.Option == nil {
+		return defaultValue
+	}
+	value, ok := c.Option[key].(int)
+	if !ok {
+		return defaultValue
+	}
+	return value
+}
@@ -51,3 +51,12 @@ func GetParsePassword() ParsePassword {
 
 	return plugin.(ParsePassword)
 }
+
+// ParseSecret 使用密码插件解析配置中的密码、密钥等敏感信息，没有配置密码插件时原样返回
+func ParseSecret(cipher string) (string, error) {
+	parser := GetParsePassword()
+	if parser == nil || cipher == "" {
+		return cipher, nil
+	}
+	return parser.ParsePassword(cipher)
+}
@@ -0,0 +1,156 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package parseCode
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/polarismesh/polaris-server/plugin"
+)
+
+const (
+	// AESPluginName AES-GCM解密插件
+	AESPluginName = "aesParse"
+
+	// DefaultKeyEnv 默认保存密钥的环境变量
+	DefaultKeyEnv = "POLARIS_PASSWORD_KEY"
+)
+
+// 初始化注册函数
+func init() {
+	plugin.RegisterPlugin(AESPluginName, &AESPassword{})
+}
+
+/**
+ * AESPassword 使用AES-GCM解密配置中的密码
+ * 密文为base64编码的nonce与加密数据，密钥为base64编码的16、24或者32字节
+ */
+type AESPassword struct {
+	aead cipher.AEAD
+}
+
+// Name 返回插件名字
+func (p *AESPassword) Name() string {
+	return AESPluginName
+}
+
+// Destroy 销毁插件
+func (p *AESPassword) Destroy() error {
+	return nil
+}
+
+// Initialize 插件初始化
+// 支持的配置项：keyFile密钥文件，keyEnv保存密钥的环境变量（默认为POLARIS_PASSWORD_KEY），优先使用keyFile
+func (p *AESPassword) Initialize(c *plugin.ConfigEntry) error {
+	keyFile, _ := c.Option["keyFile"].(string)
+	keyEnv, _ := c.Option["keyEnv"].(string)
+	key, err := LoadKey(keyFile, keyEnv)
+	if err != nil {
+		return err
+	}
+	p.aead, err = newAEAD(key)
+	return err
+}
+
+// ParsePassword 解密密码
+func (p *AESPassword) ParsePassword(cipherText string) (string, error) {
+	if p.aead == nil {
+		return "", errors.New("aes password plugin is not initialized")
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(cipherText))
+	if err != nil {
+		return "", fmt.Errorf("password is not base64 encoded: %s", err.Error())
+	}
+	nonceSize := p.aead.NonceSize()
+	if len(data) < nonceSize {
+		return "", errors.New("password cipher is too short")
+	}
+	plain, err := p.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt password err: %s", err.Error())
+	}
+	return string(plain), nil
+}
+
+/**
+ * LoadKey 从密钥文件或者环境变量读取密钥
+ * 密钥文件不为空时从文件读取，否则从环境变量读取，环境变量为空时使用DefaultKeyEnv
+ */
+func LoadKey(keyFile string, keyEnv string) ([]byte, error) {
+	var encoded string
+	if keyFile != "" {
+		data, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read key file(%s) err: %s", keyFile, err.Error())
+		}
+		encoded = string(data)
+	} else {
+		if keyEnv == "" {
+			keyEnv = DefaultKeyEnv
+		}
+		encoded = os.Getenv(keyEnv)
+		if encoded == "" {
+			return nil, fmt.Errorf("key env(%s) is empty", keyEnv)
+		}
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("key is not base64 encoded: %s", err.Error())
+	}
+	return key, nil
+}
+
+/**
+ * EncryptPassword 使用AES-GCM加密密码，返回可以写入配置文件的密文
+ */
+func EncryptPassword(key []byte, password string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	data := aead.Seal(nonce, nonce, []byte(password), nil)
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// 根据密钥创建AES-GCM对象
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, fmt.Errorf("aes key length must be 16, 24 or 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,72 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package parseCode
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/polarismesh/polaris-server/plugin"
+)
+
+const (
+	// EnvPluginName 展开环境变量的插件
+	EnvPluginName = "envParse"
+)
+
+// 初始化注册函数
+func init() {
+	plugin.RegisterPlugin(EnvPluginName, &EnvPassword{})
+}
+
+/**
+ * EnvPassword 展开密码中的环境变量，支持$VAR以及${VAR}
+ * 引用的环境变量不存在时返回错误，避免使用空密码
+ */
+type EnvPassword struct{}
+
+// Name 返回插件名字
+func (p *EnvPassword) Name() string {
+	return EnvPluginName
+}
+
+// Destroy 销毁插件
+func (p *EnvPassword) Destroy() error {
+	return nil
+}
+
+// Initialize 插件初始化
+func (p *EnvPassword) Initialize(c *plugin.ConfigEntry) error {
+	return nil
+}
+
+// ParsePassword 展开环境变量
+func (p *EnvPassword) ParsePassword(cipher string) (string, error) {
+	var missing string
+	password := os.Expand(cipher, func(key string) string {
+		value, ok := os.LookupEnv(key)
+		if !ok && missing == "" {
+			missing = key
+		}
+		return value
+	})
+	if missing != "" {
+		return "", fmt.Errorf("env(%s) is not set", missing)
+	}
+	return password, nil
+}
@@ -0,0 +1,78 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package parseCode
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/polarismesh/polaris-server/plugin"
+)
+
+const (
+	// FilePluginName 从文件读取密码的插件，适用于以文件挂载的kubernetes secret
+	FilePluginName = "fileParse"
+)
+
+// 初始化注册函数
+func init() {
+	plugin.RegisterPlugin(FilePluginName, &FilePassword{})
+}
+
+/**
+ * FilePassword 配置中的密码为文件路径，读取文件的内容作为密码
+ * 相对路径基于配置项dir，文件末尾的换行会被去掉
+ */
+type FilePassword struct {
+	dir string
+}
+
+// Name 返回插件名字
+func (p *FilePassword) Name() string {
+	return FilePluginName
+}
+
+// Destroy 销毁插件
+func (p *FilePassword) Destroy() error {
+	return nil
+}
+
+// Initialize 插件初始化
+// 支持的配置项：dir密码文件所在的目录，例如secret的挂载目录
+func (p *FilePassword) Initialize(c *plugin.ConfigEntry) error {
+	p.dir, _ = c.Option["dir"].(string)
+	return nil
+}
+
+// ParsePassword 读取密码文件
+func (p *FilePassword) ParsePassword(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", nil
+	}
+	if !filepath.IsAbs(path) && p.dir != "" {
+		path = filepath.Join(p.dir, path)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read password file(%s) err: %s", path, err.Error())
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
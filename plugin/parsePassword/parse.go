@@ -46,7 +46,7 @@ func (p *Password) Initialize(c *plugin.ConfigEntry) error {
 	return nil
 }
 
-// ParsePassword 解析密码
+// ParsePassword 解析密码，配置中的密码为明文，原样返回
 func (p *Password) ParsePassword(cipher string) (string, error) {
-	return "", nil
+	return cipher, nil
 }
@@ -0,0 +1,124 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package parseCode
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/polarismesh/polaris-server/plugin"
+)
+
+// TestAESPassword 测试AES-GCM加密后可以通过插件解密
+func TestAESPassword(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	cipher, err := EncryptPassword(key, "polaris@123")
+	if err != nil {
+		t.Fatalf("encrypt err: %s", err.Error())
+	}
+
+	dir, err := ioutil.TempDir("", "password")
+	if err != nil {
+		t.Fatalf("create temp dir err: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	keyFile := filepath.Join(dir, "key")
+	if err := ioutil.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600); err != nil {
+		t.Fatalf("write key file err: %s", err.Error())
+	}
+
+	os.Setenv("POLARIS_TEST_PASSWORD_KEY", base64.StdEncoding.EncodeToString(key))
+	defer os.Unsetenv("POLARIS_TEST_PASSWORD_KEY")
+	for _, option := range []map[string]interface{}{
+		{"keyFile": keyFile},
+		{"keyEnv": "POLARIS_TEST_PASSWORD_KEY"},
+	} {
+		p := &AESPassword{}
+		if err := p.Initialize(&plugin.ConfigEntry{Name: AESPluginName, Option: option}); err != nil {
+			t.Fatalf("initialize with %+v err: %s", option, err.Error())
+		}
+		password, err := p.ParsePassword(cipher)
+		if err != nil || password != "polaris@123" {
+			t.Fatalf("decrypt with %+v: %s, %v", option, password, err)
+		}
+		if _, err := p.ParsePassword("polaris@123"); err == nil {
+			t.Fatalf("plaintext should not be decrypted")
+		}
+	}
+
+	other, _ := EncryptPassword([]byte("fedcba9876543210"), "polaris@123")
+	p := &AESPassword{}
+	_ = p.Initialize(&plugin.ConfigEntry{Option: map[string]interface{}{"keyFile": keyFile}})
+	if _, err := p.ParsePassword(other); err == nil {
+		t.Fatalf("cipher of another key should not be decrypted")
+	}
+
+	option := map[string]interface{}{"keyEnv": "POLARIS_TEST_NOT_EXIST"}
+	if err := p.Initialize(&plugin.ConfigEntry{Option: option}); err == nil {
+		t.Fatalf("initialize without key should fail")
+	}
+	if _, err := EncryptPassword([]byte("short"), "polaris@123"); err == nil {
+		t.Fatalf("invalid key length should fail")
+	}
+}
+
+// TestFilePassword 测试从文件读取密码
+func TestFilePassword(t *testing.T) {
+	dir, err := ioutil.TempDir("", "password")
+	if err != nil {
+		t.Fatalf("create temp dir err: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "db-password"), []byte("polaris@123\n"), 0600); err != nil {
+		t.Fatalf("write password file err: %s", err.Error())
+	}
+
+	p := &FilePassword{}
+	_ = p.Initialize(&plugin.ConfigEntry{Option: map[string]interface{}{"dir": dir}})
+	for _, path := range []string{"db-password", filepath.Join(dir, "db-password")} {
+		if password, err := p.ParsePassword(path); err != nil || password != "polaris@123" {
+			t.Fatalf("read password file(%s): %s, %v", path, password, err)
+		}
+	}
+	if _, err := p.ParsePassword("not-exist"); err == nil {
+		t.Fatalf("not exist file should fail")
+	}
+}
+
+// TestEnvPassword 测试展开环境变量
+func TestEnvPassword(t *testing.T) {
+	os.Setenv("POLARIS_TEST_DB_PWD", "polaris@123")
+	defer os.Unsetenv("POLARIS_TEST_DB_PWD")
+
+	p := &EnvPassword{}
+	for cipher, expect := range map[string]string{
+		"$POLARIS_TEST_DB_PWD":    "polaris@123",
+		"${POLARIS_TEST_DB_PWD}!": "polaris@123!",
+		"plain":                   "plain",
+	} {
+		if password, err := p.ParsePassword(cipher); err != nil || password != expect {
+			t.Fatalf("expand %s: %s, %v", cipher, password, err)
+		}
+	}
+	if _, err := p.ParsePassword("${POLARIS_TEST_NOT_EXIST}"); err == nil {
+		t.Fatalf("not exist env should fail")
+	}
+}
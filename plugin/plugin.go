@@ -19,7 +19,10 @@ package plugin
 
 import (
 	"fmt"
+	"reflect"
 	"sync"
+
+	"github.com/polarismesh/polaris-server/common/log"
 )
 
 var (
@@ -55,6 +58,14 @@ type Plugin interface {
 	Destroy() error
 }
 
+/**
+ * Reloadable 支持热更新配置的插件
+ */
+type Reloadable interface {
+	// Reload 使用新的配置重新初始化插件，失败时保持原有配置
+	Reload(c *ConfigEntry) error
+}
+
 /**
  * ConfigEntry 单个插件配置
  */
@@ -76,3 +87,51 @@ type Config struct {
 	Auth                 ConfigEntry `yaml:"auth"`
 	MeshResourceValidate ConfigEntry `yaml:"meshResourceValidate"`
 }
+
+// 插件配置项的名字与配置的对应关系
+func (c *Config) entries() map[string]*ConfigEntry {
+	return map[string]*ConfigEntry{
+		"cmdb":                 &c.CMDB,
+		"ratelimit":            &c.RateLimit,
+		"history":              &c.History,
+		"statis":               &c.Statis,
+		"discoverStatis":       &c.DiscoverStatis,
+		"parsePassword":        &c.ParsePassword,
+		"auth":                 &c.Auth,
+		"meshResourceValidate": &c.MeshResourceValidate,
+	}
+}
+
+/**
+ * ReloadPluginConfig 热更新插件配置
+ * 插件名字不变并且插件实现了Reloadable时直接生效，否则需要重启
+ * 返回已经生效的配置项，以及需要重启才能生效的配置项
+ */
+func ReloadPluginConfig(c *Config) ([]string, []string, error) {
+	var applied, restart []string
+	running := config.entries()
+	for key, entry := range c.entries() {
+		old := running[key]
+		if reflect.DeepEqual(old, entry) {
+			continue
+		}
+		item := "plugin." + key
+		if old.Name != entry.Name {
+			restart = append(restart, item)
+			continue
+		}
+		plugin, ok := pluginSet[entry.Name].(Reloadable)
+		if !ok {
+			restart = append(restart, item)
+			continue
+		}
+		if err := plugin.Reload(entry); err != nil {
+			log.Errorf("[Plugin] reload plugin(%s) err: %s", entry.Name, err.Error())
+			return applied, restart, fmt.Errorf("reload plugin(%s) err: %s", entry.Name, err.Error())
+		}
+		log.Infof("[Plugin] plugin(%s) is reloaded, option: %+v", entry.Name, entry.Option)
+		*old = *entry
+		applied = append(applied, item)
+	}
+	return applied, restart, nil
+}
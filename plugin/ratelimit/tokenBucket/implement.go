@@ -24,37 +24,50 @@ import (
 
 // 插件初始化函数
 func (tb *tokenBucket) initialize(c *plugin.ConfigEntry) error {
-	config, err := decodeConfig(c.Option)
+	config, limiters, err := newLimiters(c)
 	if err != nil {
-		log.Errorf("[Plugin][%s] initialize err: %s", PluginName, err.Error())
 		return err
 	}
 
+	tb.mutex.Lock()
 	tb.config = config
-	tb.limiters = make(map[plugin.RatelimitType]limiter)
+	tb.limiters = limiters
+	tb.mutex.Unlock()
+	return nil
+}
+
+// 根据插件配置创建各类限流器
+func newLimiters(c *plugin.ConfigEntry) (*Config, map[plugin.RatelimitType]limiter, error) {
+	config, err := decodeConfig(c.Option)
+	if err != nil {
+		log.Errorf("[Plugin][%s] initialize err: %s", PluginName, err.Error())
+		return nil, nil, err
+	}
+
+	limiters := make(map[plugin.RatelimitType]limiter)
 
 	// IP限流
 	irt, err := newResourceRatelimit(plugin.IPRatelimit, config.IPLimitConf)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	tb.limiters[plugin.IPRatelimit] = irt
+	limiters[plugin.IPRatelimit] = irt
 
 	// 接口限流
 	art, err := newAPIRatelimit(config.APILimitConf)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	tb.limiters[plugin.APIRatelimit] = art
+	limiters[plugin.APIRatelimit] = art
 
 	// 操作实例限流
 	instance, err := newResourceRatelimit(plugin.InstanceRatelimit, config.InstanceLimitConf)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	tb.limiters[plugin.InstanceRatelimit] = instance
+	limiters[plugin.InstanceRatelimit] = instance
 
-	return nil
+	return config, limiters, nil
 }
 
 // 插件的限流实现函数
@@ -63,7 +76,9 @@ func (tb *tokenBucket) allow(typ plugin.RatelimitType, key string) bool {
 	if key == "" {
 		return true
 	}
+	tb.mutex.RLock()
 	l, ok := tb.limiters[typ]
+	tb.mutex.RUnlock()
 	if !ok {
 		return true
 	}
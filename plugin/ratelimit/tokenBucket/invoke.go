@@ -18,11 +18,14 @@
 package tokenBucket
 
 import (
+	"sync"
+
 	"github.com/polarismesh/polaris-server/plugin"
 )
 
 // 实现Plugin接口
 type tokenBucket struct {
+	mutex    sync.RWMutex
 	config   *Config
 	limiters map[plugin.RatelimitType]limiter
 }
@@ -37,6 +40,11 @@ func (tb *tokenBucket) Initialize(c *plugin.ConfigEntry) error {
 	return tb.initialize(c)
 }
 
+// 实现Reloadable接口，限流规则修改后替换全部的限流器，令牌桶重新计数
+func (tb *tokenBucket) Reload(c *plugin.ConfigEntry) error {
+	return tb.initialize(c)
+}
+
 // 实现Plugin接口，Destroy方法
 func (tb *tokenBucket) Destroy() error {
 	return nil
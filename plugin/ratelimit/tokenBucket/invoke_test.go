@@ -85,6 +85,34 @@ func TestTokenBucket_Initialize(t *testing.T) {
 	})
 }
 
+// 测试热更新限流配置
+func TestTokenBucket_Reload(t *testing.T) {
+	configEntry := &plugin.ConfigEntry{Name: PluginName}
+	configEntry.Option = baseConfigOption()
+	tb := &tokenBucket{}
+	if err := tb.Initialize(configEntry); err != nil {
+		t.Fatalf("error: %s", err.Error())
+	}
+	Convey("无效配置，返回失败并且保持原有的限流器", t, func() {
+		old := tb.limiters
+		So(tb.Reload(&plugin.ConfigEntry{Name: PluginName, Option: map[string]interface{}{
+			"api-limit": &APILimitConfig{Open: true},
+		}}), ShouldNotBeNil)
+		So(tb.limiters[plugin.IPRatelimit], ShouldEqual, old[plugin.IPRatelimit])
+	})
+	Convey("有效配置，替换全部的限流器", t, func() {
+		option := baseConfigOption()
+		option["ip-limit"] = &ResourceLimitConfig{
+			Open:                   true,
+			Global:                 &BucketRatelimit{true, 1, 1},
+			MaxResourceCacheAmount: 100,
+		}
+		So(tb.Reload(&plugin.ConfigEntry{Name: PluginName, Option: option}), ShouldBeNil)
+		So(tb.Allow(plugin.IPRatelimit, "1.2.3.4"), ShouldEqual, true)
+		So(tb.Allow(plugin.IPRatelimit, "1.2.3.4"), ShouldEqual, false)
+	})
+}
+
 // 测试Allow函数
 func TestTokenBucket_Allow(t *testing.T) {
 	configEntry := &plugin.ConfigEntry{Name: PluginName}
@@ -0,0 +1,112 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package prometheus
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/polarismesh/polaris-server/common/metrics"
+	"github.com/polarismesh/polaris-server/plugin"
+)
+
+const (
+	// PluginName 插件名称
+	PluginName = "prometheus"
+)
+
+/**
+ * @brief 注册统计插件
+ */
+func init() {
+	s := &StatisWorker{}
+	plugin.RegisterPlugin(s.Name(), s)
+}
+
+/**
+ * StatisWorker Prometheus统计插件
+ * 接口调用数据记录到全局的指标注册中心，通过HTTP server的/metrics接口拉取
+ */
+type StatisWorker struct {
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+}
+
+/**
+ * Name 获取统计插件名称
+ */
+func (s *StatisWorker) Name() string {
+	return PluginName
+}
+
+/**
+ * Initialize 初始化统计插件
+ */
+func (s *StatisWorker) Initialize(conf *plugin.ConfigEntry) error {
+	buckets, err := parseBuckets(conf.Option["buckets"])
+	if err != nil {
+		return err
+	}
+
+	registry := metrics.DefaultRegistry()
+	s.requests = registry.NewCounterVec("polaris_api_requests_total",
+		"Total number of api requests by return code.", "api", "code")
+	s.duration = registry.NewHistogramVec("polaris_api_request_duration_seconds",
+		"Latency of api requests in seconds.", buckets, "api")
+	return nil
+}
+
+/**
+ * Destroy 销毁统计插件
+ */
+func (s *StatisWorker) Destroy() error {
+	return nil
+}
+
+/**
+ * AddAPICall 上报请求
+ */
+func (s *StatisWorker) AddAPICall(api string, code int, duration int64) error {
+	s.requests.Inc(api, strconv.Itoa(code))
+	s.duration.Observe(float64(duration)/1e9, api)
+	return nil
+}
+
+// 解析直方图分桶配置，单位为秒
+func parseBuckets(raw interface{}) ([]float64, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	values, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid buckets type: %T", raw)
+	}
+
+	buckets := make([]float64, 0, len(values))
+	for _, value := range values {
+		switch v := value.(type) {
+		case int:
+			buckets = append(buckets, float64(v))
+		case float64:
+			buckets = append(buckets, v)
+		default:
+			return nil, fmt.Errorf("invalid bucket value: %v", value)
+		}
+	}
+	return buckets, nil
+}
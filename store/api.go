@@ -64,6 +64,15 @@ type Store interface {
 
 	// 平台信息接口
 	PlatformStore
+
+	// 操作记录接口
+	HistoryStore
+
+	// 用户、用户组以及角色接口
+	AuthStore
+
+	// 数据变更通知接口
+	ChangeNotifyStore
 }
 
 /**
@@ -370,6 +379,63 @@ type PlatformStore interface {
 	GetPlatforms(query map[string]string, offset uint32, limit uint32) (uint32, []*model.Platform, error)
 }
 
+/**
+ * HistoryStore 操作记录的存储接口
+ */
+type HistoryStore interface {
+	// 新增操作记录
+	AddHistory(history *model.History) error
+
+	// 根据过滤条件以及时间范围查询操作记录，按照时间倒序
+	// filter支持resource、operation、namespace、service、operator，start和end为零值时不限制
+	GetHistories(filter map[string]string, start, end time.Time, offset, limit uint32) (
+		uint32, []*model.History, error)
+
+	// 删除指定时间之前的操作记录，返回删除的数量
+	DeleteHistories(before time.Time) (uint32, error)
+}
+
+/**
+ * AuthStore 用户、用户组以及角色的存储接口，均以名字作为唯一标识
+ */
+type AuthStore interface {
+	// 新增用户
+	AddUser(user *model.User) error
+
+	// 更新用户的密码以及描述
+	UpdateUser(user *model.User) error
+
+	// 删除用户
+	DeleteUser(name string) error
+
+	// 查询全部用户
+	GetUsers() ([]*model.User, error)
+
+	// 新增用户组
+	AddUserGroup(group *model.UserGroup) error
+
+	// 更新用户组的成员以及描述
+	UpdateUserGroup(group *model.UserGroup) error
+
+	// 删除用户组
+	DeleteUserGroup(name string) error
+
+	// 查询全部用户组
+	GetUserGroups() ([]*model.UserGroup, error)
+
+	// 新增角色
+	AddRole(role *model.Role) error
+
+	// 更新角色的成员、权限策略以及描述
+	UpdateRole(role *model.Role) error
+
+	// 删除角色
+	DeleteRole(name string) error
+
+	// 查询全部角色
+	GetRoles() ([]*model.Role, error)
+}
+
 /**
  * Transaction 事务接口，不支持多协程并发操作，当前只支持单个协程串行操作
  */
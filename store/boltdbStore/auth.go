@@ -0,0 +1,334 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package boltdbStore
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+const (
+	tblUser      string = "user"
+	tblUserGroup string = "user_group"
+	tblRole      string = "role"
+)
+
+// 用户组的存储结构，成员以json格式保存
+type userGroupForStore struct {
+	ID         string
+	Name       string
+	Comment    string
+	Users      string
+	CreateTime time.Time
+	ModifyTime time.Time
+}
+
+// 角色的存储结构，成员以及权限策略以json格式保存
+type roleForStore struct {
+	ID         string
+	Name       string
+	Comment    string
+	Users      string
+	Groups     string
+	Policies   string
+	CreateTime time.Time
+	ModifyTime time.Time
+}
+
+type authStore struct {
+	handler BoltHandler
+}
+
+// AddUser 新增用户
+func (a *authStore) AddUser(user *model.User) error {
+	if user.Name == "" {
+		return errors.New("add user missing name")
+	}
+	if exist, err := a.exist(tblUser, user.Name, &model.User{}); err != nil || exist {
+		return duplicateOrError("user", user.Name, err)
+	}
+
+	tNow := time.Now()
+	user.CreateTime = tNow
+	user.ModifyTime = tNow
+	if err := a.handler.SaveValue(tblUser, user.Name, user); err != nil {
+		log.Errorf("[Store][auth] add user(%s) err: %s", user.Name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+// UpdateUser 更新用户
+func (a *authStore) UpdateUser(user *model.User) error {
+	properties := map[string]interface{}{
+		"Password":   user.Password,
+		"Comment":    user.Comment,
+		"ModifyTime": time.Now(),
+	}
+	if err := a.handler.UpdateValue(tblUser, user.Name, properties); err != nil {
+		log.Errorf("[Store][auth] update user(%s) err: %s", user.Name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+// DeleteUser 删除用户
+func (a *authStore) DeleteUser(name string) error {
+	if err := a.handler.DeleteValues(tblUser, []string{name}); err != nil {
+		log.Errorf("[Store][auth] delete user(%s) err: %s", name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+// GetUsers 查询全部用户
+func (a *authStore) GetUsers() ([]*model.User, error) {
+	result, err := a.handler.LoadValuesAll(tblUser, &model.User{})
+	if err != nil {
+		log.Errorf("[Store][auth] get users err: %s", err.Error())
+		return nil, store.Error(err)
+	}
+	out := make([]*model.User, 0, len(result))
+	for _, value := range result {
+		out = append(out, value.(*model.User))
+	}
+	return out, nil
+}
+
+// AddUserGroup 新增用户组
+func (a *authStore) AddUserGroup(group *model.UserGroup) error {
+	if group.Name == "" {
+		return errors.New("add user group missing name")
+	}
+	if exist, err := a.exist(tblUserGroup, group.Name, &userGroupForStore{}); err != nil || exist {
+		return duplicateOrError("user group", group.Name, err)
+	}
+
+	tNow := time.Now()
+	group.CreateTime = tNow
+	group.ModifyTime = tNow
+	users, err := json.Marshal(group.Users)
+	if err != nil {
+		return err
+	}
+	data := &userGroupForStore{
+		ID:         group.ID,
+		Name:       group.Name,
+		Comment:    group.Comment,
+		Users:      string(users),
+		CreateTime: group.CreateTime,
+		ModifyTime: group.ModifyTime,
+	}
+	if err := a.handler.SaveValue(tblUserGroup, group.Name, data); err != nil {
+		log.Errorf("[Store][auth] add user group(%s) err: %s", group.Name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+// UpdateUserGroup 更新用户组
+func (a *authStore) UpdateUserGroup(group *model.UserGroup) error {
+	users, err := json.Marshal(group.Users)
+	if err != nil {
+		return err
+	}
+	properties := map[string]interface{}{
+		"Comment":    group.Comment,
+		"Users":      string(users),
+		"ModifyTime": time.Now(),
+	}
+	if err := a.handler.UpdateValue(tblUserGroup, group.Name, properties); err != nil {
+		log.Errorf("[Store][auth] update user group(%s) err: %s", group.Name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+// DeleteUserGroup 删除用户组
+func (a *authStore) DeleteUserGroup(name string) error {
+	if err := a.handler.DeleteValues(tblUserGroup, []string{name}); err != nil {
+		log.Errorf("[Store][auth] delete user group(%s) err: %s", name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+// GetUserGroups 查询全部用户组
+func (a *authStore) GetUserGroups() ([]*model.UserGroup, error) {
+	result, err := a.handler.LoadValuesAll(tblUserGroup, &userGroupForStore{})
+	if err != nil {
+		log.Errorf("[Store][auth] get user groups err: %s", err.Error())
+		return nil, store.Error(err)
+	}
+	out := make([]*model.UserGroup, 0, len(result))
+	for _, value := range result {
+		data := value.(*userGroupForStore)
+		group := &model.UserGroup{
+			ID:         data.ID,
+			Name:       data.Name,
+			Comment:    data.Comment,
+			CreateTime: data.CreateTime,
+			ModifyTime: data.ModifyTime,
+		}
+		if err := unmarshalAuthField(data.Users, &group.Users); err != nil {
+			log.Errorf("[Store][auth] user group(%s) users is invalid: %s", data.Name, err.Error())
+			return nil, err
+		}
+		out = append(out, group)
+	}
+	return out, nil
+}
+
+// AddRole 新增角色
+func (a *authStore) AddRole(role *model.Role) error {
+	if role.Name == "" {
+		return errors.New("add role missing name")
+	}
+	if exist, err := a.exist(tblRole, role.Name, &roleForStore{}); err != nil || exist {
+		return duplicateOrError("role", role.Name, err)
+	}
+
+	tNow := time.Now()
+	role.CreateTime = tNow
+	role.ModifyTime = tNow
+	data, err := role2Store(role)
+	if err != nil {
+		return err
+	}
+	if err := a.handler.SaveValue(tblRole, role.Name, data); err != nil {
+		log.Errorf("[Store][auth] add role(%s) err: %s", role.Name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+// UpdateRole 更新角色
+func (a *authStore) UpdateRole(role *model.Role) error {
+	data, err := role2Store(role)
+	if err != nil {
+		return err
+	}
+	properties := map[string]interface{}{
+		"Comment":    data.Comment,
+		"Users":      data.Users,
+		"Groups":     data.Groups,
+		"Policies":   data.Policies,
+		"ModifyTime": time.Now(),
+	}
+	if err := a.handler.UpdateValue(tblRole, role.Name, properties); err != nil {
+		log.Errorf("[Store][auth] update role(%s) err: %s", role.Name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+// DeleteRole 删除角色
+func (a *authStore) DeleteRole(name string) error {
+	if err := a.handler.DeleteValues(tblRole, []string{name}); err != nil {
+		log.Errorf("[Store][auth] delete role(%s) err: %s", name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+// GetRoles 查询全部角色
+func (a *authStore) GetRoles() ([]*model.Role, error) {
+	result, err := a.handler.LoadValuesAll(tblRole, &roleForStore{})
+	if err != nil {
+		log.Errorf("[Store][auth] get roles err: %s", err.Error())
+		return nil, store.Error(err)
+	}
+	out := make([]*model.Role, 0, len(result))
+	for _, value := range result {
+		data := value.(*roleForStore)
+		role := &model.Role{
+			ID:         data.ID,
+			Name:       data.Name,
+			Comment:    data.Comment,
+			CreateTime: data.CreateTime,
+			ModifyTime: data.ModifyTime,
+		}
+		if err := unmarshalAuthField(data.Users, &role.Users); err != nil {
+			return nil, err
+		}
+		if err := unmarshalAuthField(data.Groups, &role.Groups); err != nil {
+			return nil, err
+		}
+		if err := unmarshalAuthField(data.Policies, &role.Policies); err != nil {
+			log.Errorf("[Store][auth] role(%s) policies is invalid: %s", data.Name, err.Error())
+			return nil, err
+		}
+		out = append(out, role)
+	}
+	return out, nil
+}
+
+// 判断指定名字的数据是否已经存在
+func (a *authStore) exist(typ string, name string, typObject interface{}) (bool, error) {
+	result, err := a.handler.LoadValues(typ, []string{name}, typObject)
+	if err != nil {
+		log.Errorf("[Store][auth] load %s(%s) err: %s", typ, name, err.Error())
+		return false, store.Error(err)
+	}
+	return len(result) > 0, nil
+}
+
+func duplicateOrError(typ string, name string, err error) error {
+	if err != nil {
+		return err
+	}
+	log.Errorf("[Store][auth] add %s(%s) duplicate", typ, name)
+	return store.NewStatusError(store.DuplicateEntryErr, typ+" "+name+" is existed")
+}
+
+func role2Store(role *model.Role) (*roleForStore, error) {
+	users, err := json.Marshal(role.Users)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := json.Marshal(role.Groups)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := json.Marshal(role.Policies)
+	if err != nil {
+		return nil, err
+	}
+	return &roleForStore{
+		ID:         role.ID,
+		Name:       role.Name,
+		Comment:    role.Comment,
+		Users:      string(users),
+		Groups:     string(groups),
+		Policies:   string(policies),
+		CreateTime: role.CreateTime,
+		ModifyTime: role.ModifyTime,
+	}, nil
+}
+
+// 解析json格式的字段，为空时不处理
+func unmarshalAuthField(data string, v interface{}) error {
+	if data == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(data), v)
+}
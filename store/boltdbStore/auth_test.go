@@ -0,0 +1,115 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package boltdbStore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/polarismesh/polaris-server/common/model"
+)
+
+func TestAuthStore(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "test_auth")
+	fileName := filepath.Join(tempDir, "test_auth.bolt")
+	handler, err := NewBoltHandler(&BoltConfig{FileName: fileName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = handler.Close()
+		_ = os.RemoveAll(tempDir)
+	}()
+
+	aStore := &authStore{handler: handler}
+	if err := aStore.AddUser(&model.User{ID: "1", Name: "alice", Password: "hash"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := aStore.AddUser(&model.User{ID: "2", Name: "alice", Password: "hash"}); err == nil {
+		t.Fatalf("duplicate user should be rejected")
+	}
+	if err := aStore.UpdateUser(&model.User{Name: "alice", Password: "new-hash", Comment: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+	users, err := aStore.GetUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Password != "new-hash" || users[0].Comment != "admin" {
+		t.Fatalf("users not match: %+v", users)
+	}
+
+	group := &model.UserGroup{ID: "1", Name: "ops", Users: []string{"alice"}}
+	if err := aStore.AddUserGroup(group); err != nil {
+		t.Fatal(err)
+	}
+	role := &model.Role{
+		ID:     "1",
+		Name:   "service-admin",
+		Users:  []string{"alice"},
+		Groups: []string{"ops"},
+		Policies: []*model.AuthPolicy{
+			{Resource: "service", Namespace: "Test", Actions: []string{"read", "write"}},
+		},
+	}
+	if err := aStore.AddRole(role); err != nil {
+		t.Fatal(err)
+	}
+
+	groups, err := aStore.GetUserGroups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || len(groups[0].Users) != 1 || groups[0].Users[0] != "alice" {
+		t.Fatalf("groups not match: %+v", groups)
+	}
+	roles, err := aStore.GetRoles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roles) != 1 || len(roles[0].Groups) != 1 || len(roles[0].Policies) != 1 ||
+		roles[0].Policies[0].Namespace != "Test" || len(roles[0].Policies[0].Actions) != 2 {
+		t.Fatalf("roles not match: %+v", roles)
+	}
+
+	role.Users = nil
+	if err := aStore.UpdateRole(role); err != nil {
+		t.Fatal(err)
+	}
+	roles, _ = aStore.GetRoles()
+	if len(roles) != 1 || len(roles[0].Users) != 0 {
+		t.Fatalf("role users should be empty: %+v", roles)
+	}
+
+	if err := aStore.DeleteRole("service-admin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := aStore.DeleteUserGroup("ops"); err != nil {
+		t.Fatal(err)
+	}
+	if err := aStore.DeleteUser("alice"); err != nil {
+		t.Fatal(err)
+	}
+	users, _ = aStore.GetUsers()
+	groups, _ = aStore.GetUserGroups()
+	roles, _ = aStore.GetRoles()
+	if len(users) != 0 || len(groups) != 0 || len(roles) != 0 {
+		t.Fatalf("all auth data should be deleted")
+	}
+}
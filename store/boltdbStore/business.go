@@ -47,6 +47,7 @@ func (bs *businessStore) AddBusiness(b *model.Business) error {
 	tNow := time.Now()
 	b.CreateTime = tNow
 	b.ModifyTime = tNow
+	b.Valid = true
 
 	if err := dbOp.SaveValue(tblBusiness, b.ID, b); err != nil {
 		log.Errorf("[Store][business] add business err : %s", err.Error())
@@ -83,6 +84,7 @@ func (bs *businessStore) UpdateBusiness(b *model.Business) error {
 	dbOp := bs.handler
 
 	b.ModifyTime = time.Now()
+	b.Valid = true
 
 	if err := dbOp.SaveValue(tblBusiness, b.ID, b); err != nil {
 		log.Errorf("[Store][business] add business err : %s", err.Error())
@@ -28,7 +28,6 @@ import (
 	"github.com/polarismesh/polaris-server/common/log"
 	"github.com/polarismesh/polaris-server/common/model"
 	"github.com/polarismesh/polaris-server/common/utils"
-	"github.com/polarismesh/polaris-server/naming"
 	"github.com/polarismesh/polaris-server/store"
 )
 
@@ -49,6 +48,16 @@ type circuitBreakerStore struct {
 func (c *circuitBreakerStore) CreateCircuitBreaker(cb *model.CircuitBreaker) error {
 	dbOp := c.handler
 
+	// 没有指定时间时由存储层生成
+	tNow := time.Now()
+	if cb.CreateTime.IsZero() {
+		cb.CreateTime = tNow
+	}
+	if cb.ModifyTime.IsZero() {
+		cb.ModifyTime = tNow
+	}
+	cb.Valid = true
+
 	if err := dbOp.SaveValue(tblCircuitBreaker, c.buildKey(cb.ID, cb.Version), cb); err != nil {
 		log.Errorf("[Store][circuitBreaker] create circuit breaker(%s, %s, %s) err: %s",
 			cb.ID, cb.Name, cb.Version, err.Error())
@@ -83,7 +92,7 @@ func (c *circuitBreakerStore) tagCircuitBreaker(cb *model.CircuitBreaker) error
 	}
 
 	if result == nil {
-		return store.NewStatusError(store.NotFoundCircuitBreaker, fmt.Sprintf("not exist for CircuitBreaker(id=%s, " +
+		return store.NewStatusError(store.NotFoundMasterConfig, fmt.Sprintf("not exist for CircuitBreaker(id=%s, "+
 			"version=%s)", key, VersionForMaster))
 	}
 
@@ -91,6 +100,7 @@ func (c *circuitBreakerStore) tagCircuitBreaker(cb *model.CircuitBreaker) error
 
 	cb.CreateTime = tNow
 	cb.ModifyTime = tNow
+	cb.Valid = true
 
 	if err := dbOp.SaveValue(tblCircuitBreaker, key, cb); err != nil {
 		log.Errorf("[Store][circuitBreaker] tag rule breaker(%s, %s, %s) err: %s",
@@ -108,6 +118,7 @@ func (c *circuitBreakerStore) ReleaseCircuitBreaker(cbr *model.CircuitBreakerRel
 
 	cbr.CreateTime = tNow
 	cbr.ModifyTime = tNow
+	cbr.Valid = true
 
 	if err := c.releaseCircuitBreaker(cbr); err != nil {
 		log.Errorf("[Store][CircuitBreaker] release rule err: %s", err.Error())
@@ -159,6 +170,21 @@ func (c *circuitBreakerStore) DeleteTagCircuitBreaker(id string, version string)
 
 	dbOp := c.handler
 
+	// 已经绑定了服务的规则不删除，与数据库的实现保持一致
+	var relations []*model.CircuitBreakerRelation
+	var err error
+	if version == VersionForMaster {
+		relations, err = c.GetCircuitBreakerMasterRelation(id)
+	} else {
+		relations, err = c.GetCircuitBreakerRelation(id, version)
+	}
+	if err != nil {
+		return err
+	}
+	if len(relations) > 0 {
+		return nil
+	}
+
 	if err := dbOp.DeleteValues(tblCircuitBreaker, []string{c.buildKey(id, version)}); err != nil {
 		log.Errorf("[Store][circuitBreaker] delete tag rule(%s, %s) err: %s", id, version, err.Error())
 		return store.Error(err)
@@ -177,6 +203,17 @@ func (c *circuitBreakerStore) UpdateCircuitBreaker(cb *model.CircuitBreaker) err
 
 	dbOp := c.handler
 
+	// 整体覆盖保存，创建时间沿用旧的规则
+	old, err := c.GetCircuitBreaker(cb.ID, cb.Version)
+	if err != nil {
+		return err
+	}
+	if old != nil {
+		cb.CreateTime = old.CreateTime
+	}
+	cb.ModifyTime = time.Now()
+	cb.Valid = true
+
 	if err := dbOp.SaveValue(tblCircuitBreaker, c.buildKey(cb.ID, cb.Version), cb); err != nil {
 		log.Errorf("[Store][CircuitBreaker] update rule(%s,%s) exec err: %s", cb.ID, cb.Version, err.Error())
 		return store.Error(err)
@@ -235,14 +272,24 @@ func (c *circuitBreakerStore) GetCircuitBreakerVersions(id string) ([]string, er
 	return ans, nil
 }
 
-// GetCircuitBreakerMasterRelation 获取熔断规则master版本的绑定关系
+// GetCircuitBreakerMasterRelation 获取熔断规则所有版本的绑定关系
 func (c *circuitBreakerStore) GetCircuitBreakerMasterRelation(ruleID string) ([]*model.CircuitBreakerRelation, error) {
-	return c.GetCircuitBreakerRelation(ruleID, VersionForMaster)
+	return c.getCircuitBreakerRelations(ruleID, func(string) bool {
+		return true
+	})
 }
 
 // GetCircuitBreakerRelation 获取已标记熔断规则的绑定关系
 func (c *circuitBreakerStore) GetCircuitBreakerRelation(
 	ruleID, ruleVersion string) ([]*model.CircuitBreakerRelation, error) {
+	return c.getCircuitBreakerRelations(ruleID, func(version string) bool {
+		return strings.Compare(ruleVersion, version) == 0
+	})
+}
+
+// getCircuitBreakerRelations 获取熔断规则指定版本的绑定关系
+func (c *circuitBreakerStore) getCircuitBreakerRelations(
+	ruleID string, matchVersion func(string) bool) ([]*model.CircuitBreakerRelation, error) {
 	dbOp := c.handler
 
 	// first: get rule_id => service_ids
@@ -263,7 +310,7 @@ func (c *circuitBreakerStore) GetCircuitBreakerRelation(
 
 	for _, val := range results {
 		record := val.(*model.CircuitBreakerRelation)
-		if strings.Compare(ruleVersion, record.RuleVersion) != 0 {
+		if !matchVersion(record.RuleVersion) {
 			continue
 		}
 		relations = append(relations, record)
@@ -307,6 +354,7 @@ func (c *circuitBreakerStore) GetCircuitBreakerForCache(
 		results = append(results, &model.ServiceWithCircuitBreaker{
 			ServiceID:      serviceId,
 			CircuitBreaker: cbs[cbKey].(*model.CircuitBreaker),
+			Valid:          relations[serviceId].(*model.CircuitBreakerRelation).Valid,
 			CreateTime:     relations[serviceId].(*model.CircuitBreakerRelation).CreateTime,
 			ModifyTime:     relations[serviceId].(*model.CircuitBreakerRelation).ModifyTime,
 		})
@@ -374,50 +422,85 @@ func (c *circuitBreakerStore) ListReleaseCircuitBreakers(
 
 	dbOp := c.handler
 
-	// converted key : rule_id => id, rule_version => version
-	if ruleID, ok := filters["rule_id"]; ok {
-		filters[naming.ID] = ruleID
-		delete(filters, "rule_id")
+	// converted key : rule_id => RuleID, rule_version => RuleVersion
+	relationFilters := make(map[string]string, len(filters))
+	for k, v := range filters {
+		switch k {
+		case "rule_id":
+			relationFilters["RuleID"] = v
+		case "rule_version":
+			relationFilters["RuleVersion"] = v
+		default:
+			relationFilters[k] = v
+		}
 	}
 
-	if ruleVersion, ok := filters["rule_version"]; ok {
-		filters[naming.Version] = ruleVersion
-		delete(filters, "rule_version")
+	// 发布的规则以绑定关系为准，每个绑定关系对应一个服务
+	results, err := dbOp.LoadValuesByFilter(tblCircuitBreakerRelation, utils.CollectFilterFields(relationFilters),
+		&model.CircuitBreakerRelation{}, func(m map[string]interface{}) bool {
+			for k, v := range relationFilters {
+				qV := m[k]
+				if !reflect.DeepEqual(qV, v) {
+					return false
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return nil, store.Error(err)
 	}
 
-	results, err := dbOp.LoadValuesByFilter(tblCircuitBreaker, utils.CollectFilterFields(filters), &model.CircuitBreaker{}, func(m map[string]interface{}) bool {
-		for k, v := range filters {
-			qV := m[k]
-			if !reflect.DeepEqual(qV, v) {
-				return false
-			}
-		}
-		return true
-	})
+	serviceIDs := make([]string, 0, len(results))
+	for serviceID := range results {
+		serviceIDs = append(serviceIDs, serviceID)
+	}
+	services, err := dbOp.LoadValues(tblNameService, serviceIDs, &model.Service{})
 	if err != nil {
 		return nil, store.Error(err)
 	}
 
 	cbSlice := make([]*model.CircuitBreakerInfo, 0)
-	for _, v := range results {
-		record := v.(*model.CircuitBreaker)
-		cbSlice = append(cbSlice, convertCircuitBreakerToInfo(record))
+	for serviceID, v := range results {
+		relation := v.(*model.CircuitBreakerRelation)
+		svcValue, ok := services[serviceID]
+		if !ok {
+			continue
+		}
+		svc := svcValue.(*model.Service)
+		cbSlice = append(cbSlice, &model.CircuitBreakerInfo{
+			CircuitBreaker: &model.CircuitBreaker{
+				ID:      relation.RuleID,
+				Version: relation.RuleVersion,
+			},
+			Services: []*model.Service{
+				{
+					Name:       svc.Name,
+					Namespace:  svc.Namespace,
+					Owner:      svc.Owner,
+					CreateTime: relation.CreateTime,
+					ModifyTime: relation.ModifyTime,
+				},
+			},
+		})
 	}
 
 	sort.Slice(cbSlice, func(i, j int) bool {
-		a := cbSlice[i]
-		b := cbSlice[j]
-		return a.CircuitBreaker.ModifyTime.Before(b.CircuitBreaker.ModifyTime)
+		a := cbSlice[i].Services[0]
+		b := cbSlice[j].Services[0]
+		return a.ModifyTime.After(b.ModifyTime)
 	})
 
-	if offset >= uint32(len(cbSlice)) {
-		offset = 0
-		limit = math.MaxUint32
+	total := uint32(len(cbSlice))
+	if offset >= total {
+		return &model.CircuitBreakerDetail{
+			Total:               total,
+			CircuitBreakerInfos: []*model.CircuitBreakerInfo{},
+		}, nil
 	}
 
 	out := &model.CircuitBreakerDetail{
-		Total:               uint32(len(results)),
-		CircuitBreakerInfos: cbSlice[offset:int(math.Min(float64(offset+limit), float64(len(results))))],
+		Total:               total,
+		CircuitBreakerInfos: cbSlice[offset:int(math.Min(float64(offset+limit), float64(total)))],
 	}
 
 	return out, nil
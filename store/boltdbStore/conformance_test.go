@@ -0,0 +1,68 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdbStore
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/polarismesh/polaris-server/store"
+	"github.com/polarismesh/polaris-server/store/conformance"
+)
+
+// boltdb与数据库实现的设计差异
+var conformanceSkips = map[string]string{
+	"Namespace/GetMore":             "删除为物理删除，增量拉取不返回已删除的数据",
+	"Business/Lifecycle":            "删除为物理删除，增量拉取不返回已删除的数据",
+	"Service/AddWithoutNamespace":   "没有外键约束，由上层检查命名空间是否存在",
+	"Service/GetMoreDeleted":        "删除为物理删除，增量拉取不返回已删除的数据",
+	"Instance/AddWithoutService":    "没有外键约束，由上层检查服务是否存在",
+	"Instance/GetMoreDeleted":       "删除为物理删除，增量拉取不返回已删除的数据",
+	"RoutingConfig/ForCacheDeleted": "删除为物理删除，增量拉取不返回已删除的数据",
+	"CircuitBreaker/ForCache":       "解绑为物理删除，增量拉取不返回已解绑的关系",
+	"Transaction/SharedLock":        "事务串行执行，共享锁之间互相阻塞",
+}
+
+// TestConformance 一致性测试集，每个场景使用一个新的数据文件
+func TestConformance(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "test_conformance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+
+	index := 0
+	suite := &conformance.Suite{
+		Factory: func(t *testing.T) store.Store {
+			index++
+			s := &boltStore{}
+			fileName := filepath.Join(tempDir, fmt.Sprintf("conformance_%d.bolt", index))
+			if err := s.Initialize(&store.Config{Option: map[string]interface{}{confPath: fileName}}); err != nil {
+				t.Fatalf("initialize bolt store err: %s", err.Error())
+			}
+			return s
+		},
+		Skip: conformanceSkips,
+	}
+	suite.Run(t)
+}
@@ -36,9 +36,12 @@ type boltStore struct {
 	*rateLimitStore
 	*platformStore
 	*circuitBreakerStore
+	*historyStore
+	*authStore
 
-	handler BoltHandler
-	start   bool
+	handler  BoltHandler
+	notifier store.ChangeNotifier
+	start    bool
 }
 
 // Name store name
@@ -57,7 +60,7 @@ func (m *boltStore) Initialize(c *store.Config) error {
 	if nil != err {
 		return err
 	}
-	m.handler = handler
+	m.handler = &notifyHandler{BoltHandler: handler, notifier: &m.notifier}
 	if err = m.newStore(); nil != err {
 		_ = handler.Close()
 		return err
@@ -90,6 +93,10 @@ func (m *boltStore) newStore() error {
 
 	m.platformStore = &platformStore{handler: m.handler}
 
+	m.historyStore = &historyStore{handler: m.handler}
+
+	m.authStore = &authStore{handler: m.handler}
+
 	return nil
 }
 
@@ -0,0 +1,151 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdbStore
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+const (
+	tblHistory string = "history"
+	// 保存操作记录序号的bucket
+	tblHistorySeq string = "history_seq"
+)
+
+// 操作记录查询条件与字段的对应关系
+var historyFilter2Field = map[string]string{
+	"resource":  "ResourceType",
+	"operation": "OperationType",
+	"namespace": "Namespace",
+	"service":   "Service",
+	"operator":  "Operator",
+}
+
+type historyStore struct {
+	handler BoltHandler
+}
+
+// AddHistory 新增操作记录
+func (h *historyStore) AddHistory(history *model.History) error {
+	var id uint64
+	err := h.handler.Execute(true, func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(tblHistorySeq))
+		if err != nil {
+			return err
+		}
+		id, err = bucket.NextSequence()
+		return err
+	})
+	if err != nil {
+		log.Errorf("[Store][history] generate history id err: %s", err.Error())
+		return store.Error(err)
+	}
+
+	history.ID = id
+	// 序号补齐为定长，保证key的顺序与写入顺序一致
+	if err := h.handler.SaveValue(tblHistory, fmt.Sprintf("%020d", id), history); err != nil {
+		log.Errorf("[Store][history] add history(%s, %s) err: %s",
+			history.ResourceType, history.OperationType, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+// GetHistories 根据过滤条件查询操作记录
+func (h *historyStore) GetHistories(filter map[string]string, start, end time.Time, offset, limit uint32) (
+	uint32, []*model.History, error) {
+	fields := []string{"CreateTime"}
+	conds := make(map[string]string, len(filter))
+	for key, value := range filter {
+		field, ok := historyFilter2Field[key]
+		if !ok {
+			return 0, nil, fmt.Errorf("history filter(%s) is not allowed", key)
+		}
+		fields = append(fields, field)
+		conds[field] = value
+	}
+
+	result, err := h.handler.LoadValuesByFilter(tblHistory, fields, &model.History{},
+		func(m map[string]interface{}) bool {
+			for field, value := range conds {
+				if m[field] != value {
+					return false
+				}
+			}
+			ctime, _ := m["CreateTime"].(time.Time)
+			if !start.IsZero() && ctime.Before(start) {
+				return false
+			}
+			if !end.IsZero() && ctime.After(end) {
+				return false
+			}
+			return true
+		})
+	if err != nil {
+		log.Errorf("[Store][history] get histories by filter(%+v) err: %s", filter, err.Error())
+		return 0, nil, store.Error(err)
+	}
+
+	histories := make([]*model.History, 0, len(result))
+	for _, value := range result {
+		histories = append(histories, value.(*model.History))
+	}
+	sort.Slice(histories, func(i, j int) bool {
+		return histories[i].ID > histories[j].ID
+	})
+
+	total := uint32(len(histories))
+	if offset >= total {
+		return total, make([]*model.History, 0), nil
+	}
+	last := offset + limit
+	if last > total {
+		last = total
+	}
+	return total, histories[offset:last], nil
+}
+
+// DeleteHistories 删除指定时间之前的操作记录
+func (h *historyStore) DeleteHistories(before time.Time) (uint32, error) {
+	result, err := h.handler.LoadValuesByFilter(tblHistory, []string{"CreateTime"}, &model.History{},
+		func(m map[string]interface{}) bool {
+			ctime, _ := m["CreateTime"].(time.Time)
+			return ctime.Before(before)
+		})
+	if err != nil {
+		log.Errorf("[Store][history] load histories before(%s) err: %s", before, err.Error())
+		return 0, store.Error(err)
+	}
+
+	keys := make([]string, 0, len(result))
+	for key := range result {
+		keys = append(keys, key)
+	}
+	if err := h.handler.DeleteValues(tblHistory, keys); err != nil {
+		log.Errorf("[Store][history] delete histories before(%s) err: %s", before, err.Error())
+		return 0, store.Error(err)
+	}
+	return uint32(len(keys)), nil
+}
@@ -0,0 +1,97 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package boltdbStore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/model"
+)
+
+func TestHistoryStore(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "test_history")
+	fileName := filepath.Join(tempDir, "test_history.bolt")
+	handler, err := NewBoltHandler(&BoltConfig{FileName: fileName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = handler.Close()
+		_ = os.RemoveAll(tempDir)
+	}()
+
+	hStore := &historyStore{handler: handler}
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		history := &model.History{
+			ResourceType:  "service",
+			OperationType: "create",
+			Namespace:     "Test",
+			Service:       "svc",
+			Operator:      "polaris",
+			After:         `{"name":"svc"}`,
+			CreateTime:    now.Add(time.Duration(i-10) * time.Hour),
+		}
+		if i%2 == 0 {
+			history.Operator = "admin"
+		}
+		if err := hStore.AddHistory(history); err != nil {
+			t.Fatal(err)
+		}
+		if history.ID != uint64(i+1) {
+			t.Fatalf("history id should be %d, got %d", i+1, history.ID)
+		}
+	}
+
+	total, histories, err := hStore.GetHistories(map[string]string{"operator": "admin"},
+		time.Time{}, time.Time{}, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 5 || len(histories) != 2 || histories[0].ID != 7 || histories[1].ID != 5 {
+		t.Fatalf("get histories by operator, total: %d, histories: %+v", total, histories)
+	}
+
+	total, _, err = hStore.GetHistories(nil, now.Add(-3*time.Hour-time.Minute), time.Time{}, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 {
+		t.Fatalf("get histories by time range, total should be 3, got %d", total)
+	}
+
+	if _, _, err := hStore.GetHistories(map[string]string{"unknown": "x"},
+		time.Time{}, time.Time{}, 0, 10); err == nil {
+		t.Fatalf("unknown filter should return error")
+	}
+
+	count, err := hStore.DeleteHistories(now.Add(-5*time.Hour - time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 5 {
+		t.Fatalf("delete histories count should be 5, got %d", count)
+	}
+	total, _, _ = hStore.GetHistories(nil, time.Time{}, time.Time{}, 0, 10)
+	if total != 5 {
+		t.Fatalf("histories should remain 5, got %d", total)
+	}
+}
@@ -330,10 +330,6 @@ func (i *instanceStore) GetExpandInstances(filter, metaFilter map[string]string,
 
 	log.Infof("get GetExpandInstances request %+v", filter)
 
-	if limit == 0 {
-		return 0, make([]*model.Instance, 0), nil
-	}
-
 	// find service
 	name, isServiceName := filter["name"]
 	namespace, isNamespace := filter["namespace"]
@@ -345,9 +341,20 @@ func (i *instanceStore) GetExpandInstances(filter, metaFilter map[string]string,
 			log.Errorf("[Store][boltdb] find service error, %v", err)
 			return 0, nil, err
 		}
+		if svc == nil {
+			return 0, make([]*model.Instance, 0), nil
+		}
 		filter["serviceID"] = svc.ID
 	}
 
+	// host supports multiple values separated by comma
+	hosts := make(map[string]bool)
+	if host, ok := filter["host"]; ok {
+		for _, h := range strings.Split(host, ",") {
+			hosts[h] = true
+		}
+	}
+
 	fields := []string{insFieldProto, insFieldServiceID}
 
 	instances, err := i.handler.LoadValuesByFilter(tblNameInstance, fields, &model.Instance{},
@@ -357,7 +364,6 @@ func (i *instanceStore) GetExpandInstances(filter, metaFilter map[string]string,
 				return false
 			}
 			ins := insProto.(*api.Instance)
-			host, isHost := filter["host"]
 			port, isPort := filter["port"]
 			protocol, isProtocol := filter["protocol"]
 			version, isVersion := filter["version"]
@@ -365,7 +371,7 @@ func (i *instanceStore) GetExpandInstances(filter, metaFilter map[string]string,
 			isolate, isIsolate := filter["isolate"]
 			svcID, isSvcID := filter["serviceID"]
 
-			if isHost && host != ins.GetHost().GetValue() {
+			if len(hosts) > 0 && !hosts[ins.GetHost().GetValue()] {
 				return false
 			}
 			if isPort && port != strconv.Itoa(int(ins.GetPort().GetValue())) {
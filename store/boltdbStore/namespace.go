@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
 )
 
 const tblNameNamespace = "namespace"
@@ -79,6 +80,16 @@ func (n *namespaceStore) AddNamespace(namespace *model.Namespace) error {
 	if namespace.Name == "" || namespace.Owner == "" || namespace.Token == "" {
 		return errors.New("store add namespace some param are empty")
 	}
+	exist, err := n.GetNamespace(namespace.Name)
+	if nil != err {
+		return err
+	}
+	if nil != exist {
+		return store.NewStatusError(store.DuplicateEntryErr, "namespace "+namespace.Name+" already exists")
+	}
+	current := time.Now()
+	namespace.CreateTime = current
+	namespace.ModifyTime = current
 	namespace.Valid = true
 	return n.handler.SaveValue(tblNameNamespace, namespace.Name, namespace)
 }
@@ -159,21 +170,60 @@ func (ns NamespaceSlice) Swap(i, j int) {
 // GetNamespaces get namespaces by offset and limit
 func (n *namespaceStore) GetNamespaces(
 	filter map[string][]string, offset, limit int) ([]*model.Namespace, uint32, error) {
-	values, err := n.handler.LoadValuesAll(tblNameNamespace, &model.Namespace{})
+	fields := make([]string, 0, len(filter))
+	for key := range filter {
+		fields = append(fields, toNamespaceField(key))
+	}
+	values, err := n.handler.LoadValuesByFilter(tblNameNamespace, fields, &model.Namespace{},
+		func(m map[string]interface{}) bool {
+			return matchNamespace(m, filter)
+		})
 	if nil != err {
 		return nil, 0, err
 	}
 	namespaces := NamespaceSlice(toNamespaces(values))
 	sort.Sort(sort.Reverse(namespaces))
-	startIdx := offset * limit
-	if startIdx >= len(namespaces) {
-		return nil, 0, nil
+	total := uint32(len(namespaces))
+	if offset >= len(namespaces) || limit <= 0 {
+		return nil, total, nil
 	}
-	endIdx := startIdx + limit
+	endIdx := offset + limit
 	if endIdx > len(namespaces) {
 		endIdx = len(namespaces)
 	}
-	return namespaces[startIdx:endIdx], 0, nil
+	return namespaces[offset:endIdx], total, nil
+}
+
+// 过滤条件的key为数据库的列名，转换为结构体的字段名
+func toNamespaceField(key string) string {
+	if key == "" {
+		return key
+	}
+	return strings.ToUpper(key[:1]) + key[1:]
+}
+
+// 同一个key的多个值之间为或的关系，owner为模糊匹配，与数据库的实现保持一致
+func matchNamespace(m map[string]interface{}, filter map[string][]string) bool {
+	for key, items := range filter {
+		if len(items) == 0 {
+			continue
+		}
+		value, ok := m[toNamespaceField(key)].(string)
+		if !ok {
+			return false
+		}
+		matched := false
+		for _, item := range items {
+			if (key == "owner" && strings.Contains(value, item)) || value == item {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
 }
 
 func toNamespaces(values map[string]interface{}) []*model.Namespace {
@@ -192,7 +242,7 @@ func (n *namespaceStore) GetMoreNamespaces(mtime time.Time) ([]*model.Namespace,
 			if !ok {
 				return false
 			}
-			return mTimeValue.(time.Time).After(mtime)
+			return !mTimeValue.(time.Time).Before(mtime)
 		})
 	if nil != err {
 		return nil, err
@@ -0,0 +1,79 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdbStore
+
+import (
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 表与缓存资源的对应关系，写入这些表后通知缓存更新
+var table2ChangeResource = map[string]store.ChangeResource{
+	tblNameService:            store.ChangeService,
+	tblNameInstance:           store.ChangeInstance,
+	tblNameRouting:            store.ChangeRoutingConfig,
+	tblRateLimitConfig:        store.ChangeRateLimit,
+	tblRateLimitRevision:      store.ChangeRateLimit,
+	tblCircuitBreaker:         store.ChangeCircuitBreaker,
+	tblCircuitBreakerRelation: store.ChangeCircuitBreaker,
+}
+
+// notifyHandler 在写入成功之后发出数据变更通知
+// boltdb只能被一个进程打开，本地的通知即覆盖了所有的写入
+type notifyHandler struct {
+	BoltHandler
+	notifier *store.ChangeNotifier
+}
+
+// SaveValue insert data object and notify the change
+func (n *notifyHandler) SaveValue(typ string, key string, object interface{}) error {
+	if err := n.BoltHandler.SaveValue(typ, key, object); err != nil {
+		return err
+	}
+	n.notify(typ)
+	return nil
+}
+
+// DeleteValues delete data objects and notify the change
+func (n *notifyHandler) DeleteValues(typ string, keys []string) error {
+	if err := n.BoltHandler.DeleteValues(typ, keys); err != nil {
+		return err
+	}
+	n.notify(typ)
+	return nil
+}
+
+// UpdateValue update properties of data object and notify the change
+func (n *notifyHandler) UpdateValue(typ string, key string, properties map[string]interface{}) error {
+	if err := n.BoltHandler.UpdateValue(typ, key, properties); err != nil {
+		return err
+	}
+	n.notify(typ)
+	return nil
+}
+
+func (n *notifyHandler) notify(typ string) {
+	if resource, ok := table2ChangeResource[typ]; ok {
+		n.notifier.Notify(resource)
+	}
+}
+
+// WatchChanges 订阅数据变更
+func (m *boltStore) WatchChanges(listener store.ChangeListener) bool {
+	m.notifier.AddListener(listener)
+	return true
+}
@@ -0,0 +1,76 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package boltdbStore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+func TestBoltStore_WatchChanges(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "test_notify")
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+
+	s := &boltStore{}
+	err := s.Initialize(&store.Config{Option: map[string]interface{}{"path": filepath.Join(tempDir, "notify.bolt")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = s.Destroy()
+	}()
+
+	var changes []store.ChangeResource
+	if !s.WatchChanges(func(resource store.ChangeResource) {
+		changes = append(changes, resource)
+	}) {
+		t.Fatalf("bolt store should notify all changes")
+	}
+
+	if err := s.AddService(createTestService("", "svc-1", "Test", true)); err != nil {
+		t.Fatal(err)
+	}
+	err = s.AddInstance(&model.Instance{
+		Proto: &api.Instance{
+			Id:   &wrappers.StringValue{Value: "ins-1"},
+			Host: &wrappers.StringValue{Value: "1.1.1.1"},
+			Port: &wrappers.UInt32Value{Value: 8080},
+		},
+		ServiceID: "svc-1",
+		Valid:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) < 2 || changes[0] != store.ChangeService || changes[len(changes)-1] != store.ChangeInstance {
+		t.Fatalf("changes not match: %v", changes)
+	}
+	for _, change := range changes {
+		if change != store.ChangeService && change != store.ChangeInstance {
+			t.Fatalf("unexpected change: %s", change)
+		}
+	}
+}
@@ -50,6 +50,7 @@ func (p *platformStore) CreatePlatform(platform *model.Platform) error {
 	platformKey := platform.ID
 	platform.CreateTime = tNow
 	platform.ModifyTime = tNow
+	platform.Valid = true
 
 	dbOp := p.handler
 
@@ -74,6 +75,7 @@ func (p *platformStore) UpdatePlatform(platform *model.Platform) error {
 
 	platformKey := platform.ID
 	platform.ModifyTime = time.Now()
+	platform.Valid = true
 
 	dbOp := p.handler
 
@@ -127,15 +129,39 @@ func (p *platformStore) GetPlatformById(id string) (*model.Platform, error) {
 	return val.(*model.Platform), nil
 }
 
+// 平台的过滤条件与结构体字段的映射
+var platformFilter2Field = map[string]string{
+	"id":         "ID",
+	"name":       "Name",
+	"domain":     "Domain",
+	"owner":      "Owner",
+	"department": "Department",
+}
+
 // GetPlatforms 根据过滤条件查询平台信息
 func (p *platformStore) GetPlatforms(
 	query map[string]string, offset uint32, limit uint32) (uint32, []*model.Platform, error) {
 
 	dbOp := p.handler
 
-	result, err := dbOp.LoadValuesByFilter(tblPlatform, utils.CollectFilterFields(query), &model.Platform{}, func(m map[string]interface{}) bool {
-		for k, v := range query {
+	// 数据库的列名转换为结构体的字段名，owner与数据库一样为模糊匹配
+	fieldQuery := make(map[string]string, len(query))
+	for k, v := range query {
+		if field, ok := platformFilter2Field[k]; ok {
+			k = field
+		}
+		fieldQuery[k] = v
+	}
+
+	result, err := dbOp.LoadValuesByFilter(tblPlatform, utils.CollectFilterFields(fieldQuery), &model.Platform{}, func(m map[string]interface{}) bool {
+		for k, v := range fieldQuery {
 			qV := m[k]
+			if k == "Owner" {
+				if owner, ok := qV.(string); ok && strings.Contains(owner, v) {
+					continue
+				}
+				return false
+			}
 			if !reflect.DeepEqual(qV, v) {
 				return false
 			}
@@ -160,5 +186,8 @@ func (p *platformStore) GetPlatforms(
 		return a.ModifyTime.After(b.ModifyTime)
 	})
 
+	if int(offset) >= total {
+		return uint32(total), []*model.Platform{}, nil
+	}
 	return uint32(total), platformSlice[offset:int(math.Min(float64(offset+limit), float64(total)))], nil
 }
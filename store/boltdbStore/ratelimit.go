@@ -18,47 +18,356 @@
 package boltdbStore
 
 import (
-	"github.com/polarismesh/polaris-server/common/model"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
 )
 
 type rateLimitStore struct {
 	handler BoltHandler
 }
 
-// 新增限流规则
-func (r *rateLimitStore) CreateRateLimit(limiting *model.RateLimit) error {
-	//TODO
+const (
+	tblRateLimitConfig   = "ratelimit_config"
+	tblRateLimitRevision = "ratelimit_revision"
+
+	rateLimitFieldID         = "ID"
+	rateLimitFieldServiceID  = "ServiceID"
+	rateLimitFieldClusterID  = "ClusterID"
+	rateLimitFieldLabels     = "Labels"
+	rateLimitFieldPriority   = "Priority"
+	rateLimitFieldRule       = "Rule"
+	rateLimitFieldRevision   = "Revision"
+	rateLimitFieldValid      = "Valid"
+	rateLimitFieldModifyTime = "ModifyTime"
+
+	rateLimitRevisionFieldLastRevision = "LastRevision"
+)
+
+// rateLimitFilterFields 查询参数到限流规则字段的映射，与defaultStore的列名保持一致
+var rateLimitFilterFields = map[string]string{
+	"id":         rateLimitFieldID,
+	"service_id": rateLimitFieldServiceID,
+	"cluster_id": rateLimitFieldClusterID,
+	"labels":     rateLimitFieldLabels,
+	"priority":   rateLimitFieldPriority,
+	"rule":       rateLimitFieldRule,
+	"revision":   rateLimitFieldRevision,
+}
+
+// CreateRateLimit 新增限流规则
+func (r *rateLimitStore) CreateRateLimit(limit *model.RateLimit) error {
+	if limit.ID == "" || limit.ServiceID == "" || limit.Revision == "" {
+		log.Errorf("[Store][boltdb] create rate limit missing some params")
+		return store.NewStatusError(store.EmptyParamsErr, "create rate limit missing some params")
+	}
+
+	initRateLimit(limit)
+
+	if err := r.handler.SaveValue(tblRateLimitConfig, limit.ID, limit); err != nil {
+		log.Errorf("[Store][boltdb] create rate limit(%+v) err: %s", limit, err.Error())
+		return store.Error(err)
+	}
+
+	if err := r.updateLastRevision(limit.ServiceID, limit.Revision); err != nil {
+		log.Errorf("[Store][boltdb][Create] update rate limit revision with service id(%s) err: %s",
+			limit.ServiceID, err.Error())
+		return store.Error(err)
+	}
+
 	return nil
 }
 
-// 更新限流规则
-func (r *rateLimitStore) UpdateRateLimit(limiting *model.RateLimit) error {
-	//TODO
+// UpdateRateLimit 更新限流规则
+func (r *rateLimitStore) UpdateRateLimit(limit *model.RateLimit) error {
+	if limit.ID == "" || limit.ServiceID == "" || limit.Revision == "" {
+		log.Errorf("[Store][boltdb] update rate limit missing some params")
+		return store.NewStatusError(store.EmptyParamsErr, "update rate limit missing some params")
+	}
+
+	properties := make(map[string]interface{})
+	properties[rateLimitFieldLabels] = limit.Labels
+	properties[rateLimitFieldPriority] = limit.Priority
+	properties[rateLimitFieldRule] = limit.Rule
+	properties[rateLimitFieldRevision] = limit.Revision
+	properties[rateLimitFieldModifyTime] = time.Now()
+
+	if err := r.handler.UpdateValue(tblRateLimitConfig, limit.ID, properties); err != nil {
+		log.Errorf("[Store][boltdb] update rate limit(%+v) err: %s", limit, err.Error())
+		return store.Error(err)
+	}
+
+	if err := r.updateLastRevision(limit.ServiceID, limit.Revision); err != nil {
+		log.Errorf("[Store][boltdb][Update] update rate limit revision with service id(%s) err: %s",
+			limit.ServiceID, err.Error())
+		return store.Error(err)
+	}
+
 	return nil
 }
 
-// 删除限流规则
-func (r *rateLimitStore) DeleteRateLimit(limiting *model.RateLimit) error {
-	//TODO
+// DeleteRateLimit 删除限流规则，实际是把Valid置为false，以便缓存感知到删除
+func (r *rateLimitStore) DeleteRateLimit(limit *model.RateLimit) error {
+	if limit.ID == "" || limit.ServiceID == "" || limit.Revision == "" {
+		log.Errorf("[Store][boltdb] delete rate limit missing some params")
+		return store.NewStatusError(store.EmptyParamsErr, "delete rate limit missing some params")
+	}
+
+	properties := make(map[string]interface{})
+	properties[rateLimitFieldValid] = false
+	properties[rateLimitFieldModifyTime] = time.Now()
+
+	if err := r.handler.UpdateValue(tblRateLimitConfig, limit.ID, properties); err != nil {
+		log.Errorf("[Store][boltdb] delete rate limit(%+v) err: %s", limit, err.Error())
+		return store.Error(err)
+	}
+
+	if err := r.updateLastRevision(limit.ServiceID, limit.Revision); err != nil {
+		log.Errorf("[Store][boltdb][Delete] update rate limit revision with service id(%s) err: %s",
+			limit.ServiceID, err.Error())
+		return store.Error(err)
+	}
+
 	return nil
 }
 
-// 根据过滤条件拉取限流规则
+// GetExtendRateLimits 根据过滤条件拉取限流规则
 func (r *rateLimitStore) GetExtendRateLimits(
 	query map[string]string, offset uint32, limit uint32) (uint32, []*model.ExtendRateLimit, error) {
-	//TODO
-	return 0, nil, nil
+
+	// 服务名和命名空间需要关联服务表进行过滤，其余条件直接作用于限流规则
+	svcName, isName := query["name"]
+	svcNamespace, isNamespace := query["namespace"]
+
+	fields := []string{rateLimitFieldValid}
+	for key := range query {
+		if field, ok := rateLimitFilterFields[key]; ok {
+			fields = append(fields, field)
+		}
+	}
+
+	rateLimits, err := r.handler.LoadValuesByFilter(tblRateLimitConfig, fields, &model.RateLimit{},
+		func(m map[string]interface{}) bool {
+			valid, ok := m[rateLimitFieldValid]
+			if !ok || !valid.(bool) {
+				return false
+			}
+			for key, value := range query {
+				field, ok := rateLimitFilterFields[key]
+				if !ok {
+					continue
+				}
+				if !matchRateLimitField(field, m[field], value) {
+					return false
+				}
+			}
+			return true
+		})
+	if err != nil {
+		log.Errorf("[Store][boltdb] load rate limit from kv error, %v", err)
+		return 0, nil, store.Error(err)
+	}
+	if len(rateLimits) == 0 {
+		return 0, []*model.ExtendRateLimit{}, nil
+	}
+
+	svcIds := make(map[string]bool)
+	for _, v := range rateLimits {
+		svcIds[v.(*model.RateLimit).ServiceID] = true
+	}
+
+	services, err := r.handler.LoadValuesByFilter(tblNameService, []string{SvcFieldID}, &model.Service{},
+		func(m map[string]interface{}) bool {
+			id, ok := m[SvcFieldID]
+			if !ok {
+				return false
+			}
+			_, ok = svcIds[id.(string)]
+			return ok
+		})
+	if err != nil {
+		log.Errorf("[Store][boltdb] load service in rate limit from kv error, %v", err)
+		return 0, nil, store.Error(err)
+	}
+
+	out := make([]*model.ExtendRateLimit, 0, len(rateLimits))
+	for _, v := range rateLimits {
+		rateLimit := v.(*model.RateLimit)
+		svc, ok := services[rateLimit.ServiceID].(*model.Service)
+		if !ok {
+			// 与数据库的关联查询保持一致，服务不存在的规则不返回
+			log.Warnf("[Store][boltdb] get service in rate limit error, service is nil, id: %s",
+				rateLimit.ServiceID)
+			continue
+		}
+		if isName && svc.Name != svcName {
+			continue
+		}
+		if isNamespace && svc.Namespace != svcNamespace {
+			continue
+		}
+		out = append(out, &model.ExtendRateLimit{
+			ServiceName:   svc.Name,
+			NamespaceName: svc.Namespace,
+			RateLimit:     rateLimit,
+		})
+	}
+
+	return uint32(len(out)), getRealRateLimitList(out, offset, limit), nil
 }
 
-// 根据限流ID拉取限流规则
+// GetRateLimitWithID 根据限流ID拉取限流规则
 func (r *rateLimitStore) GetRateLimitWithID(id string) (*model.RateLimit, error) {
-	//TODO
-	return nil, nil
+	if id == "" {
+		log.Errorf("[Store][boltdb] get rate limit missing some params")
+		return nil, store.NewStatusError(store.EmptyParamsErr, "get rate limit missing some params")
+	}
+
+	result, err := r.handler.LoadValues(tblRateLimitConfig, []string{id}, &model.RateLimit{})
+	if err != nil {
+		log.Errorf("[Store][boltdb] load rate limit with id(%s) from kv error, %v", id, err)
+		return nil, store.Error(err)
+	}
+
+	rateLimit, ok := result[id].(*model.RateLimit)
+	if !ok || !rateLimit.Valid {
+		return nil, nil
+	}
+	return rateLimit, nil
+}
+
+// GetRateLimitsForCache 根据修改时间拉取增量限流规则及最新版本号
+func (r *rateLimitStore) GetRateLimitsForCache(
+	mtime time.Time, firstUpdate bool) ([]*model.RateLimit, []*model.RateLimitRevision, error) {
+
+	fields := []string{rateLimitFieldModifyTime, rateLimitFieldValid}
+
+	rateLimits, err := r.handler.LoadValuesByFilter(tblRateLimitConfig, fields, &model.RateLimit{},
+		func(m map[string]interface{}) bool {
+			if firstUpdate {
+				valid, ok := m[rateLimitFieldValid]
+				if !ok || !valid.(bool) {
+					return false
+				}
+			}
+			rMtime, ok := m[rateLimitFieldModifyTime]
+			if !ok {
+				return false
+			}
+			return !rMtime.(time.Time).Before(mtime)
+		})
+	if err != nil {
+		log.Errorf("[Store][boltdb] load rate limit for cache from kv error, %v", err)
+		return nil, nil, store.Error(err)
+	}
+	if len(rateLimits) == 0 {
+		return nil, nil, nil
+	}
+
+	svcIds := make([]string, 0, len(rateLimits))
+	visited := make(map[string]bool)
+	for _, v := range rateLimits {
+		serviceID := v.(*model.RateLimit).ServiceID
+		if visited[serviceID] {
+			continue
+		}
+		visited[serviceID] = true
+		svcIds = append(svcIds, serviceID)
+	}
+
+	lastRevisions, err := r.handler.LoadValues(tblRateLimitRevision, svcIds, &model.RateLimitRevision{})
+	if err != nil {
+		log.Errorf("[Store][boltdb] load rate limit revision from kv error, %v", err)
+		return nil, nil, store.Error(err)
+	}
+
+	// 与数据库的关联查询保持一致，每条限流规则对应一条所属服务的最新版本号
+	out := make([]*model.RateLimit, 0, len(rateLimits))
+	revisions := make([]*model.RateLimitRevision, 0, len(rateLimits))
+	for _, v := range rateLimits {
+		rateLimit := v.(*model.RateLimit)
+		revision, ok := lastRevisions[rateLimit.ServiceID].(*model.RateLimitRevision)
+		if !ok {
+			continue
+		}
+		out = append(out, rateLimit)
+		revisions = append(revisions, &model.RateLimitRevision{
+			ServiceID:    rateLimit.ServiceID,
+			LastRevision: revision.LastRevision,
+		})
+	}
+
+	return out, revisions, nil
+}
+
+// updateLastRevision 更新服务下限流规则的最新版本号
+func (r *rateLimitStore) updateLastRevision(serviceID string, revision string) error {
+	result, err := r.handler.LoadValues(tblRateLimitRevision, []string{serviceID}, &model.RateLimitRevision{})
+	if err != nil {
+		return err
+	}
+
+	if _, ok := result[serviceID]; !ok {
+		return r.handler.SaveValue(tblRateLimitRevision, serviceID, &model.RateLimitRevision{
+			ServiceID:    serviceID,
+			LastRevision: revision,
+		})
+	}
+
+	properties := make(map[string]interface{})
+	properties[rateLimitRevisionFieldLastRevision] = revision
+	return r.handler.UpdateValue(tblRateLimitRevision, serviceID, properties)
+}
+
+// matchRateLimitField 判断限流规则的字段是否满足查询条件，labels为模糊匹配
+func matchRateLimitField(field string, value interface{}, expect string) bool {
+	if value == nil {
+		return expect == ""
+	}
+	switch field {
+	case rateLimitFieldLabels:
+		return strings.Contains(value.(string), expect)
+	case rateLimitFieldPriority:
+		return strconv.FormatUint(value.(uint64), 10) == expect
+	default:
+		return value.(string) == expect
+	}
+}
+
+func getRealRateLimitList(rateLimits []*model.ExtendRateLimit, offset, limit uint32) []*model.ExtendRateLimit {
+	sort.Slice(rateLimits, func(i, j int) bool {
+		// sort by modify time desc
+		if rateLimits[i].RateLimit.ModifyTime.After(rateLimits[j].RateLimit.ModifyTime) {
+			return true
+		} else if rateLimits[i].RateLimit.ModifyTime.Before(rateLimits[j].RateLimit.ModifyTime) {
+			return false
+		} else {
+			return strings.Compare(rateLimits[i].RateLimit.ID, rateLimits[j].RateLimit.ID) < 0
+		}
+	})
+
+	beginIndex := offset
+	endIndex := beginIndex + limit
+	totalCount := uint32(len(rateLimits))
+	// handle invalid offset, limit
+	if beginIndex >= totalCount || beginIndex >= endIndex {
+		return []*model.ExtendRateLimit{}
+	}
+	if endIndex > totalCount {
+		endIndex = totalCount
+	}
+
+	return rateLimits[beginIndex:endIndex]
 }
 
-// 根据修改时间拉取增量限流规则及最新版本号
-func (r *rateLimitStore) GetRateLimitsForCache(mtime time.Time, firstUpdate bool) ([]*model.RateLimit, []*model.RateLimitRevision, error) {
-	//TODO
-	return nil, nil, nil
-}
\ No newline at end of file
+func initRateLimit(r *model.RateLimit) {
+	currTime := time.Now()
+	r.CreateTime = currTime
+	r.ModifyTime = currTime
+	r.Valid = true
+}
@@ -0,0 +1,220 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdbStore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/model"
+)
+
+const (
+	rateLimitCount = 5
+)
+
+func CreateRateLimitDBHandlerAndRun(t *testing.T, tf func(t *testing.T, handler BoltHandler)) {
+	tempDir, _ := ioutil.TempDir("", "test_ratelimit")
+	_ = os.Remove(filepath.Join(tempDir, "test_ratelimit.bolt"))
+	handler, err := NewBoltHandler(&BoltConfig{FileName: filepath.Join(tempDir, "test_ratelimit.bolt")})
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = handler.Close()
+		_ = os.Remove(filepath.Join(tempDir, "test_ratelimit.bolt"))
+	}()
+	tf(t, handler)
+}
+
+func createTestRateLimits(t *testing.T, handler BoltHandler) *rateLimitStore {
+	sStore := &serviceStore{handler: handler}
+	for i := 0; i < 2; i++ {
+		err := sStore.AddService(&model.Service{
+			ID:        "svcid" + strconv.Itoa(i),
+			Name:      "svcname" + strconv.Itoa(i),
+			Namespace: "testns",
+			Token:     "token",
+			Owner:     "owner",
+			Revision:  "revision",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rStore := &rateLimitStore{handler: handler}
+	for i := 0; i < rateLimitCount; i++ {
+		err := rStore.CreateRateLimit(&model.RateLimit{
+			ID:        "id" + strconv.Itoa(i),
+			ServiceID: "svcid" + strconv.Itoa(i%2),
+			ClusterID: "cluster",
+			Labels:    `{"key":"value` + strconv.Itoa(i) + `"}`,
+			Priority:  uint32(i),
+			Rule:      "rule" + strconv.Itoa(i),
+			Revision:  "revision" + strconv.Itoa(i),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rStore
+}
+
+func TestRateLimitStore_CreateRateLimit(t *testing.T) {
+	CreateRateLimitDBHandlerAndRun(t, func(t *testing.T, handler BoltHandler) {
+		rStore := createTestRateLimits(t, handler)
+
+		rateLimit, err := rStore.GetRateLimitWithID("id0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rateLimit == nil || !rateLimit.Valid || rateLimit.Rule != "rule0" {
+			t.Fatalf("rate limit not match, got %+v", rateLimit)
+		}
+
+		err = rStore.CreateRateLimit(&model.RateLimit{ID: "id-missing"})
+		if err == nil {
+			t.Fatal("create rate limit without service id should fail")
+		}
+	})
+}
+
+func TestRateLimitStore_UpdateRateLimit(t *testing.T) {
+	CreateRateLimitDBHandlerAndRun(t, func(t *testing.T, handler BoltHandler) {
+		rStore := createTestRateLimits(t, handler)
+
+		err := rStore.UpdateRateLimit(&model.RateLimit{
+			ID:        "id1",
+			ServiceID: "svcid1",
+			Labels:    `{"key":"new"}`,
+			Priority:  10,
+			Rule:      "newrule",
+			Revision:  "newrevision",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rateLimit, err := rStore.GetRateLimitWithID("id1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rateLimit.Rule != "newrule" || rateLimit.Priority != 10 || rateLimit.Revision != "newrevision" {
+			t.Fatalf("rate limit not updated, got %+v", rateLimit)
+		}
+
+		_, revisions, err := rStore.GetRateLimitsForCache(time.Unix(0, 0), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, revision := range revisions {
+			if revision.ServiceID == "svcid1" && revision.LastRevision != "newrevision" {
+				t.Fatalf("last revision not match, got %s", revision.LastRevision)
+			}
+		}
+	})
+}
+
+func TestRateLimitStore_DeleteRateLimit(t *testing.T) {
+	CreateRateLimitDBHandlerAndRun(t, func(t *testing.T, handler BoltHandler) {
+		rStore := createTestRateLimits(t, handler)
+
+		lastMtime := time.Now()
+		err := rStore.DeleteRateLimit(&model.RateLimit{ID: "id2", ServiceID: "svcid0", Revision: "delrevision"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rateLimit, err := rStore.GetRateLimitWithID("id2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rateLimit != nil {
+			t.Fatalf("deleted rate limit should not be returned, got %+v", rateLimit)
+		}
+
+		// 首次加载不返回已删除的规则
+		rateLimits, _, err := rStore.GetRateLimitsForCache(time.Unix(0, 0), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rateLimits) != rateLimitCount-1 {
+			t.Fatalf("rate limit count not match, expect %d, got %d", rateLimitCount-1, len(rateLimits))
+		}
+
+		// 增量加载需要返回已删除的规则，以便缓存删除
+		rateLimits, revisions, err := rStore.GetRateLimitsForCache(lastMtime, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rateLimits) != 1 || rateLimits[0].ID != "id2" || rateLimits[0].Valid {
+			t.Fatalf("incremental rate limits not match, got %+v", rateLimits)
+		}
+		if len(revisions) != 1 || revisions[0].LastRevision != "delrevision" {
+			t.Fatalf("incremental revisions not match, got %+v", revisions)
+		}
+	})
+}
+
+func TestRateLimitStore_GetExtendRateLimits(t *testing.T) {
+	CreateRateLimitDBHandlerAndRun(t, func(t *testing.T, handler BoltHandler) {
+		rStore := createTestRateLimits(t, handler)
+
+		total, rateLimits, err := rStore.GetExtendRateLimits(map[string]string{}, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != rateLimitCount || len(rateLimits) != rateLimitCount {
+			t.Fatalf("rate limit count not match, expect %d, got %d/%d", rateLimitCount, total, len(rateLimits))
+		}
+
+		total, rateLimits, err = rStore.GetExtendRateLimits(
+			map[string]string{"name": "svcname0", "namespace": "testns"}, 0, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 3 || len(rateLimits) != 1 {
+			t.Fatalf("rate limit count not match, expect 3/1, got %d/%d", total, len(rateLimits))
+		}
+		if rateLimits[0].ServiceName != "svcname0" || rateLimits[0].NamespaceName != "testns" {
+			t.Fatalf("rate limit service not match, got %+v", rateLimits[0])
+		}
+
+		total, rateLimits, err = rStore.GetExtendRateLimits(map[string]string{"labels": "value3"}, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 1 || rateLimits[0].RateLimit.ID != "id3" {
+			t.Fatalf("rate limit labels filter not match, got %d", total)
+		}
+
+		total, _, err = rStore.GetExtendRateLimits(map[string]string{"name": "notexist"}, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 0 {
+			t.Fatalf("rate limit count not match, expect 0, got %d", total)
+		}
+	})
+}
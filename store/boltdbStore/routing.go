@@ -255,6 +255,15 @@ func (r *routingStore) GetRoutingConfigs(
 			return true
 		})
 
+	if err != nil {
+		log.Errorf("[Store][boltdb] load service from kv error, %v", err)
+		return 0, nil, err
+	}
+
+	// filter by service name and namespace
+	name, isName := filter["name"]
+	namespace, isNamespace := filter["namespace"]
+
 	var out []*model.ExtendRoutingConfig
 
 	for id, r := range routeConf {
@@ -266,12 +275,15 @@ func (r *routingStore) GetRoutingConfigs(
 		} else {
 			log.Warnf("[Store][boltdb] get service in route conf error, service is nil, id: %s", id)
 		}
+		if (isName && temp.ServiceName != name) || (isNamespace && temp.NamespaceName != namespace) {
+			continue
+		}
 		temp.Config = r.(*model.RoutingConfig)
 
 		out = append(out, &temp)
 	}
 
-	return uint32(len(routeConf)), getRealRouteConfList(out, offset, limit), nil
+	return uint32(len(out)), getRealRouteConfList(out, offset, limit), nil
 }
 
 func toRouteConf(m map[string]interface{}) []*model.RoutingConfig {
@@ -293,10 +305,10 @@ func getRealRouteConfList(routeConf []*model.ExtendRoutingConfig, offset, limit
 		return routeConf
 	}
 	if beginIndex >= endIndex {
-		return routeConf
+		return []*model.ExtendRoutingConfig{}
 	}
 	if beginIndex >= totalCount {
-		return routeConf
+		return []*model.ExtendRoutingConfig{}
 	}
 	if endIndex > totalCount {
 		endIndex = totalCount
@@ -320,4 +332,5 @@ func initRouting(r *model.RoutingConfig) {
 	currTime := time.Now()
 	r.CreateTime = currTime
 	r.ModifyTime = currTime
+	r.Valid = true
 }
@@ -20,15 +20,14 @@ package boltdbStore
 import (
 	"database/sql"
 	"errors"
-	"github.com/polarismesh/polaris-server/common/utils"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
 	api "github.com/polarismesh/polaris-server/common/api/v1"
 	"github.com/polarismesh/polaris-server/common/log"
 	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
 	"github.com/polarismesh/polaris-server/store"
 	"github.com/polarismesh/polaris-server/store/defaultStore"
 )
@@ -68,7 +67,17 @@ func (ss *serviceStore) AddService(s *model.Service) error {
 		return store.NewStatusError(store.EmptyParamsErr, "add Service missing some params")
 	}
 
-	err := ss.handler.SaveValue(tblNameService, s.ID, s)
+	exist, err := ss.getServiceByNameAndNs(s.Name, s.Namespace)
+	if err != nil {
+		return store.Error(err)
+	}
+	// the same id is saved as an overwrite
+	if exist != nil && exist.ID != s.ID {
+		return store.NewStatusError(store.DuplicateEntryErr,
+			"service "+s.Name+" already exists in namespace "+s.Namespace)
+	}
+
+	err = ss.handler.SaveValue(tblNameService, s.ID, s)
 
 	return store.Error(err)
 }
@@ -93,6 +102,9 @@ func (ss *serviceStore) DeleteServiceAlias(name string, namespace string) error
 		log.Errorf("[Store][boltdb] get service alias error, %v", err)
 		return err
 	}
+	if svc == nil {
+		return nil
+	}
 
 	err = ss.handler.DeleteValues(tblNameService, []string{svc.ID})
 	if err != nil {
@@ -110,6 +122,15 @@ func (ss *serviceStore) UpdateServiceAlias(alias *model.Service, needUpdateOwner
 		return store.NewStatusError(store.EmptyParamsErr, "Update Service Alias missing some params")
 	}
 
+	// 源服务已经被删除时不能再更新别名
+	source, err := ss.getServiceByID(alias.Reference)
+	if err != nil {
+		return store.Error(err)
+	}
+	if source == nil {
+		return store.NewStatusError(store.NotFoundService, "source service "+alias.Reference+" not found")
+	}
+
 	properties := make(map[string]interface{})
 	properties[SvcFieldName] = alias.Name
 	properties[SvcFieldNamespace] = alias.Namespace
@@ -123,7 +144,7 @@ func (ss *serviceStore) UpdateServiceAlias(alias *model.Service, needUpdateOwner
 	properties[SvcFieldReference] = alias.Reference
 	properties[SvcFieldModifyTime] = time.Now()
 
-	err := ss.handler.UpdateValue(tblNameService, alias.ID, properties)
+	err = ss.handler.UpdateValue(tblNameService, alias.ID, properties)
 
 	return store.Error(err)
 }
@@ -179,6 +200,9 @@ func (ss *serviceStore) GetSourceServiceToken(name string, namespace string) (*m
 		return nil, nil
 	case err != nil:
 		return nil, err
+	case s.Reference != "":
+		// 别名不是源服务
+		return nil, nil
 	default:
 		out.ID = s.ID
 		out.Token = s.Token
@@ -282,76 +306,33 @@ func (ss *serviceStore) GetMoreServices(
 func (ss *serviceStore) GetServiceAliases(
 	filter map[string]string, offset uint32, limit uint32) (uint32, []*model.ServiceAlias, error) {
 
-	var totalCount uint32
-
 	// find all alias service with filters
-	fields := []string{SvcFieldReference, SvcFieldMeta, SvcFieldDepartment, SvcFieldBusiness}
-	for k := range filter {
-		fields = append(fields, k)
-	}
+	fields := []string{SvcFieldReference, SvcFieldName, SvcFieldOwner}
+
+	aliasName, isAlias := filter["alias"]
+	owner, isOwner := filter["owner"]
 
 	referenceService := make(map[string]bool)
 	services, err := ss.handler.LoadValuesByFilter(tblNameService, fields, &model.Service{},
 		func(m map[string]interface{}) bool {
 			// judge whether it is alias by whether there is a reference
-			reference, err := m[SvcFieldReference]
-			if !err {
-				return false
-			}
-			if reference.(string) == "" {
+			reference, ok := m[SvcFieldReference]
+			if !ok || reference.(string) == "" {
 				return false
 			}
-
-			name, isName := filter["name"]
-			keys, isKeys := filter["keys"]
-			values, isValues := filter["values"]
-			department, isDepartment := filter["department"]
-			business, isBusiness := filter["business"]
-
-			// filter by other
-			if isName {
+			if isAlias {
 				svcName, ok := m[SvcFieldName]
-				if !ok {
-					return false
-				}
-				if svcName.(string) != name {
+				if !ok || svcName.(string) != aliasName {
 					return false
 				}
 			}
-
-			if isKeys {
-				svcMeta, ok := m[SvcFieldMeta]
-				if !ok {
-					return false
-				}
-				metaValue, ok := svcMeta.(map[string]string)[keys]
-				if !ok {
-					return false
-				}
-				if isValues && values != metaValue {
+			if isOwner {
+				svcOwner, ok := m[SvcFieldOwner]
+				if !ok || !strings.Contains(svcOwner.(string), owner) {
 					return false
 				}
 			}
-
-			if isDepartment {
-				svcDepartment, ok := m[SvcFieldDepartment]
-				if !ok {
-					return false
-				}
-				if department != svcDepartment.(string) {
-					return false
-				}
-			}
-			if isBusiness && business != m[SvcFieldBusiness].(string) {
-				svcBusiness, ok := m[SvcFieldBusiness]
-				if !ok {
-					return false
-				}
-				if business != svcBusiness.(string) {
-					return false
-				}
-			}
-			referenceService[m[SvcFieldReference].(string)] = true
+			referenceService[reference.(string)] = true
 			return true
 		})
 	if err != nil {
@@ -362,34 +343,50 @@ func (ss *serviceStore) GetServiceAliases(
 		return 0, []*model.ServiceAlias{}, nil
 	}
 
-	totalCount = uint32(len(services))
-
 	// find source service for every alias
 	fields = []string{SvcFieldID}
 
 	refServices, err := ss.handler.LoadValuesByFilter(tblNameService, fields, &model.Service{},
 		func(m map[string]interface{}) bool {
 			_, ok := referenceService[m[SvcFieldID].(string)]
-			if !ok {
-				return false
-			}
-			return true
+			return ok
 		})
+	if err != nil {
+		log.Errorf("[Store][boltdb] load service from kv error, %v", err)
+		return 0, nil, err
+	}
+
+	// filter by source service, alias without source service is ignored
+	sourceName, isService := filter["service"]
+	sourceNs, isNamespace := filter["namespace"]
+	for id, value := range services {
+		refValue, ok := refServices[value.(*model.Service).Reference]
+		if !ok {
+			delete(services, id)
+			continue
+		}
+		source := refValue.(*model.Service)
+		if (isService && source.Name != sourceName) || (isNamespace && source.Namespace != sourceNs) {
+			delete(services, id)
+		}
+	}
+	totalCount := uint32(len(services))
 
 	// sort and limit
 	s := getRealServicesList(services, offset, limit)
 
 	var serviceAlias []*model.ServiceAlias
 	for _, service := range s {
+		source := refServices[service.Reference].(*model.Service)
 		alias := model.ServiceAlias{}
 		alias.ID = service.ID
 		alias.Alias = service.Name
 		alias.ServiceID = service.Reference
-		alias.Service = refServices[service.Reference].(*model.Service).Name
+		alias.Service = source.Name
 		alias.ModifyTime = service.ModifyTime
 		alias.CreateTime = service.CreateTime
 		alias.Comment = service.Comment
-		alias.Namespace = service.Namespace
+		alias.Namespace = source.Namespace
 		alias.Owner = service.Owner
 
 		serviceAlias = append(serviceAlias, &alias)
@@ -431,10 +428,14 @@ func (ss *serviceStore) GetServicesBatch(services []*model.Service) ([]*model.Se
 
 	fields := []string{SvcFieldName, SvcFieldNamespace}
 
-	serviceInfo := make(map[string]string)
+	// 同名的服务可以出现在多个命名空间下
+	serviceInfo := make(map[string]map[string]bool)
 
 	for _, service := range services {
-		serviceInfo[service.Name] = service.Namespace
+		if _, ok := serviceInfo[service.Name]; !ok {
+			serviceInfo[service.Name] = make(map[string]bool)
+		}
+		serviceInfo[service.Name][service.Namespace] = true
 	}
 
 	svcs, err := ss.handler.LoadValuesByFilter(tblNameService, fields, &model.Service{},
@@ -449,16 +450,7 @@ func (ss *serviceStore) GetServicesBatch(services []*model.Service) ([]*model.Se
 				return false
 			}
 
-			name := svcName.(string)
-			namespace := svcNs.(string)
-			ns, ok := serviceInfo[name]
-			if !ok {
-				return false
-			}
-			if ns != namespace {
-				return false
-			}
-			return true
+			return serviceInfo[svcName.(string)][svcNs.(string)]
 		})
 	if err != nil {
 		log.Errorf("[Store][boltdb] load service from kv error, %v", err)
@@ -531,21 +523,19 @@ func (ss *serviceStore) getServiceByID(id string) (*model.Service, error) {
 		return nil, MultipleSvcFound
 	}
 
-	return svc[id].(*model.Service), err
+	out, ok := svc[id]
+	if !ok {
+		return nil, nil
+	}
+	return out.(*model.Service), nil
 }
 
 func (ss *serviceStore) getServices(serviceFilters, serviceMetas map[string]string,
 	instanceFilters *store.InstanceArgs, offset, limit uint32) (uint32, []*model.Service, error) {
 
 	insFiltersIds := make(map[string]bool)
-	// int array to string array
-	if instanceFilters != nil && (len(instanceFilters.Ports) > 0 || len(instanceFilters.Hosts) > 0) {
-
-		portArray := make([]string, len(instanceFilters.Ports))
-		for i, port := range instanceFilters.Ports {
-			portArray[i] = strconv.Itoa(int(port))
-		}
-
+	isInsFilter := instanceFilters != nil && (len(instanceFilters.Ports) > 0 || len(instanceFilters.Hosts) > 0)
+	if isInsFilter {
 		// get the filtered list of serviceIDs from instanceFilters
 		filter := []string{insFieldProto}
 
@@ -589,15 +579,17 @@ func (ss *serviceStore) getServices(serviceFilters, serviceMetas map[string]stri
 			log.Errorf("[Store][boltdb] load instance from kv error %v", err)
 			return 0, nil, err
 		}
+		// no instance matched, so no service matched either
+		if len(inss) == 0 {
+			return 0, []*model.Service{}, nil
+		}
 		for _, i := range inss {
 			insFiltersIds[i.(*model.Instance).ServiceID] = true
 		}
 	}
 
-	fields := []string{SvcFieldName, SvcFieldMeta, SvcFieldDepartment, SvcFieldBusiness}
-	if len(insFiltersIds) > 0 {
-		fields = append(fields, SvcFieldID)
-	}
+	fields := []string{SvcFieldID, SvcFieldName, SvcFieldNamespace, SvcFieldMeta,
+		SvcFieldDepartment, SvcFieldBusiness, SvcFieldReference}
 
 	isKeys := true
 	isValues := true
@@ -619,13 +611,18 @@ func (ss *serviceStore) getServices(serviceFilters, serviceMetas map[string]stri
 	}
 
 	name, isName := serviceFilters["name"]
+	namespace, isNamespace := serviceFilters["namespace"]
 	department, isDepartment := serviceFilters["department"]
 	business, isBusiness := serviceFilters["business"]
 
 	svcs, err := ss.handler.LoadValuesByFilter(tblNameService, fields, &model.Service{},
 		func(m map[string]interface{}) bool {
+			// alias is not a service
+			if reference, ok := m[SvcFieldReference]; ok && reference.(string) != "" {
+				return false
+			}
 			// filter by id
-			if len(insFiltersIds) > 0 {
+			if isInsFilter {
 				svcId, ok := m[SvcFieldID]
 				if !ok {
 					return false
@@ -636,18 +633,11 @@ func (ss *serviceStore) getServices(serviceFilters, serviceMetas map[string]stri
 				}
 			}
 			// filter by other
-			if isName {
-				svcName, ok := m[SvcFieldName]
-				if !ok {
-					return false
-				}
-				if utils.IsWildName(name) {
-					return strings.Contains(svcName.(string), name[0:len(name)-1])
-				} else {
-					if svcName.(string) != name {
-						return false
-					}
-				}
+			if isName && !matchServiceField(m, SvcFieldName, name) {
+				return false
+			}
+			if isNamespace && !matchServiceField(m, SvcFieldNamespace, namespace) {
+				return false
 			}
 
 			if isKeys {
@@ -664,31 +654,18 @@ func (ss *serviceStore) getServices(serviceFilters, serviceMetas map[string]stri
 				}
 			}
 
-			if isDepartment {
-				svcDepartment, ok := m[SvcFieldDepartment]
-				if !ok {
-					return false
-				}
-				if utils.IsWildName(department) {
-					return strings.Contains(svcDepartment.(string), department[0:len(department)-1])
-				} else {
-					if svcDepartment.(string) != department {
-						return false
-					}
-				}
+			if isDepartment && !matchServiceField(m, SvcFieldDepartment, department) {
+				return false
 			}
 
+			// business is fuzzy matched as the database does
 			if isBusiness {
 				svcBusiness, ok := m[SvcFieldBusiness]
 				if !ok {
 					return false
 				}
-				if utils.IsWildName(business) {
-					return strings.Contains(svcBusiness.(string), business[0:len(business)-1])
-				} else {
-					if svcBusiness.(string) != business {
-						return false
-					}
+				if !strings.Contains(svcBusiness.(string), strings.TrimSuffix(business, "*")) {
+					return false
 				}
 			}
 
@@ -702,6 +679,18 @@ func (ss *serviceStore) getServices(serviceFilters, serviceMetas map[string]stri
 	return uint32(totalCount), getRealServicesList(svcs, offset, limit), nil
 }
 
+// matchServiceField match field with value, wildcard value is fuzzy matched
+func matchServiceField(m map[string]interface{}, field string, value string) bool {
+	fieldValue, ok := m[field]
+	if !ok {
+		return false
+	}
+	if utils.IsWildName(value) {
+		return strings.Contains(fieldValue.(string), value[0:len(value)-1])
+	}
+	return fieldValue.(string) == value
+}
+
 func getRealServicesList(originServices map[string]interface{}, offset, limit uint32) []*model.Service {
 	services := make([]*model.Service, 0)
 	beginIndex := offset
@@ -428,7 +428,7 @@ func TestServiceStore_UpdateServiceAlias(t *testing.T) {
 		Owner:      "testo",
 		Token:      "t1",
 		Revision:   "modifyRevision2",
-		Reference:  "m1",
+		Reference:  "svcid1",
 		Business:   "modifyBusiness",
 		Department: "modifyDepartment",
 	}, true)
@@ -0,0 +1,197 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package conformance
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 用户的新增、更新以及删除，名字重复时返回错误
+func testAuthUser(t *testing.T, s store.Store) {
+	user := &model.User{ID: newID(), Name: uniqueName("user"), Password: newID(), Comment: "conformance user"}
+	if err := s.AddUser(user); err != nil {
+		t.Fatalf("add user err: %s", err.Error())
+	}
+	checkUser(t, s, user)
+	duplicated := &model.User{ID: newID(), Name: user.Name, Password: newID()}
+	if err := s.AddUser(duplicated); store.Code(err) != store.DuplicateEntryErr {
+		t.Fatalf("add duplicated user should return duplicate entry, got %v", err)
+	}
+
+	user.Password = newID()
+	user.Comment = "updated comment"
+	if err := s.UpdateUser(user); err != nil {
+		t.Fatalf("update user err: %s", err.Error())
+	}
+	checkUser(t, s, user)
+
+	if err := s.DeleteUser(user.Name); err != nil {
+		t.Fatalf("delete user err: %s", err.Error())
+	}
+	users, err := s.GetUsers()
+	if err != nil {
+		t.Fatalf("get users err: %s", err.Error())
+	}
+	for _, entry := range users {
+		if entry.Name == user.Name {
+			t.Fatalf("deleted user should not be returned")
+		}
+	}
+}
+
+// 用户组的新增、更新以及删除，名字重复时返回错误
+func testAuthUserGroup(t *testing.T, s store.Store) {
+	group := &model.UserGroup{
+		ID:      newID(),
+		Name:    uniqueName("group"),
+		Comment: "conformance group",
+		Users:   []string{"user-1", "user-2"},
+	}
+	if err := s.AddUserGroup(group); err != nil {
+		t.Fatalf("add user group err: %s", err.Error())
+	}
+	checkUserGroup(t, s, group)
+	duplicated := &model.UserGroup{ID: newID(), Name: group.Name}
+	if err := s.AddUserGroup(duplicated); store.Code(err) != store.DuplicateEntryErr {
+		t.Fatalf("add duplicated user group should return duplicate entry, got %v", err)
+	}
+
+	group.Comment = "updated comment"
+	group.Users = []string{"user-3"}
+	if err := s.UpdateUserGroup(group); err != nil {
+		t.Fatalf("update user group err: %s", err.Error())
+	}
+	checkUserGroup(t, s, group)
+
+	if err := s.DeleteUserGroup(group.Name); err != nil {
+		t.Fatalf("delete user group err: %s", err.Error())
+	}
+	if findUserGroup(t, s, group.Name) != nil {
+		t.Fatalf("deleted user group should not be returned")
+	}
+}
+
+// 角色的新增、更新以及删除，成员以及权限策略完整保存
+func testAuthRole(t *testing.T, s store.Store) {
+	role := &model.Role{
+		ID:      newID(),
+		Name:    uniqueName("role"),
+		Comment: "conformance role",
+		Users:   []string{"user-1"},
+		Groups:  []string{"group-1"},
+		Policies: []*model.AuthPolicy{
+			{Resource: "service", Namespace: "Test", Service: "*", Actions: []string{"read", "write"}},
+			{Resource: "namespace", Actions: []string{"read"}},
+		},
+	}
+	if err := s.AddRole(role); err != nil {
+		t.Fatalf("add role err: %s", err.Error())
+	}
+	checkRole(t, s, role)
+	duplicated := &model.Role{ID: newID(), Name: role.Name}
+	if err := s.AddRole(duplicated); store.Code(err) != store.DuplicateEntryErr {
+		t.Fatalf("add duplicated role should return duplicate entry, got %v", err)
+	}
+
+	role.Comment = "updated comment"
+	role.Users = []string{"user-2", "user-3"}
+	role.Groups = []string{}
+	role.Policies = []*model.AuthPolicy{{Resource: "instance", Namespace: "*", Actions: []string{"write"}}}
+	if err := s.UpdateRole(role); err != nil {
+		t.Fatalf("update role err: %s", err.Error())
+	}
+	checkRole(t, s, role)
+
+	if err := s.DeleteRole(role.Name); err != nil {
+		t.Fatalf("delete role err: %s", err.Error())
+	}
+	if findRole(t, s, role.Name) != nil {
+		t.Fatalf("deleted role should not be returned")
+	}
+}
+
+func checkUser(t *testing.T, s store.Store, expect *model.User) {
+	users, err := s.GetUsers()
+	if err != nil {
+		t.Fatalf("get users err: %s", err.Error())
+	}
+	for _, got := range users {
+		if got.Name != expect.Name {
+			continue
+		}
+		if got.ID != expect.ID || got.Password != expect.Password || got.Comment != expect.Comment {
+			t.Fatalf("user not match, expect %+v, got %+v", expect, got)
+		}
+		if !isRecent(got.CreateTime) || !isRecent(got.ModifyTime) {
+			t.Fatalf("user ctime(%s) and mtime(%s) should be generated by store", got.CreateTime, got.ModifyTime)
+		}
+		return
+	}
+	t.Fatalf("user(%s) should be returned", expect.Name)
+}
+
+func checkUserGroup(t *testing.T, s store.Store, expect *model.UserGroup) {
+	got := findUserGroup(t, s, expect.Name)
+	if got == nil {
+		t.Fatalf("user group(%s) should be returned", expect.Name)
+	}
+	if got.ID != expect.ID || got.Comment != expect.Comment || !reflect.DeepEqual(got.Users, expect.Users) {
+		t.Fatalf("user group not match, expect %+v, got %+v", expect, got)
+	}
+}
+
+func checkRole(t *testing.T, s store.Store, expect *model.Role) {
+	got := findRole(t, s, expect.Name)
+	if got == nil {
+		t.Fatalf("role(%s) should be returned", expect.Name)
+	}
+	if got.ID != expect.ID || got.Comment != expect.Comment || !reflect.DeepEqual(got.Users, expect.Users) ||
+		len(got.Groups) != len(expect.Groups) || !reflect.DeepEqual(got.Policies, expect.Policies) {
+		t.Fatalf("role not match, expect %+v, got %+v", expect, got)
+	}
+}
+
+func findUserGroup(t *testing.T, s store.Store, name string) *model.UserGroup {
+	groups, err := s.GetUserGroups()
+	if err != nil {
+		t.Fatalf("get user groups err: %s", err.Error())
+	}
+	for _, entry := range groups {
+		if entry.Name == name {
+			return entry
+		}
+	}
+	return nil
+}
+
+func findRole(t *testing.T, s store.Store, name string) *model.Role {
+	roles, err := s.GetRoles()
+	if err != nil {
+		t.Fatalf("get roles err: %s", err.Error())
+	}
+	for _, entry := range roles {
+		if entry.Name == name {
+			return entry
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package conformance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 业务集的增删改查以及增量拉取
+func testBusinessLifecycle(t *testing.T, s store.Store) {
+	business := &model.Business{ID: newID(), Name: uniqueName("biz"), Token: newID(), Owner: testOwner}
+	if err := s.AddBusiness(business); err != nil {
+		t.Fatalf("add business err: %s", err.Error())
+	}
+	got, err := s.GetBusinessByID(business.ID)
+	if err != nil {
+		t.Fatalf("get business err: %s", err.Error())
+	}
+	if got == nil || !got.Valid || got.Name != business.Name || got.Token != business.Token ||
+		got.Owner != business.Owner || !isRecent(got.ModifyTime) {
+		t.Fatalf("business not match, expect %+v, got %+v", business, got)
+	}
+	list, err := s.ListBusiness(testOwner)
+	if err != nil {
+		t.Fatalf("list business err: %s", err.Error())
+	}
+	if findBusiness(list, business.ID) == nil {
+		t.Fatalf("business(%s) should be listed by owner", business.ID)
+	}
+
+	update := &model.Business{ID: business.ID, Name: uniqueName("biz"), Owner: testOwner + "-new"}
+	if err := s.UpdateBusiness(update); err != nil {
+		t.Fatalf("update business err: %s", err.Error())
+	}
+	token := newID()
+	if err := s.UpdateBusinessToken(business.ID, token); err != nil {
+		t.Fatalf("update business token err: %s", err.Error())
+	}
+	got, err = s.GetBusinessByID(business.ID)
+	if err != nil {
+		t.Fatalf("get business err: %s", err.Error())
+	}
+	if got.Name != update.Name || got.Owner != update.Owner || got.Token != token {
+		t.Fatalf("business is not updated: %+v", got)
+	}
+
+	mtime := truncate(got.ModifyTime)
+	more, err := s.GetMoreBusiness(mtime)
+	if err != nil {
+		t.Fatalf("get more business err: %s", err.Error())
+	}
+	if entry := findBusiness(more, business.ID); entry == nil || !entry.Valid {
+		t.Fatalf("business modified at mtime should be returned, got %+v", entry)
+	}
+	more, err = s.GetMoreBusiness(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("get more business err: %s", err.Error())
+	}
+	if findBusiness(more, business.ID) != nil {
+		t.Fatalf("business modified before mtime should not be returned")
+	}
+
+	if err := s.DeleteBusiness(business.ID); err != nil {
+		t.Fatalf("delete business err: %s", err.Error())
+	}
+	got, err = s.GetBusinessByID(business.ID)
+	if err != nil {
+		t.Fatalf("get business err: %s", err.Error())
+	}
+	if got != nil && got.Valid {
+		t.Fatalf("deleted business should not be valid")
+	}
+	more, err = s.GetMoreBusiness(mtime)
+	if err != nil {
+		t.Fatalf("get more business err: %s", err.Error())
+	}
+	if entry := findBusiness(more, business.ID); entry == nil || entry.Valid {
+		t.Fatalf("deleted business should be returned with valid=false, got %+v", entry)
+	}
+}
+
+func findBusiness(businesses []*model.Business, id string) *model.Business {
+	for _, entry := range businesses {
+		if entry.ID == id {
+			return entry
+		}
+	}
+	return nil
+}
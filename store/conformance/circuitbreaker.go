@@ -0,0 +1,290 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package conformance
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// master版本的熔断规则
+const masterVersion = "master"
+
+// 熔断规则的新增、更新、打标签以及删除
+func testCircuitBreakerLifecycle(t *testing.T, s store.Store) {
+	namespace := addNamespace(t, s)
+	master := addCircuitBreaker(t, s, namespace.Name)
+
+	got, err := s.GetCircuitBreaker(master.ID, masterVersion)
+	if err != nil {
+		t.Fatalf("get circuit breaker err: %s", err.Error())
+	}
+	checkCircuitBreaker(t, master, got)
+
+	master.Inbounds = `[{"sources":[{"service":"updated"}]}]`
+	master.Comment = "updated comment"
+	master.Revision = newID()
+	if err := s.UpdateCircuitBreaker(master); err != nil {
+		t.Fatalf("update circuit breaker err: %s", err.Error())
+	}
+	got, err = s.GetCircuitBreaker(master.ID, masterVersion)
+	if err != nil {
+		t.Fatalf("get circuit breaker err: %s", err.Error())
+	}
+	checkCircuitBreaker(t, master, got)
+
+	tag := tagCircuitBreaker(t, s, master, "1.0.0")
+	got, err = s.GetCircuitBreaker(master.ID, tag.Version)
+	if err != nil {
+		t.Fatalf("get circuit breaker tag err: %s", err.Error())
+	}
+	checkCircuitBreaker(t, tag, got)
+	versions, err := s.GetCircuitBreakerVersions(master.ID)
+	if err != nil {
+		t.Fatalf("get circuit breaker versions err: %s", err.Error())
+	}
+	sort.Strings(versions)
+	if len(versions) != 2 || versions[0] != tag.Version || versions[1] != masterVersion {
+		t.Fatalf("circuit breaker versions should be master and tag, got %v", versions)
+	}
+
+	unknown := *master
+	unknown.ID = newID()
+	unknown.Version = "1.0.0"
+	if err := s.TagCircuitBreaker(&unknown); store.Code(err) != store.NotFoundMasterConfig {
+		t.Fatalf("tag circuit breaker without master should return not found master, got %v", err)
+	}
+
+	detail, err := s.ListMasterCircuitBreakers(map[string]string{"id": master.ID}, 0, 10)
+	if err != nil {
+		t.Fatalf("list master circuit breakers err: %s", err.Error())
+	}
+	if detail.Total != 1 || len(detail.CircuitBreakerInfos) != 1 ||
+		detail.CircuitBreakerInfos[0].CircuitBreaker.Version != masterVersion {
+		t.Fatalf("only master circuit breaker should be listed, got %d", detail.Total)
+	}
+
+	if err := s.DeleteTagCircuitBreaker(master.ID, tag.Version); err != nil {
+		t.Fatalf("delete circuit breaker tag err: %s", err.Error())
+	}
+	if got, err := s.GetCircuitBreaker(master.ID, tag.Version); err != nil || got != nil {
+		t.Fatalf("deleted circuit breaker tag should not be returned, got %+v, err: %v", got, err)
+	}
+	if err := s.DeleteMasterCircuitBreaker(master.ID); err != nil {
+		t.Fatalf("delete master circuit breaker err: %s", err.Error())
+	}
+	if got, err := s.GetCircuitBreaker(master.ID, masterVersion); err != nil || got != nil {
+		t.Fatalf("deleted master circuit breaker should not be returned, got %+v, err: %v", got, err)
+	}
+	detail, err = s.ListMasterCircuitBreakers(map[string]string{"id": master.ID}, 0, 10)
+	if err != nil {
+		t.Fatalf("list master circuit breakers err: %s", err.Error())
+	}
+	if detail.Total != 0 {
+		t.Fatalf("deleted master circuit breaker should not be listed, got %d", detail.Total)
+	}
+}
+
+// 发布以及解绑熔断规则，已绑定服务的规则不能被删除
+func testCircuitBreakerRelease(t *testing.T, s store.Store) {
+	namespace, service := addNamespaceAndService(t, s)
+	master := addCircuitBreaker(t, s, namespace.Name)
+	tag := tagCircuitBreaker(t, s, master, "1.0.0")
+
+	unknown := &model.CircuitBreakerRelation{ServiceID: service.ID, RuleID: master.ID, RuleVersion: "2.0.0"}
+	if err := s.ReleaseCircuitBreaker(unknown); err == nil {
+		t.Fatalf("release not existed circuit breaker tag should return error")
+	}
+	relation := &model.CircuitBreakerRelation{ServiceID: service.ID, RuleID: master.ID, RuleVersion: tag.Version}
+	if err := s.ReleaseCircuitBreaker(relation); err != nil {
+		t.Fatalf("release circuit breaker err: %s", err.Error())
+	}
+
+	relations, err := s.GetCircuitBreakerRelation(master.ID, tag.Version)
+	if err != nil {
+		t.Fatalf("get circuit breaker relation err: %s", err.Error())
+	}
+	if len(relations) != 1 || relations[0].ServiceID != service.ID || !relations[0].Valid {
+		t.Fatalf("circuit breaker relation not match: %+v", relations)
+	}
+	relations, err = s.GetCircuitBreakerMasterRelation(master.ID)
+	if err != nil {
+		t.Fatalf("get circuit breaker master relation err: %s", err.Error())
+	}
+	if len(relations) != 1 || relations[0].RuleVersion != tag.Version {
+		t.Fatalf("circuit breaker master relation not match: %+v", relations)
+	}
+	got, err := s.GetCircuitBreakersByService(service.Name, service.Namespace)
+	if err != nil {
+		t.Fatalf("get circuit breaker by service err: %s", err.Error())
+	}
+	if got == nil || got.ID != master.ID || got.Version != tag.Version || got.Inbounds != tag.Inbounds {
+		t.Fatalf("circuit breaker of service not match: %+v", got)
+	}
+	detail, err := s.ListReleaseCircuitBreakers(map[string]string{"rule_id": master.ID}, 0, 10)
+	if err != nil {
+		t.Fatalf("list release circuit breakers err: %s", err.Error())
+	}
+	if detail.Total != 1 || len(detail.CircuitBreakerInfos) != 1 ||
+		len(detail.CircuitBreakerInfos[0].Services) != 1 ||
+		detail.CircuitBreakerInfos[0].Services[0].Name != service.Name {
+		t.Fatalf("released circuit breaker should be listed with service, got %d", detail.Total)
+	}
+
+	// 绑定了服务的规则不会被删除
+	if err := s.DeleteTagCircuitBreaker(master.ID, tag.Version); err != nil {
+		t.Fatalf("delete circuit breaker tag err: %s", err.Error())
+	}
+	if err := s.DeleteMasterCircuitBreaker(master.ID); err != nil {
+		t.Fatalf("delete master circuit breaker err: %s", err.Error())
+	}
+	if got, err := s.GetCircuitBreaker(master.ID, tag.Version); err != nil || got == nil {
+		t.Fatalf("released circuit breaker tag should not be deleted, err: %v", err)
+	}
+	if got, err := s.GetCircuitBreaker(master.ID, masterVersion); err != nil || got == nil {
+		t.Fatalf("released master circuit breaker should not be deleted, err: %v", err)
+	}
+
+	if err := s.UnbindCircuitBreaker(service.ID, master.ID, tag.Version); err != nil {
+		t.Fatalf("unbind circuit breaker err: %s", err.Error())
+	}
+	if got, err := s.GetCircuitBreakersByService(service.Name, service.Namespace); err != nil || got != nil {
+		t.Fatalf("unbound circuit breaker should not be returned by service, got %+v, err: %v", got, err)
+	}
+	if relations, err := s.GetCircuitBreakerRelation(master.ID, tag.Version); err != nil || len(relations) != 0 {
+		t.Fatalf("unbound relation should not be returned, got %+v, err: %v", relations, err)
+	}
+	if err := s.DeleteTagCircuitBreaker(master.ID, tag.Version); err != nil {
+		t.Fatalf("delete circuit breaker tag err: %s", err.Error())
+	}
+	if got, err := s.GetCircuitBreaker(master.ID, tag.Version); err != nil || got != nil {
+		t.Fatalf("unbound circuit breaker tag should be deleted, got %+v, err: %v", got, err)
+	}
+}
+
+// 增量拉取服务绑定的熔断规则，非首次拉取时返回已解绑的关系
+func testCircuitBreakerForCache(t *testing.T, s store.Store) {
+	namespace, service := addNamespaceAndService(t, s)
+	master := addCircuitBreaker(t, s, namespace.Name)
+	tag := tagCircuitBreaker(t, s, master, "1.0.0")
+	relation := &model.CircuitBreakerRelation{ServiceID: service.ID, RuleID: master.ID, RuleVersion: tag.Version}
+	if err := s.ReleaseCircuitBreaker(relation); err != nil {
+		t.Fatalf("release circuit breaker err: %s", err.Error())
+	}
+	relations, err := s.GetCircuitBreakerRelation(master.ID, tag.Version)
+	if err != nil || len(relations) != 1 {
+		t.Fatalf("get circuit breaker relation err: %v", err)
+	}
+	mtime := truncate(relations[0].ModifyTime).Add(-time.Second)
+
+	for _, firstUpdate := range []bool{true, false} {
+		out, err := s.GetCircuitBreakerForCache(mtime, firstUpdate)
+		if err != nil {
+			t.Fatalf("get circuit breakers for cache err: %s", err.Error())
+		}
+		entry := findServiceCircuitBreaker(out, service.ID)
+		if entry == nil || !entry.Valid || entry.CircuitBreaker == nil ||
+			entry.CircuitBreaker.ID != master.ID || entry.CircuitBreaker.Version != tag.Version ||
+			entry.CircuitBreaker.Revision != tag.Revision {
+			t.Fatalf("circuit breaker of service should be returned for cache(first %v), got %+v", firstUpdate, entry)
+		}
+	}
+
+	if err := s.UnbindCircuitBreaker(service.ID, master.ID, tag.Version); err != nil {
+		t.Fatalf("unbind circuit breaker err: %s", err.Error())
+	}
+	out, err := s.GetCircuitBreakerForCache(mtime, false)
+	if err != nil {
+		t.Fatalf("get circuit breakers for cache err: %s", err.Error())
+	}
+	if entry := findServiceCircuitBreaker(out, service.ID); entry == nil || entry.Valid {
+		t.Fatalf("unbound circuit breaker should be returned with valid=false, got %+v", entry)
+	}
+	out, err = s.GetCircuitBreakerForCache(mtime, true)
+	if err != nil {
+		t.Fatalf("get circuit breakers for cache err: %s", err.Error())
+	}
+	if findServiceCircuitBreaker(out, service.ID) != nil {
+		t.Fatalf("unbound circuit breaker should not be returned when first update")
+	}
+}
+
+// 新增一条master版本的熔断规则
+func addCircuitBreaker(t *testing.T, s store.Store, namespace string) *model.CircuitBreaker {
+	cb := &model.CircuitBreaker{
+		ID:         newID(),
+		Version:    masterVersion,
+		Name:       uniqueName("cb"),
+		Namespace:  namespace,
+		Business:   "conformance-business",
+		Department: "conformance-department",
+		Comment:    "conformance circuit breaker",
+		Inbounds:   `[{"sources":[{"service":"*"}]}]`,
+		Outbounds:  `[{"destinations":[{"service":"*"}]}]`,
+		Token:      newID(),
+		Owner:      testOwner,
+		Revision:   newID(),
+	}
+	if err := s.CreateCircuitBreaker(cb); err != nil {
+		t.Fatalf("create circuit breaker(%s) err: %s", cb.Name, err.Error())
+	}
+	return cb
+}
+
+// 给master版本的熔断规则打标签
+func tagCircuitBreaker(t *testing.T, s store.Store, master *model.CircuitBreaker,
+	version string) *model.CircuitBreaker {
+	tag := *master
+	tag.Version = version
+	tag.Revision = newID()
+	if err := s.TagCircuitBreaker(&tag); err != nil {
+		t.Fatalf("tag circuit breaker(%s, %s) err: %s", master.Name, version, err.Error())
+	}
+	return &tag
+}
+
+func checkCircuitBreaker(t *testing.T, expect *model.CircuitBreaker, got *model.CircuitBreaker) {
+	if got == nil {
+		t.Fatalf("circuit breaker(%s, %s) should be returned", expect.ID, expect.Version)
+	}
+	if got.ID != expect.ID || got.Version != expect.Version || got.Name != expect.Name ||
+		got.Namespace != expect.Namespace || got.Business != expect.Business ||
+		got.Department != expect.Department || got.Comment != expect.Comment ||
+		got.Inbounds != expect.Inbounds || got.Outbounds != expect.Outbounds || got.Token != expect.Token ||
+		got.Owner != expect.Owner || got.Revision != expect.Revision || !got.Valid {
+		t.Fatalf("circuit breaker not match, expect %+v, got %+v", expect, got)
+	}
+	if !isRecent(got.CreateTime) || !isRecent(got.ModifyTime) {
+		t.Fatalf("circuit breaker ctime(%s) and mtime(%s) should be generated by store",
+			got.CreateTime, got.ModifyTime)
+	}
+}
+
+func findServiceCircuitBreaker(entries []*model.ServiceWithCircuitBreaker,
+	serviceID string) *model.ServiceWithCircuitBreaker {
+	for _, entry := range entries {
+		if entry.ServiceID == serviceID {
+			return entry
+		}
+	}
+	return nil
+}
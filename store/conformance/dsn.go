@@ -0,0 +1,59 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package conformance
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+/**
+ * LoadDSN 从环境变量读取一致性测试使用的数据库，未配置时跳过测试
+ * @note 格式为user:pwd@addr/dbname，数据库需要预先导入对应存储插件的polaris_server.sql
+ *       CI中由.github/workflows/testing.yml的conformance任务启动数据库并设置环境变量
+ */
+func LoadDSN(t *testing.T, env string) map[interface{}]interface{} {
+	dsn := os.Getenv(env)
+	if dsn == "" {
+		t.Skipf("%s is not set", env)
+	}
+	master, ok := ParseDSN(dsn)
+	if !ok {
+		t.Fatalf("%s should be user:pwd@addr/dbname", env)
+	}
+	return master
+}
+
+/**
+ * ParseDSN 把user:pwd@addr/dbname解析为存储插件的master配置
+ */
+func ParseDSN(dsn string) (map[interface{}]interface{}, bool) {
+	at := strings.LastIndex(dsn, "@")
+	slash := strings.LastIndex(dsn, "/")
+	colon := strings.Index(dsn, ":")
+	if at < 0 || slash < at || colon < 0 || colon > at {
+		return nil, false
+	}
+	return map[interface{}]interface{}{
+		"dbUser": dsn[:colon],
+		"dbPwd":  dsn[colon+1 : at],
+		"dbAddr": dsn[at+1 : slash],
+		"dbName": dsn[slash+1:],
+	}, true
+}
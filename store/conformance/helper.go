@@ -0,0 +1,137 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package conformance
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 场景里使用的负责人
+const testOwner = "conformance"
+
+// 生成32位的ID，与server生成的ID长度一致
+func newID() string {
+	id := uuid.New()
+	return hex.EncodeToString(id[:])
+}
+
+// 生成不重复的名字，多个场景共享同一个数据库时互不影响
+func uniqueName(prefix string) string {
+	return prefix + "-" + newID()[:8]
+}
+
+// 新增一个命名空间
+func addNamespace(t *testing.T, s store.Store) *model.Namespace {
+	namespace := &model.Namespace{
+		Name:    uniqueName("ns"),
+		Comment: "conformance namespace",
+		Token:   newID(),
+		Owner:   testOwner,
+	}
+	if err := s.AddNamespace(namespace); err != nil {
+		t.Fatalf("add namespace(%s) err: %s", namespace.Name, err.Error())
+	}
+	return namespace
+}
+
+// 构造一个服务
+func newService(namespace string, name string) *model.Service {
+	return &model.Service{
+		ID:         newID(),
+		Name:       name,
+		Namespace:  namespace,
+		Business:   "conformance-business",
+		Ports:      "8080",
+		Meta:       map[string]string{"env": "test"},
+		Comment:    "conformance service",
+		Department: "conformance-department",
+		Token:      newID(),
+		Owner:      testOwner,
+		Revision:   newID(),
+	}
+}
+
+// 新增一个服务
+func addService(t *testing.T, s store.Store, namespace string) *model.Service {
+	service := newService(namespace, uniqueName("svc"))
+	if err := s.AddService(service); err != nil {
+		t.Fatalf("add service(%s, %s) err: %s", service.Name, service.Namespace, err.Error())
+	}
+	return service
+}
+
+// 构造一个实例
+func newInstance(service *model.Service, host string, port uint32) *model.Instance {
+	instance := utils.CreateInstanceModel(service.ID, &api.Instance{
+		Id:       utils.NewStringValue(newID()),
+		Host:     utils.NewStringValue(host),
+		Port:     utils.NewUInt32Value(port),
+		Protocol: utils.NewStringValue("grpc"),
+		Version:  utils.NewStringValue("1.0.0"),
+		Metadata: map[string]string{"env": "test"},
+		Location: &api.Location{
+			Region: utils.NewStringValue("south-china"),
+			Zone:   utils.NewStringValue("shenzhen"),
+			Campus: utils.NewStringValue("nanshan"),
+		},
+	})
+	instance.Proto.Service = utils.NewStringValue(service.Name)
+	instance.Proto.Namespace = utils.NewStringValue(service.Namespace)
+	instance.Valid = true
+	return instance
+}
+
+// 新增一个实例
+func addInstance(t *testing.T, s store.Store, service *model.Service, host string, port uint32) *model.Instance {
+	instance := newInstance(service, host, port)
+	if err := s.AddInstance(instance); err != nil {
+		t.Fatalf("add instance(%s:%d) err: %s", host, port, err.Error())
+	}
+	return instance
+}
+
+// 新增一个命名空间以及其中的一个服务
+func addNamespaceAndService(t *testing.T, s store.Store) (*model.Namespace, *model.Service) {
+	namespace := addNamespace(t, s)
+	return namespace, addService(t, s, namespace.Name)
+}
+
+// 判断时间是否由存储层在写入时生成，允许数据库与本机存在一定的时钟偏差
+func isRecent(tm time.Time) bool {
+	return tm.After(time.Now().Add(-time.Hour)) && tm.Before(time.Now().Add(time.Hour))
+}
+
+// 截断到秒，与数据库保存的精度一致
+func truncate(mtime time.Time) time.Time {
+	return time.Unix(mtime.Unix(), 0)
+}
+
+// 唯一的主机地址，实例按照host过滤时不受其他场景的影响
+func uniqueHost() string {
+	id := uuid.New()
+	return fmt.Sprintf("10.%d.%d.%d", id[0], id[1], id[2])
+}
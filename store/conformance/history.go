@@ -0,0 +1,132 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package conformance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 新增以及查询操作记录，按照ID倒序，时间范围包含边界
+func testHistoryAddAndQuery(t *testing.T, s store.Store) {
+	namespace := uniqueName("ns")
+	ctime := truncate(time.Now())
+	var histories []*model.History
+	for _, operation := range []string{"Create", "Update", "Delete"} {
+		history := addHistory(t, s, namespace, operation, ctime)
+		histories = append(histories, history)
+	}
+	if histories[0].ID == 0 || histories[0].ID >= histories[1].ID || histories[1].ID >= histories[2].ID {
+		t.Fatalf("history id should be increasing, got %d, %d, %d",
+			histories[0].ID, histories[1].ID, histories[2].ID)
+	}
+
+	total, out, err := s.GetHistories(map[string]string{"namespace": namespace}, ctime, ctime, 0, 2)
+	if err != nil {
+		t.Fatalf("get histories err: %s", err.Error())
+	}
+	if total != 3 || len(out) != 2 || out[0].ID != histories[2].ID || out[1].ID != histories[1].ID {
+		t.Fatalf("histories should be ordered by id desc, total: %d, size: %d", total, len(out))
+	}
+	got := out[0]
+	if got.ResourceType != histories[2].ResourceType || got.OperationType != histories[2].OperationType ||
+		got.Namespace != namespace || got.Service != histories[2].Service || got.Context != histories[2].Context ||
+		got.Operator != histories[2].Operator || got.Revision != histories[2].Revision ||
+		got.Before != histories[2].Before || got.After != histories[2].After || !got.CreateTime.Equal(ctime) {
+		t.Fatalf("history not match, expect %+v, got %+v", histories[2], got)
+	}
+
+	cases := []struct {
+		filter     map[string]string
+		start, end time.Time
+		expect     uint32
+	}{
+		{map[string]string{"namespace": namespace, "operation": "Update"}, time.Time{}, time.Time{}, 1},
+		{map[string]string{"namespace": namespace, "resource": "Service"}, time.Time{}, time.Time{}, 3},
+		{map[string]string{"namespace": namespace, "operator": testOwner}, time.Time{}, time.Time{}, 3},
+		{map[string]string{"namespace": namespace, "service": uniqueName("svc")}, time.Time{}, time.Time{}, 0},
+		{map[string]string{"namespace": namespace}, ctime.Add(time.Second), time.Time{}, 0},
+		{map[string]string{"namespace": namespace}, time.Time{}, ctime.Add(-time.Second), 0},
+	}
+	for _, c := range cases {
+		total, _, err := s.GetHistories(c.filter, c.start, c.end, 0, 10)
+		if err != nil {
+			t.Fatalf("get histories by filter(%v) err: %s", c.filter, err.Error())
+		}
+		if total != c.expect {
+			t.Fatalf("histories by filter(%v, %s, %s) should be %d, got %d", c.filter, c.start, c.end, c.expect, total)
+		}
+	}
+
+	if _, _, err := s.GetHistories(map[string]string{"unknown": "value"}, time.Time{}, time.Time{}, 0, 10); err == nil {
+		t.Fatalf("get histories by unknown filter should return error")
+	}
+}
+
+// 删除指定时间之前的操作记录
+func testHistoryDelete(t *testing.T, s store.Store) {
+	namespace := uniqueName("ns")
+	// 使用很早的时间，避免删除其他场景的数据
+	expired := time.Date(2001, 1, 1, 0, 0, 0, 0, time.Local)
+	addHistory(t, s, namespace, "Create", expired)
+	addHistory(t, s, namespace, "Update", expired.Add(time.Hour))
+	kept := addHistory(t, s, namespace, "Delete", expired.Add(2*time.Hour))
+
+	count, err := s.DeleteHistories(expired.Add(time.Hour + time.Minute))
+	if err != nil {
+		t.Fatalf("delete histories err: %s", err.Error())
+	}
+	if count < 2 {
+		t.Fatalf("at least 2 histories should be deleted, got %d", count)
+	}
+	total, out, err := s.GetHistories(map[string]string{"namespace": namespace}, time.Time{}, time.Time{}, 0, 10)
+	if err != nil {
+		t.Fatalf("get histories err: %s", err.Error())
+	}
+	if total != 1 || len(out) != 1 || out[0].ID != kept.ID {
+		t.Fatalf("only history after time should be kept, got %d", total)
+	}
+
+	if _, err := s.DeleteHistories(expired.Add(3 * time.Hour)); err != nil {
+		t.Fatalf("delete histories err: %s", err.Error())
+	}
+}
+
+// 新增一条服务的操作记录
+func addHistory(t *testing.T, s store.Store, namespace string, operation string,
+	ctime time.Time) *model.History {
+	history := &model.History{
+		ResourceType:  "Service",
+		OperationType: operation,
+		Namespace:     namespace,
+		Service:       "conformance-service",
+		Context:       "conformance history",
+		Operator:      testOwner,
+		Revision:      newID(),
+		Before:        `{"name":"before"}`,
+		After:         `{"name":"after"}`,
+		CreateTime:    ctime,
+	}
+	if err := s.AddHistory(history); err != nil {
+		t.Fatalf("add history(%s) err: %s", operation, err.Error())
+	}
+	return history
+}
@@ -0,0 +1,539 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package conformance
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	api "github.com/polarismesh/polaris-server/common/api/v1"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/common/utils"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 新增实例，包括健康检查以及元数据，有效实例总数随之变化
+func testInstanceAddAndGet(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	count := getInstancesCount(t, s)
+
+	instance := newInstance(service, uniqueHost(), 8080)
+	instance.Proto.EnableHealthCheck = utils.NewBoolValue(true)
+	instance.Proto.HealthCheck = &api.HealthCheck{
+		Type:      api.HealthCheck_HEARTBEAT,
+		Heartbeat: &api.HeartbeatHealthCheck{Ttl: utils.NewUInt32Value(5)},
+	}
+	instance.Proto.Weight = utils.NewUInt32Value(50)
+	instance.Proto.LogicSet = utils.NewStringValue("set-1")
+	if err := s.AddInstance(instance); err != nil {
+		t.Fatalf("add instance err: %s", err.Error())
+	}
+
+	got, err := s.GetInstance(instance.ID())
+	if err != nil {
+		t.Fatalf("get instance err: %s", err.Error())
+	}
+	checkInstance(t, instance, got)
+	if !got.EnableHealthCheck() || got.HealthCheck().GetHeartbeat().GetTtl().GetValue() != 5 {
+		t.Fatalf("instance health check not match: %+v", got.HealthCheck())
+	}
+	if current := getInstancesCount(t, s); current != count+1 {
+		t.Fatalf("instances count should be %d, got %d", count+1, current)
+	}
+
+	if got, err := s.GetInstance(newID()); err != nil || got != nil {
+		t.Fatalf("get not existed instance should return nil, got %+v, err: %v", got, err)
+	}
+}
+
+// 服务不存在时不能新增实例
+func testInstanceAddWithoutService(t *testing.T, s store.Store) {
+	namespace := addNamespace(t, s)
+	service := newService(namespace.Name, uniqueName("svc"))
+	err := s.AddInstance(newInstance(service, uniqueHost(), 8080))
+	if store.Code(err) != store.NotFoundService {
+		t.Fatalf("add instance to not existed service should return not found service, got %v", err)
+	}
+}
+
+// 更新实例的属性以及元数据，元数据整体替换
+func testInstanceUpdate(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	instance := addInstance(t, s, service, uniqueHost(), 8080)
+
+	instance.Proto.Protocol = utils.NewStringValue("http")
+	instance.Proto.Version = utils.NewStringValue("2.0.0")
+	instance.Proto.Weight = utils.NewUInt32Value(200)
+	instance.Proto.Metadata = map[string]string{"env": "prod", "zone": "a"}
+	instance.Proto.Revision = utils.NewStringValue(newID())
+	if err := s.UpdateInstance(instance); err != nil {
+		t.Fatalf("update instance err: %s", err.Error())
+	}
+	got, err := s.GetInstance(instance.ID())
+	if err != nil {
+		t.Fatalf("get instance err: %s", err.Error())
+	}
+	checkInstance(t, instance, got)
+}
+
+// 删除实例之后查询不到，清理之后可以重新创建相同ID的实例
+func testInstanceDelete(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	instance := addInstance(t, s, service, uniqueHost(), 8080)
+	count := getInstancesCount(t, s)
+
+	if err := s.DeleteInstance(instance.ID()); err != nil {
+		t.Fatalf("delete instance err: %s", err.Error())
+	}
+	if got, err := s.GetInstance(instance.ID()); err != nil || got != nil {
+		t.Fatalf("deleted instance should not be returned, got %+v, err: %v", got, err)
+	}
+	if current := getInstancesCount(t, s); current != count-1 {
+		t.Fatalf("instances count should be %d, got %d", count-1, current)
+	}
+
+	if err := s.CleanInstance(instance.ID()); err != nil {
+		t.Fatalf("clean instance err: %s", err.Error())
+	}
+	if err := s.AddInstance(instance); err != nil {
+		t.Fatalf("add instance after clean err: %s", err.Error())
+	}
+	got, err := s.GetInstance(instance.ID())
+	if err != nil {
+		t.Fatalf("get instance err: %s", err.Error())
+	}
+	checkInstance(t, instance, got)
+}
+
+// 批量新增以及批量删除实例
+func testInstanceBatchAddAndDelete(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	host := uniqueHost()
+	instances := []*model.Instance{
+		newInstance(service, host, 8080),
+		newInstance(service, host, 8081),
+		newInstance(service, host, 8082),
+	}
+	if err := s.BatchAddInstances(instances); err != nil {
+		t.Fatalf("batch add instances err: %s", err.Error())
+	}
+	for _, instance := range instances {
+		got, err := s.GetInstance(instance.ID())
+		if err != nil {
+			t.Fatalf("get instance err: %s", err.Error())
+		}
+		checkInstance(t, instance, got)
+	}
+
+	if err := s.BatchDeleteInstances([]interface{}{instances[0].ID(), instances[1].ID()}); err != nil {
+		t.Fatalf("batch delete instances err: %s", err.Error())
+	}
+	for i, instance := range instances {
+		got, err := s.GetInstance(instance.ID())
+		if err != nil {
+			t.Fatalf("get instance err: %s", err.Error())
+		}
+		if (got == nil) != (i < 2) {
+			t.Fatalf("instance(%d) delete status not match, got %+v", i, got)
+		}
+	}
+}
+
+// 检查实例是否存在，只有有效的实例存在
+func testInstanceExisted(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	alive := addInstance(t, s, service, uniqueHost(), 8080)
+	deleted := addInstance(t, s, service, uniqueHost(), 8080)
+	if err := s.DeleteInstance(deleted.ID()); err != nil {
+		t.Fatalf("delete instance err: %s", err.Error())
+	}
+
+	unknown := newID()
+	ids := map[string]bool{alive.ID(): false, deleted.ID(): false, unknown: false}
+	out, err := s.CheckInstancesExisted(ids)
+	if err != nil {
+		t.Fatalf("check instances existed err: %s", err.Error())
+	}
+	if !out[alive.ID()] || out[deleted.ID()] || out[unknown] {
+		t.Fatalf("instances existed not match: %v", out)
+	}
+}
+
+// 批量查询实例的简要信息，包括所属服务的名字以及token
+func testInstanceBrief(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	alive := addInstance(t, s, service, uniqueHost(), 8080)
+	deleted := addInstance(t, s, service, uniqueHost(), 8081)
+	if err := s.DeleteInstance(deleted.ID()); err != nil {
+		t.Fatalf("delete instance err: %s", err.Error())
+	}
+
+	out, err := s.GetInstancesBrief(map[string]bool{alive.ID(): true, deleted.ID(): true})
+	if err != nil {
+		t.Fatalf("get instances brief err: %s", err.Error())
+	}
+	if len(out) != 1 {
+		t.Fatalf("only valid instance should be returned, got %d", len(out))
+	}
+	got := out[alive.ID()]
+	if got == nil || got.Host() != alive.Host() || got.Port() != alive.Port() ||
+		got.Service() != service.Name || got.Namespace() != service.Namespace ||
+		got.ServiceToken() != service.Token {
+		t.Fatalf("instance brief not match: %+v", got)
+	}
+}
+
+// 根据服务以及host查询实例
+func testInstanceMainByService(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	host := uniqueHost()
+	first := addInstance(t, s, service, host, 8080)
+	second := addInstance(t, s, service, host, 8081)
+	addInstance(t, s, service, uniqueHost(), 8080)
+	if err := s.DeleteInstance(second.ID()); err != nil {
+		t.Fatalf("delete instance err: %s", err.Error())
+	}
+
+	out, err := s.GetInstancesMainByService(service.ID, host)
+	if err != nil {
+		t.Fatalf("get instances main by service err: %s", err.Error())
+	}
+	if len(out) != 1 || out[0].ID() != first.ID() || out[0].Port() != first.Port() {
+		t.Fatalf("only valid instance of host should be returned, got %d", len(out))
+	}
+}
+
+// 分页查询实例，不包括已删除的实例
+func testInstanceExpandPaging(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	host := uniqueHost()
+	var instances []*model.Instance
+	for port := uint32(8080); port < 8083; port++ {
+		instances = append(instances, addInstance(t, s, service, host, port))
+	}
+
+	seen := make(map[string]bool)
+	for offset, size := range map[uint32]int{0: 2, 2: 1, 4: 0} {
+		total, out, err := s.GetExpandInstances(serviceFilter(service), nil, offset, 2)
+		if err != nil {
+			t.Fatalf("get expand instances err: %s", err.Error())
+		}
+		if total != 3 || len(out) != size {
+			t.Fatalf("instances page(offset %d) should be %d/3, got %d/%d", offset, size, len(out), total)
+		}
+		for _, entry := range out {
+			if seen[entry.ID()] {
+				t.Fatalf("instance(%s) is returned twice", entry.ID())
+			}
+			if entry.Service() != service.Name || entry.Namespace() != service.Namespace {
+				t.Fatalf("instance service should be %s, got %s", service.Name, entry.Service())
+			}
+			seen[entry.ID()] = true
+		}
+	}
+	if len(seen) != 3 {
+		t.Fatalf("instances should be returned by pages, got %v", seen)
+	}
+
+	if err := s.DeleteInstance(instances[0].ID()); err != nil {
+		t.Fatalf("delete instance err: %s", err.Error())
+	}
+	total, out, err := s.GetExpandInstances(serviceFilter(service), nil, 0, 10)
+	if err != nil {
+		t.Fatalf("get expand instances err: %s", err.Error())
+	}
+	if total != 2 || len(out) != 2 {
+		t.Fatalf("deleted instance should not be returned, total: %d, size: %d", total, len(out))
+	}
+}
+
+// 按照实例的属性以及健康、隔离状态过滤实例
+func testInstanceExpandFilter(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	host := uniqueHost()
+	first := newInstance(service, host, 8080)
+	first.Proto.Healthy = utils.NewBoolValue(true)
+	second := newInstance(service, host, 8081)
+	second.Proto.Protocol = utils.NewStringValue("http")
+	second.Proto.Version = utils.NewStringValue("2.0.0")
+	second.Proto.Healthy = utils.NewBoolValue(false)
+	second.Proto.Isolate = utils.NewBoolValue(true)
+	for _, instance := range []*model.Instance{first, second} {
+		if err := s.AddInstance(instance); err != nil {
+			t.Fatalf("add instance err: %s", err.Error())
+		}
+	}
+
+	cases := []struct {
+		filter map[string]string
+		expect []*model.Instance
+	}{
+		{map[string]string{"host": host}, []*model.Instance{first, second}},
+		{map[string]string{"host": host, "port": "8081"}, []*model.Instance{second}},
+		{map[string]string{"host": host, "protocol": "grpc"}, []*model.Instance{first}},
+		{map[string]string{"host": host, "version": "2.0.0"}, []*model.Instance{second}},
+		{map[string]string{"host": host, "health_status": "1"}, []*model.Instance{first}},
+		{map[string]string{"host": host, "health_status": "0"}, []*model.Instance{second}},
+		{map[string]string{"host": host, "isolate": "1"}, []*model.Instance{second}},
+		{map[string]string{"host": host, "port": "9090"}, nil},
+	}
+	for _, c := range cases {
+		total, out, err := s.GetExpandInstances(c.filter, nil, 0, 10)
+		if err != nil {
+			t.Fatalf("get expand instances by filter(%v) err: %s", c.filter, err.Error())
+		}
+		checkInstanceIDs(t, c.filter, c.expect, total, out)
+	}
+}
+
+// 按照逗号分隔的多个host过滤实例
+func testInstanceExpandMultiHost(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	first := addInstance(t, s, service, uniqueHost(), 8080)
+	second := addInstance(t, s, service, uniqueHost(), 8080)
+	addInstance(t, s, service, uniqueHost(), 8080)
+
+	filter := map[string]string{"host": first.Host() + "," + second.Host()}
+	total, out, err := s.GetExpandInstances(filter, nil, 0, 10)
+	if err != nil {
+		t.Fatalf("get expand instances by hosts err: %s", err.Error())
+	}
+	checkInstanceIDs(t, filter, []*model.Instance{first, second}, total, out)
+}
+
+// 按照元数据过滤实例，返回的实例带有完整的元数据
+func testInstanceExpandMetadata(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	host := uniqueHost()
+	tagged := newInstance(service, host, 8080)
+	tagged.Proto.Metadata = map[string]string{"env": "test", "group": uniqueName("group")}
+	if err := s.AddInstance(tagged); err != nil {
+		t.Fatalf("add instance err: %s", err.Error())
+	}
+	addInstance(t, s, service, host, 8081)
+
+	metas := map[string]string{"group": tagged.Metadata()["group"]}
+	total, out, err := s.GetExpandInstances(serviceFilter(service), metas, 0, 10)
+	if err != nil {
+		t.Fatalf("get expand instances by metadata err: %s", err.Error())
+	}
+	checkInstanceIDs(t, metas, []*model.Instance{tagged}, total, out)
+	if !reflect.DeepEqual(out[0].Metadata(), tagged.Metadata()) {
+		t.Fatalf("instance metadata should be %v, got %v", tagged.Metadata(), out[0].Metadata())
+	}
+}
+
+// limit为0时只返回总数
+func testInstanceExpandCountOnly(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	host := uniqueHost()
+	addInstance(t, s, service, host, 8080)
+	addInstance(t, s, service, host, 8081)
+
+	total, out, err := s.GetExpandInstances(serviceFilter(service), nil, 0, 0)
+	if err != nil {
+		t.Fatalf("get expand instances count err: %s", err.Error())
+	}
+	if total != 2 || len(out) != 0 {
+		t.Fatalf("only count should be returned, total: %d, size: %d", total, len(out))
+	}
+}
+
+// 查询不存在的服务下的实例，返回空
+func testInstanceExpandUnknownService(t *testing.T, s store.Store) {
+	namespace := addNamespace(t, s)
+	filter := map[string]string{"name": uniqueName("svc"), "namespace": namespace.Name}
+	total, out, err := s.GetExpandInstances(filter, nil, 0, 10)
+	if err != nil {
+		t.Fatalf("get expand instances of not existed service err: %s", err.Error())
+	}
+	if total != 0 || len(out) != 0 {
+		t.Fatalf("instances of not existed service should be empty, total: %d, size: %d", total, len(out))
+	}
+}
+
+// 增量拉取实例，精确到秒并且包含等于mtime的数据，可以按照服务过滤
+func testInstanceGetMore(t *testing.T, s store.Store) {
+	namespace, service := addNamespaceAndService(t, s)
+	other := addService(t, s, namespace.Name)
+	instance := addInstance(t, s, service, uniqueHost(), 8080)
+	otherInstance := addInstance(t, s, other, uniqueHost(), 8080)
+	got, err := s.GetInstance(instance.ID())
+	if err != nil || got == nil {
+		t.Fatalf("get instance err: %v", err)
+	}
+	mtime := truncate(got.ModifyTime)
+
+	for _, firstUpdate := range []bool{true, false} {
+		for _, needMeta := range []bool{true, false} {
+			out, err := s.GetMoreInstances(mtime, firstUpdate, needMeta, []string{service.ID})
+			if err != nil {
+				t.Fatalf("get more instances err: %s", err.Error())
+			}
+			entry, ok := out[instance.ID()]
+			if !ok || !entry.Valid || entry.Revision() != instance.Revision() {
+				t.Fatalf("instance modified at mtime should be returned(first %v, meta %v), got %+v",
+					firstUpdate, needMeta, entry)
+			}
+			if needMeta && !reflect.DeepEqual(entry.Metadata(), instance.Metadata()) {
+				t.Fatalf("instance metadata should be %v, got %v", instance.Metadata(), entry.Metadata())
+			}
+			if _, ok := out[otherInstance.ID()]; ok {
+				t.Fatalf("instance of other service should not be returned")
+			}
+		}
+	}
+
+	out, err := s.GetMoreInstances(mtime, false, true, nil)
+	if err != nil {
+		t.Fatalf("get more instances err: %s", err.Error())
+	}
+	if _, ok := out[otherInstance.ID()]; !ok {
+		t.Fatalf("instances of all services should be returned without service filter")
+	}
+	out, err = s.GetMoreInstances(time.Now().Add(time.Hour), false, true, nil)
+	if err != nil {
+		t.Fatalf("get more instances err: %s", err.Error())
+	}
+	if _, ok := out[instance.ID()]; ok {
+		t.Fatalf("instance modified before mtime should not be returned")
+	}
+}
+
+// 增量拉取实例，非首次拉取时返回已删除的实例，Valid=false
+func testInstanceGetMoreDeleted(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	instance := addInstance(t, s, service, uniqueHost(), 8080)
+	got, err := s.GetInstance(instance.ID())
+	if err != nil || got == nil {
+		t.Fatalf("get instance err: %v", err)
+	}
+	mtime := truncate(got.ModifyTime)
+	if err := s.DeleteInstance(instance.ID()); err != nil {
+		t.Fatalf("delete instance err: %s", err.Error())
+	}
+
+	out, err := s.GetMoreInstances(mtime, false, true, []string{service.ID})
+	if err != nil {
+		t.Fatalf("get more instances err: %s", err.Error())
+	}
+	if entry, ok := out[instance.ID()]; !ok || entry.Valid {
+		t.Fatalf("deleted instance should be returned with valid=false, got %+v", entry)
+	}
+	out, err = s.GetMoreInstances(mtime, true, true, []string{service.ID})
+	if err != nil {
+		t.Fatalf("get more instances err: %s", err.Error())
+	}
+	if _, ok := out[instance.ID()]; ok {
+		t.Fatalf("deleted instance should not be returned when first update")
+	}
+}
+
+// 修改实例的健康状态以及隔离状态，同时更新revision
+func testInstanceHealthAndIsolate(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	first := addInstance(t, s, service, uniqueHost(), 8080)
+	second := addInstance(t, s, service, uniqueHost(), 8080)
+
+	revision := newID()
+	if err := s.SetInstanceHealthStatus(first.ID(), 1, revision); err != nil {
+		t.Fatalf("set instance health status err: %s", err.Error())
+	}
+	got, err := s.GetInstance(first.ID())
+	if err != nil {
+		t.Fatalf("get instance err: %s", err.Error())
+	}
+	if got == nil || !got.Healthy() || got.Revision() != revision {
+		t.Fatalf("instance health status is not updated: %+v", got)
+	}
+	if err := s.SetInstanceHealthStatus(first.ID(), 0, newID()); err != nil {
+		t.Fatalf("set instance health status err: %s", err.Error())
+	}
+	if got, _ := s.GetInstance(first.ID()); got == nil || got.Healthy() {
+		t.Fatalf("instance should be unhealthy: %+v", got)
+	}
+
+	revision = newID()
+	if err := s.BatchSetInstanceIsolate([]interface{}{first.ID(), second.ID()}, 1, revision); err != nil {
+		t.Fatalf("batch set instance isolate err: %s", err.Error())
+	}
+	for _, instance := range []*model.Instance{first, second} {
+		got, err := s.GetInstance(instance.ID())
+		if err != nil {
+			t.Fatalf("get instance err: %s", err.Error())
+		}
+		if got == nil || !got.Isolate() || got.Revision() != revision {
+			t.Fatalf("instance isolate is not updated: %+v", got)
+		}
+	}
+}
+
+// 按照服务名以及命名空间过滤实例
+func serviceFilter(service *model.Service) map[string]string {
+	return map[string]string{"name": service.Name, "namespace": service.Namespace}
+}
+
+func getInstancesCount(t *testing.T, s store.Store) uint32 {
+	count, err := s.GetInstancesCount()
+	if err != nil {
+		t.Fatalf("get instances count err: %s", err.Error())
+	}
+	return count
+}
+
+// 校验存储返回的实例与写入的一致
+func checkInstance(t *testing.T, expect *model.Instance, got *model.Instance) {
+	if got == nil {
+		t.Fatalf("instance(%s) should be returned", expect.ID())
+	}
+	if got.ID() != expect.ID() || got.ServiceID != expect.ServiceID || got.Host() != expect.Host() ||
+		got.Port() != expect.Port() || got.Protocol() != expect.Protocol() || got.Version() != expect.Version() ||
+		got.Weight() != expect.Weight() || got.Healthy() != expect.Healthy() || got.Isolate() != expect.Isolate() ||
+		got.LogicSet() != expect.LogicSet() || got.Revision() != expect.Revision() || !got.Valid {
+		t.Fatalf("instance not match, expect %+v, got %+v", expect.Proto, got.Proto)
+	}
+	if got.Location().GetRegion().GetValue() != expect.Location().GetRegion().GetValue() ||
+		got.Location().GetZone().GetValue() != expect.Location().GetZone().GetValue() ||
+		got.Location().GetCampus().GetValue() != expect.Location().GetCampus().GetValue() {
+		t.Fatalf("instance location should be %+v, got %+v", expect.Location(), got.Location())
+	}
+	if !reflect.DeepEqual(got.Metadata(), expect.Metadata()) {
+		t.Fatalf("instance metadata should be %v, got %v", expect.Metadata(), got.Metadata())
+	}
+	if !isRecent(got.ModifyTime) {
+		t.Fatalf("instance mtime(%s) should be generated by store", got.ModifyTime)
+	}
+}
+
+// 校验查询返回的实例集合
+func checkInstanceIDs(t *testing.T, filter map[string]string, expect []*model.Instance, total uint32,
+	got []*model.Instance) {
+	if int(total) != len(expect) || len(got) != len(expect) {
+		t.Fatalf("instances by filter(%v) should be %d, got %d/%d", filter, len(expect), len(got), total)
+	}
+	ids := make(map[string]bool, len(got))
+	for _, entry := range got {
+		ids[entry.ID()] = true
+	}
+	for _, entry := range expect {
+		if !ids[entry.ID()] {
+			t.Fatalf("instance(%s:%d) should be returned by filter(%v)", entry.Host(), entry.Port(), filter)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package conformance
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 生成L5的sid，modID的低6位为layoutID，每次生成的sid都不相同
+func testL5Sid(t *testing.T, s store.Store) {
+	const layoutID = 3
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		sid, err := s.GenNextL5Sid(layoutID)
+		if err != nil {
+			t.Fatalf("gen next l5 sid err: %s", err.Error())
+		}
+		items := strings.Split(sid, ":")
+		if len(items) != 2 {
+			t.Fatalf("l5 sid(%s) should be modID:cmdID", sid)
+		}
+		modID, err := strconv.ParseUint(items[0], 10, 32)
+		if err != nil {
+			t.Fatalf("l5 sid(%s) modID is invalid: %s", sid, err.Error())
+		}
+		if _, err := strconv.ParseUint(items[1], 10, 32); err != nil {
+			t.Fatalf("l5 sid(%s) cmdID is invalid: %s", sid, err.Error())
+		}
+		if modID&63 != layoutID {
+			t.Fatalf("l5 sid(%s) should be generated with layout %d", sid, layoutID)
+		}
+		if seen[sid] {
+			t.Fatalf("l5 sid(%s) is generated twice", sid)
+		}
+		seen[sid] = true
+	}
+
+	if _, err := s.GetMoreL5Extend(time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("get more l5 extend err: %s", err.Error())
+	}
+	if _, err := s.GetMoreL5Routes(0); err != nil {
+		t.Fatalf("get more l5 routes err: %s", err.Error())
+	}
+	if _, err := s.GetMoreL5Policies(0); err != nil {
+		t.Fatalf("get more l5 policies err: %s", err.Error())
+	}
+	if _, err := s.GetMoreL5Sections(0); err != nil {
+		t.Fatalf("get more l5 sections err: %s", err.Error())
+	}
+	if _, err := s.GetMoreL5IPConfigs(0); err != nil {
+		t.Fatalf("get more l5 ip configs err: %s", err.Error())
+	}
+}
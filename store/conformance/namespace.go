@@ -0,0 +1,175 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package conformance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 新增命名空间，ctime和mtime由存储层生成，名字重复时报错
+func testNamespaceAddAndGet(t *testing.T, s store.Store) {
+	namespace := addNamespace(t, s)
+
+	got, err := s.GetNamespace(namespace.Name)
+	if err != nil {
+		t.Fatalf("get namespace err: %s", err.Error())
+	}
+	if got == nil || !got.Valid || got.Name != namespace.Name || got.Comment != namespace.Comment ||
+		got.Token != namespace.Token || got.Owner != namespace.Owner {
+		t.Fatalf("namespace not match, expect %+v, got %+v", namespace, got)
+	}
+	if !isRecent(got.CreateTime) || !isRecent(got.ModifyTime) {
+		t.Fatalf("namespace ctime(%s) and mtime(%s) should be generated by store", got.CreateTime, got.ModifyTime)
+	}
+
+	list, err := s.ListNamespaces(testOwner)
+	if err != nil {
+		t.Fatalf("list namespaces err: %s", err.Error())
+	}
+	if findNamespace(list, namespace.Name) == nil {
+		t.Fatalf("namespace(%s) should be listed by owner", namespace.Name)
+	}
+
+	if err := s.AddNamespace(&model.Namespace{Name: namespace.Name, Owner: testOwner, Token: newID()}); err == nil {
+		t.Fatalf("add duplicated namespace should return error")
+	}
+	if err := s.AddNamespace(&model.Namespace{Name: uniqueName("ns"), Owner: testOwner}); err == nil {
+		t.Fatalf("add namespace without token should return error")
+	}
+
+	got, err = s.GetNamespace(uniqueName("ns"))
+	if err != nil || got != nil {
+		t.Fatalf("get not existed namespace should return nil, got %+v, err: %v", got, err)
+	}
+}
+
+// 更新命名空间的负责人、描述以及token
+func testNamespaceUpdate(t *testing.T, s store.Store) {
+	namespace := addNamespace(t, s)
+	namespace.Owner = testOwner + "-new"
+	namespace.Comment = "updated comment"
+	if err := s.UpdateNamespace(namespace); err != nil {
+		t.Fatalf("update namespace err: %s", err.Error())
+	}
+	token := newID()
+	if err := s.UpdateNamespaceToken(namespace.Name, token); err != nil {
+		t.Fatalf("update namespace token err: %s", err.Error())
+	}
+
+	got, err := s.GetNamespace(namespace.Name)
+	if err != nil {
+		t.Fatalf("get namespace err: %s", err.Error())
+	}
+	if got.Owner != namespace.Owner || got.Comment != namespace.Comment || got.Token != token {
+		t.Fatalf("namespace is not updated: %+v", got)
+	}
+}
+
+// 按照名字过滤以及分页查询命名空间，只返回有效的数据
+func testNamespaceGetNamespaces(t *testing.T, s store.Store) {
+	names := make([]string, 0, 3)
+	for i := 0; i < 3; i++ {
+		names = append(names, addNamespace(t, s).Name)
+	}
+
+	seen := make(map[string]bool)
+	for offset, size := range map[int]int{0: 2, 2: 1, 4: 0} {
+		out, total, err := s.GetNamespaces(map[string][]string{"name": names}, offset, 2)
+		if err != nil {
+			t.Fatalf("get namespaces err: %s", err.Error())
+		}
+		if total != 3 || len(out) != size {
+			t.Fatalf("namespaces page(offset %d) should be %d/3, got %d/%d", offset, size, len(out), total)
+		}
+		for _, entry := range out {
+			if seen[entry.Name] {
+				t.Fatalf("namespace(%s) is returned twice", entry.Name)
+			}
+			seen[entry.Name] = true
+		}
+	}
+	if len(seen) != 3 {
+		t.Fatalf("namespaces should be returned by pages, got %v", seen)
+	}
+
+	deleteNamespace(t, s, names[0])
+	out, total, err := s.GetNamespaces(map[string][]string{"name": names}, 0, 10)
+	if err != nil {
+		t.Fatalf("get namespaces err: %s", err.Error())
+	}
+	if total != 2 || len(out) != 2 {
+		t.Fatalf("deleted namespace should not be returned, total: %d, size: %d", total, len(out))
+	}
+}
+
+// 增量拉取命名空间，精确到秒并且包含等于mtime的数据，删除的数据返回Valid=false
+func testNamespaceGetMore(t *testing.T, s store.Store) {
+	namespace := addNamespace(t, s)
+	got, err := s.GetNamespace(namespace.Name)
+	if err != nil {
+		t.Fatalf("get namespace err: %s", err.Error())
+	}
+
+	out, err := s.GetMoreNamespaces(truncate(got.ModifyTime))
+	if err != nil {
+		t.Fatalf("get more namespaces err: %s", err.Error())
+	}
+	if entry := findNamespace(out, namespace.Name); entry == nil || !entry.Valid {
+		t.Fatalf("namespace modified at mtime should be returned, got %+v", entry)
+	}
+	out, err = s.GetMoreNamespaces(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("get more namespaces err: %s", err.Error())
+	}
+	if entry := findNamespace(out, namespace.Name); entry != nil {
+		t.Fatalf("namespace modified before mtime should not be returned")
+	}
+
+	deleteNamespace(t, s, namespace.Name)
+	out, err = s.GetMoreNamespaces(truncate(got.ModifyTime))
+	if err != nil {
+		t.Fatalf("get more namespaces err: %s", err.Error())
+	}
+	if entry := findNamespace(out, namespace.Name); entry == nil || entry.Valid {
+		t.Fatalf("deleted namespace should be returned with valid=false, got %+v", entry)
+	}
+}
+
+// 通过事务删除命名空间
+func deleteNamespace(t *testing.T, s store.Store, name string) {
+	tx := createTransaction(t, s)
+	if err := tx.DeleteNamespace(name); err != nil {
+		t.Fatalf("delete namespace(%s) err: %s", name, err.Error())
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit transaction err: %s", err.Error())
+	}
+}
+
+func findNamespace(namespaces []*model.Namespace, name string) *model.Namespace {
+	for _, entry := range namespaces {
+		if entry.Name == name {
+			return entry
+		}
+	}
+	return nil
+}
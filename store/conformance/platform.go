@@ -0,0 +1,158 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package conformance
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 平台信息的新增、查询、更新以及删除
+func testPlatformLifecycle(t *testing.T, s store.Store) {
+	platform := addPlatform(t, s)
+	got, err := s.GetPlatformById(platform.ID)
+	if err != nil {
+		t.Fatalf("get platform err: %s", err.Error())
+	}
+	checkPlatform(t, platform, got)
+
+	platform.Name = uniqueName("platform")
+	platform.Domain = "updated.conformance.io"
+	platform.QPS = 200
+	platform.Token = newID()
+	platform.Comment = "updated comment"
+	if err := s.UpdatePlatform(platform); err != nil {
+		t.Fatalf("update platform err: %s", err.Error())
+	}
+	got, err = s.GetPlatformById(platform.ID)
+	if err != nil {
+		t.Fatalf("get platform err: %s", err.Error())
+	}
+	checkPlatform(t, platform, got)
+
+	if err := s.DeletePlatform(platform.ID); err != nil {
+		t.Fatalf("delete platform err: %s", err.Error())
+	}
+	if got, err := s.GetPlatformById(platform.ID); err != nil || got != nil {
+		t.Fatalf("deleted platform should not be returned, got %+v, err: %v", got, err)
+	}
+
+	// 删除之后可以重新创建
+	if err := s.CreatePlatform(platform); err != nil {
+		t.Fatalf("create platform after delete err: %s", err.Error())
+	}
+	got, err = s.GetPlatformById(platform.ID)
+	if err != nil {
+		t.Fatalf("get platform err: %s", err.Error())
+	}
+	checkPlatform(t, platform, got)
+}
+
+// 分页查询平台信息，支持按照名字、负责人以及部门过滤
+func testPlatformList(t *testing.T, s store.Store) {
+	department := uniqueName("department")
+	var platforms []*model.Platform
+	for i := 0; i < 3; i++ {
+		platform := newPlatform()
+		platform.Department = department
+		if err := s.CreatePlatform(platform); err != nil {
+			t.Fatalf("create platform err: %s", err.Error())
+		}
+		platforms = append(platforms, platform)
+	}
+
+	seen := make(map[string]bool)
+	for offset, size := range map[uint32]int{0: 2, 2: 1, 4: 0} {
+		total, out, err := s.GetPlatforms(map[string]string{"department": department}, offset, 2)
+		if err != nil {
+			t.Fatalf("get platforms err: %s", err.Error())
+		}
+		if total != 3 || len(out) != size {
+			t.Fatalf("platforms page(offset %d) should be %d/3, got %d/%d", offset, size, len(out), total)
+		}
+		for _, entry := range out {
+			seen[entry.ID] = true
+		}
+	}
+	if len(seen) != 3 {
+		t.Fatalf("platforms should be returned by pages, got %v", seen)
+	}
+
+	cases := []map[string]string{
+		{"id": platforms[0].ID},
+		{"name": platforms[0].Name},
+		{"department": department, "owner": platforms[0].Owner[len(testOwner):]},
+	}
+	for _, filter := range cases {
+		total, out, err := s.GetPlatforms(filter, 0, 10)
+		if err != nil {
+			t.Fatalf("get platforms by filter(%v) err: %s", filter, err.Error())
+		}
+		if total != 1 || len(out) != 1 || out[0].ID != platforms[0].ID {
+			t.Fatalf("platform should be filtered by %v, got %d", filter, total)
+		}
+	}
+
+	if err := s.DeletePlatform(platforms[0].ID); err != nil {
+		t.Fatalf("delete platform err: %s", err.Error())
+	}
+	total, _, err := s.GetPlatforms(map[string]string{"department": department}, 0, 10)
+	if err != nil {
+		t.Fatalf("get platforms err: %s", err.Error())
+	}
+	if total != 2 {
+		t.Fatalf("deleted platform should not be returned, got %d", total)
+	}
+}
+
+func newPlatform() *model.Platform {
+	return &model.Platform{
+		ID:         newID(),
+		Name:       uniqueName("platform"),
+		Domain:     "conformance.io",
+		QPS:        100,
+		Token:      newID(),
+		Owner:      testOwner + newID()[:8],
+		Department: "conformance-department",
+		Comment:    "conformance platform",
+	}
+}
+
+func addPlatform(t *testing.T, s store.Store) *model.Platform {
+	platform := newPlatform()
+	if err := s.CreatePlatform(platform); err != nil {
+		t.Fatalf("create platform(%s) err: %s", platform.Name, err.Error())
+	}
+	return platform
+}
+
+func checkPlatform(t *testing.T, expect *model.Platform, got *model.Platform) {
+	if got == nil {
+		t.Fatalf("platform(%s) should be returned", expect.ID)
+	}
+	if got.ID != expect.ID || got.Name != expect.Name || got.Domain != expect.Domain || got.QPS != expect.QPS ||
+		got.Token != expect.Token || got.Owner != expect.Owner || got.Department != expect.Department ||
+		got.Comment != expect.Comment || !got.Valid {
+		t.Fatalf("platform not match, expect %+v, got %+v", expect, got)
+	}
+	if !isRecent(got.CreateTime) || !isRecent(got.ModifyTime) {
+		t.Fatalf("platform ctime(%s) and mtime(%s) should be generated by store", got.CreateTime, got.ModifyTime)
+	}
+}
@@ -0,0 +1,218 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package conformance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 限流规则的新增、查询、更新以及删除
+func testRateLimitLifecycle(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	if err := s.CreateRateLimit(&model.RateLimit{ID: newID(), ServiceID: service.ID}); err == nil {
+		t.Fatalf("create rate limit without revision should return error")
+	}
+	limit := addRateLimit(t, s, service)
+
+	got, err := s.GetRateLimitWithID(limit.ID)
+	if err != nil {
+		t.Fatalf("get rate limit err: %s", err.Error())
+	}
+	checkRateLimit(t, limit, got)
+
+	limit.Labels = `{"method":"update"}`
+	limit.Priority = 2
+	limit.Rule = `{"amounts":[{"maxAmount":20}]}`
+	limit.Revision = newID()
+	if err := s.UpdateRateLimit(limit); err != nil {
+		t.Fatalf("update rate limit err: %s", err.Error())
+	}
+	got, err = s.GetRateLimitWithID(limit.ID)
+	if err != nil {
+		t.Fatalf("get rate limit err: %s", err.Error())
+	}
+	checkRateLimit(t, limit, got)
+
+	limit.Revision = newID()
+	if err := s.DeleteRateLimit(limit); err != nil {
+		t.Fatalf("delete rate limit err: %s", err.Error())
+	}
+	if got, err := s.GetRateLimitWithID(limit.ID); err != nil || got != nil {
+		t.Fatalf("deleted rate limit should not be returned, got %+v, err: %v", got, err)
+	}
+}
+
+// 分页查询限流规则，带有服务名以及命名空间，支持按照服务以及标签过滤
+func testRateLimitList(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	var limits []*model.RateLimit
+	for i := 0; i < 3; i++ {
+		limits = append(limits, addRateLimit(t, s, service))
+	}
+
+	seen := make(map[string]bool)
+	for offset, size := range map[uint32]int{0: 2, 2: 1} {
+		total, out, err := s.GetExtendRateLimits(rateLimitFilter(service), offset, 2)
+		if err != nil {
+			t.Fatalf("get rate limits err: %s", err.Error())
+		}
+		if total != 3 || len(out) != size {
+			t.Fatalf("rate limits page(offset %d) should be %d/3, got %d/%d", offset, size, len(out), total)
+		}
+		for _, entry := range out {
+			if entry.ServiceName != service.Name || entry.NamespaceName != service.Namespace ||
+				entry.RateLimit.ServiceID != service.ID {
+				t.Fatalf("rate limit service not match: %+v", entry)
+			}
+			seen[entry.RateLimit.ID] = true
+		}
+	}
+	if len(seen) != 3 {
+		t.Fatalf("rate limits should be returned by pages, got %v", seen)
+	}
+
+	limits[0].Labels = `{"method":"` + uniqueName("method") + `"}`
+	limits[0].Revision = newID()
+	if err := s.UpdateRateLimit(limits[0]); err != nil {
+		t.Fatalf("update rate limit err: %s", err.Error())
+	}
+	filter := rateLimitFilter(service)
+	filter["labels"] = limits[0].Labels[len(`{"method":"`) : len(limits[0].Labels)-2]
+	total, out, err := s.GetExtendRateLimits(filter, 0, 10)
+	if err != nil {
+		t.Fatalf("get rate limits err: %s", err.Error())
+	}
+	if total != 1 || len(out) != 1 || out[0].RateLimit.ID != limits[0].ID {
+		t.Fatalf("rate limit should be filtered by labels, got %d", total)
+	}
+
+	limits[1].Revision = newID()
+	if err := s.DeleteRateLimit(limits[1]); err != nil {
+		t.Fatalf("delete rate limit err: %s", err.Error())
+	}
+	total, _, err = s.GetExtendRateLimits(rateLimitFilter(service), 0, 10)
+	if err != nil {
+		t.Fatalf("get rate limits err: %s", err.Error())
+	}
+	if total != 2 {
+		t.Fatalf("deleted rate limit should not be returned, got %d", total)
+	}
+}
+
+// 增量拉取限流规则以及服务的最新版本号，非首次拉取时返回已删除的规则
+func testRateLimitForCache(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	limit := addRateLimit(t, s, service)
+	deleted := addRateLimit(t, s, service)
+	got, err := s.GetRateLimitWithID(limit.ID)
+	if err != nil || got == nil {
+		t.Fatalf("get rate limit err: %v", err)
+	}
+	mtime := truncate(got.ModifyTime).Add(-time.Second)
+	deleted.Revision = newID()
+	if err := s.DeleteRateLimit(deleted); err != nil {
+		t.Fatalf("delete rate limit err: %s", err.Error())
+	}
+
+	limits, revisions, err := s.GetRateLimitsForCache(mtime, false)
+	if err != nil {
+		t.Fatalf("get rate limits for cache err: %s", err.Error())
+	}
+	if entry := findRateLimit(limits, limit.ID); entry == nil || !entry.Valid || entry.Revision != limit.Revision {
+		t.Fatalf("rate limit should be returned for cache, got %+v", entry)
+	}
+	if entry := findRateLimit(limits, deleted.ID); entry == nil || entry.Valid {
+		t.Fatalf("deleted rate limit should be returned with valid=false, got %+v", entry)
+	}
+	found := false
+	for _, revision := range revisions {
+		if revision.ServiceID != service.ID {
+			continue
+		}
+		found = true
+		if revision.LastRevision != deleted.Revision {
+			t.Fatalf("last revision should be %s, got %s", deleted.Revision, revision.LastRevision)
+		}
+	}
+	if !found {
+		t.Fatalf("last revision of service(%s) should be returned", service.Name)
+	}
+
+	limits, _, err = s.GetRateLimitsForCache(mtime, true)
+	if err != nil {
+		t.Fatalf("get rate limits for cache err: %s", err.Error())
+	}
+	if findRateLimit(limits, limit.ID) == nil || findRateLimit(limits, deleted.ID) != nil {
+		t.Fatalf("only valid rate limit should be returned when first update")
+	}
+	limits, _, err = s.GetRateLimitsForCache(time.Now().Add(time.Hour), false)
+	if err != nil {
+		t.Fatalf("get rate limits for cache err: %s", err.Error())
+	}
+	if findRateLimit(limits, limit.ID) != nil {
+		t.Fatalf("rate limit modified before mtime should not be returned")
+	}
+}
+
+// 为服务新增一条限流规则
+func addRateLimit(t *testing.T, s store.Store, service *model.Service) *model.RateLimit {
+	limit := &model.RateLimit{
+		ID:        newID(),
+		ServiceID: service.ID,
+		ClusterID: "conformance-cluster",
+		Labels:    `{"method":"get"}`,
+		Priority:  1,
+		Rule:      `{"amounts":[{"maxAmount":10}]}`,
+		Revision:  newID(),
+	}
+	if err := s.CreateRateLimit(limit); err != nil {
+		t.Fatalf("create rate limit(%s) err: %s", service.Name, err.Error())
+	}
+	return limit
+}
+
+func rateLimitFilter(service *model.Service) map[string]string {
+	return map[string]string{"name": service.Name, "namespace": service.Namespace}
+}
+
+func checkRateLimit(t *testing.T, expect *model.RateLimit, got *model.RateLimit) {
+	if got == nil {
+		t.Fatalf("rate limit(%s) should be returned", expect.ID)
+	}
+	if got.ID != expect.ID || got.ServiceID != expect.ServiceID || got.ClusterID != expect.ClusterID ||
+		got.Labels != expect.Labels || got.Priority != expect.Priority || got.Rule != expect.Rule ||
+		got.Revision != expect.Revision || !got.Valid {
+		t.Fatalf("rate limit not match, expect %+v, got %+v", expect, got)
+	}
+	if !isRecent(got.CreateTime) || !isRecent(got.ModifyTime) {
+		t.Fatalf("rate limit ctime(%s) and mtime(%s) should be generated by store", got.CreateTime, got.ModifyTime)
+	}
+}
+
+func findRateLimit(limits []*model.RateLimit, id string) *model.RateLimit {
+	for _, entry := range limits {
+		if entry.ID == id {
+			return entry
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package conformance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 路由配置的新增、查询、更新以及删除，缺少参数时返回错误
+func testRoutingConfigLifecycle(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	if err := s.CreateRoutingConfig(&model.RoutingConfig{ID: service.ID, Revision: newID()}); err == nil {
+		t.Fatalf("create routing config without bounds should return error")
+	}
+	conf := addRoutingConfig(t, s, service)
+
+	got, err := s.GetRoutingConfigWithID(service.ID)
+	if err != nil {
+		t.Fatalf("get routing config with id err: %s", err.Error())
+	}
+	checkRoutingConfig(t, conf, got)
+	got, err = s.GetRoutingConfigWithService(service.Name, service.Namespace)
+	if err != nil {
+		t.Fatalf("get routing config with service err: %s", err.Error())
+	}
+	checkRoutingConfig(t, conf, got)
+
+	conf.InBounds = `[{"sources":[{"service":"*"}]}]`
+	conf.OutBounds = `[{"destinations":[{"service":"*"}]}]`
+	conf.Revision = newID()
+	if err := s.UpdateRoutingConfig(conf); err != nil {
+		t.Fatalf("update routing config err: %s", err.Error())
+	}
+	got, err = s.GetRoutingConfigWithID(service.ID)
+	if err != nil {
+		t.Fatalf("get routing config with id err: %s", err.Error())
+	}
+	checkRoutingConfig(t, conf, got)
+
+	if err := s.DeleteRoutingConfig(service.ID); err != nil {
+		t.Fatalf("delete routing config err: %s", err.Error())
+	}
+	if got, err := s.GetRoutingConfigWithID(service.ID); err != nil || got != nil {
+		t.Fatalf("deleted routing config should not be returned, got %+v, err: %v", got, err)
+	}
+	if got, err := s.GetRoutingConfigWithService(service.Name, service.Namespace); err != nil || got != nil {
+		t.Fatalf("deleted routing config should not be returned by service, got %+v, err: %v", got, err)
+	}
+
+	// 删除之后可以重新创建
+	conf = addRoutingConfig(t, s, service)
+	got, err = s.GetRoutingConfigWithID(service.ID)
+	if err != nil {
+		t.Fatalf("get routing config with id err: %s", err.Error())
+	}
+	checkRoutingConfig(t, conf, got)
+}
+
+// 分页查询路由配置，带有服务名以及命名空间
+func testRoutingConfigList(t *testing.T, s store.Store) {
+	namespace := addNamespace(t, s)
+	var services []*model.Service
+	for i := 0; i < 3; i++ {
+		service := addService(t, s, namespace.Name)
+		addRoutingConfig(t, s, service)
+		services = append(services, service)
+	}
+
+	seen := make(map[string]bool)
+	for offset, size := range map[uint32]int{0: 2, 2: 1} {
+		total, out, err := s.GetRoutingConfigs(map[string]string{"namespace": namespace.Name}, offset, 2)
+		if err != nil {
+			t.Fatalf("get routing configs err: %s", err.Error())
+		}
+		if total != 3 || len(out) != size {
+			t.Fatalf("routing configs page(offset %d) should be %d/3, got %d/%d", offset, size, len(out), total)
+		}
+		for _, entry := range out {
+			service := findService(services, entry.Config.ID)
+			if service == nil || entry.ServiceName != service.Name || entry.NamespaceName != namespace.Name {
+				t.Fatalf("routing config service not match: %+v", entry)
+			}
+			seen[entry.Config.ID] = true
+		}
+	}
+	if len(seen) != 3 {
+		t.Fatalf("routing configs should be returned by pages, got %v", seen)
+	}
+
+	filter := map[string]string{"name": services[0].Name, "namespace": namespace.Name}
+	total, out, err := s.GetRoutingConfigs(filter, 0, 10)
+	if err != nil {
+		t.Fatalf("get routing configs err: %s", err.Error())
+	}
+	if total != 1 || len(out) != 1 || out[0].Config.ID != services[0].ID {
+		t.Fatalf("routing config should be filtered by service name, got %d", total)
+	}
+
+	if err := s.DeleteRoutingConfig(services[0].ID); err != nil {
+		t.Fatalf("delete routing config err: %s", err.Error())
+	}
+	total, _, err = s.GetRoutingConfigs(map[string]string{"namespace": namespace.Name}, 0, 10)
+	if err != nil {
+		t.Fatalf("get routing configs err: %s", err.Error())
+	}
+	if total != 2 {
+		t.Fatalf("deleted routing config should not be returned, got %d", total)
+	}
+}
+
+// 增量拉取路由配置，返回mtime之后变更的数据
+func testRoutingConfigForCache(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	conf := addRoutingConfig(t, s, service)
+	got, err := s.GetRoutingConfigWithID(service.ID)
+	if err != nil || got == nil {
+		t.Fatalf("get routing config with id err: %v", err)
+	}
+	mtime := truncate(got.ModifyTime).Add(-time.Second)
+
+	for _, firstUpdate := range []bool{true, false} {
+		out, err := s.GetRoutingConfigsForCache(mtime, firstUpdate)
+		if err != nil {
+			t.Fatalf("get routing configs for cache err: %s", err.Error())
+		}
+		entry := findRoutingConfig(out, service.ID)
+		if entry == nil || !entry.Valid || entry.Revision != conf.Revision {
+			t.Fatalf("routing config should be returned for cache(first %v), got %+v", firstUpdate, entry)
+		}
+	}
+
+	out, err := s.GetRoutingConfigsForCache(time.Now().Add(time.Hour), false)
+	if err != nil {
+		t.Fatalf("get routing configs for cache err: %s", err.Error())
+	}
+	if findRoutingConfig(out, service.ID) != nil {
+		t.Fatalf("routing config modified before mtime should not be returned")
+	}
+}
+
+// 增量拉取路由配置，非首次拉取时返回已删除的配置，Valid=false
+func testRoutingConfigForCacheDeleted(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	addRoutingConfig(t, s, service)
+	got, err := s.GetRoutingConfigWithID(service.ID)
+	if err != nil || got == nil {
+		t.Fatalf("get routing config with id err: %v", err)
+	}
+	mtime := truncate(got.ModifyTime).Add(-time.Second)
+	if err := s.DeleteRoutingConfig(service.ID); err != nil {
+		t.Fatalf("delete routing config err: %s", err.Error())
+	}
+
+	out, err := s.GetRoutingConfigsForCache(mtime, false)
+	if err != nil {
+		t.Fatalf("get routing configs for cache err: %s", err.Error())
+	}
+	if entry := findRoutingConfig(out, service.ID); entry == nil || entry.Valid {
+		t.Fatalf("deleted routing config should be returned with valid=false, got %+v", entry)
+	}
+	out, err = s.GetRoutingConfigsForCache(mtime, true)
+	if err != nil {
+		t.Fatalf("get routing configs for cache err: %s", err.Error())
+	}
+	if findRoutingConfig(out, service.ID) != nil {
+		t.Fatalf("deleted routing config should not be returned when first update")
+	}
+}
+
+// 为服务新增路由配置
+func addRoutingConfig(t *testing.T, s store.Store, service *model.Service) *model.RoutingConfig {
+	conf := &model.RoutingConfig{
+		ID:        service.ID,
+		InBounds:  `[{"sources":[{"service":"` + service.Name + `"}]}]`,
+		OutBounds: `[{"destinations":[{"service":"` + service.Name + `"}]}]`,
+		Revision:  newID(),
+	}
+	if err := s.CreateRoutingConfig(conf); err != nil {
+		t.Fatalf("create routing config(%s) err: %s", service.Name, err.Error())
+	}
+	return conf
+}
+
+func checkRoutingConfig(t *testing.T, expect *model.RoutingConfig, got *model.RoutingConfig) {
+	if got == nil {
+		t.Fatalf("routing config(%s) should be returned", expect.ID)
+	}
+	if got.ID != expect.ID || got.InBounds != expect.InBounds || got.OutBounds != expect.OutBounds ||
+		got.Revision != expect.Revision || !got.Valid {
+		t.Fatalf("routing config not match, expect %+v, got %+v", expect, got)
+	}
+	if !isRecent(got.CreateTime) || !isRecent(got.ModifyTime) {
+		t.Fatalf("routing config ctime(%s) and mtime(%s) should be generated by store", got.CreateTime, got.ModifyTime)
+	}
+}
+
+func findRoutingConfig(confs []*model.RoutingConfig, id string) *model.RoutingConfig {
+	for _, entry := range confs {
+		if entry.ID == id {
+			return entry
+		}
+	}
+	return nil
+}
@@ -0,0 +1,555 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package conformance
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 系统服务所在的命名空间
+const systemNamespace = "Polaris"
+
+// 新增服务，重复的服务返回错误，有效服务总数随之变化
+func testServiceAddAndGet(t *testing.T, s store.Store) {
+	namespace := addNamespace(t, s)
+	count := getServicesCount(t, s)
+	service := addService(t, s, namespace.Name)
+
+	got, err := s.GetService(service.Name, service.Namespace)
+	if err != nil {
+		t.Fatalf("get service err: %s", err.Error())
+	}
+	checkService(t, service, got)
+	got, err = s.GetServiceByID(service.ID)
+	if err != nil {
+		t.Fatalf("get service by id err: %s", err.Error())
+	}
+	checkService(t, service, got)
+	if current := getServicesCount(t, s); current != count+1 {
+		t.Fatalf("services count should be %d, got %d", count+1, current)
+	}
+
+	source, err := s.GetSourceServiceToken(service.Name, service.Namespace)
+	if err != nil {
+		t.Fatalf("get source service token err: %s", err.Error())
+	}
+	if source == nil || source.ID != service.ID || source.Token != service.Token {
+		t.Fatalf("source service token not match: %+v", source)
+	}
+
+	if err := s.AddService(newService(namespace.Name, service.Name)); err == nil {
+		t.Fatalf("add duplicated service should return error")
+	}
+
+	got, err = s.GetService(uniqueName("svc"), namespace.Name)
+	if err != nil || got != nil {
+		t.Fatalf("get not existed service should return nil, got %+v, err: %v", got, err)
+	}
+	got, err = s.GetServiceByID(newID())
+	if err != nil || got != nil {
+		t.Fatalf("get not existed service by id should return nil, got %+v, err: %v", got, err)
+	}
+}
+
+// 命名空间不存在时不能新增服务
+func testServiceAddWithoutNamespace(t *testing.T, s store.Store) {
+	err := s.AddService(newService(uniqueName("ns"), uniqueName("svc")))
+	if store.Code(err) != store.NotFoundNamespace {
+		t.Fatalf("add service to not existed namespace should return not found namespace, got %v", err)
+	}
+}
+
+// 更新服务的属性、元数据以及token，元数据整体替换
+func testServiceUpdate(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+
+	service.Business = "conformance-business-new"
+	service.Comment = "updated comment"
+	service.Department = "conformance-department-new"
+	service.Owner = testOwner + "-new"
+	service.Meta = map[string]string{"env": "prod", "version": "v2"}
+	service.Revision = newID()
+	if err := s.UpdateService(service, true); err != nil {
+		t.Fatalf("update service err: %s", err.Error())
+	}
+	got, err := s.GetService(service.Name, service.Namespace)
+	if err != nil {
+		t.Fatalf("get service err: %s", err.Error())
+	}
+	checkService(t, service, got)
+
+	service.Token = newID()
+	service.Revision = newID()
+	if err := s.UpdateServiceToken(service.ID, service.Token, service.Revision); err != nil {
+		t.Fatalf("update service token err: %s", err.Error())
+	}
+	got, err = s.GetServiceByID(service.ID)
+	if err != nil {
+		t.Fatalf("get service by id err: %s", err.Error())
+	}
+	checkService(t, service, got)
+}
+
+// 删除服务之后查询不到，并且可以重新创建同名的服务
+func testServiceDelete(t *testing.T, s store.Store) {
+	namespace, service := addNamespaceAndService(t, s)
+	count := getServicesCount(t, s)
+
+	if err := s.DeleteService(service.ID, service.Name, service.Namespace); err != nil {
+		t.Fatalf("delete service err: %s", err.Error())
+	}
+	if got, err := s.GetService(service.Name, service.Namespace); err != nil || got != nil {
+		t.Fatalf("deleted service should not be returned, got %+v, err: %v", got, err)
+	}
+	if got, err := s.GetServiceByID(service.ID); err != nil || got != nil {
+		t.Fatalf("deleted service should not be returned by id, got %+v, err: %v", got, err)
+	}
+	if got, err := s.GetSourceServiceToken(service.Name, service.Namespace); err != nil || got != nil {
+		t.Fatalf("deleted service should not return token, got %+v, err: %v", got, err)
+	}
+	if current := getServicesCount(t, s); current != count-1 {
+		t.Fatalf("services count should be %d, got %d", count-1, current)
+	}
+
+	recreated := newService(namespace.Name, service.Name)
+	if err := s.AddService(recreated); err != nil {
+		t.Fatalf("add service after delete err: %s", err.Error())
+	}
+	got, err := s.GetService(service.Name, service.Namespace)
+	if err != nil {
+		t.Fatalf("get service err: %s", err.Error())
+	}
+	checkService(t, recreated, got)
+}
+
+// 分页查询服务，不包括别名以及已删除的服务
+func testServiceGetServicesPaging(t *testing.T, s store.Store) {
+	namespace := addNamespace(t, s)
+	var services []*model.Service
+	for i := 0; i < 3; i++ {
+		services = append(services, addService(t, s, namespace.Name))
+	}
+	addAlias(t, s, services[0])
+
+	seen := make(map[string]bool)
+	for offset, size := range map[uint32]int{0: 2, 2: 1, 4: 0} {
+		total, out, err := s.GetServices(map[string]string{"namespace": namespace.Name}, nil, nil, offset, 2)
+		if err != nil {
+			t.Fatalf("get services err: %s", err.Error())
+		}
+		if total != 3 || len(out) != size {
+			t.Fatalf("services page(offset %d) should be %d/3, got %d/%d", offset, size, len(out), total)
+		}
+		for _, entry := range out {
+			if seen[entry.ID] {
+				t.Fatalf("service(%s) is returned twice", entry.Name)
+			}
+			seen[entry.ID] = true
+		}
+	}
+	if len(seen) != 3 {
+		t.Fatalf("services should be returned by pages, got %v", seen)
+	}
+
+	if err := s.DeleteService(services[0].ID, services[0].Name, services[0].Namespace); err != nil {
+		t.Fatalf("delete service err: %s", err.Error())
+	}
+	total, out, err := s.GetServices(map[string]string{"namespace": namespace.Name}, nil, nil, 0, 10)
+	if err != nil {
+		t.Fatalf("get services err: %s", err.Error())
+	}
+	if total != 2 || len(out) != 2 {
+		t.Fatalf("deleted service should not be returned, total: %d, size: %d", total, len(out))
+	}
+}
+
+// 按照服务名、通配服务名、业务以及元数据过滤服务，返回的服务带有元数据
+func testServiceGetServicesFilter(t *testing.T, s store.Store) {
+	namespace := addNamespace(t, s)
+	prefix := uniqueName("svc")
+	first := newService(namespace.Name, prefix+"-first")
+	first.Business = "business-first"
+	first.Meta = map[string]string{"group": uniqueName("group")}
+	second := newService(namespace.Name, prefix+"-second")
+	second.Business = "business-second"
+	other := newService(namespace.Name, uniqueName("svc"))
+	for _, service := range []*model.Service{first, second, other} {
+		if err := s.AddService(service); err != nil {
+			t.Fatalf("add service err: %s", err.Error())
+		}
+	}
+
+	cases := []struct {
+		filter map[string]string
+		metas  map[string]string
+		expect []*model.Service
+	}{
+		{map[string]string{"namespace": namespace.Name, "name": first.Name}, nil, []*model.Service{first}},
+		{map[string]string{"namespace": namespace.Name, "name": prefix + "*"}, nil, []*model.Service{first, second}},
+		{map[string]string{"namespace": namespace.Name, "business": "second"}, nil, []*model.Service{second}},
+		{map[string]string{"namespace": namespace.Name}, first.Meta, []*model.Service{first}},
+		{map[string]string{"namespace": namespace.Name, "name": uniqueName("svc")}, nil, nil},
+	}
+	for _, c := range cases {
+		total, out, err := s.GetServices(c.filter, c.metas, nil, 0, 10)
+		if err != nil {
+			t.Fatalf("get services by filter(%v) err: %s", c.filter, err.Error())
+		}
+		if int(total) != len(c.expect) || len(out) != len(c.expect) {
+			t.Fatalf("services by filter(%v, %v) should be %d, got %d/%d",
+				c.filter, c.metas, len(c.expect), len(out), total)
+		}
+		for _, expect := range c.expect {
+			got := findService(out, expect.ID)
+			if got == nil {
+				t.Fatalf("service(%s) should be returned by filter(%v, %v)", expect.Name, c.filter, c.metas)
+			}
+			if !reflect.DeepEqual(got.Meta, expect.Meta) {
+				t.Fatalf("service(%s) metadata should be %v, got %v", expect.Name, expect.Meta, got.Meta)
+			}
+		}
+	}
+}
+
+// 按照实例的host以及port过滤服务，已删除的实例不参与过滤
+func testServiceGetServicesByInstance(t *testing.T, s store.Store) {
+	namespace := addNamespace(t, s)
+	first := addService(t, s, namespace.Name)
+	second := addService(t, s, namespace.Name)
+	host := uniqueHost()
+	instance := addInstance(t, s, first, host, 8080)
+	addInstance(t, s, second, host, 9090)
+
+	cases := []struct {
+		args   *store.InstanceArgs
+		expect []*model.Service
+	}{
+		{&store.InstanceArgs{Hosts: []string{host}}, []*model.Service{first, second}},
+		{&store.InstanceArgs{Hosts: []string{host}, Ports: []uint32{8080}}, []*model.Service{first}},
+		{&store.InstanceArgs{Hosts: []string{uniqueHost()}}, nil},
+	}
+	for _, c := range cases {
+		total, out, err := s.GetServices(map[string]string{"namespace": namespace.Name}, nil, c.args, 0, 10)
+		if err != nil {
+			t.Fatalf("get services by instance(%+v) err: %s", c.args, err.Error())
+		}
+		if int(total) != len(c.expect) || len(out) != len(c.expect) {
+			t.Fatalf("services by instance(%+v) should be %d, got %d/%d", c.args, len(c.expect), len(out), total)
+		}
+		for _, expect := range c.expect {
+			if findService(out, expect.ID) == nil {
+				t.Fatalf("service(%s) should be returned by instance(%+v)", expect.Name, c.args)
+			}
+		}
+	}
+
+	if err := s.DeleteInstance(instance.ID()); err != nil {
+		t.Fatalf("delete instance err: %s", err.Error())
+	}
+	args := &store.InstanceArgs{Hosts: []string{host}, Ports: []uint32{8080}}
+	total, _, err := s.GetServices(map[string]string{"namespace": namespace.Name}, nil, args, 0, 10)
+	if err != nil {
+		t.Fatalf("get services by instance err: %s", err.Error())
+	}
+	if total != 0 {
+		t.Fatalf("deleted instance should not match any service, got %d", total)
+	}
+}
+
+// 增量拉取服务，精确到秒并且包含等于mtime的数据，disableBusiness时只返回系统服务
+func testServiceGetMore(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	got, err := s.GetService(service.Name, service.Namespace)
+	if err != nil {
+		t.Fatalf("get service err: %s", err.Error())
+	}
+	mtime := truncate(got.ModifyTime)
+
+	for _, firstUpdate := range []bool{true, false} {
+		out, err := s.GetMoreServices(mtime, firstUpdate, false, true)
+		if err != nil {
+			t.Fatalf("get more services err: %s", err.Error())
+		}
+		entry, ok := out[service.ID]
+		if !ok || !entry.Valid || entry.Revision != service.Revision {
+			t.Fatalf("service modified at mtime should be returned(first %v), got %+v", firstUpdate, entry)
+		}
+		if !reflect.DeepEqual(entry.Meta, service.Meta) {
+			t.Fatalf("service metadata should be %v, got %v", service.Meta, entry.Meta)
+		}
+		out, err = s.GetMoreServices(mtime, firstUpdate, false, false)
+		if err != nil {
+			t.Fatalf("get more services without meta err: %s", err.Error())
+		}
+		if _, ok := out[service.ID]; !ok {
+			t.Fatalf("service should be returned without meta(first %v)", firstUpdate)
+		}
+	}
+
+	out, err := s.GetMoreServices(time.Now().Add(time.Hour), false, false, true)
+	if err != nil {
+		t.Fatalf("get more services err: %s", err.Error())
+	}
+	if _, ok := out[service.ID]; ok {
+		t.Fatalf("service modified before mtime should not be returned")
+	}
+	out, err = s.GetMoreServices(mtime, false, true, true)
+	if err != nil {
+		t.Fatalf("get more system services err: %s", err.Error())
+	}
+	for _, entry := range out {
+		if entry.Namespace != systemNamespace {
+			t.Fatalf("service(%s, %s) is not system service", entry.Name, entry.Namespace)
+		}
+	}
+}
+
+// 增量拉取服务，非首次拉取时返回已删除的服务，Valid=false
+func testServiceGetMoreDeleted(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	got, err := s.GetService(service.Name, service.Namespace)
+	if err != nil {
+		t.Fatalf("get service err: %s", err.Error())
+	}
+	mtime := truncate(got.ModifyTime)
+	if err := s.DeleteService(service.ID, service.Name, service.Namespace); err != nil {
+		t.Fatalf("delete service err: %s", err.Error())
+	}
+
+	out, err := s.GetMoreServices(mtime, false, false, true)
+	if err != nil {
+		t.Fatalf("get more services err: %s", err.Error())
+	}
+	if entry, ok := out[service.ID]; !ok || entry.Valid {
+		t.Fatalf("deleted service should be returned with valid=false, got %+v", entry)
+	}
+	out, err = s.GetMoreServices(mtime, true, false, true)
+	if err != nil {
+		t.Fatalf("get more services err: %s", err.Error())
+	}
+	if _, ok := out[service.ID]; ok {
+		t.Fatalf("deleted service should not be returned when first update")
+	}
+}
+
+// 服务别名的新增、更新以及删除，源服务删除之后不能再更新别名
+func testServiceAlias(t *testing.T, s store.Store) {
+	_, source := addNamespaceAndService(t, s)
+	alias := addAlias(t, s, source)
+
+	got, err := s.GetService(alias.Name, alias.Namespace)
+	if err != nil {
+		t.Fatalf("get alias err: %s", err.Error())
+	}
+	if got == nil || got.Reference != source.ID {
+		t.Fatalf("alias should reference service(%s), got %+v", source.ID, got)
+	}
+	if token, err := s.GetSourceServiceToken(alias.Name, alias.Namespace); err != nil || token != nil {
+		t.Fatalf("alias should not be source service, got %+v, err: %v", token, err)
+	}
+
+	alias.Comment = "updated alias"
+	alias.Owner = testOwner + "-new"
+	alias.Revision = newID()
+	if err := s.UpdateServiceAlias(alias, true); err != nil {
+		t.Fatalf("update alias err: %s", err.Error())
+	}
+	got, err = s.GetServiceByID(alias.ID)
+	if err != nil {
+		t.Fatalf("get alias by id err: %s", err.Error())
+	}
+	if got == nil || got.Comment != alias.Comment || got.Owner != alias.Owner || got.Revision != alias.Revision {
+		t.Fatalf("alias is not updated: %+v", got)
+	}
+
+	if err := s.DeleteServiceAlias(alias.Name, alias.Namespace); err != nil {
+		t.Fatalf("delete alias err: %s", err.Error())
+	}
+	if got, err := s.GetService(alias.Name, alias.Namespace); err != nil || got != nil {
+		t.Fatalf("deleted alias should not be returned, got %+v, err: %v", got, err)
+	}
+
+	another := addAlias(t, s, source)
+	if err := s.DeleteService(source.ID, source.Name, source.Namespace); err != nil {
+		t.Fatalf("delete service err: %s", err.Error())
+	}
+	another.Revision = newID()
+	err = s.UpdateServiceAlias(another, false)
+	if store.Code(err) != store.NotFoundService {
+		t.Fatalf("update alias of deleted service should return not found service, got %v", err)
+	}
+}
+
+// 分页查询服务别名，支持按照源服务以及别名过滤
+func testServiceGetServiceAliases(t *testing.T, s store.Store) {
+	namespace, source := addNamespaceAndService(t, s)
+	var aliases []*model.Service
+	for i := 0; i < 3; i++ {
+		aliases = append(aliases, addAlias(t, s, source))
+	}
+
+	filter := map[string]string{"service": source.Name, "namespace": namespace.Name}
+	seen := make(map[string]bool)
+	for offset, size := range map[uint32]int{0: 2, 2: 1} {
+		total, out, err := s.GetServiceAliases(filter, offset, 2)
+		if err != nil {
+			t.Fatalf("get service aliases err: %s", err.Error())
+		}
+		if total != 3 || len(out) != size {
+			t.Fatalf("aliases page(offset %d) should be %d/3, got %d/%d", offset, size, len(out), total)
+		}
+		for _, entry := range out {
+			if entry.ServiceID != source.ID || entry.Service != source.Name || entry.Namespace != namespace.Name {
+				t.Fatalf("alias source not match: %+v", entry)
+			}
+			seen[entry.ID] = true
+		}
+	}
+	if len(seen) != 3 {
+		t.Fatalf("aliases should be returned by pages, got %v", seen)
+	}
+
+	total, out, err := s.GetServiceAliases(map[string]string{"alias": aliases[0].Name}, 0, 10)
+	if err != nil {
+		t.Fatalf("get service aliases err: %s", err.Error())
+	}
+	if total != 1 || len(out) != 1 || out[0].Alias != aliases[0].Name {
+		t.Fatalf("alias should be filtered by name, got %d", total)
+	}
+
+	if err := s.DeleteServiceAlias(aliases[0].Name, aliases[0].Namespace); err != nil {
+		t.Fatalf("delete alias err: %s", err.Error())
+	}
+	total, _, err = s.GetServiceAliases(filter, 0, 10)
+	if err != nil {
+		t.Fatalf("get service aliases err: %s", err.Error())
+	}
+	if total != 2 {
+		t.Fatalf("deleted alias should not be returned, got %d", total)
+	}
+}
+
+// 系统服务为系统命名空间下的有效服务
+func testServiceGetSystemServices(t *testing.T, s store.Store) {
+	service := addService(t, s, systemNamespace)
+	defer func() {
+		_ = s.DeleteService(service.ID, service.Name, service.Namespace)
+	}()
+	_, other := addNamespaceAndService(t, s)
+
+	out, err := s.GetSystemServices()
+	if err != nil {
+		t.Fatalf("get system services err: %s", err.Error())
+	}
+	if findService(out, service.ID) == nil {
+		t.Fatalf("service(%s) should be system service", service.Name)
+	}
+	if findService(out, other.ID) != nil {
+		t.Fatalf("service(%s) should not be system service", other.Name)
+	}
+}
+
+// 根据服务名以及命名空间批量查询服务
+func testServiceGetServicesBatch(t *testing.T, s store.Store) {
+	namespace := addNamespace(t, s)
+	first := addService(t, s, namespace.Name)
+	second := addService(t, s, namespace.Name)
+
+	out, err := s.GetServicesBatch([]*model.Service{
+		{Name: first.Name, Namespace: namespace.Name},
+		{Name: second.Name, Namespace: namespace.Name},
+		{Name: first.Name, Namespace: uniqueName("ns")},
+		{Name: uniqueName("svc"), Namespace: namespace.Name},
+	})
+	if err != nil {
+		t.Fatalf("get services batch err: %s", err.Error())
+	}
+	if len(out) != 2 {
+		t.Fatalf("services batch should return 2 services, got %d", len(out))
+	}
+	for _, expect := range []*model.Service{first, second} {
+		got := findService(out, expect.ID)
+		if got == nil || got.Name != expect.Name || got.Namespace != expect.Namespace || got.Owner != expect.Owner {
+			t.Fatalf("service(%s) not match in batch, got %+v", expect.Name, got)
+		}
+	}
+
+	out, err = s.GetServicesBatch(nil)
+	if err != nil || len(out) != 0 {
+		t.Fatalf("empty services batch should return nothing, got %d, err: %v", len(out), err)
+	}
+}
+
+// 为源服务新增一个别名
+func addAlias(t *testing.T, s store.Store, source *model.Service) *model.Service {
+	alias := &model.Service{
+		ID:        newID(),
+		Name:      uniqueName("alias"),
+		Namespace: source.Namespace,
+		Reference: source.ID,
+		Comment:   "conformance alias",
+		Token:     newID(),
+		Owner:     testOwner,
+		Revision:  newID(),
+	}
+	if err := s.AddService(alias); err != nil {
+		t.Fatalf("add alias(%s) err: %s", alias.Name, err.Error())
+	}
+	return alias
+}
+
+func getServicesCount(t *testing.T, s store.Store) uint32 {
+	count, err := s.GetServicesCount()
+	if err != nil {
+		t.Fatalf("get services count err: %s", err.Error())
+	}
+	return count
+}
+
+// 校验存储返回的服务与写入的一致
+func checkService(t *testing.T, expect *model.Service, got *model.Service) {
+	if got == nil {
+		t.Fatalf("service(%s, %s) should be returned", expect.Name, expect.Namespace)
+	}
+	if got.ID != expect.ID || got.Name != expect.Name || got.Namespace != expect.Namespace ||
+		got.Business != expect.Business || got.Ports != expect.Ports || got.Comment != expect.Comment ||
+		got.Department != expect.Department || got.Token != expect.Token || got.Owner != expect.Owner ||
+		got.Revision != expect.Revision || !got.Valid {
+		t.Fatalf("service not match, expect %+v, got %+v", expect, got)
+	}
+	if !reflect.DeepEqual(got.Meta, expect.Meta) {
+		t.Fatalf("service metadata should be %v, got %v", expect.Meta, got.Meta)
+	}
+	if !isRecent(got.CreateTime) || !isRecent(got.ModifyTime) {
+		t.Fatalf("service ctime(%s) and mtime(%s) should be generated by store", got.CreateTime, got.ModifyTime)
+	}
+}
+
+func findService(services []*model.Service, id string) *model.Service {
+	for _, entry := range services {
+		if entry.ID == id {
+			return entry
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+/**
+ * Package conformance 存储插件的一致性测试集
+ * 通过store.Store接口，用相同的场景驱动各个存储插件，校验它们的行为与数据库实现保持一致
+ * 各个存储插件在自己的测试中调用Suite.Run，对已知的差异通过Skip说明原因
+ */
+package conformance
+
+import (
+	"runtime/debug"
+	"sort"
+	"testing"
+
+	"github.com/polarismesh/polaris-server/store"
+)
+
+/**
+ * Factory 创建一个已经初始化的存储实例
+ * 每个场景都会调用一次，场景结束之后由测试集负责Destroy
+ */
+type Factory func(t *testing.T) store.Store
+
+/**
+ * Suite 一致性测试集
+ */
+type Suite struct {
+	// 创建存储实例
+	Factory Factory
+	// 跳过的场景，key为场景名，value为跳过的原因
+	Skip map[string]string
+}
+
+// 一个测试场景，场景名的格式为：资源/行为
+type scenario struct {
+	name string
+	run  func(t *testing.T, s store.Store)
+}
+
+// 全部的测试场景，按照资源的依赖顺序排列
+var scenarios = []scenario{
+	{"Namespace/AddAndGet", testNamespaceAddAndGet},
+	{"Namespace/Update", testNamespaceUpdate},
+	{"Namespace/GetNamespaces", testNamespaceGetNamespaces},
+	{"Namespace/GetMore", testNamespaceGetMore},
+	{"Business/Lifecycle", testBusinessLifecycle},
+	{"Service/AddAndGet", testServiceAddAndGet},
+	{"Service/AddWithoutNamespace", testServiceAddWithoutNamespace},
+	{"Service/Update", testServiceUpdate},
+	{"Service/Delete", testServiceDelete},
+	{"Service/GetServicesPaging", testServiceGetServicesPaging},
+	{"Service/GetServicesFilter", testServiceGetServicesFilter},
+	{"Service/GetServicesByInstance", testServiceGetServicesByInstance},
+	{"Service/GetMore", testServiceGetMore},
+	{"Service/GetMoreDeleted", testServiceGetMoreDeleted},
+	{"Service/Alias", testServiceAlias},
+	{"Service/GetServiceAliases", testServiceGetServiceAliases},
+	{"Service/GetSystemServices", testServiceGetSystemServices},
+	{"Service/GetServicesBatch", testServiceGetServicesBatch},
+	{"Instance/AddAndGet", testInstanceAddAndGet},
+	{"Instance/AddWithoutService", testInstanceAddWithoutService},
+	{"Instance/Update", testInstanceUpdate},
+	{"Instance/Delete", testInstanceDelete},
+	{"Instance/BatchAddAndDelete", testInstanceBatchAddAndDelete},
+	{"Instance/Existed", testInstanceExisted},
+	{"Instance/Brief", testInstanceBrief},
+	{"Instance/MainByService", testInstanceMainByService},
+	{"Instance/ExpandPaging", testInstanceExpandPaging},
+	{"Instance/ExpandFilter", testInstanceExpandFilter},
+	{"Instance/ExpandMultiHost", testInstanceExpandMultiHost},
+	{"Instance/ExpandMetadata", testInstanceExpandMetadata},
+	{"Instance/ExpandCountOnly", testInstanceExpandCountOnly},
+	{"Instance/ExpandUnknownService", testInstanceExpandUnknownService},
+	{"Instance/GetMore", testInstanceGetMore},
+	{"Instance/GetMoreDeleted", testInstanceGetMoreDeleted},
+	{"Instance/HealthAndIsolate", testInstanceHealthAndIsolate},
+	{"RoutingConfig/Lifecycle", testRoutingConfigLifecycle},
+	{"RoutingConfig/GetRoutingConfigs", testRoutingConfigList},
+	{"RoutingConfig/ForCache", testRoutingConfigForCache},
+	{"RoutingConfig/ForCacheDeleted", testRoutingConfigForCacheDeleted},
+	{"RateLimit/Lifecycle", testRateLimitLifecycle},
+	{"RateLimit/GetExtendRateLimits", testRateLimitList},
+	{"RateLimit/ForCache", testRateLimitForCache},
+	{"CircuitBreaker/Lifecycle", testCircuitBreakerLifecycle},
+	{"CircuitBreaker/Release", testCircuitBreakerRelease},
+	{"CircuitBreaker/ForCache", testCircuitBreakerForCache},
+	{"Platform/Lifecycle", testPlatformLifecycle},
+	{"Platform/GetPlatforms", testPlatformList},
+	{"L5/Sid", testL5Sid},
+	{"History/AddAndQuery", testHistoryAddAndQuery},
+	{"History/Delete", testHistoryDelete},
+	{"Auth/User", testAuthUser},
+	{"Auth/UserGroup", testAuthUserGroup},
+	{"Auth/Role", testAuthRole},
+	{"Transaction/LockNamespace", testTransactionLockNamespace},
+	{"Transaction/DeleteNamespace", testTransactionDeleteNamespace},
+	{"Transaction/LockService", testTransactionLockService},
+	{"Transaction/ExclusiveLock", testTransactionExclusiveLock},
+	{"Transaction/SharedLock", testTransactionSharedLock},
+	{"Transaction/LockBootstrap", testTransactionLockBootstrap},
+}
+
+/**
+ * Run 依次执行全部的场景，每个场景使用一个新的存储实例
+ */
+func (s *Suite) Run(t *testing.T) {
+	known := make(map[string]bool, len(scenarios))
+	for _, entry := range scenarios {
+		known[entry.name] = true
+	}
+	// 跳过列表中的场景必须存在，避免场景改名之后差异被悄悄掩盖
+	var unknown []string
+	for name := range s.Skip {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		t.Fatalf("skip unknown scenarios: %v", unknown)
+	}
+
+	for _, entry := range scenarios {
+		entry := entry
+		t.Run(entry.name, func(t *testing.T) {
+			if reason, ok := s.Skip[entry.name]; ok {
+				t.Skip(reason)
+			}
+			st := s.Factory(t)
+			defer func() {
+				if err := st.Destroy(); err != nil {
+					t.Errorf("destroy store err: %s", err.Error())
+				}
+			}()
+			// 场景panic时记录为失败，不影响其他场景的执行
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("scenario panic: %v\n%s", r, debug.Stack())
+				}
+			}()
+			entry.run(t, st)
+		})
+	}
+}
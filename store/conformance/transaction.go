@@ -0,0 +1,194 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package conformance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 等待另一个事务获取锁的时间
+const lockWaitTimeout = 10 * time.Second
+
+// 事务里锁住命名空间，只返回有效的命名空间
+func testTransactionLockNamespace(t *testing.T, s store.Store) {
+	namespace := addNamespace(t, s)
+	tx := createTransaction(t, s)
+	defer commit(t, tx)
+
+	got, err := tx.LockNamespace(namespace.Name)
+	if err != nil {
+		t.Fatalf("lock namespace err: %s", err.Error())
+	}
+	if got == nil || got.Name != namespace.Name || got.Token != namespace.Token || !got.Valid {
+		t.Fatalf("locked namespace not match: %+v", got)
+	}
+	if got, err := tx.LockNamespace(uniqueName("ns")); err != nil || got != nil {
+		t.Fatalf("lock not existed namespace should return nil, got %+v, err: %v", got, err)
+	}
+}
+
+// 事务里删除命名空间，删除之后不能再锁住
+func testTransactionDeleteNamespace(t *testing.T, s store.Store) {
+	namespace := addNamespace(t, s)
+	deleteNamespace(t, s, namespace.Name)
+
+	got, err := s.GetNamespace(namespace.Name)
+	if err != nil {
+		t.Fatalf("get namespace err: %s", err.Error())
+	}
+	if got != nil && got.Valid {
+		t.Fatalf("deleted namespace should not be valid: %+v", got)
+	}
+	tx := createTransaction(t, s)
+	defer commit(t, tx)
+	if got, err := tx.LockNamespace(namespace.Name); err != nil || got != nil {
+		t.Fatalf("lock deleted namespace should return nil, got %+v, err: %v", got, err)
+	}
+}
+
+// 事务里锁住服务，只返回有效的服务
+func testTransactionLockService(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	deleted := addService(t, s, service.Namespace)
+	if err := s.DeleteService(deleted.ID, deleted.Name, deleted.Namespace); err != nil {
+		t.Fatalf("delete service err: %s", err.Error())
+	}
+
+	lockers := map[string]func(tx store.Transaction, name, namespace string) (*model.Service, error){
+		"lock": func(tx store.Transaction, name, namespace string) (*model.Service, error) {
+			return tx.LockService(name, namespace)
+		},
+		"rlock": func(tx store.Transaction, name, namespace string) (*model.Service, error) {
+			return tx.RLockService(name, namespace)
+		},
+	}
+	for kind, lock := range lockers {
+		tx := createTransaction(t, s)
+		got, err := lock(tx, service.Name, service.Namespace)
+		if err != nil {
+			commit(t, tx)
+			t.Fatalf("%s service err: %s", kind, err.Error())
+		}
+		if got == nil || got.ID != service.ID || got.Token != service.Token || !got.Valid {
+			commit(t, tx)
+			t.Fatalf("%s service not match: %+v", kind, got)
+		}
+		for _, name := range []string{deleted.Name, uniqueName("svc")} {
+			if got, err := lock(tx, name, service.Namespace); err != nil || got != nil {
+				commit(t, tx)
+				t.Fatalf("%s invalid service(%s) should return nil, got %+v, err: %v", kind, name, got, err)
+			}
+		}
+		commit(t, tx)
+	}
+}
+
+// 排它锁在事务提交之前阻塞其他事务
+func testTransactionExclusiveLock(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	tx := createTransaction(t, s)
+	if _, err := tx.LockService(service.Name, service.Namespace); err != nil {
+		commit(t, tx)
+		t.Fatalf("lock service err: %s", err.Error())
+	}
+
+	locked := lockServiceAsync(s, service, false)
+	select {
+	case err := <-locked:
+		commit(t, tx)
+		t.Fatalf("service should not be locked by another transaction, err: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	commit(t, tx)
+	select {
+	case err := <-locked:
+		if err != nil {
+			t.Fatalf("lock service after commit err: %s", err.Error())
+		}
+	case <-time.After(lockWaitTimeout):
+		t.Fatalf("service should be locked after commit")
+	}
+}
+
+// 共享锁之间互不阻塞
+func testTransactionSharedLock(t *testing.T, s store.Store) {
+	_, service := addNamespaceAndService(t, s)
+	tx := createTransaction(t, s)
+	defer commit(t, tx)
+	if _, err := tx.RLockService(service.Name, service.Namespace); err != nil {
+		t.Fatalf("rlock service err: %s", err.Error())
+	}
+
+	select {
+	case err := <-lockServiceAsync(s, service, true):
+		if err != nil {
+			t.Fatalf("rlock service by another transaction err: %s", err.Error())
+		}
+	case <-time.After(lockWaitTimeout):
+		t.Fatalf("shared lock should not block another shared lock")
+	}
+}
+
+// 启动锁
+func testTransactionLockBootstrap(t *testing.T, s store.Store) {
+	tx := createTransaction(t, s)
+	defer commit(t, tx)
+	if err := tx.LockBootstrap("sz", "127.0.0.1"); err != nil {
+		t.Fatalf("lock bootstrap err: %s", err.Error())
+	}
+}
+
+// 在另一个事务里锁住服务，获取到锁之后提交事务
+func lockServiceAsync(s store.Store, service *model.Service, shared bool) <-chan error {
+	locked := make(chan error, 1)
+	go func() {
+		tx, err := s.CreateTransaction()
+		if err != nil {
+			locked <- err
+			return
+		}
+		if shared {
+			_, err = tx.RLockService(service.Name, service.Namespace)
+		} else {
+			_, err = tx.LockService(service.Name, service.Namespace)
+		}
+		if commitErr := tx.Commit(); err == nil {
+			err = commitErr
+		}
+		locked <- err
+	}()
+	return locked
+}
+
+func createTransaction(t *testing.T, s store.Store) store.Transaction {
+	tx, err := s.CreateTransaction()
+	if err != nil {
+		t.Fatalf("create transaction err: %s", err.Error())
+	}
+	return tx
+}
+
+func commit(t *testing.T, tx store.Transaction) {
+	if err := tx.Commit(); err != nil {
+		t.Errorf("commit transaction err: %s", err.Error())
+	}
+}
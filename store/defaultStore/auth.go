@@ -0,0 +1,305 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package defaultStore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+/**
+ * @brief authStore的实现，用户组以及角色的成员和权限策略以json格式保存
+ */
+type authStore struct {
+	master *BaseDB
+}
+
+/**
+ * @brief 新增用户
+ */
+func (a *authStore) AddUser(user *model.User) error {
+	str := `insert into user (id, name, password, comment, ctime, mtime) values(?,?,?,?,sysdate(),sysdate())`
+	if _, err := a.master.Exec(str, user.ID, user.Name, user.Password, user.Comment); err != nil {
+		log.Errorf("[Store][auth] add user(%s) err: %s", user.Name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+/**
+ * @brief 更新用户
+ */
+func (a *authStore) UpdateUser(user *model.User) error {
+	str := `update user set password = ?, comment = ?, mtime = sysdate() where name = ?`
+	if _, err := a.master.Exec(str, user.Password, user.Comment, user.Name); err != nil {
+		log.Errorf("[Store][auth] update user(%s) err: %s", user.Name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+/**
+ * @brief 删除用户
+ */
+func (a *authStore) DeleteUser(name string) error {
+	if _, err := a.master.Exec(`delete from user where name = ?`, name); err != nil {
+		log.Errorf("[Store][auth] delete user(%s) err: %s", name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+/**
+ * @brief 查询全部用户
+ */
+func (a *authStore) GetUsers() ([]*model.User, error) {
+	str := `select id, name, password, IFNULL(comment, ""), unix_timestamp(ctime), unix_timestamp(mtime) 
+			from user`
+	rows, err := a.master.Query(str)
+	if err != nil {
+		log.Errorf("[Store][auth] get users err: %s", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*model.User
+	for rows.Next() {
+		var user model.User
+		var ctime, mtime int64
+		if err := rows.Scan(&user.ID, &user.Name, &user.Password, &user.Comment, &ctime, &mtime); err != nil {
+			log.Errorf("[Store][auth] fetch user rows scan err: %s", err.Error())
+			return nil, err
+		}
+		user.CreateTime = time.Unix(ctime, 0)
+		user.ModifyTime = time.Unix(mtime, 0)
+		out = append(out, &user)
+	}
+	if err := rows.Err(); err != nil {
+		log.Errorf("[Store][auth] fetch user rows next err: %s", err.Error())
+		return nil, err
+	}
+	return out, nil
+}
+
+/**
+ * @brief 新增用户组
+ */
+func (a *authStore) AddUserGroup(group *model.UserGroup) error {
+	users, err := json.Marshal(group.Users)
+	if err != nil {
+		return err
+	}
+	str := `insert into user_group (id, name, comment, users, ctime, mtime) values(?,?,?,?,sysdate(),sysdate())`
+	if _, err := a.master.Exec(str, group.ID, group.Name, group.Comment, string(users)); err != nil {
+		log.Errorf("[Store][auth] add user group(%s) err: %s", group.Name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+/**
+ * @brief 更新用户组
+ */
+func (a *authStore) UpdateUserGroup(group *model.UserGroup) error {
+	users, err := json.Marshal(group.Users)
+	if err != nil {
+		return err
+	}
+	str := `update user_group set comment = ?, users = ?, mtime = sysdate() where name = ?`
+	if _, err := a.master.Exec(str, group.Comment, string(users), group.Name); err != nil {
+		log.Errorf("[Store][auth] update user group(%s) err: %s", group.Name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+/**
+ * @brief 删除用户组
+ */
+func (a *authStore) DeleteUserGroup(name string) error {
+	if _, err := a.master.Exec(`delete from user_group where name = ?`, name); err != nil {
+		log.Errorf("[Store][auth] delete user group(%s) err: %s", name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+/**
+ * @brief 查询全部用户组
+ */
+func (a *authStore) GetUserGroups() ([]*model.UserGroup, error) {
+	str := `select id, name, IFNULL(comment, ""), IFNULL(users, ""), unix_timestamp(ctime), unix_timestamp(mtime) 
+			from user_group`
+	rows, err := a.master.Query(str)
+	if err != nil {
+		log.Errorf("[Store][auth] get user groups err: %s", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*model.UserGroup
+	for rows.Next() {
+		var group model.UserGroup
+		var users string
+		var ctime, mtime int64
+		if err := rows.Scan(&group.ID, &group.Name, &group.Comment, &users, &ctime, &mtime); err != nil {
+			log.Errorf("[Store][auth] fetch user group rows scan err: %s", err.Error())
+			return nil, err
+		}
+		if err := unmarshalAuthField(users, &group.Users); err != nil {
+			log.Errorf("[Store][auth] user group(%s) users is invalid: %s", group.Name, err.Error())
+			return nil, err
+		}
+		group.CreateTime = time.Unix(ctime, 0)
+		group.ModifyTime = time.Unix(mtime, 0)
+		out = append(out, &group)
+	}
+	if err := rows.Err(); err != nil {
+		log.Errorf("[Store][auth] fetch user group rows next err: %s", err.Error())
+		return nil, err
+	}
+	return out, nil
+}
+
+/**
+ * @brief 新增角色
+ */
+func (a *authStore) AddRole(role *model.Role) error {
+	users, groups, policies, err := marshalRole(role)
+	if err != nil {
+		return err
+	}
+	str := `insert into role (id, name, comment, users, user_groups, policies, ctime, mtime) 
+			values(?,?,?,?,?,?,sysdate(),sysdate())`
+	if _, err := a.master.Exec(str, role.ID, role.Name, role.Comment, users, groups, policies); err != nil {
+		log.Errorf("[Store][auth] add role(%s) err: %s", role.Name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+/**
+ * @brief 更新角色
+ */
+func (a *authStore) UpdateRole(role *model.Role) error {
+	users, groups, policies, err := marshalRole(role)
+	if err != nil {
+		return err
+	}
+	str := `update role set comment = ?, users = ?, user_groups = ?, policies = ?, mtime = sysdate() 
+			where name = ?`
+	if _, err := a.master.Exec(str, role.Comment, users, groups, policies, role.Name); err != nil {
+		log.Errorf("[Store][auth] update role(%s) err: %s", role.Name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+/**
+ * @brief 删除角色
+ */
+func (a *authStore) DeleteRole(name string) error {
+	if _, err := a.master.Exec(`delete from role where name = ?`, name); err != nil {
+		log.Errorf("[Store][auth] delete role(%s) err: %s", name, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+/**
+ * @brief 查询全部角色
+ */
+func (a *authStore) GetRoles() ([]*model.Role, error) {
+	str := `select id, name, IFNULL(comment, ""), IFNULL(users, ""), IFNULL(user_groups, ""), 
+			IFNULL(policies, ""), unix_timestamp(ctime), unix_timestamp(mtime) from role`
+	rows, err := a.master.Query(str)
+	if err != nil {
+		log.Errorf("[Store][auth] get roles err: %s", err.Error())
+		return nil, err
+	}
+	return fetchRoleRows(rows)
+}
+
+/**
+ * @brief 读取角色数据
+ */
+func fetchRoleRows(rows *sql.Rows) ([]*model.Role, error) {
+	defer rows.Close()
+	var out []*model.Role
+	for rows.Next() {
+		var role model.Role
+		var users, groups, policies string
+		var ctime, mtime int64
+		err := rows.Scan(&role.ID, &role.Name, &role.Comment, &users, &groups, &policies, &ctime, &mtime)
+		if err != nil {
+			log.Errorf("[Store][auth] fetch role rows scan err: %s", err.Error())
+			return nil, err
+		}
+		if err := unmarshalAuthField(users, &role.Users); err != nil {
+			return nil, err
+		}
+		if err := unmarshalAuthField(groups, &role.Groups); err != nil {
+			return nil, err
+		}
+		if err := unmarshalAuthField(policies, &role.Policies); err != nil {
+			log.Errorf("[Store][auth] role(%s) policies is invalid: %s", role.Name, err.Error())
+			return nil, err
+		}
+		role.CreateTime = time.Unix(ctime, 0)
+		role.ModifyTime = time.Unix(mtime, 0)
+		out = append(out, &role)
+	}
+	if err := rows.Err(); err != nil {
+		log.Errorf("[Store][auth] fetch role rows next err: %s", err.Error())
+		return nil, err
+	}
+	return out, nil
+}
+
+/**
+ * @brief 把角色的成员以及权限策略序列化为json
+ */
+func marshalRole(role *model.Role) (string, string, string, error) {
+	users, err := json.Marshal(role.Users)
+	if err != nil {
+		return "", "", "", err
+	}
+	groups, err := json.Marshal(role.Groups)
+	if err != nil {
+		return "", "", "", err
+	}
+	policies, err := json.Marshal(role.Policies)
+	if err != nil {
+		return "", "", "", err
+	}
+	return string(users), string(groups), string(policies), nil
+}
+
+/**
+ * @brief 解析json格式的字段，为空时不处理
+ */
+func unmarshalAuthField(data string, v interface{}) error {
+	if data == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(data), v)
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"github.com/polarismesh/polaris-server/common/log"
 	"github.com/polarismesh/polaris-server/plugin"
+	"github.com/polarismesh/polaris-server/store"
 	"strings"
 	"time"
 )
@@ -36,6 +37,8 @@ type BaseDB struct {
 	cfg            *dbConfig
 	isolationLevel sql.IsolationLevel
 	parsePwd       plugin.ParsePassword
+	// 写入成功之后的数据变更回调
+	onChange func(resources ...store.ChangeResource)
 }
 
 /**
@@ -118,6 +121,11 @@ func (b *BaseDB) Exec(query string, args ...interface{}) (sql.Result, error) {
 		result, err = b.DB.Exec(query, args...)
 		return err
 	})
+	if err == nil {
+		if resource, ok := parseChangeResource(query); ok {
+			b.notifyChanges(resource)
+		}
+	}
 
 	return result, err
 }
@@ -147,12 +155,42 @@ func (b *BaseDB) Begin() (*BaseTx, error) {
 		return err
 	})
 
-	return &BaseTx{Tx: tx}, err
+	return &BaseTx{Tx: tx, db: b}, err
+}
+
+// 通知数据变更
+func (b *BaseDB) notifyChanges(resources ...store.ChangeResource) {
+	if b.onChange != nil && len(resources) > 0 {
+		b.onChange(resources...)
+	}
 }
 
 // 对sql.Tx的封装
 type BaseTx struct {
 	*sql.Tx
+	db *BaseDB
+	// 事务中涉及的数据变更，提交成功之后再通知
+	changes []store.ChangeResource
+}
+
+// 重写tx.Exec函数，记录数据变更
+func (t *BaseTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	result, err := t.Tx.Exec(query, args...)
+	if err == nil {
+		if resource, ok := parseChangeResource(query); ok {
+			t.changes = append(t.changes, resource)
+		}
+	}
+	return result, err
+}
+
+// 重写tx.Commit函数，提交成功之后通知数据变更
+func (t *BaseTx) Commit() error {
+	if err := t.Tx.Commit(); err != nil {
+		return err
+	}
+	t.db.notifyChanges(t.changes...)
+	return nil
 }
 
 // 重试主函数
@@ -103,8 +103,8 @@ func (bs *businessStore) ListBusiness(owner string) ([]*model.Business, error) {
 		return nil, fmt.Errorf("List Business Mising param owner")
 	}
 
-	str := genBusinessSelectSQL() + " where owner like '%?%'"
-	rows, err := bs.db.Query(str, owner)
+	str := genBusinessSelectSQL() + " where owner like ?"
+	rows, err := bs.db.Query(str, "%"+owner+"%")
 	if err != nil {
 		log.Errorf("[Store][database] list all business err: %s", err.Error())
 		return nil, err
@@ -0,0 +1,333 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultStore
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+const (
+	// 默认的变更记录拉取间隔
+	defaultChangeLogInterval = 200 * time.Millisecond
+	// 变更记录的保留时间，超过的记录定期删除
+	changeLogRetention     = 10 * time.Minute
+	changeLogCleanInterval = time.Minute
+	// 每次拉取的最大记录数
+	changeLogBatchSize = 1000
+	// 自增id的分配顺序与提交顺序不一致，每次拉取时重复拉取lastID之前的记录数
+	changeLogLookback = 100
+)
+
+// 写入语句的表名
+var writeTableRegexp = regexp.MustCompile(
+	"(?i)^\\s*(?:insert\\s+(?:ignore\\s+)?into|replace\\s+into|update|delete\\s+from)\\s+`?(\\w+)")
+
+// 表与缓存资源的对应关系，写入这些表后通知缓存更新
+var table2ChangeResource = map[string]store.ChangeResource{
+	"service":                      store.ChangeService,
+	"service_metadata":             store.ChangeService,
+	"owner_service_map":            store.ChangeService,
+	"instance":                     store.ChangeInstance,
+	"instance_metadata":            store.ChangeInstance,
+	"health_check":                 store.ChangeInstance,
+	"routing_config":               store.ChangeRoutingConfig,
+	"ratelimit_config":             store.ChangeRateLimit,
+	"ratelimit_revision":           store.ChangeRateLimit,
+	"circuitbreaker_rule":          store.ChangeCircuitBreaker,
+	"circuitbreaker_rule_relation": store.ChangeCircuitBreaker,
+	"t_route":                      store.ChangeL5,
+	"t_policy":                     store.ChangeL5,
+	"t_section":                    store.ChangeL5,
+	"t_ip_config":                  store.ChangeL5,
+}
+
+/**
+ * @brief 解析写入语句对应的缓存资源
+ */
+func parseChangeResource(query string) (store.ChangeResource, bool) {
+	match := writeTableRegexp.FindStringSubmatch(query)
+	if match == nil {
+		return "", false
+	}
+	resource, ok := table2ChangeResource[strings.ToLower(match[1])]
+	return resource, ok
+}
+
+/**
+ * @brief 变更记录表的配置
+ */
+type changeLogConfig struct {
+	open     bool
+	interval time.Duration
+}
+
+/**
+ * @brief 解析store.option中的changeLog配置
+ */
+func parseChangeLogConf(opt map[string]interface{}) (*changeLogConfig, error) {
+	c := &changeLogConfig{interval: defaultChangeLogInterval}
+	entry, ok := opt["changeLog"]
+	if !ok || entry == nil {
+		return c, nil
+	}
+	obj, ok := entry.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.New("database changeLog config is error")
+	}
+	c.open, _ = obj["open"].(bool)
+	if interval, _ := obj["interval"].(int); interval > 0 {
+		c.interval = time.Duration(interval) * time.Millisecond
+	}
+	return c, nil
+}
+
+/**
+ * @brief 数据变更通知
+ * 本server的写入直接通知，开启changeLog时同时写入change_log表，其他server定期拉取
+ */
+type changeNotifier struct {
+	store.ChangeNotifier
+
+	config *changeLogConfig
+	db     *BaseDB
+	// 本server的标识，拉取时跳过本server写入的记录
+	server string
+	window *changeLogWindow
+	once   sync.Once
+	cancel context.CancelFunc
+}
+
+/**
+ * @brief 创建数据变更通知，开启changeLog时需要已经执行过对应的升级脚本
+ */
+func newChangeNotifier(db *BaseDB, config *changeLogConfig) (*changeNotifier, error) {
+	c := &changeNotifier{
+		config: config,
+		db:     db,
+		server: strings.Replace(uuid.New().String(), "-", "", -1),
+		window: newChangeLogWindow(),
+	}
+	if !config.open {
+		return c, nil
+	}
+
+	if err := db.QueryRow("select IFNULL(max(id), 0) from change_log").Scan(&c.window.lastID); err != nil {
+		log.Errorf("[Store][database] query change log err: %s, run `polaris-server migrate up` first",
+			err.Error())
+		return nil, err
+	}
+	// 记录启动前已经存在的记录，避免第一次拉取时重复通知
+	if _, err := c.fetch(); err != nil {
+		log.Errorf("[Store][database] fetch change log err: %s", err.Error())
+		return nil, err
+	}
+	log.Infof("[Store][database] change log is open, interval: %v, last id: %d",
+		config.interval, c.window.lastID)
+	return c, nil
+}
+
+/**
+ * @brief 写入成功之后的回调
+ */
+func (c *changeNotifier) changed(resources ...store.ChangeResource) {
+	resources = uniqueChangeResources(resources)
+	c.Notify(resources...)
+	if !c.config.open {
+		return
+	}
+
+	str := "insert into change_log(resource, server, ctime) values"
+	args := make([]interface{}, 0, len(resources)*2)
+	for i, resource := range resources {
+		if i > 0 {
+			str += ","
+		}
+		str += "(?, ?, sysdate())"
+		args = append(args, string(resource), c.server)
+	}
+	if _, err := c.db.Exec(str, args...); err != nil {
+		// 写入失败时，其他server依赖定期的全量轮询
+		log.Errorf("[Store][database] insert change log(%v) err: %s", resources, err.Error())
+	}
+}
+
+/**
+ * @brief 启动拉取协程，只启动一次
+ */
+func (c *changeNotifier) start() {
+	if !c.config.open {
+		return
+	}
+	c.once.Do(func() {
+		var ctx context.Context
+		ctx, c.cancel = context.WithCancel(context.Background())
+		go c.run(ctx)
+	})
+}
+
+/**
+ * @brief 停止拉取协程
+ */
+func (c *changeNotifier) stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// 定期拉取其他server的变更记录，并清理过期的记录
+func (c *changeNotifier) run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.interval)
+	defer ticker.Stop()
+	cleanTicker := time.NewTicker(changeLogCleanInterval)
+	defer cleanTicker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.pull()
+		case <-cleanTicker.C:
+			c.clean()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+/**
+ * @brief 拉取新增的变更记录
+ */
+func (c *changeNotifier) pull() {
+	resources, err := c.fetch()
+	if err != nil {
+		log.Errorf("[Store][database] pull change log err: %s", err.Error())
+		return
+	}
+	if len(resources) > 0 {
+		c.Notify(uniqueChangeResources(resources)...)
+	}
+}
+
+// 拉取没有处理过的变更记录，返回其他server变更的资源
+func (c *changeNotifier) fetch() ([]store.ChangeResource, error) {
+	str := "select id, resource, server from change_log where id > ? order by id limit ?"
+	rows, err := c.db.Query(str, c.window.begin(), changeLogLookback+changeLogBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var resources []store.ChangeResource
+	for rows.Next() {
+		var id uint64
+		var resource, server string
+		if err := rows.Scan(&id, &resource, &server); err != nil {
+			return nil, err
+		}
+		if c.window.add(id) && server != c.server {
+			resources = append(resources, store.ChangeResource(resource))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	c.window.shrink()
+	return resources, nil
+}
+
+/**
+ * @brief 已经处理过的变更记录
+ * 自增id在事务提交之后才可见，较小的id可能晚于较大的id提交，
+ * 因此每次从lastID之前changeLogLookback条开始拉取，并跳过已经处理过的id
+ */
+type changeLogWindow struct {
+	lastID uint64
+	seen   map[uint64]struct{}
+}
+
+// 新建变更记录的窗口
+func newChangeLogWindow() *changeLogWindow {
+	return &changeLogWindow{seen: make(map[uint64]struct{})}
+}
+
+// 拉取的起始id，不包含该id
+func (w *changeLogWindow) begin() uint64 {
+	if w.lastID <= changeLogLookback {
+		return 0
+	}
+	return w.lastID - changeLogLookback
+}
+
+// 记录拉取到的id，返回该id是否是第一次处理
+func (w *changeLogWindow) add(id uint64) bool {
+	if id <= w.begin() {
+		return false
+	}
+	if _, ok := w.seen[id]; ok {
+		return false
+	}
+	w.seen[id] = struct{}{}
+	if id > w.lastID {
+		w.lastID = id
+	}
+	return true
+}
+
+// 删除窗口之外的id
+func (w *changeLogWindow) shrink() {
+	begin := w.begin()
+	for id := range w.seen {
+		if id <= begin {
+			delete(w.seen, id)
+		}
+	}
+}
+
+/**
+ * @brief 删除过期的变更记录
+ */
+func (c *changeNotifier) clean() {
+	str := "delete from change_log where ctime < ?"
+	if _, err := c.db.Exec(str, time2String(time.Now().Add(-changeLogRetention))); err != nil {
+		log.Errorf("[Store][database] clean change log err: %s", err.Error())
+	}
+}
+
+// 去掉重复的资源
+func uniqueChangeResources(resources []store.ChangeResource) []store.ChangeResource {
+	if len(resources) <= 1 {
+		return resources
+	}
+	exists := make(map[store.ChangeResource]bool, len(resources))
+	out := make([]store.ChangeResource, 0, len(resources))
+	for _, resource := range resources {
+		if !exists[resource] {
+			exists[resource] = true
+			out = append(out, resource)
+		}
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultStore
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 解析写入语句对应的缓存资源
+func TestParseChangeResource(t *testing.T) {
+	cases := map[string]store.ChangeResource{
+		"insert into instance(id, host) values(?, ?)":             store.ChangeInstance,
+		"  replace into health_check(id, type) values(?, ?)":      store.ChangeInstance,
+		"update service set flag = 1 where id = ?":                store.ChangeService,
+		"delete from routing_config where id = ? and flag = 1":    store.ChangeRoutingConfig,
+		"UPDATE ratelimit_revision SET last_revision = ?":         store.ChangeRateLimit,
+		"insert into `circuitbreaker_rule_relation` values(?, ?)": store.ChangeCircuitBreaker,
+	}
+	for query, expect := range cases {
+		if resource, ok := parseChangeResource(query); !ok || resource != expect {
+			t.Fatalf("query(%s) resource: %s, expect: %s", query, resource, expect)
+		}
+	}
+
+	for _, query := range []string{
+		"select id from instance",
+		"insert into history(resource_type) values(?)",
+		"delete from change_log where ctime < ?",
+		"update namespace set token = ?",
+	} {
+		if resource, ok := parseChangeResource(query); ok {
+			t.Fatalf("query(%s) should not change cache, got %s", query, resource)
+		}
+	}
+}
+
+// 事务或者批量写入时合并重复的资源
+func TestUniqueChangeResources(t *testing.T) {
+	out := uniqueChangeResources([]store.ChangeResource{
+		store.ChangeInstance, store.ChangeService, store.ChangeInstance,
+	})
+	if len(out) != 2 || out[0] != store.ChangeInstance || out[1] != store.ChangeService {
+		t.Fatalf("unique resources: %v", out)
+	}
+}
+
+// 较小的id晚于较大的id提交时，重复拉取窗口内的记录，并且只处理一次
+func TestChangeLogWindow(t *testing.T) {
+	w := newChangeLogWindow()
+	if w.begin() != 0 {
+		t.Fatalf("begin should be 0, got %d", w.begin())
+	}
+
+	// id为2的记录还没有提交
+	for _, id := range []uint64{1, 3} {
+		if !w.add(id) {
+			t.Fatalf("id(%d) should be added", id)
+		}
+	}
+	w.shrink()
+	// 再次拉取时已经处理的id被跳过，晚提交的id被处理
+	for id, expect := range map[uint64]bool{1: false, 2: true, 3: false, 4: true} {
+		if w.add(id) != expect {
+			t.Fatalf("add id(%d) should be %v", id, expect)
+		}
+	}
+	if w.lastID != 4 {
+		t.Fatalf("last id should be 4, got %d", w.lastID)
+	}
+
+	// 超过窗口的id被删除
+	last := uint64(changeLogLookback + 10)
+	if !w.add(last) {
+		t.Fatalf("id(%d) should be added", last)
+	}
+	w.shrink()
+	if w.begin() != 10 || len(w.seen) != 1 {
+		t.Fatalf("window should be shrunk, begin: %d, seen: %d", w.begin(), len(w.seen))
+	}
+	if w.add(5) || !w.add(11) || w.add(last) {
+		t.Fatalf("ids should be filtered by window")
+	}
+}
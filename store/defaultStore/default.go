@@ -58,6 +58,8 @@ type stableStore struct {
 	*rateLimitStore
 	*circuitBreakerStore
 	*platformStore
+	*historyStore
+	*authStore
 
 	// 主数据库，可以进行读写
 	master *BaseDB
@@ -67,6 +69,8 @@ type stableStore struct {
 	slave    *BaseDB
 	start    bool
 	metaTask *TaskManager
+	// 数据变更通知
+	notifier *changeNotifier
 }
 
 /**
@@ -88,6 +92,10 @@ func (s *stableStore) Initialize(conf *store.Config) error {
 	if err != nil {
 		return err
 	}
+	changeLogConfig, err := parseChangeLogConf(conf.Option)
+	if err != nil {
+		return err
+	}
 	master, err := NewBaseDB(masterConfig, plugin.GetParsePassword())
 	if err != nil {
 		return err
@@ -115,6 +123,21 @@ func (s *stableStore) Initialize(conf *store.Config) error {
 
 	log.Infof("[Store][database] connect the database successfully")
 
+	// 数据库版本落后于二进制时，根据配置拒绝启动或者自动升级
+	policy, _ := conf.Option["migrate"].(string)
+	if err := checkSchemaVersion(s.master, policy); err != nil {
+		log.Errorf("[Store][database] check schema version err: %s", err.Error())
+		return err
+	}
+
+	notifier, err := newChangeNotifier(s.master, changeLogConfig)
+	if err != nil {
+		return err
+	}
+	s.notifier = notifier
+	s.master.onChange = notifier.changed
+	s.masterTx.onChange = notifier.changed
+
 	s.start = true
 	s.newStore()
 	return nil
@@ -188,6 +211,9 @@ func parseStoreConfig(opts interface{}) (*dbConfig, error) {
  * @brief 退出函数
  */
 func (s *stableStore) Destroy() error {
+	if s.notifier != nil {
+		s.notifier.stop()
+	}
 	if s.master != nil {
 		_ = s.master.Close()
 	}
@@ -201,6 +227,15 @@ func (s *stableStore) Destroy() error {
 	return nil
 }
 
+/**
+ * @brief 订阅数据变更，只有开启changeLog时才能收到其他server的写入
+ */
+func (s *stableStore) WatchChanges(listener store.ChangeListener) bool {
+	s.notifier.AddListener(listener)
+	s.notifier.start()
+	return s.notifier.config.open
+}
+
 /**
  * @brief 创建一个事务
  */
@@ -237,6 +272,10 @@ func (s *stableStore) newStore() {
 	s.circuitBreakerStore = &circuitBreakerStore{master: s.master, slave: s.slave}
 
 	s.platformStore = &platformStore{master: s.master}
+
+	s.historyStore = &historyStore{master: s.master, slave: s.slave}
+
+	s.authStore = &authStore{master: s.master}
 }
 
 // time.Time转为字符串时间
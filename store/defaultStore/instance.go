@@ -647,7 +647,8 @@ func fetchInstanceWithMetaRows(rows *sql.Rows) (map[string]*model.Instance, erro
 		if err := rows.Scan(&item.ID, &item.ServiceID, &item.VpcID, &item.Host, &item.Port, &item.Protocol,
 			&item.Version, &item.HealthStatus, &item.Isolate, &item.Weight, &item.EnableHealthCheck,
 			&item.LogicSet, &item.Region, &item.Zone, &item.Campus, &item.Priority, &item.Revision,
-			&item.Flag, &item.CheckType, &item.TTL, &id, &mKey, &mValue,
+			&item.Flag, &item.CheckType, &item.TTL, &item.ProbeInterval, &item.ProbeTimeout, &item.ProbePath,
+			&item.ProbeExpectedStatus, &id, &mKey, &mValue,
 			&item.CreateTime, &item.ModifyTime); err != nil {
 			log.Errorf("[Store][database] fetch instance+meta rows err: %s", err.Error())
 			return nil, err
@@ -850,15 +851,17 @@ func addInstanceCheck(tx *BaseTx, instance *model.Instance) error {
 		return nil
 	}
 
-	str := "insert into health_check(`id`, `type`, `ttl`) values(?, ?, ?)"
-	_, err := tx.Exec(str, instance.ID(), check.GetType(),
-		check.GetHeartbeat().GetTtl().GetValue())
+	str := "insert into health_check(`id`, `type`, `ttl`, `probe_interval`, `probe_timeout`, `probe_path`, " +
+		"`probe_expected_status`) values(?, ?, ?, ?, ?, ?, ?)"
+	args := append([]interface{}{instance.ID()}, healthCheckArgs(check)...)
+	_, err := tx.Exec(str, args...)
 	return err
 }
 
 // 批量增加healthCheck数据
 func batchAddInstanceCheck(tx *BaseTx, instances []*model.Instance) error {
-	str := "insert into health_check(`id`, `type`, `ttl`) values"
+	str := "insert into health_check(`id`, `type`, `ttl`, `probe_interval`, `probe_timeout`, `probe_path`, " +
+		"`probe_expected_status`) values"
 	first := true
 	args := make([]interface{}, 0)
 	for _, entry := range instances {
@@ -868,10 +871,10 @@ func batchAddInstanceCheck(tx *BaseTx, instances []*model.Instance) error {
 		if !first {
 			str += ","
 		}
-		str += "(?,?,?)"
+		str += "(?,?,?,?,?,?,?)"
 		first = false
-		args = append(args, entry.ID(), entry.HealthCheck().GetType(),
-			entry.HealthCheck().GetHeartbeat().GetTtl().GetValue())
+		args = append(args, entry.ID())
+		args = append(args, healthCheckArgs(entry.HealthCheck())...)
 	}
 	// 不存在健康检查信息，直接返回
 	if first {
@@ -883,6 +886,24 @@ func batchAddInstanceCheck(tx *BaseTx, instances []*model.Instance) error {
 
 }
 
+// health_check表中除id外的字段值
+func healthCheckArgs(check *v1.HealthCheck) []interface{} {
+	var interval, timeout, expectedStatus uint32
+	var path string
+	switch check.GetType() {
+	case v1.HealthCheck_TCP:
+		interval = check.GetTcp().GetInterval().GetValue()
+		timeout = check.GetTcp().GetTimeout().GetValue()
+	case v1.HealthCheck_HTTP:
+		interval = check.GetHttp().GetInterval().GetValue()
+		timeout = check.GetHttp().GetTimeout().GetValue()
+		path = check.GetHttp().GetPath().GetValue()
+		expectedStatus = check.GetHttp().GetExpectedStatus().GetValue()
+	}
+	return []interface{}{check.GetType(), check.GetHeartbeat().GetTtl().GetValue(),
+		interval, timeout, path, expectedStatus}
+}
+
 // 往表中加入instance meta数据
 func addInstanceMeta(tx *BaseTx, id string, meta map[string]string) error {
 	if len(meta) == 0 {
@@ -961,9 +982,10 @@ func updateInstanceCheck(tx *BaseTx, instance *model.Instance) error {
 		return deleteInstanceCheck(tx, instance.ID())
 	}
 
-	str := "replace into health_check(id, type, ttl) values(?, ?, ?)"
-	_, err := tx.Exec(str, instance.ID(), check.GetType(),
-		check.GetHeartbeat().GetTtl().GetValue())
+	str := "replace into health_check(id, type, ttl, probe_interval, probe_timeout, probe_path, " +
+		"probe_expected_status) values(?, ?, ?, ?, ?, ?, ?)"
+	args := append([]interface{}{instance.ID()}, healthCheckArgs(check)...)
+	_, err := tx.Exec(str, args...)
 	return err
 }
 
@@ -1020,7 +1042,8 @@ func callFetchInstanceRows(rows *sql.Rows, callback func(entry *model.InstanceSt
 		err := rows.Scan(&item.ID, &item.ServiceID, &item.VpcID, &item.Host, &item.Port, &item.Protocol,
 			&item.Version, &item.HealthStatus, &item.Isolate, &item.Weight, &item.EnableHealthCheck,
 			&item.LogicSet, &item.Region, &item.Zone, &item.Campus, &item.Priority, &item.Revision,
-			&item.Flag, &item.CheckType, &item.TTL, &item.CreateTime, &item.ModifyTime)
+			&item.Flag, &item.CheckType, &item.TTL, &item.ProbeInterval, &item.ProbeTimeout, &item.ProbePath,
+			&item.ProbeExpectedStatus, &item.CreateTime, &item.ModifyTime)
 		if err != nil {
 			log.Errorf("[Store][database] fetch instance rows err: %s", err.Error())
 			return err
@@ -1062,7 +1085,8 @@ func fetchExpandInstanceRows(rows *sql.Rows) ([]*model.Instance, error) {
 			&instance.Protocol, &instance.Version, &instance.HealthStatus, &instance.Isolate,
 			&instance.Weight, &instance.EnableHealthCheck, &instance.LogicSet, &instance.Region,
 			&instance.Zone, &instance.Campus, &instance.Priority, &instance.Revision, &instance.Flag,
-			&instance.CheckType, &instance.TTL, &item.ServiceName, &item.Namespace,
+			&instance.CheckType, &instance.TTL, &instance.ProbeInterval, &instance.ProbeTimeout,
+			&instance.ProbePath, &instance.ProbeExpectedStatus, &item.ServiceName, &item.Namespace,
 			&instance.CreateTime, &instance.ModifyTime)
 		if err != nil {
 			log.Errorf("[Store][database] fetch instance rows err: %s", err.Error())
@@ -1119,7 +1143,9 @@ func genInstanceSelectSQL() string {
 	str := `select instance.id, service_id, IFNULL(vpc_id,""), host, port, IFNULL(protocol, ""), IFNULL(version, ""),
 			health_status, isolate, weight, enable_health_check, IFNULL(logic_set, ""), IFNULL(cmdb_region, ""), 
 			IFNULL(cmdb_zone, ""), IFNULL(cmdb_idc, ""), priority, revision, flag, IFNULL(health_check.type, -1), 
-			IFNULL(health_check.ttl, 0), UNIX_TIMESTAMP(instance.ctime), UNIX_TIMESTAMP(instance.mtime)   
+			IFNULL(health_check.ttl, 0), IFNULL(health_check.probe_interval, 0), 
+			IFNULL(health_check.probe_timeout, 0), IFNULL(health_check.probe_path, ""), 
+			IFNULL(health_check.probe_expected_status, 0), UNIX_TIMESTAMP(instance.ctime), UNIX_TIMESTAMP(instance.mtime)   
 			from instance left join health_check 
 			on instance.id = health_check.id `
 	return str
@@ -1130,7 +1156,9 @@ func genCompleteInstanceSelectSQL() string {
 	str := `select instance.id, service_id, IFNULL(vpc_id,""), host, port, IFNULL(protocol, ""), IFNULL(version, ""),
 		health_status, isolate, weight, enable_health_check, IFNULL(logic_set, ""), IFNULL(cmdb_region, ""),
 		IFNULL(cmdb_zone, ""), IFNULL(cmdb_idc, ""), priority, revision, flag, IFNULL(health_check.type, -1),
-		IFNULL(health_check.ttl, 0), IFNULL(instance_metadata.id, ""), IFNULL(mkey, ""), IFNULL(mvalue, ""), 
+		IFNULL(health_check.ttl, 0), IFNULL(health_check.probe_interval, 0), IFNULL(health_check.probe_timeout, 0),
+		IFNULL(health_check.probe_path, ""), IFNULL(health_check.probe_expected_status, 0),
+		IFNULL(instance_metadata.id, ""), IFNULL(mkey, ""), IFNULL(mvalue, ""), 
 		UNIX_TIMESTAMP(instance.ctime), UNIX_TIMESTAMP(instance.mtime)
 		from instance 
 		left join health_check on instance.id = health_check.id 
@@ -1143,7 +1171,9 @@ func genExpandInstanceSelectSQL(needForceIndex bool) string {
 	str := `select instance.id, service_id, IFNULL(vpc_id,""), host, port, IFNULL(protocol, ""), IFNULL(version, ""), 
 					health_status, isolate, weight, enable_health_check, IFNULL(logic_set, ""), IFNULL(cmdb_region, ""), 
 					IFNULL(cmdb_zone, ""), IFNULL(cmdb_idc, ""), priority, instance.revision, instance.flag, 
-					IFNULL(health_check.type, -1), IFNULL(health_check.ttl, 0), service.name, service.namespace, 
+					IFNULL(health_check.type, -1), IFNULL(health_check.ttl, 0), IFNULL(health_check.probe_interval, 0), 
+					IFNULL(health_check.probe_timeout, 0), IFNULL(health_check.probe_path, ""), 
+					IFNULL(health_check.probe_expected_status, 0), service.name, service.namespace, 
 					UNIX_TIMESTAMP(instance.ctime), UNIX_TIMESTAMP(instance.mtime) 
 					from (service inner join instance `
 	if needForceIndex {
@@ -0,0 +1,513 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultStore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/plugin"
+)
+
+//go:generate go run gen_migrations.go
+
+const (
+	// MigrateIgnore 启动时不检查数据库版本
+	MigrateIgnore = "ignore"
+	// MigrateCheck 数据库版本落后于二进制时拒绝启动
+	MigrateCheck = "check"
+	// MigrateAuto 数据库版本落后于二进制时自动升级
+	MigrateAuto = "auto"
+
+	// 多个server同时升级时，通过mysql的命名锁串行执行
+	migrateLockName    = "polaris_server_schema_migrate"
+	migrateLockTimeout = 60
+)
+
+// 升级脚本的文件名，例如0001_init.up.sql
+var migrationFileRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// 内嵌的升级脚本，按照版本号升序排列
+var migrations = mustLoadMigrations(migrationFiles)
+
+/**
+ * @brief 一个版本的数据库升级脚本
+ */
+type SchemaMigration struct {
+	Version int
+	Name    string
+	up      []string
+	down    []string
+}
+
+/**
+ * @brief 数据库中已经执行的版本记录
+ */
+type SchemaVersion struct {
+	Version     int
+	Name        string
+	AppliedTime time.Time
+}
+
+/**
+ * @brief 数据库的版本状态
+ */
+type SchemaStatus struct {
+	// 数据库当前的版本，0表示没有执行过任何升级脚本
+	Current int
+	// 二进制内嵌的最新版本
+	Latest int
+	// 数据库中已经存在polaris的表，但是没有版本记录，需要先指定基线版本
+	Legacy  bool
+	Applied []*SchemaVersion
+	Pending []*SchemaMigration
+}
+
+/**
+ * @brief 数据库版本管理，所有的升级操作都在主库的同一个连接上执行
+ */
+type SchemaMigrator struct {
+	db *BaseDB
+}
+
+/**
+ * @brief 根据store的配置连接主库，创建数据库版本管理
+ */
+func NewSchemaMigrator(opt map[string]interface{}) (*SchemaMigrator, error) {
+	masterConfig, _, err := parseDatabaseConf(opt)
+	if err != nil {
+		return nil, err
+	}
+	master, err := NewBaseDB(masterConfig, plugin.GetParsePassword())
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaMigrator{db: master}, nil
+}
+
+/**
+ * @brief 二进制内嵌的最新版本
+ */
+func LatestSchemaVersion() int {
+	return len(migrations)
+}
+
+/**
+ * @brief 关闭数据库连接
+ */
+func (m *SchemaMigrator) Close() error {
+	return m.db.Close()
+}
+
+/**
+ * @brief 查询数据库的版本状态
+ */
+func (m *SchemaMigrator) Status() (*SchemaStatus, error) {
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return readSchemaStatus(ctx, conn)
+}
+
+/**
+ * @brief 升级到指定的版本，target为0表示升级到最新版本
+ */
+func (m *SchemaMigrator) Up(target int) ([]*SchemaMigration, error) {
+	if target == 0 {
+		target = LatestSchemaVersion()
+	}
+	if target < 0 || target > LatestSchemaVersion() {
+		return nil, fmt.Errorf("target schema version(%d) is out of range [1, %d]", target, LatestSchemaVersion())
+	}
+
+	var applied []*SchemaMigration
+	err := m.withLock(func(ctx context.Context, conn *sql.Conn) error {
+		status, err := readSchemaStatus(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := checkSchemaKnown(status); err != nil {
+			return err
+		}
+		if err := createSchemaVersionTable(ctx, conn); err != nil {
+			return err
+		}
+		for _, migration := range status.Pending {
+			if migration.Version > target {
+				break
+			}
+			if err := applyMigration(ctx, conn, migration, true); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+/**
+ * @brief 回滚到指定的版本，target为0表示回滚全部版本
+ */
+func (m *SchemaMigrator) Down(target int) ([]*SchemaMigration, error) {
+	if target < 0 || target > LatestSchemaVersion() {
+		return nil, fmt.Errorf("target schema version(%d) is out of range [0, %d]", target, LatestSchemaVersion())
+	}
+
+	var reverted []*SchemaMigration
+	err := m.withLock(func(ctx context.Context, conn *sql.Conn) error {
+		status, err := readSchemaStatus(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := checkSchemaKnown(status); err != nil {
+			return err
+		}
+		for i := len(status.Applied) - 1; i >= 0; i-- {
+			version := status.Applied[i].Version
+			if version <= target {
+				break
+			}
+			migration := migrations[version-1]
+			if err := applyMigration(ctx, conn, migration, false); err != nil {
+				return err
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+/**
+ * @brief 为没有版本记录的数据库指定基线版本
+ * 基线版本及之前的升级脚本只记录版本，不会执行
+ */
+func (m *SchemaMigrator) Baseline(version int) error {
+	if version <= 0 || version > LatestSchemaVersion() {
+		return fmt.Errorf("baseline schema version(%d) is out of range [1, %d]", version, LatestSchemaVersion())
+	}
+
+	return m.withLock(func(ctx context.Context, conn *sql.Conn) error {
+		status, err := readSchemaStatus(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(status.Applied) != 0 {
+			return fmt.Errorf("database schema version is %d, baseline is not allowed", status.Current)
+		}
+		if err := createSchemaVersionTable(ctx, conn); err != nil {
+			return err
+		}
+		for _, migration := range migrations[:version] {
+			if err := insertSchemaVersion(ctx, conn, migration); err != nil {
+				return err
+			}
+		}
+		log.Infof("[Store][database] set schema baseline version: %d", version)
+		return nil
+	})
+}
+
+/**
+ * @brief 持有命名锁执行升级操作，保证多个server不会同时升级
+ */
+func (m *SchemaMigrator) withLock(handle func(ctx context.Context, conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var locked sql.NullInt64
+	err = conn.QueryRowContext(ctx, "select GET_LOCK(?, ?)", migrateLockName, migrateLockTimeout).Scan(&locked)
+	if err != nil {
+		log.Errorf("[Store][database] get schema migrate lock err: %s", err.Error())
+		return err
+	}
+	if !locked.Valid || locked.Int64 != 1 {
+		return errors.New("get schema migrate lock timeout, other server may be migrating")
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, "select RELEASE_LOCK(?)", migrateLockName); err != nil {
+			log.Errorf("[Store][database] release schema migrate lock err: %s", err.Error())
+		}
+	}()
+
+	return handle(ctx, conn)
+}
+
+/**
+ * @brief 执行一个版本的升级或者回滚脚本
+ * DDL会隐式提交事务，执行失败时需要根据日志人工修复
+ */
+func applyMigration(ctx context.Context, conn *sql.Conn, migration *SchemaMigration, up bool) error {
+	statements, direction := migration.up, "up"
+	if !up {
+		statements, direction = migration.down, "down"
+	}
+	log.Infof("[Store][database] schema migrate %s: %04d_%s", direction, migration.Version, migration.Name)
+	for _, statement := range statements {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			log.Errorf("[Store][database] schema migrate %s %04d_%s err: %s, statement: %s",
+				direction, migration.Version, migration.Name, err.Error(), statement)
+			return fmt.Errorf("migrate %s %04d_%s: %s", direction, migration.Version, migration.Name, err.Error())
+		}
+	}
+
+	if up {
+		return insertSchemaVersion(ctx, conn, migration)
+	}
+	_, err := conn.ExecContext(ctx, "delete from schema_version where version = ?", migration.Version)
+	return err
+}
+
+/**
+ * @brief 记录已经执行的版本
+ */
+func insertSchemaVersion(ctx context.Context, conn *sql.Conn, migration *SchemaMigration) error {
+	str := "insert into schema_version(version, name, ctime) values(?, ?, sysdate())"
+	if _, err := conn.ExecContext(ctx, str, migration.Version, migration.Name); err != nil {
+		log.Errorf("[Store][database] insert schema version(%d) err: %s", migration.Version, err.Error())
+		return err
+	}
+	return nil
+}
+
+/**
+ * @brief 创建版本记录表
+ */
+func createSchemaVersionTable(ctx context.Context, conn *sql.Conn) error {
+	str := "CREATE TABLE IF NOT EXISTS `schema_version` (" +
+		"`version` int(11) NOT NULL, " +
+		"`name` varchar(128) COLLATE utf8_bin NOT NULL, " +
+		"`ctime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, " +
+		"PRIMARY KEY (`version`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin"
+	if _, err := conn.ExecContext(ctx, str); err != nil {
+		log.Errorf("[Store][database] create schema_version table err: %s", err.Error())
+		return err
+	}
+	return nil
+}
+
+/**
+ * @brief 读取数据库的版本状态
+ */
+func readSchemaStatus(ctx context.Context, conn *sql.Conn) (*SchemaStatus, error) {
+	status := &SchemaStatus{Latest: LatestSchemaVersion()}
+	exist, err := tableExists(ctx, conn, "schema_version")
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		str := "select version, name, unix_timestamp(ctime) from schema_version order by version"
+		rows, err := conn.QueryContext(ctx, str)
+		if err != nil {
+			log.Errorf("[Store][database] query schema version err: %s", err.Error())
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var version SchemaVersion
+			var ctime int64
+			if err := rows.Scan(&version.Version, &version.Name, &ctime); err != nil {
+				return nil, err
+			}
+			version.AppliedTime = time.Unix(ctime, 0)
+			status.Applied = append(status.Applied, &version)
+			status.Current = version.Version
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(status.Applied) == 0 {
+		// 通过polaris_server.sql导入但是没有版本记录的数据库
+		if status.Legacy, err = tableExists(ctx, conn, "namespace"); err != nil {
+			return nil, err
+		}
+	}
+	for _, migration := range migrations {
+		if migration.Version > status.Current {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	return status, nil
+}
+
+/**
+ * @brief 判断当前库中是否存在指定的表
+ */
+func tableExists(ctx context.Context, conn *sql.Conn, table string) (bool, error) {
+	str := "select count(*) from information_schema.tables where table_schema = database() and table_name = ?"
+	var count int
+	if err := conn.QueryRowContext(ctx, str, table).Scan(&count); err != nil {
+		log.Errorf("[Store][database] check table(%s) exists err: %s", table, err.Error())
+		return false, err
+	}
+	return count > 0, nil
+}
+
+/**
+ * @brief 检查数据库的版本是否可以执行升级或者回滚
+ */
+func checkSchemaKnown(status *SchemaStatus) error {
+	if status.Legacy {
+		return errors.New("database has no schema version, " +
+			"run `polaris-server migrate up --baseline <version>` to set the version of the existing schema")
+	}
+	if status.Current > status.Latest {
+		return fmt.Errorf("database schema version(%d) is newer than the binary(%d)", status.Current, status.Latest)
+	}
+	return nil
+}
+
+/**
+ * @brief 启动时根据配置的策略检查数据库版本
+ */
+func checkSchemaVersion(db *BaseDB, policy string) error {
+	if policy == "" || policy == MigrateIgnore {
+		return nil
+	}
+	if policy != MigrateCheck && policy != MigrateAuto {
+		return fmt.Errorf("store option migrate(%s) is invalid, must be %s, %s or %s",
+			policy, MigrateIgnore, MigrateCheck, MigrateAuto)
+	}
+
+	m := &SchemaMigrator{db: db}
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	if status.Current > status.Latest {
+		log.Warnf("[Store][database] database schema version(%d) is newer than the binary(%d)",
+			status.Current, status.Latest)
+		return nil
+	}
+	if status.Current == status.Latest {
+		return nil
+	}
+	if policy == MigrateCheck || status.Legacy {
+		if err := checkSchemaKnown(status); err != nil {
+			return err
+		}
+		return fmt.Errorf("database schema version(%d) is behind the binary(%d), "+
+			"run `polaris-server migrate up` or set store option migrate to auto", status.Current, status.Latest)
+	}
+
+	applied, err := m.Up(0)
+	if err != nil {
+		return err
+	}
+	log.Infof("[Store][database] database schema is migrated from version %d to %d",
+		status.Current, status.Current+len(applied))
+	return nil
+}
+
+/**
+ * @brief 解析内嵌的升级脚本，版本号必须从1开始连续，并且同时存在升级和回滚脚本
+ */
+func loadMigrations(files map[string]string) ([]*SchemaMigration, error) {
+	versions := make(map[int]*SchemaMigration)
+	for file, script := range files {
+		match := migrationFileRegexp.FindStringSubmatch(file)
+		if match == nil {
+			return nil, fmt.Errorf("migration file(%s) is invalid", file)
+		}
+		version, _ := strconv.Atoi(match[1])
+		migration, ok := versions[version]
+		if !ok {
+			migration = &SchemaMigration{Version: version, Name: match[2]}
+			versions[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version(%d) has different names: %s, %s",
+				version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.up = splitStatements(script)
+		} else {
+			migration.down = splitStatements(script)
+		}
+	}
+
+	out := make([]*SchemaMigration, 0, len(versions))
+	for _, migration := range versions {
+		out = append(out, migration)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Version < out[j].Version
+	})
+	for i, migration := range out {
+		if migration.Version != i+1 {
+			return nil, fmt.Errorf("migration version(%d) is missing", i+1)
+		}
+		if len(migration.up) == 0 || len(migration.down) == 0 {
+			return nil, fmt.Errorf("migration version(%d) must have both up and down statements", migration.Version)
+		}
+	}
+	return out, nil
+}
+
+// 内嵌的脚本在编译时就已经确定，解析失败直接panic
+func mustLoadMigrations(files map[string]string) []*SchemaMigration {
+	out, err := loadMigrations(files)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+/**
+ * @brief 把脚本拆分为单条语句
+ * 以--开头的行为注释，以分号结尾的行为一条语句的结束
+ */
+func splitStatements(script string) []string {
+	var statements []string
+	var lines []string
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, "\r"))
+		if strings.HasSuffix(trimmed, ";") {
+			statement := strings.TrimSuffix(strings.TrimSpace(strings.Join(lines, "\n")), ";")
+			statements = append(statements, statement)
+			lines = nil
+		}
+	}
+	if statement := strings.TrimSpace(strings.Join(lines, "\n")); statement != "" {
+		statements = append(statements, statement)
+	}
+	return statements
+}
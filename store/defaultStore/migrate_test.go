@@ -0,0 +1,90 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultStore
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 内嵌的升级脚本需要与migrations目录保持一致
+func TestMigrationFilesGenerated(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("migrations", "*.sql"))
+	if err != nil {
+		t.Fatalf("glob migrations err: %s", err.Error())
+	}
+	if len(files) != len(migrationFiles) {
+		t.Fatalf("migrations_gen.go is out of date, run go generate")
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read %s err: %s", file, err.Error())
+		}
+		if migrationFiles[filepath.Base(file)] != string(data) {
+			t.Fatalf("%s is out of date in migrations_gen.go, run go generate", file)
+		}
+	}
+}
+
+// 版本号连续，并且polaris_server.sql记录了全部的版本
+func TestLoadMigrations(t *testing.T) {
+	if LatestSchemaVersion() == 0 {
+		t.Fatalf("no migration is loaded")
+	}
+	data, err := ioutil.ReadFile("polaris_server.sql")
+	if err != nil {
+		t.Fatalf("read polaris_server.sql err: %s", err.Error())
+	}
+	for _, migration := range migrations {
+		row := fmt.Sprintf("(%d, '%s')", migration.Version, migration.Name)
+		if !strings.Contains(string(data), row) {
+			t.Fatalf("polaris_server.sql should insert schema version %s", row)
+		}
+	}
+
+	if _, err := loadMigrations(map[string]string{
+		"0001_init.up.sql": "select 1;", "0001_init.down.sql": "select 1;",
+		"0003_next.up.sql": "select 1;", "0003_next.down.sql": "select 1;",
+	}); err == nil {
+		t.Fatalf("missing version should be invalid")
+	}
+	if _, err := loadMigrations(map[string]string{"0001_init.up.sql": "select 1;"}); err == nil {
+		t.Fatalf("migration without down should be invalid")
+	}
+	if _, err := loadMigrations(map[string]string{"init.sql": "select 1;"}); err == nil {
+		t.Fatalf("file name should be invalid")
+	}
+}
+
+// 拆分脚本为单条语句
+func TestSplitStatements(t *testing.T) {
+	script := "-- comment\n\nCREATE TABLE `a` (\n  `id` int(11) NOT NULL\n) ENGINE=InnoDB;\r\n" +
+		"INSERT INTO `a` (`id`) VALUES\n(1),\n(2);\nDROP TABLE `b`"
+	statements := splitStatements(script)
+	if len(statements) != 3 {
+		t.Fatalf("statements: %q", statements)
+	}
+	if statements[0] != "CREATE TABLE `a` (\n  `id` int(11) NOT NULL\n) ENGINE=InnoDB" ||
+		statements[1] != "INSERT INTO `a` (`id`) VALUES\n(1),\n(2)" || statements[2] != "DROP TABLE `b`" {
+		t.Fatalf("statements: %q", statements)
+	}
+}
@@ -0,0 +1,29 @@
+-- 按照创建的逆序删除，保证外键约束
+
+DROP TABLE IF EXISTS `ratelimit_flux_rule_revision`;
+DROP TABLE IF EXISTS `ratelimit_flux_rule_config`;
+DROP TABLE IF EXISTS `mesh_resource_revision`;
+DROP TABLE IF EXISTS `mesh_resource`;
+DROP TABLE IF EXISTS `mesh_service_revision`;
+DROP TABLE IF EXISTS `mesh_service`;
+DROP TABLE IF EXISTS `mesh`;
+DROP TABLE IF EXISTS `cl5_module`;
+DROP TABLE IF EXISTS `start_lock`;
+DROP TABLE IF EXISTS `t_section`;
+DROP TABLE IF EXISTS `t_route`;
+DROP TABLE IF EXISTS `t_policy`;
+DROP TABLE IF EXISTS `t_ip_config`;
+DROP TABLE IF EXISTS `platform`;
+DROP TABLE IF EXISTS `circuitbreaker_rule_relation`;
+DROP TABLE IF EXISTS `circuitbreaker_rule`;
+DROP TABLE IF EXISTS `owner_service_map`;
+DROP TABLE IF EXISTS `service_metadata`;
+DROP TABLE IF EXISTS `service`;
+DROP TABLE IF EXISTS `ratelimit_revision`;
+DROP TABLE IF EXISTS `ratelimit_config`;
+DROP TABLE IF EXISTS `routing_config`;
+DROP TABLE IF EXISTS `namespace`;
+DROP TABLE IF EXISTS `instance_metadata`;
+DROP TABLE IF EXISTS `health_check`;
+DROP TABLE IF EXISTS `instance`;
+DROP TABLE IF EXISTS `business`;
@@ -0,0 +1,5 @@
+ALTER TABLE `health_check`
+  DROP COLUMN `probe_interval`,
+  DROP COLUMN `probe_timeout`,
+  DROP COLUMN `probe_path`,
+  DROP COLUMN `probe_expected_status`;
@@ -0,0 +1,7 @@
+-- 主动健康检查的探测参数
+
+ALTER TABLE `health_check`
+  ADD COLUMN `probe_interval` int(11) NOT NULL DEFAULT '0',
+  ADD COLUMN `probe_timeout` int(11) NOT NULL DEFAULT '0',
+  ADD COLUMN `probe_path` varchar(256) COLLATE utf8_bin DEFAULT NULL,
+  ADD COLUMN `probe_expected_status` int(11) NOT NULL DEFAULT '0';
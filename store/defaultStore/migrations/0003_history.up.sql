@@ -0,0 +1,19 @@
+-- 操作记录
+
+CREATE TABLE `history` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `resource_type` varchar(32) COLLATE utf8_bin NOT NULL,
+  `operation_type` varchar(32) COLLATE utf8_bin NOT NULL,
+  `namespace` varchar(64) COLLATE utf8_bin NOT NULL DEFAULT '',
+  `service` varchar(128) COLLATE utf8_bin NOT NULL DEFAULT '',
+  `context` text COLLATE utf8_bin,
+  `operator` varchar(128) COLLATE utf8_bin NOT NULL DEFAULT '',
+  `revision` varchar(40) COLLATE utf8_bin NOT NULL DEFAULT '',
+  `before_snapshot` mediumtext COLLATE utf8_bin,
+  `after_snapshot` mediumtext COLLATE utf8_bin,
+  `ctime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `ctime` (`ctime`),
+  KEY `service` (`namespace`,`service`),
+  KEY `operator` (`operator`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `role`;
+DROP TABLE IF EXISTS `user_group`;
+DROP TABLE IF EXISTS `user`;
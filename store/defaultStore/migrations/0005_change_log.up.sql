@@ -0,0 +1,10 @@
+-- 数据变更记录，开启changeLog时各个server通过该表感知其他server的写入
+
+CREATE TABLE `change_log` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `resource` varchar(32) COLLATE utf8_bin NOT NULL,
+  `server` varchar(64) COLLATE utf8_bin NOT NULL,
+  `ctime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `ctime` (`ctime`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
@@ -87,8 +87,8 @@ func (ns *namespaceStore) ListNamespaces(owner string) ([]*model.Namespace, erro
 		return nil, errors.New("store lst namespaces owner is empty")
 	}
 
-	str := genNamespaceSelectSQL() + " where owner like '%?%'"
-	rows, err := ns.db.Query(str, owner)
+	str := genNamespaceSelectSQL() + " where owner like ?"
+	rows, err := ns.db.Query(str, "%"+owner+"%")
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +82,10 @@ CREATE TABLE `health_check` (
   `id` varchar(40) COLLATE utf8_bin NOT NULL,
   `type` tinyint(4) NOT NULL DEFAULT '0',
   `ttl` int(11) NOT NULL,
+  `probe_interval` int(11) NOT NULL DEFAULT '0',
+  `probe_timeout` int(11) NOT NULL DEFAULT '0',
+  `probe_path` varchar(256) COLLATE utf8_bin DEFAULT NULL,
+  `probe_expected_status` int(11) NOT NULL DEFAULT '0',
   PRIMARY KEY (`id`),
   CONSTRAINT `health_check_ibfk_1` FOREIGN KEY (`id`) REFERENCES `instance` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
@@ -591,6 +595,100 @@ CREATE TABLE `ratelimit_flux_rule_revision` (
   PRIMARY KEY (`service_id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
 -- --------------------------------------------------------
+--
+-- 操作记录表的结构 `history`
+--
+CREATE TABLE `history` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `resource_type` varchar(32) COLLATE utf8_bin NOT NULL,
+  `operation_type` varchar(32) COLLATE utf8_bin NOT NULL,
+  `namespace` varchar(64) COLLATE utf8_bin NOT NULL DEFAULT '',
+  `service` varchar(128) COLLATE utf8_bin NOT NULL DEFAULT '',
+  `context` text COLLATE utf8_bin,
+  `operator` varchar(128) COLLATE utf8_bin NOT NULL DEFAULT '',
+  `revision` varchar(40) COLLATE utf8_bin NOT NULL DEFAULT '',
+  `before_snapshot` mediumtext COLLATE utf8_bin,
+  `after_snapshot` mediumtext COLLATE utf8_bin,
+  `ctime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `ctime` (`ctime`),
+  KEY `service` (`namespace`,`service`),
+  KEY `operator` (`operator`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
+-- --------------------------------------------------------
+--
+-- 用户表的结构 `user`
+--
+CREATE TABLE `user` (
+  `id` varchar(32) COLLATE utf8_bin NOT NULL,
+  `name` varchar(128) COLLATE utf8_bin NOT NULL,
+  `password` varchar(256) COLLATE utf8_bin NOT NULL,
+  `comment` varchar(1024) COLLATE utf8_bin DEFAULT NULL,
+  `ctime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `mtime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
+-- --------------------------------------------------------
+--
+-- 用户组表的结构 `user_group`，users为json格式的用户名列表
+--
+CREATE TABLE `user_group` (
+  `id` varchar(32) COLLATE utf8_bin NOT NULL,
+  `name` varchar(128) COLLATE utf8_bin NOT NULL,
+  `comment` varchar(1024) COLLATE utf8_bin DEFAULT NULL,
+  `users` text COLLATE utf8_bin,
+  `ctime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `mtime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
+-- --------------------------------------------------------
+--
+-- 角色表的结构 `role`，users、user_groups以及policies均为json格式
+--
+CREATE TABLE `role` (
+  `id` varchar(32) COLLATE utf8_bin NOT NULL,
+  `name` varchar(128) COLLATE utf8_bin NOT NULL,
+  `comment` varchar(1024) COLLATE utf8_bin DEFAULT NULL,
+  `users` text COLLATE utf8_bin,
+  `user_groups` text COLLATE utf8_bin,
+  `policies` mediumtext COLLATE utf8_bin,
+  `ctime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `mtime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
+-- --------------------------------------------------------
+--
+-- 数据变更记录表的结构 `change_log`，开启changeLog时各个server通过该表感知其他server的写入
+--
+CREATE TABLE `change_log` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `resource` varchar(32) COLLATE utf8_bin NOT NULL,
+  `server` varchar(64) COLLATE utf8_bin NOT NULL,
+  `ctime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `ctime` (`ctime`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
+-- --------------------------------------------------------
+--
+-- 数据库版本表的结构 `schema_version`，记录已经执行的migrations升级脚本
+--
+CREATE TABLE `schema_version` (
+  `version` int(11) NOT NULL,
+  `name` varchar(128) COLLATE utf8_bin NOT NULL,
+  `ctime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin;
+
+INSERT INTO `schema_version` (`version`, `name`) VALUES
+(1, 'init'),
+(2, 'health_check_probe'),
+(3, 'history'),
+(4, 'auth'),
+(5, 'change_log');
+-- --------------------------------------------------------
 /*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
 /*!40101 SET CHARACTER_SET_RESULTS=@OLD_CHARACTER_SET_RESULTS */;
 /*!40101 SET COLLATION_CONNECTION=@OLD_COLLATION_CONNECTION */;
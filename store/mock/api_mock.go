@@ -1338,6 +1338,236 @@ func (mr *MockStoreMockRecorder) GetPlatforms(query, offset, limit interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlatforms", reflect.TypeOf((*MockStore)(nil).GetPlatforms), query, offset, limit)
 }
 
+// AddHistory mocks base method
+func (m *MockStore) AddHistory(history *model.History) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddHistory", history)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddHistory indicates an expected call of AddHistory
+func (mr *MockStoreMockRecorder) AddHistory(history interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHistory", reflect.TypeOf((*MockStore)(nil).AddHistory), history)
+}
+
+// GetHistories mocks base method
+func (m *MockStore) GetHistories(filter map[string]string, start, end time.Time, offset, limit uint32) (uint32, []*model.History, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistories", filter, start, end, offset, limit)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].([]*model.History)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetHistories indicates an expected call of GetHistories
+func (mr *MockStoreMockRecorder) GetHistories(filter, start, end, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistories", reflect.TypeOf((*MockStore)(nil).GetHistories), filter, start, end, offset, limit)
+}
+
+// DeleteHistories mocks base method
+func (m *MockStore) DeleteHistories(before time.Time) (uint32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHistories", before)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteHistories indicates an expected call of DeleteHistories
+func (mr *MockStoreMockRecorder) DeleteHistories(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHistories", reflect.TypeOf((*MockStore)(nil).DeleteHistories), before)
+}
+
+// AddUser mocks base method
+func (m *MockStore) AddUser(user *model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUser", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUser indicates an expected call of AddUser
+func (mr *MockStoreMockRecorder) AddUser(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUser", reflect.TypeOf((*MockStore)(nil).AddUser), user)
+}
+
+// UpdateUser mocks base method
+func (m *MockStore) UpdateUser(user *model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUser indicates an expected call of UpdateUser
+func (mr *MockStoreMockRecorder) UpdateUser(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStore)(nil).UpdateUser), user)
+}
+
+// DeleteUser mocks base method
+func (m *MockStore) DeleteUser(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser
+func (mr *MockStoreMockRecorder) DeleteUser(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStore)(nil).DeleteUser), name)
+}
+
+// GetUsers mocks base method
+func (m *MockStore) GetUsers() ([]*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers")
+	ret0, _ := ret[0].([]*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers
+func (mr *MockStoreMockRecorder) GetUsers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockStore)(nil).GetUsers))
+}
+
+// AddUserGroup mocks base method
+func (m *MockStore) AddUserGroup(group *model.UserGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUserGroup", group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUserGroup indicates an expected call of AddUserGroup
+func (mr *MockStoreMockRecorder) AddUserGroup(group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserGroup", reflect.TypeOf((*MockStore)(nil).AddUserGroup), group)
+}
+
+// UpdateUserGroup mocks base method
+func (m *MockStore) UpdateUserGroup(group *model.UserGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserGroup", group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserGroup indicates an expected call of UpdateUserGroup
+func (mr *MockStoreMockRecorder) UpdateUserGroup(group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserGroup", reflect.TypeOf((*MockStore)(nil).UpdateUserGroup), group)
+}
+
+// DeleteUserGroup mocks base method
+func (m *MockStore) DeleteUserGroup(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserGroup", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserGroup indicates an expected call of DeleteUserGroup
+func (mr *MockStoreMockRecorder) DeleteUserGroup(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserGroup", reflect.TypeOf((*MockStore)(nil).DeleteUserGroup), name)
+}
+
+// GetUserGroups mocks base method
+func (m *MockStore) GetUserGroups() ([]*model.UserGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserGroups")
+	ret0, _ := ret[0].([]*model.UserGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserGroups indicates an expected call of GetUserGroups
+func (mr *MockStoreMockRecorder) GetUserGroups() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserGroups", reflect.TypeOf((*MockStore)(nil).GetUserGroups))
+}
+
+// AddRole mocks base method
+func (m *MockStore) AddRole(role *model.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRole", role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRole indicates an expected call of AddRole
+func (mr *MockStoreMockRecorder) AddRole(role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRole", reflect.TypeOf((*MockStore)(nil).AddRole), role)
+}
+
+// UpdateRole mocks base method
+func (m *MockStore) UpdateRole(role *model.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole
+func (mr *MockStoreMockRecorder) UpdateRole(role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockStore)(nil).UpdateRole), role)
+}
+
+// DeleteRole mocks base method
+func (m *MockStore) DeleteRole(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRole", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRole indicates an expected call of DeleteRole
+func (mr *MockStoreMockRecorder) DeleteRole(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockStore)(nil).DeleteRole), name)
+}
+
+// GetRoles mocks base method
+func (m *MockStore) GetRoles() ([]*model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoles")
+	ret0, _ := ret[0].([]*model.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoles indicates an expected call of GetRoles
+func (mr *MockStoreMockRecorder) GetRoles() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoles", reflect.TypeOf((*MockStore)(nil).GetRoles))
+}
+
+// WatchChanges mocks base method
+func (m *MockStore) WatchChanges(listener store.ChangeListener) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchChanges", listener)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// WatchChanges indicates an expected call of WatchChanges
+func (mr *MockStoreMockRecorder) WatchChanges(listener interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchChanges", reflect.TypeOf((*MockStore)(nil).WatchChanges), listener)
+}
+
 // MockNamespaceStore is a mock of NamespaceStore interface
 type MockNamespaceStore struct {
 	ctrl     *gomock.Controller
@@ -2791,6 +3021,305 @@ func (mr *MockPlatformStoreMockRecorder) GetPlatforms(query, offset, limit inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlatforms", reflect.TypeOf((*MockPlatformStore)(nil).GetPlatforms), query, offset, limit)
 }
 
+// MockHistoryStore is a mock of HistoryStore interface
+type MockHistoryStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryStoreMockRecorder
+}
+
+// MockHistoryStoreMockRecorder is the mock recorder for MockHistoryStore
+type MockHistoryStoreMockRecorder struct {
+	mock *MockHistoryStore
+}
+
+// NewMockHistoryStore creates a new mock instance
+func NewMockHistoryStore(ctrl *gomock.Controller) *MockHistoryStore {
+	mock := &MockHistoryStore{ctrl: ctrl}
+	mock.recorder = &MockHistoryStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockHistoryStore) EXPECT() *MockHistoryStoreMockRecorder {
+	return m.recorder
+}
+
+// AddHistory mocks base method
+func (m *MockHistoryStore) AddHistory(history *model.History) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddHistory", history)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddHistory indicates an expected call of AddHistory
+func (mr *MockHistoryStoreMockRecorder) AddHistory(history interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHistory", reflect.TypeOf((*MockHistoryStore)(nil).AddHistory), history)
+}
+
+// GetHistories mocks base method
+func (m *MockHistoryStore) GetHistories(filter map[string]string, start, end time.Time, offset, limit uint32) (uint32, []*model.History, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistories", filter, start, end, offset, limit)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].([]*model.History)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetHistories indicates an expected call of GetHistories
+func (mr *MockHistoryStoreMockRecorder) GetHistories(filter, start, end, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistories", reflect.TypeOf((*MockHistoryStore)(nil).GetHistories), filter, start, end, offset, limit)
+}
+
+// DeleteHistories mocks base method
+func (m *MockHistoryStore) DeleteHistories(before time.Time) (uint32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHistories", before)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteHistories indicates an expected call of DeleteHistories
+func (mr *MockHistoryStoreMockRecorder) DeleteHistories(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHistories", reflect.TypeOf((*MockHistoryStore)(nil).DeleteHistories), before)
+}
+
+// MockAuthStore is a mock of AuthStore interface
+type MockAuthStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthStoreMockRecorder
+}
+
+// MockAuthStoreMockRecorder is the mock recorder for MockAuthStore
+type MockAuthStoreMockRecorder struct {
+	mock *MockAuthStore
+}
+
+// NewMockAuthStore creates a new mock instance
+func NewMockAuthStore(ctrl *gomock.Controller) *MockAuthStore {
+	mock := &MockAuthStore{ctrl: ctrl}
+	mock.recorder = &MockAuthStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAuthStore) EXPECT() *MockAuthStoreMockRecorder {
+	return m.recorder
+}
+
+// AddUser mocks base method
+func (m *MockAuthStore) AddUser(user *model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUser", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUser indicates an expected call of AddUser
+func (mr *MockAuthStoreMockRecorder) AddUser(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUser", reflect.TypeOf((*MockAuthStore)(nil).AddUser), user)
+}
+
+// UpdateUser mocks base method
+func (m *MockAuthStore) UpdateUser(user *model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUser indicates an expected call of UpdateUser
+func (mr *MockAuthStoreMockRecorder) UpdateUser(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockAuthStore)(nil).UpdateUser), user)
+}
+
+// DeleteUser mocks base method
+func (m *MockAuthStore) DeleteUser(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser
+func (mr *MockAuthStoreMockRecorder) DeleteUser(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockAuthStore)(nil).DeleteUser), name)
+}
+
+// GetUsers mocks base method
+func (m *MockAuthStore) GetUsers() ([]*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers")
+	ret0, _ := ret[0].([]*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers
+func (mr *MockAuthStoreMockRecorder) GetUsers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockAuthStore)(nil).GetUsers))
+}
+
+// AddUserGroup mocks base method
+func (m *MockAuthStore) AddUserGroup(group *model.UserGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUserGroup", group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUserGroup indicates an expected call of AddUserGroup
+func (mr *MockAuthStoreMockRecorder) AddUserGroup(group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserGroup", reflect.TypeOf((*MockAuthStore)(nil).AddUserGroup), group)
+}
+
+// UpdateUserGroup mocks base method
+func (m *MockAuthStore) UpdateUserGroup(group *model.UserGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserGroup", group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserGroup indicates an expected call of UpdateUserGroup
+func (mr *MockAuthStoreMockRecorder) UpdateUserGroup(group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserGroup", reflect.TypeOf((*MockAuthStore)(nil).UpdateUserGroup), group)
+}
+
+// DeleteUserGroup mocks base method
+func (m *MockAuthStore) DeleteUserGroup(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserGroup", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserGroup indicates an expected call of DeleteUserGroup
+func (mr *MockAuthStoreMockRecorder) DeleteUserGroup(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserGroup", reflect.TypeOf((*MockAuthStore)(nil).DeleteUserGroup), name)
+}
+
+// GetUserGroups mocks base method
+func (m *MockAuthStore) GetUserGroups() ([]*model.UserGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserGroups")
+	ret0, _ := ret[0].([]*model.UserGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserGroups indicates an expected call of GetUserGroups
+func (mr *MockAuthStoreMockRecorder) GetUserGroups() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserGroups", reflect.TypeOf((*MockAuthStore)(nil).GetUserGroups))
+}
+
+// AddRole mocks base method
+func (m *MockAuthStore) AddRole(role *model.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRole", role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRole indicates an expected call of AddRole
+func (mr *MockAuthStoreMockRecorder) AddRole(role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRole", reflect.TypeOf((*MockAuthStore)(nil).AddRole), role)
+}
+
+// UpdateRole mocks base method
+func (m *MockAuthStore) UpdateRole(role *model.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole
+func (mr *MockAuthStoreMockRecorder) UpdateRole(role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockAuthStore)(nil).UpdateRole), role)
+}
+
+// DeleteRole mocks base method
+func (m *MockAuthStore) DeleteRole(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRole", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRole indicates an expected call of DeleteRole
+func (mr *MockAuthStoreMockRecorder) DeleteRole(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockAuthStore)(nil).DeleteRole), name)
+}
+
+// GetRoles mocks base method
+func (m *MockAuthStore) GetRoles() ([]*model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoles")
+	ret0, _ := ret[0].([]*model.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoles indicates an expected call of GetRoles
+func (mr *MockAuthStoreMockRecorder) GetRoles() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoles", reflect.TypeOf((*MockAuthStore)(nil).GetRoles))
+}
+
+// MockChangeNotifyStore is a mock of ChangeNotifyStore interface
+type MockChangeNotifyStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockChangeNotifyStoreMockRecorder
+}
+
+// MockChangeNotifyStoreMockRecorder is the mock recorder for MockChangeNotifyStore
+type MockChangeNotifyStoreMockRecorder struct {
+	mock *MockChangeNotifyStore
+}
+
+// NewMockChangeNotifyStore creates a new mock instance
+func NewMockChangeNotifyStore(ctrl *gomock.Controller) *MockChangeNotifyStore {
+	mock := &MockChangeNotifyStore{ctrl: ctrl}
+	mock.recorder = &MockChangeNotifyStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockChangeNotifyStore) EXPECT() *MockChangeNotifyStoreMockRecorder {
+	return m.recorder
+}
+
+// WatchChanges mocks base method
+func (m *MockChangeNotifyStore) WatchChanges(listener store.ChangeListener) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchChanges", listener)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// WatchChanges indicates an expected call of WatchChanges
+func (mr *MockChangeNotifyStoreMockRecorder) WatchChanges(listener interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchChanges", reflect.TypeOf((*MockChangeNotifyStore)(nil).WatchChanges), listener)
+}
+
 // MockTransaction is a mock of Transaction interface
 type MockTransaction struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,87 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package store
+
+import (
+	"sync"
+)
+
+/**
+ * ChangeResource 发生变更的资源类型，与缓存的资源一一对应
+ */
+type ChangeResource string
+
+const (
+	// ChangeService 服务以及服务元数据、别名
+	ChangeService ChangeResource = "service"
+	// ChangeInstance 实例以及实例的健康检查、元数据
+	ChangeInstance ChangeResource = "instance"
+	// ChangeRoutingConfig 路由配置
+	ChangeRoutingConfig ChangeResource = "routingConfig"
+	// ChangeRateLimit 限流规则
+	ChangeRateLimit ChangeResource = "rateLimit"
+	// ChangeCircuitBreaker 熔断规则
+	ChangeCircuitBreaker ChangeResource = "circuitBreaker"
+	// ChangeL5 l5的路由、策略以及分段数据
+	ChangeL5 ChangeResource = "l5"
+)
+
+/**
+ * ChangeListener 数据变更的回调
+ * 在写入数据的协程中同步调用，实现方不能阻塞
+ */
+type ChangeListener func(resource ChangeResource)
+
+/**
+ * ChangeNotifyStore 数据变更通知接口
+ */
+type ChangeNotifyStore interface {
+	// 订阅数据变更，返回值表示是否能够收到所有server的写入通知
+	// 返回false时，只能收到本server的写入，调用方仍然需要按照原有的间隔轮询
+	WatchChanges(listener ChangeListener) bool
+}
+
+/**
+ * ChangeNotifier 数据变更的分发，供存储插件复用
+ */
+type ChangeNotifier struct {
+	mutex     sync.RWMutex
+	listeners []ChangeListener
+}
+
+/**
+ * AddListener 增加一个订阅者
+ */
+func (n *ChangeNotifier) AddListener(listener ChangeListener) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.listeners = append(n.listeners, listener)
+}
+
+/**
+ * Notify 通知所有的订阅者
+ */
+func (n *ChangeNotifier) Notify(resources ...ChangeResource) {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	for _, resource := range resources {
+		for _, listener := range n.listeners {
+			listener(resource)
+		}
+	}
+}
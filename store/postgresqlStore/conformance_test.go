@@ -0,0 +1,44 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package postgresqlStore
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-server/store"
+	"github.com/polarismesh/polaris-server/store/conformance"
+)
+
+// 一致性测试使用的数据库，格式为user:pwd@addr/dbname，需要预先导入polaris_server.sql
+const conformanceEnv = "POLARIS_TEST_POSTGRESQL"
+
+// TestConformance 一致性测试集，未配置数据库时跳过
+func TestConformance(t *testing.T) {
+	master := conformance.LoadDSN(t, conformanceEnv)
+
+	suite := &conformance.Suite{
+		Factory: func(t *testing.T) store.Store {
+			s := &stableStore{}
+			if err := s.Initialize(&store.Config{Option: map[string]interface{}{"master": master}}); err != nil {
+				t.Fatalf("initialize PostgreSQL store err: %s", err.Error())
+			}
+			return s
+		},
+	}
+	suite.Run(t)
+}
@@ -0,0 +1,177 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package postgresqlStore
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/common/model"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+// 操作记录查询条件与字段的对应关系
+var historyFilter2Column = map[string]string{
+	"resource":  "resource_type",
+	"operation": "operation_type",
+	"namespace": "namespace",
+	"service":   "service",
+	"operator":  "operator",
+}
+
+/**
+ * @brief historyStore的实现
+ */
+type historyStore struct {
+	master *BaseDB
+	slave  *BaseDB
+}
+
+/**
+ * @brief 新增操作记录
+ */
+func (h *historyStore) AddHistory(history *model.History) error {
+	str := `insert into history (resource_type, operation_type, namespace, service, context, operator, revision,
+			before_snapshot, after_snapshot, ctime) values(?,?,?,?,?,?,?,?,?,?) returning id`
+	// postgresql不支持LastInsertId，通过returning获取自增id
+	err := h.master.QueryRow(str, history.ResourceType, history.OperationType, history.Namespace,
+		history.Service, history.Context, history.Operator, history.Revision, history.Before, history.After,
+		time2String(history.CreateTime)).Scan(&history.ID)
+	if err != nil {
+		log.Errorf("[Store][history] add history(%s, %s) err: %s",
+			history.ResourceType, history.OperationType, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+/**
+ * @brief 根据过滤条件查询操作记录及总数
+ */
+func (h *historyStore) GetHistories(filter map[string]string, start, end time.Time, offset, limit uint32) (
+	uint32, []*model.History, error) {
+	whereStr, args, err := genHistoryWhereSQL(filter, start, end)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	num, err := queryEntryCount(h.slave, "select count(*) from history "+whereStr, args)
+	if err != nil {
+		return 0, nil, err
+	}
+	if limit == 0 {
+		return num, make([]*model.History, 0), nil
+	}
+
+	str := genSelectHistorySQL() + whereStr
+	opStr, opArgs := genOrderAndPage(&Order{"id", "desc"}, &Page{offset, limit})
+	rows, err := h.slave.Query(str+opStr, append(args, opArgs...)...)
+	if err != nil {
+		log.Errorf("[Store][history] get histories by filter query(%s) err: %s", str, err.Error())
+		return 0, nil, err
+	}
+	out, err := fetchHistoryRows(rows)
+	if err != nil {
+		return 0, nil, err
+	}
+	return num, out, nil
+}
+
+/**
+ * @brief 删除指定时间之前的操作记录
+ */
+func (h *historyStore) DeleteHistories(before time.Time) (uint32, error) {
+	str := `delete from history where ctime < ?`
+	result, err := h.master.Exec(str, time2String(before))
+	if err != nil {
+		log.Errorf("[Store][history] delete histories before(%s) err: %s", time2String(before), err.Error())
+		return 0, store.Error(err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, store.Error(err)
+	}
+	return uint32(count), nil
+}
+
+/**
+ * @brief 生成操作记录查询的where语句
+ */
+func genHistoryWhereSQL(filter map[string]string, start, end time.Time) (string, []interface{}, error) {
+	var conds []string
+	var args []interface{}
+	for key, value := range filter {
+		column, ok := historyFilter2Column[key]
+		if !ok {
+			return "", nil, fmt.Errorf("history filter(%s) is not allowed", key)
+		}
+		conds = append(conds, column+" = ?")
+		args = append(args, value)
+	}
+	if !start.IsZero() {
+		conds = append(conds, "ctime >= ?")
+		args = append(args, time2String(start))
+	}
+	if !end.IsZero() {
+		conds = append(conds, "ctime <= ?")
+		args = append(args, time2String(end))
+	}
+
+	if len(conds) == 0 {
+		return "", args, nil
+	}
+	return " where " + strings.Join(conds, And+" "), args, nil
+}
+
+/**
+ * @brief 读取操作记录数据
+ */
+func fetchHistoryRows(rows *sql.Rows) ([]*model.History, error) {
+	defer rows.Close()
+	var out []*model.History
+	for rows.Next() {
+		var history model.History
+		var ctime int64
+		err := rows.Scan(&history.ID, &history.ResourceType, &history.OperationType, &history.Namespace,
+			&history.Service, &history.Context, &history.Operator, &history.Revision, &history.Before,
+			&history.After, &ctime)
+		if err != nil {
+			log.Errorf("[Store][history] fetch history scan err: %s", err.Error())
+			return nil, err
+		}
+		history.CreateTime = time.Unix(ctime, 0)
+		out = append(out, &history)
+	}
+	if err := rows.Err(); err != nil {
+		log.Errorf("[Store][history] fetch history next err: %s", err.Error())
+		return nil, err
+	}
+	return out, nil
+}
+
+/**
+ * @brief 查询操作记录sql
+ */
+func genSelectHistorySQL() string {
+	str := `select id, resource_type, operation_type, namespace, service, COALESCE(context, ''), operator, revision,
+			COALESCE(before_snapshot, ''), COALESCE(after_snapshot, ''), extract(epoch from ctime)::bigint from history `
+	return str
+}
@@ -0,0 +1,218 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package postgresqlStore
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/polarismesh/polaris-server/common/log"
+	"github.com/polarismesh/polaris-server/store"
+)
+
+const (
+	// 数据变更通知的channel
+	changeChannel = "polaris_change"
+	// 监听连接断开之后的重连间隔
+	minReconnectInterval = time.Second
+	maxReconnectInterval = 30 * time.Second
+	// 长时间没有通知时，ping一下监听连接
+	listenerPingInterval = time.Minute
+)
+
+// 写入语句的表名
+var writeTableRegexp = regexp.MustCompile(
+	"(?i)^\\s*(?:insert\\s+into|update|delete\\s+from)\\s+\"?(\\w+)")
+
+// 表与缓存资源的对应关系，写入这些表后通知缓存更新
+var table2ChangeResource = map[string]store.ChangeResource{
+	"service":                      store.ChangeService,
+	"service_metadata":             store.ChangeService,
+	"owner_service_map":            store.ChangeService,
+	"instance":                     store.ChangeInstance,
+	"instance_metadata":            store.ChangeInstance,
+	"health_check":                 store.ChangeInstance,
+	"routing_config":               store.ChangeRoutingConfig,
+	"ratelimit_config":             store.ChangeRateLimit,
+	"ratelimit_revision":           store.ChangeRateLimit,
+	"circuitbreaker_rule":          store.ChangeCircuitBreaker,
+	"circuitbreaker_rule_relation": store.ChangeCircuitBreaker,
+	"t_route":                      store.ChangeL5,
+	"t_policy":                     store.ChangeL5,
+	"t_section":                    store.ChangeL5,
+	"t_ip_config":                  store.ChangeL5,
+}
+
+// 所有的缓存资源，监听连接重连之后全部通知一次，避免丢失断开期间的变更
+var allChangeResources = []store.ChangeResource{
+	store.ChangeService,
+	store.ChangeInstance,
+	store.ChangeRoutingConfig,
+	store.ChangeRateLimit,
+	store.ChangeCircuitBreaker,
+	store.ChangeL5,
+}
+
+/**
+ * @brief 解析写入语句对应的缓存资源
+ */
+func parseChangeResource(query string) (store.ChangeResource, bool) {
+	match := writeTableRegexp.FindStringSubmatch(query)
+	if match == nil {
+		return "", false
+	}
+	resource, ok := table2ChangeResource[strings.ToLower(match[1])]
+	return resource, ok
+}
+
+/**
+ * @brief 数据变更通知
+ * 本server的写入直接通知，同时通过pg_notify广播，其他server通过LISTEN收到变更
+ * 通知的内容为"server:resource"
+ */
+type changeNotifier struct {
+	store.ChangeNotifier
+
+	db  *BaseDB
+	cfg *dbConfig
+	// 本server的标识，跳过本server发出的通知
+	server string
+	once   sync.Once
+	cancel context.CancelFunc
+}
+
+/**
+ * @brief 创建数据变更通知
+ */
+func newChangeNotifier(db *BaseDB, cfg *dbConfig) *changeNotifier {
+	return &changeNotifier{
+		db:     db,
+		cfg:    cfg,
+		server: strings.Replace(uuid.New().String(), "-", "", -1),
+	}
+}
+
+/**
+ * @brief 写入成功之后的回调
+ */
+func (c *changeNotifier) changed(resources ...store.ChangeResource) {
+	resources = uniqueChangeResources(resources)
+	c.Notify(resources...)
+
+	for _, resource := range resources {
+		// pg_notify在事务之外执行，通知立即发出
+		if _, err := c.db.Exec("select pg_notify(?, ?)", changeChannel, c.payload(resource)); err != nil {
+			// 通知失败时，其他server依赖定期的全量轮询
+			log.Errorf("[Store][postgresql] notify change(%s) err: %s", resource, err.Error())
+		}
+	}
+}
+
+// 生成通知的内容
+func (c *changeNotifier) payload(resource store.ChangeResource) string {
+	return c.server + ":" + string(resource)
+}
+
+// 解析通知的内容，返回资源以及是否为其他server发出的通知
+func (c *changeNotifier) parsePayload(payload string) (store.ChangeResource, bool) {
+	idx := strings.IndexByte(payload, ':')
+	if idx < 0 || payload[:idx] == c.server {
+		return "", false
+	}
+	return store.ChangeResource(payload[idx+1:]), true
+}
+
+/**
+ * @brief 启动监听协程，只启动一次
+ */
+func (c *changeNotifier) start() {
+	c.once.Do(func() {
+		listener := pq.NewListener(c.cfg.dsn(), minReconnectInterval, maxReconnectInterval,
+			func(event pq.ListenerEventType, err error) {
+				if err != nil {
+					log.Errorf("[Store][postgresql] change listener event(%d) err: %s", event, err.Error())
+				}
+			})
+		if err := listener.Listen(changeChannel); err != nil {
+			// 连接失败时由pq在后台重连，重连成功之后自动执行LISTEN
+			log.Errorf("[Store][postgresql] listen channel(%s) err: %s", changeChannel, err.Error())
+		}
+
+		var ctx context.Context
+		ctx, c.cancel = context.WithCancel(context.Background())
+		go c.run(ctx, listener)
+	})
+}
+
+/**
+ * @brief 停止监听协程
+ */
+func (c *changeNotifier) stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// 接收其他server的变更通知
+func (c *changeNotifier) run(ctx context.Context, listener *pq.Listener) {
+	defer listener.Close()
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case notification := <-listener.Notify:
+			if notification == nil {
+				// 连接重建，断开期间的通知已经丢失
+				log.Infof("[Store][postgresql] change listener reconnected")
+				c.Notify(allChangeResources...)
+				continue
+			}
+			if resource, ok := c.parsePayload(notification.Extra); ok {
+				c.Notify(resource)
+			}
+		case <-ticker.C:
+			go func() {
+				_ = listener.Ping()
+			}()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// 去掉重复的资源
+func uniqueChangeResources(resources []store.ChangeResource) []store.ChangeResource {
+	if len(resources) <= 1 {
+		return resources
+	}
+	exists := make(map[store.ChangeResource]bool, len(resources))
+	out := make([]store.ChangeResource, 0, len(resources))
+	for _, resource := range resources {
+		if !exists[resource] {
+			exists[resource] = true
+			out = append(out, resource)
+		}
+	}
+	return out
+}
@@ -0,0 +1,514 @@
+--
+-- Database: polaris_server，postgresql版本的表结构
+-- 创建数据库：CREATE DATABASE polaris_server ENCODING 'UTF8';
+-- 导入表结构：psql -d polaris_server -f polaris_server.sql
+--
+
+--
+-- mtime字段的自动更新，相当于mysql的ON UPDATE CURRENT_TIMESTAMP
+-- 语句中显式修改了mtime时，保留修改后的值
+--
+CREATE OR REPLACE FUNCTION update_mtime() RETURNS trigger AS $$
+BEGIN
+  IF NEW.mtime IS NOT DISTINCT FROM OLD.mtime THEN
+    NEW.mtime := now();
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 business
+--
+CREATE TABLE business (
+  id varchar(32) NOT NULL,
+  name varchar(64) NOT NULL,
+  token varchar(64) NOT NULL,
+  owner varchar(1024) NOT NULL,
+  flag smallint NOT NULL DEFAULT 0,
+  ctime timestamptz NOT NULL DEFAULT now(),
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (id)
+);
+CREATE TRIGGER business_mtime BEFORE UPDATE ON business FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 instance
+--
+CREATE TABLE instance (
+  id varchar(40) NOT NULL,
+  service_id varchar(32) NOT NULL,
+  vpc_id varchar(64) DEFAULT NULL,
+  host varchar(128) NOT NULL,
+  port integer NOT NULL,
+  protocol varchar(32) DEFAULT NULL,
+  version varchar(32) DEFAULT NULL,
+  health_status smallint NOT NULL DEFAULT 1,
+  isolate smallint NOT NULL DEFAULT 0,
+  weight smallint NOT NULL DEFAULT 100,
+  enable_health_check smallint NOT NULL DEFAULT 0,
+  logic_set varchar(128) DEFAULT NULL,
+  cmdb_region varchar(128) DEFAULT NULL,
+  cmdb_zone varchar(128) DEFAULT NULL,
+  cmdb_idc varchar(128) DEFAULT NULL,
+  priority smallint NOT NULL DEFAULT 0,
+  revision varchar(32) NOT NULL,
+  flag smallint NOT NULL DEFAULT 0,
+  ctime timestamptz NOT NULL DEFAULT now(),
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (id)
+);
+CREATE INDEX instance_service_id ON instance (service_id);
+CREATE INDEX instance_mtime ON instance (mtime);
+CREATE INDEX instance_host ON instance (host);
+CREATE TRIGGER instance_mtime BEFORE UPDATE ON instance FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 health_check
+--
+CREATE TABLE health_check (
+  id varchar(40) NOT NULL,
+  type smallint NOT NULL DEFAULT 0,
+  ttl integer NOT NULL,
+  probe_interval integer NOT NULL DEFAULT 0,
+  probe_timeout integer NOT NULL DEFAULT 0,
+  probe_path varchar(256) DEFAULT NULL,
+  probe_expected_status integer NOT NULL DEFAULT 0,
+  PRIMARY KEY (id),
+  CONSTRAINT health_check_ibfk_1 FOREIGN KEY (id) REFERENCES instance (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 instance_metadata
+--
+CREATE TABLE instance_metadata (
+  id varchar(40) NOT NULL,
+  mkey varchar(128) NOT NULL,
+  mvalue varchar(4096) NOT NULL,
+  ctime timestamptz NOT NULL DEFAULT now(),
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (id, mkey),
+  CONSTRAINT instance_metadata_ibfk_1 FOREIGN KEY (id) REFERENCES instance (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX instance_metadata_mkey ON instance_metadata (mkey);
+CREATE TRIGGER instance_metadata_mtime BEFORE UPDATE ON instance_metadata
+  FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 namespace
+--
+CREATE TABLE namespace (
+  name varchar(64) NOT NULL,
+  comment varchar(1024) DEFAULT NULL,
+  token varchar(64) NOT NULL,
+  owner varchar(1024) NOT NULL,
+  flag smallint NOT NULL DEFAULT 0,
+  ctime timestamptz NOT NULL DEFAULT now(),
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (name)
+);
+CREATE TRIGGER namespace_mtime BEFORE UPDATE ON namespace FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+--
+-- 转存表中的数据 namespace
+--
+INSERT INTO namespace (name, comment, token, owner, flag, ctime, mtime) VALUES
+('Polaris', 'Polaris-server', '2d1bfe5d12e04d54b8ee69e62494c7fd', 'polaris', 0, '2019-09-06 07:55:07', '2019-09-06 07:55:07'),
+('default', 'Default Environment', 'e2e473081d3d4306b52264e49f7ce227', 'polaris', 0, '2021-07-27 19:37:37', '2021-07-27 19:37:37');
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 routing_config
+--
+CREATE TABLE routing_config (
+  id varchar(32) NOT NULL,
+  in_bounds text,
+  out_bounds text,
+  revision varchar(40) NOT NULL,
+  flag smallint NOT NULL DEFAULT 0,
+  ctime timestamptz NOT NULL DEFAULT now(),
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (id)
+);
+CREATE INDEX routing_config_mtime ON routing_config (mtime);
+CREATE TRIGGER routing_config_mtime BEFORE UPDATE ON routing_config FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 ratelimit_config
+--
+CREATE TABLE ratelimit_config (
+  id varchar(32) NOT NULL,
+  service_id varchar(32) NOT NULL,
+  cluster_id varchar(32) NOT NULL,
+  labels text NOT NULL,
+  priority smallint NOT NULL DEFAULT 0,
+  rule text NOT NULL,
+  revision varchar(32) NOT NULL,
+  flag smallint NOT NULL DEFAULT 0,
+  ctime timestamptz NOT NULL DEFAULT now(),
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (id)
+);
+CREATE INDEX ratelimit_config_mtime ON ratelimit_config (mtime);
+CREATE INDEX ratelimit_config_service_id ON ratelimit_config (service_id);
+CREATE TRIGGER ratelimit_config_mtime BEFORE UPDATE ON ratelimit_config
+  FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 ratelimit_revision
+--
+CREATE TABLE ratelimit_revision (
+  service_id varchar(32) NOT NULL,
+  last_revision varchar(40) NOT NULL,
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (service_id)
+);
+CREATE INDEX ratelimit_revision_mtime ON ratelimit_revision (mtime);
+CREATE TRIGGER ratelimit_revision_mtime BEFORE UPDATE ON ratelimit_revision
+  FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 service
+--
+CREATE TABLE service (
+  id varchar(32) NOT NULL,
+  name varchar(128) NOT NULL,
+  namespace varchar(64) NOT NULL,
+  ports varchar(8192) DEFAULT NULL,
+  business varchar(64) DEFAULT NULL,
+  department varchar(1024) DEFAULT NULL,
+  cmdb_mod1 varchar(1024) DEFAULT NULL,
+  cmdb_mod2 varchar(1024) DEFAULT NULL,
+  cmdb_mod3 varchar(1024) DEFAULT NULL,
+  comment varchar(1024) DEFAULT NULL,
+  token varchar(2048) NOT NULL,
+  revision varchar(32) NOT NULL,
+  owner varchar(1024) NOT NULL,
+  flag smallint NOT NULL DEFAULT 0,
+  reference varchar(32) DEFAULT NULL,
+  refer_filter varchar(1024) DEFAULT NULL,
+  platform_id varchar(32) DEFAULT '',
+  ctime timestamptz NOT NULL DEFAULT now(),
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (id),
+  CONSTRAINT service_name UNIQUE (name, namespace)
+);
+CREATE INDEX service_namespace ON service (namespace);
+CREATE INDEX service_mtime ON service (mtime);
+CREATE INDEX service_reference ON service (reference);
+CREATE INDEX service_platform_id ON service (platform_id);
+CREATE TRIGGER service_mtime BEFORE UPDATE ON service FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+--
+-- 转存表中的数据 service
+--
+INSERT INTO service (id, name, namespace, comment, business, token, revision, owner, flag, ctime, mtime) VALUES
+('5d0e7ed4c771483a88541529333127c0', 'polaris.discover', 'Polaris', 'polaris discover service', 'polaris', '40c3acb5dbc6472982fbe91b011eddc0', '1c9b5059adf04f709370a827d3fa5290', 'polaris', 0, '2021-09-06 07:55:07', '2021-09-06 07:55:07'),
+('fe7cac0a2fb84fce85940125c9c5a32f', 'polaris.healthcheck', 'Polaris', 'polaris healthcheck service', 'polaris', '40c3acb5dbc6472982fbe91b011eddc0', '3fa6ef85feba48a48c6acc091a1d8e9e', 'polaris', 0, '2021-09-06 07:55:07', '2021-09-06 07:55:09'),
+('3987abdde91e4f19b482b40a2c5e66bd', 'polaris.redis', 'Polaris', 'polaris redis service', 'polaris', '1d622250c8cd49e996522bdf16b4eaf8', 'e0d3293b5f3749818358033f1c879a62', 'polaris', 0, '2021-09-06 07:55:07', '2021-09-06 07:55:10'),
+('bbfdda174ea64e11ac862adf14593c03', 'polaris.monitor', 'Polaris', 'polaris monitor service', 'polaris', '50b4e7d8affa4634b52523d398d1a369', '3649b17283d94d7baee5fb5d8160a225', 'polaris', 0, '2021-09-06 07:55:07', '2021-09-06 07:55:11');
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 service_metadata
+--
+CREATE TABLE service_metadata (
+  id varchar(32) NOT NULL,
+  mkey varchar(128) NOT NULL,
+  mvalue varchar(4096) NOT NULL,
+  ctime timestamptz NOT NULL DEFAULT now(),
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (id, mkey),
+  CONSTRAINT service_metadata_ibfk_1 FOREIGN KEY (id) REFERENCES service (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX service_metadata_mkey ON service_metadata (mkey);
+CREATE TRIGGER service_metadata_mtime BEFORE UPDATE ON service_metadata
+  FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 owner_service_map
+--
+CREATE TABLE owner_service_map (
+  id varchar(32) NOT NULL,
+  owner varchar(32) NOT NULL,
+  service varchar(128) NOT NULL,
+  namespace varchar(64) NOT NULL,
+  PRIMARY KEY (id)
+);
+CREATE INDEX owner_service_map_owner ON owner_service_map (owner);
+CREATE INDEX owner_service_map_name ON owner_service_map (service, namespace);
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 circuitbreaker_rule
+--
+CREATE TABLE circuitbreaker_rule (
+  id varchar(97) NOT NULL,
+  version varchar(32) NOT NULL DEFAULT 'master',
+  name varchar(32) NOT NULL,
+  namespace varchar(64) NOT NULL,
+  business varchar(64) DEFAULT NULL,
+  department varchar(1024) DEFAULT NULL,
+  comment varchar(1024) DEFAULT NULL,
+  inbounds text NOT NULL,
+  outbounds text NOT NULL,
+  token varchar(32) NOT NULL,
+  owner varchar(1024) NOT NULL,
+  revision varchar(32) NOT NULL,
+  flag smallint NOT NULL DEFAULT 0,
+  ctime timestamptz NOT NULL DEFAULT now(),
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (id, version),
+  CONSTRAINT circuitbreaker_rule_name UNIQUE (name, namespace, version)
+);
+CREATE INDEX circuitbreaker_rule_mtime ON circuitbreaker_rule (mtime);
+CREATE TRIGGER circuitbreaker_rule_mtime BEFORE UPDATE ON circuitbreaker_rule
+  FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 circuitbreaker_rule_relation
+--
+CREATE TABLE circuitbreaker_rule_relation (
+  service_id varchar(32) NOT NULL,
+  rule_id varchar(97) NOT NULL,
+  rule_version varchar(32) NOT NULL,
+  flag smallint NOT NULL DEFAULT 0,
+  ctime timestamptz NOT NULL DEFAULT now(),
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (service_id),
+  CONSTRAINT circuitbreaker_rule_relation_ibfk_1 FOREIGN KEY (service_id) REFERENCES service (id)
+    ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX circuitbreaker_rule_relation_mtime ON circuitbreaker_rule_relation (mtime);
+CREATE INDEX circuitbreaker_rule_relation_rule_id ON circuitbreaker_rule_relation (rule_id);
+CREATE TRIGGER circuitbreaker_rule_relation_mtime BEFORE UPDATE ON circuitbreaker_rule_relation
+  FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 platform
+--
+CREATE TABLE platform (
+  id varchar(32) NOT NULL,
+  name varchar(128) NOT NULL,
+  domain varchar(1024) NOT NULL,
+  qps smallint NOT NULL,
+  token varchar(32) NOT NULL,
+  owner varchar(1024) NOT NULL,
+  department varchar(1024) DEFAULT NULL,
+  comment varchar(1024) DEFAULT NULL,
+  flag smallint NOT NULL DEFAULT 0,
+  ctime timestamptz NOT NULL DEFAULT now(),
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (id)
+);
+CREATE INDEX platform_mtime ON platform (mtime);
+CREATE TRIGGER platform_mtime BEFORE UPDATE ON platform FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 t_ip_config，l5相关的表，字段名不加引号，统一为小写
+--
+CREATE TABLE t_ip_config (
+  Fip bigint NOT NULL,
+  FareaId bigint NOT NULL,
+  FcityId bigint NOT NULL,
+  FidcId bigint NOT NULL,
+  Fflag smallint DEFAULT 0,
+  Fstamp timestamp NOT NULL,
+  Fflow bigint NOT NULL,
+  PRIMARY KEY (Fip)
+);
+CREATE INDEX t_ip_config_fflow ON t_ip_config (Fflow);
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 t_policy
+--
+CREATE TABLE t_policy (
+  FmodId bigint NOT NULL,
+  Fdiv bigint NOT NULL,
+  Fmod bigint NOT NULL,
+  Fflag smallint DEFAULT 0,
+  Fstamp timestamp NOT NULL,
+  Fflow bigint NOT NULL,
+  PRIMARY KEY (FmodId)
+);
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 t_route
+--
+CREATE TABLE t_route (
+  Fip bigint NOT NULL,
+  FmodId bigint NOT NULL,
+  FcmdId bigint NOT NULL,
+  FsetId varchar(32) NOT NULL,
+  Fflag smallint DEFAULT 0,
+  Fstamp timestamp NOT NULL,
+  Fflow bigint NOT NULL,
+  PRIMARY KEY (Fip, FmodId, FcmdId)
+);
+CREATE INDEX t_route_fflow ON t_route (Fflow);
+CREATE INDEX t_route_idx1 ON t_route (FmodId, FcmdId, FsetId);
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 t_section
+--
+CREATE TABLE t_section (
+  FmodId bigint NOT NULL,
+  Ffrom bigint NOT NULL,
+  Fto bigint NOT NULL,
+  Fxid bigint NOT NULL,
+  Fflag smallint DEFAULT 0,
+  Fstamp timestamp NOT NULL,
+  Fflow bigint NOT NULL,
+  PRIMARY KEY (FmodId, Ffrom, Fto)
+);
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 start_lock
+--
+CREATE TABLE start_lock (
+  lock_id integer NOT NULL,
+  lock_key varchar(32) NOT NULL,
+  server varchar(32) NOT NULL,
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (lock_id, lock_key)
+);
+COMMENT ON COLUMN start_lock.lock_id IS '锁序号';
+COMMENT ON COLUMN start_lock.lock_key IS '锁的名字';
+COMMENT ON COLUMN start_lock.server IS '持有启动锁的Server';
+CREATE TRIGGER start_lock_mtime BEFORE UPDATE ON start_lock FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+--
+-- 转存表中的数据 start_lock
+--
+INSERT INTO start_lock (lock_id, lock_key, server, mtime) VALUES
+(1, 'sz', 'aaa', '2019-12-05 08:35:49');
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 cl5_module，用以生成sid
+--
+CREATE TABLE cl5_module (
+  module_id integer NOT NULL,
+  interface_id integer NOT NULL,
+  range_num integer NOT NULL,
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (module_id)
+);
+CREATE TRIGGER cl5_module_mtime BEFORE UPDATE ON cl5_module FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+--
+-- 转存表中的数据 cl5_module
+--
+INSERT INTO cl5_module (module_id, interface_id, range_num) VALUES (3000001, 1, 0);
+
+-- --------------------------------------------------------
+
+--
+-- 操作记录表的结构 history
+--
+CREATE TABLE history (
+  id bigserial NOT NULL,
+  resource_type varchar(32) NOT NULL,
+  operation_type varchar(32) NOT NULL,
+  namespace varchar(64) NOT NULL DEFAULT '',
+  service varchar(128) NOT NULL DEFAULT '',
+  context text,
+  operator varchar(128) NOT NULL DEFAULT '',
+  revision varchar(40) NOT NULL DEFAULT '',
+  before_snapshot text,
+  after_snapshot text,
+  ctime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (id)
+);
+CREATE INDEX history_ctime ON history (ctime);
+CREATE INDEX history_service ON history (namespace, service);
+CREATE INDEX history_operator ON history (operator);
+
+-- --------------------------------------------------------
+
+--
+-- 用户表的结构 "user"，user为postgresql的保留字，需要加引号
+--
+CREATE TABLE "user" (
+  id varchar(32) NOT NULL,
+  name varchar(128) NOT NULL,
+  password varchar(256) NOT NULL,
+  comment varchar(1024) DEFAULT NULL,
+  ctime timestamptz NOT NULL DEFAULT now(),
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (id),
+  CONSTRAINT user_name UNIQUE (name)
+);
+CREATE TRIGGER user_mtime BEFORE UPDATE ON "user" FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+-- --------------------------------------------------------
+
+--
+-- 用户组表的结构 user_group，users为json格式的用户名列表
+--
+CREATE TABLE user_group (
+  id varchar(32) NOT NULL,
+  name varchar(128) NOT NULL,
+  comment varchar(1024) DEFAULT NULL,
+  users text,
+  ctime timestamptz NOT NULL DEFAULT now(),
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (id),
+  CONSTRAINT user_group_name UNIQUE (name)
+);
+CREATE TRIGGER user_group_mtime BEFORE UPDATE ON user_group FOR EACH ROW EXECUTE PROCEDURE update_mtime();
+
+-- --------------------------------------------------------
+
+--
+-- 角色表的结构 role，users、user_groups以及policies均为json格式
+--
+CREATE TABLE role (
+  id varchar(32) NOT NULL,
+  name varchar(128) NOT NULL,
+  comment varchar(1024) DEFAULT NULL,
+  users text,
+  user_groups text,
+  policies text,
+  ctime timestamptz NOT NULL DEFAULT now(),
+  mtime timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (id),
+  CONSTRAINT role_name UNIQUE (name)
+);
+CREATE TRIGGER role_mtime BEFORE UPDATE ON role FOR EACH ROW EXECUTE PROCEDURE update_mtime();